@@ -0,0 +1,99 @@
+package activities
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// AuthConfig configures how requests to a downstream service are authenticated.
+// Set either an API key or the OAuth2 client-credentials fields, not both.
+type AuthConfig struct {
+	APIKey string
+	// APIKeyHeader is the header carrying the API key (default X-API-Key)
+	APIKeyHeader string
+
+	OAuth2TokenURL     string
+	OAuth2ClientID     string
+	OAuth2ClientSecret string
+	OAuth2Scopes       []string
+}
+
+// RequestAuthenticator adds credentials to an outgoing request
+type RequestAuthenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// NewRequestAuthenticator creates an authenticator from the configuration.
+// It returns nil when no credentials are configured. The HTTP client is used
+// for token requests so they get the same TLS and proxy settings.
+func NewRequestAuthenticator(config AuthConfig, httpClient *http.Client) (RequestAuthenticator, error) {
+	hasAPIKey := config.APIKey != ""
+	hasOAuth2 := config.OAuth2TokenURL != "" || config.OAuth2ClientID != ""
+
+	switch {
+	case hasAPIKey && hasOAuth2:
+		return nil, fmt.Errorf("configure either an API key or OAuth2 client credentials, not both")
+	case hasAPIKey:
+		header := config.APIKeyHeader
+		if header == "" {
+			header = "X-API-Key"
+		}
+		return &APIKeyAuthenticator{Header: header, Key: config.APIKey}, nil
+	case hasOAuth2:
+		if config.OAuth2TokenURL == "" || config.OAuth2ClientID == "" || config.OAuth2ClientSecret == "" {
+			return nil, fmt.Errorf("OAuth2 token URL, client ID, and client secret are all required")
+		}
+		return NewOAuth2Authenticator(clientcredentials.Config{
+			ClientID:     config.OAuth2ClientID,
+			ClientSecret: config.OAuth2ClientSecret,
+			TokenURL:     config.OAuth2TokenURL,
+			Scopes:       config.OAuth2Scopes,
+		}, httpClient), nil
+	default:
+		return nil, nil
+	}
+}
+
+// APIKeyAuthenticator sends a static API key in a request header
+type APIKeyAuthenticator struct {
+	Header string
+	Key    string
+}
+
+// Authenticate sets the API key header
+func (a *APIKeyAuthenticator) Authenticate(req *http.Request) error {
+	req.Header.Set(a.Header, a.Key)
+	return nil
+}
+
+// OAuth2Authenticator sends a bearer token obtained with the OAuth2
+// client-credentials flow. Tokens are cached and refreshed shortly before
+// they expire, so most requests do not hit the token endpoint.
+type OAuth2Authenticator struct {
+	tokens oauth2.TokenSource
+}
+
+// NewOAuth2Authenticator creates an authenticator for the client-credentials flow
+func NewOAuth2Authenticator(config clientcredentials.Config, httpClient *http.Client) *OAuth2Authenticator {
+	ctx := context.Background()
+	if httpClient != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, httpClient)
+	}
+	return &OAuth2Authenticator{
+		tokens: oauth2.ReuseTokenSource(nil, config.TokenSource(ctx)),
+	}
+}
+
+// Authenticate sets the Authorization header with a valid access token
+func (a *OAuth2Authenticator) Authenticate(req *http.Request) error {
+	token, err := a.tokens.Token()
+	if err != nil {
+		return fmt.Errorf("failed to obtain OAuth2 token: %w", err)
+	}
+	token.SetAuthHeader(req)
+	return nil
+}
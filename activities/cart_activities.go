@@ -0,0 +1,46 @@
+package activities
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aswathylr-builds/temporal-order-processing/featureflags"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/activity"
+)
+
+// cartReminderText returns the message of a cart reminder, more pressing
+// with each reminder sent
+func cartReminderText(reminder models.CartReminder) string {
+	switch {
+	case reminder.Final:
+		return fmt.Sprintf("Last chance: your cart of %d items (%.2f) expires soon.", len(reminder.Items), reminder.Amount)
+	case reminder.Number == 1:
+		return fmt.Sprintf("You left %d items in your cart. Ready to check out?", len(reminder.Items))
+	default:
+		return fmt.Sprintf("Your cart of %d items (%.2f) is still waiting for you.", len(reminder.Items), reminder.Amount)
+	}
+}
+
+// SendCartReminder reminds a shopper to check out an abandoned cart on each
+// notification channel whose feature flag is on. Sending is simulated.
+func (a *OrderActivities) SendCartReminder(ctx context.Context, reminder models.CartReminder) error {
+	var channels []string
+	if a.flags().Enabled(ctx, featureflags.EmailNotifications) {
+		channels = append(channels, "email")
+	}
+	if a.flags().Enabled(ctx, featureflags.SMSNotifications) {
+		channels = append(channels, "sms")
+	}
+
+	if activity.IsActivity(ctx) {
+		logger := activity.GetLogger(ctx)
+		if len(channels) == 0 {
+			logger.Info("No notification channels enabled", "cart_id", reminder.CartID, "reminder", reminder.Number)
+			return nil
+		}
+		logger.Info("Cart reminder sent", "cart_id", reminder.CartID, "customer_id", reminder.CustomerID,
+			"reminder", reminder.Number, "final", reminder.Final, "channels", channels, "text", cartReminderText(reminder))
+	}
+	return nil
+}
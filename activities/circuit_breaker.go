@@ -0,0 +1,159 @@
+package activities
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of a circuit breaker
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"
+	CircuitOpen     CircuitState = "open"
+	CircuitHalfOpen CircuitState = "half-open"
+)
+
+// CircuitBreakerConfig configures when a circuit breaker trips and recovers
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens the circuit
+	FailureThreshold int
+	// OpenTimeout is how long the circuit stays open before allowing trial requests
+	OpenTimeout time.Duration
+	// HalfOpenMaxRequests is the number of concurrent trial requests allowed while half-open
+	HalfOpenMaxRequests int
+}
+
+// DefaultCircuitBreakerConfig returns the default circuit breaker settings
+func DefaultCircuitBreakerConfig() CircuitBreakerConfig {
+	return CircuitBreakerConfig{
+		FailureThreshold:    5,
+		OpenTimeout:         30 * time.Second,
+		HalfOpenMaxRequests: 1,
+	}
+}
+
+// ServiceUnavailableError is returned when a request is rejected because the
+// circuit breaker for the downstream service is open
+type ServiceUnavailableError struct {
+	Service    string
+	RetryAfter time.Duration
+}
+
+func (e *ServiceUnavailableError) Error() string {
+	return fmt.Sprintf("%s service unavailable: circuit breaker open, retry after %s", e.Service, e.RetryAfter)
+}
+
+// CircuitBreaker stops calling a failing downstream service for a cool-down
+// period. It is safe for concurrent use and is meant to be shared by all
+// activity executions in a worker process.
+type CircuitBreaker struct {
+	name   string
+	config CircuitBreakerConfig
+
+	mu               sync.Mutex
+	state            CircuitState
+	failures         int
+	openedAt         time.Time
+	halfOpenInFlight int
+	now              func() time.Time
+}
+
+// NewCircuitBreaker creates a closed circuit breaker for the named service
+func NewCircuitBreaker(name string, config CircuitBreakerConfig) *CircuitBreaker {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = DefaultCircuitBreakerConfig().FailureThreshold
+	}
+	if config.OpenTimeout <= 0 {
+		config.OpenTimeout = DefaultCircuitBreakerConfig().OpenTimeout
+	}
+	if config.HalfOpenMaxRequests <= 0 {
+		config.HalfOpenMaxRequests = DefaultCircuitBreakerConfig().HalfOpenMaxRequests
+	}
+
+	return &CircuitBreaker{
+		name:   name,
+		config: config,
+		state:  CircuitClosed,
+		now:    time.Now,
+	}
+}
+
+// State returns the current state of the circuit
+func (cb *CircuitBreaker) State() CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.advance()
+	return cb.state
+}
+
+// Do sends the request through the client if the circuit allows it. Transport
+// errors and 5xx responses count as failures; any other response closes the
+// circuit again.
+func (cb *CircuitBreaker) Do(client *http.Client, req *http.Request) (*http.Response, error) {
+	if err := cb.allow(); err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	cb.record(err == nil && resp.StatusCode < http.StatusInternalServerError)
+	return resp, err
+}
+
+// allow reserves a slot for a request or returns a ServiceUnavailableError
+func (cb *CircuitBreaker) allow() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.advance()
+
+	switch cb.state {
+	case CircuitOpen:
+		return &ServiceUnavailableError{
+			Service:    cb.name,
+			RetryAfter: cb.config.OpenTimeout - cb.now().Sub(cb.openedAt),
+		}
+	case CircuitHalfOpen:
+		if cb.halfOpenInFlight >= cb.config.HalfOpenMaxRequests {
+			return &ServiceUnavailableError{
+				Service:    cb.name,
+				RetryAfter: cb.config.OpenTimeout,
+			}
+		}
+		cb.halfOpenInFlight++
+	}
+	return nil
+}
+
+// record updates the circuit with the outcome of a request
+func (cb *CircuitBreaker) record(success bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == CircuitHalfOpen && cb.halfOpenInFlight > 0 {
+		cb.halfOpenInFlight--
+	}
+
+	if success {
+		cb.state = CircuitClosed
+		cb.failures = 0
+		return
+	}
+
+	cb.failures++
+	if cb.state == CircuitHalfOpen || cb.failures >= cb.config.FailureThreshold {
+		cb.state = CircuitOpen
+		cb.openedAt = cb.now()
+		cb.halfOpenInFlight = 0
+	}
+}
+
+// advance moves an open circuit to half-open once the cool-down has elapsed.
+// Callers must hold cb.mu.
+func (cb *CircuitBreaker) advance() {
+	if cb.state == CircuitOpen && cb.now().Sub(cb.openedAt) >= cb.config.OpenTimeout {
+		cb.state = CircuitHalfOpen
+		cb.halfOpenInFlight = 0
+	}
+}
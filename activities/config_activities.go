@@ -0,0 +1,40 @@
+package activities
+
+import (
+	"context"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/activity"
+)
+
+// DynamicConfigSource supplies the current dynamic configuration. On error it
+// returns the last valid configuration alongside the error.
+type DynamicConfigSource interface {
+	DynamicConfig() (models.DynamicConfig, error)
+}
+
+// ConfigActivities exposes dynamic configuration to workflows
+type ConfigActivities struct {
+	Source DynamicConfigSource
+}
+
+// NewConfigActivities creates a new instance of ConfigActivities. A nil
+// source serves the zero configuration.
+func NewConfigActivities(source DynamicConfigSource) *ConfigActivities {
+	return &ConfigActivities{Source: source}
+}
+
+// GetConfig returns the current dynamic configuration. Workflows call it
+// instead of reading configuration directly, so each value they act on is
+// recorded in history and a replay sees the same value even after it changes.
+// A broken edit to the configuration keeps the last valid values in effect.
+func (a *ConfigActivities) GetConfig(ctx context.Context) (models.DynamicConfig, error) {
+	if a.Source == nil {
+		return models.DynamicConfig{}, nil
+	}
+	config, err := a.Source.DynamicConfig()
+	if err != nil && activity.IsActivity(ctx) {
+		activity.GetLogger(ctx).Warn("Invalid dynamic config, keeping last valid values", "error", err)
+	}
+	return config, nil
+}
@@ -0,0 +1,55 @@
+package activities
+
+import (
+	"context"
+	"math"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/activity"
+)
+
+// ConvertCurrency converts an order's amount into the requested currency,
+// by default the base currency, at the exchange rate provider's current
+// rate, rounded to the cent. An amount already in that currency converts at
+// a rate of 1. Without a provider configured, other currencies fail with a
+// non-retryable CurrencyUnsupported error.
+func (a *OrderActivities) ConvertCurrency(ctx context.Context, req models.ConversionRequest) (*models.CurrencyConversion, error) {
+	to := req.To
+	if to == "" {
+		to = a.baseCurrency()
+	}
+
+	rate := models.ExchangeRate{From: req.From, To: to, Rate: 1}
+	if req.From != to {
+		if a.ExchangeRates == nil {
+			return nil, unsupportedCurrency(req.From, to)
+		}
+		var err error
+		if rate, err = a.ExchangeRates.Rate(ctx, req.From, to); err != nil {
+			return nil, err
+		}
+	}
+
+	conversion := &models.CurrencyConversion{
+		From:            req.From,
+		To:              to,
+		Rate:            rate.Rate,
+		RateAsOf:        rate.AsOf,
+		Amount:          req.Amount,
+		ConvertedAmount: math.Round(req.Amount*rate.Rate*100) / 100,
+	}
+	if activity.IsActivity(ctx) {
+		logger := activity.GetLogger(ctx)
+		logger.Info("Currency converted", "order_id", req.OrderID, "from", conversion.From, "to", conversion.To,
+			"rate", conversion.Rate, "amount", conversion.Amount, "converted_amount", conversion.ConvertedAmount)
+	}
+	return conversion, nil
+}
+
+// baseCurrency returns the currency payments are charged in
+func (a *OrderActivities) baseCurrency() string {
+	if a.BaseCurrency == "" {
+		return models.DefaultBaseCurrency
+	}
+	return a.BaseCurrency
+}
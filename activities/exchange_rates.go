@@ -0,0 +1,149 @@
+package activities
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/correlation"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/temporal"
+)
+
+// DefaultExchangeRateCacheTTL is how long a cached exchange rate is used
+// before it is fetched again
+const DefaultExchangeRateCacheTTL = 15 * time.Minute
+
+// ExchangeRateProvider gives the rate at which one currency converts into
+// another. A pair it has no rate for fails with a non-retryable
+// CurrencyUnsupported error.
+type ExchangeRateProvider interface {
+	Rate(ctx context.Context, from, to string) (models.ExchangeRate, error)
+}
+
+// unsupportedCurrency is the error for a currency pair with no known rate
+func unsupportedCurrency(from, to string) error {
+	return temporal.NewNonRetryableApplicationError(
+		fmt.Sprintf("no exchange rate from %s to %s", from, to), models.ErrTypeCurrencyUnsupported, nil)
+}
+
+// HTTPExchangeRates reads rates from an exchange-rate API, which answers
+// GET {URL}/rates?from=EUR&to=USD with {"rate": 1.08, "as_of": "..."} and
+// 404 Not Found for a pair it does not quote
+type HTTPExchangeRates struct {
+	URL    string
+	Client *http.Client
+}
+
+// Rate asks the exchange-rate API for the rate from one currency to another
+func (p *HTTPExchangeRates) Rate(ctx context.Context, from, to string) (models.ExchangeRate, error) {
+	query := url.Values{}
+	query.Set("from", from)
+	query.Set("to", to)
+	req, err := http.NewRequestWithContext(ctx, "GET", p.URL+"/rates?"+query.Encode(), nil)
+	if err != nil {
+		return models.ExchangeRate{}, fmt.Errorf("failed to create exchange rate request: %w", err)
+	}
+	correlation.SetHeaders(ctx, req)
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return models.ExchangeRate{}, fmt.Errorf("failed to call exchange rate API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return models.ExchangeRate{}, unsupportedCurrency(from, to)
+	case resp.StatusCode >= 300:
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return models.ExchangeRate{}, fmt.Errorf("exchange rate API returned status %d: %s", resp.StatusCode, message)
+	}
+	var quote struct {
+		Rate float64   `json:"rate"`
+		AsOf time.Time `json:"as_of"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&quote); err != nil {
+		return models.ExchangeRate{}, fmt.Errorf("failed to decode exchange rate: %w", err)
+	}
+	if quote.Rate <= 0 {
+		return models.ExchangeRate{}, fmt.Errorf("exchange rate API returned rate %v from %s to %s", quote.Rate, from, to)
+	}
+	if quote.AsOf.IsZero() {
+		quote.AsOf = time.Now()
+	}
+	return models.ExchangeRate{From: from, To: to, Rate: quote.Rate, AsOf: quote.AsOf}, nil
+}
+
+// FixedExchangeRates is an ExchangeRateProvider with fixed rates, keyed by
+// "FROM/TO" such as "EUR/USD". A pair given only the other way round uses
+// the inverse rate. It stands in for a rate API in development and tests.
+type FixedExchangeRates map[string]float64
+
+// Rate returns the fixed rate from one currency to another
+func (r FixedExchangeRates) Rate(ctx context.Context, from, to string) (models.ExchangeRate, error) {
+	rate, ok := r[from+"/"+to]
+	if !ok {
+		inverse, ok := r[to+"/"+from]
+		if !ok || inverse <= 0 {
+			return models.ExchangeRate{}, unsupportedCurrency(from, to)
+		}
+		rate = 1 / inverse
+	}
+	return models.ExchangeRate{From: from, To: to, Rate: rate, AsOf: time.Now()}, nil
+}
+
+// CachedExchangeRates keeps the rates of another provider for TTL, so a
+// burst of orders in one currency asks the provider once. It is shared by
+// all ConvertCurrency executions in a worker.
+type CachedExchangeRates struct {
+	provider ExchangeRateProvider
+	ttl      time.Duration
+
+	mu    sync.Mutex
+	rates map[string]cachedExchangeRate
+}
+
+type cachedExchangeRate struct {
+	rate      models.ExchangeRate
+	fetchedAt time.Time
+}
+
+// NewCachedExchangeRates caches the rates of provider for ttl; a ttl of
+// zero uses DefaultExchangeRateCacheTTL
+func NewCachedExchangeRates(provider ExchangeRateProvider, ttl time.Duration) *CachedExchangeRates {
+	if ttl <= 0 {
+		ttl = DefaultExchangeRateCacheTTL
+	}
+	return &CachedExchangeRates{
+		provider: provider,
+		ttl:      ttl,
+		rates:    make(map[string]cachedExchangeRate),
+	}
+}
+
+// Rate returns the cached rate from one currency to another, asking the
+// provider when it is missing or older than the TTL. Failures are not cached.
+func (c *CachedExchangeRates) Rate(ctx context.Context, from, to string) (models.ExchangeRate, error) {
+	key := from + "/" + to
+	c.mu.Lock()
+	cached, ok := c.rates[key]
+	c.mu.Unlock()
+	if ok && time.Since(cached.fetchedAt) < c.ttl {
+		return cached.rate, nil
+	}
+
+	rate, err := c.provider.Rate(ctx, from, to)
+	if err != nil {
+		return models.ExchangeRate{}, err
+	}
+	c.mu.Lock()
+	c.rates[key] = cachedExchangeRate{rate: rate, fetchedAt: time.Now()}
+	c.mu.Unlock()
+	return rate, nil
+}
@@ -0,0 +1,48 @@
+package activities
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/activity"
+)
+
+// DefaultFraudMaxAmountPerItem is the average item price above which an order
+// is treated as likely fraud
+const DefaultFraudMaxAmountPerItem = 2000.0
+
+// CheckFraud screens an order before payment. A suspicious order fails with a
+// non-retryable FraudSuspected error; otherwise its risk score is returned.
+func (a *OrderActivities) CheckFraud(ctx context.Context, order models.Order) (*models.FraudCheckResult, error) {
+	maxPerItem := a.FraudMaxAmountPerItem
+	if maxPerItem <= 0 {
+		maxPerItem = DefaultFraudMaxAmountPerItem
+	}
+
+	// An order without items has nothing to average its amount over
+	items := len(order.Items)
+	if items == 0 {
+		items = 1
+	}
+	perItem := order.Amount / float64(items)
+	result := &models.FraudCheckResult{Score: perItem / maxPerItem}
+	var customerID string
+	if order.Customer != nil {
+		customerID = order.Customer.ID
+	}
+
+	if activity.IsActivity(ctx) {
+		activity.GetLogger(ctx).Info("Fraud check scored order", "order_id", order.ID, "customer_id", customerID, "score", result.Score)
+	}
+
+	if perItem > maxPerItem {
+		return nil, (&models.FraudSuspectedError{
+			OrderID:    order.ID,
+			CustomerID: customerID,
+			Score:      result.Score,
+			Reason:     fmt.Sprintf("average item price $%.2f exceeds $%.2f", perItem, maxPerItem),
+		}).ApplicationError()
+	}
+	return result, nil
+}
@@ -0,0 +1,72 @@
+package activities
+
+import (
+	"context"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/activity"
+)
+
+// ReserveItem reserves inventory for one item of a large order. It is the
+// first step of ItemFulfillmentWorkflow and the only one that can be rejected.
+func (a *OrderActivities) ReserveItem(ctx context.Context, req models.ItemFulfillmentRequest) error {
+	if activity.IsActivity(ctx) {
+		logger := activity.GetLogger(ctx)
+		logger.Info("Reserving item", "order_id", req.OrderID, "item", req.Item, "warehouse_id", req.WarehouseID)
+	}
+
+	if missing := a.outOfStock([]string{req.Item}); len(missing) > 0 {
+		return (&models.InventoryOutOfStockError{OrderID: req.OrderID, Items: missing}).ApplicationError()
+	}
+	return simulateWork(ctx, a.itemFulfillmentTime(req.IsExpedited)/3, req.Item)
+}
+
+// PickItem picks a reserved item from the warehouse shelf
+func (a *OrderActivities) PickItem(ctx context.Context, req models.ItemFulfillmentRequest) error {
+	if activity.IsActivity(ctx) {
+		logger := activity.GetLogger(ctx)
+		logger.Info("Picking item", "order_id", req.OrderID, "item", req.Item, "warehouse_id", req.WarehouseID)
+	}
+	return simulateWork(ctx, a.itemFulfillmentTime(req.IsExpedited)/3, req.Item)
+}
+
+// PackItem packs a picked item for shipment
+func (a *OrderActivities) PackItem(ctx context.Context, req models.ItemFulfillmentRequest) error {
+	if activity.IsActivity(ctx) {
+		logger := activity.GetLogger(ctx)
+		logger.Info("Packing item", "order_id", req.OrderID, "item", req.Item, "warehouse_id", req.WarehouseID)
+	}
+	return simulateWork(ctx, a.itemFulfillmentTime(req.IsExpedited)/3, req.Item)
+}
+
+// itemFulfillmentTime is the simulated time to fulfill one item end to end
+func (a *OrderActivities) itemFulfillmentTime(isExpedited bool) time.Duration {
+	if a.ItemFulfillmentTime > 0 {
+		return a.ItemFulfillmentTime
+	}
+	if isExpedited {
+		return time.Second
+	}
+	return 3 * time.Second
+}
+
+// simulateWork waits for d, heartbeating details every second, and returns
+// early if the activity is cancelled
+func simulateWork(ctx context.Context, d time.Duration, details interface{}) error {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	done := time.After(d)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-done:
+			return nil
+		case <-ticker.C:
+			if activity.IsActivity(ctx) {
+				activity.RecordHeartbeat(ctx, details)
+			}
+		}
+	}
+}
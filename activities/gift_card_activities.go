@@ -0,0 +1,65 @@
+package activities
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/activity"
+)
+
+// HoldGiftCardBalance holds part of a gift card's balance for an order so it
+// cannot be spent elsewhere before the order is fulfilled. A balance too low
+// for the hold is a non-retryable decline.
+func (a *OrderActivities) HoldGiftCardBalance(ctx context.Context, req models.GiftCardHoldRequest) (*models.GiftCardHold, error) {
+	// Simulate the gift card service call (reduced for demo)
+	time.Sleep(200 * time.Millisecond)
+
+	if a.GiftCardBalance > 0 && req.Amount > a.GiftCardBalance {
+		return nil, (&models.PaymentDeclinedError{
+			OrderID:     req.OrderID,
+			Amount:      req.Amount,
+			DeclineCode: "insufficient_gift_card_balance",
+			Reason:      fmt.Sprintf("Gift card balance of $%.2f is below $%.2f", a.GiftCardBalance, req.Amount),
+		}).ApplicationError()
+	}
+
+	hold := &models.GiftCardHold{
+		HoldID:  fmt.Sprintf("HOLD-%s-%s", req.OrderID, req.Card),
+		OrderID: req.OrderID,
+		Card:    req.Card,
+		Amount:  req.Amount,
+	}
+	if activity.IsActivity(ctx) {
+		logger := activity.GetLogger(ctx)
+		logger.Info("Gift card balance held", "order_id", req.OrderID, "hold_id", hold.HoldID, "amount", req.Amount)
+	}
+	return hold, nil
+}
+
+// CaptureGiftCard spends a held gift card balance, completing the gift card's
+// part of the payment once the order is fulfilled
+func (a *OrderActivities) CaptureGiftCard(ctx context.Context, hold models.GiftCardHold) error {
+	// Simulate the gift card service call (reduced for demo)
+	time.Sleep(200 * time.Millisecond)
+
+	if activity.IsActivity(ctx) {
+		logger := activity.GetLogger(ctx)
+		logger.Info("Gift card captured", "order_id", hold.OrderID, "hold_id", hold.HoldID, "amount", hold.Amount)
+	}
+	return nil
+}
+
+// ReleaseGiftCard lifts a hold without spending it. It is the compensation
+// for a hold whose order is cancelled or fails before fulfillment.
+func (a *OrderActivities) ReleaseGiftCard(ctx context.Context, hold models.GiftCardHold) error {
+	// Simulate the gift card service call (reduced for demo)
+	time.Sleep(200 * time.Millisecond)
+
+	if activity.IsActivity(ctx) {
+		logger := activity.GetLogger(ctx)
+		logger.Info("Gift card hold released", "order_id", hold.OrderID, "hold_id", hold.HoldID, "amount", hold.Amount)
+	}
+	return nil
+}
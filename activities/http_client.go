@@ -0,0 +1,189 @@
+package activities
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// HTTPConfig configures the HTTP client used to call downstream services
+type HTTPConfig struct {
+	// Timeout bounds the whole request, including retries
+	Timeout               time.Duration
+	DialTimeout           time.Duration
+	TLSHandshakeTimeout   time.Duration
+	ResponseHeaderTimeout time.Duration
+	IdleConnTimeout       time.Duration
+	MaxIdleConns          int
+	MaxIdleConnsPerHost   int
+
+	// CAFile is a PEM bundle used to verify the server instead of the system roots
+	CAFile string
+	// CertFile and KeyFile enable mTLS with a client certificate
+	CertFile string
+	KeyFile  string
+	// ServerName overrides the name used to verify the server certificate
+	ServerName string
+
+	// ProxyURL routes requests through a proxy; when empty the standard
+	// HTTP_PROXY/HTTPS_PROXY/NO_PROXY environment variables apply
+	ProxyURL string
+
+	// MaxRetries is the number of retries on connection errors and 5xx responses
+	MaxRetries   int
+	RetryWaitMin time.Duration
+	RetryWaitMax time.Duration
+}
+
+// DefaultHTTPConfig returns the default HTTP client settings
+func DefaultHTTPConfig() HTTPConfig {
+	return HTTPConfig{
+		Timeout:               10 * time.Second,
+		DialTimeout:           5 * time.Second,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ResponseHeaderTimeout: 5 * time.Second,
+		IdleConnTimeout:       90 * time.Second,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   10,
+		MaxRetries:            2,
+		RetryWaitMin:          100 * time.Millisecond,
+		RetryWaitMax:          2 * time.Second,
+	}
+}
+
+// NewHTTPClient builds an HTTP client from the configuration
+func NewHTTPClient(config HTTPConfig) (*http.Client, error) {
+	tlsConfig, err := buildTLSConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if config.ProxyURL != "" {
+		proxyURL, err := url.Parse(config.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	transport := &http.Transport{
+		Proxy: proxy,
+		DialContext: (&net.Dialer{
+			Timeout:   config.DialTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSClientConfig:       tlsConfig,
+		TLSHandshakeTimeout:   config.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: config.ResponseHeaderTimeout,
+		IdleConnTimeout:       config.IdleConnTimeout,
+		MaxIdleConns:          config.MaxIdleConns,
+		MaxIdleConnsPerHost:   config.MaxIdleConnsPerHost,
+		ForceAttemptHTTP2:     true,
+	}
+
+	var roundTripper http.RoundTripper = transport
+	if config.MaxRetries > 0 {
+		roundTripper = &retryTransport{
+			next:    transport,
+			retries: config.MaxRetries,
+			waitMin: config.RetryWaitMin,
+			waitMax: config.RetryWaitMax,
+		}
+	}
+
+	return &http.Client{
+		Timeout:   config.Timeout,
+		Transport: roundTripper,
+	}, nil
+}
+
+// buildTLSConfig loads the CA bundle and client certificate, if configured
+func buildTLSConfig(config HTTPConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: config.ServerName,
+	}
+
+	if config.CAFile != "" {
+		caPEM, err := os.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", config.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if config.CertFile != "" || config.KeyFile != "" {
+		if config.CertFile == "" || config.KeyFile == "" {
+			return nil, fmt.Errorf("both client certificate and key are required for mTLS")
+		}
+		cert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// retryTransport retries requests on connection errors and 5xx responses
+// using exponential backoff with full jitter
+type retryTransport struct {
+	next    http.RoundTripper
+	retries int
+	waitMin time.Duration
+	waitMax time.Duration
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+
+		retryable := err != nil || resp.StatusCode >= http.StatusInternalServerError
+		if !retryable || attempt >= t.retries || (req.Body != nil && req.GetBody == nil) {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(t.backoff(attempt)):
+		}
+
+		// Rewind the body for the next attempt
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("failed to rewind request body: %w", err)
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// backoff returns a random wait in [0, min(waitMax, waitMin*2^attempt)]
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	ceiling := t.waitMin << attempt
+	if ceiling <= 0 || ceiling > t.waitMax {
+		ceiling = t.waitMax
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
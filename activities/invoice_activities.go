@@ -0,0 +1,90 @@
+package activities
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
+)
+
+// GenerateInvoice renders the invoice for a completed order to a file in the
+// local scratch directory and returns its path. The file only exists on this
+// host, so UploadInvoice must run in the same session.
+func (a *OrderActivities) GenerateInvoice(ctx context.Context, req models.InvoiceRequest) (string, error) {
+	if activity.IsActivity(ctx) {
+		logger := activity.GetLogger(ctx)
+		logger.Info("Generating invoice", "order_id", req.OrderID)
+	}
+
+	dir := a.invoiceDir()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create invoice directory: %w", err)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "INVOICE %s\n", req.OrderID)
+	fmt.Fprintf(&b, "Transaction: %s\n", req.TransactionID)
+	for _, item := range req.Items {
+		fmt.Fprintf(&b, "- %s\n", item)
+	}
+	fmt.Fprintf(&b, "Total: $%.2f\n", req.Amount)
+
+	path := filepath.Join(dir, fmt.Sprintf("invoice-%s.txt", req.OrderID))
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write invoice: %w", err)
+	}
+	return path, nil
+}
+
+// UploadInvoice moves a generated invoice from local scratch space to the
+// invoice store and returns its URL
+func (a *OrderActivities) UploadInvoice(ctx context.Context, orderID, path string) (string, error) {
+	if activity.IsActivity(ctx) {
+		logger := activity.GetLogger(ctx)
+		logger.Info("Uploading invoice", "order_id", orderID, "path", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		// Retrying on another host cannot find the file either; the workflow
+		// starts a new session and regenerates it instead
+		return "", temporal.NewNonRetryableApplicationError(
+			fmt.Sprintf("invoice file %s is not on this host", path), models.ErrTypeInvoiceFileMissing, err)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read invoice: %w", err)
+	}
+
+	storeDir := a.invoiceStoreDir()
+	if err := os.MkdirAll(storeDir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create invoice store: %w", err)
+	}
+	dest := filepath.Join(storeDir, filepath.Base(path))
+	if err := os.WriteFile(dest, data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to upload invoice: %w", err)
+	}
+	_ = os.Remove(path)
+
+	return "file://" + dest, nil
+}
+
+// invoiceDir is the host-local scratch directory for generated invoices
+func (a *OrderActivities) invoiceDir() string {
+	if a.InvoiceDir != "" {
+		return a.InvoiceDir
+	}
+	return filepath.Join(os.TempDir(), "order-invoices")
+}
+
+// invoiceStoreDir stands in for the shared object store invoices are uploaded to
+func (a *OrderActivities) invoiceStoreDir() string {
+	if a.InvoiceStoreDir != "" {
+		return a.InvoiceStoreDir
+	}
+	return filepath.Join(os.TempDir(), "order-invoice-store")
+}
@@ -0,0 +1,136 @@
+package activities
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+
+	"github.com/aswathylr-builds/temporal-order-processing/correlation"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/activity"
+)
+
+// DefaultLoyaltyPointsPerDollar is how many loyalty points an order earns per
+// dollar paid
+const DefaultLoyaltyPointsPerDollar = 1.0
+
+// AwardLoyaltyPoints credits the customer with the points an order earns on
+// its paid amount. Orders too small to earn a point award nothing and return
+// nil. Without a loyalty service configured the award is simulated.
+func (a *OrderActivities) AwardLoyaltyPoints(ctx context.Context, req models.LoyaltyRequest) (*models.LoyaltyTransaction, error) {
+	rate := a.LoyaltyPointsPerDollar
+	if rate <= 0 {
+		rate = DefaultLoyaltyPointsPerDollar
+	}
+	req.Points = int(math.Floor(req.Amount * rate))
+	if req.Points <= 0 {
+		return nil, nil
+	}
+
+	txn := &models.LoyaltyTransaction{
+		TransactionID: fmt.Sprintf("LOY-%s-award", req.OrderID),
+		OrderID:       req.OrderID,
+		CustomerID:    req.CustomerID,
+		Points:        req.Points,
+	}
+	if a.LoyaltyURL != "" {
+		if _, err := a.callLoyaltyService(ctx, "/award", req, txn); err != nil {
+			return nil, err
+		}
+	}
+
+	if activity.IsActivity(ctx) {
+		logger := activity.GetLogger(ctx)
+		logger.Info("Loyalty points awarded", "order_id", req.OrderID, "customer_id", req.CustomerID, "points", txn.Points, "transaction_id", txn.TransactionID)
+	}
+	return txn, nil
+}
+
+// RedeemLoyaltyPoints debits the points an order spends from the customer's
+// balance. A refusal by the loyalty service, such as for a short balance, is
+// a non-retryable LoyaltyRedemptionRejected error. Without a loyalty service
+// configured the redemption is simulated.
+func (a *OrderActivities) RedeemLoyaltyPoints(ctx context.Context, req models.LoyaltyRequest) (*models.LoyaltyTransaction, error) {
+	rejected := &models.LoyaltyRedemptionRejectedError{OrderID: req.OrderID, CustomerID: req.CustomerID, Points: req.Points}
+	if req.CustomerID == "" {
+		rejected.Reason = "the order has no customer"
+		return nil, rejected.ApplicationError()
+	}
+
+	txn := &models.LoyaltyTransaction{
+		TransactionID: fmt.Sprintf("LOY-%s-redeem", req.OrderID),
+		OrderID:       req.OrderID,
+		CustomerID:    req.CustomerID,
+		Points:        -req.Points,
+	}
+	if a.LoyaltyURL != "" {
+		status, err := a.callLoyaltyService(ctx, "/redeem", req, txn)
+		if status >= 400 && status < 500 {
+			rejected.Reason = err.Error()
+			return nil, rejected.ApplicationError()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if activity.IsActivity(ctx) {
+		logger := activity.GetLogger(ctx)
+		logger.Info("Loyalty points redeemed", "order_id", req.OrderID, "customer_id", req.CustomerID, "points", req.Points, "transaction_id", txn.TransactionID)
+	}
+	return txn, nil
+}
+
+// ReverseLoyaltyPoints undoes an award or redemption. It is the compensation
+// for the loyalty steps of an order that is cancelled or refunded.
+func (a *OrderActivities) ReverseLoyaltyPoints(ctx context.Context, txn models.LoyaltyTransaction) error {
+	if a.LoyaltyURL != "" {
+		if _, err := a.callLoyaltyService(ctx, "/reverse", txn, nil); err != nil {
+			return err
+		}
+	}
+
+	if activity.IsActivity(ctx) {
+		logger := activity.GetLogger(ctx)
+		logger.Info("Loyalty points reversed", "order_id", txn.OrderID, "customer_id", txn.CustomerID, "points", -txn.Points, "transaction_id", txn.TransactionID)
+	}
+	return nil
+}
+
+// callLoyaltyService posts body as JSON to path on the loyalty service and
+// decodes a successful response into out, if given. It returns the response
+// status, if one was received, with an error for any non-2xx status.
+func (a *OrderActivities) callLoyaltyService(ctx context.Context, path string, body, out any) (int, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal loyalty request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", a.LoyaltyURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create loyalty request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	correlation.SetHeaders(ctx, req)
+
+	resp, err := a.HTTPClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to call loyalty service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return resp.StatusCode, fmt.Errorf("loyalty service returned status %d: %s", resp.StatusCode, bytes.TrimSpace(message))
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp.StatusCode, fmt.Errorf("failed to decode loyalty response: %w", err)
+		}
+	}
+	return resp.StatusCode, nil
+}
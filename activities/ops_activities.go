@@ -0,0 +1,91 @@
+package activities
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/aswathylr-builds/temporal-order-processing/correlation"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/activity"
+)
+
+// opsAlert is the payload posted to the ops webhook
+type opsAlert struct {
+	Text    string              `json:"text"`
+	Failure models.OrderFailure `json:"failure"`
+}
+
+// screeningAlert is the payload posted to the ops webhook for a customer
+// screening hit
+type screeningAlert struct {
+	Text string                     `json:"text"`
+	Hit  models.CustomerDeniedError `json:"hit"`
+}
+
+// NotifyOpsOfFailure alerts the operations team that an order was dead-lettered.
+// Without an ops webhook configured the alert is only logged.
+func (a *OrderActivities) NotifyOpsOfFailure(ctx context.Context, failure models.OrderFailure) error {
+	text := fmt.Sprintf("Order %s failed at %s stage and needs attention: %s. Send the %q signal to workflow %s to retry.",
+		failure.Order.ID, failure.Stage, failure.Reason, models.SignalRetry, models.DeadLetterWorkflowID(failure.Order.ID, failure.RunID))
+
+	if activity.IsActivity(ctx) {
+		logger := activity.GetLogger(ctx)
+		logger.Warn("Order dead-lettered", "order_id", failure.Order.ID, "stage", failure.Stage, "reason", failure.Reason)
+	}
+
+	return a.postOpsAlert(ctx, opsAlert{Text: text, Failure: failure})
+}
+
+// NotifyOpsOfScreeningHit alerts the operations team that an order was
+// rejected because its customer is on a denylist or sanctions list. Without
+// an ops webhook configured the alert is only logged.
+func (a *OrderActivities) NotifyOpsOfScreeningHit(ctx context.Context, hit models.CustomerDeniedError) error {
+	text := fmt.Sprintf("Order %s was rejected: customer %s matched the %s list (%s).",
+		hit.OrderID, hit.CustomerID, hit.List, hit.Reason)
+
+	if activity.IsActivity(ctx) {
+		logger := activity.GetLogger(ctx)
+		logger.Warn("Customer screening hit", "order_id", hit.OrderID, "customer_id", hit.CustomerID, "list", hit.List)
+	}
+	return a.postOpsAlert(ctx, screeningAlert{Text: text, Hit: hit})
+}
+
+// postOpsAlert posts an alert payload to the ops webhook; without one
+// configured it does nothing
+func (a *OrderActivities) postOpsAlert(ctx context.Context, alert any) error {
+	return a.postAlert(ctx, "ops", a.OpsWebhookURL, alert)
+}
+
+// postAlert posts an alert payload to the named team's webhook; an empty
+// webhookURL does nothing
+func (a *OrderActivities) postAlert(ctx context.Context, team, webhookURL string, alert any) error {
+	if webhookURL == "" {
+		return nil
+	}
+
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s alert: %w", team, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create %s alert request: %w", team, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	correlation.SetHeaders(ctx, req)
+
+	resp, err := a.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send %s alert: %w", team, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s webhook returned status %d", team, resp.StatusCode)
+	}
+	return nil
+}
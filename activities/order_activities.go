@@ -1,14 +1,12 @@
 package activities
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"time"
 
+	"github.com/aswathylr-builds/temporal-order-processing/featureflags"
 	"github.com/aswathylr-builds/temporal-order-processing/models"
 	"go.temporal.io/sdk/activity"
 )
@@ -17,16 +15,106 @@ import (
 type OrderActivities struct {
 	HTTPClient    *http.Client
 	ValidationURL string
+	// ValidationBreaker is shared by all ValidateOrder executions in this
+	// worker so an outage trips it once rather than per activity attempt
+	ValidationBreaker *CircuitBreaker
+	// ValidationLimiter caps the request rate to the validation service;
+	// nil means unlimited
+	ValidationLimiter *RateLimiter
+	// ValidationAuth adds credentials to validation requests; nil sends none
+	ValidationAuth RequestAuthenticator
+	// LocalRules is the fallback validator used when the remote service is down
+	LocalRules *RulesValidator
+	// OpsWebhookURL receives alerts for dead-lettered orders; empty only logs them
+	OpsWebhookURL string
+	// NotificationURL receives the customer notifications NotifyOrderComplete
+	// sends, one models.OrderNotification per channel; empty simulates sending
+	NotificationURL string
+	// OutOfStockItems simulates inventory: orders containing these items fail processing
+	OutOfStockItems []string
+	// PaymentDeclineOver simulates the gateway declining payments above this amount; zero disables it
+	PaymentDeclineOver float64
+	// GiftCardBalance simulates the balance of every gift card: larger holds
+	// are declined; zero is unlimited
+	GiftCardBalance float64
+	// ProcessingTime and ExpeditedProcessingTime override the simulated
+	// fulfillment durations (15s and 5s) when set
+	ProcessingTime          time.Duration
+	ExpeditedProcessingTime time.Duration
+	// ItemFulfillmentTime overrides the simulated per-item duration used by
+	// FulfillItem (3s, or 1s when expedited) when set
+	ItemFulfillmentTime time.Duration
+	// InvoiceDir is the host-local scratch directory GenerateInvoice writes to,
+	// and InvoiceStoreDir the store UploadInvoice copies invoices into; both
+	// default to directories under os.TempDir()
+	InvoiceDir      string
+	InvoiceStoreDir string
+	// Flags gates optional behavior such as notification channels; nil uses
+	// featureflags.Default()
+	Flags *featureflags.Flags
+	// ScreeningURL is the base URL of the sanctions screening API; empty
+	// screens against ScreeningDenylist only
+	ScreeningURL string
+	// ScreeningDenylist lists the IDs of customers whose orders are rejected
+	ScreeningDenylist []string
+	// FraudMaxAmountPerItem is the average item price above which CheckFraud
+	// flags an order; zero uses DefaultFraudMaxAmountPerItem
+	FraudMaxAmountPerItem float64
+	// LoyaltyURL is the base URL of the loyalty service; empty simulates it
+	LoyaltyURL string
+	// LoyaltyPointsPerDollar is how many points AwardLoyaltyPoints credits
+	// per dollar paid; zero uses DefaultLoyaltyPointsPerDollar
+	LoyaltyPointsPerDollar float64
+	// CustomerURL is the base URL of the customer service risk assessment
+	// reads order history from and step-up verification goes through; empty
+	// treats every customer as new and only logs step-up requests
+	CustomerURL string
+	// CarrierURL is the base URL of the carrier API TrackShipment polls;
+	// empty simulates it
+	CarrierURL string
+	// Warehouses are the locations SelectWarehouse routes items to; empty
+	// ships everything from one warehouse
+	Warehouses []models.Warehouse
+	// VendorURL is the base URL of the marketplace API purchase orders are
+	// sent to dropship vendors through; empty simulates vendors that accept
+	// at once
+	VendorURL string
+	// PaymentGatewayURL is the base URL of the payment gateway API
+	// FetchGatewayTransactions reads the day's transactions from
+	PaymentGatewayURL string
+	// FinanceWebhookURL receives reconciliation reports; empty only logs them
+	FinanceWebhookURL string
+	// BaseCurrency is the currency payments are charged in; empty uses
+	// models.DefaultBaseCurrency
+	BaseCurrency string
+	// ExchangeRates converts orders in other currencies into BaseCurrency;
+	// nil rejects them
+	ExchangeRates ExchangeRateProvider
 }
 
-// NewOrderActivities creates a new instance of OrderActivities
+// NewOrderActivities creates a new instance of OrderActivities with the default HTTP client settings
 func NewOrderActivities(validationURL string) *OrderActivities {
-	return &OrderActivities{
-		HTTPClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
-		ValidationURL: validationURL,
+	activities, err := NewOrderActivitiesWithConfig(validationURL, DefaultHTTPConfig())
+	if err != nil {
+		// The default configuration has no files to load, so this cannot happen
+		panic(err)
 	}
+	return activities
+}
+
+// NewOrderActivitiesWithConfig creates a new instance of OrderActivities using the given HTTP client settings
+func NewOrderActivitiesWithConfig(validationURL string, httpConfig HTTPConfig) (*OrderActivities, error) {
+	httpClient, err := NewHTTPClient(httpConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+
+	return &OrderActivities{
+		HTTPClient:        httpClient,
+		ValidationURL:     validationURL,
+		ValidationBreaker: NewCircuitBreaker("validation", DefaultCircuitBreakerConfig()),
+		LocalRules:        NewRulesValidator(DefaultValidationRules()),
+	}, nil
 }
 
 // ValidateOrder validates an order by calling an external service
@@ -37,48 +125,53 @@ func (a *OrderActivities) ValidateOrder(ctx context.Context, order models.Order)
 		logger.Info("Validating order", "order_id", order.ID, "amount", order.Amount)
 	}
 
-	validationReq := models.ValidationRequest{
-		OrderID: order.ID,
-		Amount:  order.Amount,
-		Items:   order.Items,
-	}
-
-	jsonData, err := json.Marshal(validationReq)
+	validationResp, err := a.HTTPValidator().Validate(ctx, order)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal validation request: %w", err)
+		return nil, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", a.ValidationURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	if activity.IsActivity(ctx) {
+		logger := activity.GetLogger(ctx)
+		logger.Info("Order validation completed", "order_id", order.ID, "valid", validationResp.Valid)
 	}
-	req.Header.Set("Content-Type", "application/json")
+	return validationResp, nil
+}
 
-	resp, err := a.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to call validation service: %w", err)
+// HTTPValidator returns a Validator that calls the remote validation service
+// using this instance's client, circuit breaker, rate limiter, and credentials
+func (a *OrderActivities) HTTPValidator() *HTTPValidator {
+	return &HTTPValidator{
+		Client:  a.HTTPClient,
+		URL:     a.ValidationURL,
+		Breaker: a.ValidationBreaker,
+		Limiter: a.ValidationLimiter,
+		Auth:    a.ValidationAuth,
 	}
-	defer resp.Body.Close()
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+// ValidateOrderLocally validates an order against the local rules engine.
+// The workflow uses it when the remote validation service is down.
+func (a *OrderActivities) ValidateOrderLocally(ctx context.Context, order models.Order) (*models.ValidationResponse, error) {
+	if activity.IsActivity(ctx) {
+		logger := activity.GetLogger(ctx)
+		logger.Info("Validating order with local rules", "order_id", order.ID, "amount", order.Amount)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("validation service returned status %d: %s", resp.StatusCode, string(body))
+	rules := a.LocalRules
+	if rules == nil {
+		rules = NewRulesValidator(DefaultValidationRules())
 	}
 
-	var validationResp models.ValidationResponse
-	if err := json.Unmarshal(body, &validationResp); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal validation response: %w", err)
+	validationResp, err := rules.Validate(ctx, order)
+	if err != nil {
+		return nil, err
 	}
 
 	if activity.IsActivity(ctx) {
 		logger := activity.GetLogger(ctx)
-		logger.Info("Order validation completed", "order_id", order.ID, "valid", validationResp.Valid)
+		logger.Info("Local order validation completed", "order_id", order.ID, "valid", validationResp.Valid)
 	}
-	return &validationResp, nil
+	return validationResp, nil
 }
 
 // ProcessOrder processes the order (simulates business logic)
@@ -89,62 +182,235 @@ func (a *OrderActivities) ProcessOrder(ctx context.Context, order models.Order,
 		logger.Info("Processing order", "order_id", order.ID, "expedited", isExpedited)
 	}
 
+	if missing := a.outOfStock(order.Items); len(missing) > 0 {
+		return (&models.InventoryOutOfStockError{OrderID: order.ID, Items: missing}).ApplicationError()
+	}
+
 	// Simulate processing time (for demo - allows time to send signals)
 	processingTime := 15 * time.Second
+	if a.ProcessingTime > 0 {
+		processingTime = a.ProcessingTime
+	}
 	if isExpedited {
 		processingTime = 5 * time.Second
+		if a.ExpeditedProcessingTime > 0 {
+			processingTime = a.ExpeditedProcessingTime
+		}
 		if isActivityCtx {
 			logger := activity.GetLogger(ctx)
 			logger.Info("Expedited processing enabled", "order_id", order.ID)
 		}
 	}
 
+	// Work is split evenly across items so progress can be checkpointed per item
+	units := len(order.Items)
+	if units == 0 {
+		units = 1
+	}
+	perUnit := processingTime / time.Duration(units)
+
+	// Resume from the last checkpoint when this is a retry
+	var progress models.ProcessingProgress
+	if isActivityCtx && activity.HasHeartbeatDetails(ctx) {
+		if err := activity.GetHeartbeatDetails(ctx, &progress); err == nil && progress.ItemsProcessed < units {
+			logger := activity.GetLogger(ctx)
+			logger.Info("Resuming order processing", "order_id", order.ID,
+				"items_processed", progress.ItemsProcessed, "last_processed_item", progress.LastProcessedItem)
+		} else {
+			progress = models.ProcessingProgress{}
+		}
+	}
+
 	// Use activity heartbeat for long-running operations
 	heartbeatInterval := 1 * time.Second
 	ticker := time.NewTicker(heartbeatInterval)
 	defer ticker.Stop()
 
-	done := time.After(processingTime)
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-done:
-			if isActivityCtx {
-				logger := activity.GetLogger(ctx)
-				logger.Info("Order processing completed", "order_id", order.ID)
-			}
-			return nil
-		case <-ticker.C:
-			if isActivityCtx {
-				activity.RecordHeartbeat(ctx, "processing")
+	for i := progress.ItemsProcessed; i < units; i++ {
+		done := time.After(perUnit)
+	item:
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-done:
+				break item
+			case <-ticker.C:
+				if isActivityCtx {
+					activity.RecordHeartbeat(ctx, progress)
+				}
 			}
 		}
+
+		progress.ItemsProcessed = i + 1
+		if i < len(order.Items) {
+			progress.LastProcessedItem = order.Items[i]
+		}
+		progress.PercentComplete = float64(progress.ItemsProcessed) * 100 / float64(units)
+		if isActivityCtx {
+			activity.RecordHeartbeat(ctx, progress)
+		}
 	}
+
+	if isActivityCtx {
+		logger := activity.GetLogger(ctx)
+		logger.Info("Order processing completed", "order_id", order.ID)
+	}
+	return nil
 }
 
-// NotifyOrderComplete sends a notification that the order is complete
-func (a *OrderActivities) NotifyOrderComplete(ctx context.Context, order models.Order) error {
+// FulfillItem fulfills a single order item. The workflow runs one per item,
+// in parallel, for orders processed with per-item fulfillment.
+func (a *OrderActivities) FulfillItem(ctx context.Context, order models.Order, item string, isExpedited bool) error {
+	isActivityCtx := activity.IsActivity(ctx)
+	if isActivityCtx {
+		logger := activity.GetLogger(ctx)
+		logger.Info("Fulfilling item", "order_id", order.ID, "item", item, "expedited", isExpedited)
+	}
+
+	if missing := a.outOfStock([]string{item}); len(missing) > 0 {
+		return (&models.InventoryOutOfStockError{OrderID: order.ID, Items: missing}).ApplicationError()
+	}
+
+	// Simulate picking and packing the item
+	if err := simulateWork(ctx, a.itemFulfillmentTime(isExpedited), item); err != nil {
+		return err
+	}
+
+	if isActivityCtx {
+		logger := activity.GetLogger(ctx)
+		logger.Info("Item fulfilled", "order_id", order.ID, "item", item)
+	}
+	return nil
+}
+
+// CheckStock returns the items in the list that are still out of stock. A
+// backordered order calls it periodically to notice a restock on its own.
+func (a *OrderActivities) CheckStock(ctx context.Context, items []string) ([]string, error) {
+	missing := a.outOfStock(items)
 	if activity.IsActivity(ctx) {
 		logger := activity.GetLogger(ctx)
-		logger.Info("Sending completion notification", "order_id", order.ID)
+		logger.Info("Checked stock", "items", items, "out_of_stock", missing)
 	}
+	return missing, nil
+}
 
-	// Simulate notification logic (reduced for demo)
-	time.Sleep(200 * time.Millisecond)
+// outOfStock returns the items in the list that are out of stock
+func (a *OrderActivities) outOfStock(items []string) []string {
+	var missing []string
+	for _, item := range items {
+		for _, oos := range a.OutOfStockItems {
+			if item == oos {
+				missing = append(missing, item)
+				break
+			}
+		}
+	}
+	return missing
+}
+
+// NotifyOrderComplete sends a notification that the order is complete. When
+// the order carries its customer, each channel goes to the customer's email
+// or phone, and a channel the customer has no contact for is skipped. When it
+// carries its shipping, the notification states the shipping method, the
+// carrier, and the promised delivery. Notifications are posted to
+// NotificationURL, or simulated without one.
+func (a *OrderActivities) NotifyOrderComplete(ctx context.Context, order models.Order) error {
+	// Each channel is switched on or off by its feature flag
+	var channels []string
+	if a.flags().Enabled(ctx, featureflags.EmailNotifications) {
+		channels = append(channels, "email")
+	}
+	if a.flags().Enabled(ctx, featureflags.SMSNotifications) {
+		channels = append(channels, "sms")
+	}
+
+	if len(channels) == 0 {
+		if activity.IsActivity(ctx) {
+			activity.GetLogger(ctx).Info("No notification channels enabled", "order_id", order.ID)
+		}
+		return nil
+	}
+
+	var sent []string
+	for _, channel := range channels {
+		if order.Customer != nil && order.Customer.Contact(channel) == "" {
+			if activity.IsActivity(ctx) {
+				activity.GetLogger(ctx).Info("Customer has no contact for channel, skipping", "order_id", order.ID, "customer_id", order.Customer.ID, "channel", channel)
+			}
+			continue
+		}
+		notification := models.NewOrderNotification(order, channel)
+		if activity.IsActivity(ctx) {
+			logger := activity.GetLogger(ctx)
+			logger.Info("Sending completion notification", "order_id", order.ID, "channel", channel,
+				"shipping_method", notification.ShippingMethod, "promised_by", notification.PromisedBy)
+		}
+
+		if a.NotificationURL == "" {
+			// Simulate notification logic (reduced for demo)
+			time.Sleep(200 * time.Millisecond)
+		} else if err := a.postAlert(ctx, "notification", a.NotificationURL, notification); err != nil {
+			return err
+		}
+		sent = append(sent, channel)
+	}
 
 	if activity.IsActivity(ctx) {
 		logger := activity.GetLogger(ctx)
-		logger.Info("Notification sent successfully", "order_id", order.ID)
+		logger.Info("Notification sent successfully", "order_id", order.ID, "channels", sent)
 	}
 	return nil
 }
 
+// flags returns the feature flags gating optional activity behavior
+func (a *OrderActivities) flags() *featureflags.Flags {
+	if a.Flags != nil {
+		return a.Flags
+	}
+	return featureflags.Default()
+}
+
 // ProcessPayment handles payment processing
 func (a *OrderActivities) ProcessPayment(ctx context.Context, paymentReq models.PaymentRequest) (*models.PaymentResponse, error) {
 	// Simulate payment processing (reduced for demo)
 	time.Sleep(500 * time.Millisecond)
 
+	// A decline is final: retrying the same card would only be declined again
+	if paymentReq.Amount <= 0 {
+		return nil, (&models.PaymentDeclinedError{
+			OrderID:     paymentReq.OrderID,
+			Amount:      paymentReq.Amount,
+			DeclineCode: "invalid_amount",
+			Reason:      "Payment amount must be positive",
+		}).ApplicationError()
+	}
+	if a.PaymentDeclineOver > 0 && paymentReq.Amount > a.PaymentDeclineOver {
+		return nil, (&models.PaymentDeclinedError{
+			OrderID:     paymentReq.OrderID,
+			Amount:      paymentReq.Amount,
+			DeclineCode: "insufficient_funds",
+			Reason:      fmt.Sprintf("Amount exceeds available funds of $%.2f", a.PaymentDeclineOver),
+		}).ApplicationError()
+	}
+
+	// An incomplete payment method cannot be routed, and never will be
+	var route string
+	if paymentReq.PaymentMethod != nil {
+		if err := paymentReq.PaymentMethod.Validate(); err != nil {
+			return nil, (&models.PaymentDeclinedError{
+				OrderID:     paymentReq.OrderID,
+				Amount:      paymentReq.Amount,
+				DeclineCode: "invalid_payment_method",
+				Reason:      err.Error(),
+			}).ApplicationError()
+		}
+		route = paymentReq.PaymentMethod.Route()
+		if activity.IsActivity(ctx) {
+			activity.GetLogger(ctx).Info("Routing payment", "order_id", paymentReq.OrderID, "type", paymentReq.PaymentMethod.Type, "route", route)
+		}
+	}
+
 	// Generate a mock transaction ID
 	transactionID := fmt.Sprintf("TXN-%s-%d", paymentReq.OrderID, time.Now().Unix())
 
@@ -152,7 +418,21 @@ func (a *OrderActivities) ProcessPayment(ctx context.Context, paymentReq models.
 		Success:       true,
 		TransactionID: transactionID,
 		Message:       "Payment processed successfully",
+		Route:         route,
 	}
 
 	return response, nil
 }
+
+// RefundPayment reverses a captured payment. It is the compensation for a
+// payment whose order was cancelled before fulfillment.
+func (a *OrderActivities) RefundPayment(ctx context.Context, refund models.RefundRequest) error {
+	// Simulate the refund call (reduced for demo)
+	time.Sleep(200 * time.Millisecond)
+
+	if activity.IsActivity(ctx) {
+		logger := activity.GetLogger(ctx)
+		logger.Info("Payment refunded", "order_id", refund.OrderID, "transaction_id", refund.TransactionID, "amount", refund.Amount)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package activities
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.temporal.io/sdk/activity"
+	"golang.org/x/time/rate"
+)
+
+// RateLimitConfig configures client-side rate limiting of a downstream service.
+// The limit applies per worker process, so divide the service quota by the
+// number of workers when running more than one.
+type RateLimitConfig struct {
+	// RequestsPerSecond is the sustained request rate
+	RequestsPerSecond float64
+	// Burst is the number of requests allowed at once above the sustained rate
+	Burst int
+	// MaxWait is the longest a request may queue for a token before giving up
+	MaxWait time.Duration
+	// HeartbeatInterval is how often a queued activity heartbeats while waiting
+	HeartbeatInterval time.Duration
+}
+
+// DefaultRateLimitConfig returns the default rate limit settings
+func DefaultRateLimitConfig() RateLimitConfig {
+	return RateLimitConfig{
+		RequestsPerSecond: 10,
+		Burst:             10,
+		MaxWait:           20 * time.Second,
+		HeartbeatInterval: time.Second,
+	}
+}
+
+// RateLimiter is a token-bucket limiter shared by all executions of an
+// activity in a worker. Waiters are served in arrival order.
+type RateLimiter struct {
+	name    string
+	config  RateLimitConfig
+	limiter *rate.Limiter
+}
+
+// NewRateLimiter creates a token-bucket rate limiter for the named service
+func NewRateLimiter(name string, config RateLimitConfig) *RateLimiter {
+	defaults := DefaultRateLimitConfig()
+	if config.RequestsPerSecond <= 0 {
+		config.RequestsPerSecond = defaults.RequestsPerSecond
+	}
+	if config.Burst <= 0 {
+		config.Burst = 1
+	}
+	if config.MaxWait <= 0 {
+		config.MaxWait = defaults.MaxWait
+	}
+	if config.HeartbeatInterval <= 0 {
+		config.HeartbeatInterval = defaults.HeartbeatInterval
+	}
+
+	return &RateLimiter{
+		name:    name,
+		config:  config,
+		limiter: rate.NewLimiter(rate.Limit(config.RequestsPerSecond), config.Burst),
+	}
+}
+
+// Wait blocks until a token is available. While queued inside an activity it
+// records heartbeats so a long wait is not mistaken for a stuck worker.
+func (rl *RateLimiter) Wait(ctx context.Context) error {
+	reservation := rl.limiter.Reserve()
+	if !reservation.OK() {
+		return fmt.Errorf("%s rate limiter cannot satisfy request", rl.name)
+	}
+
+	delay := reservation.Delay()
+	if delay == 0 {
+		return nil
+	}
+	if delay > rl.config.MaxWait {
+		reservation.Cancel()
+		return fmt.Errorf("%s rate limit queue full: wait of %s exceeds %s", rl.name, delay, rl.config.MaxWait)
+	}
+
+	isActivityCtx := activity.IsActivity(ctx)
+	if isActivityCtx {
+		logger := activity.GetLogger(ctx)
+		logger.Info("Waiting for rate limit token", "service", rl.name, "delay", delay)
+	}
+
+	ticker := time.NewTicker(rl.config.HeartbeatInterval)
+	defer ticker.Stop()
+
+	ready := time.After(delay)
+	for {
+		select {
+		case <-ctx.Done():
+			// Return the token so later waiters are not delayed by us
+			reservation.Cancel()
+			return ctx.Err()
+		case <-ready:
+			return nil
+		case <-ticker.C:
+			if isActivityCtx {
+				activity.RecordHeartbeat(ctx, "waiting for rate limit")
+			}
+		}
+	}
+}
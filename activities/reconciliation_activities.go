@@ -0,0 +1,80 @@
+package activities
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/correlation"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
+)
+
+// reconciliationAlert is the payload posted to the finance webhook
+type reconciliationAlert struct {
+	Text   string                      `json:"text"`
+	Report models.ReconciliationReport `json:"report"`
+}
+
+// FetchGatewayTransactions reads the captures and refunds the payment
+// gateway made in a reconciliation window. Payments are only simulated
+// without a gateway API configured, so there is nothing to reconcile and it
+// fails with a non-retryable GatewayNotConfigured error.
+func (a *OrderActivities) FetchGatewayTransactions(ctx context.Context, window models.ReconciliationWindow) ([]models.GatewayTransaction, error) {
+	if a.PaymentGatewayURL == "" {
+		return nil, temporal.NewNonRetryableApplicationError(
+			"no payment gateway API is configured to reconcile against", models.ErrTypeGatewayNotConfigured, nil)
+	}
+
+	query := url.Values{}
+	query.Set("from", window.From.Format(time.RFC3339))
+	query.Set("to", window.To.Format(time.RFC3339))
+	req, err := http.NewRequestWithContext(ctx, "GET", a.PaymentGatewayURL+"/transactions?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gateway request: %w", err)
+	}
+	correlation.SetHeaders(ctx, req)
+
+	resp, err := a.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call payment gateway: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("payment gateway returned status %d: %s", resp.StatusCode, message)
+	}
+	var transactions []models.GatewayTransaction
+	if err := json.NewDecoder(resp.Body).Decode(&transactions); err != nil {
+		return nil, fmt.Errorf("failed to decode gateway transactions: %w", err)
+	}
+
+	if activity.IsActivity(ctx) {
+		logger := activity.GetLogger(ctx)
+		logger.Info("Fetched gateway transactions", "from", window.From, "to", window.To, "transactions", len(transactions))
+	}
+	return transactions, nil
+}
+
+// ReportDiscrepancies sends finance a day's reconciliation report. Without a
+// finance webhook configured the report is only logged.
+func (a *OrderActivities) ReportDiscrepancies(ctx context.Context, report models.ReconciliationReport) error {
+	text := fmt.Sprintf("Payments for %s reconciled: %d of %d completed orders matched %d gateway transactions.",
+		report.Date, report.Matched, report.Orders, report.Transactions)
+	if len(report.Discrepancies) > 0 {
+		text += fmt.Sprintf(" %d discrepancies need review.", len(report.Discrepancies))
+	}
+
+	if activity.IsActivity(ctx) {
+		logger := activity.GetLogger(ctx)
+		logger.Info("Payments reconciled", "date", report.Date, "orders", report.Orders, "transactions", report.Transactions,
+			"matched", report.Matched, "discrepancies", len(report.Discrepancies))
+	}
+	return a.postAlert(ctx, "finance", a.FinanceWebhookURL, reconciliationAlert{Text: text, Report: report})
+}
@@ -0,0 +1,87 @@
+package activities
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/aswathylr-builds/temporal-order-processing/correlation"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/activity"
+)
+
+// GetCustomerHistory looks up how many orders a customer has placed and how
+// many were charged back. A customer the customer service does not know has
+// no history. Without a customer service configured every customer is new.
+func (a *OrderActivities) GetCustomerHistory(ctx context.Context, customerID string) (*models.CustomerHistory, error) {
+	history := &models.CustomerHistory{CustomerID: customerID}
+	if a.CustomerURL != "" {
+		req, err := http.NewRequestWithContext(ctx, "GET", a.CustomerURL+"/customers/"+url.PathEscape(customerID)+"/history", nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create customer history request: %w", err)
+		}
+		correlation.SetHeaders(ctx, req)
+
+		resp, err := a.HTTPClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to call customer service: %w", err)
+		}
+		defer resp.Body.Close()
+
+		switch {
+		case resp.StatusCode == http.StatusNotFound:
+		case resp.StatusCode >= 300:
+			message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+			return nil, fmt.Errorf("customer service returned status %d: %s", resp.StatusCode, bytes.TrimSpace(message))
+		default:
+			if err := json.NewDecoder(resp.Body).Decode(history); err != nil {
+				return nil, fmt.Errorf("failed to decode customer history: %w", err)
+			}
+			history.CustomerID = customerID
+		}
+	}
+
+	if activity.IsActivity(ctx) {
+		activity.GetLogger(ctx).Info("Customer history fetched", "customer_id", customerID, "orders", history.Orders, "chargebacks", history.Chargebacks)
+	}
+	return history, nil
+}
+
+// RequestStepUpVerification asks the customer service to challenge an
+// order's customer, such as with a one-time code. The customer's answer
+// reaches the order later, by the verify signal. Without a customer service
+// configured the challenge is only logged.
+func (a *OrderActivities) RequestStepUpVerification(ctx context.Context, stepUp models.StepUpRequest) error {
+	if a.CustomerURL != "" {
+		body, err := json.Marshal(stepUp)
+		if err != nil {
+			return fmt.Errorf("failed to marshal step-up request: %w", err)
+		}
+		req, err := http.NewRequestWithContext(ctx, "POST", a.CustomerURL+"/customers/"+url.PathEscape(stepUp.CustomerID)+"/verifications", bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("failed to create step-up request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		correlation.SetHeaders(ctx, req)
+
+		resp, err := a.HTTPClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to call customer service: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode >= 300 {
+			message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+			return fmt.Errorf("customer service returned status %d: %s", resp.StatusCode, bytes.TrimSpace(message))
+		}
+	}
+
+	if activity.IsActivity(ctx) {
+		activity.GetLogger(ctx).Info("Step-up verification requested", "order_id", stepUp.OrderID, "customer_id", stepUp.CustomerID)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package activities
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+
+	"github.com/aswathylr-builds/temporal-order-processing/correlation"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/activity"
+)
+
+// DenylistName is the list a customer on ScreeningDenylist is reported on
+const DenylistName = "denylist"
+
+// ScreenCustomer checks an order's customer against the configured denylist
+// and, when a screening API is configured, its sanctions lists. A match fails
+// with a non-retryable CustomerDenied error; a failing API is retried.
+func (a *OrderActivities) ScreenCustomer(ctx context.Context, req models.ScreeningRequest) (*models.ScreeningResult, error) {
+	result := &models.ScreeningResult{}
+	if slices.Contains(a.ScreeningDenylist, req.CustomerID) {
+		result = &models.ScreeningResult{Match: true, List: DenylistName, Reason: "customer is on the denylist"}
+	} else if a.ScreeningURL != "" {
+		var err error
+		if result, err = a.callScreening(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+
+	if activity.IsActivity(ctx) {
+		activity.GetLogger(ctx).Info("Customer screened", "order_id", req.OrderID, "customer_id", req.CustomerID, "match", result.Match, "list", result.List)
+	}
+
+	if result.Match {
+		return nil, (&models.CustomerDeniedError{
+			OrderID:    req.OrderID,
+			CustomerID: req.CustomerID,
+			List:       result.List,
+			Reason:     result.Reason,
+		}).ApplicationError()
+	}
+	return result, nil
+}
+
+// callScreening asks the screening API whether the customer is on a list
+func (a *OrderActivities) callScreening(ctx context.Context, screening models.ScreeningRequest) (*models.ScreeningResult, error) {
+	body, err := json.Marshal(screening)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal screening request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", a.ScreeningURL+"/screen", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create screening request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	correlation.SetHeaders(ctx, req)
+
+	resp, err := a.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call screening API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("screening API returned status %d: %s", resp.StatusCode, message)
+	}
+	var result models.ScreeningResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode screening response: %w", err)
+	}
+	return &result, nil
+}
@@ -0,0 +1,86 @@
+package activities
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/aswathylr-builds/temporal-order-processing/correlation"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
+)
+
+// settlementAlert is the payload posted to the finance webhook for a day's
+// settlement
+type settlementAlert struct {
+	Text   string                  `json:"text"`
+	Report models.SettlementReport `json:"report"`
+}
+
+// SettleBatch asks the payment gateway to settle a batch of payments into
+// its merchant account and returns the settlement ID. The batch ID makes a
+// retried or repeated settlement idempotent. A batch the gateway refuses
+// fails with a non-retryable SettlementRejected error.
+func (a *OrderActivities) SettleBatch(ctx context.Context, batch models.SettlementBatch) (*models.SettlementResult, error) {
+	if a.PaymentGatewayURL == "" {
+		return nil, temporal.NewNonRetryableApplicationError(
+			"no payment gateway API is configured to settle through", models.ErrTypeGatewayNotConfigured, nil)
+	}
+
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal settlement batch: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", a.PaymentGatewayURL+"/settlements", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create settlement request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", batch.ID)
+	correlation.SetHeaders(ctx, req)
+
+	resp, err := a.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call payment gateway: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			return nil, temporal.NewNonRetryableApplicationError(
+				fmt.Sprintf("payment gateway rejected settlement batch %s: %s", batch.ID, message), models.ErrTypeSettlementRejected, nil)
+		}
+		return nil, fmt.Errorf("payment gateway returned status %d: %s", resp.StatusCode, message)
+	}
+	var result models.SettlementResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode settlement response: %w", err)
+	}
+
+	if activity.IsActivity(ctx) {
+		logger := activity.GetLogger(ctx)
+		logger.Info("Settlement batch settled", "batch_id", batch.ID, "merchant_account", batch.MerchantAccount,
+			"amount", batch.Amount, "settlement_id", result.SettlementID)
+	}
+	return &result, nil
+}
+
+// ReportSettlement sends finance a day's settlement report. Without a
+// finance webhook configured the report is only logged.
+func (a *OrderActivities) ReportSettlement(ctx context.Context, report models.SettlementReport) error {
+	text := fmt.Sprintf("Payments for %s settled: %d of %d batches.", report.Date, report.Settled, len(report.Batches))
+	if report.Failed > 0 {
+		text += fmt.Sprintf(" %d batches failed and need review.", report.Failed)
+	}
+
+	if activity.IsActivity(ctx) {
+		logger := activity.GetLogger(ctx)
+		logger.Info("Payments settled", "date", report.Date, "batches", len(report.Batches), "settled", report.Settled, "failed", report.Failed)
+	}
+	return a.postAlert(ctx, "finance", a.FinanceWebhookURL, settlementAlert{Text: text, Report: report})
+}
@@ -0,0 +1,84 @@
+package activities
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/correlation"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/activity"
+)
+
+// simulatedTransitPolls is the poll on which the simulated carrier reports a
+// shipment delivered
+const simulatedTransitPolls = 3
+
+// TrackShipment asks the carrier for a shipment's status, naming the
+// request's carrier, if any, to the carrier API. Without a carrier API
+// configured, shipments are simulated: in transit, then out for delivery,
+// then delivered on the third poll.
+func (a *OrderActivities) TrackShipment(ctx context.Context, req models.TrackingRequest) (*models.TrackingUpdate, error) {
+	var update *models.TrackingUpdate
+	if a.CarrierURL == "" {
+		update = simulateTracking(req)
+	} else {
+		var err error
+		if update, err = a.callCarrier(ctx, req.ShipmentID, req.Carrier); err != nil {
+			return nil, err
+		}
+	}
+
+	if activity.IsActivity(ctx) {
+		logger := activity.GetLogger(ctx)
+		logger.Info("Shipment tracked", "order_id", req.OrderID, "shipment_id", req.ShipmentID, "carrier", req.Carrier, "poll", req.Poll, "status", update.Status)
+	}
+	return update, nil
+}
+
+// simulateTracking returns the simulated carrier's status for the request
+func simulateTracking(req models.TrackingRequest) *models.TrackingUpdate {
+	update := &models.TrackingUpdate{ShipmentID: req.ShipmentID, Status: models.ShipmentInTransit, At: time.Now()}
+	switch {
+	case req.Poll >= simulatedTransitPolls:
+		update.Status = models.ShipmentDelivered
+	case req.Poll == simulatedTransitPolls-1:
+		update.Status = models.ShipmentOutForDelivery
+	}
+	return update
+}
+
+// callCarrier gets the shipment's status from the carrier API, passing the
+// carrier, when named, as the carrier query parameter
+func (a *OrderActivities) callCarrier(ctx context.Context, shipmentID, carrier string) (*models.TrackingUpdate, error) {
+	endpoint := a.CarrierURL + "/shipments/" + url.PathEscape(shipmentID)
+	if carrier != "" {
+		endpoint += "?" + url.Values{"carrier": {carrier}}.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create carrier request: %w", err)
+	}
+	correlation.SetHeaders(ctx, req)
+
+	resp, err := a.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call carrier: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("carrier returned status %d: %s", resp.StatusCode, message)
+	}
+	var update models.TrackingUpdate
+	if err := json.NewDecoder(resp.Body).Decode(&update); err != nil {
+		return nil, fmt.Errorf("failed to decode carrier response: %w", err)
+	}
+	update.ShipmentID = shipmentID
+	return &update, nil
+}
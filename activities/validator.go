@@ -0,0 +1,185 @@
+package activities
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/aswathylr-builds/temporal-order-processing/correlation"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/temporal"
+)
+
+// Validator decides whether an order may proceed
+type Validator interface {
+	Validate(ctx context.Context, order models.Order) (*models.ValidationResponse, error)
+}
+
+// HTTPValidator validates orders by calling the remote validation service
+type HTTPValidator struct {
+	Client *http.Client
+	URL    string
+	// Breaker, Limiter, and Auth are optional
+	Breaker *CircuitBreaker
+	Limiter *RateLimiter
+	Auth    RequestAuthenticator
+}
+
+// Validate posts the order to the validation service and decodes its verdict
+func (v *HTTPValidator) Validate(ctx context.Context, order models.Order) (*models.ValidationResponse, error) {
+	validationReq := models.ValidationRequest{
+		OrderID:  order.ID,
+		Amount:   order.Amount,
+		Items:    order.Items,
+		Customer: order.Customer,
+	}
+
+	jsonData, err := json.Marshal(validationReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal validation request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", v.URL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	correlation.SetHeaders(ctx, req)
+
+	if v.Auth != nil {
+		if err := v.Auth.Authenticate(req); err != nil {
+			return nil, fmt.Errorf("failed to authenticate validation request: %w", err)
+		}
+	}
+
+	if v.Limiter != nil {
+		if err := v.Limiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("failed to acquire validation rate limit: %w", err)
+		}
+	}
+
+	resp, err := v.do(req)
+	if err != nil {
+		var unavailable *ServiceUnavailableError
+		if errors.As(err, &unavailable) {
+			// Surface a typed error so the workflow can back off for longer,
+			// and don't let Temporal retry before the circuit may close
+			return nil, temporal.NewApplicationErrorWithOptions(unavailable.Error(), models.ErrTypeServiceUnavailable,
+				temporal.ApplicationErrorOptions{NextRetryDelay: unavailable.RetryAfter})
+		}
+		// A transport failure is an outage the workflow may fall back from
+		return nil, temporal.NewApplicationErrorWithCause("failed to call validation service: "+err.Error(), models.ErrTypeServiceUnreachable, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return nil, temporal.NewApplicationError(fmt.Sprintf("validation service returned status %d: %s", resp.StatusCode, string(body)), models.ErrTypeServiceUnreachable)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("validation service returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var validationResp models.ValidationResponse
+	if err := json.Unmarshal(body, &validationResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal validation response: %w", err)
+	}
+	return &validationResp, nil
+}
+
+// do sends the request through the circuit breaker when one is configured
+func (v *HTTPValidator) do(req *http.Request) (*http.Response, error) {
+	if v.Breaker == nil {
+		return v.Client.Do(req)
+	}
+	return v.Breaker.Do(v.Client, req)
+}
+
+// ValidationRules are the limits enforced by the local rules engine
+type ValidationRules struct {
+	// MaxAmount rejects orders at or above this amount; zero disables the check
+	MaxAmount float64
+	// AllowedItems restricts orders to these items; empty allows any item
+	AllowedItems []string
+	// MaxQuantityPerItem caps how many times one item may appear; zero disables the check
+	MaxQuantityPerItem int
+	// MaxItems caps the total number of items; zero disables the check
+	MaxItems int
+}
+
+// DefaultValidationRules mirrors the remote service's limits
+func DefaultValidationRules() ValidationRules {
+	return ValidationRules{
+		MaxAmount:          10000,
+		MaxQuantityPerItem: models.MaxLineQuantity,
+		MaxItems:           50,
+	}
+}
+
+// RulesValidator validates orders locally against a fixed set of rules.
+// Its verdicts are marked provisional because the remote service may apply
+// checks the local rules do not know about.
+type RulesValidator struct {
+	rules   ValidationRules
+	allowed map[string]bool
+}
+
+// NewRulesValidator creates a local rules-engine validator
+func NewRulesValidator(rules ValidationRules) *RulesValidator {
+	var allowed map[string]bool
+	if len(rules.AllowedItems) > 0 {
+		allowed = make(map[string]bool, len(rules.AllowedItems))
+		for _, item := range rules.AllowedItems {
+			allowed[item] = true
+		}
+	}
+	return &RulesValidator{rules: rules, allowed: allowed}
+}
+
+// Validate checks the order against each rule and reports the first violation
+func (v *RulesValidator) Validate(ctx context.Context, order models.Order) (*models.ValidationResponse, error) {
+	reject := func(format string, args ...interface{}) (*models.ValidationResponse, error) {
+		return &models.ValidationResponse{
+			Valid:       false,
+			Message:     fmt.Sprintf(format, args...),
+			Provisional: true,
+		}, nil
+	}
+
+	if v.rules.MaxAmount > 0 && order.Amount >= v.rules.MaxAmount {
+		return reject("Amount exceeds maximum allowed limit of $%.2f", v.rules.MaxAmount)
+	}
+	if v.rules.MaxItems > 0 && len(order.Items) > v.rules.MaxItems {
+		return reject("Order has %d items, maximum is %d", len(order.Items), v.rules.MaxItems)
+	}
+
+	quantities := make(map[string]int, len(order.Items))
+	for _, item := range order.Items {
+		if v.allowed != nil && !v.allowed[item] {
+			return reject("Item %q is not allowed", item)
+		}
+		quantities[item]++
+		if v.rules.MaxQuantityPerItem > 0 && quantities[item] > v.rules.MaxQuantityPerItem {
+			return reject("Item %q exceeds maximum quantity of %d", item, v.rules.MaxQuantityPerItem)
+		}
+	}
+	if order.Customer != nil {
+		if err := order.Customer.Validate(); err != nil {
+			return reject("Invalid customer: %v", err)
+		}
+	}
+
+	return &models.ValidationResponse{
+		Valid:       true,
+		Message:     "Order provisionally validated by local rules",
+		Provisional: true,
+	}, nil
+}
@@ -0,0 +1,104 @@
+package activities
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/aswathylr-builds/temporal-order-processing/correlation"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/activity"
+)
+
+// vendorAlert is the payload posted to the ops webhook for a purchase order
+// its vendor has not answered
+type vendorAlert struct {
+	Text          string                   `json:"text"`
+	PurchaseOrder models.VendorFulfillment `json:"purchase_order"`
+}
+
+// SendPurchaseOrder sends a purchase order to its vendor through the
+// marketplace API. The vendor's answer is returned when the API gives it at
+// once; a nil response means the vendor answers later, by the
+// vendor-response signal. Without a marketplace API configured, vendors are
+// simulated and accept at once.
+func (a *OrderActivities) SendPurchaseOrder(ctx context.Context, po models.PurchaseOrder) (*models.VendorResponse, error) {
+	var response *models.VendorResponse
+	if a.VendorURL == "" {
+		response = &models.VendorResponse{Accepted: true}
+	} else {
+		var err error
+		if response, err = a.callVendor(ctx, po); err != nil {
+			return nil, err
+		}
+	}
+
+	if activity.IsActivity(ctx) {
+		logger := activity.GetLogger(ctx)
+		logger.Info("Purchase order sent", "order_id", po.OrderID, "purchase_order", po.Number, "vendor_id", po.VendorID, "answered", response != nil)
+	}
+	return response, nil
+}
+
+// callVendor posts the purchase order to the marketplace API. A 202 Accepted
+// means the vendor answers later.
+func (a *OrderActivities) callVendor(ctx context.Context, po models.PurchaseOrder) (*models.VendorResponse, error) {
+	body, err := json.Marshal(po)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal purchase order: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", a.VendorURL+"/purchase-orders", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create vendor request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// The purchase order number makes a retried send idempotent
+	req.Header.Set("Idempotency-Key", po.Number)
+	correlation.SetHeaders(ctx, req)
+
+	resp, err := a.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call vendor API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusAccepted:
+		return nil, nil
+	case resp.StatusCode >= 300:
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("vendor API returned status %d: %s", resp.StatusCode, message)
+	}
+	var response models.VendorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode vendor response: %w", err)
+	}
+	return &response, nil
+}
+
+// NotifyOpsOfUnresponsiveVendor alerts the operations team that a vendor has
+// not answered a purchase order in time. Without an ops webhook configured
+// the alert is only logged.
+func (a *OrderActivities) NotifyOpsOfUnresponsiveVendor(ctx context.Context, fulfillment models.VendorFulfillment) error {
+	text := fmt.Sprintf("Vendor %s has not answered purchase order %s for items %v. Send the %q signal to workflow %s with the vendor's answer.",
+		fulfillment.VendorID, fulfillment.PurchaseOrder, fulfillment.Items, models.SignalVendorResponse, activityWorkflowID(ctx))
+
+	if activity.IsActivity(ctx) {
+		logger := activity.GetLogger(ctx)
+		logger.Warn("Vendor unresponsive", "purchase_order", fulfillment.PurchaseOrder, "vendor_id", fulfillment.VendorID)
+	}
+
+	return a.postOpsAlert(ctx, vendorAlert{Text: text, PurchaseOrder: fulfillment})
+}
+
+// activityWorkflowID returns the ID of the workflow running the activity,
+// empty outside an activity
+func activityWorkflowID(ctx context.Context) string {
+	if !activity.IsActivity(ctx) {
+		return ""
+	}
+	return activity.GetInfo(ctx).WorkflowExecution.ID
+}
@@ -0,0 +1,86 @@
+package activities
+
+import (
+	"context"
+	"slices"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/activity"
+)
+
+const (
+	// DefaultWarehouseDistance is how far, in km, a warehouse is taken to be
+	// from a region its distances do not list
+	DefaultWarehouseDistance = 1000.0
+
+	// warehouseItemScore is what stocking one more of an order's items is
+	// worth against distance, in km, when scoring warehouses, so that orders
+	// ship from as few warehouses as possible and distance breaks ties
+	warehouseItemScore = 10000.0
+)
+
+// defaultWarehouses stands in when no warehouses are configured: one
+// warehouse carrying every item
+var defaultWarehouses = []models.Warehouse{{ID: "main"}}
+
+// SelectWarehouse routes an order's items to warehouses. Each warehouse is
+// scored by how many of the unrouted items it stocks, less its distance to
+// the order's region; the best takes the items it stocks, and scoring repeats
+// until every item is routed or no warehouse stocks the rest.
+func (a *OrderActivities) SelectWarehouse(ctx context.Context, req models.WarehouseRequest) (*models.WarehouseRouting, error) {
+	warehouses := a.Warehouses
+	if len(warehouses) == 0 {
+		warehouses = defaultWarehouses
+	}
+
+	routing := &models.WarehouseRouting{}
+	remaining := slices.Clone(req.Items)
+	for len(remaining) > 0 {
+		best, bestScore := -1, 0.0
+		var bestItems []string
+		for i, warehouse := range warehouses {
+			stocked := stockedItems(warehouse, remaining)
+			if len(stocked) == 0 {
+				continue
+			}
+			score := float64(len(stocked))*warehouseItemScore - warehouseDistance(warehouse, req.Region)
+			if best < 0 || score > bestScore {
+				best, bestScore, bestItems = i, score, stocked
+			}
+		}
+		if best < 0 {
+			routing.Unavailable = remaining
+			break
+		}
+		routing.Assignments = append(routing.Assignments, models.WarehouseAssignment{WarehouseID: warehouses[best].ID, Items: bestItems})
+		remaining = slices.DeleteFunc(remaining, func(item string) bool { return slices.Contains(bestItems, item) })
+	}
+
+	if activity.IsActivity(ctx) {
+		logger := activity.GetLogger(ctx)
+		logger.Info("Warehouses selected", "order_id", req.OrderID, "region", req.Region, "shipments", len(routing.Assignments), "unavailable", routing.Unavailable)
+	}
+	return routing, nil
+}
+
+// stockedItems returns the items, of those given, the warehouse stocks
+func stockedItems(warehouse models.Warehouse, items []string) []string {
+	if len(warehouse.Stock) == 0 {
+		return slices.Clone(items)
+	}
+	var stocked []string
+	for _, item := range items {
+		if slices.Contains(warehouse.Stock, item) {
+			stocked = append(stocked, item)
+		}
+	}
+	return stocked
+}
+
+// warehouseDistance returns how far the warehouse ships to region
+func warehouseDistance(warehouse models.Warehouse, region string) float64 {
+	if distance, ok := warehouse.Distances[region]; ok {
+		return distance
+	}
+	return DefaultWarehouseDistance
+}
@@ -0,0 +1,39 @@
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/activity"
+)
+
+// Activities contains the activity that writes order archives
+type Activities struct {
+	store Store
+}
+
+// NewActivities creates archive activities writing to store
+func NewActivities(store Store) *Activities {
+	return &Activities{store: store}
+}
+
+// ArchiveOrder writes a completed order's archive to cold storage under
+// models.OrderArchiveKey and returns the key. Archiving an order again
+// replaces its archive, so a retried write or a PII purge leaves one copy.
+func (a *Activities) ArchiveOrder(ctx context.Context, record models.OrderArchive) (string, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal order archive: %w", err)
+	}
+	key := models.OrderArchiveKey(record.Order.ID, record.ClosedAt)
+	if err := a.store.Put(ctx, key, data); err != nil {
+		return "", err
+	}
+
+	if activity.IsActivity(ctx) {
+		activity.GetLogger(ctx).Info("Order archived", "order_id", record.Order.ID, "key", key, "pii_purged", record.PIIPurged)
+	}
+	return key, nil
+}
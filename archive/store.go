@@ -0,0 +1,115 @@
+// Package archive keeps completed orders in cold storage for the data
+// retention policy, one JSON document per order, in a local directory or an
+// S3 bucket whose storage class can send them straight to Glacier.
+package archive
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// DefaultStorageClass is the S3 storage class archives are written with:
+// Glacier Instant Retrieval, which keeps them cheap but readable at once
+const DefaultStorageClass = string(types.StorageClassGlacierIr)
+
+// ValidStorageClass reports whether class is an S3 storage class
+func ValidStorageClass(class string) bool {
+	return slices.Contains(types.StorageClass("").Values(), types.StorageClass(class))
+}
+
+// Store is cold storage that order archives are written to
+type Store interface {
+	// Put writes data under key, replacing whatever was there
+	Put(ctx context.Context, key string, data []byte) error
+}
+
+// DirStore keeps archives as files under a directory. It stands in for cold
+// storage in development.
+type DirStore struct {
+	Dir string
+}
+
+// Put writes data to the file at key under the directory. The file is
+// written beside its final name and renamed, so a reader never sees part of
+// it.
+func (s DirStore) Put(ctx context.Context, key string, data []byte) error {
+	path := filepath.Join(s.Dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	return nil
+}
+
+// S3API is the subset of the S3 client S3Store uses
+type S3API interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+}
+
+// S3Store keeps archives as objects in an S3 bucket, under a key prefix
+type S3Store struct {
+	client       S3API
+	bucket       string
+	prefix       string
+	storageClass types.StorageClass
+}
+
+// NewS3Store creates a store writing to bucket with client. Keys are
+// prefixed with prefix, and objects written with storageClass, such as
+// GLACIER or DEEP_ARCHIVE; empty uses DefaultStorageClass.
+func NewS3Store(client S3API, bucket, prefix, storageClass string) *S3Store {
+	if storageClass == "" {
+		storageClass = DefaultStorageClass
+	}
+	return &S3Store{client: client, bucket: bucket, prefix: prefix, storageClass: types.StorageClass(storageClass)}
+}
+
+// NewS3StoreFromEnv creates a store with credentials from the default AWS
+// chain (environment, shared config, instance role). An empty region uses
+// AWS_REGION.
+func NewS3StoreFromEnv(ctx context.Context, bucket, prefix, region, storageClass string) (*S3Store, error) {
+	if bucket == "" {
+		return nil, errors.New("S3 bucket is required")
+	}
+	var opts []func(*awsconfig.LoadOptions) error
+	if region != "" {
+		opts = append(opts, awsconfig.WithRegion(region))
+	}
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	return NewS3Store(s3.NewFromConfig(cfg), bucket, prefix, storageClass), nil
+}
+
+// Put writes data as the object prefix+key, encrypted at rest by S3
+func (s *S3Store) Put(ctx context.Context, key string, data []byte) error {
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:               aws.String(s.bucket),
+		Key:                  aws.String(s.prefix + key),
+		Body:                 bytes.NewReader(data),
+		ContentType:          aws.String("application/json"),
+		StorageClass:         s.storageClass,
+		ServerSideEncryption: types.ServerSideEncryptionAes256,
+	})
+	if err != nil {
+		return fmt.Errorf("S3 PutObject: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package authz
+
+import (
+	"strings"
+	"time"
+
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/workflow"
+)
+
+// workflowTokenTTL bounds the tokens a workflow signs for the protected
+// signals it sends. The receiver checks them against its own workflow time,
+// which falls behind while its workers are down, so it is generous.
+const workflowTokenTTL = 24 * time.Hour
+
+// Config selects which workflow calls require a valid auth token
+type Config struct {
+	Signer *Signer
+	// ProtectedSignals are the signals dropped unless the sender is authorized
+	ProtectedSignals []string
+	// SignalSenders maps protected signals only one workflow may send, such
+	// as a child's update to its parent, to that workflow's type; the signal
+	// is dropped unless its token was signed for that type
+	SignalSenders map[string]string
+	// ProtectQueries rejects unauthorized queries, except the SDK's built-in
+	// "__" queries used by the Temporal UI
+	ProtectQueries bool
+}
+
+// Interceptor is a worker interceptor that authorizes signals, updates, and
+// queries using the token in the Temporal header.
+//
+// Unauthorized signals are dropped and logged rather than failed: a signal
+// handler error would fail the workflow task and stall the order. Updates
+// are rejected in their validator, so the caller sees the error. Because
+// verification is repeated on replay, keep retired secrets configured until
+// no running workflow has history signed with them.
+//
+// Protected signals a workflow sends, such as a child's updates to its
+// parent order, are signed on the way out with the sending workflow type as
+// the subject, so the worker receiving them accepts them.
+type Interceptor struct {
+	interceptor.WorkerInterceptorBase
+	config    Config
+	protected map[string]bool
+}
+
+// NewInterceptor creates an authorization interceptor
+func NewInterceptor(config Config) *Interceptor {
+	protected := make(map[string]bool, len(config.ProtectedSignals))
+	for _, signal := range config.ProtectedSignals {
+		protected[signal] = true
+	}
+	return &Interceptor{config: config, protected: protected}
+}
+
+// InterceptWorkflow wraps each workflow's inbound calls with authorization
+func (i *Interceptor) InterceptWorkflow(ctx workflow.Context, next interceptor.WorkflowInboundInterceptor) interceptor.WorkflowInboundInterceptor {
+	w := &workflowInboundInterceptor{parent: i}
+	w.Next = next
+	return w
+}
+
+type workflowInboundInterceptor struct {
+	interceptor.WorkflowInboundInterceptorBase
+	parent *Interceptor
+}
+
+func (w *workflowInboundInterceptor) Init(outbound interceptor.WorkflowOutboundInterceptor) error {
+	o := &workflowOutboundInterceptor{parent: w.parent}
+	o.Next = outbound
+	return w.Next.Init(o)
+}
+
+func (w *workflowInboundInterceptor) HandleSignal(ctx workflow.Context, in *interceptor.HandleSignalInput) error {
+	if w.parent.protected[in.SignalName] {
+		claims, err := w.authorize(ctx)
+		if err != nil {
+			workflow.GetLogger(ctx).Warn("Rejected unauthorized signal", "signal", in.SignalName, "error", err)
+			return nil
+		}
+		if sender, ok := w.parent.config.SignalSenders[in.SignalName]; ok && claims.Subject != sender {
+			workflow.GetLogger(ctx).Warn("Rejected signal from unexpected sender", "signal", in.SignalName, "subject", claims.Subject, "sender", sender)
+			return nil
+		}
+		workflow.GetLogger(ctx).Info("Authorized signal", "signal", in.SignalName, "subject", claims.Subject)
+	}
+	return w.Next.HandleSignal(ctx, in)
+}
+
+func (w *workflowInboundInterceptor) ValidateUpdate(ctx workflow.Context, in *interceptor.UpdateInput) error {
+	if _, err := w.authorize(ctx); err != nil {
+		return err
+	}
+	return w.Next.ValidateUpdate(ctx, in)
+}
+
+func (w *workflowInboundInterceptor) HandleQuery(ctx workflow.Context, in *interceptor.HandleQueryInput) (interface{}, error) {
+	if w.parent.config.ProtectQueries && !strings.HasPrefix(in.QueryType, "__") {
+		if _, err := w.authorize(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return w.Next.HandleQuery(ctx, in)
+}
+
+// authorize verifies the token in the header of the call being handled.
+// Expiry is checked against workflow time so replays reach the same verdict.
+func (w *workflowInboundInterceptor) authorize(ctx workflow.Context) (Claims, error) {
+	payload, ok := interceptor.WorkflowHeader(ctx)[HeaderKey]
+	if !ok {
+		return Claims{}, ErrMissingToken
+	}
+	var token string
+	if err := converter.GetDefaultDataConverter().FromPayload(payload, &token); err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+	return w.parent.config.Signer.Verify(token, workflow.Now(ctx))
+}
+
+type workflowOutboundInterceptor struct {
+	interceptor.WorkflowOutboundInterceptorBase
+	parent *Interceptor
+}
+
+func (o *workflowOutboundInterceptor) SignalExternalWorkflow(ctx workflow.Context, workflowID, runID, signalName string, arg interface{}) workflow.Future {
+	if o.parent.protected[signalName] {
+		if err := o.sign(ctx); err != nil {
+			future, settable := workflow.NewFuture(ctx)
+			settable.SetError(err)
+			return future
+		}
+	}
+	return o.Next.SignalExternalWorkflow(ctx, workflowID, runID, signalName, arg)
+}
+
+// sign writes a token naming the current workflow type into the header of
+// the outgoing call. Expiry is set from workflow time so replays sign the
+// same claims.
+func (o *workflowOutboundInterceptor) sign(ctx workflow.Context) error {
+	token, err := o.parent.config.Signer.Sign(Claims{
+		Subject:   workflow.GetInfo(ctx).WorkflowType.Name,
+		ExpiresAt: workflow.Now(ctx).Add(workflowTokenTTL),
+	})
+	if err != nil {
+		return err
+	}
+	payload, err := converter.GetDefaultDataConverter().ToPayload(token)
+	if err != nil {
+		return err
+	}
+	interceptor.WorkflowHeader(ctx)[HeaderKey] = payload
+	return nil
+}
@@ -0,0 +1,83 @@
+package authz
+
+import (
+	"context"
+	"time"
+
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/workflow"
+)
+
+// HeaderKey is the Temporal header carrying the auth token
+const HeaderKey = "authorization"
+
+type tokenKey struct{}
+
+type signerKey struct{}
+
+// tokenSigner signs a token for each call made with a context from WithSigner
+type tokenSigner struct {
+	signer  *Signer
+	subject string
+	ttl     time.Duration
+}
+
+// WithToken returns a copy of ctx whose signals, updates, and queries carry the token
+func WithToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, tokenKey{}, token)
+}
+
+// WithSigner returns a copy of ctx whose signals, updates, and queries carry
+// a token for subject signed when each call is made and valid for ttl, so a
+// context kept for a long session never sends an expired token. A token set
+// with WithToken takes precedence.
+func WithSigner(ctx context.Context, signer *Signer, subject string, ttl time.Duration) context.Context {
+	return context.WithValue(ctx, signerKey{}, tokenSigner{signer: signer, subject: subject, ttl: ttl})
+}
+
+// TokenPropagator writes the token from a client context into the Temporal
+// header of outgoing calls. Only clients need it; the worker reads the header
+// directly in its interceptor, so tokens never reach activity contexts.
+type TokenPropagator struct{}
+
+// NewTokenPropagator creates a client-side auth token propagator
+func NewTokenPropagator() workflow.ContextPropagator {
+	return &TokenPropagator{}
+}
+
+// Inject writes the token from a client context into the headers, signing
+// one first when the context has a signer
+func (p *TokenPropagator) Inject(ctx context.Context, writer workflow.HeaderWriter) error {
+	token, _ := ctx.Value(tokenKey{}).(string)
+	if s, ok := ctx.Value(signerKey{}).(tokenSigner); ok && token == "" {
+		var err error
+		token, err = s.signer.Sign(Claims{Subject: s.subject, ExpiresAt: time.Now().Add(s.ttl)})
+		if err != nil {
+			return err
+		}
+	}
+	if token == "" {
+		return nil
+	}
+	payload, err := converter.GetDefaultDataConverter().ToPayload(token)
+	if err != nil {
+		return err
+	}
+	writer.Set(HeaderKey, payload)
+	return nil
+}
+
+// InjectFromWorkflow does nothing: workflows never forward the caller's token
+func (p *TokenPropagator) InjectFromWorkflow(ctx workflow.Context, writer workflow.HeaderWriter) error {
+	return nil
+}
+
+// Extract does nothing: see TokenPropagator
+func (p *TokenPropagator) Extract(ctx context.Context, reader workflow.HeaderReader) (context.Context, error) {
+	return ctx, nil
+}
+
+// ExtractToWorkflow does nothing: see TokenPropagator
+func (p *TokenPropagator) ExtractToWorkflow(ctx workflow.Context, reader workflow.HeaderReader) (workflow.Context, error) {
+	return ctx, nil
+}
@@ -0,0 +1,63 @@
+// Package buildinfo identifies the build of the running binary, from values
+// injected at link time or, failing that, the build settings Go records.
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Set at link time, for example:
+//
+//	go build -ldflags "-X github.com/aswathylr-builds/temporal-order-processing/buildinfo.Version=1.4.0"
+//
+// `make build` sets all three from git.
+var (
+	Version   string
+	GitSHA    string
+	BuildTime string
+)
+
+// Info describes a build
+type Info struct {
+	Version string `json:"version"`
+	GitSHA  string `json:"git_sha,omitempty"`
+	// BuildTime is when the binary was built, or when its commit was made
+	// if not injected
+	BuildTime string `json:"build_time,omitempty"`
+	GoVersion string `json:"go_version"`
+	// Modified reports uncommitted changes in the build's source tree
+	Modified bool `json:"modified,omitempty"`
+}
+
+// Get returns the build of the running binary. Values not injected at link
+// time come from the module version and VCS settings Go records when
+// building from a git checkout; Version is "dev" when neither is known.
+func Get() Info {
+	info := Info{Version: Version, GitSHA: GitSHA, BuildTime: BuildTime, GoVersion: runtime.Version()}
+
+	if build, ok := debug.ReadBuildInfo(); ok {
+		if info.Version == "" && build.Main.Version != "" && build.Main.Version != "(devel)" {
+			info.Version = build.Main.Version
+		}
+		for _, setting := range build.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.GitSHA == "" {
+					info.GitSHA = setting.Value
+				}
+			case "vcs.time":
+				if info.BuildTime == "" {
+					info.BuildTime = setting.Value
+				}
+			case "vcs.modified":
+				info.Modified = setting.Value == "true"
+			}
+		}
+	}
+
+	if info.Version == "" {
+		info.Version = "dev"
+	}
+	return info
+}
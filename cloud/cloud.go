@@ -0,0 +1,54 @@
+// Package cloud configures the Temporal client to connect to Temporal Cloud
+// with an API key instead of a self-hosted HostPort.
+package cloud
+
+import (
+	"crypto/tls"
+	"fmt"
+
+	"go.temporal.io/sdk/client"
+)
+
+// RegionalEndpointFormat is the Temporal Cloud API key endpoint for a region,
+// such as us-east-1.aws
+const RegionalEndpointFormat = "%s.api.temporal.io:7233"
+
+// Config holds the Temporal Cloud connection settings. Namespace applies to
+// self-hosted clusters too; APIKey and Region are Cloud specific.
+type Config struct {
+	Namespace string
+	APIKey    string
+	Region    string
+}
+
+// HostPort returns the regional endpoint when a region is set, otherwise fallback
+func (c Config) HostPort(fallback string) string {
+	if c.Region == "" {
+		return fallback
+	}
+	return fmt.Sprintf(RegionalEndpointFormat, c.Region)
+}
+
+// Validate reports settings that cannot work together
+func (c Config) Validate() error {
+	if c.APIKey != "" && c.Namespace == "" {
+		return fmt.Errorf("TEMPORAL_NAMESPACE is required with an API key; Cloud namespaces look like <name>.<account-id>")
+	}
+	return nil
+}
+
+// Apply sets the namespace and API key credentials on the client options.
+// API keys are only accepted over TLS, so TLS is enabled with the system
+// roots unless it is already configured.
+func (c Config) Apply(options *client.Options) {
+	if c.Namespace != "" {
+		options.Namespace = c.Namespace
+	}
+	if c.APIKey == "" {
+		return
+	}
+	options.Credentials = client.NewAPIKeyStaticCredentials(c.APIKey)
+	if options.ConnectionOptions.TLS == nil {
+		options.ConnectionOptions.TLS = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+}
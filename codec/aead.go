@@ -0,0 +1,117 @@
+package codec
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/fips140"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"sync"
+)
+
+const (
+	// maxCachedAEADs bounds the AEADs a codec keeps, one per key it has used
+	maxCachedAEADs = maxUnwrappedKeys + 16
+
+	// maxPooledBuffer is the largest buffer returned to bufferPool, so one
+	// oversized payload does not pin its memory
+	maxPooledBuffer = 1 << 20
+)
+
+// aeadCache holds the AES-GCM AEAD of each key a codec has used, so the
+// cipher's key schedule is computed once per key rather than per payload.
+// The zero value is ready to use.
+type aeadCache struct {
+	mu    sync.Mutex
+	aeads map[string]cipher.AEAD
+}
+
+// get returns the AEAD for key, creating it on first use
+func (c *aeadCache) get(key []byte) (cipher.AEAD, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if aead, ok := c.aeads[string(key)]; ok {
+		return aead, nil
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if c.aeads == nil {
+		c.aeads = make(map[string]cipher.AEAD)
+	} else if len(c.aeads) >= maxCachedAEADs {
+		clear(c.aeads)
+	}
+	c.aeads[string(key)] = aead
+	return aead, nil
+}
+
+// newAEAD creates the AES-GCM AEAD for key. In FIPS 140-3 mode the module
+// generates the nonces, as approved GCM requires; it prepends them to the
+// ciphertext like encrypt does, so the payload format is the same.
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	newGCM := cipher.NewGCM
+	if fips140.Enabled() {
+		newGCM = cipher.NewGCMWithRandomNonce
+	}
+	gcm, err := newGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+	return gcm, nil
+}
+
+// bufferPool recycles the scratch buffers holding plaintext while it is
+// encrypted or unmarshaled
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		buf := make([]byte, 0, 4096)
+		return &buf
+	},
+}
+
+func getBuffer() *[]byte {
+	return bufferPool.Get().(*[]byte)
+}
+
+func putBuffer(buf *[]byte) {
+	if cap(*buf) > maxPooledBuffer {
+		return
+	}
+	*buf = (*buf)[:0]
+	bufferPool.Put(buf)
+}
+
+// encrypt encrypts plaintext with aead into a new slice holding the nonce
+// followed by the ciphertext. An AEAD that generates its own nonces has a
+// NonceSize of zero and adds the nonce itself.
+func encrypt(aead cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonceSize := aead.NonceSize()
+	out := make([]byte, nonceSize, nonceSize+len(plaintext)+aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, out); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return aead.Seal(out, out, plaintext, nil), nil
+}
+
+// decrypt decrypts the output of encrypt, appending the plaintext to dst
+func decrypt(aead cipher.AEAD, dst, ciphertext []byte) ([]byte, error) {
+	nonceSize := aead.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+
+	nonce, ciphertext := ciphertext[:nonceSize], ciphertext[nonceSize:]
+	plaintext, err := aead.Open(dst, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
+	}
+	return plaintext, nil
+}
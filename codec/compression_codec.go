@@ -0,0 +1,165 @@
+package codec
+
+import (
+	"fmt"
+
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
+	commonpb "go.temporal.io/api/common/v1"
+)
+
+// Compression is a payload compression algorithm
+type Compression string
+
+const (
+	CompressionNone   Compression = "none"
+	CompressionZstd   Compression = "zstd"
+	CompressionSnappy Compression = "snappy"
+)
+
+const (
+	// MetadataEncodingZstd is the encoding type for zstd-compressed payloads
+	MetadataEncodingZstd = "binary/zstd"
+	// MetadataEncodingSnappy is the encoding type for snappy-compressed payloads
+	MetadataEncodingSnappy = "binary/snappy"
+
+	// DefaultCompressionMinBytes is the payload size below which compression
+	// rarely pays for its metadata and CPU
+	DefaultCompressionMinBytes = 256
+
+	// maxDecompressedBytes bounds decompression so a corrupt or hostile
+	// payload cannot exhaust memory; Temporal payloads are far smaller
+	maxDecompressedBytes = 64 << 20
+)
+
+// ParseCompression parses a Compression name; "" is CompressionNone
+func ParseCompression(name string) (Compression, error) {
+	switch c := Compression(name); c {
+	case "", CompressionNone:
+		return CompressionNone, nil
+	case CompressionZstd, CompressionSnappy:
+		return c, nil
+	default:
+		return "", fmt.Errorf("unknown compression %q, want %s, %s, or %s", name, CompressionNone, CompressionZstd, CompressionSnappy)
+	}
+}
+
+// CompressionCodec implements converter.PayloadCodec by compressing payloads
+// of at least MinBytes. A payload is left as is when compression does not
+// shrink it. Decode handles both algorithms whichever one the codec writes,
+// so the algorithm can be changed without breaking existing histories.
+type CompressionCodec struct {
+	algorithm Compression
+	// MinBytes is the smallest payload that is compressed
+	MinBytes int
+
+	encoder *zstd.Encoder
+	decoder *zstd.Decoder
+}
+
+// NewCompressionCodec creates a codec compressing with algorithm; with
+// CompressionNone it only decompresses
+func NewCompressionCodec(algorithm Compression) (*CompressionCodec, error) {
+	algorithm, err := ParseCompression(string(algorithm))
+	if err != nil {
+		return nil, err
+	}
+	encoder, err := zstd.NewWriter(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create zstd encoder: %w", err)
+	}
+	decoder, err := zstd.NewReader(nil, zstd.WithDecoderMaxMemory(maxDecompressedBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create zstd decoder: %w", err)
+	}
+	return &CompressionCodec{
+		algorithm: algorithm,
+		MinBytes:  DefaultCompressionMinBytes,
+		encoder:   encoder,
+		decoder:   decoder,
+	}, nil
+}
+
+// Encode compresses the provided payloads
+func (c *CompressionCodec) Encode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	if c.algorithm == CompressionNone {
+		return payloads, nil
+	}
+	result := make([]*commonpb.Payload, len(payloads))
+
+	for i, payload := range payloads {
+		// Marshal the entire payload (including metadata) to bytes
+		origBytes, err := payload.Marshal()
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal payload: %w", err)
+		}
+		if len(origBytes) < c.MinBytes {
+			result[i] = payload
+			continue
+		}
+
+		var compressed []byte
+		var encoding string
+		switch c.algorithm {
+		case CompressionZstd:
+			compressed, encoding = c.encoder.EncodeAll(origBytes, nil), MetadataEncodingZstd
+		case CompressionSnappy:
+			compressed, encoding = s2.EncodeSnappy(nil, origBytes), MetadataEncodingSnappy
+		}
+		if len(compressed) >= len(origBytes) {
+			result[i] = payload
+			continue
+		}
+
+		result[i] = &commonpb.Payload{
+			Metadata: map[string][]byte{
+				"encoding": []byte(encoding),
+			},
+			Data: compressed,
+		}
+	}
+
+	return result, nil
+}
+
+// Decode decompresses the provided payloads
+func (c *CompressionCodec) Decode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	result := make([]*commonpb.Payload, len(payloads))
+
+	for i, payload := range payloads {
+		var decompressed []byte
+		var err error
+		switch string(payload.GetMetadata()["encoding"]) {
+		case MetadataEncodingZstd:
+			decompressed, err = c.decoder.DecodeAll(payload.Data, nil)
+		case MetadataEncodingSnappy:
+			decompressed, err = decodeSnappy(payload.Data)
+		default:
+			result[i] = payload
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress payload: %w", err)
+		}
+
+		// Unmarshal the decompressed bytes back to a Payload
+		result[i] = &commonpb.Payload{}
+		if err := result[i].Unmarshal(decompressed); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal decompressed payload: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// decodeSnappy decodes a snappy block, checking its declared length first
+func decodeSnappy(data []byte) ([]byte, error) {
+	size, err := s2.DecodedLen(data)
+	if err != nil {
+		return nil, err
+	}
+	if size > maxDecompressedBytes {
+		return nil, fmt.Errorf("decompressed size %d exceeds %d bytes", size, maxDecompressedBytes)
+	}
+	return s2.Decode(nil, data)
+}
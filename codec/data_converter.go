@@ -0,0 +1,80 @@
+package codec
+
+import (
+	"go.temporal.io/sdk/converter"
+)
+
+// DataConverterBuilder assembles a data converter from the payload codecs
+// this service uses, in the order they must run: on encode, fields are
+// encrypted while payloads are still JSON, and payloads are compressed before
+// they are encrypted, since ciphertext does not compress
+type DataConverterBuilder struct {
+	payloads    converter.DataConverter
+	compression Compression
+	encryption  converter.PayloadCodec
+	fields      converter.PayloadCodec
+}
+
+// NewDataConverterBuilder creates a builder for a converter with no codecs
+// over the SDK's default payload converters
+func NewDataConverterBuilder() *DataConverterBuilder {
+	return &DataConverterBuilder{
+		payloads:    converter.GetDefaultDataConverter(),
+		compression: CompressionNone,
+	}
+}
+
+// WithPayloadConverter converts values to payloads with payloads, before the
+// codecs run, such as orderspb.NewDataConverter
+func (b *DataConverterBuilder) WithPayloadConverter(payloads converter.DataConverter) *DataConverterBuilder {
+	b.payloads = payloads
+	return b
+}
+
+// WithCompression compresses payloads with algorithm
+func (b *DataConverterBuilder) WithCompression(algorithm Compression) *DataConverterBuilder {
+	b.compression = algorithm
+	return b
+}
+
+// WithEncryption encrypts payloads with codec, such as an EncryptionCodec
+func (b *DataConverterBuilder) WithEncryption(codec converter.PayloadCodec) *DataConverterBuilder {
+	b.encryption = codec
+	return b
+}
+
+// WithFieldEncryption encrypts fields of JSON payloads with codec, such as a
+// FieldEncryptionCodec
+func (b *DataConverterBuilder) WithFieldEncryption(codec converter.PayloadCodec) *DataConverterBuilder {
+	b.fields = codec
+	return b
+}
+
+// Codecs returns the codecs in converter.NewCodecDataConverter order, which
+// applies them last to first on encode
+func (b *DataConverterBuilder) Codecs() ([]converter.PayloadCodec, error) {
+	var codecs []converter.PayloadCodec
+	if b.encryption != nil {
+		codecs = append(codecs, b.encryption)
+	}
+	// Compression is always decoded, so payloads written while it was
+	// enabled stay readable after it is turned off
+	compression, err := NewCompressionCodec(b.compression)
+	if err != nil {
+		return nil, err
+	}
+	codecs = append(codecs, compression)
+	if b.fields != nil {
+		codecs = append(codecs, b.fields)
+	}
+	return codecs, nil
+}
+
+// Build returns the data converter
+func (b *DataConverterBuilder) Build() (converter.DataConverter, error) {
+	codecs, err := b.Codecs()
+	if err != nil {
+		return nil, err
+	}
+	return converter.NewCodecDataConverter(b.payloads, codecs...), nil
+}
@@ -1,40 +1,183 @@
 package codec
 
 import (
-	"crypto/aes"
+	"context"
 	"crypto/cipher"
-	"crypto/rand"
 	"fmt"
-	"io"
+	"sync"
+	"time"
 
 	commonpb "go.temporal.io/api/common/v1"
 	"go.temporal.io/sdk/converter"
+	"google.golang.org/protobuf/proto"
 )
 
 const (
 	// MetadataEncodingEncrypted is the encoding type for encrypted payloads
 	MetadataEncodingEncrypted = "binary/encrypted"
+
+	// MetadataEncryptionKeyWrapped holds the KMS-wrapped data key of a payload
+	// encrypted in envelope mode
+	MetadataEncryptionKeyWrapped = "encryption-key-wrapped"
+
+	// DefaultDataKeyTTL is how long an envelope codec reuses a generated data
+	// key before asking the DataKeyProvider for a new one
+	DefaultDataKeyTTL = 5 * time.Minute
+
+	// maxUnwrappedKeys bounds the cache of data keys unwrapped on Decode
+	maxUnwrappedKeys = 256
 )
 
 // EncryptionCodec implements converter.PayloadCodec for encrypting/decrypting workflow data
 type EncryptionCodec struct {
-	key []byte
+	keys Keyring
+
+	// Envelope mode; dataKeys is nil for a codec with a single key
+	dataKeys   DataKeyProvider
+	dataKeyTTL time.Duration
+
+	mu        sync.Mutex
+	current   *dataKey
+	unwrapped map[string][]byte
+
+	aeads aeadCache
+}
+
+// dataKey is a generated data key and the time it was generated
+type dataKey struct {
+	plaintext []byte
+	wrapped   []byte
+	created   time.Time
 }
 
 // NewEncryptionCodec creates a new encryption codec with the provided key
 // The key should be 32 bytes for AES-256
 func NewEncryptionCodec(key []byte) (*EncryptionCodec, error) {
-	if len(key) != 32 {
-		return nil, fmt.Errorf("key must be 32 bytes for AES-256, got %d bytes", len(key))
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+
+	codec := &EncryptionCodec{
+		keys: Keyring{Current: StaticKey(key)},
+	}
+	if _, err := codec.aeads.get(key); err != nil {
+		return nil, err
+	}
+	return codec, nil
+}
+
+// NewEncryptionCodecWithProvider creates an encryption codec that asks keys
+// for the key on every Encode and Decode call, so providers can cache and
+// refresh it; keys should be cheap to call, as vault.KeyProvider is
+func NewEncryptionCodecWithProvider(keys KeyProvider) *EncryptionCodec {
+	return NewEncryptionCodecWithKeyring(Keyring{Current: keys})
+}
+
+// NewEncryptionCodecWithKeyring creates an encryption codec that encrypts
+// with keys.Current and records its key ID, and decrypts with whichever key
+// of the keyring a payload names
+func NewEncryptionCodecWithKeyring(keys Keyring) *EncryptionCodec {
+	return &EncryptionCodec{
+		keys: keys,
 	}
+}
 
+// EnvelopeConfig configures an envelope encryption codec
+type EnvelopeConfig struct {
+	// DataKeys generates and unwraps data keys, such as awskms.DataKeys
+	DataKeys DataKeyProvider
+	// DataKeyTTL is how long a data key is reused for Encode; zero uses
+	// DefaultDataKeyTTL
+	DataKeyTTL time.Duration
+	// LegacyKeys, when set, decrypts payloads written before envelope
+	// encryption was enabled
+	LegacyKeys Keyring
+}
+
+// NewEnvelopeEncryptionCodec creates a codec that encrypts each payload with
+// a data key from config.DataKeys and stores the wrapped data key in the
+// payload metadata. Only the wrapped key is persisted; plaintext data keys
+// are kept in memory to limit calls to the key service.
+func NewEnvelopeEncryptionCodec(config EnvelopeConfig) *EncryptionCodec {
+	if config.DataKeyTTL == 0 {
+		config.DataKeyTTL = DefaultDataKeyTTL
+	}
 	return &EncryptionCodec{
-		key: key,
-	}, nil
+		keys:       config.LegacyKeys,
+		dataKeys:   config.DataKeys,
+		dataKeyTTL: config.DataKeyTTL,
+		unwrapped:  make(map[string][]byte),
+	}
+}
+
+// encodeKey returns the key to encrypt with and, in envelope mode, its
+// wrapped form for the payload metadata
+func (e *EncryptionCodec) encodeKey() (key, wrapped []byte, err error) {
+	if e.dataKeys == nil {
+		key, err := fetchKey(e.keys.Current)
+		return key, nil, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.current != nil && time.Since(e.current.created) < e.dataKeyTTL {
+		return e.current.plaintext, e.current.wrapped, nil
+	}
+	plaintext, wrapped, err := e.dataKeys.GenerateDataKey(context.Background())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+	if err := checkKey(plaintext); err != nil {
+		return nil, nil, err
+	}
+	e.current = &dataKey{plaintext: plaintext, wrapped: wrapped, created: time.Now()}
+	e.unwrapped[string(wrapped)] = plaintext
+	return plaintext, wrapped, nil
+}
+
+// unwrap returns the plaintext of a wrapped data key, from the cache when
+// this process has seen it before
+func (e *EncryptionCodec) unwrap(wrapped []byte) ([]byte, error) {
+	if e.dataKeys == nil {
+		return nil, fmt.Errorf("payload has a wrapped data key but envelope encryption is not configured")
+	}
+
+	e.mu.Lock()
+	key, ok := e.unwrapped[string(wrapped)]
+	e.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+
+	key, err := e.dataKeys.DecryptDataKey(context.Background(), wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+
+	e.mu.Lock()
+	if len(e.unwrapped) >= maxUnwrappedKeys {
+		clear(e.unwrapped)
+	}
+	e.unwrapped[string(wrapped)] = key
+	e.mu.Unlock()
+	return key, nil
 }
 
 // Encode encrypts the provided payloads
 func (e *EncryptionCodec) Encode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	key, wrapped, err := e.encodeKey()
+	if err != nil {
+		return nil, err
+	}
+	aead, err := e.aeads.get(key)
+	if err != nil {
+		return nil, err
+	}
+	buf := getBuffer()
+	defer putBuffer(buf)
 	result := make([]*commonpb.Payload, len(payloads))
 
 	for i, payload := range payloads {
@@ -45,13 +188,14 @@ func (e *EncryptionCodec) Encode(payloads []*commonpb.Payload) ([]*commonpb.Payl
 		}
 
 		// Marshal the entire payload (including metadata) to bytes
-		origBytes, err := payload.Marshal()
+		origBytes, err := proto.MarshalOptions{}.MarshalAppend((*buf)[:0], payload)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal payload: %w", err)
 		}
+		*buf = origBytes
 
 		// Encrypt the marshaled payload
-		encrypted, err := e.encrypt(origBytes)
+		encrypted, err := encrypt(aead, origBytes)
 		if err != nil {
 			return nil, fmt.Errorf("failed to encrypt payload: %w", err)
 		}
@@ -63,6 +207,11 @@ func (e *EncryptionCodec) Encode(payloads []*commonpb.Payload) ([]*commonpb.Payl
 			},
 			Data: encrypted,
 		}
+		if wrapped != nil {
+			result[i].Metadata[MetadataEncryptionKeyWrapped] = wrapped
+		} else {
+			result[i].Metadata[MetadataEncryptionKeyID] = []byte(KeyID(key))
+		}
 	}
 
 	return result, nil
@@ -70,6 +219,9 @@ func (e *EncryptionCodec) Encode(payloads []*commonpb.Payload) ([]*commonpb.Payl
 
 // Decode decrypts the provided payloads
 func (e *EncryptionCodec) Decode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	keys := &decryptionKeys{ring: e.keys}
+	buf := getBuffer()
+	defer putBuffer(buf)
 	result := make([]*commonpb.Payload, len(payloads))
 
 	for i, payload := range payloads {
@@ -79,13 +231,41 @@ func (e *EncryptionCodec) Decode(payloads []*commonpb.Payload) ([]*commonpb.Payl
 			continue
 		}
 
-		// Decrypt the data
-		decrypted, err := e.decrypt(payload.Data)
+		var candidates [][]byte
+		if wrapped := payload.Metadata[MetadataEncryptionKeyWrapped]; len(wrapped) > 0 {
+			key, err := e.unwrap(wrapped)
+			if err != nil {
+				return nil, err
+			}
+			candidates = [][]byte{key}
+		} else {
+			var err error
+			candidates, err = keys.forPayload(string(payload.Metadata[MetadataEncryptionKeyID]))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// Decrypt the data; payloads without a key ID try every key, and
+		// GCM authentication rejects the wrong ones
+		var decrypted []byte
+		var err error
+		for _, key := range candidates {
+			var aead cipher.AEAD
+			if aead, err = e.aeads.get(key); err != nil {
+				return nil, err
+			}
+			if decrypted, err = decrypt(aead, (*buf)[:0], payload.Data); err == nil {
+				break
+			}
+		}
 		if err != nil {
 			return nil, fmt.Errorf("failed to decrypt payload: %w", err)
 		}
+		*buf = decrypted
 
-		// Unmarshal the decrypted bytes back to a Payload
+		// Unmarshal the decrypted bytes back to a Payload; Unmarshal copies
+		// them, so the buffer can be reused
 		result[i] = &commonpb.Payload{}
 		if err := result[i].Unmarshal(decrypted); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal decrypted payload: %w", err)
@@ -95,62 +275,32 @@ func (e *EncryptionCodec) Decode(payloads []*commonpb.Payload) ([]*commonpb.Payl
 	return result, nil
 }
 
-// encrypt encrypts data using AES-GCM
-func (e *EncryptionCodec) encrypt(plaintext []byte) ([]byte, error) {
-	block, err := aes.NewCipher(e.key)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create cipher: %w", err)
-	}
-
-	gcm, err := cipher.NewGCM(block)
+// NewEncryptionDataConverter creates a data converter with encryption codec;
+// it also decompresses payloads, and compression is enabled with
+// NewDataConverterBuilder
+func NewEncryptionDataConverter(key []byte) (converter.DataConverter, error) {
+	codec, err := NewEncryptionCodec(key)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create GCM: %w", err)
-	}
-
-	nonce := make([]byte, gcm.NonceSize())
-	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
-		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+		return nil, err
 	}
 
-	ciphertext := gcm.Seal(nonce, nonce, plaintext, nil)
-	return ciphertext, nil
+	return NewDataConverterBuilder().WithEncryption(codec).Build()
 }
 
-// decrypt decrypts data using AES-GCM
-func (e *EncryptionCodec) decrypt(ciphertext []byte) ([]byte, error) {
-	block, err := aes.NewCipher(e.key)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create cipher: %w", err)
-	}
-
-	gcm, err := cipher.NewGCM(block)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create GCM: %w", err)
-	}
-
-	nonceSize := gcm.NonceSize()
-	if len(ciphertext) < nonceSize {
-		return nil, fmt.Errorf("ciphertext too short")
-	}
-
-	nonce, ciphertext := ciphertext[:nonceSize], ciphertext[nonceSize:]
-	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to decrypt: %w", err)
-	}
-
-	return plaintext, nil
+// NewEncryptionDataConverterWithProvider creates a data converter with an
+// encryption codec whose key comes from keys
+func NewEncryptionDataConverterWithProvider(keys KeyProvider) (converter.DataConverter, error) {
+	return NewEncryptionDataConverterWithKeyring(Keyring{Current: keys})
 }
 
-// NewEncryptionDataConverter creates a data converter with encryption codec
-func NewEncryptionDataConverter(key []byte) (converter.DataConverter, error) {
-	codec, err := NewEncryptionCodec(key)
-	if err != nil {
-		return nil, err
-	}
+// NewEncryptionDataConverterWithKeyring creates a data converter with an
+// encryption codec using keys
+func NewEncryptionDataConverterWithKeyring(keys Keyring) (converter.DataConverter, error) {
+	return NewDataConverterBuilder().WithEncryption(NewEncryptionCodecWithKeyring(keys)).Build()
+}
 
-	return converter.NewCodecDataConverter(
-		converter.GetDefaultDataConverter(),
-		codec,
-	), nil
+// NewEnvelopeEncryptionDataConverter creates a data converter with an
+// envelope encryption codec
+func NewEnvelopeEncryptionDataConverter(config EnvelopeConfig) (converter.DataConverter, error) {
+	return NewDataConverterBuilder().WithEncryption(NewEnvelopeEncryptionCodec(config)).Build()
 }
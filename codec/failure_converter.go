@@ -0,0 +1,18 @@
+package codec
+
+import (
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/temporal"
+)
+
+// NewEncryptedFailureConverter creates a failure converter that moves failure
+// messages and stack traces into an encoded payload converted with dc, so an
+// encrypting dc keeps them from reaching the server in plaintext. The server
+// and UI show "Encoded failure" until the payload is decoded, such as by the
+// codec server.
+func NewEncryptedFailureConverter(dc converter.DataConverter) converter.FailureConverter {
+	return temporal.NewDefaultFailureConverter(temporal.DefaultFailureConverterOptions{
+		DataConverter:          dc,
+		EncodeCommonAttributes: true,
+	})
+}
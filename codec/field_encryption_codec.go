@@ -0,0 +1,228 @@
+package codec
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+)
+
+// MetadataEncryptedFields lists, comma-separated, the JSON field names whose
+// values FieldEncryptionCodec encrypted in a payload
+const MetadataEncryptedFields = "encrypted-fields"
+
+// DefaultEncryptedFields are the JSON field names of customer PII and payment
+// secrets encrypted by FieldEncryptionCodec
+var DefaultEncryptedFields = []string{
+	"email",
+	"customer_email",
+	"address",
+	"shipping_address",
+	"billing_address",
+	"payment_token",
+	"card_number",
+	"account_number",
+}
+
+// FieldEncryptionCodec implements converter.PayloadCodec by encrypting only
+// the values of designated fields, at any depth, of JSON payloads. The rest
+// of a payload, such as the order ID and status, stays readable in the UI
+// without the codec server. Each encrypted value is replaced by the base64
+// AES-GCM ciphertext of its JSON, and payloads record the key ID like
+// EncryptionCodec does.
+type FieldEncryptionCodec struct {
+	keys   Keyring
+	fields map[string]bool
+	aeads  aeadCache
+}
+
+// NewFieldEncryptionCodec creates a codec encrypting fields, matched
+// case-insensitively, with keys.Current
+func NewFieldEncryptionCodec(keys Keyring, fields []string) *FieldEncryptionCodec {
+	c := &FieldEncryptionCodec{keys: keys, fields: make(map[string]bool, len(fields))}
+	for _, field := range fields {
+		if field = strings.TrimSpace(field); field != "" {
+			c.fields[strings.ToLower(field)] = true
+		}
+	}
+	return c
+}
+
+// Encode encrypts the designated fields of the provided payloads
+func (c *FieldEncryptionCodec) Encode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	key, err := fetchKey(c.keys.Current)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := c.aeads.get(key)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*commonpb.Payload, len(payloads))
+
+	for i, payload := range payloads {
+		// Only JSON payloads have fields; skip those already encrypted
+		if string(payload.Metadata[converter.MetadataEncoding]) != converter.MetadataEncodingJSON ||
+			payload.Metadata[MetadataEncryptedFields] != nil {
+			result[i] = payload
+			continue
+		}
+
+		value, err := decodeJSON(payload.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse JSON payload: %w", err)
+		}
+		encrypted := make(map[string]bool)
+		value, err = c.transform(value, encrypted, func(field interface{}) (interface{}, error) {
+			plaintext, err := json.Marshal(field)
+			if err != nil {
+				return nil, err
+			}
+			ciphertext, err := encrypt(aead, plaintext)
+			if err != nil {
+				return nil, err
+			}
+			return base64.StdEncoding.EncodeToString(ciphertext), nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt field: %w", err)
+		}
+		if len(encrypted) == 0 {
+			result[i] = payload
+			continue
+		}
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal payload: %w", err)
+		}
+		names := make([]string, 0, len(encrypted))
+		for name := range encrypted {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		result[i] = &commonpb.Payload{Metadata: copyMetadata(payload.Metadata), Data: data}
+		result[i].Metadata[MetadataEncryptedFields] = []byte(strings.Join(names, ","))
+		result[i].Metadata[MetadataEncryptionKeyID] = []byte(KeyID(key))
+	}
+
+	return result, nil
+}
+
+// Decode decrypts the fields each payload lists as encrypted
+func (c *FieldEncryptionCodec) Decode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	keys := &decryptionKeys{ring: c.keys}
+	result := make([]*commonpb.Payload, len(payloads))
+
+	for i, payload := range payloads {
+		listed := payload.Metadata[MetadataEncryptedFields]
+		if listed == nil {
+			result[i] = payload
+			continue
+		}
+
+		candidates, err := keys.forPayload(string(payload.Metadata[MetadataEncryptionKeyID]))
+		if err != nil {
+			return nil, err
+		}
+		value, err := decodeJSON(payload.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse JSON payload: %w", err)
+		}
+		// Decrypt the fields the payload lists, not the configured ones,
+		// so the list can change without breaking existing histories
+		decoder := NewFieldEncryptionCodec(c.keys, strings.Split(string(listed), ","))
+		value, err = decoder.transform(value, map[string]bool{}, func(field interface{}) (interface{}, error) {
+			encoded, ok := field.(string)
+			if !ok {
+				return nil, fmt.Errorf("encrypted field is a %T, not a string", field)
+			}
+			ciphertext, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				return nil, err
+			}
+			var plaintext []byte
+			for _, key := range candidates {
+				var aead cipher.AEAD
+				if aead, err = c.aeads.get(key); err != nil {
+					return nil, err
+				}
+				if plaintext, err = decrypt(aead, nil, ciphertext); err == nil {
+					break
+				}
+			}
+			if err != nil {
+				return nil, err
+			}
+			return decodeJSON(plaintext)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt field: %w", err)
+		}
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal payload: %w", err)
+		}
+		result[i] = &commonpb.Payload{Metadata: copyMetadata(payload.Metadata), Data: data}
+		delete(result[i].Metadata, MetadataEncryptedFields)
+		delete(result[i].Metadata, MetadataEncryptionKeyID)
+	}
+
+	return result, nil
+}
+
+// transform replaces the value of every designated field with apply's
+// result, recording the names it matched in matched. Values of designated
+// fields are not searched further.
+func (c *FieldEncryptionCodec) transform(v interface{}, matched map[string]bool, apply func(interface{}) (interface{}, error)) (interface{}, error) {
+	var err error
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for key, field := range value {
+			name := strings.ToLower(key)
+			if c.fields[name] {
+				value[key], err = apply(field)
+				matched[name] = true
+			} else {
+				value[key], err = c.transform(field, matched, apply)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	case []interface{}:
+		for i, item := range value {
+			if value[i], err = c.transform(item, matched, apply); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return v, nil
+}
+
+// decodeJSON parses data keeping numbers exact, so re-encoding a payload does
+// not change them
+func decodeJSON(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func copyMetadata(metadata map[string][]byte) map[string][]byte {
+	copied := make(map[string][]byte, len(metadata)+2)
+	for k, v := range metadata {
+		copied[k] = v
+	}
+	return copied
+}
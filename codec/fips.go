@@ -0,0 +1,40 @@
+package codec
+
+import (
+	"bytes"
+	"crypto/fips140"
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// CheckFIPS verifies the codecs run on FIPS 140-3 approved primitives: the
+// Go Cryptographic Module must be enabled, and AES-GCM with module-generated
+// nonces must round trip. The module runs its own self-tests when it loads.
+func CheckFIPS() error {
+	if !fips140.Enabled() {
+		return errors.New("the Go Cryptographic Module is not enabled; build with GOFIPS140=v1.0.0 or run with GODEBUG=fips140=on")
+	}
+
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return fmt.Errorf("failed to generate self-test key: %w", err)
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+	plaintext := []byte("fips self-test")
+	ciphertext, err := encrypt(aead, plaintext)
+	if err != nil {
+		return err
+	}
+	decrypted, err := decrypt(aead, nil, ciphertext)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		return errors.New("AES-GCM self-test failed")
+	}
+	return nil
+}
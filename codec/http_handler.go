@@ -0,0 +1,102 @@
+package codec
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"slices"
+	"strings"
+
+	"go.temporal.io/sdk/converter"
+)
+
+const (
+	// NamespaceHeader carries the namespace of the payloads the Temporal Web
+	// UI and CLI send to a remote codec
+	NamespaceHeader = "X-Namespace"
+
+	// maxCodecRequestBytes bounds request bodies; Temporal payloads are far smaller
+	maxCodecRequestBytes = 16 << 20
+)
+
+// HTTPHandlerOptions configures NewHTTPHandler
+type HTTPHandlerOptions struct {
+	// CORSOrigins are the browser origins allowed to call the handler, such
+	// as the Web UI's; "*" allows any
+	CORSOrigins []string
+	// AuthTokens, when set, are the bearer tokens accepted in the
+	// Authorization header
+	AuthTokens []string
+}
+
+// HTTPHandler implements the Temporal remote codec endpoint: POST .../encode
+// and .../decode with a JSON Payloads body, run through the codecs of the
+// namespace in the X-Namespace header, so each namespace decodes only with
+// its own keys
+type HTTPHandler struct {
+	namespaces map[string]http.Handler
+	options    HTTPHandlerOptions
+}
+
+// NewHTTPHandler creates a remote codec handler. codecs maps each namespace
+// to its codecs in converter.NewCodecDataConverter order, such as
+// DataConverterBuilder.Codecs returns.
+func NewHTTPHandler(codecs map[string][]converter.PayloadCodec, options HTTPHandlerOptions) *HTTPHandler {
+	namespaces := make(map[string]http.Handler, len(codecs))
+	for namespace, namespaceCodecs := range codecs {
+		namespaces[namespace] = converter.NewPayloadCodecHTTPHandler(namespaceCodecs...)
+	}
+	return &HTTPHandler{namespaces: namespaces, options: options}
+}
+
+// ServeHTTP implements http.Handler
+func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if origin := r.Header.Get("Origin"); origin != "" && h.allowOrigin(origin) {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+		w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, "+NamespaceHeader)
+		w.Header().Add("Vary", "Origin")
+	}
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST, OPTIONS")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.authorized(r) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	namespace := r.Header.Get(NamespaceHeader)
+	handler, ok := h.namespaces[namespace]
+	if !ok {
+		http.Error(w, "unknown namespace "+namespace, http.StatusForbidden)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxCodecRequestBytes)
+	handler.ServeHTTP(w, r)
+}
+
+func (h *HTTPHandler) allowOrigin(origin string) bool {
+	return slices.Contains(h.options.CORSOrigins, "*") || slices.Contains(h.options.CORSOrigins, origin)
+}
+
+func (h *HTTPHandler) authorized(r *http.Request) bool {
+	if len(h.options.AuthTokens) == 0 {
+		return true
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+	for _, accepted := range h.options.AuthTokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(accepted)) == 1 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package codec
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// KeySize is the length of an AES-256 key
+const KeySize = 32
+
+// KeyProvider supplies the AES-256 key used to encrypt payloads
+type KeyProvider interface {
+	Key(ctx context.Context) ([]byte, error)
+}
+
+// DataKeyProvider issues data keys for envelope encryption from a key
+// management service that never reveals its master key
+type DataKeyProvider interface {
+	// GenerateDataKey returns a new AES-256 key and the same key wrapped
+	// (encrypted) by the master key
+	GenerateDataKey(ctx context.Context) (plaintext, wrapped []byte, err error)
+	// DecryptDataKey unwraps a key returned by GenerateDataKey
+	DecryptDataKey(ctx context.Context, wrapped []byte) ([]byte, error)
+}
+
+// StaticKey is a KeyProvider for a fixed key
+type StaticKey []byte
+
+// Key implements KeyProvider
+func (k StaticKey) Key(ctx context.Context) ([]byte, error) {
+	return k, nil
+}
+
+// FileKeyProvider reads the key from a local file, generating and saving a
+// random key on first use. It is meant for local development only: every
+// worker and starter must share the file, and it is not protected at rest.
+// The file is read on every call, so wrap the result in StaticKey.
+type FileKeyProvider struct {
+	Path string
+}
+
+// Key implements KeyProvider
+func (p FileKeyProvider) Key(ctx context.Context) ([]byte, error) {
+	key, err := os.ReadFile(p.Path)
+	if err == nil {
+		if err := checkKey(key); err != nil {
+			return nil, fmt.Errorf("invalid key file %s: %w", p.Path, err)
+		}
+		return key, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+
+	key = make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	// O_EXCL so a concurrently started process cannot overwrite the key
+	// another one has already used
+	file, err := os.OpenFile(p.Path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if errors.Is(err, os.ErrExist) {
+		return p.Key(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save key file: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(key); err != nil {
+		return nil, fmt.Errorf("failed to save key file: %w", err)
+	}
+	return key, nil
+}
+
+// checkKey reports whether key has the AES-256 key length
+func checkKey(key []byte) error {
+	if len(key) != KeySize {
+		return fmt.Errorf("key must be 32 bytes for AES-256, got %d bytes", len(key))
+	}
+	return nil
+}
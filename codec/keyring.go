@@ -0,0 +1,106 @@
+package codec
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// MetadataEncryptionKeyID names the key a payload was encrypted with, so
+// Decode can pick it from a Keyring after the key has been rotated
+const MetadataEncryptionKeyID = "encryption-key-id"
+
+// Keyring holds the Current key, which encrypts, and Retired keys, which only
+// decrypt payloads written before a rotation. Keep a retired key until no
+// open workflow history or retained payload was encrypted with it.
+type Keyring struct {
+	Current KeyProvider
+	Retired []KeyProvider
+}
+
+// KeyID returns the ID recorded in the metadata of payloads encrypted with
+// key: a fingerprint, so keys need no names and the ID reveals nothing of
+// the key
+func KeyID(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:8])
+}
+
+// decryptionKeys resolves keys for one Decode call. The current key is
+// fetched at most once, and retired keys only when a payload needs one.
+type decryptionKeys struct {
+	ring    Keyring
+	current []byte
+	retired [][]byte
+}
+
+// forPayload returns the candidate keys for a payload with the key ID id,
+// which is empty for payloads written before key IDs were recorded
+func (d *decryptionKeys) forPayload(id string) ([][]byte, error) {
+	current, err := d.currentKey()
+	if err != nil {
+		return nil, err
+	}
+	if id != "" && KeyID(current) == id {
+		return [][]byte{current}, nil
+	}
+
+	retired, err := d.retiredKeys()
+	if err != nil {
+		return nil, err
+	}
+	if id == "" {
+		return append([][]byte{current}, retired...), nil
+	}
+	for _, key := range retired {
+		if KeyID(key) == id {
+			return [][]byte{key}, nil
+		}
+	}
+	return nil, fmt.Errorf("payload was encrypted with key %s, which is not in the keyring", id)
+}
+
+func (d *decryptionKeys) currentKey() ([]byte, error) {
+	if d.current != nil {
+		return d.current, nil
+	}
+	if d.ring.Current == nil {
+		return nil, errors.New("payload has no wrapped data key and no legacy key is configured")
+	}
+	key, err := fetchKey(d.ring.Current)
+	if err != nil {
+		return nil, err
+	}
+	d.current = key
+	return key, nil
+}
+
+func (d *decryptionKeys) retiredKeys() ([][]byte, error) {
+	if d.retired != nil || len(d.ring.Retired) == 0 {
+		return d.retired, nil
+	}
+	keys := make([][]byte, 0, len(d.ring.Retired))
+	for _, provider := range d.ring.Retired {
+		key, err := fetchKey(provider)
+		if err != nil {
+			return nil, fmt.Errorf("retired key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	d.retired = keys
+	return keys, nil
+}
+
+// fetchKey reads a key from keys and checks its length
+func fetchKey(keys KeyProvider) ([]byte, error) {
+	key, err := keys.Key(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get encryption key: %w", err)
+	}
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
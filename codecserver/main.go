@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/codec"
+	"github.com/aswathylr-builds/temporal-order-processing/config"
+	"github.com/aswathylr-builds/temporal-order-processing/logging"
+)
+
+// Serves the Temporal remote codec endpoint so the Web UI and CLI can show
+// decoded payloads. Point the UI at it with TEMPORAL_CODEC_ENDPOINT.
+func main() {
+	// Settings come from the CONFIG_FILE YAML file, overlaid with environment variables
+	cfg, err := config.LoadFromEnv()
+	if err != nil {
+		fatal("Invalid configuration", "error", err)
+	}
+
+	logger := logging.New(os.Stderr, cfg.LoggingConfig())
+	slog.SetDefault(logger)
+
+	// Fetch every namespace's keys now so a misconfigured key store fails at startup
+	keyCtx, keyCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	codecs, err := cfg.CodecServerCodecs(keyCtx)
+	keyCancel()
+	if err != nil {
+		fatal("Failed to set up payload codecs", "error", err)
+	}
+	if len(cfg.CodecServer.AuthTokens) == 0 {
+		slog.Warn("Codec server accepts unauthenticated requests; set CODEC_SERVER_AUTH_TOKENS outside local development")
+	}
+
+	handler := codec.NewHTTPHandler(codecs, codec.HTTPHandlerOptions{
+		CORSOrigins: cfg.CodecServer.CORSOrigins,
+		AuthTokens:  cfg.CodecServer.AuthTokens,
+	})
+	server := &http.Server{
+		Addr:         fmt.Sprintf(":%d", cfg.CodecServer.Port),
+		Handler:      handler,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 30 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+	namespaces := make([]string, 0, len(codecs))
+	for namespace := range codecs {
+		namespaces = append(namespaces, namespace)
+	}
+	slog.Info("Codec server started", "port", cfg.CodecServer.Port, "namespaces", namespaces, "fips", cfg.Encryption.FIPS)
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	select {
+	case <-sigCh:
+		slog.Info("Shutting down codec server")
+	case err := <-errCh:
+		if !errors.Is(err, http.ErrServerClosed) {
+			fatal("Codec server failed", "error", err)
+		}
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Error("Codec server shutdown failed", "error", err)
+	}
+}
+
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
@@ -0,0 +1,1121 @@
+// Package config loads worker, starter, and health server settings from an
+// optional YAML file overlaid with environment variables.
+//
+// Every setting has a `yaml` key and most have an `env` variable; an
+// environment variable that is set wins over the file, and the file wins over
+// the defaults from Default.
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/archive"
+	"github.com/aswathylr-builds/temporal-order-processing/awskms"
+	"github.com/aswathylr-builds/temporal-order-processing/cloud"
+	"github.com/aswathylr-builds/temporal-order-processing/codec"
+	"github.com/aswathylr-builds/temporal-order-processing/featureflags"
+	"github.com/aswathylr-builds/temporal-order-processing/health"
+	"github.com/aswathylr-builds/temporal-order-processing/interceptors"
+	"github.com/aswathylr-builds/temporal-order-processing/logging"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/proto/orderspb"
+	"github.com/aswathylr-builds/temporal-order-processing/store"
+	"github.com/aswathylr-builds/temporal-order-processing/temporalauth"
+	"github.com/aswathylr-builds/temporal-order-processing/tlsconfig"
+	"github.com/aswathylr-builds/temporal-order-processing/tuning"
+	"github.com/aswathylr-builds/temporal-order-processing/vault"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/temporal"
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv names the environment variable holding the config file path
+const FileEnv = "CONFIG_FILE"
+
+// ProfileEnv names the environment variable holding the default profile of
+// the starter's -profile flag
+const ProfileEnv = "CONFIG_PROFILE"
+
+// DefaultTemporalHost is used when neither a host nor a Cloud region is set
+const DefaultTemporalHost = "localhost:7233"
+
+// Config holds all settings of the worker and starter
+type Config struct {
+	Temporal       Temporal       `yaml:"temporal"`
+	Encryption     Encryption     `yaml:"encryption"`
+	Logging        Logging        `yaml:"logging"`
+	Auth           Auth           `yaml:"auth"`
+	Payload        Payload        `yaml:"payload"`
+	Validation     Validation     `yaml:"validation"`
+	Kafka          Kafka          `yaml:"kafka"`
+	SQS            SQS            `yaml:"sqs"`
+	Database       Database       `yaml:"database"`
+	Ops            Ops            `yaml:"ops"`
+	Notifications  Notifications  `yaml:"notifications"`
+	Invoices       Invoices       `yaml:"invoices"`
+	Simulation     Simulation     `yaml:"simulation"`
+	Health         Health         `yaml:"health"`
+	Metrics        Metrics        `yaml:"metrics"`
+	Worker         Worker         `yaml:"worker"`
+	Dynamic        Dynamic        `yaml:"dynamic"`
+	FeatureFlags   FeatureFlags   `yaml:"feature_flags"`
+	Fraud          Fraud          `yaml:"fraud"`
+	Screening      Screening      `yaml:"screening"`
+	Loyalty        Loyalty        `yaml:"loyalty"`
+	Customers      Customers      `yaml:"customers"`
+	Carrier        Carrier        `yaml:"carrier"`
+	Warehouses     []Warehouse    `yaml:"warehouses"`
+	Vendor         Vendor         `yaml:"vendor"`
+	Reconciliation Reconciliation `yaml:"reconciliation"`
+	Currency       Currency       `yaml:"currency"`
+	Archive        Archive        `yaml:"archive"`
+	CodecServer    CodecServer    `yaml:"codec_server"`
+	Webhook        Webhook        `yaml:"webhook"`
+
+	// Profiles are named connections; see WithProfile
+	Profiles map[string]Profile `yaml:"profiles"`
+}
+
+// Temporal is the connection to the Temporal server or Temporal Cloud.
+// An empty HostPort resolves to the Cloud regional endpoint when CloudRegion
+// is set, otherwise DefaultTemporalHost.
+type Temporal struct {
+	HostPort    string `yaml:"host_port" env:"TEMPORAL_HOST"`
+	Namespace   string `yaml:"namespace" env:"TEMPORAL_NAMESPACE"`
+	APIKey      string `yaml:"api_key" env:"TEMPORAL_API_KEY"`
+	CloudRegion string `yaml:"cloud_region" env:"TEMPORAL_CLOUD_REGION"`
+	TLS         TLS    `yaml:"tls"`
+	// Auth sends JWT bearer tokens to a frontend with an authorizer plugin
+	Auth TemporalAuth `yaml:"auth"`
+}
+
+// TemporalAuth selects one source of JWT bearer tokens for the Temporal
+// client: a static Token, a TokenFile re-read before the token expires, or
+// the OAuth2 client-credentials flow
+type TemporalAuth struct {
+	Token              string        `yaml:"token" env:"TEMPORAL_AUTH_TOKEN"`
+	TokenFile          string        `yaml:"token_file" env:"TEMPORAL_AUTH_TOKEN_FILE"`
+	OAuth2TokenURL     string        `yaml:"oauth_token_url" env:"TEMPORAL_OAUTH_TOKEN_URL"`
+	OAuth2ClientID     string        `yaml:"oauth_client_id" env:"TEMPORAL_OAUTH_CLIENT_ID"`
+	OAuth2ClientSecret string        `yaml:"oauth_client_secret" env:"TEMPORAL_OAUTH_CLIENT_SECRET"`
+	OAuth2Scopes       []string      `yaml:"oauth_scopes" env:"TEMPORAL_OAUTH_SCOPES"`
+	OAuth2Audience     string        `yaml:"oauth_audience" env:"TEMPORAL_OAUTH_AUDIENCE"`
+	RefreshBefore      time.Duration `yaml:"refresh_before" env:"TEMPORAL_AUTH_REFRESH_BEFORE"`
+}
+
+// TLS locates the certificates for the Temporal connection
+type TLS struct {
+	CAFile     string `yaml:"ca_file" env:"TEMPORAL_TLS_CA_FILE"`
+	CertFile   string `yaml:"cert_file" env:"TEMPORAL_TLS_CERT_FILE"`
+	KeyFile    string `yaml:"key_file" env:"TEMPORAL_TLS_KEY_FILE"`
+	ServerName string `yaml:"server_name" env:"TEMPORAL_TLS_SERVER_NAME"`
+}
+
+// Encryption enables the payload encryption codec. With KMS.KeyID set,
+// payloads use envelope encryption with AWS KMS data keys. Otherwise the key
+// is read from Vault when Vault.Address is set, or, for local development
+// only, from KeyFile, which is generated on first use. With Mode set to
+// EncryptionModeHMAC, payloads are signed with the key rather than encrypted,
+// and with EncryptionModeFields only the JSON Fields of payloads are
+// encrypted.
+type Encryption struct {
+	Enabled bool   `yaml:"enabled" env:"ENCRYPTION_ENABLED"`
+	KeyFile string `yaml:"key_file" env:"ENCRYPTION_KEY_FILE"`
+	Vault   Vault  `yaml:"vault"`
+	KMS     KMS    `yaml:"kms"`
+	// RetiredKeyFiles hold keys rotated out of KeyFile; they only decrypt
+	RetiredKeyFiles []string `yaml:"retired_key_files" env:"ENCRYPTION_RETIRED_KEY_FILES"`
+	Mode            string   `yaml:"mode" env:"ENCRYPTION_MODE"`
+	// AllowUnsigned accepts unsigned payloads in EncryptionModeHMAC while
+	// histories written before signing was enabled are still open
+	AllowUnsigned bool `yaml:"allow_unsigned" env:"ENCRYPTION_ALLOW_UNSIGNED"`
+	// Fields are the JSON field names encrypted in EncryptionModeFields
+	Fields []string `yaml:"fields" env:"ENCRYPTION_FIELDS"`
+	// FIPS restricts the codecs to FIPS 140-3 approved primitives and key
+	// sources, and fails startup unless the Go Cryptographic Module is enabled
+	FIPS bool `yaml:"fips" env:"ENCRYPTION_FIPS"`
+}
+
+// Encryption modes: AES-GCM encrypts payloads, HMAC only signs them for
+// tamper detection, and fields encrypts only designated fields
+const (
+	EncryptionModeAESGCM = "aes-gcm"
+	EncryptionModeHMAC   = "hmac"
+	EncryptionModeFields = "fields"
+)
+
+// KMS selects the AWS KMS key that wraps payload data keys. Credentials come
+// from the default AWS chain. When Vault is also configured, its key still
+// decrypts payloads written before KMS was enabled.
+type KMS struct {
+	KeyID      string        `yaml:"key_id" env:"ENCRYPTION_KMS_KEY_ID"`
+	Region     string        `yaml:"region" env:"AWS_REGION"`
+	DataKeyTTL time.Duration `yaml:"data_key_ttl" env:"ENCRYPTION_DATA_KEY_TTL"`
+}
+
+// Vault locates the encryption key in Vault's KV v2 engine and the
+// credentials to read it: a token, or an AppRole role ID and secret ID
+type Vault struct {
+	Address      string        `yaml:"address" env:"VAULT_ADDR"`
+	Namespace    string        `yaml:"namespace" env:"VAULT_NAMESPACE"`
+	CAFile       string        `yaml:"ca_file" env:"VAULT_CACERT"`
+	Token        string        `yaml:"token" env:"VAULT_TOKEN"`
+	RoleID       string        `yaml:"role_id" env:"VAULT_ROLE_ID"`
+	SecretID     string        `yaml:"secret_id" env:"VAULT_SECRET_ID"`
+	AppRoleMount string        `yaml:"approle_mount" env:"VAULT_APPROLE_MOUNT"`
+	KVMount      string        `yaml:"kv_mount" env:"VAULT_KV_MOUNT"`
+	KeyPath      string        `yaml:"key_path" env:"VAULT_ENCRYPTION_KEY_PATH"`
+	KeyField     string        `yaml:"key_field" env:"VAULT_ENCRYPTION_KEY_FIELD"`
+	KeyCacheTTL  time.Duration `yaml:"key_cache_ttl" env:"VAULT_KEY_CACHE_TTL"`
+	// RetiredKeyFields are fields of the same secret holding keys rotated
+	// out of KeyField; they only decrypt
+	RetiredKeyFields []string `yaml:"retired_key_fields" env:"VAULT_RETIRED_KEY_FIELDS"`
+}
+
+// CodecServer configures the remote codec server the Temporal Web UI calls
+// to show decoded payloads. It serves temporal.namespace with the top-level
+// encryption settings, and each of Namespaces with its own settings layered
+// over them, so each namespace decodes only with its own keys.
+type CodecServer struct {
+	Port        int                  `yaml:"port" env:"CODEC_SERVER_PORT"`
+	CORSOrigins []string             `yaml:"cors_origins" env:"CODEC_SERVER_CORS_ORIGINS"`
+	AuthTokens  []string             `yaml:"auth_tokens" env:"CODEC_SERVER_AUTH_TOKENS"`
+	Namespaces  map[string]yaml.Node `yaml:"namespaces"`
+}
+
+// Webhook configures the receiver of storefront order-created webhooks
+type Webhook struct {
+	Port          int      `yaml:"port" env:"WEBHOOK_PORT"`
+	Secrets       []string `yaml:"secrets" env:"WEBHOOK_SECRETS"`
+	OrderIDPrefix string   `yaml:"order_id_prefix" env:"WEBHOOK_ORDER_ID_PREFIX"`
+}
+
+// Profile is a named connection, such as dev or prod, selected with the
+// starter's -profile flag. Its temporal and encryption settings are layered
+// over the top-level ones.
+type Profile struct {
+	Temporal   yaml.Node `yaml:"temporal"`
+	Encryption yaml.Node `yaml:"encryption"`
+}
+
+// Logging selects the log format and level. RedactFields are masked in
+// activity debug logs in addition to interceptors.DefaultRedactedFields.
+type Logging struct {
+	Format       string   `yaml:"format" env:"LOG_FORMAT"`
+	Level        string   `yaml:"level" env:"LOG_LEVEL"`
+	RedactFields []string `yaml:"redact_fields" env:"LOG_REDACT_FIELDS"`
+}
+
+// Auth configures signed tokens for signals and, optionally, queries
+type Auth struct {
+	SignalSecrets  []string `yaml:"signal_secrets" env:"SIGNAL_AUTH_SECRETS"`
+	ProtectQueries bool     `yaml:"protect_queries" env:"SIGNAL_AUTH_QUERIES"`
+}
+
+// Payload limits the size of payloads sent to Temporal, measured before
+// compression, and selects how they are compressed. Format selects how the
+// order models are encoded: PayloadFormatJSON, or PayloadFormatProtobuf with
+// the messages in proto/orderspb; both are always decoded.
+type Payload struct {
+	MaxBytes    int    `yaml:"max_bytes" env:"PAYLOAD_MAX_BYTES"`
+	Compression string `yaml:"compression" env:"PAYLOAD_COMPRESSION"`
+	Format      string `yaml:"format" env:"PAYLOAD_FORMAT"`
+}
+
+// Validation is the downstream order validation service
+type Validation struct {
+	URL            string               `yaml:"url" env:"VALIDATION_URL"`
+	HTTP           ValidationHTTP       `yaml:"http"`
+	Auth           ValidationAuth       `yaml:"auth"`
+	CircuitBreaker CircuitBreaker       `yaml:"circuit_breaker"`
+	RateLimit      ValidationRateLimit  `yaml:"rate_limit"`
+	LocalRules     ValidationLocalRules `yaml:"local_rules"`
+}
+
+// ValidationHTTP configures the validation service HTTP client
+type ValidationHTTP struct {
+	Timeout        time.Duration `yaml:"timeout" env:"VALIDATION_HTTP_TIMEOUT"`
+	MaxIdleConns   int           `yaml:"max_idle_conns" env:"VALIDATION_MAX_IDLE_CONNS"`
+	MaxRetries     int           `yaml:"max_retries" env:"VALIDATION_HTTP_RETRIES"`
+	CAFile         string        `yaml:"ca_file" env:"VALIDATION_CA_FILE"`
+	ClientCertFile string        `yaml:"client_cert_file" env:"VALIDATION_CLIENT_CERT_FILE"`
+	ClientKeyFile  string        `yaml:"client_key_file" env:"VALIDATION_CLIENT_KEY_FILE"`
+	TLSServerName  string        `yaml:"tls_server_name" env:"VALIDATION_TLS_SERVER_NAME"`
+	ProxyURL       string        `yaml:"proxy_url" env:"VALIDATION_PROXY_URL"`
+}
+
+// ValidationAuth holds the validation service credentials
+type ValidationAuth struct {
+	APIKey             string   `yaml:"api_key" env:"VALIDATION_API_KEY"`
+	APIKeyHeader       string   `yaml:"api_key_header" env:"VALIDATION_API_KEY_HEADER"`
+	OAuth2TokenURL     string   `yaml:"oauth_token_url" env:"VALIDATION_OAUTH_TOKEN_URL"`
+	OAuth2ClientID     string   `yaml:"oauth_client_id" env:"VALIDATION_OAUTH_CLIENT_ID"`
+	OAuth2ClientSecret string   `yaml:"oauth_client_secret" env:"VALIDATION_OAUTH_CLIENT_SECRET"`
+	OAuth2Scopes       []string `yaml:"oauth_scopes" env:"VALIDATION_OAUTH_SCOPES"`
+}
+
+// CircuitBreaker configures the validation circuit breaker
+type CircuitBreaker struct {
+	FailureThreshold int           `yaml:"failure_threshold" env:"CIRCUIT_BREAKER_FAILURE_THRESHOLD"`
+	OpenTimeout      time.Duration `yaml:"open_timeout" env:"CIRCUIT_BREAKER_OPEN_TIMEOUT"`
+}
+
+// ValidationRateLimit caps requests to the validation service; zero
+// RequestsPerSecond disables it, and zero Burst allows one second's worth
+type ValidationRateLimit struct {
+	RequestsPerSecond float64       `yaml:"requests_per_second" env:"VALIDATION_RATE_LIMIT"`
+	Burst             int           `yaml:"burst" env:"VALIDATION_RATE_BURST"`
+	MaxWait           time.Duration `yaml:"max_wait" env:"VALIDATION_RATE_MAX_WAIT"`
+}
+
+// ValidationLocalRules configures the fallback rules validator
+type ValidationLocalRules struct {
+	MaxAmount    float64  `yaml:"max_amount" env:"LOCAL_RULES_MAX_AMOUNT"`
+	MaxItems     int      `yaml:"max_items" env:"LOCAL_RULES_MAX_ITEMS"`
+	MaxQuantity  int      `yaml:"max_quantity" env:"LOCAL_RULES_MAX_QUANTITY"`
+	AllowedItems []string `yaml:"allowed_items" env:"LOCAL_RULES_ALLOWED_ITEMS"`
+}
+
+// Kafka publishes order lifecycle events; no brokers disables publishing.
+// The consumer reads order-create and order-cancel messages from
+// OrderIntakeTopic as the ConsumerGroup consumer group. Detached analytics
+// exports go to AnalyticsTopic.
+type Kafka struct {
+	Brokers          []string `yaml:"brokers" env:"KAFKA_BROKERS"`
+	OrderEventsTopic string   `yaml:"order_events_topic" env:"KAFKA_ORDER_EVENTS_TOPIC"`
+	OrderIntakeTopic string   `yaml:"order_intake_topic" env:"KAFKA_ORDER_INTAKE_TOPIC"`
+	ConsumerGroup    string   `yaml:"consumer_group" env:"KAFKA_CONSUMER_GROUP"`
+	AnalyticsTopic   string   `yaml:"analytics_topic" env:"KAFKA_ANALYTICS_TOPIC"`
+}
+
+// SQS is an alternative intake for the consumer: with QueueURL set it reads
+// order messages from that queue instead of Kafka. Credentials come from the
+// default AWS chain.
+type SQS struct {
+	QueueURL           string        `yaml:"queue_url" env:"SQS_QUEUE_URL"`
+	DeadLetterQueueURL string        `yaml:"dead_letter_queue_url" env:"SQS_DEAD_LETTER_QUEUE_URL"`
+	Region             string        `yaml:"region" env:"SQS_REGION"`
+	VisibilityTimeout  time.Duration `yaml:"visibility_timeout" env:"SQS_VISIBILITY_TIMEOUT"`
+	RetryBackoff       time.Duration `yaml:"retry_backoff" env:"SQS_RETRY_BACKOFF"`
+	MaxRetryBackoff    time.Duration `yaml:"max_retry_backoff" env:"SQS_MAX_RETRY_BACKOFF"`
+}
+
+// Database mirrors order state to Postgres; an empty URL disables it
+type Database struct {
+	URL          string `yaml:"url" env:"DATABASE_URL"`
+	MaxOpenConns int    `yaml:"max_open_conns" env:"DATABASE_MAX_OPEN_CONNS"`
+	MaxIdleConns int    `yaml:"max_idle_conns" env:"DATABASE_MAX_IDLE_CONNS"`
+}
+
+// Ops receives alerts for dead-lettered orders
+type Ops struct {
+	WebhookURL string `yaml:"webhook_url" env:"OPS_WEBHOOK_URL"`
+}
+
+// Notifications locates the service customer notifications are posted to;
+// an empty URL simulates it
+type Notifications struct {
+	URL string `yaml:"url" env:"NOTIFICATION_URL"`
+}
+
+// Invoices locates the invoice scratch directory and store
+type Invoices struct {
+	Dir      string `yaml:"dir" env:"INVOICE_DIR"`
+	StoreDir string `yaml:"store_dir" env:"INVOICE_STORE_DIR"`
+}
+
+// Simulation drives the demo's simulated inventory and payment gateway
+type Simulation struct {
+	OutOfStockItems    []string `yaml:"out_of_stock_items" env:"OUT_OF_STOCK_ITEMS"`
+	PaymentDeclineOver float64  `yaml:"payment_decline_over" env:"PAYMENT_DECLINE_OVER"`
+	GiftCardBalance    float64  `yaml:"gift_card_balance" env:"GIFT_CARD_BALANCE"`
+}
+
+// Health configures the health check server and the services it probes
+type Health struct {
+	Port        int    `yaml:"port" env:"HEALTH_PORT"`
+	WiremockURL string `yaml:"wiremock_url" env:"WIREMOCK_URL"`
+	// CacheTTL is how often dependencies are checked in the background;
+	// requests are served the latest results. Zero checks on every request.
+	CacheTTL time.Duration `yaml:"cache_ttl" env:"HEALTH_CACHE_TTL"`
+	// GRPCPort, if set, serves the grpc.health.v1 protocol for probes that
+	// only speak gRPC, such as a service mesh
+	GRPCPort int `yaml:"grpc_port" env:"HEALTH_GRPC_PORT"`
+	// FailureThreshold consecutive failures make a dependency unhealthy, and
+	// RecoveryThreshold consecutive successes make it healthy again
+	FailureThreshold  int       `yaml:"failure_threshold" env:"HEALTH_FAILURE_THRESHOLD"`
+	RecoveryThreshold int       `yaml:"recovery_threshold" env:"HEALTH_RECOVERY_THRESHOLD"`
+	TLS               HealthTLS `yaml:"tls"`
+	// AuthTokens are the bearer tokens accepted on /health, /health/worker,
+	// and /metrics; the probe endpoints stay open
+	AuthTokens []string   `yaml:"auth_tokens" env:"HEALTH_AUTH_TOKENS"`
+	Push       HealthPush `yaml:"push"`
+	// Temporal, Wiremock, Postgres, and Kafka report their dependency as
+	// degraded or unhealthy when it responds slower than the thresholds
+	Temporal LatencyThresholds `yaml:"temporal" envPrefix:"HEALTH_TEMPORAL_"`
+	Wiremock LatencyThresholds `yaml:"wiremock" envPrefix:"HEALTH_WIREMOCK_"`
+	Postgres LatencyThresholds `yaml:"postgres" envPrefix:"HEALTH_POSTGRES_"`
+	Kafka    LatencyThresholds `yaml:"kafka" envPrefix:"HEALTH_KAFKA_"`
+}
+
+// HealthTLS serves the health endpoints over TLS; a client certificate
+// verified against ClientCAFile authenticates like a bearer token
+type HealthTLS struct {
+	CertFile     string `yaml:"cert_file" env:"HEALTH_TLS_CERT_FILE"`
+	KeyFile      string `yaml:"key_file" env:"HEALTH_TLS_KEY_FILE"`
+	ClientCAFile string `yaml:"client_ca_file" env:"HEALTH_TLS_CLIENT_CA_FILE"`
+}
+
+// HealthPush pushes health results to a StatsD agent, such as the Datadog
+// agent or the CloudWatch agent's StatsD listener, for workers that cannot
+// be probed from outside
+type HealthPush struct {
+	// Address of the agent, such as localhost:8125; empty disables pushing
+	Address  string        `yaml:"address" env:"HEALTH_PUSH_ADDRESS"`
+	Interval time.Duration `yaml:"interval" env:"HEALTH_PUSH_INTERVAL"`
+	Prefix   string        `yaml:"prefix" env:"HEALTH_PUSH_PREFIX"`
+	// Format is dogstatsd, with tags, or statsd
+	Format string   `yaml:"format" env:"HEALTH_PUSH_FORMAT"`
+	Tags   []string `yaml:"tags" env:"HEALTH_PUSH_TAGS"`
+}
+
+// LatencyThresholds are the latencies at which a health check reports its
+// dependency as degraded (warn) or unhealthy (critical); zero disables one
+type LatencyThresholds struct {
+	Warn     time.Duration `yaml:"warn" env:"LATENCY_WARN"`
+	Critical time.Duration `yaml:"critical" env:"LATENCY_CRITICAL"`
+}
+
+func (l LatencyThresholds) validate(name string) error {
+	if l.Warn < 0 || l.Critical < 0 {
+		return fmt.Errorf("%s latency thresholds must not be negative", name)
+	}
+	if l.Warn > 0 && l.Critical > 0 && l.Critical < l.Warn {
+		return fmt.Errorf("%s.critical must not be less than warn", name)
+	}
+	return nil
+}
+
+// Metrics configures the Prometheus metrics server
+type Metrics struct {
+	Port int `yaml:"port" env:"METRICS_PORT"`
+}
+
+// Dynamic locates the file served by the GetConfig activity; see DynamicFile
+type Dynamic struct {
+	File string `yaml:"file" env:"DYNAMIC_CONFIG_FILE"`
+}
+
+// FeatureFlags locates the flag providers consulted after FEATURE_*
+// environment variables: a YAML file, then a remote JSON endpoint
+type FeatureFlags struct {
+	File            string        `yaml:"file" env:"FEATURE_FLAGS_FILE"`
+	URL             string        `yaml:"url" env:"FEATURE_FLAGS_URL"`
+	RefreshInterval time.Duration `yaml:"refresh_interval" env:"FEATURE_FLAGS_REFRESH_INTERVAL"`
+}
+
+// Fraud tunes the CheckFraud activity
+type Fraud struct {
+	MaxAmountPerItem float64 `yaml:"max_amount_per_item" env:"FRAUD_MAX_AMOUNT_PER_ITEM"`
+}
+
+// Screening configures the ScreenCustomer activity: the IDs of denylisted
+// customers and the sanctions screening API, if any
+type Screening struct {
+	URL      string   `yaml:"url" env:"SCREENING_URL"`
+	Denylist []string `yaml:"denylist" env:"SCREENING_DENYLIST"`
+}
+
+// Loyalty locates the loyalty service orders earn and redeem points with; an
+// empty URL simulates it
+type Loyalty struct {
+	URL             string  `yaml:"url" env:"LOYALTY_URL"`
+	PointsPerDollar float64 `yaml:"points_per_dollar" env:"LOYALTY_POINTS_PER_DOLLAR"`
+}
+
+// Customers locates the customer service risk assessment reads order history
+// from and sends step-up verification through; an empty URL treats every
+// customer as new
+type Customers struct {
+	URL string `yaml:"url" env:"CUSTOMER_URL"`
+}
+
+// Carrier locates the carrier API shipments are tracked with; an empty URL
+// simulates it
+type Carrier struct {
+	URL string `yaml:"url" env:"CARRIER_URL"`
+}
+
+// Vendor locates the marketplace API purchase orders are sent to dropship
+// vendors through; an empty URL simulates vendors that accept at once
+type Vendor struct {
+	URL string `yaml:"url" env:"VENDOR_URL"`
+}
+
+// Reconciliation configures the nightly payment reconciliation and
+// settlement: the payment gateway API transactions are read from and settled
+// through, the finance webhook reports are posted to, and the cron schedules
+// the order worker creates. An empty schedule creates none.
+type Reconciliation struct {
+	GatewayURL         string `yaml:"gateway_url" env:"PAYMENT_GATEWAY_URL"`
+	FinanceWebhookURL  string `yaml:"finance_webhook_url" env:"FINANCE_WEBHOOK_URL"`
+	Schedule           string `yaml:"schedule" env:"RECONCILIATION_SCHEDULE"`
+	SettlementSchedule string `yaml:"settlement_schedule" env:"SETTLEMENT_SCHEDULE"`
+}
+
+// Currency configures what orders in other currencies are converted into
+// before payment, and where exchange rates come from: the rate API at
+// RatesURL, or else the FixedRates keyed "FROM/TO". Without either, only
+// orders in the base currency are accepted. Rates are cached for CacheTTL.
+type Currency struct {
+	Base       string             `yaml:"base" env:"BASE_CURRENCY"`
+	RatesURL   string             `yaml:"rates_url" env:"EXCHANGE_RATES_URL"`
+	FixedRates map[string]float64 `yaml:"fixed_rates"`
+	CacheTTL   time.Duration      `yaml:"cache_ttl" env:"EXCHANGE_RATES_CACHE_TTL"`
+}
+
+// Archive is the cold storage completed orders are archived to: an S3
+// bucket when Bucket is set, otherwise files under Dir, which defaults to a
+// directory under the system temp directory
+type Archive struct {
+	Dir          string `yaml:"dir" env:"ARCHIVE_DIR"`
+	Bucket       string `yaml:"bucket" env:"ARCHIVE_S3_BUCKET"`
+	Prefix       string `yaml:"prefix" env:"ARCHIVE_S3_PREFIX"`
+	Region       string `yaml:"region" env:"ARCHIVE_S3_REGION"`
+	StorageClass string `yaml:"storage_class" env:"ARCHIVE_STORAGE_CLASS"`
+}
+
+// Warehouse is a location orders are fulfilled from when the warehouse
+// routing dynamic setting is on. Stock lists the items it carries, empty
+// carrying every item; Distances is how far, in km, it ships to each region.
+// Without warehouses, one warehouse carries every item.
+type Warehouse struct {
+	ID        string             `yaml:"id"`
+	Stock     []string           `yaml:"stock"`
+	Distances map[string]float64 `yaml:"distances"`
+}
+
+// Worker configures the worker processes. Payments tuning fields left at zero
+// inherit the Orders values.
+type Worker struct {
+	Role                  string         `yaml:"role" env:"WORKER_ROLE"`
+	StopTimeout           time.Duration  `yaml:"stop_timeout" env:"WORKER_STOP_TIMEOUT"`
+	StickyCacheSize       int            `yaml:"sticky_cache_size" env:"WORKER_STICKY_CACHE_SIZE"`
+	MaxConcurrentSessions int            `yaml:"max_concurrent_sessions" env:"WORKER_MAX_CONCURRENT_SESSIONS"`
+	Orders                WorkerTuning   `yaml:"orders" envPrefix:"WORKER_"`
+	Payments              WorkerTuning   `yaml:"payments" envPrefix:"PAYMENT_WORKER_"`
+	ResourceTuning        ResourceTuning `yaml:"resource_tuning"`
+}
+
+// WorkerTuning sets a worker's concurrency and rate limits; zero keeps the
+// SDK default
+type WorkerTuning struct {
+	MaxConcurrentActivities      int     `yaml:"max_concurrent_activities" env:"MAX_CONCURRENT_ACTIVITIES"`
+	MaxConcurrentWorkflowTasks   int     `yaml:"max_concurrent_workflow_tasks" env:"MAX_CONCURRENT_WORKFLOW_TASKS"`
+	MaxConcurrentLocalActivities int     `yaml:"max_concurrent_local_activities" env:"MAX_CONCURRENT_LOCAL_ACTIVITIES"`
+	ActivitiesPerSecond          float64 `yaml:"activities_per_second" env:"ACTIVITIES_PER_SECOND"`
+	TaskQueueActivitiesPerSecond float64 `yaml:"task_queue_activities_per_second" env:"TASK_QUEUE_ACTIVITIES_PER_SECOND"`
+}
+
+// ResourceTuning sizes activity concurrency by memory and CPU headroom
+type ResourceTuning struct {
+	Enabled           bool    `yaml:"enabled" env:"WORKER_RESOURCE_TUNING"`
+	MinActivitySlots  int     `yaml:"min_activity_slots" env:"WORKER_RESOURCE_MIN_ACTIVITY_SLOTS"`
+	MaxActivitySlots  int     `yaml:"max_activity_slots" env:"WORKER_RESOURCE_MAX_ACTIVITY_SLOTS"`
+	TargetMemoryUsage float64 `yaml:"target_memory_usage" env:"WORKER_TARGET_MEMORY_USAGE"`
+	TargetCPUUsage    float64 `yaml:"target_cpu_usage" env:"WORKER_TARGET_CPU_USAGE"`
+}
+
+// Payload formats of the order models
+const (
+	PayloadFormatJSON     = "json"
+	PayloadFormatProtobuf = "protobuf"
+)
+
+// Worker roles select which task queues a worker process polls
+const (
+	RoleAll      = "all"
+	RoleOrders   = "orders"
+	RolePayments = "payments"
+)
+
+// Default returns the settings used when neither the file nor the
+// environment sets a value
+func Default() Config {
+	httpConfig := activities.DefaultHTTPConfig()
+	breaker := activities.DefaultCircuitBreakerConfig()
+	rules := activities.DefaultValidationRules()
+	pool := store.DefaultPoolConfig()
+	slots := tuning.DefaultResourceSlotSupplierOptions()
+
+	return Config{
+		Encryption: Encryption{
+			KeyFile: ".encryption.key",
+			Vault: Vault{
+				AppRoleMount: "approle",
+				KVMount:      "secret",
+				KeyPath:      "temporal/order-processing",
+				KeyField:     "key",
+				KeyCacheTTL:  vault.DefaultKeyCacheTTL,
+			},
+			KMS:    KMS{DataKeyTTL: codec.DefaultDataKeyTTL},
+			Mode:   EncryptionModeAESGCM,
+			Fields: codec.DefaultEncryptedFields,
+		},
+		Logging: Logging{Format: logging.FormatText, Level: "info"},
+		Payload: Payload{
+			MaxBytes:    interceptors.DefaultMaxPayloadBytes,
+			Compression: string(codec.CompressionNone),
+			Format:      PayloadFormatJSON,
+		},
+		Validation: Validation{
+			URL: "http://localhost:8081/validate",
+			HTTP: ValidationHTTP{
+				Timeout:      httpConfig.Timeout,
+				MaxIdleConns: httpConfig.MaxIdleConns,
+				MaxRetries:   httpConfig.MaxRetries,
+			},
+			CircuitBreaker: CircuitBreaker{
+				FailureThreshold: breaker.FailureThreshold,
+				OpenTimeout:      breaker.OpenTimeout,
+			},
+			RateLimit: ValidationRateLimit{MaxWait: activities.DefaultRateLimitConfig().MaxWait},
+			LocalRules: ValidationLocalRules{
+				MaxAmount:   rules.MaxAmount,
+				MaxItems:    rules.MaxItems,
+				MaxQuantity: rules.MaxQuantityPerItem,
+			},
+		},
+		Kafka:    Kafka{OrderEventsTopic: "order-events", OrderIntakeTopic: "order-intake", ConsumerGroup: "order-consumer", AnalyticsTopic: "order-analytics"},
+		SQS:      SQS{VisibilityTimeout: 30 * time.Second, RetryBackoff: 5 * time.Second, MaxRetryBackoff: 5 * time.Minute},
+		Database: Database{MaxOpenConns: pool.MaxOpenConns, MaxIdleConns: pool.MaxIdleConns},
+		Health: Health{
+			Port:              8090,
+			WiremockURL:       "http://localhost:8081",
+			CacheTTL:          5 * time.Second,
+			Temporal:          LatencyThresholds{Warn: time.Second},
+			FailureThreshold:  1,
+			RecoveryThreshold: 1,
+			Wiremock:          LatencyThresholds{Warn: time.Second},
+			Postgres:          LatencyThresholds{Warn: time.Second},
+			Kafka:             LatencyThresholds{Warn: time.Second},
+			Push: HealthPush{
+				Interval: health.DefaultPublishInterval,
+				Prefix:   "order_worker",
+				Format:   health.FormatDogStatsD,
+			},
+		},
+		Metrics: Metrics{Port: 9090},
+		FeatureFlags: FeatureFlags{
+			RefreshInterval: featureflags.DefaultRefreshInterval,
+		},
+		Fraud:   Fraud{MaxAmountPerItem: activities.DefaultFraudMaxAmountPerItem},
+		Loyalty: Loyalty{PointsPerDollar: activities.DefaultLoyaltyPointsPerDollar},
+		Currency: Currency{
+			Base:     models.DefaultBaseCurrency,
+			CacheTTL: activities.DefaultExchangeRateCacheTTL,
+		},
+		Archive: Archive{StorageClass: archive.DefaultStorageClass},
+		CodecServer: CodecServer{
+			Port:        8888,
+			CORSOrigins: []string{"http://localhost:8080"},
+		},
+		Webhook: Webhook{Port: 8095, OrderIDPrefix: "SHOP-"},
+		Worker: Worker{
+			Role:        RoleAll,
+			StopTimeout: 30 * time.Second,
+			ResourceTuning: ResourceTuning{
+				MinActivitySlots:  slots.MinSlots,
+				MaxActivitySlots:  slots.MaxSlots,
+				TargetMemoryUsage: slots.TargetMemoryUsage,
+				TargetCPUUsage:    slots.TargetCPUUsage,
+			},
+		},
+	}
+}
+
+// Load returns the defaults overlaid with the YAML file at path, if path is
+// not empty, and then with the environment. The result is validated.
+func Load(path string) (Config, error) {
+	config := Default()
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return Config{}, fmt.Errorf("failed to read config file: %w", err)
+		}
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			return Config{}, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	}
+
+	if err := overlayEnv(&config, os.LookupEnv); err != nil {
+		return Config{}, err
+	}
+
+	config.Logging.Format = strings.ToLower(config.Logging.Format)
+
+	if err := config.Validate(); err != nil {
+		return Config{}, err
+	}
+	return config, nil
+}
+
+// LoadFromEnv loads the file named by CONFIG_FILE, if set, and the environment
+func LoadFromEnv() (Config, error) {
+	return Load(os.Getenv(FileEnv))
+}
+
+// Validate reports every invalid or missing setting at once
+func (c Config) Validate() error {
+	var errs []error
+	if err := c.CloudConfig().Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.TemporalAuthConfig().Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("temporal.auth: %w", err))
+	} else if c.TemporalAuthConfig().Enabled() && c.Temporal.APIKey != "" {
+		errs = append(errs, errors.New("temporal.auth and temporal.api_key both set the authorization header; configure one"))
+	}
+	if (c.Temporal.TLS.CertFile == "") != (c.Temporal.TLS.KeyFile == "") {
+		errs = append(errs, errors.New("temporal.tls.cert_file and temporal.tls.key_file must be set together"))
+	}
+	if v := c.Encryption.Vault; c.Encryption.Enabled && v.Address != "" {
+		if v.Token == "" && (v.RoleID == "" || v.SecretID == "") {
+			errs = append(errs, errors.New("encryption.vault needs a token, or a role_id and secret_id"))
+		}
+		if v.KeyPath == "" {
+			errs = append(errs, errors.New("encryption.vault.key_path is required"))
+		}
+	} else if c.Encryption.Enabled && c.Encryption.KeyFile == "" && c.Encryption.KMS.KeyID == "" {
+		errs = append(errs, errors.New("encryption.key_file is required when encryption is enabled"))
+	}
+	if c.Encryption.KMS.DataKeyTTL < 0 {
+		errs = append(errs, errors.New("encryption.kms.data_key_ttl must not be negative"))
+	}
+	switch c.Encryption.Mode {
+	case EncryptionModeAESGCM:
+	case EncryptionModeHMAC, EncryptionModeFields:
+		if c.Encryption.KMS.KeyID != "" {
+			errs = append(errs, fmt.Errorf("encryption.kms needs encryption.mode %q", EncryptionModeAESGCM))
+		}
+		if c.Encryption.Mode == EncryptionModeFields && len(c.Encryption.Fields) == 0 {
+			errs = append(errs, fmt.Errorf("encryption.fields is required with encryption.mode %q", EncryptionModeFields))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("encryption.mode must be %q, %q, or %q, got %q", EncryptionModeAESGCM, EncryptionModeHMAC, EncryptionModeFields, c.Encryption.Mode))
+	}
+	if c.Encryption.FIPS {
+		errs = append(errs, c.validateFIPS()...)
+	}
+	if _, err := codec.ParseCompression(c.Payload.Compression); err != nil {
+		errs = append(errs, fmt.Errorf("payload.compression: %w", err))
+	}
+	if c.Payload.Format != PayloadFormatJSON && c.Payload.Format != PayloadFormatProtobuf {
+		errs = append(errs, fmt.Errorf("payload.format must be %q or %q, got %q", PayloadFormatJSON, PayloadFormatProtobuf, c.Payload.Format))
+	}
+	if c.Logging.Format != logging.FormatText && c.Logging.Format != logging.FormatJSON {
+		errs = append(errs, fmt.Errorf("logging.format must be %q or %q, got %q", logging.FormatText, logging.FormatJSON, c.Logging.Format))
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Logging.Level)); err != nil {
+		errs = append(errs, fmt.Errorf("logging.level: %w", err))
+	}
+	if c.Validation.URL == "" {
+		errs = append(errs, errors.New("validation.url is required"))
+	}
+	if (c.Validation.HTTP.ClientCertFile == "") != (c.Validation.HTTP.ClientKeyFile == "") {
+		errs = append(errs, errors.New("validation.http.client_cert_file and client_key_file must be set together"))
+	}
+	if c.Kafka.OrderEventsTopic == "" && len(c.Kafka.Brokers) > 0 {
+		errs = append(errs, errors.New("kafka.order_events_topic is required with brokers"))
+	}
+	if c.Kafka.AnalyticsTopic == "" && len(c.Kafka.Brokers) > 0 {
+		errs = append(errs, errors.New("kafka.analytics_topic is required with brokers"))
+	}
+	if c.Reconciliation.Schedule != "" && (c.Reconciliation.GatewayURL == "" || c.Database.URL == "") {
+		errs = append(errs, errors.New("reconciliation.schedule needs reconciliation.gateway_url and database.url to compare payments with orders"))
+	}
+	if c.Reconciliation.SettlementSchedule != "" && (c.Reconciliation.GatewayURL == "" || c.Database.URL == "") {
+		errs = append(errs, errors.New("reconciliation.settlement_schedule needs reconciliation.gateway_url and database.url to record settlements on orders"))
+	}
+	if c.Currency.Base == "" {
+		errs = append(errs, errors.New("currency.base is required"))
+	}
+	if c.Currency.CacheTTL < 0 {
+		errs = append(errs, errors.New("currency.cache_ttl must not be negative"))
+	}
+	if !archive.ValidStorageClass(c.Archive.StorageClass) {
+		errs = append(errs, fmt.Errorf("archive.storage_class %q is not an S3 storage class", c.Archive.StorageClass))
+	}
+	switch c.Worker.Role {
+	case RoleAll, RoleOrders, RolePayments:
+	default:
+		errs = append(errs, fmt.Errorf("worker.role must be %s, %s, or %s, got %q", RoleAll, RoleOrders, RolePayments, c.Worker.Role))
+	}
+	if c.Health.FailureThreshold < 1 || c.Health.RecoveryThreshold < 1 {
+		errs = append(errs, errors.New("health.failure_threshold and health.recovery_threshold must be at least 1"))
+	}
+	if (c.Health.TLS.CertFile == "") != (c.Health.TLS.KeyFile == "") {
+		errs = append(errs, errors.New("health.tls.cert_file and health.tls.key_file must be set together"))
+	}
+	if c.Health.TLS.ClientCAFile != "" && c.Health.TLS.CertFile == "" {
+		errs = append(errs, errors.New("health.tls.client_ca_file requires health.tls.cert_file"))
+	}
+	if c.Health.Push.Interval <= 0 {
+		errs = append(errs, errors.New("health.push.interval must be positive"))
+	}
+	switch c.Health.Push.Format {
+	case health.FormatDogStatsD, health.FormatStatsD:
+	default:
+		errs = append(errs, fmt.Errorf("health.push.format must be %s or %s, got %q", health.FormatDogStatsD, health.FormatStatsD, c.Health.Push.Format))
+	}
+	if c.Health.GRPCPort < 0 {
+		errs = append(errs, errors.New("health.grpc_port must not be negative"))
+	}
+	if c.Health.CacheTTL < 0 {
+		errs = append(errs, errors.New("health.cache_ttl must not be negative"))
+	}
+	errs = append(errs,
+		c.Health.Temporal.validate("health.temporal"),
+		c.Health.Wiremock.validate("health.wiremock"),
+		c.Health.Postgres.validate("health.postgres"),
+		c.Health.Kafka.validate("health.kafka"),
+	)
+	if c.SQS.VisibilityTimeout < 2*time.Second || c.SQS.VisibilityTimeout > 12*time.Hour {
+		errs = append(errs, fmt.Errorf("sqs.visibility_timeout must be between 2s and 12h, got %s", c.SQS.VisibilityTimeout))
+	}
+	if c.SQS.RetryBackoff <= 0 || c.SQS.MaxRetryBackoff < c.SQS.RetryBackoff {
+		errs = append(errs, errors.New("sqs.retry_backoff must be positive and at most sqs.max_retry_backoff"))
+	}
+	if c.Worker.StopTimeout < 0 {
+		errs = append(errs, errors.New("worker.stop_timeout must not be negative"))
+	}
+	if _, err := c.CodecServerNamespaces(); err != nil {
+		errs = append(errs, err)
+	}
+	for name, profile := range c.Profiles {
+		if err := profile.apply(&Config{}); err != nil {
+			errs = append(errs, fmt.Errorf("profiles.%s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WithProfile returns c with the settings of the named profile layered over
+// its own, or c unchanged if name is empty. The profile wins over the
+// environment, since selecting it is an explicit choice of where to connect.
+func (c Config) WithProfile(name string) (Config, error) {
+	if name == "" {
+		return c, nil
+	}
+	profile, ok := c.Profiles[name]
+	if !ok {
+		if len(c.Profiles) == 0 {
+			return Config{}, fmt.Errorf("unknown profile %q; the config file has no profiles", name)
+		}
+		names := slices.Sorted(maps.Keys(c.Profiles))
+		return Config{}, fmt.Errorf("unknown profile %q; configured profiles: %s", name, strings.Join(names, ", "))
+	}
+	if err := profile.apply(&c); err != nil {
+		return Config{}, fmt.Errorf("profiles.%s: %w", name, err)
+	}
+	if err := c.Validate(); err != nil {
+		return Config{}, fmt.Errorf("profile %s: %w", name, err)
+	}
+	return c, nil
+}
+
+// apply decodes the profile's sections over those of config
+func (p Profile) apply(config *Config) error {
+	if !p.Temporal.IsZero() {
+		if err := p.Temporal.Decode(&config.Temporal); err != nil {
+			return fmt.Errorf("temporal: %w", err)
+		}
+	}
+	if !p.Encryption.IsZero() {
+		if err := p.Encryption.Decode(&config.Encryption); err != nil {
+			return fmt.Errorf("encryption: %w", err)
+		}
+	}
+	return nil
+}
+
+// LocalValidationRules returns the rules of the local validation fallback
+func (c Config) LocalValidationRules() activities.ValidationRules {
+	rules := activities.DefaultValidationRules()
+	rules.MaxAmount = c.Validation.LocalRules.MaxAmount
+	rules.MaxItems = c.Validation.LocalRules.MaxItems
+	rules.MaxQuantityPerItem = c.Validation.LocalRules.MaxQuantity
+	rules.AllowedItems = c.Validation.LocalRules.AllowedItems
+	return rules
+}
+
+// TemporalHostPort returns temporal.host_port, or the Temporal Cloud regional
+// endpoint when only a region is set, or DefaultTemporalHost
+func (c Config) TemporalHostPort() string {
+	if c.Temporal.HostPort != "" {
+		return c.Temporal.HostPort
+	}
+	return c.CloudConfig().HostPort(DefaultTemporalHost)
+}
+
+// LoggingConfig returns the settings for logging.New; Validate has already
+// checked the level parses
+func (c Config) LoggingConfig() logging.Config {
+	config := logging.Config{Format: c.Logging.Format, Level: slog.LevelInfo}
+	_ = config.Level.UnmarshalText([]byte(c.Logging.Level))
+	return config
+}
+
+// DataConverter returns the data converter for payload compression and
+// encryption. It fetches an encryption key with ctx so a misconfigured key
+// store fails at startup.
+func (c Config) DataConverter(ctx context.Context) (converter.DataConverter, error) {
+	builder, err := c.dataConverterBuilder(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return builder.Build()
+}
+
+// validateFIPS reports the settings FIPS mode does not allow: the Go
+// Cryptographic Module must be enabled, the development key file is not an
+// approved key source, and a Vault key must be read over TLS
+func (c Config) validateFIPS() []error {
+	var errs []error
+	if err := codec.CheckFIPS(); err != nil {
+		errs = append(errs, fmt.Errorf("encryption.fips: %w", err))
+	}
+	if !c.Encryption.Enabled {
+		return errs
+	}
+	switch c.EncryptionKeySource() {
+	case "file":
+		errs = append(errs, errors.New("encryption.fips: the development key_file is not an approved key source; use vault or kms"))
+	case "vault":
+		if !strings.HasPrefix(strings.ToLower(c.Encryption.Vault.Address), "https://") {
+			errs = append(errs, errors.New("encryption.fips: encryption.vault.address must use https"))
+		}
+	}
+	return errs
+}
+
+// FailureConverter returns the failure converter for a client using
+// dataConverter. With whole payloads encrypted, failure messages and stack
+// traces are encrypted along with failure details.
+func (c Config) FailureConverter(dataConverter converter.DataConverter) converter.FailureConverter {
+	if c.Encryption.Enabled && c.Encryption.Mode != EncryptionModeFields {
+		return codec.NewEncryptedFailureConverter(dataConverter)
+	}
+	return temporal.NewDefaultFailureConverter(temporal.DefaultFailureConverterOptions{DataConverter: dataConverter})
+}
+
+// CodecServerCodecs returns the payload codecs of every namespace the codec
+// server serves, fetching each encryption key with ctx
+func (c Config) CodecServerCodecs(ctx context.Context) (map[string][]converter.PayloadCodec, error) {
+	namespaces, err := c.CodecServerNamespaces()
+	if err != nil {
+		return nil, err
+	}
+	codecs := make(map[string][]converter.PayloadCodec, len(namespaces))
+	for namespace, encryption := range namespaces {
+		namespaceConfig := c
+		namespaceConfig.Encryption = encryption
+		builder, err := namespaceConfig.dataConverterBuilder(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("namespace %s: %w", namespace, err)
+		}
+		if codecs[namespace], err = builder.Codecs(); err != nil {
+			return nil, fmt.Errorf("namespace %s: %w", namespace, err)
+		}
+	}
+	return codecs, nil
+}
+
+// CodecServerNamespaces returns the encryption settings of each namespace the
+// codec server serves
+func (c Config) CodecServerNamespaces() (map[string]Encryption, error) {
+	own := c.Temporal.Namespace
+	if own == "" {
+		own = client.DefaultNamespace
+	}
+	namespaces := map[string]Encryption{own: c.Encryption}
+	for namespace, node := range c.CodecServer.Namespaces {
+		encryption := c.Encryption
+		if err := node.Decode(&encryption); err != nil {
+			return nil, fmt.Errorf("codec_server.namespaces.%s: %w", namespace, err)
+		}
+		namespaces[namespace] = encryption
+	}
+	return namespaces, nil
+}
+
+// dataConverterBuilder returns a builder with the configured codecs
+func (c Config) dataConverterBuilder(ctx context.Context) (*codec.DataConverterBuilder, error) {
+	compression, err := codec.ParseCompression(c.Payload.Compression)
+	if err != nil {
+		return nil, err
+	}
+	builder := codec.NewDataConverterBuilder().
+		WithPayloadConverter(orderspb.NewDataConverter(c.Payload.Format == PayloadFormatProtobuf)).
+		WithCompression(compression)
+	if c.Encryption.Enabled {
+		encryption, err := c.encryptionCodec(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if c.Encryption.Mode == EncryptionModeFields {
+			builder.WithFieldEncryption(encryption)
+		} else {
+			builder.WithEncryption(encryption)
+		}
+	}
+	return builder, nil
+}
+
+// encryptionCodec returns the payload encryption codec: envelope encryption
+// with KMS when configured, otherwise a codec over EncryptionKeys for the
+// encryption mode
+func (c Config) encryptionCodec(ctx context.Context) (converter.PayloadCodec, error) {
+	if c.Encryption.KMS.KeyID == "" {
+		keys, err := c.EncryptionKeys()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := keys.Current.Key(ctx); err != nil {
+			return nil, fmt.Errorf("failed to load encryption key: %w", err)
+		}
+		switch c.Encryption.Mode {
+		case EncryptionModeHMAC:
+			signer := codec.NewHMACCodec(keys)
+			signer.AllowUnsigned = c.Encryption.AllowUnsigned
+			return signer, nil
+		case EncryptionModeFields:
+			return codec.NewFieldEncryptionCodec(keys, c.Encryption.Fields), nil
+		}
+		return codec.NewEncryptionCodecWithKeyring(keys), nil
+	}
+
+	dataKeys, err := awskms.NewFromEnv(ctx, c.Encryption.KMS.KeyID, c.Encryption.KMS.Region, c.Encryption.FIPS)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := dataKeys.GenerateDataKey(ctx); err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+	envelope := codec.EnvelopeConfig{DataKeys: dataKeys, DataKeyTTL: c.Encryption.KMS.DataKeyTTL}
+	if c.Encryption.Vault.Address != "" {
+		if envelope.LegacyKeys, err = c.EncryptionKeys(); err != nil {
+			return nil, err
+		}
+	}
+	return codec.NewEnvelopeEncryptionCodec(envelope), nil
+}
+
+// EncryptionKeySource names where the encryption key comes from, for logs
+func (c Config) EncryptionKeySource() string {
+	switch {
+	case c.Encryption.KMS.KeyID != "":
+		return "kms"
+	case c.Encryption.Vault.Address != "":
+		return "vault"
+	default:
+		return "file"
+	}
+}
+
+// EncryptionKeys returns the payload encryption keyring: keys from Vault
+// when configured, otherwise from the development key files
+func (c Config) EncryptionKeys() (codec.Keyring, error) {
+	v := c.Encryption.Vault
+	if v.Address == "" {
+		key, err := codec.FileKeyProvider{Path: c.Encryption.KeyFile}.Key(context.Background())
+		if err != nil {
+			return codec.Keyring{}, err
+		}
+		keys := codec.Keyring{Current: codec.StaticKey(key)}
+		for _, path := range c.Encryption.RetiredKeyFiles {
+			// Retired keys must already exist, so they are not generated
+			retired, err := os.ReadFile(path)
+			if err != nil {
+				return codec.Keyring{}, fmt.Errorf("failed to read retired key file: %w", err)
+			}
+			if len(retired) != codec.KeySize {
+				return codec.Keyring{}, fmt.Errorf("retired key file %s must hold a %d byte key, got %d bytes", path, codec.KeySize, len(retired))
+			}
+			keys.Retired = append(keys.Retired, codec.StaticKey(retired))
+		}
+		return keys, nil
+	}
+
+	var auth vault.Auth = vault.TokenAuth{Token: v.Token}
+	if v.RoleID != "" {
+		auth = vault.AppRoleAuth{Mount: v.AppRoleMount, RoleID: v.RoleID, SecretID: v.SecretID}
+	}
+	client, err := vault.NewClient(vault.Config{Address: v.Address, Namespace: v.Namespace, CAFile: v.CAFile}, auth)
+	if err != nil {
+		return codec.Keyring{}, err
+	}
+	provider := func(field string) *vault.KeyProvider {
+		keys := vault.NewKeyProvider(client, vault.KeyConfig{
+			Mount:    v.KVMount,
+			Path:     v.KeyPath,
+			Field:    field,
+			CacheTTL: v.KeyCacheTTL,
+		})
+		keys.OnRefreshError = func(err error) {
+			slog.Warn("Failed to refresh encryption key from Vault, using cached key", "field", field, "error", err)
+		}
+		return keys
+	}
+	keys := codec.Keyring{Current: provider(v.KeyField)}
+	for _, field := range v.RetiredKeyFields {
+		keys.Retired = append(keys.Retired, provider(field))
+	}
+	return keys, nil
+}
+
+// CloudConfig returns the namespace and Temporal Cloud settings
+func (c Config) CloudConfig() cloud.Config {
+	return cloud.Config{
+		Namespace: c.Temporal.Namespace,
+		APIKey:    c.Temporal.APIKey,
+		Region:    c.Temporal.CloudRegion,
+	}
+}
+
+// TemporalAuthConfig returns the Temporal client's bearer token settings
+func (c Config) TemporalAuthConfig() temporalauth.Config {
+	a := c.Temporal.Auth
+	return temporalauth.Config{
+		Token:              a.Token,
+		TokenFile:          a.TokenFile,
+		OAuth2TokenURL:     a.OAuth2TokenURL,
+		OAuth2ClientID:     a.OAuth2ClientID,
+		OAuth2ClientSecret: a.OAuth2ClientSecret,
+		OAuth2Scopes:       a.OAuth2Scopes,
+		OAuth2Audience:     a.OAuth2Audience,
+		RefreshBefore:      a.RefreshBefore,
+	}
+}
+
+// TLSConfig returns the Temporal connection TLS settings
+func (c Config) TLSConfig() tlsconfig.Config {
+	return tlsconfig.Config{
+		CAFile:     c.Temporal.TLS.CAFile,
+		CertFile:   c.Temporal.TLS.CertFile,
+		KeyFile:    c.Temporal.TLS.KeyFile,
+		ServerName: c.Temporal.TLS.ServerName,
+	}
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"gopkg.in/yaml.v3"
+)
+
+// dynamicFileContents is the YAML layout of the dynamic config file
+type dynamicFileContents struct {
+	HighValueApprovalThreshold float64           `yaml:"high_value_approval_threshold"`
+	ProcessingSLA              time.Duration     `yaml:"processing_sla"`
+	ExpeditedChannels          []string          `yaml:"expedited_channels"`
+	ParentClosePolicies        map[string]string `yaml:"parent_close_policies"`
+	AnalyticsExport            bool              `yaml:"analytics_export"`
+	PaymentNexusEndpoint       string            `yaml:"payment_nexus_endpoint"`
+	BackorderTimeout           time.Duration     `yaml:"backorder_timeout"`
+	BackorderRecheckInterval   time.Duration     `yaml:"backorder_recheck_interval"`
+	EditGracePeriod            time.Duration     `yaml:"edit_grace_period"`
+	TrackingPollInterval       time.Duration     `yaml:"tracking_poll_interval"`
+	WarehouseRouting           bool              `yaml:"warehouse_routing"`
+	OrderArchival              bool              `yaml:"order_archival"`
+	PIIRetention               time.Duration     `yaml:"pii_retention"`
+}
+
+// DynamicFile serves models.DynamicConfig from a YAML file that can be edited
+// while workers run. The file is re-read whenever its modification time
+// changes; a missing file serves the zero configuration.
+type DynamicFile struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	current models.DynamicConfig
+}
+
+// NewDynamicFile creates a source reading the file at path
+func NewDynamicFile(path string) *DynamicFile {
+	return &DynamicFile{path: path}
+}
+
+// DynamicConfig returns the file's current contents. If the file cannot be
+// read or parsed, the last valid contents are returned along with the error.
+func (f *DynamicFile) DynamicConfig() (models.DynamicConfig, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	info, err := os.Stat(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		f.modTime, f.current = time.Time{}, models.DynamicConfig{}
+		return f.current, nil
+	}
+	if err != nil {
+		return f.current, err
+	}
+	if info.ModTime().Equal(f.modTime) {
+		return f.current, nil
+	}
+
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return f.current, err
+	}
+	var contents dynamicFileContents
+	if err := yaml.Unmarshal(data, &contents); err != nil {
+		return f.current, fmt.Errorf("failed to parse %s: %w", f.path, err)
+	}
+	if contents.HighValueApprovalThreshold < 0 || contents.ProcessingSLA < 0 ||
+		contents.BackorderTimeout < 0 || contents.BackorderRecheckInterval < 0 ||
+		contents.EditGracePeriod < 0 || contents.TrackingPollInterval < 0 ||
+		contents.PIIRetention < 0 {
+		return f.current, fmt.Errorf("%s: values must not be negative", f.path)
+	}
+	for child, policy := range contents.ParentClosePolicies {
+		switch child {
+		case models.ChildPayment, models.ChildFulfillment, models.ChildAnalytics, models.ChildArchive:
+		default:
+			return f.current, fmt.Errorf("%s: parent_close_policies: unknown child %q; use %s, %s, %s, or %s", f.path, child, models.ChildPayment, models.ChildFulfillment, models.ChildAnalytics, models.ChildArchive)
+		}
+		switch policy {
+		case models.ParentCloseTerminate, models.ParentCloseRequestCancel, models.ParentCloseAbandon:
+		default:
+			return f.current, fmt.Errorf("%s: parent_close_policies.%s: unknown policy %q; use %s, %s, or %s", f.path, child, policy, models.ParentCloseTerminate, models.ParentCloseRequestCancel, models.ParentCloseAbandon)
+		}
+	}
+
+	f.modTime = info.ModTime()
+	f.current = models.DynamicConfig{
+		HighValueApprovalThreshold: contents.HighValueApprovalThreshold,
+		ProcessingSLA:              contents.ProcessingSLA,
+		ExpeditedChannels:          contents.ExpeditedChannels,
+		ParentClosePolicies:        contents.ParentClosePolicies,
+		AnalyticsExport:            contents.AnalyticsExport,
+		PaymentNexusEndpoint:       contents.PaymentNexusEndpoint,
+		BackorderTimeout:           contents.BackorderTimeout,
+		BackorderRecheckInterval:   contents.BackorderRecheckInterval,
+		EditGracePeriod:            contents.EditGracePeriod,
+		TrackingPollInterval:       contents.TrackingPollInterval,
+		WarehouseRouting:           contents.WarehouseRouting,
+		OrderArchival:              contents.OrderArchival,
+		PIIRetention:               contents.PIIRetention,
+	}
+	return f.current, nil
+}
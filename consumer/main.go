@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/aswathylr-builds/temporal-order-processing/authz"
+	"github.com/aswathylr-builds/temporal-order-processing/config"
+	"github.com/aswathylr-builds/temporal-order-processing/intake"
+	"github.com/aswathylr-builds/temporal-order-processing/logging"
+	"github.com/aswathylr-builds/temporal-order-processing/temporalclient"
+)
+
+const (
+	taskQueue = "order-processing-queue"
+)
+
+// Reads order-create and order-cancel messages from a Kafka topic, or an SQS
+// queue when one is configured, and starts or cancels the order workflow for
+// each. A message is acknowledged only after Temporal accepts the request, so
+// delivery is at least once; the idempotent start and cancel make
+// redelivery harmless.
+func main() {
+	// Settings come from the CONFIG_FILE YAML file, overlaid with environment variables
+	cfg, err := config.LoadFromEnv()
+	if err != nil {
+		fatal("Invalid configuration", "error", err)
+	}
+
+	logger := logging.New(os.Stderr, cfg.LoggingConfig())
+	slog.SetDefault(logger)
+
+	if cfg.SQS.QueueURL == "" && len(cfg.Kafka.Brokers) == 0 {
+		fatal("kafka.brokers or sqs.queue_url is required; set KAFKA_BROKERS or SQS_QUEUE_URL")
+	}
+
+	c, err := temporalclient.Dial(cfg, logger)
+	if err != nil {
+		fatal("Unable to create Temporal client", "error", err)
+	}
+	defer c.Close()
+
+	submitter := intake.NewSubmitter(c, taskQueue)
+	if len(cfg.Auth.SignalSecrets) > 0 {
+		signer, err := authz.NewSigner(cfg.Auth.SignalSecrets...)
+		if err != nil {
+			fatal("Invalid signal auth configuration", "error", err)
+		}
+		submitter.WithSigner(signer, "order-consumer")
+	}
+
+	handler := intake.NewMessageHandler(submitter, submitter)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var consumer interface{ Run(context.Context) error }
+	if cfg.SQS.QueueURL != "" {
+		sqsConsumer, err := intake.NewSQSConsumerFromEnv(ctx, handler, cfg.SQS.Region, intake.SQSConsumerOptions{
+			QueueURL:           cfg.SQS.QueueURL,
+			DeadLetterQueueURL: cfg.SQS.DeadLetterQueueURL,
+			VisibilityTimeout:  cfg.SQS.VisibilityTimeout,
+			RetryBackoff:       cfg.SQS.RetryBackoff,
+			MaxRetryBackoff:    cfg.SQS.MaxRetryBackoff,
+		})
+		if err != nil {
+			fatal("Invalid SQS configuration", "error", err)
+		}
+		if cfg.SQS.DeadLetterQueueURL == "" {
+			slog.Warn("sqs.dead_letter_queue_url is not set; malformed messages will be logged and dropped")
+		}
+		consumer = sqsConsumer
+		slog.Info("Order consumer started", "queue", cfg.SQS.QueueURL, "task_queue", taskQueue)
+	} else {
+		kafkaConsumer, err := intake.NewKafkaConsumer(handler, intake.KafkaConsumerOptions{
+			Brokers: cfg.Kafka.Brokers,
+			Topic:   cfg.Kafka.OrderIntakeTopic,
+			GroupID: cfg.Kafka.ConsumerGroup,
+		})
+		if err != nil {
+			fatal("Invalid Kafka configuration", "error", err)
+		}
+		defer kafkaConsumer.Close()
+		consumer = kafkaConsumer
+		slog.Info("Order consumer started", "topic", cfg.Kafka.OrderIntakeTopic, "group", cfg.Kafka.ConsumerGroup, "task_queue", taskQueue)
+	}
+
+	err = consumer.Run(ctx)
+	stop()
+	if err != nil {
+		slog.Error("Order consumer failed", "error", err)
+		os.Exit(1)
+	}
+	slog.Info("Shutting down order consumer")
+}
+
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
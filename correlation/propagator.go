@@ -0,0 +1,125 @@
+package correlation
+
+import (
+	"context"
+	"net/http"
+
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/workflow"
+)
+
+const (
+	// HeaderCorrelationID and HeaderTenantID are forwarded on outgoing HTTP calls
+	HeaderCorrelationID = "X-Correlation-ID"
+	HeaderTenantID      = "X-Tenant-ID"
+
+	// propagationKey is the Temporal header carrying the values
+	propagationKey = "correlation"
+)
+
+// Values identify the request an order belongs to across services
+type Values struct {
+	CorrelationID string `json:"correlation_id,omitempty"`
+	TenantID      string `json:"tenant_id,omitempty"`
+}
+
+type contextKey struct{}
+
+// WithValues returns a copy of ctx carrying the values. Workflows started or
+// signalled with this context carry them into every activity they run.
+func WithValues(ctx context.Context, values Values) context.Context {
+	return context.WithValue(ctx, contextKey{}, values)
+}
+
+// FromContext returns the values carried by an activity or client context
+func FromContext(ctx context.Context) (Values, bool) {
+	values, ok := ctx.Value(contextKey{}).(Values)
+	return values, ok
+}
+
+// FromWorkflowContext returns the values carried by a workflow context
+func FromWorkflowContext(ctx workflow.Context) (Values, bool) {
+	values, ok := ctx.Value(contextKey{}).(Values)
+	return values, ok
+}
+
+// SetHeaders adds the values in ctx to an outgoing HTTP request
+func SetHeaders(ctx context.Context, req *http.Request) {
+	values, ok := FromContext(ctx)
+	if !ok {
+		return
+	}
+	if values.CorrelationID != "" {
+		req.Header.Set(HeaderCorrelationID, values.CorrelationID)
+	}
+	if values.TenantID != "" {
+		req.Header.Set(HeaderTenantID, values.TenantID)
+	}
+}
+
+// Propagator carries Values through Temporal headers from the starter into
+// workflows, child workflows, and activities. Register it on both the client
+// and the worker via client.Options.ContextPropagators.
+type Propagator struct{}
+
+// NewPropagator creates a correlation context propagator
+func NewPropagator() workflow.ContextPropagator {
+	return &Propagator{}
+}
+
+// Inject writes the values from a client context into the headers
+func (p *Propagator) Inject(ctx context.Context, writer workflow.HeaderWriter) error {
+	values, ok := FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	return inject(values, writer)
+}
+
+// InjectFromWorkflow writes the values from a workflow context into the headers
+func (p *Propagator) InjectFromWorkflow(ctx workflow.Context, writer workflow.HeaderWriter) error {
+	values, ok := FromWorkflowContext(ctx)
+	if !ok {
+		return nil
+	}
+	return inject(values, writer)
+}
+
+// Extract reads the values from the headers into an activity context
+func (p *Propagator) Extract(ctx context.Context, reader workflow.HeaderReader) (context.Context, error) {
+	values, ok, err := extract(reader)
+	if err != nil || !ok {
+		return ctx, err
+	}
+	return WithValues(ctx, values), nil
+}
+
+// ExtractToWorkflow reads the values from the headers into a workflow context
+func (p *Propagator) ExtractToWorkflow(ctx workflow.Context, reader workflow.HeaderReader) (workflow.Context, error) {
+	values, ok, err := extract(reader)
+	if err != nil || !ok {
+		return ctx, err
+	}
+	return workflow.WithValue(ctx, contextKey{}, values), nil
+}
+
+func inject(values Values, writer workflow.HeaderWriter) error {
+	payload, err := converter.GetDefaultDataConverter().ToPayload(values)
+	if err != nil {
+		return err
+	}
+	writer.Set(propagationKey, payload)
+	return nil
+}
+
+func extract(reader workflow.HeaderReader) (Values, bool, error) {
+	payload, ok := reader.Get(propagationKey)
+	if !ok {
+		return Values{}, false, nil
+	}
+	var values Values
+	if err := converter.GetDefaultDataConverter().FromPayload(payload, &values); err != nil {
+		return Values{}, false, err
+	}
+	return values, true, nil
+}
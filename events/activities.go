@@ -0,0 +1,41 @@
+package events
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/activity"
+)
+
+// EventActivities contains activities that emit order lifecycle events
+type EventActivities struct {
+	Publisher Publisher
+}
+
+// NewEventActivities creates a new instance of EventActivities
+func NewEventActivities(publisher Publisher) *EventActivities {
+	if publisher == nil {
+		publisher = NoopPublisher{}
+	}
+	return &EventActivities{
+		Publisher: publisher,
+	}
+}
+
+// PublishOrderEvent publishes a single order lifecycle event
+func (a *EventActivities) PublishOrderEvent(ctx context.Context, event models.OrderEvent) error {
+	if activity.IsActivity(ctx) {
+		logger := activity.GetLogger(ctx)
+		logger.Info("Publishing order event", "order_id", event.OrderID, "type", event.Type, "event_id", event.EventID)
+	}
+
+	if event.SchemaVersion == 0 {
+		event.SchemaVersion = models.OrderEventSchemaVersion
+	}
+
+	if err := a.Publisher.Publish(ctx, event); err != nil {
+		return fmt.Errorf("failed to publish %s event: %w", event.Type, err)
+	}
+	return nil
+}
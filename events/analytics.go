@@ -0,0 +1,101 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/segmentio/kafka-go"
+	"go.temporal.io/sdk/activity"
+)
+
+// AnalyticsExporter exports completed-order records to an analytics store
+type AnalyticsExporter interface {
+	Export(ctx context.Context, record models.OrderAnalytics) error
+	Close() error
+}
+
+// KafkaAnalyticsExporter writes order analytics records to a Kafka topic
+type KafkaAnalyticsExporter struct {
+	writer *kafka.Writer
+}
+
+// NewKafkaAnalyticsExporter creates an exporter that writes to the given
+// topic, keyed by order ID like order events
+func NewKafkaAnalyticsExporter(brokers []string, topic string) (*KafkaAnalyticsExporter, error) {
+	if len(brokers) == 0 {
+		return nil, fmt.Errorf("at least one Kafka broker is required")
+	}
+	if topic == "" {
+		return nil, fmt.Errorf("Kafka topic is required")
+	}
+
+	return &KafkaAnalyticsExporter{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Topic:        topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+			BatchTimeout: 10 * time.Millisecond,
+			WriteTimeout: 10 * time.Second,
+		},
+	}, nil
+}
+
+// Export writes a single record to Kafka
+func (e *KafkaAnalyticsExporter) Export(ctx context.Context, record models.OrderAnalytics) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal order analytics: %w", err)
+	}
+	msg := kafka.Message{Key: []byte(record.OrderID), Value: value, Time: record.ClosedAt}
+	if err := e.writer.WriteMessages(ctx, msg); err != nil {
+		return fmt.Errorf("failed to write order analytics to Kafka: %w", err)
+	}
+	return nil
+}
+
+// Close flushes pending records and closes the underlying writer
+func (e *KafkaAnalyticsExporter) Close() error {
+	return e.writer.Close()
+}
+
+// NoopAnalyticsExporter discards records. It is used when no analytics
+// topic is configured so the export workflow can always run.
+type NoopAnalyticsExporter struct{}
+
+// Export discards the record
+func (NoopAnalyticsExporter) Export(ctx context.Context, record models.OrderAnalytics) error {
+	return nil
+}
+
+// Close is a no-op
+func (NoopAnalyticsExporter) Close() error {
+	return nil
+}
+
+// AnalyticsActivities contains the activity that exports order analytics
+type AnalyticsActivities struct {
+	Exporter AnalyticsExporter
+}
+
+// NewAnalyticsActivities creates a new instance of AnalyticsActivities
+func NewAnalyticsActivities(exporter AnalyticsExporter) *AnalyticsActivities {
+	if exporter == nil {
+		exporter = NoopAnalyticsExporter{}
+	}
+	return &AnalyticsActivities{Exporter: exporter}
+}
+
+// ExportOrderAnalytics exports the analytics record of a completed order
+func (a *AnalyticsActivities) ExportOrderAnalytics(ctx context.Context, record models.OrderAnalytics) error {
+	if activity.IsActivity(ctx) {
+		activity.GetLogger(ctx).Info("Exporting order analytics", "order_id", record.OrderID, "status", record.Status)
+	}
+	if err := a.Exporter.Export(ctx, record); err != nil {
+		return fmt.Errorf("failed to export analytics for order %s: %w", record.OrderID, err)
+	}
+	return nil
+}
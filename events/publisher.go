@@ -0,0 +1,87 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/segmentio/kafka-go"
+)
+
+// Publisher publishes order lifecycle events to a downstream stream
+type Publisher interface {
+	Publish(ctx context.Context, event models.OrderEvent) error
+	Close() error
+}
+
+// KafkaPublisher publishes order events to a Kafka topic
+type KafkaPublisher struct {
+	writer *kafka.Writer
+}
+
+// NewKafkaPublisher creates a publisher that writes to the given topic.
+// Events are keyed by order ID so all events for one order land on the
+// same partition and are consumed in order.
+func NewKafkaPublisher(brokers []string, topic string) (*KafkaPublisher, error) {
+	if len(brokers) == 0 {
+		return nil, fmt.Errorf("at least one Kafka broker is required")
+	}
+	if topic == "" {
+		return nil, fmt.Errorf("Kafka topic is required")
+	}
+
+	return &KafkaPublisher{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Topic:        topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+			BatchTimeout: 10 * time.Millisecond,
+			WriteTimeout: 10 * time.Second,
+		},
+	}, nil
+}
+
+// Publish writes a single event to Kafka
+func (p *KafkaPublisher) Publish(ctx context.Context, event models.OrderEvent) error {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal order event: %w", err)
+	}
+
+	msg := kafka.Message{
+		Key:   []byte(event.OrderID),
+		Value: value,
+		Headers: []kafka.Header{
+			{Key: "event_type", Value: []byte(event.Type)},
+			{Key: "event_id", Value: []byte(event.EventID)},
+		},
+		Time: event.OccurredAt,
+	}
+
+	if err := p.writer.WriteMessages(ctx, msg); err != nil {
+		return fmt.Errorf("failed to write event to Kafka: %w", err)
+	}
+	return nil
+}
+
+// Close flushes pending messages and closes the underlying writer
+func (p *KafkaPublisher) Close() error {
+	return p.writer.Close()
+}
+
+// NoopPublisher discards events. It is used when no event stream is configured
+// so that workflows can always schedule the publish activity.
+type NoopPublisher struct{}
+
+// Publish discards the event
+func (NoopPublisher) Publish(ctx context.Context, event models.OrderEvent) error {
+	return nil
+}
+
+// Close is a no-op
+func (NoopPublisher) Close() error {
+	return nil
+}
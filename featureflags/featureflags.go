@@ -0,0 +1,104 @@
+// Package featureflags turns features on and off per environment without code
+// changes. Flags are looked up in a chain of providers (environment variables,
+// a file, a remote service) and fall back to built-in defaults.
+//
+// Activities call Flags.Enabled directly. Workflows must call WorkflowEnabled,
+// which records the value in a side effect so replays see the same answer
+// after the flag changes.
+package featureflags
+
+import (
+	"context"
+	"sync"
+)
+
+// Flags gating order processing behavior
+const (
+	// ChildWorkflowPayment runs payments as a PaymentWorkflow child on the
+	// payment task queue; when off, ProcessPayment runs as an activity
+	ChildWorkflowPayment = "child-workflow-payment"
+	// FraudCheck screens orders with the CheckFraud activity before payment
+	FraudCheck = "fraud-check"
+	// RiskAssessment scores orders in a RiskAssessmentWorkflow child, which
+	// runs the fraud check itself, and sends them on by risk tier: to
+	// payment, step-up verification, or manual review
+	RiskAssessment = "risk-assessment"
+	// CustomerScreening checks the customer against the denylist and
+	// sanctions lists with the ScreenCustomer activity before payment
+	CustomerScreening = "customer-screening"
+	// EmailNotifications sends the order completion email
+	EmailNotifications = "email-notifications"
+	// SMSNotifications sends the order completion text message
+	SMSNotifications = "sms-notifications"
+	// SearchAttributes upserts the OrderStatus and OrderExpedited search
+	// attributes, which must be registered on the namespace before it is on
+	SearchAttributes = "search-attributes"
+)
+
+// Defaults are the values used when no provider defines a flag
+var Defaults = map[string]bool{
+	ChildWorkflowPayment: true,
+	FraudCheck:           false,
+	RiskAssessment:       false,
+	CustomerScreening:    false,
+	EmailNotifications:   true,
+	SMSNotifications:     false,
+	SearchAttributes:     false,
+}
+
+// Provider is a source of flag values
+type Provider interface {
+	// Lookup returns the flag's value and whether this provider defines it
+	Lookup(ctx context.Context, name string) (enabled bool, ok bool, err error)
+}
+
+// Flags resolves flags from providers in order; the first provider defining a
+// flag wins, and Defaults apply when none does
+type Flags struct {
+	providers []Provider
+	// OnError, if set, is called when a provider fails; the lookup then
+	// continues with the next provider
+	OnError func(name string, err error)
+}
+
+// New creates flags backed by the given providers, highest precedence first
+func New(providers ...Provider) *Flags {
+	return &Flags{providers: providers}
+}
+
+// Enabled reports whether the flag is on
+func (f *Flags) Enabled(ctx context.Context, name string) bool {
+	for _, provider := range f.providers {
+		enabled, ok, err := provider.Lookup(ctx, name)
+		if err != nil {
+			if f.OnError != nil {
+				f.OnError(name, err)
+			}
+			continue
+		}
+		if ok {
+			return enabled
+		}
+	}
+	return Defaults[name]
+}
+
+var (
+	defaultMu    sync.RWMutex
+	defaultFlags = New()
+)
+
+// SetDefault sets the flags read by WorkflowEnabled; workers call it at startup
+func SetDefault(flags *Flags) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultFlags = flags
+}
+
+// Default returns the flags set with SetDefault, or flags serving only
+// Defaults if it has not been called
+func Default() *Flags {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultFlags
+}
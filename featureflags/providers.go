@@ -0,0 +1,173 @@
+package featureflags
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultEnvPrefix prefixes the environment variables read by EnvProvider
+const DefaultEnvPrefix = "FEATURE_"
+
+// EnvProvider reads flags from environment variables: fraud-check is
+// FEATURE_FRAUD_CHECK. Values are parsed with strconv.ParseBool.
+type EnvProvider struct {
+	Prefix string
+}
+
+// NewEnvProvider creates an environment provider using DefaultEnvPrefix
+func NewEnvProvider() *EnvProvider {
+	return &EnvProvider{Prefix: DefaultEnvPrefix}
+}
+
+// Lookup implements Provider
+func (p *EnvProvider) Lookup(ctx context.Context, name string) (bool, bool, error) {
+	variable := p.Prefix + strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(name))
+	value, ok := os.LookupEnv(variable)
+	if !ok || value == "" {
+		return false, false, nil
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, false, fmt.Errorf("invalid %s: %w", variable, err)
+	}
+	return enabled, true, nil
+}
+
+// FileProvider reads flags from a YAML file mapping flag names to booleans.
+// The file is re-read whenever its modification time changes, so flags can
+// be flipped without a restart; a missing file defines no flags.
+type FileProvider struct {
+	path string
+
+	mu      sync.Mutex
+	modTime time.Time
+	flags   map[string]bool
+}
+
+// NewFileProvider creates a provider reading the file at path
+func NewFileProvider(path string) *FileProvider {
+	return &FileProvider{path: path}
+}
+
+// Lookup implements Provider. If the file cannot be read or parsed, the last
+// valid contents are used and the error is returned.
+func (p *FileProvider) Lookup(ctx context.Context, name string) (bool, bool, error) {
+	flags, err := p.load()
+	enabled, ok := flags[name]
+	return enabled, ok, err
+}
+
+func (p *FileProvider) load() (map[string]bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	info, err := os.Stat(p.path)
+	if errors.Is(err, os.ErrNotExist) {
+		p.modTime, p.flags = time.Time{}, nil
+		return nil, nil
+	}
+	if err != nil {
+		return p.flags, err
+	}
+	if info.ModTime().Equal(p.modTime) {
+		return p.flags, nil
+	}
+
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return p.flags, err
+	}
+	var flags map[string]bool
+	if err := yaml.Unmarshal(data, &flags); err != nil {
+		return p.flags, fmt.Errorf("failed to parse %s: %w", p.path, err)
+	}
+	p.modTime, p.flags = info.ModTime(), flags
+	return flags, nil
+}
+
+// DefaultRefreshInterval is how long RemoteProvider caches flags
+const DefaultRefreshInterval = 30 * time.Second
+
+// RemoteProvider fetches flags from an HTTP endpoint returning a JSON object
+// of flag names to booleans, such as {"fraud-check": true}. Responses are
+// cached for RefreshInterval; when a refresh fails, the last fetched flags are
+// used until the next attempt.
+type RemoteProvider struct {
+	URL             string
+	Client          *http.Client
+	RefreshInterval time.Duration
+
+	mu        sync.Mutex
+	fetchedAt time.Time
+	flags     map[string]bool
+}
+
+// NewRemoteProvider creates a provider for url with a 5 second request timeout
+func NewRemoteProvider(url string, refreshInterval time.Duration) *RemoteProvider {
+	if refreshInterval <= 0 {
+		refreshInterval = DefaultRefreshInterval
+	}
+	return &RemoteProvider{
+		URL:             url,
+		Client:          &http.Client{Timeout: 5 * time.Second},
+		RefreshInterval: refreshInterval,
+	}
+}
+
+// Lookup implements Provider
+func (p *RemoteProvider) Lookup(ctx context.Context, name string) (bool, bool, error) {
+	flags, err := p.load(ctx)
+	enabled, ok := flags[name]
+	return enabled, ok, err
+}
+
+func (p *RemoteProvider) load(ctx context.Context) (map[string]bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.fetchedAt.IsZero() && time.Since(p.fetchedAt) < p.RefreshInterval {
+		return p.flags, nil
+	}
+	// Wait a full interval before retrying a failed fetch
+	p.fetchedAt = time.Now()
+
+	flags, err := p.fetch(ctx)
+	if err != nil {
+		return p.flags, err
+	}
+	p.flags = flags
+	return flags, nil
+}
+
+func (p *RemoteProvider) fetch(ctx context.Context) (map[string]bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create feature flag request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch feature flags: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("feature flag service returned status %d", resp.StatusCode)
+	}
+	var flags map[string]bool
+	if err := json.NewDecoder(resp.Body).Decode(&flags); err != nil {
+		return nil, fmt.Errorf("failed to decode feature flags: %w", err)
+	}
+	return flags, nil
+}
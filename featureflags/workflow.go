@@ -0,0 +1,21 @@
+package featureflags
+
+import (
+	"context"
+
+	"go.temporal.io/sdk/workflow"
+)
+
+// WorkflowEnabled reports whether the flag is on, reading Default in a side
+// effect. The value is recorded in history on first execution, so a replay
+// takes the same branch even if the flag has since been flipped.
+func WorkflowEnabled(ctx workflow.Context, name string) bool {
+	var enabled bool
+	err := workflow.SideEffect(ctx, func(workflow.Context) interface{} {
+		return Default().Enabled(context.Background(), name)
+	}).Get(&enabled)
+	if err != nil {
+		return Defaults[name]
+	}
+	return enabled
+}
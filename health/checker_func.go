@@ -0,0 +1,150 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sync"
+	"time"
+)
+
+// checkerFunc adapts a function to the Checker interface
+type checkerFunc struct {
+	name  string
+	check func(ctx context.Context) ComponentHealth
+}
+
+// CheckerFunc returns a Checker named name that runs check
+func CheckerFunc(name string, check func(ctx context.Context) ComponentHealth) Checker {
+	return checkerFunc{name: name, check: check}
+}
+
+// Name returns the checker name
+func (c checkerFunc) Name() string {
+	return c.name
+}
+
+// Check performs the health check
+func (c checkerFunc) Check(ctx context.Context) ComponentHealth {
+	return c.check(ctx)
+}
+
+// CheckBuilder configures a custom check for RegisterCheck, such as a
+// database, cache, or queue the embedding service depends on
+type CheckBuilder struct {
+	checker     Checker
+	interval    time.Duration
+	timeout     time.Duration
+	criticality Criticality
+	tags        map[string]string
+}
+
+// NewCheck starts building a critical check named name that runs check on
+// every evaluation, bounded only by the server's own timeout
+func NewCheck(name string, check func(ctx context.Context) ComponentHealth) *CheckBuilder {
+	return &CheckBuilder{
+		checker:     CheckerFunc(name, check),
+		criticality: CriticalityCritical,
+	}
+}
+
+// WithInterval runs the check at most once per interval, reporting its last
+// result in between, for checks too expensive to run on every evaluation
+func (b *CheckBuilder) WithInterval(interval time.Duration) *CheckBuilder {
+	b.interval = interval
+	return b
+}
+
+// WithTimeout reports the component unhealthy if the check takes longer than
+// timeout
+func (b *CheckBuilder) WithTimeout(timeout time.Duration) *CheckBuilder {
+	b.timeout = timeout
+	return b
+}
+
+// WithCriticality sets how the component's failure affects the service
+func (b *CheckBuilder) WithCriticality(criticality Criticality) *CheckBuilder {
+	b.criticality = criticality
+	return b
+}
+
+// WithTags adds tags to the component's results, such as the team that owns
+// the dependency
+func (b *CheckBuilder) WithTags(tags map[string]string) *CheckBuilder {
+	if b.tags == nil {
+		b.tags = make(map[string]string, len(tags))
+	}
+	maps.Copy(b.tags, tags)
+	return b
+}
+
+// Build returns the configured Checker; its criticality is applied by
+// RegisterCheck
+func (b *CheckBuilder) Build() Checker {
+	return &builtChecker{
+		Checker:  b.checker,
+		interval: b.interval,
+		timeout:  b.timeout,
+		tags:     maps.Clone(b.tags),
+	}
+}
+
+// RegisterCheck adds the check built by builder
+func (s *Server) RegisterCheck(builder *CheckBuilder) {
+	s.RegisterCheckerWithCriticality(builder.Build(), builder.criticality)
+}
+
+// builtChecker applies a CheckBuilder's interval, timeout, and tags around a
+// Checker
+type builtChecker struct {
+	Checker
+	interval time.Duration
+	timeout  time.Duration
+	tags     map[string]string
+
+	mu        sync.Mutex
+	last      ComponentHealth
+	checkedAt time.Time
+}
+
+// Check performs the health check, or returns the last result within the
+// interval
+func (c *builtChecker) Check(ctx context.Context) ComponentHealth {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.interval > 0 && !c.checkedAt.IsZero() && time.Since(c.checkedAt) < c.interval {
+		return c.last
+	}
+
+	health := c.check(ctx)
+	if len(c.tags) > 0 {
+		health.Tags = c.tags
+	}
+	c.last, c.checkedAt = health, time.Now()
+	return health
+}
+
+// check runs the checker, giving up after the timeout even if it ignores its
+// context
+func (c *builtChecker) check(ctx context.Context) ComponentHealth {
+	if c.timeout <= 0 {
+		return c.Checker.Check(ctx)
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	result := make(chan ComponentHealth, 1)
+	go func() {
+		result <- c.Checker.Check(ctx)
+	}()
+	select {
+	case health := <-result:
+		return health
+	case <-ctx.Done():
+		return ComponentHealth{
+			Status:  StatusUnhealthy,
+			Message: fmt.Sprintf("Check timed out after %s", c.timeout),
+			Latency: c.timeout.String(),
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package health
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// defaultWatchInterval is how often Watch re-evaluates the checkers when
+// results are not cached
+const defaultWatchInterval = 5 * time.Second
+
+// startGRPC serves the grpc.health.v1 protocol on GRPCPort, over TLS if
+// tlsConfig is set
+func (s *Server) startGRPC(tlsConfig *tls.Config) error {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", s.GRPCPort))
+	if err != nil {
+		return fmt.Errorf("failed to listen for gRPC health checks: %w", err)
+	}
+
+	var options []grpc.ServerOption
+	if tlsConfig != nil {
+		options = append(options, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	s.grpcServer = grpc.NewServer(options...)
+	s.grpcDone = make(chan struct{})
+	healthpb.RegisterHealthServer(s.grpcServer, &grpcHealthServer{server: s, done: s.grpcDone})
+
+	go func() {
+		if err := s.grpcServer.Serve(listener); err != nil {
+			fmt.Printf("gRPC health check server error: %v\n", err)
+		}
+	}()
+
+	fmt.Printf("gRPC health check server started on port %d\n", s.GRPCPort)
+	return nil
+}
+
+// stopGRPC ends open Watch streams and stops the gRPC server, forcibly if ctx
+// expires first
+func (s *Server) stopGRPC(ctx context.Context) {
+	if s.grpcServer == nil {
+		return
+	}
+	close(s.grpcDone)
+
+	stopped := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+	}
+	s.grpcServer = nil
+}
+
+// GRPCHandler returns a grpc.health.v1 server reporting the same results as the
+// HTTP endpoints, for registering on an existing gRPC server
+func (s *Server) GRPCHandler() healthpb.HealthServer {
+	return &grpcHealthServer{server: s}
+}
+
+// grpcHealthServer implements grpc.health.v1. The empty service name reports
+// readiness, and a checker's name reports that component.
+type grpcHealthServer struct {
+	healthpb.UnimplementedHealthServer
+	server *Server
+	done   chan struct{}
+}
+
+// Check returns the serving status of the requested service
+func (g *grpcHealthServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	components, _ := g.server.results(ctx)
+	servingStatus := servingStatus(components, req.GetService())
+	if servingStatus == healthpb.HealthCheckResponse_SERVICE_UNKNOWN {
+		return nil, status.Errorf(codes.NotFound, "unknown service %q", req.GetService())
+	}
+	return &healthpb.HealthCheckResponse{Status: servingStatus}, nil
+}
+
+// Watch sends the serving status of the requested service, then again
+// whenever it changes
+func (g *grpcHealthServer) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	interval := g.server.CacheTTL
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := healthpb.HealthCheckResponse_ServingStatus(-1)
+	for {
+		components, _ := g.server.results(stream.Context())
+		if current := servingStatus(components, req.GetService()); current != last {
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: current}); err != nil {
+				return err
+			}
+			last = current
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		case <-g.done:
+			return status.Error(codes.Unavailable, "health check server is shutting down")
+		case <-ticker.C:
+		}
+	}
+}
+
+// servingStatus maps the checker results to the status of service: the empty
+// service is serving while the server is ready, and a component is serving
+// unless it is unhealthy
+func servingStatus(components map[string]ComponentHealth, service string) healthpb.HealthCheckResponse_ServingStatus {
+	if service == "" {
+		if ready(components) {
+			return healthpb.HealthCheckResponse_SERVING
+		}
+		return healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	health, ok := components[service]
+	if !ok {
+		return healthpb.HealthCheckResponse_SERVICE_UNKNOWN
+	}
+	if health.Status == StatusUnhealthy {
+		return healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	return healthpb.HealthCheckResponse_SERVING
+}
@@ -2,13 +2,16 @@ package health
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"sync"
 	"time"
 
+	"github.com/aswathylr-builds/temporal-order-processing/buildinfo"
 	"go.temporal.io/sdk/client"
+	"google.golang.org/grpc"
 )
 
 // Status represents the health status of a component
@@ -20,11 +23,30 @@ const (
 	StatusDegraded  Status = "degraded"
 )
 
+// Criticality is how a component's failure affects the service
+type Criticality string
+
+const (
+	// CriticalityCritical components make the service unhealthy and not
+	// ready when they fail
+	CriticalityCritical Criticality = "critical"
+	// CriticalityInformational components only degrade /health when they
+	// fail; the service stays ready
+	CriticalityInformational Criticality = "informational"
+)
+
 // ComponentHealth represents the health of a single component
 type ComponentHealth struct {
-	Status  Status `json:"status"`
-	Message string `json:"message,omitempty"`
-	Latency string `json:"latency,omitempty"`
+	Status      Status      `json:"status"`
+	Message     string      `json:"message,omitempty"`
+	Latency     string      `json:"latency,omitempty"`
+	Criticality Criticality `json:"criticality,omitempty"`
+	// Tags describe the component, as set by CheckBuilder.WithTags
+	Tags map[string]string `json:"tags,omitempty"`
+	// Endpoint is the address the checker probes, when it has one
+	Endpoint string `json:"endpoint,omitempty"`
+	// LastSuccess is when the component last checked healthy or degraded
+	LastSuccess *time.Time `json:"last_success,omitempty"`
 }
 
 // HealthResponse represents the overall health check response
@@ -33,6 +55,7 @@ type HealthResponse struct {
 	Version    string                      `json:"version"`
 	Timestamp  time.Time                   `json:"timestamp"`
 	Components map[string]ComponentHealth  `json:"components"`
+	Build      buildinfo.Info              `json:"build"`
 }
 
 // Checker interface for health checks
@@ -41,46 +64,156 @@ type Checker interface {
 	Name() string
 }
 
+// checkTimeout bounds one evaluation of every checker
+const checkTimeout = 5 * time.Second
+
 // Server manages health check endpoints
 type Server struct {
-	port     int
-	checkers []Checker
-	mu       sync.RWMutex
-	server   *http.Server
+	// CacheTTL, if set, makes the handlers serve results that a background
+	// loop refreshes every CacheTTL, so frequent probes do not load the
+	// dependencies; zero runs the checkers on every request
+	CacheTTL time.Duration
+	// GRPCPort, if set, also serves the grpc.health.v1 protocol on this port
+	GRPCPort int
+	// FailureThreshold is how many consecutive failures a component needs
+	// before it is reported unhealthy, so single blips only degrade it;
+	// RecoveryThreshold is how many consecutive successes it then needs to
+	// recover. Zero means one.
+	FailureThreshold  int
+	RecoveryThreshold int
+	// TLS, if its certificate is set, serves the HTTP and gRPC endpoints over
+	// TLS
+	TLS TLSConfig
+	// AuthTokens, if set, are the bearer tokens accepted on the endpoints
+	// other than /health/live and /health/ready
+	AuthTokens []string
+	// Build identifies the binary serving the endpoints; it defaults to
+	// buildinfo.Get
+	Build buildinfo.Info
+
+	port        int
+	checkers    []registeredChecker
+	workerStats *WorkerStats
+	mu          sync.RWMutex
+	server      *http.Server
+
+	cacheMu   sync.Mutex
+	cached    map[string]ComponentHealth
+	checkedAt time.Time
+	stop      chan struct{}
+
+	grpcServer *grpc.Server
+	grpcDone   chan struct{}
+
+	metrics *serverMetrics
+
+	hooks    []func(Transition)
+	statesMu sync.Mutex
+	states   map[string]*componentState
+
+	publishers  []registeredPublisher
+	publishStop chan struct{}
 }
 
 // NewServer creates a new health check server
 func NewServer(port int) *Server {
+	build := buildinfo.Get()
 	return &Server{
+		Build:    build,
 		port:     port,
-		checkers: make([]Checker, 0),
+		checkers: make([]registeredChecker, 0),
+		metrics:  newServerMetrics(time.Now(), build),
 	}
 }
 
-// RegisterChecker adds a new health checker
+// registeredChecker is a checker and its criticality
+type registeredChecker struct {
+	Checker
+	criticality Criticality
+}
+
+// RegisterChecker adds a new critical health checker
 func (s *Server) RegisterChecker(checker Checker) {
+	s.RegisterCheckerWithCriticality(checker, CriticalityCritical)
+}
+
+// RegisterCheckerWithCriticality adds a new health checker whose failure
+// affects the service as criticality says
+func (s *Server) RegisterCheckerWithCriticality(checker Checker, criticality Criticality) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.checkers = append(s.checkers, checker)
+	s.checkers = append(s.checkers, registeredChecker{Checker: checker, criticality: criticality})
 }
 
-// Start starts the health check HTTP server
-func (s *Server) Start() error {
+// RegisterWorkerStats reports worker utilization from stats as the "worker"
+// component and on /health/worker
+func (s *Server) RegisterWorkerStats(stats *WorkerStats) {
+	s.mu.Lock()
+	s.workerStats = stats
+	s.mu.Unlock()
+	s.RegisterChecker(NewWorkerChecker(stats))
+}
+
+// Handler returns the health check endpoints
+func (s *Server) Handler() http.Handler {
 	mux := http.NewServeMux()
-	mux.HandleFunc("/health", s.healthHandler)
+	mux.HandleFunc("/health", s.requireAuth(s.healthHandler))
 	mux.HandleFunc("/health/live", s.livenessHandler)
 	mux.HandleFunc("/health/ready", s.readinessHandler)
+	mux.HandleFunc("/health/worker", s.requireAuth(s.workerHandler))
+	mux.HandleFunc("/metrics", s.requireAuth(s.metricsHandler))
+	return mux
+}
 
+// Start starts the health check HTTP server
+func (s *Server) Start() error {
 	s.server = &http.Server{
 		Addr:         fmt.Sprintf(":%d", s.port),
-		Handler:      mux,
+		Handler:      s.Handler(),
 		ReadTimeout:  5 * time.Second,
 		WriteTimeout: 10 * time.Second,
 		IdleTimeout:  15 * time.Second,
 	}
 
+	var tlsConfig *tls.Config
+	if s.TLS.Enabled() {
+		var err error
+		if tlsConfig, err = s.TLS.build(); err != nil {
+			return err
+		}
+		s.server.TLSConfig = tlsConfig
+	}
+
+	if s.GRPCPort > 0 {
+		if err := s.startGRPC(tlsConfig); err != nil {
+			return err
+		}
+	}
+
+	if s.CacheTTL > 0 {
+		s.stop = make(chan struct{})
+		go s.refreshLoop(s.stop)
+	}
+
+	s.mu.RLock()
+	publishers := s.publishers
+	s.mu.RUnlock()
+	if len(publishers) > 0 {
+		s.publishStop = make(chan struct{})
+		for _, publisher := range publishers {
+			go s.publishLoop(publisher, s.publishStop)
+		}
+	}
+
 	go func() {
-		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		var err error
+		if tlsConfig != nil {
+			// The certificate is already loaded into TLSConfig
+			err = s.server.ListenAndServeTLS("", "")
+		} else {
+			err = s.server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			fmt.Printf("Health check server error: %v\n", err)
 		}
 	}()
@@ -91,42 +224,90 @@ func (s *Server) Start() error {
 
 // Shutdown gracefully shuts down the health check server
 func (s *Server) Shutdown(ctx context.Context) error {
+	if s.stop != nil {
+		close(s.stop)
+		s.stop = nil
+	}
+	if s.publishStop != nil {
+		close(s.publishStop)
+		s.publishStop = nil
+	}
+	s.stopGRPC(ctx)
 	if s.server != nil {
 		return s.server.Shutdown(ctx)
 	}
 	return nil
 }
 
-// healthHandler returns detailed health status
-func (s *Server) healthHandler(w http.ResponseWriter, r *http.Request) {
-	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+// refreshLoop evaluates the checkers every CacheTTL until stop is closed
+func (s *Server) refreshLoop(stop chan struct{}) {
+	ticker := time.NewTicker(s.CacheTTL)
+	defer ticker.Stop()
+	for {
+		s.refresh(context.Background())
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refresh evaluates the checkers and caches their results
+func (s *Server) refresh(ctx context.Context) (map[string]ComponentHealth, time.Time) {
+	components, checkedAt := s.evaluate(ctx)
+	s.cacheMu.Lock()
+	s.cached, s.checkedAt = components, checkedAt
+	s.cacheMu.Unlock()
+	return components, checkedAt
+}
+
+// results returns the cached results when caching is enabled and they have
+// been evaluated, otherwise evaluates the checkers now
+func (s *Server) results(ctx context.Context) (map[string]ComponentHealth, time.Time) {
+	if s.CacheTTL <= 0 {
+		return s.evaluate(ctx)
+	}
+	s.cacheMu.Lock()
+	components, checkedAt := s.cached, s.checkedAt
+	s.cacheMu.Unlock()
+	if components == nil {
+		return s.refresh(ctx)
+	}
+	return components, checkedAt
+}
+
+// evaluate runs every checker
+func (s *Server) evaluate(ctx context.Context) (map[string]ComponentHealth, time.Time) {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
 	defer cancel()
 
 	s.mu.RLock()
 	checkers := s.checkers
 	s.mu.RUnlock()
 
-	components := make(map[string]ComponentHealth)
-	overallStatus := StatusHealthy
-
+	checkedAt := time.Now()
+	components := make(map[string]ComponentHealth, len(checkers))
+	var transitions []Transition
 	for _, checker := range checkers {
+		start := time.Now()
 		health := checker.Check(ctx)
-		components[checker.Name()] = health
-
-		// Determine overall status
-		if health.Status == StatusUnhealthy {
-			overallStatus = StatusUnhealthy
-		} else if health.Status == StatusDegraded && overallStatus == StatusHealthy {
-			overallStatus = StatusDegraded
+		health.Criticality = checker.criticality
+		health, transition := s.debounce(checker.Name(), health, checkedAt)
+		if transition != nil {
+			transitions = append(transitions, *transition)
 		}
+		components[checker.Name()] = health
+		s.metrics.observe(checker.Name(), health, time.Since(start))
 	}
+	s.notify(transitions)
+	return components, checkedAt
+}
 
-	response := HealthResponse{
-		Status:     overallStatus,
-		Version:    "1.0.0",
-		Timestamp:  time.Now(),
-		Components: components,
-	}
+// healthHandler returns detailed health status
+func (s *Server) healthHandler(w http.ResponseWriter, r *http.Request) {
+	response := s.report(r.Context())
+	overallStatus := response.Status
 
 	statusCode := http.StatusOK
 	if overallStatus == StatusUnhealthy {
@@ -140,6 +321,33 @@ func (s *Server) healthHandler(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// report returns the latest results with the overall status
+func (s *Server) report(ctx context.Context) HealthResponse {
+	components, checkedAt := s.results(ctx)
+	overallStatus := StatusHealthy
+
+	for _, health := range components {
+		// Determine overall status; informational components only degrade it
+		if health.Status == StatusUnhealthy && health.Criticality == CriticalityInformational {
+			if overallStatus == StatusHealthy {
+				overallStatus = StatusDegraded
+			}
+		} else if health.Status == StatusUnhealthy {
+			overallStatus = StatusUnhealthy
+		} else if health.Status == StatusDegraded && overallStatus == StatusHealthy {
+			overallStatus = StatusDegraded
+		}
+	}
+
+	return HealthResponse{
+		Status:     overallStatus,
+		Version:    s.Build.Version,
+		Timestamp:  checkedAt,
+		Components: components,
+		Build:      s.Build,
+	}
+}
+
 // livenessHandler returns basic liveness status (for Kubernetes)
 func (s *Server) livenessHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
@@ -149,27 +357,27 @@ func (s *Server) livenessHandler(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// readinessHandler checks if the service is ready to handle requests
-func (s *Server) readinessHandler(w http.ResponseWriter, r *http.Request) {
-	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
-	defer cancel()
-
+// workerHandler returns worker slot, poller, and sticky cache utilization
+func (s *Server) workerHandler(w http.ResponseWriter, r *http.Request) {
 	s.mu.RLock()
-	checkers := s.checkers
+	stats := s.workerStats
 	s.mu.RUnlock()
 
-	ready := true
-	for _, checker := range checkers {
-		health := checker.Check(ctx)
-		if health.Status == StatusUnhealthy {
-			ready = false
-			break
-		}
+	if stats == nil {
+		http.Error(w, "worker stats are not registered", http.StatusNotFound)
+		return
 	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats.Report())
+}
+
+// readinessHandler checks if the service is ready to handle requests
+func (s *Server) readinessHandler(w http.ResponseWriter, r *http.Request) {
+	components, _ := s.results(r.Context())
 
 	statusCode := http.StatusOK
 	status := "ready"
-	if !ready {
+	if !ready(components) {
 		statusCode = http.StatusServiceUnavailable
 		status = "not_ready"
 	}
@@ -181,9 +389,45 @@ func (s *Server) readinessHandler(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// ready reports whether no critical component is unhealthy
+func ready(components map[string]ComponentHealth) bool {
+	for _, health := range components {
+		if health.Status == StatusUnhealthy && health.Criticality != CriticalityInformational {
+			return false
+		}
+	}
+	return true
+}
+
+// LatencyThresholds marks a component that responds successfully but slowly
+// as degraded or unhealthy. Zero disables a threshold.
+type LatencyThresholds struct {
+	// Warn is the latency at which a healthy component is degraded
+	Warn time.Duration
+	// Critical is the latency at which a component is unhealthy
+	Critical time.Duration
+}
+
+// apply downgrades health if latency crosses a threshold
+func (l LatencyThresholds) apply(health ComponentHealth, latency time.Duration) ComponentHealth {
+	switch {
+	case l.Critical > 0 && latency >= l.Critical && health.Status != StatusUnhealthy:
+		health.Status = StatusUnhealthy
+		health.Message += fmt.Sprintf(" (latency over critical threshold %s)", l.Critical)
+	case l.Warn > 0 && latency >= l.Warn && health.Status == StatusHealthy:
+		health.Status = StatusDegraded
+		health.Message += fmt.Sprintf(" (latency over warn threshold %s)", l.Warn)
+	}
+	return health
+}
+
 // TemporalChecker checks Temporal server connectivity
 type TemporalChecker struct {
 	client client.Client
+	// Thresholds degrade the component when the server responds slowly
+	Thresholds LatencyThresholds
+	// Endpoint is the server address reported with the results
+	Endpoint string
 }
 
 // NewTemporalChecker creates a new Temporal health checker
@@ -206,17 +450,19 @@ func (t *TemporalChecker) Check(ctx context.Context) ComponentHealth {
 
 	if err != nil {
 		return ComponentHealth{
-			Status:  StatusUnhealthy,
-			Message: fmt.Sprintf("Temporal connection failed: %v", err),
-			Latency: latency.String(),
+			Status:   StatusUnhealthy,
+			Message:  fmt.Sprintf("Temporal connection failed: %v", err),
+			Latency:  latency.String(),
+			Endpoint: t.Endpoint,
 		}
 	}
 
-	return ComponentHealth{
-		Status:  StatusHealthy,
-		Message: "Connected to Temporal server",
-		Latency: latency.String(),
-	}
+	return t.Thresholds.apply(ComponentHealth{
+		Status:   StatusHealthy,
+		Message:  "Connected to Temporal server",
+		Latency:  latency.String(),
+		Endpoint: t.Endpoint,
+	}, latency)
 }
 
 // HTTPChecker checks HTTP endpoint availability
@@ -224,6 +470,8 @@ type HTTPChecker struct {
 	name   string
 	url    string
 	client *http.Client
+	// Thresholds degrade the component when the endpoint responds slowly
+	Thresholds LatencyThresholds
 }
 
 // NewHTTPChecker creates a new HTTP health checker
@@ -244,6 +492,13 @@ func (h *HTTPChecker) Name() string {
 
 // Check performs the health check
 func (h *HTTPChecker) Check(ctx context.Context) ComponentHealth {
+	health := h.check(ctx)
+	health.Endpoint = h.url
+	return health
+}
+
+// check requests the URL and maps the response to a status
+func (h *HTTPChecker) check(ctx context.Context) ComponentHealth {
 	start := time.Now()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", h.url, nil)
@@ -267,16 +522,16 @@ func (h *HTTPChecker) Check(ctx context.Context) ComponentHealth {
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		return ComponentHealth{
+		return h.Thresholds.apply(ComponentHealth{
 			Status:  StatusHealthy,
 			Message: fmt.Sprintf("HTTP %d", resp.StatusCode),
 			Latency: latency.String(),
-		}
+		}, latency)
 	}
 
-	return ComponentHealth{
+	return h.Thresholds.apply(ComponentHealth{
 		Status:  StatusDegraded,
 		Message: fmt.Sprintf("HTTP %d", resp.StatusCode),
 		Latency: latency.String(),
-	}
+	}, latency)
 }
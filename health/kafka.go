@@ -0,0 +1,95 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaChecker checks a Kafka cluster by fetching the metadata of a topic,
+// which needs a reachable broker and, for the topic, elected partition
+// leaders
+type KafkaChecker struct {
+	client  *kafka.Client
+	brokers []string
+	topic   string
+	// Thresholds degrade the component when the cluster responds slowly
+	Thresholds LatencyThresholds
+}
+
+// NewKafkaChecker creates a health checker for the cluster at brokers. With
+// a topic, its partitions are checked too.
+func NewKafkaChecker(brokers []string, topic string) *KafkaChecker {
+	return &KafkaChecker{
+		client:  &kafka.Client{Addr: kafka.TCP(brokers...)},
+		brokers: brokers,
+		topic:   topic,
+	}
+}
+
+// Name returns the checker name
+func (k *KafkaChecker) Name() string {
+	return "kafka"
+}
+
+// Check performs the health check
+func (k *KafkaChecker) Check(ctx context.Context) ComponentHealth {
+	health := k.check(ctx)
+	health.Endpoint = strings.Join(k.brokers, ",")
+	return health
+}
+
+// check fetches the metadata and maps it to a status
+func (k *KafkaChecker) check(ctx context.Context) ComponentHealth {
+	start := time.Now()
+
+	request := &kafka.MetadataRequest{}
+	if k.topic != "" {
+		request.Topics = []string{k.topic}
+	}
+	metadata, err := k.client.Metadata(ctx, request)
+	latency := time.Since(start)
+
+	if err != nil {
+		return ComponentHealth{
+			Status:  StatusUnhealthy,
+			Message: fmt.Sprintf("Metadata request failed: %v", err),
+			Latency: latency.String(),
+		}
+	}
+
+	message := fmt.Sprintf("%d brokers", len(metadata.Brokers))
+	status := StatusHealthy
+	for _, topic := range metadata.Topics {
+		if topic.Name != k.topic {
+			continue
+		}
+		if topic.Error != nil {
+			return ComponentHealth{
+				Status:  StatusUnhealthy,
+				Message: fmt.Sprintf("Topic %s: %v", k.topic, topic.Error),
+				Latency: latency.String(),
+			}
+		}
+		leaderless := 0
+		for _, partition := range topic.Partitions {
+			if partition.Error != nil || partition.Leader.Host == "" {
+				leaderless++
+			}
+		}
+		message += fmt.Sprintf(", topic %s has %d partitions", k.topic, len(topic.Partitions))
+		if leaderless > 0 {
+			status = StatusDegraded
+			message += fmt.Sprintf(", %d without a leader", leaderless)
+		}
+	}
+
+	return k.Thresholds.apply(ComponentHealth{
+		Status:  status,
+		Message: message,
+		Latency: latency.String(),
+	}, latency)
+}
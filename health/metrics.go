@@ -0,0 +1,81 @@
+package health
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/buildinfo"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Health metrics published on the health server's /metrics
+const (
+	// ComponentStatusMetric is 1 for each component's current status and 0
+	// for its other statuses, tagged with component, criticality, and status
+	ComponentStatusMetric = "health_component_status"
+	// CheckDurationMetric is how long each checker takes, tagged with component
+	CheckDurationMetric = "health_check_duration_seconds"
+	// UptimeMetric is how long the health server has been running
+	UptimeMetric = "health_uptime_seconds"
+	// BuildInfoMetric is always 1, tagged with the version and git SHA of
+	// the running binary
+	BuildInfoMetric = "health_build_info"
+)
+
+// serverMetrics records checker results into the health server's own
+// Prometheus registry
+type serverMetrics struct {
+	registry *prom.Registry
+	status   *prom.GaugeVec
+	duration *prom.HistogramVec
+}
+
+func newServerMetrics(started time.Time, build buildinfo.Info) *serverMetrics {
+	m := &serverMetrics{
+		registry: prom.NewRegistry(),
+		status: prom.NewGaugeVec(prom.GaugeOpts{
+			Name: ComponentStatusMetric,
+			Help: "Current status of each health checker's component, 1 for the status it is in.",
+		}, []string{"component", "criticality", "status"}),
+		duration: prom.NewHistogramVec(prom.HistogramOpts{
+			Name:    CheckDurationMetric,
+			Help:    "How long each health checker takes.",
+			Buckets: []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+		}, []string{"component"}),
+	}
+	m.registry.MustRegister(m.status, m.duration, prom.NewGaugeFunc(prom.GaugeOpts{
+		Name: UptimeMetric,
+		Help: "How long the health server has been running.",
+	}, func() float64 {
+		return time.Since(started).Seconds()
+	}), prom.NewGaugeFunc(prom.GaugeOpts{
+		Name:        BuildInfoMetric,
+		Help:        "The build of the running binary.",
+		ConstLabels: prom.Labels{"version": build.Version, "git_sha": build.GitSHA, "go_version": build.GoVersion},
+	}, func() float64 {
+		return 1
+	}))
+	return m
+}
+
+// observe records one checker's result and how long it took
+func (m *serverMetrics) observe(name string, health ComponentHealth, duration time.Duration) {
+	m.duration.WithLabelValues(name).Observe(duration.Seconds())
+	for _, status := range []Status{StatusHealthy, StatusDegraded, StatusUnhealthy} {
+		value := 0.0
+		if health.Status == status {
+			value = 1
+		}
+		m.status.WithLabelValues(name, string(health.Criticality), string(status)).Set(value)
+	}
+}
+
+// metricsHandler serves the health metrics in Prometheus format. Without
+// cached results, each scrape runs the checkers, as other endpoints do.
+func (s *Server) metricsHandler(w http.ResponseWriter, r *http.Request) {
+	if s.CacheTTL <= 0 {
+		s.results(r.Context())
+	}
+	promhttp.HandlerFor(s.metrics.registry, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+}
@@ -0,0 +1,169 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+)
+
+// StatsD line formats
+const (
+	// FormatDogStatsD tags metrics with `|#key:value`, as the Datadog agent
+	// and the CloudWatch agent's StatsD listener accept
+	FormatDogStatsD = "dogstatsd"
+	// FormatStatsD puts the component in the metric name, for servers that
+	// do not support tags
+	FormatStatsD = "statsd"
+)
+
+// Publisher pushes health results to an external monitoring system, for
+// workers that cannot be probed from outside, such as behind NAT
+type Publisher interface {
+	Publish(ctx context.Context, report HealthResponse) error
+}
+
+// statusValue maps a status to a gauge value, so alerts can fire below 1
+func statusValue(status Status) float64 {
+	switch status {
+	case StatusHealthy:
+		return 1
+	case StatusDegraded:
+		return 0.5
+	default:
+		return 0
+	}
+}
+
+// StatsDOptions configures a StatsDPublisher
+type StatsDOptions struct {
+	// Prefix starts every metric name, such as "order_worker"
+	Prefix string
+	// Format is FormatDogStatsD or FormatStatsD; empty means FormatDogStatsD
+	Format string
+	// Tags are added to every metric in FormatDogStatsD, as "key:value"
+	Tags []string
+}
+
+// StatsDPublisher pushes health results over UDP in the StatsD line
+// protocol: a heartbeat counter, the overall status, and each component's
+// status, where 1 is healthy, 0.5 degraded, and 0 unhealthy
+type StatsDPublisher struct {
+	conn    net.Conn
+	options StatsDOptions
+}
+
+// NewStatsDPublisher creates a publisher sending to the StatsD agent at addr,
+// such as localhost:8125
+func NewStatsDPublisher(addr string, options StatsDOptions) (*StatsDPublisher, error) {
+	switch options.Format {
+	case "":
+		options.Format = FormatDogStatsD
+	case FormatDogStatsD, FormatStatsD:
+	default:
+		return nil, fmt.Errorf("unknown StatsD format %q", options.Format)
+	}
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to StatsD agent: %w", err)
+	}
+	return &StatsDPublisher{conn: conn, options: options}, nil
+}
+
+// Publish sends one datagram per metric
+func (p *StatsDPublisher) Publish(ctx context.Context, report HealthResponse) error {
+	lines := []string{
+		p.line("heartbeat", "1|c", nil),
+		p.line("status", fmt.Sprintf("%g|g", statusValue(report.Status)), nil),
+	}
+
+	names := make([]string, 0, len(report.Components))
+	for name := range report.Components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := fmt.Sprintf("%g|g", statusValue(report.Components[name].Status))
+		if p.options.Format == FormatStatsD {
+			lines = append(lines, p.line("component."+sanitizeStatsD(name)+".status", value, nil))
+		} else {
+			lines = append(lines, p.line("component.status", value, []string{"component:" + sanitizeStatsD(name)}))
+		}
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		p.conn.SetWriteDeadline(deadline)
+	}
+	for _, line := range lines {
+		if _, err := p.conn.Write([]byte(line)); err != nil {
+			return fmt.Errorf("failed to send health metrics: %w", err)
+		}
+	}
+	return nil
+}
+
+// Close closes the connection to the agent
+func (p *StatsDPublisher) Close() error {
+	return p.conn.Close()
+}
+
+// line formats one metric with the prefix and, in FormatDogStatsD, tags
+func (p *StatsDPublisher) line(name, value string, tags []string) string {
+	if p.options.Prefix != "" {
+		name = p.options.Prefix + "." + name
+	}
+	line := name + ":" + value
+	if p.options.Format == FormatDogStatsD {
+		tags = append(append([]string(nil), p.options.Tags...), tags...)
+		if len(tags) > 0 {
+			line += "|#" + strings.Join(tags, ",")
+		}
+	}
+	return line
+}
+
+// sanitizeStatsD replaces the characters StatsD reserves
+func sanitizeStatsD(name string) string {
+	return strings.NewReplacer(":", "_", "|", "_", "@", "_", ",", "_", "#", "_").Replace(name)
+}
+
+// DefaultPublishInterval is how often publishers are pushed to when no
+// interval is given
+const DefaultPublishInterval = 30 * time.Second
+
+// RegisterPublisher pushes the latest results to publisher every interval
+// once the server starts
+func (s *Server) RegisterPublisher(publisher Publisher, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultPublishInterval
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.publishers = append(s.publishers, registeredPublisher{publisher: publisher, interval: interval})
+}
+
+// registeredPublisher is a publisher and how often it is pushed to
+type registeredPublisher struct {
+	publisher Publisher
+	interval  time.Duration
+}
+
+// publishLoop pushes to publisher every interval until stop is closed
+func (s *Server) publishLoop(publisher registeredPublisher, stop chan struct{}) {
+	ticker := time.NewTicker(publisher.interval)
+	defer ticker.Stop()
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+		if err := publisher.publisher.Publish(ctx, s.report(ctx)); err != nil {
+			fmt.Printf("Health publisher error: %v\n", err)
+		}
+		cancel()
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// DefaultSQLQuery is the query SQLChecker runs after pinging the database
+const DefaultSQLQuery = "SELECT 1"
+
+// SQLChecker checks a SQL database: it pings it, runs a simple query, and
+// reports the connection pool's stats
+type SQLChecker struct {
+	name string
+	db   *sql.DB
+	// Query is run to confirm the database answers queries, not just
+	// connections
+	Query string
+	// Thresholds degrade the component when the database responds slowly
+	Thresholds LatencyThresholds
+}
+
+// NewSQLChecker creates a health checker named name for db
+func NewSQLChecker(name string, db *sql.DB) *SQLChecker {
+	return &SQLChecker{name: name, db: db, Query: DefaultSQLQuery}
+}
+
+// Name returns the checker name
+func (c *SQLChecker) Name() string {
+	return c.name
+}
+
+// Check performs the health check
+func (c *SQLChecker) Check(ctx context.Context) ComponentHealth {
+	start := time.Now()
+
+	if err := c.db.PingContext(ctx); err != nil {
+		return ComponentHealth{
+			Status:  StatusUnhealthy,
+			Message: fmt.Sprintf("Ping failed: %v", err),
+			Latency: time.Since(start).String(),
+		}
+	}
+	var result any
+	if err := c.db.QueryRowContext(ctx, c.Query).Scan(&result); err != nil {
+		return ComponentHealth{
+			Status:  StatusUnhealthy,
+			Message: fmt.Sprintf("Query failed: %v", err),
+			Latency: time.Since(start).String(),
+		}
+	}
+	latency := time.Since(start)
+
+	stats := c.db.Stats()
+	message := fmt.Sprintf("Pool: %d open, %d in use, %d idle, %d waits", stats.OpenConnections, stats.InUse, stats.Idle, stats.WaitCount)
+	status := StatusHealthy
+	if stats.MaxOpenConnections > 0 && stats.InUse >= stats.MaxOpenConnections {
+		status = StatusDegraded
+		message = "Connection pool exhausted. " + message
+	}
+
+	return c.Thresholds.apply(ComponentHealth{
+		Status:  status,
+		Message: message,
+		Latency: latency.String(),
+	}, latency)
+}
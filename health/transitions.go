@@ -0,0 +1,96 @@
+package health
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Transition is a change in the status a component is reported with
+type Transition struct {
+	Component string
+	From      Status
+	To        Status
+	// Health is the result that caused the transition
+	Health ComponentHealth
+	At     time.Time
+}
+
+// componentState tracks a component's consecutive results
+type componentState struct {
+	reported    Status
+	failures    int
+	successes   int
+	lastSuccess time.Time
+}
+
+// OnTransition calls hook whenever a component's reported status changes,
+// after FailureThreshold or RecoveryThreshold consecutive results. Hooks run
+// on the goroutine evaluating the checkers, so they should not block.
+func (s *Server) OnTransition(hook func(Transition)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hooks = append(s.hooks, hook)
+}
+
+// debounce returns the status to report for a component's latest result. A
+// component becomes unhealthy only after FailureThreshold consecutive
+// failures, reporting degraded until then, and recovers only after
+// RecoveryThreshold consecutive successes. Components start out healthy.
+func (s *Server) debounce(name string, health ComponentHealth, at time.Time) (ComponentHealth, *Transition) {
+	s.statesMu.Lock()
+	defer s.statesMu.Unlock()
+	if s.states == nil {
+		s.states = make(map[string]*componentState)
+	}
+	state, ok := s.states[name]
+	if !ok {
+		state = &componentState{reported: StatusHealthy}
+		s.states[name] = state
+	}
+
+	result := health
+	if health.Status == StatusUnhealthy {
+		state.failures++
+		state.successes = 0
+		if state.reported != StatusUnhealthy && state.failures < max(s.FailureThreshold, 1) {
+			result.Status = StatusDegraded
+			result.Message = strings.TrimSpace(fmt.Sprintf("%s (failure %d of %d)", result.Message, state.failures, s.FailureThreshold))
+		}
+	} else {
+		state.successes++
+		state.failures = 0
+		state.lastSuccess = at
+		if state.reported == StatusUnhealthy && state.successes < max(s.RecoveryThreshold, 1) {
+			result.Status = StatusUnhealthy
+			result.Message = strings.TrimSpace(fmt.Sprintf("%s (recovering, success %d of %d)", result.Message, state.successes, s.RecoveryThreshold))
+		}
+	}
+
+	if !state.lastSuccess.IsZero() {
+		lastSuccess := state.lastSuccess
+		result.LastSuccess = &lastSuccess
+	}
+
+	if result.Status == state.reported {
+		return result, nil
+	}
+	transition := &Transition{Component: name, From: state.reported, To: result.Status, Health: result, At: at}
+	state.reported = result.Status
+	return result, transition
+}
+
+// notify calls the transition hooks
+func (s *Server) notify(transitions []Transition) {
+	if len(transitions) == 0 {
+		return
+	}
+	s.mu.RLock()
+	hooks := s.hooks
+	s.mu.RUnlock()
+	for _, transition := range transitions {
+		for _, hook := range hooks {
+			hook(transition)
+		}
+	}
+}
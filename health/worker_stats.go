@@ -0,0 +1,256 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.temporal.io/sdk/client"
+)
+
+// SDK metric and tag names WorkerStats records
+const (
+	metricSlotsAvailable  = "temporal_worker_task_slots_available"
+	metricSlotsUsed       = "temporal_worker_task_slots_used"
+	metricPollers         = "temporal_num_pollers"
+	metricStickyCacheHit  = "temporal_sticky_cache_hit"
+	metricStickyCacheMiss = "temporal_sticky_cache_miss"
+	metricStickyCacheSize = "temporal_sticky_cache_size"
+	tagTaskQueue          = "task_queue"
+	tagWorkerType         = "worker_type"
+	tagPollerType         = "poller_type"
+)
+
+// SlotUsage is the task slot usage of one worker type on a task queue
+type SlotUsage struct {
+	TaskQueue  string `json:"task_queue"`
+	WorkerType string `json:"worker_type"`
+	Used       int    `json:"used"`
+	Available  int    `json:"available"`
+}
+
+// PollerCount is the number of running pollers of one type on a task queue
+type PollerCount struct {
+	TaskQueue  string `json:"task_queue"`
+	PollerType string `json:"poller_type"`
+	Count      int    `json:"count"`
+}
+
+// StickyCacheStats describes the sticky workflow cache; HitRate is the share
+// of workflow tasks that found their workflow cached
+type StickyCacheStats struct {
+	Hits    int64   `json:"hits"`
+	Misses  int64   `json:"misses"`
+	HitRate float64 `json:"hit_rate"`
+	Size    int     `json:"size"`
+}
+
+// WorkerReport is the worker utilization served on /health/worker
+type WorkerReport struct {
+	Slots       []SlotUsage      `json:"slots"`
+	Pollers     []PollerCount    `json:"pollers"`
+	StickyCache StickyCacheStats `json:"sticky_cache"`
+	Timestamp   time.Time        `json:"timestamp"`
+}
+
+// WorkerStats is a client.MetricsHandler that passes every metric to the
+// wrapped handler and keeps the latest slot, poller, and sticky cache values
+// the SDK reports, so health checks can read them without scraping
+// Prometheus. Set it as client.Options.MetricsHandler.
+type WorkerStats struct {
+	next  client.MetricsHandler
+	tags  map[string]string
+	store *workerStatsStore
+}
+
+type workerStatsStore struct {
+	mu          sync.Mutex
+	slots       map[[2]string]*SlotUsage
+	pollers     map[[2]string]*PollerCount
+	stickyHits  int64
+	stickyMiss  int64
+	stickyCache int
+}
+
+// NewWorkerStats creates a WorkerStats wrapping next
+func NewWorkerStats(next client.MetricsHandler) *WorkerStats {
+	if next == nil {
+		next = client.MetricsNopHandler
+	}
+	return &WorkerStats{
+		next: next,
+		store: &workerStatsStore{
+			slots:   make(map[[2]string]*SlotUsage),
+			pollers: make(map[[2]string]*PollerCount),
+		},
+	}
+}
+
+// WithTags implements client.MetricsHandler
+func (w *WorkerStats) WithTags(tags map[string]string) client.MetricsHandler {
+	merged := make(map[string]string, len(w.tags)+len(tags))
+	for k, v := range w.tags {
+		merged[k] = v
+	}
+	for k, v := range tags {
+		merged[k] = v
+	}
+	return &WorkerStats{next: w.next.WithTags(tags), tags: merged, store: w.store}
+}
+
+// Counter implements client.MetricsHandler
+func (w *WorkerStats) Counter(name string) client.MetricsCounter {
+	counter := w.next.Counter(name)
+	var record func(int64)
+	switch name {
+	case metricStickyCacheHit:
+		record = func(d int64) { w.store.update(func(s *workerStatsStore) { s.stickyHits += d }) }
+	case metricStickyCacheMiss:
+		record = func(d int64) { w.store.update(func(s *workerStatsStore) { s.stickyMiss += d }) }
+	default:
+		return counter
+	}
+	return counterFunc(func(d int64) {
+		counter.Inc(d)
+		record(d)
+	})
+}
+
+// Gauge implements client.MetricsHandler
+func (w *WorkerStats) Gauge(name string) client.MetricsGauge {
+	gauge := w.next.Gauge(name)
+	var record func(*workerStatsStore, int)
+	switch name {
+	case metricSlotsAvailable:
+		record = func(s *workerStatsStore, v int) { s.slot(w.tags).Available = v }
+	case metricSlotsUsed:
+		record = func(s *workerStatsStore, v int) { s.slot(w.tags).Used = v }
+	case metricPollers:
+		record = func(s *workerStatsStore, v int) { s.poller(w.tags).Count = v }
+	case metricStickyCacheSize:
+		record = func(s *workerStatsStore, v int) { s.stickyCache = v }
+	default:
+		return gauge
+	}
+	return gaugeFunc(func(v float64) {
+		gauge.Update(v)
+		w.store.update(func(s *workerStatsStore) { record(s, int(v)) })
+	})
+}
+
+// Timer implements client.MetricsHandler
+func (w *WorkerStats) Timer(name string) client.MetricsTimer {
+	return w.next.Timer(name)
+}
+
+type counterFunc func(int64)
+
+func (f counterFunc) Inc(d int64) { f(d) }
+
+type gaugeFunc func(float64)
+
+func (f gaugeFunc) Update(v float64) { f(v) }
+
+func (s *workerStatsStore) update(fn func(*workerStatsStore)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(s)
+}
+
+func (s *workerStatsStore) slot(tags map[string]string) *SlotUsage {
+	key := [2]string{tags[tagTaskQueue], tags[tagWorkerType]}
+	if s.slots[key] == nil {
+		s.slots[key] = &SlotUsage{TaskQueue: key[0], WorkerType: key[1]}
+	}
+	return s.slots[key]
+}
+
+func (s *workerStatsStore) poller(tags map[string]string) *PollerCount {
+	key := [2]string{tags[tagTaskQueue], tags[tagPollerType]}
+	if s.pollers[key] == nil {
+		s.pollers[key] = &PollerCount{TaskQueue: key[0], PollerType: key[1]}
+	}
+	return s.pollers[key]
+}
+
+// Report returns the latest values, sorted by task queue and type
+func (w *WorkerStats) Report() WorkerReport {
+	s := w.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	report := WorkerReport{
+		Slots:   make([]SlotUsage, 0, len(s.slots)),
+		Pollers: make([]PollerCount, 0, len(s.pollers)),
+		StickyCache: StickyCacheStats{
+			Hits:   s.stickyHits,
+			Misses: s.stickyMiss,
+			Size:   s.stickyCache,
+		},
+		Timestamp: time.Now(),
+	}
+	if lookups := s.stickyHits + s.stickyMiss; lookups > 0 {
+		report.StickyCache.HitRate = float64(s.stickyHits) / float64(lookups)
+	}
+	for _, slot := range s.slots {
+		report.Slots = append(report.Slots, *slot)
+	}
+	for _, poller := range s.pollers {
+		report.Pollers = append(report.Pollers, *poller)
+	}
+	sort.Slice(report.Slots, func(i, j int) bool {
+		a, b := report.Slots[i], report.Slots[j]
+		return a.TaskQueue < b.TaskQueue || a.TaskQueue == b.TaskQueue && a.WorkerType < b.WorkerType
+	})
+	sort.Slice(report.Pollers, func(i, j int) bool {
+		a, b := report.Pollers[i], report.Pollers[j]
+		return a.TaskQueue < b.TaskQueue || a.TaskQueue == b.TaskQueue && a.PollerType < b.PollerType
+	})
+	return report
+}
+
+// WorkerChecker reports worker utilization as a health component. It is
+// degraded while a worker has no pollers or no free task slots, since tasks
+// then wait on the server however healthy Temporal is.
+type WorkerChecker struct {
+	stats *WorkerStats
+}
+
+// NewWorkerChecker creates a checker reading stats
+func NewWorkerChecker(stats *WorkerStats) *WorkerChecker {
+	return &WorkerChecker{stats: stats}
+}
+
+// Name returns the checker name
+func (c *WorkerChecker) Name() string {
+	return "worker"
+}
+
+// Check performs the health check
+func (c *WorkerChecker) Check(ctx context.Context) ComponentHealth {
+	report := c.stats.Report()
+	if len(report.Pollers) == 0 {
+		return ComponentHealth{Status: StatusDegraded, Message: "No pollers reported yet"}
+	}
+
+	var problems, usage []string
+	for _, poller := range report.Pollers {
+		if poller.Count == 0 {
+			problems = append(problems, fmt.Sprintf("no %s pollers on %s", poller.PollerType, poller.TaskQueue))
+		}
+	}
+	for _, slot := range report.Slots {
+		usage = append(usage, fmt.Sprintf("%s %s %d/%d", slot.TaskQueue, slot.WorkerType, slot.Used, slot.Used+slot.Available))
+		if slot.Available == 0 && slot.Used > 0 {
+			problems = append(problems, fmt.Sprintf("%s slots on %s exhausted", slot.WorkerType, slot.TaskQueue))
+		}
+	}
+	message := fmt.Sprintf("Slots used: %s; sticky cache hit rate %.0f%%", strings.Join(usage, ", "), report.StickyCache.HitRate*100)
+	if len(problems) > 0 {
+		return ComponentHealth{Status: StatusDegraded, Message: strings.Join(problems, "; ") + ". " + message}
+	}
+	return ComponentHealth{Status: StatusHealthy, Message: message}
+}
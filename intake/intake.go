@@ -0,0 +1,114 @@
+// Package intake turns orders arriving from outside systems, such as
+// storefront webhooks and message queues, into order workflows.
+//
+// Every intake starts an order under models.OrderWorkflowID, so a message
+// delivered twice finds the workflow its first delivery started rather than
+// starting a second one.
+package intake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/authz"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/client"
+)
+
+// Submission is the outcome of submitting an order
+type Submission struct {
+	OrderID    string `json:"order_id"`
+	WorkflowID string `json:"workflow_id"`
+	RunID      string `json:"run_id"`
+	// Duplicate is set when a workflow already existed for the order, running
+	// or closed, and no new one was started
+	Duplicate bool `json:"duplicate,omitempty"`
+}
+
+// ErrOrderNotFound is returned when cancelling an order that has no running
+// workflow, either because it was never started or because it has closed
+var ErrOrderNotFound = errors.New("order workflow not found")
+
+// OrderSubmitter starts order workflows; Submitter implements it against a
+// Temporal client
+type OrderSubmitter interface {
+	StartOrder(ctx context.Context, order models.Order, memo models.OrderMemo) (Submission, error)
+}
+
+// OrderCanceller cancels order workflows; Submitter implements it against a
+// Temporal client
+type OrderCanceller interface {
+	CancelOrder(ctx context.Context, orderID string) error
+}
+
+// Submitter starts and cancels order workflows on a task queue
+type Submitter struct {
+	client    client.Client
+	taskQueue string
+	signer    *authz.Signer
+	subject   string
+}
+
+// NewSubmitter creates a submitter starting orders on taskQueue
+func NewSubmitter(c client.Client, taskQueue string) *Submitter {
+	return &Submitter{client: c, taskQueue: taskQueue}
+}
+
+// WithSigner signs the signals the submitter sends with tokens for subject,
+// for workers that require signed signals
+func (s *Submitter) WithSigner(signer *authz.Signer, subject string) *Submitter {
+	s.signer, s.subject = signer, subject
+	return s
+}
+
+// StartOrder starts the order workflow for order, with memo as its workflow
+// memo. An order whose workflow already exists, even one that has closed, is
+// reported as a duplicate rather than started again.
+func (s *Submitter) StartOrder(ctx context.Context, order models.Order, memo models.OrderMemo) (Submission, error) {
+	submission := Submission{OrderID: order.ID, WorkflowID: models.OrderWorkflowID(order.ID)}
+	options := client.StartWorkflowOptions{
+		ID:                       submission.WorkflowID,
+		TaskQueue:                s.taskQueue,
+		Memo:                     memo.Fields(),
+		WorkflowIDReusePolicy:    enumspb.WORKFLOW_ID_REUSE_POLICY_REJECT_DUPLICATE,
+		WorkflowIDConflictPolicy: enumspb.WORKFLOW_ID_CONFLICT_POLICY_FAIL,
+		// Without this a running duplicate silently returns the existing run
+		WorkflowExecutionErrorWhenAlreadyStarted: true,
+	}
+	run, err := s.client.ExecuteWorkflow(ctx, options, workflows.OrderWorkflow, order)
+	var alreadyStarted *serviceerror.WorkflowExecutionAlreadyStarted
+	if errors.As(err, &alreadyStarted) {
+		submission.RunID = alreadyStarted.RunId
+		submission.Duplicate = true
+		return submission, nil
+	}
+	if err != nil {
+		return Submission{}, err
+	}
+	submission.RunID = run.GetRunID()
+	return submission, nil
+}
+
+// CancelOrder sends the cancel signal to the order's workflow. Cancelling an
+// order that is already cancelled is harmless, so a redelivered cancel can be
+// sent again.
+func (s *Submitter) CancelOrder(ctx context.Context, orderID string) error {
+	if s.signer != nil {
+		token, err := s.signer.Sign(authz.Claims{Subject: s.subject, ExpiresAt: time.Now().Add(5 * time.Minute)})
+		if err != nil {
+			return fmt.Errorf("failed to sign auth token: %w", err)
+		}
+		ctx = authz.WithToken(ctx, token)
+	}
+	err := s.client.SignalWorkflow(ctx, models.OrderWorkflowID(orderID), "", models.SignalCancel, nil)
+	var notFound *serviceerror.NotFound
+	if errors.As(err, &notFound) {
+		return fmt.Errorf("%w: %s", ErrOrderNotFound, orderID)
+	}
+	return err
+}
@@ -0,0 +1,120 @@
+package intake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaConsumerOptions configures NewKafkaConsumer
+type KafkaConsumerOptions struct {
+	Brokers []string
+	Topic   string
+	// GroupID is the consumer group whose committed offsets track progress
+	GroupID string
+	// RetryBackoff is the first wait before a failed message is retried; it
+	// doubles up to MaxRetryBackoff
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+}
+
+// KafkaConsumer reads order messages from a Kafka topic and hands them to a
+// MessageHandler. A message's offset is committed only once it has been
+// handled, so a message is redelivered after a crash rather than lost.
+type KafkaConsumer struct {
+	reader  *kafka.Reader
+	handler *MessageHandler
+	options KafkaConsumerOptions
+}
+
+// NewKafkaConsumer creates a consumer reading topic as a member of a consumer group
+func NewKafkaConsumer(handler *MessageHandler, options KafkaConsumerOptions) (*KafkaConsumer, error) {
+	if len(options.Brokers) == 0 {
+		return nil, fmt.Errorf("at least one Kafka broker is required")
+	}
+	if options.Topic == "" {
+		return nil, fmt.Errorf("Kafka topic is required")
+	}
+	if options.GroupID == "" {
+		return nil, fmt.Errorf("Kafka consumer group is required")
+	}
+	if options.RetryBackoff <= 0 {
+		options.RetryBackoff = time.Second
+	}
+	if options.MaxRetryBackoff < options.RetryBackoff {
+		options.MaxRetryBackoff = 30 * time.Second
+	}
+
+	return &KafkaConsumer{
+		reader: kafka.NewReader(kafka.ReaderConfig{
+			Brokers: options.Brokers,
+			Topic:   options.Topic,
+			GroupID: options.GroupID,
+			// Offsets are committed explicitly after each message is handled
+			CommitInterval: 0,
+			StartOffset:    kafka.FirstOffset,
+		}),
+		handler: handler,
+		options: options,
+	}, nil
+}
+
+// Run consumes messages until ctx is cancelled. A message that fails is
+// retried with backoff and blocks its partition meanwhile, which keeps the
+// create and cancel of an order, keyed to the same partition, in order. An
+// invalid message is logged and skipped.
+func (c *KafkaConsumer) Run(ctx context.Context) error {
+	for {
+		msg, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to fetch message: %w", err)
+		}
+		logger := slog.With("partition", msg.Partition, "offset", msg.Offset, "key", string(msg.Key))
+
+		if !c.handle(ctx, logger, msg) {
+			// Cancelled mid-retry; the uncommitted message is redelivered on restart
+			return nil
+		}
+		if err := c.reader.CommitMessages(ctx, msg); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to commit offset %d: %w", msg.Offset, err)
+		}
+	}
+}
+
+// handle handles msg, retrying until it succeeds or proves invalid. It
+// reports false if ctx was cancelled first.
+func (c *KafkaConsumer) handle(ctx context.Context, logger *slog.Logger, msg kafka.Message) bool {
+	backoff := c.options.RetryBackoff
+	for attempt := 1; ; attempt++ {
+		err := c.handler.HandleMessage(ctx, msg.Value)
+		if err == nil {
+			return true
+		}
+		if errors.Is(err, ErrInvalidMessage) {
+			logger.Error("Skipping invalid order message", "error", err)
+			return true
+		}
+		logger.Warn("Failed to handle order message; retrying", "attempt", attempt, "backoff", backoff, "error", err)
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, c.options.MaxRetryBackoff)
+	}
+}
+
+// Close leaves the consumer group and closes the underlying reader
+func (c *KafkaConsumer) Close() error {
+	return c.reader.Close()
+}
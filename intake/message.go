@@ -0,0 +1,126 @@
+package intake
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+)
+
+const (
+	// MessageOrderCreate starts the order in the message's order field
+	MessageOrderCreate = "order.create"
+	// MessageOrderCancel cancels the order named by the message's order_id
+	MessageOrderCancel = "order.cancel"
+)
+
+// ErrInvalidMessage is returned for a message that can never be handled, so
+// a consumer should drop it rather than retry
+var ErrInvalidMessage = errors.New("invalid order message")
+
+// OrderMessage is a queued request to start or cancel an order. Memo is the
+// business context a started order carries in its workflow memo.
+type OrderMessage struct {
+	Type    string            `json:"type"`
+	Order   *models.Order     `json:"order,omitempty"`
+	Memo    *models.OrderMemo `json:"memo,omitempty"`
+	OrderID string            `json:"order_id,omitempty"`
+}
+
+// MessageHandler starts and cancels order workflows for queued order messages
+type MessageHandler struct {
+	submitter OrderSubmitter
+	canceller OrderCanceller
+}
+
+// NewMessageHandler creates a message handler starting orders with submitter
+// and cancelling them with canceller
+func NewMessageHandler(submitter OrderSubmitter, canceller OrderCanceller) *MessageHandler {
+	return &MessageHandler{submitter: submitter, canceller: canceller}
+}
+
+// HandleMessage starts or cancels the order a message asks for. Both are
+// idempotent, so a redelivered message is harmless. Errors wrapping
+// ErrInvalidMessage are permanent; any other error is worth retrying.
+func (h *MessageHandler) HandleMessage(ctx context.Context, value []byte) error {
+	var msg OrderMessage
+	if err := json.Unmarshal(value, &msg); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+	}
+
+	switch msg.Type {
+	case MessageOrderCreate:
+		order, err := msg.order()
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+		}
+		var memo models.OrderMemo
+		if msg.Memo != nil {
+			memo = *msg.Memo
+		}
+		submission, err := h.submitter.StartOrder(ctx, order, memo)
+		if err != nil {
+			return fmt.Errorf("failed to start order %s: %w", order.ID, err)
+		}
+		if submission.Duplicate {
+			slog.Info("Order already started", "order_id", order.ID, "workflow_id", submission.WorkflowID)
+		} else {
+			slog.Info("Started order workflow", "order_id", order.ID, "workflow_id", submission.WorkflowID, "run_id", submission.RunID)
+		}
+		return nil
+	case MessageOrderCancel:
+		orderID := strings.TrimSpace(msg.OrderID)
+		if orderID == "" && msg.Order != nil {
+			orderID = msg.Order.ID
+		}
+		if orderID == "" {
+			return fmt.Errorf("%w: order_id is required", ErrInvalidMessage)
+		}
+		err := h.canceller.CancelOrder(ctx, orderID)
+		if errors.Is(err, ErrOrderNotFound) {
+			// A closed order can no longer be cancelled, and retrying will not change that
+			slog.Warn("No running workflow to cancel", "order_id", orderID)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to cancel order %s: %w", orderID, err)
+		}
+		slog.Info("Cancelled order workflow", "order_id", orderID)
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidMessage, msg.Type)
+	}
+}
+
+// order returns the order of a create message, defaulting its status to
+// pending and upgraded to the current schema
+func (m OrderMessage) order() (models.Order, error) {
+	if m.Order == nil {
+		return models.Order{}, errors.New("order is required")
+	}
+	// A version 2 order may give only its lines, so it is upgraded first
+	order, err := m.Order.Upgrade()
+	if err != nil {
+		return models.Order{}, fmt.Errorf("order: %w", err)
+	}
+	if err := order.Validate(); err != nil {
+		return models.Order{}, err
+	}
+	if err := order.ValidateTenders(); err != nil {
+		return models.Order{}, fmt.Errorf("order.%w", err)
+	}
+	if err := order.ValidateInstallments(); err != nil {
+		return models.Order{}, fmt.Errorf("order.%w", err)
+	}
+	if order.RedeemPoints < 0 {
+		return models.Order{}, errors.New("order.redeem_points must not be negative")
+	}
+	if order.Status == "" {
+		order.Status = models.StatusPending
+	}
+	return order, nil
+}
@@ -0,0 +1,208 @@
+package intake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// InvalidReasonAttribute is the message attribute carrying why a message
+// was moved to the dead-letter queue
+const InvalidReasonAttribute = "invalid_reason"
+
+// SQSAPI is the subset of the SQS client SQSConsumer uses
+type SQSAPI interface {
+	ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error)
+	DeleteMessage(ctx context.Context, params *sqs.DeleteMessageInput, optFns ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error)
+	ChangeMessageVisibility(ctx context.Context, params *sqs.ChangeMessageVisibilityInput, optFns ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityOutput, error)
+	SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error)
+}
+
+// SQSConsumerOptions configures NewSQSConsumer
+type SQSConsumerOptions struct {
+	QueueURL string
+	// DeadLetterQueueURL receives messages that can never be handled; they are
+	// only logged and deleted when it is empty
+	DeadLetterQueueURL string
+	// VisibilityTimeout hides a received message from other consumers while
+	// it is handled; handling gives up well before it expires
+	VisibilityTimeout time.Duration
+	// RetryBackoff is how long a failed message stays hidden before its
+	// first retry; it doubles with each receive up to MaxRetryBackoff
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+	// WaitTime is how long a receive long-polls for messages
+	WaitTime time.Duration
+}
+
+// SQSConsumer receives order messages from an SQS queue and hands them to a
+// MessageHandler. A message is deleted once handled; one that fails is left
+// on the queue to be received again after a backoff, so delivery is at
+// least once. Give the queue a redrive policy so a message that keeps
+// failing eventually moves to a dead-letter queue.
+type SQSConsumer struct {
+	client  SQSAPI
+	handler *MessageHandler
+	options SQSConsumerOptions
+}
+
+// NewSQSConsumer creates a consumer of options.QueueURL using client
+func NewSQSConsumer(client SQSAPI, handler *MessageHandler, options SQSConsumerOptions) (*SQSConsumer, error) {
+	if options.QueueURL == "" {
+		return nil, errors.New("SQS queue URL is required")
+	}
+	if options.VisibilityTimeout <= 0 {
+		options.VisibilityTimeout = 30 * time.Second
+	}
+	if options.VisibilityTimeout < 2*time.Second {
+		return nil, fmt.Errorf("SQS visibility timeout %s is too short", options.VisibilityTimeout)
+	}
+	if options.RetryBackoff <= 0 {
+		options.RetryBackoff = 5 * time.Second
+	}
+	if options.MaxRetryBackoff < options.RetryBackoff {
+		options.MaxRetryBackoff = 5 * time.Minute
+	}
+	// SQS rejects visibility timeouts above 12 hours
+	options.MaxRetryBackoff = min(options.MaxRetryBackoff, 12*time.Hour)
+	if options.WaitTime <= 0 {
+		options.WaitTime = 20 * time.Second
+	}
+	return &SQSConsumer{client: client, handler: handler, options: options}, nil
+}
+
+// NewSQSConsumerFromEnv creates a consumer with credentials from the default
+// AWS chain (environment, shared config, instance role). An empty region
+// uses AWS_REGION.
+func NewSQSConsumerFromEnv(ctx context.Context, handler *MessageHandler, region string, options SQSConsumerOptions) (*SQSConsumer, error) {
+	var opts []func(*awsconfig.LoadOptions) error
+	if region != "" {
+		opts = append(opts, awsconfig.WithRegion(region))
+	}
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	return NewSQSConsumer(sqs.NewFromConfig(cfg), handler, options)
+}
+
+// Run consumes messages until ctx is cancelled. Messages of a batch are
+// handled in the order received.
+func (c *SQSConsumer) Run(ctx context.Context) error {
+	for {
+		out, err := c.client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+			QueueUrl:            aws.String(c.options.QueueURL),
+			MaxNumberOfMessages: 10,
+			WaitTimeSeconds:     int32(c.options.WaitTime / time.Second),
+			VisibilityTimeout:   int32(c.options.VisibilityTimeout / time.Second),
+			MessageSystemAttributeNames: []types.MessageSystemAttributeName{
+				types.MessageSystemAttributeNameApproximateReceiveCount,
+				types.MessageSystemAttributeNameMessageGroupId,
+			},
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to receive messages: %w", err)
+		}
+		for _, msg := range out.Messages {
+			if ctx.Err() != nil {
+				// Unhandled messages become visible again when their timeout lapses
+				return nil
+			}
+			c.handle(ctx, msg)
+		}
+	}
+}
+
+// handle handles one message and settles it on the queue: deleted when
+// handled, moved to the dead-letter queue when invalid, and otherwise
+// hidden for a backoff before it is received again
+func (c *SQSConsumer) handle(ctx context.Context, msg types.Message) {
+	receives, _ := strconv.Atoi(msg.Attributes[string(types.MessageSystemAttributeNameApproximateReceiveCount)])
+	logger := slog.With("message_id", aws.ToString(msg.MessageId), "receive_count", receives)
+
+	// Stop before the message becomes visible to another consumer, so it is
+	// never handled twice at once
+	handleCtx, cancel := context.WithTimeout(ctx, c.options.VisibilityTimeout-c.options.VisibilityTimeout/5)
+	err := c.handler.HandleMessage(handleCtx, []byte(aws.ToString(msg.Body)))
+	cancel()
+
+	switch {
+	case err == nil:
+		c.delete(ctx, logger, msg)
+	case errors.Is(err, ErrInvalidMessage):
+		logger.Error("Invalid order message", "error", err)
+		if c.options.DeadLetterQueueURL != "" {
+			if err := c.deadLetter(ctx, msg, err); err != nil {
+				// Left on the queue so it is dead-lettered on a later receive
+				logger.Error("Failed to move message to dead-letter queue", "error", err)
+				return
+			}
+		}
+		c.delete(ctx, logger, msg)
+	default:
+		backoff := c.retryBackoff(receives)
+		logger.Warn("Failed to handle order message; retrying", "backoff", backoff, "error", err)
+		_, err := c.client.ChangeMessageVisibility(ctx, &sqs.ChangeMessageVisibilityInput{
+			QueueUrl:          aws.String(c.options.QueueURL),
+			ReceiptHandle:     msg.ReceiptHandle,
+			VisibilityTimeout: int32(backoff / time.Second),
+		})
+		if err != nil {
+			// The message still returns once its visibility timeout lapses
+			logger.Warn("Failed to set retry backoff", "error", err)
+		}
+	}
+}
+
+// retryBackoff is how long to hide a message that has failed receives times
+func (c *SQSConsumer) retryBackoff(receives int) time.Duration {
+	backoff := c.options.RetryBackoff
+	for i := 1; i < receives && backoff < c.options.MaxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, c.options.MaxRetryBackoff)
+}
+
+func (c *SQSConsumer) delete(ctx context.Context, logger *slog.Logger, msg types.Message) {
+	_, err := c.client.DeleteMessage(ctx, &sqs.DeleteMessageInput{
+		QueueUrl:      aws.String(c.options.QueueURL),
+		ReceiptHandle: msg.ReceiptHandle,
+	})
+	if err != nil {
+		// Redelivered later, which idempotent handling makes harmless
+		logger.Warn("Failed to delete handled message", "error", err)
+	}
+}
+
+// deadLetter copies msg to the dead-letter queue with the reason it is invalid
+func (c *SQSConsumer) deadLetter(ctx context.Context, msg types.Message, reason error) error {
+	input := &sqs.SendMessageInput{
+		QueueUrl:    aws.String(c.options.DeadLetterQueueURL),
+		MessageBody: msg.Body,
+		MessageAttributes: map[string]types.MessageAttributeValue{
+			InvalidReasonAttribute: {DataType: aws.String("String"), StringValue: aws.String(reason.Error())},
+		},
+	}
+	if strings.HasSuffix(c.options.DeadLetterQueueURL, ".fifo") {
+		groupID := msg.Attributes[string(types.MessageSystemAttributeNameMessageGroupId)]
+		if groupID == "" {
+			groupID = "invalid"
+		}
+		input.MessageGroupId = aws.String(groupID)
+		input.MessageDeduplicationId = msg.MessageId
+	}
+	_, err := c.client.SendMessage(ctx, input)
+	return err
+}
@@ -0,0 +1,259 @@
+package intake
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+)
+
+const (
+	// SignatureHeader carries the base64 HMAC-SHA256 of the request body
+	SignatureHeader = "X-Shopify-Hmac-Sha256"
+	// TopicHeader names the webhook's event; only TopicOrderCreated starts orders
+	TopicHeader = "X-Shopify-Topic"
+	// WebhookIDHeader identifies a delivery, and is the same on redelivery
+	WebhookIDHeader = "X-Shopify-Webhook-Id"
+
+	// TopicOrderCreated is the topic of an order-created webhook
+	TopicOrderCreated = "orders/create"
+
+	// maxWebhookBytes bounds request bodies; order webhooks are far smaller
+	maxWebhookBytes = 1 << 20
+)
+
+// StorefrontOrder is the order-created webhook payload of the storefront, in
+// the Shopify shape. Prices are decimal strings.
+type StorefrontOrder struct {
+	ID         int64                `json:"id"`
+	Name       string               `json:"name"`
+	CreatedAt  time.Time            `json:"created_at"`
+	Currency   string               `json:"currency"`
+	TotalPrice string               `json:"total_price"`
+	LineItems  []StorefrontLineItem `json:"line_items"`
+	// SourceName is the sales channel, such as web or pos
+	SourceName      string              `json:"source_name"`
+	Customer        *StorefrontCustomer `json:"customer"`
+	ShippingAddress *StorefrontAddress  `json:"shipping_address"`
+}
+
+// StorefrontCustomer is the customer who placed a StorefrontOrder
+type StorefrontCustomer struct {
+	ID        int64  `json:"id"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Email     string `json:"email"`
+	Phone     string `json:"phone"`
+}
+
+// Customer maps the storefront customer to the order's customer. The
+// storefront has no loyalty tiers, so the tier is left unknown.
+func (c StorefrontCustomer) Customer() *models.Customer {
+	return &models.Customer{
+		ID:    strconv.FormatInt(c.ID, 10),
+		Name:  strings.TrimSpace(c.FirstName + " " + c.LastName),
+		Email: c.Email,
+		Phone: c.Phone,
+	}
+}
+
+// StorefrontAddress is the shipping address of a StorefrontOrder
+type StorefrontAddress struct {
+	CountryCode string `json:"country_code"`
+}
+
+// StorefrontLineItem is a line of a StorefrontOrder
+type StorefrontLineItem struct {
+	SKU      string `json:"sku"`
+	Title    string `json:"title"`
+	Quantity int    `json:"quantity"`
+	Price    string `json:"price"`
+}
+
+// Order maps the webhook to a pending order whose ID is idPrefix followed by
+// the storefront order ID, so redeliveries map to the same workflow. Each
+// unit of a line becomes one item, named by its SKU, or its title when it
+// has none, and each line one of the order's lines. The order is in the
+// storefront's currency. A guest checkout, without a customer, leaves the
+// order's customer unset. The order must pass models.Order.Validate.
+func (o StorefrontOrder) Order(idPrefix string) (models.Order, error) {
+	if o.ID <= 0 {
+		return models.Order{}, errors.New("id is required")
+	}
+	amount, err := strconv.ParseFloat(o.TotalPrice, 64)
+	if err != nil {
+		return models.Order{}, fmt.Errorf("total_price: %w", err)
+	}
+	var items []string
+	var lines []models.OrderItem
+	for i, line := range o.LineItems {
+		item := strings.TrimSpace(line.SKU)
+		if item == "" {
+			item = strings.TrimSpace(line.Title)
+		}
+		if item == "" {
+			return models.Order{}, fmt.Errorf("line_items[%d]: sku or title is required", i)
+		}
+		if line.Quantity <= 0 {
+			return models.Order{}, fmt.Errorf("line_items[%d]: quantity must be positive", i)
+		}
+		if line.Quantity > models.MaxLineQuantity {
+			return models.Order{}, fmt.Errorf("line_items[%d]: quantity must be at most %d", i, models.MaxLineQuantity)
+		}
+		price, err := strconv.ParseFloat(line.Price, 64)
+		if err != nil {
+			return models.Order{}, fmt.Errorf("line_items[%d].price: %w", i, err)
+		}
+		for range line.Quantity {
+			items = append(items, item)
+		}
+		lines = append(lines, models.OrderItem{SKU: item, Quantity: line.Quantity, Price: price})
+	}
+	if len(items) == 0 {
+		return models.Order{}, errors.New("line_items is empty")
+	}
+	order := models.Order{
+		ID:            idPrefix + strconv.FormatInt(o.ID, 10),
+		Items:         items,
+		Amount:        amount,
+		Status:        models.StatusPending,
+		CreatedAt:     o.CreatedAt,
+		Currency:      o.Currency,
+		SchemaVersion: models.OrderSchemaVersion,
+		Lines:         lines,
+	}
+	if o.Customer != nil && o.Customer.ID > 0 {
+		order.Customer = o.Customer.Customer()
+	}
+	if err := order.Validate(); err != nil {
+		return models.Order{}, err
+	}
+	return order, nil
+}
+
+// Memo returns the order's business context: the storefront customer, the
+// sales channel, and the shipping country as the region
+func (o StorefrontOrder) Memo() models.OrderMemo {
+	memo := models.OrderMemo{Channel: o.SourceName}
+	if o.Customer != nil && o.Customer.ID > 0 {
+		memo.CustomerID = strconv.FormatInt(o.Customer.ID, 10)
+	}
+	if o.ShippingAddress != nil {
+		memo.Region = o.ShippingAddress.CountryCode
+	}
+	return memo
+}
+
+// VerifySignature reports whether signature is the base64 HMAC-SHA256 of
+// body under any of secrets; several are accepted so a secret can be rotated
+func VerifySignature(body []byte, signature string, secrets []string) bool {
+	got, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil || len(got) == 0 {
+		return false
+	}
+	for _, secret := range secrets {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		if hmac.Equal(got, mac.Sum(nil)) {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookHandlerOptions configures NewWebhookHandler
+type WebhookHandlerOptions struct {
+	// Secrets are the shared secrets the storefront signs webhooks with
+	Secrets []string
+	// OrderIDPrefix is prepended to storefront order IDs
+	OrderIDPrefix string
+}
+
+// WebhookHandler receives order-created webhooks and starts an order
+// workflow for each. It answers 201 when it started the order and 200 for a
+// redelivery of one it already started; a 5xx asks the storefront to retry.
+type WebhookHandler struct {
+	submitter OrderSubmitter
+	options   WebhookHandlerOptions
+}
+
+// NewWebhookHandler creates a webhook handler submitting orders to submitter
+func NewWebhookHandler(submitter OrderSubmitter, options WebhookHandlerOptions) *WebhookHandler {
+	return &WebhookHandler{submitter: submitter, options: options}
+}
+
+// ServeHTTP implements http.Handler
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBytes))
+	if err != nil {
+		http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if !VerifySignature(body, r.Header.Get(SignatureHeader), h.options.Secrets) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	logger := slog.With("webhook_id", r.Header.Get(WebhookIDHeader))
+	if topic := r.Header.Get(TopicHeader); topic != TopicOrderCreated {
+		// Acknowledged so the storefront does not retry a topic we ignore
+		logger.Info("Ignoring webhook topic", "topic", topic)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	var payload StorefrontOrder
+	if err := json.Unmarshal(body, &payload); err != nil {
+		rejectOrder(w, err)
+		return
+	}
+	order, err := payload.Order(h.options.OrderIDPrefix)
+	if err != nil {
+		rejectOrder(w, err)
+		return
+	}
+
+	submission, err := h.submitter.StartOrder(r.Context(), order, payload.Memo())
+	if err != nil {
+		logger.Error("Failed to start order workflow", "order_id", order.ID, "error", err)
+		http.Error(w, "failed to start order", http.StatusServiceUnavailable)
+		return
+	}
+	status := http.StatusCreated
+	if submission.Duplicate {
+		status = http.StatusOK
+		logger.Info("Order already started", "order_id", order.ID, "workflow_id", submission.WorkflowID)
+	} else {
+		logger.Info("Started order workflow", "order_id", order.ID, "workflow_id", submission.WorkflowID, "run_id", submission.RunID)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(submission)
+}
+
+// rejectOrder answers a webhook whose order cannot be started with a 400 and
+// the OrderError saying why, an OrderInvalid one for any payload error
+func rejectOrder(w http.ResponseWriter, err error) {
+	orderErr := models.OrderErrorFrom(err)
+	if orderErr.Code != models.ErrTypeOrderInvalid {
+		orderErr = &models.OrderError{Code: models.ErrTypeOrderInvalid, Stage: models.StageValidation, Message: "invalid order payload: " + err.Error()}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(orderErr)
+}
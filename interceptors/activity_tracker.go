@@ -0,0 +1,65 @@
+package interceptors
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.temporal.io/sdk/interceptor"
+)
+
+// ActivityTracker counts activity executions in this worker process so a
+// shutdown can report how many in-flight activities drained and how many were
+// abandoned when the stop timeout expired
+type ActivityTracker struct {
+	interceptor.WorkerInterceptorBase
+	inFlight    atomic.Int64
+	completed   atomic.Int64
+	interrupted atomic.Int64
+}
+
+// NewActivityTracker creates an ActivityTracker with zeroed counts
+func NewActivityTracker() *ActivityTracker {
+	return &ActivityTracker{}
+}
+
+// InFlight returns the number of activities currently executing
+func (t *ActivityTracker) InFlight() int64 {
+	return t.inFlight.Load()
+}
+
+// Completed returns the number of activity executions that have returned,
+// successfully or not
+func (t *ActivityTracker) Completed() int64 {
+	return t.completed.Load()
+}
+
+// Interrupted returns the number of completed activity executions whose
+// context was cancelled before they returned, such as activities cut off by
+// the worker stop timeout
+func (t *ActivityTracker) Interrupted() int64 {
+	return t.interrupted.Load()
+}
+
+// InterceptActivity counts the activity while it runs
+func (t *ActivityTracker) InterceptActivity(ctx context.Context, next interceptor.ActivityInboundInterceptor) interceptor.ActivityInboundInterceptor {
+	i := &activityTrackerInbound{tracker: t}
+	i.Next = next
+	return i
+}
+
+type activityTrackerInbound struct {
+	interceptor.ActivityInboundInterceptorBase
+	tracker *ActivityTracker
+}
+
+func (i *activityTrackerInbound) ExecuteActivity(ctx context.Context, in *interceptor.ExecuteActivityInput) (interface{}, error) {
+	i.tracker.inFlight.Add(1)
+	defer func() {
+		i.tracker.inFlight.Add(-1)
+		i.tracker.completed.Add(1)
+		if ctx.Err() != nil {
+			i.tracker.interrupted.Add(1)
+		}
+	}()
+	return i.Next.ExecuteActivity(ctx, in)
+}
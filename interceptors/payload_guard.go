@@ -0,0 +1,188 @@
+package interceptors
+
+import (
+	"context"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/workflow"
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultMaxPayloadBytes keeps payloads well under Temporal's 2 MiB blob limit
+const DefaultMaxPayloadBytes = 1 << 20
+
+// PayloadGuard rejects oversized payloads with a models.PayloadTooLargeError instead
+// of letting the server fail them with an opaque ResourceExhausted error.
+//
+// Registered on client.Options.Interceptors it checks workflow starts and
+// signals; workers created from that client also check activity and child
+// workflow inputs, activity results, and workflow results. Sizes are measured
+// with the default data converter, before any codec runs.
+type PayloadGuard struct {
+	interceptor.InterceptorBase
+	limit int
+}
+
+// NewPayloadGuard creates a guard rejecting payloads above maxBytes
+func NewPayloadGuard(maxBytes int) *PayloadGuard {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxPayloadBytes
+	}
+	return &PayloadGuard{limit: maxBytes}
+}
+
+// check returns a models.PayloadTooLargeError if values encode to more than the limit
+func (g *PayloadGuard) check(operation string, values ...interface{}) *models.PayloadTooLargeError {
+	payloads, err := converter.GetDefaultDataConverter().ToPayloads(values...)
+	if err != nil {
+		// Leave encoding failures to the SDK, which reports them clearly
+		return nil
+	}
+	if size := proto.Size(payloads); size > g.limit {
+		return &models.PayloadTooLargeError{Operation: operation, Size: size, Limit: g.limit}
+	}
+	return nil
+}
+
+// checkInWorker is check for workflow and activity code, where the error must
+// be a non-retryable ApplicationError: the same payload can never shrink
+func (g *PayloadGuard) checkInWorker(operation string, values ...interface{}) error {
+	if err := g.check(operation, values...); err != nil {
+		return err.ApplicationError()
+	}
+	return nil
+}
+
+// InterceptClient checks payloads sent by the client
+func (g *PayloadGuard) InterceptClient(next interceptor.ClientOutboundInterceptor) interceptor.ClientOutboundInterceptor {
+	c := &payloadGuardClient{guard: g}
+	c.Next = next
+	return c
+}
+
+// InterceptWorkflow checks payloads produced by workflow code
+func (g *PayloadGuard) InterceptWorkflow(ctx workflow.Context, next interceptor.WorkflowInboundInterceptor) interceptor.WorkflowInboundInterceptor {
+	w := &payloadGuardWorkflowInbound{guard: g}
+	w.Next = next
+	return w
+}
+
+// InterceptActivity checks activity results
+func (g *PayloadGuard) InterceptActivity(ctx context.Context, next interceptor.ActivityInboundInterceptor) interceptor.ActivityInboundInterceptor {
+	a := &payloadGuardActivityInbound{guard: g}
+	a.Next = next
+	return a
+}
+
+type payloadGuardClient struct {
+	interceptor.ClientOutboundInterceptorBase
+	guard *PayloadGuard
+}
+
+func (c *payloadGuardClient) ExecuteWorkflow(ctx context.Context, in *interceptor.ClientExecuteWorkflowInput) (client.WorkflowRun, error) {
+	if err := c.guard.check("workflow "+in.WorkflowType+" input", in.Args...); err != nil {
+		return nil, err
+	}
+	return c.Next.ExecuteWorkflow(ctx, in)
+}
+
+func (c *payloadGuardClient) SignalWorkflow(ctx context.Context, in *interceptor.ClientSignalWorkflowInput) error {
+	if err := c.guard.check("signal "+in.SignalName, in.Arg); err != nil {
+		return err
+	}
+	return c.Next.SignalWorkflow(ctx, in)
+}
+
+func (c *payloadGuardClient) SignalWithStartWorkflow(ctx context.Context, in *interceptor.ClientSignalWithStartWorkflowInput) (client.WorkflowRun, error) {
+	if err := c.guard.check("signal "+in.SignalName, in.SignalArg); err != nil {
+		return nil, err
+	}
+	if err := c.guard.check("workflow "+in.WorkflowType+" input", in.Args...); err != nil {
+		return nil, err
+	}
+	return c.Next.SignalWithStartWorkflow(ctx, in)
+}
+
+type payloadGuardWorkflowInbound struct {
+	interceptor.WorkflowInboundInterceptorBase
+	guard *PayloadGuard
+}
+
+func (w *payloadGuardWorkflowInbound) Init(outbound interceptor.WorkflowOutboundInterceptor) error {
+	o := &payloadGuardWorkflowOutbound{guard: w.guard}
+	o.Next = outbound
+	return w.Next.Init(o)
+}
+
+func (w *payloadGuardWorkflowInbound) ExecuteWorkflow(ctx workflow.Context, in *interceptor.ExecuteWorkflowInput) (interface{}, error) {
+	result, err := w.Next.ExecuteWorkflow(ctx, in)
+	if err != nil || result == nil {
+		return result, err
+	}
+	if err := w.guard.checkInWorker("workflow "+workflow.GetInfo(ctx).WorkflowType.Name+" result", result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type payloadGuardWorkflowOutbound struct {
+	interceptor.WorkflowOutboundInterceptorBase
+	guard *PayloadGuard
+}
+
+func (w *payloadGuardWorkflowOutbound) ExecuteActivity(ctx workflow.Context, activityType string, args ...interface{}) workflow.Future {
+	if err := w.guard.checkInWorker("activity "+activityType+" input", args...); err != nil {
+		future, settable := workflow.NewFuture(ctx)
+		settable.SetError(err)
+		return future
+	}
+	return w.Next.ExecuteActivity(ctx, activityType, args...)
+}
+
+func (w *payloadGuardWorkflowOutbound) ExecuteChildWorkflow(ctx workflow.Context, childWorkflowType string, args ...interface{}) workflow.ChildWorkflowFuture {
+	if err := w.guard.checkInWorker("child workflow "+childWorkflowType+" input", args...); err != nil {
+		return failedChildWorkflowFuture(ctx, err)
+	}
+	return w.Next.ExecuteChildWorkflow(ctx, childWorkflowType, args...)
+}
+
+type payloadGuardActivityInbound struct {
+	interceptor.ActivityInboundInterceptorBase
+	guard *PayloadGuard
+}
+
+func (a *payloadGuardActivityInbound) ExecuteActivity(ctx context.Context, in *interceptor.ExecuteActivityInput) (interface{}, error) {
+	result, err := a.Next.ExecuteActivity(ctx, in)
+	if err != nil || result == nil {
+		return result, err
+	}
+	if err := a.guard.checkInWorker("activity result", result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// failedChildWorkflow is a child workflow that failed before it started
+type failedChildWorkflow struct {
+	workflow.Future
+	execution workflow.Future
+}
+
+func failedChildWorkflowFuture(ctx workflow.Context, err error) workflow.ChildWorkflowFuture {
+	result, settable := workflow.NewFuture(ctx)
+	settable.SetError(err)
+	execution, executionSettable := workflow.NewFuture(ctx)
+	executionSettable.SetError(err)
+	return &failedChildWorkflow{Future: result, execution: execution}
+}
+
+func (f *failedChildWorkflow) GetChildWorkflowExecution() workflow.Future {
+	return f.execution
+}
+
+func (f *failedChildWorkflow) SignalChildWorkflow(ctx workflow.Context, signalName string, data interface{}) workflow.Future {
+	return f.execution
+}
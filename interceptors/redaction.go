@@ -0,0 +1,123 @@
+package interceptors
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/interceptor"
+)
+
+// RedactedValue replaces the value of every redacted field in logs
+const RedactedValue = "[REDACTED]"
+
+// DefaultRedactedFields are the JSON field names treated as customer PII
+var DefaultRedactedFields = []string{
+	"email",
+	"customer_email",
+	"phone",
+	"address",
+	"shipping_address",
+	"billing_address",
+	"payment_details",
+	"card_number",
+	"cvv",
+	"payment_token",
+	"account_number",
+}
+
+// Redactor masks configured JSON fields, at any depth, in values bound for logs
+type Redactor struct {
+	fields map[string]bool
+}
+
+// NewRedactor creates a redactor for the given field names, matched case-insensitively
+func NewRedactor(fields []string) *Redactor {
+	r := &Redactor{fields: make(map[string]bool, len(fields))}
+	for _, field := range fields {
+		if field = strings.TrimSpace(field); field != "" {
+			r.fields[strings.ToLower(field)] = true
+		}
+	}
+	return r
+}
+
+// Redact returns the JSON form of v with redacted fields masked. The original
+// value is never modified. Values that cannot be encoded are omitted entirely
+// rather than risk logging them unredacted.
+func (r *Redactor) Redact(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return RedactedValue
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return RedactedValue
+	}
+	return r.redact(generic)
+}
+
+func (r *Redactor) redact(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for key, field := range value {
+			if r.fields[strings.ToLower(key)] {
+				value[key] = RedactedValue
+			} else {
+				value[key] = r.redact(field)
+			}
+		}
+		return value
+	case []interface{}:
+		for i, item := range value {
+			value[i] = r.redact(item)
+		}
+		return value
+	default:
+		return value
+	}
+}
+
+// LoggingInterceptor logs every activity's inputs and outputs at debug level
+// with PII fields redacted
+type LoggingInterceptor struct {
+	interceptor.WorkerInterceptorBase
+	redactor *Redactor
+}
+
+// NewLoggingInterceptor creates a worker interceptor that redacts the given
+// fields from logged activity inputs and outputs
+func NewLoggingInterceptor(fields []string) *LoggingInterceptor {
+	return &LoggingInterceptor{redactor: NewRedactor(fields)}
+}
+
+// InterceptActivity wraps each activity execution with redacted logging
+func (i *LoggingInterceptor) InterceptActivity(ctx context.Context, next interceptor.ActivityInboundInterceptor) interceptor.ActivityInboundInterceptor {
+	a := &activityLoggingInterceptor{redactor: i.redactor}
+	a.Next = next
+	return a
+}
+
+type activityLoggingInterceptor struct {
+	interceptor.ActivityInboundInterceptorBase
+	redactor *Redactor
+}
+
+func (a *activityLoggingInterceptor) ExecuteActivity(ctx context.Context, in *interceptor.ExecuteActivityInput) (interface{}, error) {
+	logger := activity.GetLogger(ctx)
+
+	args := make([]interface{}, len(in.Args))
+	for i, arg := range in.Args {
+		args[i] = a.redactor.Redact(arg)
+	}
+	logger.Debug("Activity input", "input", args)
+
+	result, err := a.Next.ExecuteActivity(ctx, in)
+	if err != nil {
+		logger.Debug("Activity failed", "error", err)
+		return result, err
+	}
+	logger.Debug("Activity output", "output", a.redactor.Redact(result))
+	return result, nil
+}
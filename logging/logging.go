@@ -0,0 +1,102 @@
+package logging
+
+import (
+	"io"
+	"log/slog"
+	"strings"
+	"unicode"
+
+	"go.temporal.io/sdk/log"
+)
+
+// Supported output formats
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Config selects the log output format and minimum level
+type Config struct {
+	Format string
+	Level  slog.Level
+}
+
+// New creates an slog logger writing to w in the configured format
+func New(w io.Writer, config Config) *slog.Logger {
+	options := &slog.HandlerOptions{Level: config.Level}
+	if config.Format == FormatJSON {
+		return slog.New(slog.NewJSONHandler(w, options))
+	}
+	return slog.New(slog.NewTextHandler(w, options))
+}
+
+// TemporalLogger adapts an slog logger to Temporal's log.Logger. The SDK tags
+// its logs with keys such as WorkflowID and Attempt; these are rewritten to
+// snake_case (workflow_id, attempt) to match the keys used by this project.
+type TemporalLogger struct {
+	logger *slog.Logger
+}
+
+var _ log.WithLogger = (*TemporalLogger)(nil)
+
+// NewTemporalLogger wraps an slog logger for use as client.Options.Logger
+func NewTemporalLogger(logger *slog.Logger) *TemporalLogger {
+	return &TemporalLogger{logger: logger}
+}
+
+// Debug logs a message at debug level
+func (l *TemporalLogger) Debug(msg string, keyvals ...interface{}) {
+	l.logger.Debug(msg, normalizeKeys(keyvals)...)
+}
+
+// Info logs a message at info level
+func (l *TemporalLogger) Info(msg string, keyvals ...interface{}) {
+	l.logger.Info(msg, normalizeKeys(keyvals)...)
+}
+
+// Warn logs a message at warn level
+func (l *TemporalLogger) Warn(msg string, keyvals ...interface{}) {
+	l.logger.Warn(msg, normalizeKeys(keyvals)...)
+}
+
+// Error logs a message at error level
+func (l *TemporalLogger) Error(msg string, keyvals ...interface{}) {
+	l.logger.Error(msg, normalizeKeys(keyvals)...)
+}
+
+// With returns a logger that adds keyvals to every message
+func (l *TemporalLogger) With(keyvals ...interface{}) log.Logger {
+	return &TemporalLogger{logger: l.logger.With(normalizeKeys(keyvals)...)}
+}
+
+// normalizeKeys rewrites the string keys of alternating key/value pairs to snake_case
+func normalizeKeys(keyvals []interface{}) []interface{} {
+	normalized := make([]interface{}, len(keyvals))
+	copy(normalized, keyvals)
+	for i := 0; i < len(normalized)-1; i += 2 {
+		if key, ok := normalized[i].(string); ok {
+			normalized[i] = snakeCase(key)
+		}
+	}
+	return normalized
+}
+
+// snakeCase converts CamelCase keys, including acronyms such as "ID", to
+// snake_case. Keys that are already lower case are returned unchanged.
+func snakeCase(key string) string {
+	runes := []rune(key)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// Start a new word at a lower-to-upper change or at the last
+			// capital of an acronym followed by a lower-case letter
+			if i > 0 && (unicode.IsLower(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsUpper(runes[i-1]) && unicode.IsLower(runes[i+1]))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
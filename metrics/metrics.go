@@ -0,0 +1,92 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/uber-go/tally/v4"
+	"github.com/uber-go/tally/v4/prometheus"
+	"go.temporal.io/sdk/client"
+	sdktally "go.temporal.io/sdk/contrib/tally"
+)
+
+// reportInterval is how often tally flushes buffered metrics to the reporter
+const reportInterval = time.Second
+
+// Server exposes worker metrics in Prometheus format on /metrics
+type Server struct {
+	port     int
+	scope    tally.Scope
+	closer   io.Closer
+	reporter prometheus.Reporter
+	server   *http.Server
+}
+
+// NewServer creates a metrics server backed by its own Prometheus registry.
+// Pass Handler() to client.Options.MetricsHandler so the SDK's task latency,
+// activity failure, and workflow completion metrics are exported.
+func NewServer(port int) *Server {
+	registry := prom.NewRegistry()
+	reporter := prometheus.NewReporter(prometheus.Options{
+		Registerer: registry,
+		OnRegisterError: func(err error) {
+			fmt.Printf("Failed to register metric: %v\n", err)
+		},
+	})
+
+	scope, closer := tally.NewRootScope(tally.ScopeOptions{
+		CachedReporter:  reporter,
+		Separator:       prometheus.DefaultSeparator,
+		SanitizeOptions: &sdktally.PrometheusSanitizeOptions,
+	}, reportInterval)
+
+	return &Server{
+		port:     port,
+		scope:    sdktally.NewPrometheusNamingScope(scope),
+		closer:   closer,
+		reporter: reporter,
+	}
+}
+
+// Handler returns the Temporal metrics handler that records into this server
+func (s *Server) Handler() client.MetricsHandler {
+	return sdktally.NewMetricsHandler(s.scope)
+}
+
+// Start starts the metrics HTTP server
+func (s *Server) Start() error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", s.reporter.HTTPHandler())
+
+	s.server = &http.Server{
+		Addr:         fmt.Sprintf(":%d", s.port),
+		Handler:      mux,
+		ReadTimeout:  5 * time.Second,
+		WriteTimeout: 10 * time.Second,
+		IdleTimeout:  15 * time.Second,
+	}
+
+	go func() {
+		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			fmt.Printf("Metrics server error: %v\n", err)
+		}
+	}()
+
+	fmt.Printf("Metrics server started on port %d\n", s.port)
+	return nil
+}
+
+// Shutdown flushes pending metrics and stops the metrics HTTP server
+func (s *Server) Shutdown(ctx context.Context) error {
+	if err := s.closer.Close(); err != nil {
+		return err
+	}
+	if s.server != nil {
+		return s.server.Shutdown(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// OrderAnalytics is the record exported for a completed order by
+// OrderAnalyticsWorkflow
+type OrderAnalytics struct {
+	OrderResult
+	OrderMemo
+	Amount    float64   `json:"amount"`
+	ItemCount int       `json:"item_count"`
+	ClosedAt  time.Time `json:"closed_at"`
+}
+
+// OrderAnalyticsWorkflowID returns the workflow ID of an order's analytics
+// export, so an export is not started twice for one order run
+func OrderAnalyticsWorkflowID(orderID, runID string) string {
+	return fmt.Sprintf("order-analytics-%s-%s", orderID, runID)
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// OrderArchive is the cold-storage record of a completed order: the final
+// order, its status history, and its invoice reference. PIIPurged is set on
+// the copy archived again once the PII retention period has passed.
+type OrderArchive struct {
+	Order      Order          `json:"order"`
+	Memo       OrderMemo      `json:"memo"`
+	Result     OrderResult    `json:"result"`
+	Events     []StatusChange `json:"events"`
+	InvoiceURL string         `json:"invoice_url,omitempty"`
+	ClosedAt   time.Time      `json:"closed_at"`
+	PIIPurged  bool           `json:"pii_purged,omitempty"`
+}
+
+// WithoutPII returns a copy of the archive without the personal data it
+// holds: the customer, on the order and in the memo, the shipping address
+// and instructions, and the gift card numbers the order was paid with
+func (a OrderArchive) WithoutPII() OrderArchive {
+	a.Memo.CustomerID = ""
+	a.Order.Customer = nil
+	if a.Order.Shipping != nil {
+		shipping := *a.Order.Shipping
+		shipping.Address = nil
+		shipping.Instructions = ""
+		a.Order.Shipping = &shipping
+	}
+	a.Order.Tenders = slices.Clone(a.Order.Tenders)
+	for i := range a.Order.Tenders {
+		a.Order.Tenders[i].Card = ""
+	}
+	a.PIIPurged = true
+	return a
+}
+
+// OrderArchiveRequest is the input of OrderArchiveWorkflow. PIIRetention is
+// how long the archive keeps personal data before it is purged; zero keeps
+// it.
+type OrderArchiveRequest struct {
+	Archive      OrderArchive  `json:"archive"`
+	PIIRetention time.Duration `json:"pii_retention,omitempty"`
+}
+
+// OrderArchiveWorkflowID returns the workflow ID of an order's archival, so
+// an order run is not archived twice
+func OrderArchiveWorkflowID(orderID, runID string) string {
+	return fmt.Sprintf("order-archive-%s-%s", orderID, runID)
+}
+
+// OrderArchiveKey returns where an order's archive is kept in cold storage,
+// under the UTC day it closed so lifecycle rules can match by date
+func OrderArchiveKey(orderID string, closedAt time.Time) string {
+	return fmt.Sprintf("orders/%s/%s.json", closedAt.UTC().Format("2006/01/02"), orderID)
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Cart is the input of CartReminderWorkflow: a shopper's cart awaiting
+// checkout. Reminders are the waits before each reminder, each counted from
+// the one before, and ExpireAfter the wait after the last before the cart
+// expires; empty or zero uses the workflow defaults. They are part of the
+// input, rather than worker configuration, so replays always see the same
+// schedule.
+type Cart struct {
+	ID          string          `json:"id"`
+	CustomerID  string          `json:"customer_id,omitempty"`
+	Items       []string        `json:"items"`
+	Amount      float64         `json:"amount"`
+	CreatedAt   time.Time       `json:"created_at"`
+	Reminders   []time.Duration `json:"reminders,omitempty"`
+	ExpireAfter time.Duration   `json:"expire_after,omitempty"`
+}
+
+// CartReminder is one reminder to check out an abandoned cart. Number counts
+// from one; Final is set on the last reminder before the cart expires.
+type CartReminder struct {
+	CartID     string   `json:"cart_id"`
+	CustomerID string   `json:"customer_id,omitempty"`
+	Items      []string `json:"items"`
+	Amount     float64  `json:"amount"`
+	Number     int      `json:"number"`
+	Final      bool     `json:"final,omitempty"`
+}
+
+// CheckoutCompleted is the optional payload of the checkout-completed signal
+type CheckoutCompleted struct {
+	OrderID string `json:"order_id,omitempty"`
+}
+
+// CartStatus represents the current state of a cart reminder workflow
+type CartStatus struct {
+	CartID        string    `json:"cart_id"`
+	Status        string    `json:"status"`
+	RemindersSent int       `json:"reminders_sent"`
+	OrderID       string    `json:"order_id,omitempty"`
+	LastUpdated   time.Time `json:"last_updated"`
+}
+
+// Cart statuses
+const (
+	CartAwaitingCheckout = "awaiting_checkout"
+	CartCheckedOut       = "checked_out"
+	CartExpired          = "expired"
+)
+
+// SignalCheckoutCompleted tells a cart reminder workflow that its cart was
+// checked out, ending the reminders
+const SignalCheckoutCompleted = "checkout-completed"
+
+// CartWorkflowID returns the workflow ID of the reminder workflow for a cart
+func CartWorkflowID(cartID string) string {
+	return fmt.Sprintf("cart-%s", cartID)
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// DefaultBaseCurrency is the currency payments are charged in when the
+// worker configures none
+const DefaultBaseCurrency = "USD"
+
+// ExchangeRate is how many units of To one unit of From buys, as of AsOf
+type ExchangeRate struct {
+	From string    `json:"from"`
+	To   string    `json:"to"`
+	Rate float64   `json:"rate"`
+	AsOf time.Time `json:"as_of"`
+}
+
+// ConversionRequest asks ConvertCurrency to convert an order's amount from
+// its currency into To; an empty To is the worker's base currency
+type ConversionRequest struct {
+	OrderID string  `json:"order_id"`
+	Amount  float64 `json:"amount"`
+	From    string  `json:"from"`
+	To      string  `json:"to,omitempty"`
+}
+
+// CurrencyConversion is an amount converted at an exchange rate. It is
+// recorded on the payment of an order in another currency for audit.
+type CurrencyConversion struct {
+	From            string    `json:"from"`
+	To              string    `json:"to"`
+	Rate            float64   `json:"rate"`
+	RateAsOf        time.Time `json:"rate_as_of"`
+	Amount          float64   `json:"amount"`
+	ConvertedAmount float64   `json:"converted_amount"`
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Loyalty tiers of a customer
+const (
+	CustomerTierStandard = "standard"
+	CustomerTierSilver   = "silver"
+	CustomerTierGold     = "gold"
+	CustomerTierPlatinum = "platinum"
+)
+
+// CustomerTiers lists the loyalty tiers a customer can be in
+var CustomerTiers = []string{CustomerTierStandard, CustomerTierSilver, CustomerTierGold, CustomerTierPlatinum}
+
+// Customer is who placed an order and how to reach them. It travels with the
+// order, so activities notifying, screening, or rewarding the customer need
+// no lookup of their own. Email and Phone are where the email and SMS
+// notifications go; Tier is the customer's loyalty tier, empty when unknown.
+type Customer struct {
+	ID    string `json:"id"`
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+	Phone string `json:"phone,omitempty"`
+	Tier  string `json:"tier,omitempty"`
+}
+
+// Validate checks that the customer has an ID, that its email looks like an
+// address, and that its tier, if any, is known
+func (c Customer) Validate() error {
+	if fields := c.fieldErrors(); len(fields) > 0 {
+		return fields[0]
+	}
+	return nil
+}
+
+// fieldErrors lists what Validate finds wrong with the customer
+func (c Customer) fieldErrors() []FieldError {
+	var fields []FieldError
+	if c.ID == "" {
+		fields = append(fields, FieldError{Field: "customer.id", Message: "is required"})
+	}
+	if c.Email != "" && !strings.Contains(c.Email, "@") {
+		fields = append(fields, FieldError{Field: "customer.email", Message: fmt.Sprintf("%q is not an email address", c.Email)})
+	}
+	if c.Tier != "" && !slices.Contains(CustomerTiers, c.Tier) {
+		fields = append(fields, FieldError{Field: "customer.tier", Message: fmt.Sprintf("must be one of %s, got %q", strings.Join(CustomerTiers, ", "), c.Tier)})
+	}
+	return fields
+}
+
+// Contact returns where a notification on channel, email or sms, reaches the
+// customer; empty when the customer has not given one
+func (c Customer) Contact(channel string) string {
+	switch channel {
+	case "email":
+		return c.Email
+	case "sms":
+		return c.Phone
+	}
+	return ""
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// OrderFailure describes an order whose workflow failed after exhausting its
+// retries. Stage is the stage the order reached. Error is the failure as an
+// OrderError, unset for failures recorded before it was added.
+// TransactionID and Tenders are the payment an order that failed after it
+// was paid was charged, which a re-drive keeps rather than charging again.
+type OrderFailure struct {
+	Order         Order           `json:"order"`
+	WorkflowID    string          `json:"workflow_id"`
+	RunID         string          `json:"run_id"`
+	Stage         OrderStage      `json:"stage"`
+	Reason        string          `json:"reason"`
+	Error         *OrderError     `json:"error,omitempty"`
+	TransactionID string          `json:"transaction_id,omitempty"`
+	Tenders       []TenderPayment `json:"tenders,omitempty"`
+	FailedAt      time.Time       `json:"failed_at"`
+}
+
+// Paid reports whether the order failed after it was paid
+func (f OrderFailure) Paid() bool {
+	return f.TransactionID != ""
+}
+
+// DeadLetterStatus represents the current state of a dead-lettered order
+type DeadLetterStatus struct {
+	Failure     OrderFailure `json:"failure"`
+	Status      string       `json:"status"`
+	RetriedBy   string       `json:"retried_by,omitempty"`
+	LastUpdated time.Time    `json:"last_updated"`
+}
+
+// Dead-letter statuses
+const (
+	DeadLetterAwaitingRetry = "awaiting_retry"
+	DeadLetterRedriven      = "redriven"
+	DeadLetterExpired       = "expired"
+)
+
+// RetryRequest is the optional payload of the retry signal
+type RetryRequest struct {
+	RequestedBy string `json:"requested_by"`
+}
+
+// DeadLetterWorkflowID returns the workflow ID of the dead-letter workflow for a failed order run
+func DeadLetterWorkflowID(orderID, runID string) string {
+	return fmt.Sprintf("failed-order-%s-%s", orderID, runID)
+}
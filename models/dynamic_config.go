@@ -0,0 +1,72 @@
+package models
+
+import "time"
+
+// DynamicConfig holds business settings that operators can change while
+// workers run. Workflows read it through the GetConfig activity, so the values
+// an execution used are recorded in its history and replay identically.
+// Zero values disable the corresponding behavior.
+type DynamicConfig struct {
+	// HighValueApprovalThreshold is the order amount at or above which payment
+	// waits for an approve signal
+	HighValueApprovalThreshold float64 `json:"high_value_approval_threshold,omitempty"`
+	// ProcessingSLA is how long an order may take to reach a terminal status
+	// before it is flagged as breaching its SLA
+	ProcessingSLA time.Duration `json:"processing_sla,omitempty"`
+	// ExpeditedChannels lists the sales channels, as named in the order's
+	// memo, whose orders are expedited from the start
+	ExpeditedChannels []string `json:"expedited_channels,omitempty"`
+	// ParentClosePolicies sets, by child (ChildPayment, ChildFulfillment,
+	// ChildAnalytics, ChildArchive), what happens to a child workflow still running when
+	// the order closes. Children left unset keep their defaults.
+	ParentClosePolicies map[string]string `json:"parent_close_policies,omitempty"`
+	// AnalyticsExport starts a detached OrderAnalyticsWorkflow for each
+	// completed order
+	AnalyticsExport bool `json:"analytics_export,omitempty"`
+	// PaymentNexusEndpoint names the Nexus endpoint of the payments
+	// namespace; orders then pay through its payment service instead of a
+	// child workflow in their own namespace
+	PaymentNexusEndpoint string `json:"payment_nexus_endpoint,omitempty"`
+	// BackorderTimeout is how long an order with out-of-stock items waits
+	// to be restocked before it fails; zero fails it at once
+	BackorderTimeout time.Duration `json:"backorder_timeout,omitempty"`
+	// BackorderRecheckInterval is how often a backordered order checks stock
+	// itself; zero waits for a restock signal only
+	BackorderRecheckInterval time.Duration `json:"backorder_recheck_interval,omitempty"`
+	// EditGracePeriod is how long a paid order waits before fulfillment,
+	// accepting update and cancel signals; zero starts fulfillment at once
+	EditGracePeriod time.Duration `json:"edit_grace_period,omitempty"`
+	// TrackingPollInterval is how often a shipped order's TrackingWorkflow
+	// polls the carrier; the order stays open until the shipment is
+	// delivered. Zero closes the order untracked once it ships.
+	TrackingPollInterval time.Duration `json:"tracking_poll_interval,omitempty"`
+	// WarehouseRouting routes each order's items to the warehouses that
+	// stock them, shipping each warehouse's share separately
+	WarehouseRouting bool `json:"warehouse_routing,omitempty"`
+	// OrderArchival starts a detached OrderArchiveWorkflow for each completed
+	// order, which writes it to cold storage
+	OrderArchival bool `json:"order_archival,omitempty"`
+	// PIIRetention is how long an archived order keeps its personal data
+	// before it is archived again without; zero keeps it
+	PIIRetention time.Duration `json:"pii_retention,omitempty"`
+}
+
+// Child workflows of an order, as named in DynamicConfig.ParentClosePolicies
+const (
+	ChildPayment     = "payment"
+	ChildFulfillment = "fulfillment"
+	ChildAnalytics   = "analytics"
+	ChildArchive     = "archive"
+)
+
+// Parent close policies, as named in DynamicConfig.ParentClosePolicies
+const (
+	// ParentCloseTerminate terminates the child; the default of the payment
+	// and fulfillment children
+	ParentCloseTerminate = "terminate"
+	// ParentCloseRequestCancel asks the child to cancel and lets it clean up
+	ParentCloseRequestCancel = "request-cancel"
+	// ParentCloseAbandon leaves the child running, fully detached; the
+	// default of the analytics child
+	ParentCloseAbandon = "abandon"
+)
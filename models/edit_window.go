@@ -0,0 +1,33 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// OrderUpdate changes a paid order before it is fulfilled. Items replaces the
+// order's items; the amount paid is unchanged.
+type OrderUpdate struct {
+	Items []string `json:"items"`
+}
+
+// Validate checks that the update leaves the order with items
+func (u OrderUpdate) Validate() error {
+	if len(u.Items) == 0 {
+		return errors.New("items are required")
+	}
+	for _, item := range u.Items {
+		if item == "" {
+			return errors.New("items must not be empty")
+		}
+	}
+	return nil
+}
+
+// EditWindow is what the getEditWindow query returns: whether a paid order
+// still accepts update and cancel signals before fulfillment, and for how long
+type EditWindow struct {
+	Open      bool          `json:"open"`
+	ClosesAt  time.Time     `json:"closes_at,omitempty"`
+	Remaining time.Duration `json:"remaining"`
+}
@@ -0,0 +1,367 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.temporal.io/sdk/temporal"
+)
+
+// Application error types shared between activities and workflows. Activities
+// return these as the type of a temporal.ApplicationError so workflows can
+// branch on the failure kind after it crosses the Temporal boundary.
+const (
+	// ErrTypeServiceUnavailable indicates a downstream service is failing fast
+	// behind an open circuit breaker and should be retried with a longer backoff
+	ErrTypeServiceUnavailable = "ServiceUnavailable"
+	// ErrTypeServiceUnreachable indicates a downstream service could not be
+	// reached or failed on its side, answering with a 5xx status
+	ErrTypeServiceUnreachable = "ServiceUnreachable"
+	// ErrTypeValidationRejected indicates the order failed business validation
+	ErrTypeValidationRejected = "ValidationRejected"
+	// ErrTypePaymentDeclined indicates the payment was declined by the gateway
+	ErrTypePaymentDeclined = "PaymentDeclined"
+	// ErrTypeInventoryOutOfStock indicates one or more items are not in stock
+	ErrTypeInventoryOutOfStock = "InventoryOutOfStock"
+	// ErrTypePayloadTooLarge indicates a payload was over the configured size
+	// limit and was never sent to Temporal
+	ErrTypePayloadTooLarge = "PayloadTooLarge"
+	// ErrTypeInvoiceFileMissing indicates an invoice upload ran on a different
+	// host than the one that generated the file
+	ErrTypeInvoiceFileMissing = "InvoiceFileMissing"
+	// ErrTypeFraudSuspected indicates the fraud check rejected the order
+	ErrTypeFraudSuspected = "FraudSuspected"
+	// ErrTypeInstallmentPlanDefaulted indicates an installment stayed unpaid
+	// after every dunning retry
+	ErrTypeInstallmentPlanDefaulted = "InstallmentPlanDefaulted"
+	// ErrTypeLoyaltyRedemptionRejected indicates the loyalty service refused
+	// to redeem the points an order spends, such as for a short balance
+	ErrTypeLoyaltyRedemptionRejected = "LoyaltyRedemptionRejected"
+	// ErrTypeCustomerDenied indicates the customer matched a denylist or
+	// sanctions list during screening
+	ErrTypeCustomerDenied = "CustomerDenied"
+	// ErrTypeGatewayNotConfigured indicates payments cannot be reconciled
+	// because no payment gateway API is configured
+	ErrTypeGatewayNotConfigured = "GatewayNotConfigured"
+	// ErrTypeSettlementRejected indicates the payment gateway refused to
+	// settle a batch
+	ErrTypeSettlementRejected = "SettlementRejected"
+	// ErrTypeCurrencyUnsupported indicates no exchange rate is known for an
+	// order's currency
+	ErrTypeCurrencyUnsupported = "CurrencyUnsupported"
+	// ErrTypeOrderSchemaUnsupported indicates an order could not be upgraded
+	// to the current schema
+	ErrTypeOrderSchemaUnsupported = "OrderSchemaUnsupported"
+	// ErrTypeOrderInvalid indicates an order was missing fields every order
+	// needs, and was refused before any step ran
+	ErrTypeOrderInvalid = "OrderInvalid"
+	// ErrTypeTimeout indicates a step ran out of time, after any retries
+	ErrTypeTimeout = "Timeout"
+	// ErrTypeCancelled indicates a step was cancelled
+	ErrTypeCancelled = "Cancelled"
+	// ErrTypeInternal indicates any other failure, such as a bug or an
+	// unexpected response from a service
+	ErrTypeInternal = "Internal"
+)
+
+// errorTypes lists the error types OrderError codes are drawn from
+var errorTypes = []string{
+	ErrTypeServiceUnavailable, ErrTypeServiceUnreachable, ErrTypeValidationRejected, ErrTypePaymentDeclined, ErrTypeInventoryOutOfStock,
+	ErrTypePayloadTooLarge, ErrTypeInvoiceFileMissing, ErrTypeFraudSuspected, ErrTypeInstallmentPlanDefaulted,
+	ErrTypeLoyaltyRedemptionRejected, ErrTypeCustomerDenied, ErrTypeGatewayNotConfigured, ErrTypeSettlementRejected,
+	ErrTypeCurrencyUnsupported, ErrTypeOrderSchemaUnsupported, ErrTypeOrderInvalid,
+	ErrTypeTimeout, ErrTypeCancelled, ErrTypeInternal,
+}
+
+// OrderError is why an order failed, in one shape shared by every layer, so
+// callers branch on Code rather than parse messages. Code is one of the
+// ErrType constants and Stage, when known, the stage the order failed in.
+// Retryable reports whether submitting the order again may succeed, as it
+// may after a timeout but not after a declined card. Details hold the typed
+// error of the code, such as a PaymentDeclinedError, as JSON.
+//
+// The typed errors' ApplicationError methods carry their OrderError as the
+// second detail of the application error, after the typed error itself, and
+// OrderWorkflow fails with one for any other error.
+type OrderError struct {
+	Code      string          `json:"code"`
+	Stage     OrderStage      `json:"stage,omitempty"`
+	Retryable bool            `json:"retryable"`
+	Message   string          `json:"message"`
+	Details   json.RawMessage `json:"details,omitempty"`
+}
+
+func (e *OrderError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// DecodeDetails decodes the typed error in Details into v
+func (e *OrderError) DecodeDetails(v any) error {
+	if len(e.Details) == 0 {
+		return errors.New("order error has no details")
+	}
+	return json.Unmarshal(e.Details, v)
+}
+
+// ApplicationError wraps the error as a Temporal application error of type
+// Code, retryable only if Retryable, caused by cause and carrying Details and
+// itself as details
+func (e *OrderError) ApplicationError(cause error) error {
+	return temporal.NewApplicationErrorWithOptions(e.Message, e.Code, temporal.ApplicationErrorOptions{
+		NonRetryable: !e.Retryable,
+		Cause:        cause,
+		Details:      []any{e.Details, *e},
+	})
+}
+
+// AsOrderError reports whether err carries an OrderError in its first
+// application error, setting target to it if so
+func AsOrderError(err error, target *OrderError) bool {
+	var appErr *temporal.ApplicationError
+	if !errors.As(err, &appErr) {
+		return false
+	}
+	var typed any
+	var orderErr OrderError
+	if appErr.Details(&typed, &orderErr) != nil || orderErr.Code == "" {
+		return false
+	}
+	*target = orderErr
+	return true
+}
+
+// OrderErrorFrom describes err as an OrderError: the one it carries, if any,
+// or else one coded by its application error type, or as a timeout,
+// cancellation, or internal failure. It returns nil for a nil err.
+func OrderErrorFrom(err error) *OrderError {
+	if err == nil {
+		return nil
+	}
+	var orderErr OrderError
+	if AsOrderError(err, &orderErr) {
+		return &orderErr
+	}
+	var invalid *OrderInvalidError
+	if errors.As(err, &invalid) {
+		orderErr = newOrderError(ErrTypeOrderInvalid, StageValidation, invalid.Error(), *invalid)
+		return &orderErr
+	}
+
+	var appErr *temporal.ApplicationError
+	var timeoutErr *temporal.TimeoutError
+	var canceledErr *temporal.CanceledError
+	switch {
+	case errors.As(err, &appErr):
+		code := appErr.Type()
+		if !slices.Contains(errorTypes, code) {
+			code = ErrTypeInternal
+		}
+		return &OrderError{Code: code, Retryable: !appErr.NonRetryable(), Message: appErr.Message()}
+	case errors.As(err, &timeoutErr):
+		return &OrderError{Code: ErrTypeTimeout, Retryable: true, Message: timeoutErr.Error()}
+	case errors.As(err, &canceledErr):
+		return &OrderError{Code: ErrTypeCancelled, Message: canceledErr.Error()}
+	default:
+		return &OrderError{Code: ErrTypeInternal, Retryable: true, Message: err.Error()}
+	}
+}
+
+// newOrderError returns the OrderError of a typed error; typed errors are
+// final, so it is not retryable
+func newOrderError(code string, stage OrderStage, message string, typed any) OrderError {
+	// The typed errors are plain structs, which always marshal
+	details, _ := json.Marshal(typed)
+	return OrderError{Code: code, Stage: stage, Message: message, Details: details}
+}
+
+// orderApplicationError wraps a typed error as a non-retryable Temporal
+// application error carrying the typed error and its OrderError as details
+func orderApplicationError(code string, stage OrderStage, message string, typed any) error {
+	return temporal.NewNonRetryableApplicationError(message, code, nil, typed, newOrderError(code, stage, message, typed))
+}
+
+// ValidationRejectedError is returned when validation rejects an order
+type ValidationRejectedError struct {
+	OrderID string `json:"order_id"`
+	Reason  string `json:"reason"`
+}
+
+func (e *ValidationRejectedError) Error() string {
+	return fmt.Sprintf("order validation failed: %s", e.Reason)
+}
+
+// ApplicationError wraps the error as a non-retryable Temporal application error
+// carrying itself and its OrderError as details
+func (e *ValidationRejectedError) ApplicationError() error {
+	return orderApplicationError(ErrTypeValidationRejected, StageValidation, e.Error(), *e)
+}
+
+// PaymentDeclinedError is returned when the payment gateway declines a payment
+type PaymentDeclinedError struct {
+	OrderID     string  `json:"order_id"`
+	Amount      float64 `json:"amount"`
+	DeclineCode string  `json:"decline_code"`
+	Reason      string  `json:"reason"`
+}
+
+func (e *PaymentDeclinedError) Error() string {
+	return fmt.Sprintf("payment declined (%s): %s", e.DeclineCode, e.Reason)
+}
+
+// ApplicationError wraps the error as a non-retryable Temporal application error
+// carrying itself and its OrderError as details
+func (e *PaymentDeclinedError) ApplicationError() error {
+	return orderApplicationError(ErrTypePaymentDeclined, StagePayment, e.Error(), *e)
+}
+
+// InventoryOutOfStockError is returned when items in an order are out of stock
+type InventoryOutOfStockError struct {
+	OrderID string   `json:"order_id"`
+	Items   []string `json:"items"`
+}
+
+func (e *InventoryOutOfStockError) Error() string {
+	return fmt.Sprintf("items out of stock: %s", strings.Join(e.Items, ", "))
+}
+
+// ApplicationError wraps the error as a non-retryable Temporal application error
+// carrying itself and its OrderError as details
+func (e *InventoryOutOfStockError) ApplicationError() error {
+	return orderApplicationError(ErrTypeInventoryOutOfStock, StageProcessing, e.Error(), *e)
+}
+
+// PayloadTooLargeError is returned when a workflow, signal, or activity payload
+// is over the size limit enforced by the payload guard interceptor
+type PayloadTooLargeError struct {
+	Operation string `json:"operation"`
+	Size      int    `json:"size"`
+	Limit     int    `json:"limit"`
+}
+
+func (e *PayloadTooLargeError) Error() string {
+	return fmt.Sprintf("%s payload is %d bytes, over the %d byte limit", e.Operation, e.Size, e.Limit)
+}
+
+// ApplicationError wraps the error as a non-retryable Temporal application error
+// carrying itself and its OrderError as details
+func (e *PayloadTooLargeError) ApplicationError() error {
+	return orderApplicationError(ErrTypePayloadTooLarge, "", e.Error(), *e)
+}
+
+// FraudSuspectedError is returned when the fraud check rejects an order.
+// CustomerID is set when the order carries its customer.
+type FraudSuspectedError struct {
+	OrderID    string  `json:"order_id"`
+	CustomerID string  `json:"customer_id,omitempty"`
+	Score      float64 `json:"score"`
+	Reason     string  `json:"reason"`
+}
+
+func (e *FraudSuspectedError) Error() string {
+	return fmt.Sprintf("order suspected of fraud: %s", e.Reason)
+}
+
+// ApplicationError wraps the error as a non-retryable Temporal application error
+// carrying itself and its OrderError as details
+func (e *FraudSuspectedError) ApplicationError() error {
+	return orderApplicationError(ErrTypeFraudSuspected, StageValidation, e.Error(), *e)
+}
+
+// InstallmentPlanDefaultedError is returned when an installment plan
+// defaults, and is the payload of the signal that tells the order so
+type InstallmentPlanDefaultedError struct {
+	OrderID     string  `json:"order_id"`
+	Installment int     `json:"installment"`
+	Amount      float64 `json:"amount"`
+	Attempts    int     `json:"attempts"`
+	Reason      string  `json:"reason"`
+}
+
+func (e *InstallmentPlanDefaultedError) Error() string {
+	return fmt.Sprintf("installment plan defaulted on installment %d after %d attempts: %s", e.Installment, e.Attempts, e.Reason)
+}
+
+// ApplicationError wraps the error as a non-retryable Temporal application error
+// carrying itself and its OrderError as details
+func (e *InstallmentPlanDefaultedError) ApplicationError() error {
+	return orderApplicationError(ErrTypeInstallmentPlanDefaulted, StagePayment, e.Error(), *e)
+}
+
+// LoyaltyRedemptionRejectedError is returned when the loyalty service refuses
+// to redeem an order's points
+type LoyaltyRedemptionRejectedError struct {
+	OrderID    string `json:"order_id"`
+	CustomerID string `json:"customer_id"`
+	Points     int    `json:"points"`
+	Reason     string `json:"reason"`
+}
+
+func (e *LoyaltyRedemptionRejectedError) Error() string {
+	return fmt.Sprintf("loyalty redemption of %d points rejected: %s", e.Points, e.Reason)
+}
+
+// ApplicationError wraps the error as a non-retryable Temporal application error
+// carrying itself and its OrderError as details
+func (e *LoyaltyRedemptionRejectedError) ApplicationError() error {
+	return orderApplicationError(ErrTypeLoyaltyRedemptionRejected, StageValidation, e.Error(), *e)
+}
+
+// CustomerDeniedError is returned when customer screening finds the
+// customer on a denylist or sanctions list
+type CustomerDeniedError struct {
+	OrderID    string `json:"order_id"`
+	CustomerID string `json:"customer_id"`
+	List       string `json:"list"`
+	Reason     string `json:"reason"`
+}
+
+func (e *CustomerDeniedError) Error() string {
+	return fmt.Sprintf("customer %s matched the %s list: %s", e.CustomerID, e.List, e.Reason)
+}
+
+// ApplicationError wraps the error as a non-retryable Temporal application error
+// carrying itself and its OrderError as details
+func (e *CustomerDeniedError) ApplicationError() error {
+	return orderApplicationError(ErrTypeCustomerDenied, StageValidation, e.Error(), *e)
+}
+
+// OrderSchemaError is returned when an order cannot be upgraded to the
+// current schema, such as one written by a newer build
+type OrderSchemaError struct {
+	OrderID string `json:"order_id"`
+	Reason  string `json:"reason"`
+}
+
+func (e *OrderSchemaError) Error() string {
+	return fmt.Sprintf("order schema not supported: %s", e.Reason)
+}
+
+// ApplicationError wraps the error as a non-retryable Temporal application error
+// carrying itself and its OrderError as details
+func (e *OrderSchemaError) ApplicationError() error {
+	return orderApplicationError(ErrTypeOrderSchemaUnsupported, StageValidation, e.Error(), *e)
+}
+
+// OrderInvalidError is returned by Order.Validate for an order missing fields
+// every order needs, listing each field at fault
+type OrderInvalidError struct {
+	OrderID string       `json:"order_id"`
+	Fields  []FieldError `json:"fields"`
+}
+
+func (e *OrderInvalidError) Error() string {
+	problems := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		problems[i] = field.Error()
+	}
+	return fmt.Sprintf("invalid order: %s", strings.Join(problems, "; "))
+}
+
+// ApplicationError wraps the error as a non-retryable Temporal application error
+// carrying itself and its OrderError as details
+func (e *OrderInvalidError) ApplicationError() error {
+	return orderApplicationError(ErrTypeOrderInvalid, StageValidation, e.Error(), *e)
+}
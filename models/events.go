@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// OrderEventSchemaVersion is the version of the OrderEvent JSON schema.
+// Bump it whenever a field is removed or changes meaning so consumers can
+// branch on the version they receive.
+const OrderEventSchemaVersion = 1
+
+// Order lifecycle event types
+const (
+	EventOrderCreated   = "order.created"
+	EventOrderPaid      = "order.paid"
+	EventOrderCompleted = "order.completed"
+	EventOrderFailed    = "order.failed"
+)
+
+// OrderEvent is the message published to the order event stream
+type OrderEvent struct {
+	SchemaVersion int             `json:"schema_version"`
+	EventID       string          `json:"event_id"`
+	Type          string          `json:"type"`
+	OrderID       string          `json:"order_id"`
+	WorkflowID    string          `json:"workflow_id"`
+	Status        OrderStatusCode `json:"status"`
+	Stage         OrderStage      `json:"stage"`
+	Amount        float64         `json:"amount"`
+	Items         []string        `json:"items"`
+	TransactionID string          `json:"transaction_id,omitempty"`
+	Reason        string          `json:"reason,omitempty"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+}
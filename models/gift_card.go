@@ -0,0 +1,18 @@
+package models
+
+// GiftCardHoldRequest asks for an amount of a gift card's balance to be held
+// for an order
+type GiftCardHoldRequest struct {
+	OrderID string  `json:"order_id"`
+	Card    string  `json:"card"`
+	Amount  float64 `json:"amount"`
+}
+
+// GiftCardHold is a held amount of a gift card's balance, which is either
+// captured once the order is fulfilled or released
+type GiftCardHold struct {
+	HoldID  string  `json:"hold_id"`
+	OrderID string  `json:"order_id"`
+	Card    string  `json:"card"`
+	Amount  float64 `json:"amount"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// InstallmentPlan is the input of InstallmentPaymentWorkflow: the order
+// amount charged in Installments payments, one every Interval, the first at
+// once. A failed installment is retried DunningRetries times, DunningInterval
+// apart, before the plan defaults. Installments charge the order's
+// PaymentMethod.
+type InstallmentPlan struct {
+	OrderID         string         `json:"order_id"`
+	Amount          float64        `json:"amount"`
+	Installments    int            `json:"installments"`
+	Interval        time.Duration  `json:"interval"`
+	DunningRetries  int            `json:"dunning_retries"`
+	DunningInterval time.Duration  `json:"dunning_interval"`
+	PaymentMethod   *PaymentMethod `json:"payment_method,omitempty"`
+}
+
+// Amounts splits the plan amount into its installments. The split is in
+// cents; cents that do not divide evenly go to the earliest installments.
+func (p InstallmentPlan) Amounts() []float64 {
+	if p.Installments <= 0 {
+		return nil
+	}
+	cents := int64(math.Round(p.Amount * 100))
+	base, remainder := cents/int64(p.Installments), cents%int64(p.Installments)
+	amounts := make([]float64, p.Installments)
+	for i := range amounts {
+		share := base
+		if int64(i) < remainder {
+			share++
+		}
+		amounts[i] = float64(share) / 100
+	}
+	return amounts
+}
+
+// InstallmentPayment is one captured installment. Attempts counts the
+// charges it took, dunning retries included.
+type InstallmentPayment struct {
+	Number        int       `json:"number"`
+	Amount        float64   `json:"amount"`
+	TransactionID string    `json:"transaction_id"`
+	Attempts      int       `json:"attempts"`
+	PaidAt        time.Time `json:"paid_at"`
+}
+
+// InstallmentPlanResult is what InstallmentPaymentWorkflow returns once
+// every installment is paid
+type InstallmentPlanResult struct {
+	OrderID  string               `json:"order_id"`
+	Payments []InstallmentPayment `json:"payments"`
+}
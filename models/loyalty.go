@@ -0,0 +1,20 @@
+package models
+
+// LoyaltyRequest asks the loyalty service to change a customer's points for
+// an order. Points are redeemed as given; points awarded are earned on Amount.
+type LoyaltyRequest struct {
+	OrderID    string  `json:"order_id"`
+	CustomerID string  `json:"customer_id"`
+	Points     int     `json:"points,omitempty"`
+	Amount     float64 `json:"amount,omitempty"`
+}
+
+// LoyaltyTransaction is a change to a customer's points balance recorded by
+// the loyalty service. Points are positive when awarded and negative when
+// redeemed, so reversing a transaction applies -Points.
+type LoyaltyTransaction struct {
+	TransactionID string `json:"transaction_id"`
+	OrderID       string `json:"order_id"`
+	CustomerID    string `json:"customer_id"`
+	Points        int    `json:"points"`
+}
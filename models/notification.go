@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// OrderNotification is the completion notification sent to a customer on
+// one channel, email or sms. Recipient is the customer's contact for the
+// channel, empty for a guest order. The shipping fields say how the order is
+// delivered and by when it was promised, when its shipping is known.
+type OrderNotification struct {
+	OrderID        string    `json:"order_id"`
+	Channel        string    `json:"channel"`
+	Recipient      string    `json:"recipient,omitempty"`
+	ShippingMethod string    `json:"shipping_method,omitempty"`
+	Carrier        string    `json:"carrier,omitempty"`
+	PromisedBy     time.Time `json:"promised_by,omitzero"`
+}
+
+// NewOrderNotification returns the completion notification of the order on
+// channel
+func NewOrderNotification(order Order, channel string) OrderNotification {
+	notification := OrderNotification{OrderID: order.ID, Channel: channel}
+	if order.Customer != nil {
+		notification.Recipient = order.Customer.Contact(channel)
+	}
+	if order.Shipping != nil {
+		notification.ShippingMethod = order.Shipping.Method
+		notification.Carrier = order.Shipping.Carrier
+		notification.PromisedBy = order.Shipping.PromisedBy
+	}
+	return notification
+}
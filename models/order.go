@@ -1,72 +1,377 @@
 package models
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
 
-// Order represents an order in the system
+// Order represents an order in the system.
+// FulfillmentParallelism caps how many items are fulfilled at once; zero uses
+// the workflow default. It is part of the input, rather than worker
+// configuration, so replays always see the same value.
+// Tenders split the payment across several methods, charged in order; when
+// empty the whole amount is charged to one method.
+// Installments, when above one, pays the amount in that many scheduled
+// installments instead of at once.
+// RedeemPoints are loyalty points the customer spends on the order; Amount
+// is what remains to be paid after them.
+// Vendors maps the order's marketplace items to the vendors that dropship
+// them; the order's own warehouses fulfill the other items.
+// Currency is the ISO 4217 code of Amount and the tenders; empty is the
+// worker's base currency, which payments are charged in.
+// Customer is who placed the order and how to reach them; nil for a guest
+// order, whose customer, if any, is only in the workflow memo.
+// SchemaVersion is the version of the schema the order was written in, and
+// Lines the structured lines Items and Amount are derived from; see Upgrade.
+// PaymentMethod is how the order is paid, unless a tender says otherwise;
+// nil charges the gateway's default method.
+// Shipping is how and where the order is delivered, and by when it was
+// promised; nil ships it standard, with no promise, to an address kept
+// outside the order.
+// PriorPayment is the payment an order re-driven from the dead-letter queue
+// was already charged, which the re-driven run keeps instead of charging
+// again. It is ignored unless the run was started by the order's dead-letter
+// workflow.
 type Order struct {
-	ID        string    `json:"id"`
-	Items     []string  `json:"items"`
-	Amount    float64   `json:"amount"`
-	Status    string    `json:"status"`
-	CreatedAt time.Time `json:"created_at"`
+	ID                     string            `json:"id"`
+	Items                  []string          `json:"items"`
+	Amount                 float64           `json:"amount"`
+	Status                 OrderStatusCode   `json:"status"`
+	CreatedAt              time.Time         `json:"created_at"`
+	FulfillmentParallelism int               `json:"fulfillment_parallelism,omitempty"`
+	Tenders                []Tender          `json:"tenders,omitempty"`
+	Installments           int               `json:"installments,omitempty"`
+	RedeemPoints           int               `json:"redeem_points,omitempty"`
+	Vendors                map[string]string `json:"vendors,omitempty"`
+	Currency               string            `json:"currency,omitempty"`
+	Customer               *Customer         `json:"customer,omitempty"`
+	SchemaVersion          int               `json:"schema_version,omitempty"`
+	Lines                  []OrderItem       `json:"lines,omitempty"`
+	PaymentMethod          *PaymentMethod    `json:"payment_method,omitempty"`
+	Shipping               *ShippingInfo     `json:"shipping,omitempty"`
+	PriorPayment           *PaymentResponse  `json:"prior_payment,omitempty"`
+}
+
+// Payment methods of a tender
+const (
+	TenderGiftCard = "gift_card"
+	TenderCard     = "card"
+)
+
+// Tender is one payment method paying part of an order. Card identifies the
+// gift card of a gift card tender; its balance is then held at validation
+// and captured once the order is fulfilled, rather than charged at payment.
+type Tender struct {
+	Method string  `json:"method"`
+	Amount float64 `json:"amount"`
+	Card   string  `json:"card,omitempty"`
+}
+
+// HeldOnGiftCard reports whether the tender is paid from a gift card
+// balance hold rather than charged at payment
+func (t Tender) HeldOnGiftCard() bool {
+	return t.Method == TenderGiftCard && t.Card != ""
+}
+
+// ValidateTenders checks that the order's tenders, if any, are positive and
+// add up to the order amount
+func (o Order) ValidateTenders() error {
+	if len(o.Tenders) == 0 {
+		return nil
+	}
+	var total float64
+	for i, tender := range o.Tenders {
+		if tender.Method == "" {
+			return fmt.Errorf("tenders[%d].method is required", i)
+		}
+		if tender.Amount <= 0 {
+			return fmt.Errorf("tenders[%d].amount must be positive", i)
+		}
+		if tender.Card != "" && tender.Method != TenderGiftCard {
+			return fmt.Errorf("tenders[%d].card is only for %s tenders", i, TenderGiftCard)
+		}
+		total += tender.Amount
+	}
+	// Compared in cents, so float rounding of the sum does not matter
+	if math.Round(total*100) != math.Round(o.Amount*100) {
+		return fmt.Errorf("tenders add up to %.2f, not the order amount %.2f", total, o.Amount)
+	}
+	return nil
+}
+
+// ValidateInstallments checks that the order's installment count, if any, is
+// not negative and is not combined with split tenders
+func (o Order) ValidateInstallments() error {
+	if o.Installments < 0 {
+		return errors.New("installments must not be negative")
+	}
+	if o.Installments > 1 && len(o.Tenders) > 0 {
+		return errors.New("installments cannot be combined with tenders")
+	}
+	return nil
+}
+
+// OrderWorkflowID returns the workflow ID of the order workflow for an order.
+// Starting orders under this ID is what makes a repeated start idempotent.
+func OrderWorkflowID(orderID string) string {
+	return fmt.Sprintf("order-workflow-%s", orderID)
 }
 
-// OrderStatus represents the current state of an order
+// Memo keys of the business context attached to order workflows at start.
+// Memos are not encoded like payloads, so the Web UI shows them even when
+// payloads are encrypted.
+const (
+	MemoCustomerID = "customer_id"
+	MemoChannel    = "channel"
+	MemoRegion     = "region"
+)
+
+// OrderMemo is the business context of an order: who placed it, through
+// which sales channel, and in which region. It travels in the workflow memo
+// rather than the order, so it is visible without decoding the input.
+type OrderMemo struct {
+	CustomerID string `json:"customer_id,omitempty"`
+	Channel    string `json:"channel,omitempty"`
+	Region     string `json:"region,omitempty"`
+}
+
+// Fields returns the memo entries of m, omitting empty values
+func (m OrderMemo) Fields() map[string]any {
+	fields := make(map[string]any)
+	for key, value := range map[string]string{MemoCustomerID: m.CustomerID, MemoChannel: m.Channel, MemoRegion: m.Region} {
+		if value != "" {
+			fields[key] = value
+		}
+	}
+	return fields
+}
+
+// OrderItem is a structured order line, as in Order.Lines and the starter's
+// -items-file. Order.Items lists each unit as one entry, so a line of Quantity 3 becomes
+// three entries of SKU, and the prices add up to the order amount. A line
+// with a Vendor is a marketplace item that vendor dropships.
+type OrderItem struct {
+	SKU      string  `json:"sku"`
+	Quantity int     `json:"qty"`
+	Price    float64 `json:"price"`
+	Vendor   string  `json:"vendor,omitempty"`
+}
+
+// OrderStatus represents the current state of an order.
+// ItemResults tracks per-item fulfillment when items are processed in parallel.
+// InvoiceURL is where the uploaded invoice is stored, once generated.
+// SLABreached is set once the order outlives its processing SLA, and
+// PromiseMissed once it is not delivered by its shipping's PromisedBy.
+// ShipmentStatus is the carrier's status of the shipped order, while its
+// shipment is tracked. Shipments lists the shipments of an order fulfilled
+// from several warehouses, and ShipmentStatus then aggregates theirs.
+// Vendors lists the purchase orders of dropshipped items.
 type OrderStatus struct {
-	OrderID       string    `json:"order_id"`
-	Status        string    `json:"status"`
-	Stage         string    `json:"stage"`
-	IsExpedited   bool      `json:"is_expedited"`
-	PaymentStatus string    `json:"payment_status"`
-	LastUpdated   time.Time `json:"last_updated"`
+	OrderID                string              `json:"order_id"`
+	Status                 OrderStatusCode     `json:"status"`
+	Stage                  OrderStage          `json:"stage"`
+	IsExpedited            bool                `json:"is_expedited"`
+	PaymentStatus          string              `json:"payment_status"`
+	ProvisionallyValidated bool                `json:"provisionally_validated,omitempty"`
+	ItemResults            []ItemFulfillment   `json:"item_results,omitempty"`
+	InvoiceURL             string              `json:"invoice_url,omitempty"`
+	SLABreached            bool                `json:"sla_breached,omitempty"`
+	PromiseMissed          bool                `json:"promise_missed,omitempty"`
+	ShipmentStatus         string              `json:"shipment_status,omitempty"`
+	Shipments              []OrderShipment     `json:"shipments,omitempty"`
+	Vendors                []VendorFulfillment `json:"vendors,omitempty"`
+	LastUpdated            time.Time           `json:"last_updated"`
+}
+
+// OrderResult is what OrderWorkflow returns when the order closes without
+// error, whether completed, partially completed, or cancelled.
+// TransactionID is set once payment is captured, and ShipmentID once items
+// are packed for shipment, comma-separated when they ship from several
+// warehouses. Duration runs from the workflow's start to its close.
+type OrderResult struct {
+	OrderID       string          `json:"order_id"`
+	Status        OrderStatusCode `json:"status"`
+	PaymentStatus string          `json:"payment_status"`
+	TransactionID string          `json:"transaction_id,omitempty"`
+	ShipmentID    string          `json:"shipment_id,omitempty"`
+	Duration      time.Duration   `json:"duration"`
+}
+
+// ShipmentID returns the ID of the shipment carrying an order's items
+func ShipmentID(orderID string) string {
+	return fmt.Sprintf("SHIP-%s", orderID)
 }
 
-// ValidationRequest represents a request to validate an order
+// StatusChange records when an order entered a status and stage, as returned
+// by the getHistory query
+type StatusChange struct {
+	Status OrderStatusCode `json:"status"`
+	Stage  OrderStage      `json:"stage"`
+	At     time.Time       `json:"at"`
+}
+
+// ItemFulfillment is the fulfillment outcome of a single order item.
+// Step is the last fulfillment step completed when the item ran as its own
+// child workflow.
+type ItemFulfillment struct {
+	Item   string `json:"item"`
+	Status string `json:"status"`
+	Step   string `json:"step,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ItemFulfillmentRequest is the input of the per-item fulfillment child
+// workflow and its steps. WarehouseID is set when the item ships from a
+// warehouse chosen by SelectWarehouse.
+type ItemFulfillmentRequest struct {
+	OrderID     string `json:"order_id"`
+	Item        string `json:"item"`
+	IsExpedited bool   `json:"is_expedited"`
+	WarehouseID string `json:"warehouse_id,omitempty"`
+}
+
+// ValidationRequest represents a request to validate an order.
+// Customer is sent when the order carries one, so the service can check it.
 type ValidationRequest struct {
-	OrderID string  `json:"order_id"`
-	Amount  float64 `json:"amount"`
-	Items   []string `json:"items"`
+	OrderID  string    `json:"order_id"`
+	Amount   float64   `json:"amount"`
+	Items    []string  `json:"items"`
+	Customer *Customer `json:"customer,omitempty"`
 }
 
-// ValidationResponse represents the response from validation service
+// ValidationResponse represents the response from validation service.
+// Provisional is set when the verdict came from the local rules fallback.
 type ValidationResponse struct {
-	Valid   bool   `json:"valid"`
-	Message string `json:"message"`
+	Valid       bool   `json:"valid"`
+	Message     string `json:"message"`
+	Provisional bool   `json:"provisional,omitempty"`
+}
+
+// ProcessingProgress is the checkpoint ProcessOrder records in its heartbeats
+// so a retried attempt can resume where the previous one stopped
+type ProcessingProgress struct {
+	ItemsProcessed    int     `json:"items_processed"`
+	LastProcessedItem string  `json:"last_processed_item,omitempty"`
+	PercentComplete   float64 `json:"percent_complete"`
+}
+
+// InvoiceRequest is the input of GenerateInvoice
+type InvoiceRequest struct {
+	OrderID       string   `json:"order_id"`
+	Items         []string `json:"items"`
+	Amount        float64  `json:"amount"`
+	TransactionID string   `json:"transaction_id"`
+}
+
+// FraudCheckResult is the outcome of screening an order for fraud. Score is
+// the order's risk relative to the rejection threshold; above 1 is rejected.
+type FraudCheckResult struct {
+	Score float64 `json:"score"`
+}
+
+// ScreeningRequest asks ScreenCustomer to check an order's customer against
+// the denylist and sanctions lists
+type ScreeningRequest struct {
+	OrderID    string `json:"order_id"`
+	CustomerID string `json:"customer_id"`
 }
 
-// PaymentRequest represents a payment processing request
+// ScreeningResult is the outcome of screening a customer, as returned by the
+// screening API: whether the customer matched a list, which one, and why
+type ScreeningResult struct {
+	Match  bool   `json:"match"`
+	List   string `json:"list,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// PaymentRequest represents a payment processing request.
+// Method is the tender being charged; empty charges the default method.
+// PaymentMethod, when set, is the card, wallet, bank account, or gift card
+// charged, which the gateway routes the payment by.
 type PaymentRequest struct {
-	OrderID string  `json:"order_id"`
-	Amount  float64 `json:"amount"`
+	OrderID       string         `json:"order_id"`
+	Amount        float64        `json:"amount"`
+	Method        string         `json:"method,omitempty"`
+	PaymentMethod *PaymentMethod `json:"payment_method,omitempty"`
 }
 
-// PaymentResponse represents a payment processing response
+// PaymentResponse represents a payment processing response.
+// A split-tender payment lists the capture of each tender in Tenders, and its
+// TransactionID joins theirs. Conversion records the exchange rate an order
+// in another currency was charged at. Route is the network a payment with a
+// payment method went through; see PaymentMethod.Route.
 type PaymentResponse struct {
-	Success       bool   `json:"success"`
-	TransactionID string `json:"transaction_id"`
-	Message       string `json:"message"`
+	Success       bool                `json:"success"`
+	TransactionID string              `json:"transaction_id"`
+	Message       string              `json:"message"`
+	Route         string              `json:"route,omitempty"`
+	Tenders       []TenderPayment     `json:"tenders,omitempty"`
+	Conversion    *CurrencyConversion `json:"conversion,omitempty"`
+}
+
+// TenderPayment is the capture of one tender of a split-tender payment
+type TenderPayment struct {
+	Method        string  `json:"method"`
+	Amount        float64 `json:"amount"`
+	TransactionID string  `json:"transaction_id"`
+}
+
+// RefundRequest reverses a captured payment, as when an order is cancelled
+// after payment
+type RefundRequest struct {
+	OrderID       string  `json:"order_id"`
+	TransactionID string  `json:"transaction_id"`
+	Amount        float64 `json:"amount"`
 }
 
 // Signal types
 const (
 	SignalCancel   = "cancel"
 	SignalExpedite = "expedite"
+	SignalRetry    = "retry"
+	SignalApprove  = "approve"
+	SignalRestock  = "restock"
+
+	// SignalUpdate carries an OrderUpdate, honored while the order's edit
+	// window is open
+	SignalUpdate = "update"
+
+	// SignalInstallmentDefault is sent to the order by its installment plan
+	// when the plan defaults
+	SignalInstallmentDefault = "installment-default"
+)
+
+// ProtectedSignals are the signals that change an order on a caller's
+// behalf or report a child workflow's outcome to it, dropped unless signed
+// when signal authorization is on; child workflows sign theirs as they send
+// them. The worker and the replayer both protect this list, so replays
+// verify what the worker verified.
+var ProtectedSignals = []string{
+	SignalCancel, SignalExpedite, SignalRetry, SignalApprove, SignalRestock, SignalUpdate, SignalVerify, SignalCheckoutCompleted, SignalVendorResponse,
+	SignalShipmentUpdate, SignalVendorUpdate, SignalInstallmentDefault,
+}
+
+// Custom search attributes set on order workflows when the search-attributes
+// feature flag is on; both must be registered on the namespace first
+const (
+	// SearchAttributeOrderStatus is a Keyword holding the order status
+	SearchAttributeOrderStatus = "OrderStatus"
+	// SearchAttributeOrderExpedited is a Bool set when the order is expedited
+	SearchAttributeOrderExpedited = "OrderExpedited"
 )
 
-// Order statuses
+// Item fulfillment statuses
 const (
-	StatusPending    = "pending"
-	StatusValidating = "validating"
-	StatusProcessing = "processing"
-	StatusCompleted  = "completed"
-	StatusCancelled  = "cancelled"
-	StatusFailed     = "failed"
+	ItemPending   = "pending"
+	ItemFulfilled = "fulfilled"
+	ItemFailed    = "failed"
 )
 
-// Stages
+// Item fulfillment steps, in order
 const (
-	StageValidation = "validation"
-	StagePayment    = "payment"
-	StageProcessing = "processing"
-	StageCompleted  = "completed"
+	ItemStepReserved = "reserved"
+	ItemStepPicked   = "picked"
+	ItemStepPacked   = "packed"
 )
@@ -0,0 +1,176 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Payment method types. A card or gift card method charges the tender of the
+// same name.
+const (
+	PaymentMethodCard         = TenderCard
+	PaymentMethodWallet       = "wallet"
+	PaymentMethodBankTransfer = "bank_transfer"
+	PaymentMethodGiftCard     = TenderGiftCard
+)
+
+// PaymentMethodTypes lists the payment method types
+var PaymentMethodTypes = []string{PaymentMethodCard, PaymentMethodWallet, PaymentMethodBankTransfer, PaymentMethodGiftCard}
+
+// WalletProviders lists the providers a wallet method may name
+var WalletProviders = []string{"apple_pay", "google_pay", "paypal"}
+
+// PaymentMethod is how a payment is made: one of a tokenized card, a wallet,
+// a bank transfer, or a gift card, named by Type, whose field of the same
+// name holds its details. Tokens, account numbers, and card numbers are
+// secrets: their JSON names are among the fields redacted from logs and
+// encrypted by field encryption by default.
+type PaymentMethod struct {
+	Type         string              `json:"type"`
+	Card         *CardMethod         `json:"card,omitempty"`
+	Wallet       *WalletMethod       `json:"wallet,omitempty"`
+	BankTransfer *BankTransferMethod `json:"bank_transfer,omitempty"`
+	GiftCard     *GiftCardMethod     `json:"gift_card,omitempty"`
+}
+
+// CardMethod is a card tokenized by the payment gateway; the card number
+// itself never reaches the workflow
+type CardMethod struct {
+	PaymentToken string `json:"payment_token"`
+	Brand        string `json:"brand,omitempty"`
+	Last4        string `json:"last4,omitempty"`
+	ExpMonth     int    `json:"exp_month,omitempty"`
+	ExpYear      int    `json:"exp_year,omitempty"`
+}
+
+// WalletMethod is a payment token issued by a wallet provider, such as
+// apple_pay
+type WalletMethod struct {
+	Provider     string `json:"provider"`
+	PaymentToken string `json:"payment_token"`
+}
+
+// BankTransferMethod debits a bank account
+type BankTransferMethod struct {
+	AccountHolder string `json:"account_holder"`
+	AccountNumber string `json:"account_number"`
+	RoutingNumber string `json:"routing_number"`
+}
+
+// GiftCardMethod charges a gift card
+type GiftCardMethod struct {
+	CardNumber string `json:"card_number"`
+}
+
+// Validate checks that the method is of a known type, has the details of
+// that type and no other, and that those details are complete
+func (m PaymentMethod) Validate() error {
+	if fields := m.fieldErrors("payment_method"); len(fields) > 0 {
+		return fields[0]
+	}
+	return nil
+}
+
+// fieldErrors lists what Validate finds wrong with the method, naming fields
+// under path
+func (m PaymentMethod) fieldErrors(path string) []FieldError {
+	var fields []FieldError
+	invalid := func(field, format string, args ...any) {
+		fields = append(fields, FieldError{Field: path + "." + field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	given := map[string]bool{
+		PaymentMethodCard:         m.Card != nil,
+		PaymentMethodWallet:       m.Wallet != nil,
+		PaymentMethodBankTransfer: m.BankTransfer != nil,
+		PaymentMethodGiftCard:     m.GiftCard != nil,
+	}
+	if !slices.Contains(PaymentMethodTypes, m.Type) {
+		invalid("type", "must be one of %s, got %q", strings.Join(PaymentMethodTypes, ", "), m.Type)
+		return fields
+	}
+	for _, name := range PaymentMethodTypes {
+		switch {
+		case name == m.Type && !given[name]:
+			invalid(name, "is required for a %s method", m.Type)
+		case name != m.Type && given[name]:
+			invalid(name, "is not allowed for a %s method", m.Type)
+		}
+	}
+
+	switch {
+	case m.Type == PaymentMethodCard && m.Card != nil:
+		if m.Card.PaymentToken == "" {
+			invalid("card.payment_token", "is required")
+		}
+		if m.Card.Last4 != "" && !isDigits(m.Card.Last4, 4, 4) {
+			invalid("card.last4", "must be 4 digits")
+		}
+		if m.Card.ExpMonth != 0 && (m.Card.ExpMonth < 1 || m.Card.ExpMonth > 12) {
+			invalid("card.exp_month", "must be between 1 and 12")
+		}
+	case m.Type == PaymentMethodWallet && m.Wallet != nil:
+		if !slices.Contains(WalletProviders, m.Wallet.Provider) {
+			invalid("wallet.provider", "must be one of %s, got %q", strings.Join(WalletProviders, ", "), m.Wallet.Provider)
+		}
+		if m.Wallet.PaymentToken == "" {
+			invalid("wallet.payment_token", "is required")
+		}
+	case m.Type == PaymentMethodBankTransfer && m.BankTransfer != nil:
+		if m.BankTransfer.AccountHolder == "" {
+			invalid("bank_transfer.account_holder", "is required")
+		}
+		if !isDigits(m.BankTransfer.AccountNumber, 4, 17) {
+			invalid("bank_transfer.account_number", "must be 4 to 17 digits")
+		}
+		if !isDigits(m.BankTransfer.RoutingNumber, 9, 9) {
+			invalid("bank_transfer.routing_number", "must be 9 digits")
+		}
+	case m.Type == PaymentMethodGiftCard && m.GiftCard != nil:
+		if m.GiftCard.CardNumber == "" {
+			invalid("gift_card.card_number", "is required")
+		}
+	}
+	return fields
+}
+
+// Route is the network the payment gateway sends a payment of the method
+// through: the card network, the wallet provider, ach, or gift_card
+func (m PaymentMethod) Route() string {
+	switch {
+	case m.Card != nil && m.Card.Brand != "":
+		return strings.ToLower(m.Card.Brand)
+	case m.Wallet != nil:
+		return m.Wallet.Provider
+	case m.Type == PaymentMethodBankTransfer:
+		return "ach"
+	}
+	return m.Type
+}
+
+// isDigits reports whether s is between min and max decimal digits long
+func isDigits(s string, min, max int) bool {
+	if len(s) < min || len(s) > max {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// PaymentMethodFor returns the method charging a tender of the order: the
+// gift card of a gift card tender, or else the order's payment method if it
+// is of the tender's type. An empty tender is the order's default payment.
+func (o Order) PaymentMethodFor(tender Tender) *PaymentMethod {
+	if tender.HeldOnGiftCard() {
+		return &PaymentMethod{Type: PaymentMethodGiftCard, GiftCard: &GiftCardMethod{CardNumber: tender.Card}}
+	}
+	if o.PaymentMethod != nil && (tender.Method == "" || tender.Method == o.PaymentMethod.Type) {
+		return o.PaymentMethod
+	}
+	return nil
+}
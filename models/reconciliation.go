@@ -0,0 +1,85 @@
+package models
+
+import "time"
+
+// ReconciliationScheduleID is the ID of the schedule that starts
+// ReconciliationWorkflow nightly, and the prefix of the workflows it starts
+const ReconciliationScheduleID = "payment-reconciliation"
+
+// ReconciliationDateFormat is the layout of ReconciliationRequest.Date and
+// ReconciliationReport.Date
+const ReconciliationDateFormat = "2006-01-02"
+
+// ReconciliationRequest is the input of ReconciliationWorkflow: the UTC day
+// to reconcile. Empty reconciles the day before the workflow runs, which is
+// what the nightly schedule passes.
+type ReconciliationRequest struct {
+	Date string `json:"date,omitempty"`
+}
+
+// ReconciliationWindow is the span of time, From inclusive and To exclusive,
+// whose gateway transactions and completed orders are reconciled
+type ReconciliationWindow struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// GatewayTransaction is a payment the payment gateway captured or refunded
+type GatewayTransaction struct {
+	ID        string    `json:"id"`
+	OrderID   string    `json:"order_id"`
+	Type      string    `json:"type"`
+	Amount    float64   `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+	// MerchantAccount is the account the payment is settled into; empty is
+	// DefaultMerchantAccount
+	MerchantAccount string `json:"merchant_account,omitempty"`
+}
+
+// Gateway transaction types
+const (
+	TransactionCapture = "capture"
+	TransactionRefund  = "refund"
+)
+
+// CompletedOrder is an order the order store recorded as completed in a
+// reconciliation window
+type CompletedOrder struct {
+	OrderID     string    `json:"order_id"`
+	Amount      float64   `json:"amount"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// Discrepancy is an order whose payments and completion disagree.
+// CapturedAmount is what the gateway captured for it, net of refunds.
+type Discrepancy struct {
+	Kind           string   `json:"kind"`
+	OrderID        string   `json:"order_id"`
+	TransactionIDs []string `json:"transaction_ids,omitempty"`
+	OrderAmount    float64  `json:"order_amount"`
+	CapturedAmount float64  `json:"captured_amount"`
+}
+
+// Discrepancy kinds
+const (
+	// DiscrepancyMissingPayment is a completed order the gateway captured
+	// nothing for
+	DiscrepancyMissingPayment = "missing_payment"
+	// DiscrepancyUnmatchedPayment is money captured for an order that was not
+	// completed
+	DiscrepancyUnmatchedPayment = "unmatched_payment"
+	// DiscrepancyAmountMismatch is a completed order the gateway captured a
+	// different amount for
+	DiscrepancyAmountMismatch = "amount_mismatch"
+)
+
+// ReconciliationReport is the result of ReconciliationWorkflow and what it
+// sends finance: how many transactions and completed orders a day had, how
+// many orders matched, and the discrepancies, sorted by order ID
+type ReconciliationReport struct {
+	Date          string        `json:"date"`
+	Transactions  int           `json:"transactions"`
+	Orders        int           `json:"orders"`
+	Matched       int           `json:"matched"`
+	Discrepancies []Discrepancy `json:"discrepancies"`
+}
@@ -0,0 +1,66 @@
+package models
+
+// Risk tiers RiskAssessmentWorkflow sorts orders into, from least to most
+// scrutiny
+const (
+	// RiskTierAutoApprove orders go on to payment without further checks
+	RiskTierAutoApprove = "auto_approve"
+	// RiskTierStepUp orders wait for their customer to pass step-up
+	// verification, such as a one-time code, before payment
+	RiskTierStepUp = "step_up"
+	// RiskTierManualReview orders wait for a reviewer's approve signal, or a
+	// cancel, before payment
+	RiskTierManualReview = "manual_review"
+)
+
+// SignalVerify carries a StepUpResult to an order waiting for its customer
+// to pass step-up verification
+const SignalVerify = "verify"
+
+// RiskAssessmentWorkflowID returns the workflow ID of an order's
+// RiskAssessmentWorkflow
+func RiskAssessmentWorkflowID(orderID string) string {
+	return "risk-" + orderID
+}
+
+// RiskAssessmentRequest is the input of RiskAssessmentWorkflow: the order and
+// the customer, if any, who placed it
+type RiskAssessmentRequest struct {
+	Order      Order  `json:"order"`
+	CustomerID string `json:"customer_id,omitempty"`
+}
+
+// CustomerHistory is what the customer service knows of a customer's past
+// orders; a customer it does not know has none
+type CustomerHistory struct {
+	CustomerID  string `json:"customer_id"`
+	Orders      int    `json:"orders"`
+	Chargebacks int    `json:"chargebacks"`
+}
+
+// RiskAssessment is the result of RiskAssessmentWorkflow. Score, from 0 to 1,
+// weighs the fraud, order value, and customer history scores it combines, and
+// Tier is the tier it falls in. Reasons explain what raised it.
+type RiskAssessment struct {
+	OrderID      string   `json:"order_id"`
+	Tier         string   `json:"tier"`
+	Score        float64  `json:"score"`
+	FraudScore   float64  `json:"fraud_score"`
+	ValueScore   float64  `json:"value_score"`
+	HistoryScore float64  `json:"history_score"`
+	Reasons      []string `json:"reasons,omitempty"`
+}
+
+// StepUpRequest asks RequestStepUpVerification to challenge an order's
+// customer
+type StepUpRequest struct {
+	OrderID    string `json:"order_id"`
+	CustomerID string `json:"customer_id"`
+}
+
+// StepUpResult is the payload of the verify signal: whether the customer
+// passed step-up verification and, if not, why
+type StepUpResult struct {
+	Verified bool   `json:"verified"`
+	Reason   string `json:"reason,omitempty"`
+}
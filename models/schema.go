@@ -0,0 +1,128 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// OrderSchemaVersion is the version of the Order JSON schema. Version 1, the
+// original, has no schema_version and describes an order only by its item
+// names, one per unit, and its amount. Version 2 adds the structured lines
+// the items and amount are derived from.
+const OrderSchemaVersion = 2
+
+// MaxLineQuantity is the most units one line may have, the per-item quantity
+// limit order validation enforces. Each unit becomes an item, so the cap also
+// bounds what upgrading an order allocates.
+const MaxLineQuantity = 10
+
+// ErrUnsupportedOrderSchema is returned for an order of a schema version
+// newer than this build knows
+var ErrUnsupportedOrderSchema = errors.New("unsupported order schema version")
+
+// Migrate decodes an order of any schema version and upgrades it to
+// OrderSchemaVersion with Upgrade
+func Migrate(raw json.RawMessage) (Order, error) {
+	var order Order
+	if err := json.Unmarshal(raw, &order); err != nil {
+		return Order{}, fmt.Errorf("invalid order: %w", err)
+	}
+	return order.Upgrade()
+}
+
+// Upgrade returns the order in schema OrderSchemaVersion. A version 1 order
+// gets a line per distinct item, in the order the items first appear, with
+// the item's units as its quantity and its vendor, if dropshipped. Its lines
+// have no prices, since version 1 priced only the whole order. A version 2
+// order given only lines gets its items and, when unset, its amount and
+// vendors from them; one given both must agree on the units. A line of more
+// than MaxLineQuantity units is rejected. Upgrading a current order changes
+// nothing, so it is safe to repeat.
+func (o Order) Upgrade() (Order, error) {
+	if o.SchemaVersion > OrderSchemaVersion {
+		return Order{}, fmt.Errorf("%w: order %s is version %d, this build reads up to %d",
+			ErrUnsupportedOrderSchema, o.ID, o.SchemaVersion, OrderSchemaVersion)
+	}
+	if len(o.Lines) == 0 {
+		o.Lines = linesFromItems(o.Items, o.Vendors)
+		o.SchemaVersion = OrderSchemaVersion
+		return o, nil
+	}
+
+	var units int
+	var total float64
+	for i, line := range o.Lines {
+		if line.SKU == "" {
+			return Order{}, fmt.Errorf("lines[%d].sku is required", i)
+		}
+		if line.Quantity <= 0 {
+			return Order{}, fmt.Errorf("lines[%d].qty must be positive", i)
+		}
+		if line.Quantity > MaxLineQuantity {
+			return Order{}, fmt.Errorf("lines[%d].qty must be at most %d", i, MaxLineQuantity)
+		}
+		if line.Price < 0 {
+			return Order{}, fmt.Errorf("lines[%d].price must not be negative", i)
+		}
+		units += line.Quantity
+		total += float64(line.Quantity) * line.Price
+	}
+	switch {
+	case len(o.Items) == 0:
+		o.Items = itemsFromLines(o.Lines)
+	case len(o.Items) != units:
+		return Order{}, fmt.Errorf("items list %d units but lines add up to %d", len(o.Items), units)
+	}
+	if o.Amount == 0 {
+		o.Amount = math.Round(total*100) / 100
+	}
+	if o.Vendors == nil {
+		o.Vendors = vendorsFromLines(o.Lines)
+	}
+	o.SchemaVersion = OrderSchemaVersion
+	return o, nil
+}
+
+// linesFromItems groups item names, one per unit, into lines
+func linesFromItems(items []string, vendors map[string]string) []OrderItem {
+	var lines []OrderItem
+	index := make(map[string]int, len(items))
+	for _, item := range items {
+		if i, ok := index[item]; ok {
+			lines[i].Quantity++
+			continue
+		}
+		index[item] = len(lines)
+		lines = append(lines, OrderItem{SKU: item, Quantity: 1, Vendor: vendors[item]})
+	}
+	return lines
+}
+
+// itemsFromLines lists each unit of the lines as one item
+func itemsFromLines(lines []OrderItem) []string {
+	var items []string
+	for _, line := range lines {
+		for range line.Quantity {
+			items = append(items, line.SKU)
+		}
+	}
+	return items
+}
+
+// vendorsFromLines maps the SKUs of dropshipped lines to their vendors, nil
+// when none is dropshipped
+func vendorsFromLines(lines []OrderItem) map[string]string {
+	var vendors map[string]string
+	for _, line := range lines {
+		if line.Vendor == "" {
+			continue
+		}
+		if vendors == nil {
+			vendors = make(map[string]string)
+		}
+		vendors[line.SKU] = line.Vendor
+	}
+	return vendors
+}
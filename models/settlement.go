@@ -0,0 +1,77 @@
+package models
+
+import "fmt"
+
+// SettlementScheduleID is the ID of the schedule that starts
+// SettlementWorkflow at the end of each day, and the prefix of the workflows
+// it starts
+const SettlementScheduleID = "payment-settlement"
+
+// DefaultMerchantAccount is the merchant account of gateway transactions
+// that name none
+const DefaultMerchantAccount = "default"
+
+// SettlementRequest is the input of SettlementWorkflow: the UTC day, as
+// YYYY-MM-DD, whose payments are settled. Empty settles the day before the
+// workflow runs, which is what the schedule passes.
+type SettlementRequest struct {
+	Date string `json:"date,omitempty"`
+}
+
+// SettlementBatch is a day's payments into one merchant account, settled
+// together by SettleBatch. Amount is the captures net of refunds.
+type SettlementBatch struct {
+	ID              string   `json:"id"`
+	Date            string   `json:"date"`
+	MerchantAccount string   `json:"merchant_account"`
+	TransactionIDs  []string `json:"transaction_ids"`
+	OrderIDs        []string `json:"order_ids"`
+	Amount          float64  `json:"amount"`
+}
+
+// SettlementResult is the gateway's answer to a settled batch
+type SettlementResult struct {
+	SettlementID string `json:"settlement_id"`
+}
+
+// OrderSettlement records the settlement orders were paid out in; it is the
+// input of the RecordSettlement store activity
+type OrderSettlement struct {
+	SettlementID string   `json:"settlement_id"`
+	OrderIDs     []string `json:"order_ids"`
+}
+
+// BatchSettlement is the outcome of a settlement batch: its settlement ID
+// once settled, or why it failed. A batch that settled but whose settlement
+// ID could not be recorded on its orders is settled with an Error.
+type BatchSettlement struct {
+	BatchID         string   `json:"batch_id"`
+	MerchantAccount string   `json:"merchant_account"`
+	OrderIDs        []string `json:"order_ids"`
+	Amount          float64  `json:"amount"`
+	Status          string   `json:"status"`
+	SettlementID    string   `json:"settlement_id,omitempty"`
+	Error           string   `json:"error,omitempty"`
+}
+
+// Settlement batch statuses
+const (
+	SettlementSettled = "settled"
+	SettlementFailed  = "failed"
+)
+
+// SettlementReport is the result of SettlementWorkflow and what it sends
+// finance: the day's batches, sorted by merchant account, and how many
+// settled and failed
+type SettlementReport struct {
+	Date    string            `json:"date"`
+	Batches []BatchSettlement `json:"batches"`
+	Settled int               `json:"settled"`
+	Failed  int               `json:"failed"`
+}
+
+// SettlementBatchID returns the ID of a day's settlement batch for a
+// merchant account; the gateway uses it to settle a batch only once
+func SettlementBatchID(date, merchantAccount string) string {
+	return fmt.Sprintf("STL-%s-%s", date, merchantAccount)
+}
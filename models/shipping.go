@@ -0,0 +1,92 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Shipping methods. Expedited and overnight orders are fulfilled with the
+// expedited processing times from the start; a pickup order has no address.
+const (
+	ShippingStandard  = "standard"
+	ShippingExpedited = "expedited"
+	ShippingOvernight = "overnight"
+	ShippingPickup    = "pickup"
+)
+
+// ShippingMethods lists the shipping methods an order may choose
+var ShippingMethods = []string{ShippingStandard, ShippingExpedited, ShippingOvernight, ShippingPickup}
+
+// ShippingInfo is how and where an order is delivered. Carrier names the
+// carrier the shipment is tracked with, empty for the default one.
+// Instructions are passed to the carrier as given. PromisedBy, when set, is
+// when the customer was promised delivery; an order not delivered by then is
+// flagged promise_missed.
+type ShippingInfo struct {
+	Method       string    `json:"method"`
+	Carrier      string    `json:"carrier,omitempty"`
+	Address      *Address  `json:"address,omitempty"`
+	Instructions string    `json:"instructions,omitempty"`
+	PromisedBy   time.Time `json:"promised_by,omitzero"`
+}
+
+// Address is a postal address. Country is the ISO 3166-1 alpha-2 code.
+// Addresses are personal data: the address field is redacted from logs and
+// encrypted by field encryption by default.
+type Address struct {
+	Name       string `json:"name,omitempty"`
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2,omitempty"`
+	City       string `json:"city"`
+	Region     string `json:"region,omitempty"`
+	PostalCode string `json:"postal_code,omitempty"`
+	Country    string `json:"country"`
+}
+
+// Validate checks that the method is known and that an order to be shipped,
+// rather than picked up, has a complete address
+func (s ShippingInfo) Validate() error {
+	if fields := s.fieldErrors("shipping"); len(fields) > 0 {
+		return fields[0]
+	}
+	return nil
+}
+
+// fieldErrors lists what Validate finds wrong with the shipping, naming
+// fields under path
+func (s ShippingInfo) fieldErrors(path string) []FieldError {
+	var fields []FieldError
+	invalid := func(field, format string, args ...any) {
+		fields = append(fields, FieldError{Field: path + "." + field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if !slices.Contains(ShippingMethods, s.Method) {
+		invalid("method", "must be one of %s, got %q", strings.Join(ShippingMethods, ", "), s.Method)
+	}
+	switch {
+	case s.Method == ShippingPickup:
+		if s.Address != nil {
+			invalid("address", "is not used for %s", ShippingPickup)
+		}
+	case s.Address == nil:
+		invalid("address", "is required")
+	default:
+		if strings.TrimSpace(s.Address.Line1) == "" {
+			invalid("address.line1", "is required")
+		}
+		if strings.TrimSpace(s.Address.City) == "" {
+			invalid("address.city", "is required")
+		}
+		if len(s.Address.Country) != 2 {
+			invalid("address.country", "must be a two-letter country code, got %q", s.Address.Country)
+		}
+	}
+	return fields
+}
+
+// Expedited reports whether the method is one the order is expedited for
+func (s ShippingInfo) Expedited() bool {
+	return s.Method == ShippingExpedited || s.Method == ShippingOvernight
+}
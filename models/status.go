@@ -0,0 +1,187 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// OrderStatusCode is where an order is in its lifecycle. It marshals to and
+// from its JSON string, rejecting values that are not order statuses; the
+// empty status is an order input that has not set one.
+type OrderStatusCode string
+
+// Order statuses
+const (
+	StatusPending    OrderStatusCode = "pending"
+	StatusValidating OrderStatusCode = "validating"
+	// StatusAwaitingApproval means a high-value order, or one held for manual
+	// review, is waiting for an approve signal
+	StatusAwaitingApproval OrderStatusCode = "awaiting_approval"
+	// StatusAwaitingVerification means a risky order is waiting for its
+	// customer to pass step-up verification
+	StatusAwaitingVerification OrderStatusCode = "awaiting_verification"
+	StatusProcessing           OrderStatusCode = "processing"
+	StatusCompleted            OrderStatusCode = "completed"
+	StatusCancelled            OrderStatusCode = "cancelled"
+	StatusFailed               OrderStatusCode = "failed"
+	// StatusBackordered means a paid order is waiting for out-of-stock items
+	// to be restocked
+	StatusBackordered OrderStatusCode = "backordered"
+	// StatusPartiallyCompleted means some items of a large order could not be fulfilled
+	StatusPartiallyCompleted OrderStatusCode = "partially_completed"
+)
+
+// ErrIllegalStatusTransition is returned when an order is moved to a status
+// its current status cannot lead to
+var ErrIllegalStatusTransition = errors.New("illegal order status transition")
+
+// statusTransitions lists the statuses each status can move to. Any open
+// order can fail or be cancelled. The awaiting statuses return to validating
+// once answered. A failed order can still become cancelled, when the failure
+// was the cancellation interrupting it. Terminal statuses lead nowhere else.
+var statusTransitions = map[OrderStatusCode][]OrderStatusCode{
+	StatusPending:              {StatusValidating},
+	StatusValidating:           {StatusAwaitingVerification, StatusAwaitingApproval, StatusProcessing},
+	StatusAwaitingVerification: {StatusValidating},
+	StatusAwaitingApproval:     {StatusValidating},
+	StatusProcessing:           {StatusBackordered, StatusCompleted, StatusPartiallyCompleted},
+	StatusBackordered:          {StatusProcessing},
+	StatusFailed:               {StatusCancelled},
+	StatusCompleted:            nil,
+	StatusPartiallyCompleted:   nil,
+	StatusCancelled:            nil,
+}
+
+// ParseOrderStatusCode returns the order status named s
+func ParseOrderStatusCode(s string) (OrderStatusCode, error) {
+	status := OrderStatusCode(s)
+	if !status.Valid() {
+		return "", fmt.Errorf("unknown order status %q", s)
+	}
+	return status, nil
+}
+
+// Valid reports whether s is one of the order statuses
+func (s OrderStatusCode) Valid() bool {
+	_, ok := statusTransitions[s]
+	return ok
+}
+
+// IsTerminal reports whether an order in status s is closed: completed,
+// partially completed, cancelled, or failed
+func (s OrderStatusCode) IsTerminal() bool {
+	switch s {
+	case StatusCompleted, StatusPartiallyCompleted, StatusCancelled, StatusFailed:
+		return true
+	}
+	return false
+}
+
+// CanTransitionTo reports whether an order in status s may move to next.
+// Staying in the same status is always allowed.
+func (s OrderStatusCode) CanTransitionTo(next OrderStatusCode) bool {
+	if s == next {
+		return true
+	}
+	if !s.IsTerminal() && s.Valid() && (next == StatusFailed || next == StatusCancelled) {
+		return true
+	}
+	return slices.Contains(statusTransitions[s], next)
+}
+
+// MarshalJSON encodes the status as its string, failing for an unknown one
+func (s OrderStatusCode) MarshalJSON() ([]byte, error) {
+	if s != "" && !s.Valid() {
+		return nil, fmt.Errorf("unknown order status %q", string(s))
+	}
+	return json.Marshal(string(s))
+}
+
+// UnmarshalJSON decodes the status from its string, failing for an unknown
+// one
+func (s *OrderStatusCode) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return fmt.Errorf("order status must be a string: %w", err)
+	}
+	if name == "" {
+		*s = ""
+		return nil
+	}
+	status, err := ParseOrderStatusCode(name)
+	if err != nil {
+		return err
+	}
+	*s = status
+	return nil
+}
+
+// TransitionTo moves the order to status next, if its current status may
+// lead there, and otherwise returns ErrIllegalStatusTransition leaving the
+// status unchanged
+func (s *OrderStatus) TransitionTo(next OrderStatusCode) error {
+	if !s.Status.CanTransitionTo(next) {
+		return fmt.Errorf("%w: order %s from %s to %s", ErrIllegalStatusTransition, s.OrderID, s.Status, next)
+	}
+	s.Status = next
+	return nil
+}
+
+// OrderStage is the step of the workflow an order is in. Like
+// OrderStatusCode, it marshals to and from its JSON string and rejects
+// unknown stages.
+type OrderStage string
+
+// Stages, in the order the workflow runs them
+const (
+	StageValidation OrderStage = "validation"
+	StagePayment    OrderStage = "payment"
+	StageProcessing OrderStage = "processing"
+	StageCompleted  OrderStage = "completed"
+)
+
+// orderStages lists the stages in the order the workflow runs them
+var orderStages = []OrderStage{StageValidation, StagePayment, StageProcessing, StageCompleted}
+
+// ParseOrderStage returns the stage named s
+func ParseOrderStage(s string) (OrderStage, error) {
+	stage := OrderStage(s)
+	if !stage.Valid() {
+		return "", fmt.Errorf("unknown order stage %q", s)
+	}
+	return stage, nil
+}
+
+// Valid reports whether s is one of the stages
+func (s OrderStage) Valid() bool {
+	return slices.Contains(orderStages, s)
+}
+
+// MarshalJSON encodes the stage as its string, failing for an unknown one
+func (s OrderStage) MarshalJSON() ([]byte, error) {
+	if s != "" && !s.Valid() {
+		return nil, fmt.Errorf("unknown order stage %q", string(s))
+	}
+	return json.Marshal(string(s))
+}
+
+// UnmarshalJSON decodes the stage from its string, failing for an unknown
+// one
+func (s *OrderStage) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return fmt.Errorf("order stage must be a string: %w", err)
+	}
+	if name == "" {
+		*s = ""
+		return nil
+	}
+	stage, err := ParseOrderStage(name)
+	if err != nil {
+		return err
+	}
+	*s = stage
+	return nil
+}
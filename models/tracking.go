@@ -0,0 +1,73 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Shipment is the input of TrackingWorkflow: the shipment carrying an
+// order's items, polled every PollInterval. Carrier and PromisedBy come from
+// the order's shipping. StartedAt and Polls carry the tracking so far across
+// continue-as-new, and SearchAttributes whether the ShipmentStatus search
+// attribute is upserted.
+type Shipment struct {
+	OrderID          string        `json:"order_id"`
+	ShipmentID       string        `json:"shipment_id"`
+	Carrier          string        `json:"carrier,omitempty"`
+	PromisedBy       time.Time     `json:"promised_by,omitzero"`
+	PollInterval     time.Duration `json:"poll_interval"`
+	StartedAt        time.Time     `json:"started_at"`
+	Polls            int           `json:"polls,omitempty"`
+	LastStatus       string        `json:"last_status,omitempty"`
+	SearchAttributes bool          `json:"search_attributes,omitempty"`
+}
+
+// TrackingRequest asks the carrier for the status of a shipment. Poll counts
+// the requests made for it, from one.
+type TrackingRequest struct {
+	OrderID    string `json:"order_id"`
+	ShipmentID string `json:"shipment_id"`
+	Carrier    string `json:"carrier,omitempty"`
+	Poll       int    `json:"poll"`
+}
+
+// TrackingUpdate is a shipment's status as the carrier reports it. It is
+// also the payload of the shipment-update signal a TrackingWorkflow sends
+// its order once the shipment is delivered or runs into an exception.
+type TrackingUpdate struct {
+	ShipmentID string    `json:"shipment_id"`
+	Status     string    `json:"status"`
+	Location   string    `json:"location,omitempty"`
+	Detail     string    `json:"detail,omitempty"`
+	At         time.Time `json:"at"`
+}
+
+// Shipment statuses
+const (
+	ShipmentInTransit      = "in_transit"
+	ShipmentOutForDelivery = "out_for_delivery"
+	ShipmentDelivered      = "delivered"
+	ShipmentException      = "exception"
+)
+
+// Final reports whether the shipment's tracking is over: it was delivered or
+// the carrier reported an exception
+func (u TrackingUpdate) Final() bool {
+	return u.Status == ShipmentDelivered || u.Status == ShipmentException
+}
+
+// SignalShipmentUpdate is sent to the order by its TrackingWorkflow when the
+// shipment is delivered or runs into an exception
+const SignalShipmentUpdate = "shipment-update"
+
+// SearchAttributeShipmentStatus is a Keyword holding a tracked shipment's
+// status, set on TrackingWorkflow when the search-attributes feature flag is
+// on; it must be registered on the namespace first
+const SearchAttributeShipmentStatus = "ShipmentStatus"
+
+// TrackingWorkflowID returns the workflow ID of the tracking workflow for an
+// order's shipment, given the order ID, or for one of the shipments of an
+// order fulfilled from several warehouses, given its shipment ID
+func TrackingWorkflowID(id string) string {
+	return fmt.Sprintf("tracking-%s", id)
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldError is a problem with one field of an order, named by its JSON path
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	return e.Field + " " + e.Message
+}
+
+// NewOrder returns a pending order of items for amount, placed by customer,
+// or nil for a guest checkout, once it passes Validate
+func NewOrder(id string, items []string, amount float64, customer *Customer) (Order, error) {
+	order := Order{
+		ID:       id,
+		Items:    items,
+		Amount:   amount,
+		Customer: customer,
+		Status:   StatusPending,
+	}
+	if err := order.Validate(); err != nil {
+		return Order{}, err
+	}
+	return order, nil
+}
+
+// Validate checks the fields every order needs: an ID, at least one item,
+// none of them blank, a positive amount, and, unless it is a guest checkout,
+// a valid customer with an ID. A payment method and shipping, if given, must
+// be complete.
+// It returns an *OrderInvalidError listing every field at fault, so callers
+// can report them all at once.
+func (o Order) Validate() error {
+	var fields []FieldError
+	if strings.TrimSpace(o.ID) == "" {
+		fields = append(fields, FieldError{Field: "id", Message: "is required"})
+	}
+	if len(o.Items) == 0 {
+		fields = append(fields, FieldError{Field: "items", Message: "must not be empty"})
+	}
+	for i, item := range o.Items {
+		if strings.TrimSpace(item) == "" {
+			fields = append(fields, FieldError{Field: fmt.Sprintf("items[%d]", i), Message: "must not be blank"})
+		}
+	}
+	if o.Amount <= 0 {
+		fields = append(fields, FieldError{Field: "amount", Message: "must be positive"})
+	}
+	if o.Customer != nil {
+		fields = append(fields, o.Customer.fieldErrors()...)
+	}
+	if o.PaymentMethod != nil {
+		fields = append(fields, o.PaymentMethod.fieldErrors("payment_method")...)
+	}
+	if o.Shipping != nil {
+		fields = append(fields, o.Shipping.fieldErrors("shipping")...)
+	}
+	if len(fields) > 0 {
+		return &OrderInvalidError{OrderID: o.ID, Fields: fields}
+	}
+	return nil
+}
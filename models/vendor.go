@@ -0,0 +1,67 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// PurchaseOrder asks a dropship vendor to ship the marketplace items of an
+// order; it is the input of VendorFulfillmentWorkflow
+type PurchaseOrder struct {
+	Number   string   `json:"number"`
+	OrderID  string   `json:"order_id"`
+	VendorID string   `json:"vendor_id"`
+	Items    []string `json:"items"`
+}
+
+// VendorResponse is a vendor's answer to a purchase order, returned by the
+// vendor API when it answers at once or sent later with the vendor-response
+// signal. A rejection gives its Reason.
+type VendorResponse struct {
+	Accepted bool   `json:"accepted"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// VendorFulfillment is the state of a purchase order: the result and
+// getStatus query of VendorFulfillmentWorkflow, what it signals the order
+// with while the vendor has not answered, and an entry of
+// OrderStatus.Vendors
+type VendorFulfillment struct {
+	PurchaseOrder string    `json:"purchase_order"`
+	VendorID      string    `json:"vendor_id"`
+	Items         []string  `json:"items"`
+	Status        string    `json:"status"`
+	Reason        string    `json:"reason,omitempty"`
+	LastUpdated   time.Time `json:"last_updated"`
+}
+
+// Purchase order statuses
+const (
+	// VendorPending is a purchase order sent and awaiting the vendor's answer
+	VendorPending = "pending"
+	// VendorEscalated is a purchase order the vendor has not answered in
+	// time, which ops were alerted to
+	VendorEscalated = "escalated"
+	VendorAccepted  = "accepted"
+	VendorRejected  = "rejected"
+)
+
+// SignalVendorResponse is sent to a VendorFulfillmentWorkflow with the
+// vendor's answer, by the vendor's callback or by ops on its behalf
+const SignalVendorResponse = "vendor-response"
+
+// SignalVendorUpdate is sent to the order by its VendorFulfillmentWorkflow
+// children as their purchase orders change status
+const SignalVendorUpdate = "vendor-update"
+
+// PurchaseOrderNumber returns the number of the purchase order an order
+// sends a vendor
+func PurchaseOrderNumber(orderID, vendorID string) string {
+	return fmt.Sprintf("PO-%s-%s", orderID, vendorID)
+}
+
+// VendorWorkflowID returns the workflow ID of the VendorFulfillmentWorkflow
+// of an order's purchase order to a vendor
+func VendorWorkflowID(orderID, vendorID string) string {
+	return fmt.Sprintf("vendor-%s-%s", orderID, vendorID)
+}
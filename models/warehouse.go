@@ -0,0 +1,94 @@
+package models
+
+import "fmt"
+
+// Warehouse is a location orders are fulfilled from. Stock lists the items
+// it carries; empty carries every item. Distances is how far, in km, it
+// ships to each sales region, as named in the order's memo.
+type Warehouse struct {
+	ID        string             `json:"id"`
+	Stock     []string           `json:"stock,omitempty"`
+	Distances map[string]float64 `json:"distances,omitempty"`
+}
+
+// WarehouseRequest asks SelectWarehouse to route an order's items to the
+// warehouses that ship them
+type WarehouseRequest struct {
+	OrderID string   `json:"order_id"`
+	Items   []string `json:"items"`
+	Region  string   `json:"region,omitempty"`
+}
+
+// WarehouseRouting is the outcome of SelectWarehouse: the items each chosen
+// warehouse ships, and those no warehouse stocks
+type WarehouseRouting struct {
+	Assignments []WarehouseAssignment `json:"assignments"`
+	Unavailable []string              `json:"unavailable,omitempty"`
+}
+
+// WarehouseAssignment is the items of an order one warehouse ships
+type WarehouseAssignment struct {
+	WarehouseID string   `json:"warehouse_id"`
+	Items       []string `json:"items"`
+}
+
+// WarehouseFulfillmentRequest is the input of the per-warehouse fulfillment
+// child workflow: the items of one shipment
+type WarehouseFulfillmentRequest struct {
+	OrderID     string   `json:"order_id"`
+	ShipmentID  string   `json:"shipment_id"`
+	WarehouseID string   `json:"warehouse_id"`
+	Items       []string `json:"items"`
+	IsExpedited bool     `json:"is_expedited"`
+}
+
+// WarehouseFulfillmentResult is what the per-warehouse fulfillment child
+// workflow returns: the outcome of each item, indexed like the request's, and
+// which of the failed items were out of stock
+type WarehouseFulfillmentResult struct {
+	ShipmentID string            `json:"shipment_id"`
+	Items      []ItemFulfillment `json:"items"`
+	OutOfStock []string          `json:"out_of_stock,omitempty"`
+}
+
+// OrderShipment is one shipment of an order fulfilled from several
+// warehouses, as reported by OrderStatus.Shipments. Status is fulfilled when
+// any of its items was packed, which are then its Items, and failed
+// otherwise; only a fulfilled shipment ships and is tracked, TrackingStatus
+// being the carrier's status.
+type OrderShipment struct {
+	ShipmentID     string   `json:"shipment_id"`
+	WarehouseID    string   `json:"warehouse_id"`
+	Items          []string `json:"items"`
+	Status         string   `json:"status"`
+	TrackingStatus string   `json:"tracking_status,omitempty"`
+}
+
+// WarehouseShipmentID returns the ID of the nth shipment, from one, of an
+// order fulfilled from several warehouses
+func WarehouseShipmentID(orderID string, n int) string {
+	return fmt.Sprintf("%s-%d", ShipmentID(orderID), n)
+}
+
+// AggregateShipmentStatus returns the tracking status of an order from that
+// of its fulfilled shipments: an exception if any has one, delivered once all
+// are delivered, and otherwise in transit
+func AggregateShipmentStatus(shipments []OrderShipment) string {
+	shipped, delivered := 0, 0
+	for _, shipment := range shipments {
+		if shipment.Status != ItemFulfilled {
+			continue
+		}
+		shipped++
+		switch shipment.TrackingStatus {
+		case ShipmentException:
+			return ShipmentException
+		case ShipmentDelivered:
+			delivered++
+		}
+	}
+	if delivered == shipped {
+		return ShipmentDelivered
+	}
+	return ShipmentInTransit
+}
@@ -0,0 +1,103 @@
+// Package orderitems parses the items of an order as the starter takes them:
+// a comma-separated -items list, or structured order lines in an -items-file.
+package orderitems
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+)
+
+// Parse splits a comma-separated list of items, trimming each and dropping
+// empty entries
+func Parse(list string) []string {
+	items := []string{}
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// ReadFile reads the order lines in the file at path; see Decode
+func ReadFile(path string) ([]models.OrderItem, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Decode(bytes.NewReader(data))
+}
+
+// Decode reads a JSON array of order lines from r, such as
+// [{"sku": "laptop", "qty": 1, "price": 999.99}]. A line without qty is one
+// unit. A SKU is dropshipped by at most one vendor, so its lines must agree
+// on the vendor.
+func Decode(r io.Reader) ([]models.OrderItem, error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	var lines []models.OrderItem
+	if err := decoder.Decode(&lines); err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, errors.New("no order lines")
+	}
+	vendors := make(map[string]string)
+	for i, line := range lines {
+		vendor, seen := vendors[line.SKU]
+		vendors[line.SKU] = line.Vendor
+		switch {
+		case seen && vendor != line.Vendor:
+			return nil, fmt.Errorf("line %d: sku %s is listed with another vendor", i+1, line.SKU)
+		case strings.TrimSpace(line.SKU) == "":
+			return nil, fmt.Errorf("line %d: sku is required", i+1)
+		case line.Quantity < 0:
+			return nil, fmt.Errorf("line %d: qty must not be negative", i+1)
+		case line.Quantity > models.MaxLineQuantity:
+			return nil, fmt.Errorf("line %d: qty must be at most %d", i+1, models.MaxLineQuantity)
+		case line.Price < 0:
+			return nil, fmt.Errorf("line %d: price must not be negative", i+1)
+		case line.Quantity == 0:
+			lines[i].Quantity = 1
+		}
+	}
+	return lines, nil
+}
+
+// Expand returns the items of an order with lines, one entry per unit, and
+// the lines' total price
+func Expand(lines []models.OrderItem) ([]string, float64) {
+	var items []string
+	var total float64
+	for _, line := range lines {
+		for range line.Quantity {
+			items = append(items, line.SKU)
+		}
+		total += float64(line.Quantity) * line.Price
+	}
+	return items, math.Round(total*100) / 100
+}
+
+// Vendors returns the vendors of the dropshipped lines by SKU, nil when none
+// is dropshipped
+func Vendors(lines []models.OrderItem) map[string]string {
+	var vendors map[string]string
+	for _, line := range lines {
+		if line.Vendor == "" {
+			continue
+		}
+		if vendors == nil {
+			vendors = make(map[string]string)
+		}
+		vendors[line.SKU] = line.Vendor
+	}
+	return vendors
+}
@@ -0,0 +1,103 @@
+// Package orderwait blocks until an order workflow completes, for the
+// starter's -wait, and maps how it ended to the exit code scripts check.
+package orderwait
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/client"
+)
+
+// Exit codes of -wait, so scripts can tell a failed order from a slow one
+const (
+	ExitFailed  = 1
+	ExitTimeout = 2
+)
+
+// Outcome is how an awaited order workflow ended
+type Outcome struct {
+	// Result is the workflow's result, which executions started before
+	// OrderWorkflow returned one lack
+	Result *models.OrderResult
+	// FinalStatus is the order's final status, if it could be queried
+	FinalStatus *models.OrderStatus
+	// Error is the OrderError the workflow failed with, if it did
+	Error *models.OrderError
+	// ExitCode is ExitFailed if the workflow failed or the order did not
+	// complete, ExitTimeout if it is still running after the timeout, and 0
+	// otherwise
+	ExitCode int
+}
+
+// Await blocks until run completes, or until timeout if it is positive, and
+// returns how it ended
+func Await(ctx context.Context, c client.Client, run client.WorkflowRun, timeout time.Duration) Outcome {
+	waitCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	slog.Info("Waiting for workflow to complete", "workflow_id", run.GetID(), "timeout", timeout)
+	var result *models.OrderResult
+	err := run.Get(waitCtx, &result)
+	if errors.Is(waitCtx.Err(), context.DeadlineExceeded) {
+		slog.Error("Timed out waiting for workflow", "workflow_id", run.GetID(), "timeout", timeout)
+		return Outcome{ExitCode: ExitTimeout}
+	}
+
+	// The final status is best effort: it needs a worker to answer the query
+	var outcome Outcome
+	status, queryErr := Status(ctx, c, run.GetID(), run.GetRunID())
+	if queryErr != nil {
+		slog.Warn("Unable to query final order status", "error", queryErr)
+	} else {
+		outcome.FinalStatus = &status
+	}
+
+	if err != nil {
+		outcome.Error = models.OrderErrorFrom(err)
+		slog.Error("Workflow failed", "workflow_id", run.GetID(), "code", outcome.Error.Code, "stage", outcome.Error.Stage, "error", err)
+		outcome.ExitCode = ExitFailed
+		return outcome
+	}
+	outcome.Result = result
+	var code models.OrderStatusCode
+	switch {
+	case result != nil:
+		code = result.Status
+	case queryErr == nil:
+		code = status.Status
+	}
+	if code == models.StatusFailed || code == models.StatusCancelled {
+		slog.Error("Order did not complete", "workflow_id", run.GetID(), "status", code)
+		outcome.ExitCode = ExitFailed
+		return outcome
+	}
+	slog.Info("Workflow completed", "workflow_id", run.GetID())
+	return outcome
+}
+
+// Status queries the order status of a workflow run, the latest when runID
+// is empty
+func Status(ctx context.Context, c client.Client, workflowID, runID string) (models.OrderStatus, error) {
+	// Create a context with longer timeout for query
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	var status models.OrderStatus
+	response, err := c.QueryWorkflow(queryCtx, workflowID, runID, "getStatus")
+	if err != nil {
+		return status, err
+	}
+	if err := response.Get(&status); err != nil {
+		return status, fmt.Errorf("unable to decode query result: %w", err)
+	}
+	return status, nil
+}
@@ -0,0 +1,348 @@
+package orderspb
+
+import (
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// FromOrder converts a models.Order to its message
+func FromOrder(order *models.Order) *Order {
+	return &Order{
+		Id:                     order.ID,
+		Items:                  order.Items,
+		Amount:                 order.Amount,
+		Status:                 string(order.Status),
+		CreatedAt:              fromTime(order.CreatedAt),
+		FulfillmentParallelism: int32(order.FulfillmentParallelism),
+		Tenders:                fromTenders(order.Tenders),
+		Installments:           int32(order.Installments),
+		RedeemPoints:           int32(order.RedeemPoints),
+		Vendors:                order.Vendors,
+		Currency:               order.Currency,
+		Customer:               fromCustomer(order.Customer),
+		SchemaVersion:          int32(order.SchemaVersion),
+		Lines:                  fromOrderLines(order.Lines),
+		PaymentMethod:          fromPaymentMethod(order.PaymentMethod),
+		Shipping:               fromShippingInfo(order.Shipping),
+		PriorPayment:           fromPriorPayment(order.PriorPayment),
+	}
+}
+
+// ToOrder converts an Order message to a models.Order
+func ToOrder(message *Order) models.Order {
+	return models.Order{
+		ID:                     message.GetId(),
+		Items:                  message.GetItems(),
+		Amount:                 message.GetAmount(),
+		Status:                 models.OrderStatusCode(message.GetStatus()),
+		CreatedAt:              toTime(message.GetCreatedAt()),
+		FulfillmentParallelism: int(message.GetFulfillmentParallelism()),
+		Tenders:                toTenders(message.GetTenders()),
+		Installments:           int(message.GetInstallments()),
+		RedeemPoints:           int(message.GetRedeemPoints()),
+		Vendors:                message.GetVendors(),
+		Currency:               message.GetCurrency(),
+		Customer:               toCustomer(message.GetCustomer()),
+		SchemaVersion:          int(message.GetSchemaVersion()),
+		Lines:                  toOrderLines(message.GetLines()),
+		PaymentMethod:          toPaymentMethod(message.GetPaymentMethod()),
+		Shipping:               toShippingInfo(message.GetShipping()),
+		PriorPayment:           toPriorPayment(message.GetPriorPayment()),
+	}
+}
+
+// FromOrderStatus converts a models.OrderStatus to its message
+func FromOrderStatus(status *models.OrderStatus) *OrderStatus {
+	message := &OrderStatus{
+		OrderId:                status.OrderID,
+		Status:                 string(status.Status),
+		Stage:                  string(status.Stage),
+		IsExpedited:            status.IsExpedited,
+		PaymentStatus:          status.PaymentStatus,
+		ProvisionallyValidated: status.ProvisionallyValidated,
+		InvoiceUrl:             status.InvoiceURL,
+		SlaBreached:            status.SLABreached,
+		PromiseMissed:          status.PromiseMissed,
+		ShipmentStatus:         status.ShipmentStatus,
+		LastUpdated:            fromTime(status.LastUpdated),
+	}
+	for _, result := range status.ItemResults {
+		message.ItemResults = append(message.ItemResults, &ItemFulfillment{
+			Item:   result.Item,
+			Status: result.Status,
+			Step:   result.Step,
+			Error:  result.Error,
+		})
+	}
+	for _, shipment := range status.Shipments {
+		message.Shipments = append(message.Shipments, &OrderShipment{
+			ShipmentId:     shipment.ShipmentID,
+			WarehouseId:    shipment.WarehouseID,
+			Items:          shipment.Items,
+			Status:         shipment.Status,
+			TrackingStatus: shipment.TrackingStatus,
+		})
+	}
+	for _, vendor := range status.Vendors {
+		message.Vendors = append(message.Vendors, &VendorFulfillment{
+			PurchaseOrder: vendor.PurchaseOrder,
+			VendorId:      vendor.VendorID,
+			Items:         vendor.Items,
+			Status:        vendor.Status,
+			Reason:        vendor.Reason,
+			LastUpdated:   fromTime(vendor.LastUpdated),
+		})
+	}
+	return message
+}
+
+// ToOrderStatus converts an OrderStatus message to a models.OrderStatus
+func ToOrderStatus(message *OrderStatus) models.OrderStatus {
+	status := models.OrderStatus{
+		OrderID:                message.GetOrderId(),
+		Status:                 models.OrderStatusCode(message.GetStatus()),
+		Stage:                  models.OrderStage(message.GetStage()),
+		IsExpedited:            message.GetIsExpedited(),
+		PaymentStatus:          message.GetPaymentStatus(),
+		ProvisionallyValidated: message.GetProvisionallyValidated(),
+		InvoiceURL:             message.GetInvoiceUrl(),
+		SLABreached:            message.GetSlaBreached(),
+		PromiseMissed:          message.GetPromiseMissed(),
+		ShipmentStatus:         message.GetShipmentStatus(),
+		LastUpdated:            toTime(message.GetLastUpdated()),
+	}
+	for _, result := range message.GetItemResults() {
+		status.ItemResults = append(status.ItemResults, models.ItemFulfillment{
+			Item:   result.GetItem(),
+			Status: result.GetStatus(),
+			Step:   result.GetStep(),
+			Error:  result.GetError(),
+		})
+	}
+	for _, shipment := range message.GetShipments() {
+		status.Shipments = append(status.Shipments, models.OrderShipment{
+			ShipmentID:     shipment.GetShipmentId(),
+			WarehouseID:    shipment.GetWarehouseId(),
+			Items:          shipment.GetItems(),
+			Status:         shipment.GetStatus(),
+			TrackingStatus: shipment.GetTrackingStatus(),
+		})
+	}
+	for _, vendor := range message.GetVendors() {
+		status.Vendors = append(status.Vendors, models.VendorFulfillment{
+			PurchaseOrder: vendor.GetPurchaseOrder(),
+			VendorID:      vendor.GetVendorId(),
+			Items:         vendor.GetItems(),
+			Status:        vendor.GetStatus(),
+			Reason:        vendor.GetReason(),
+			LastUpdated:   toTime(vendor.GetLastUpdated()),
+		})
+	}
+	return status
+}
+
+// FromPaymentRequest converts a models.PaymentRequest to its message
+func FromPaymentRequest(request *models.PaymentRequest) *PaymentRequest {
+	return &PaymentRequest{OrderId: request.OrderID, Amount: request.Amount, Method: request.Method, PaymentMethod: fromPaymentMethod(request.PaymentMethod)}
+}
+
+// ToPaymentRequest converts a PaymentRequest message to a models.PaymentRequest
+func ToPaymentRequest(message *PaymentRequest) models.PaymentRequest {
+	return models.PaymentRequest{OrderID: message.GetOrderId(), Amount: message.GetAmount(), Method: message.GetMethod(), PaymentMethod: toPaymentMethod(message.GetPaymentMethod())}
+}
+
+// FromPaymentResponse converts a models.PaymentResponse to its message
+func FromPaymentResponse(response *models.PaymentResponse) *PaymentResponse {
+	message := &PaymentResponse{
+		Success:       response.Success,
+		TransactionId: response.TransactionID,
+		Message:       response.Message,
+		Route:         response.Route,
+	}
+	for _, tender := range response.Tenders {
+		message.Tenders = append(message.Tenders, &TenderPayment{
+			Method:        tender.Method,
+			Amount:        tender.Amount,
+			TransactionId: tender.TransactionID,
+		})
+	}
+	if conversion := response.Conversion; conversion != nil {
+		message.Conversion = &CurrencyConversion{
+			From:            conversion.From,
+			To:              conversion.To,
+			Rate:            conversion.Rate,
+			RateAsOf:        fromTime(conversion.RateAsOf),
+			Amount:          conversion.Amount,
+			ConvertedAmount: conversion.ConvertedAmount,
+		}
+	}
+	return message
+}
+
+// ToPaymentResponse converts a PaymentResponse message to a models.PaymentResponse
+func ToPaymentResponse(message *PaymentResponse) models.PaymentResponse {
+	response := models.PaymentResponse{
+		Success:       message.GetSuccess(),
+		TransactionID: message.GetTransactionId(),
+		Message:       message.GetMessage(),
+		Route:         message.GetRoute(),
+	}
+	for _, tender := range message.GetTenders() {
+		response.Tenders = append(response.Tenders, models.TenderPayment{
+			Method:        tender.GetMethod(),
+			Amount:        tender.GetAmount(),
+			TransactionID: tender.GetTransactionId(),
+		})
+	}
+	if conversion := message.GetConversion(); conversion != nil {
+		response.Conversion = &models.CurrencyConversion{
+			From:            conversion.GetFrom(),
+			To:              conversion.GetTo(),
+			Rate:            conversion.GetRate(),
+			RateAsOf:        toTime(conversion.GetRateAsOf()),
+			Amount:          conversion.GetAmount(),
+			ConvertedAmount: conversion.GetConvertedAmount(),
+		}
+	}
+	return response
+}
+
+func fromPriorPayment(payment *models.PaymentResponse) *PaymentResponse {
+	if payment == nil {
+		return nil
+	}
+	return FromPaymentResponse(payment)
+}
+
+func toPriorPayment(message *PaymentResponse) *models.PaymentResponse {
+	if message == nil {
+		return nil
+	}
+	payment := ToPaymentResponse(message)
+	return &payment
+}
+
+func fromCustomer(customer *models.Customer) *Customer {
+	if customer == nil {
+		return nil
+	}
+	return &Customer{Id: customer.ID, Name: customer.Name, Email: customer.Email, Phone: customer.Phone, Tier: customer.Tier}
+}
+
+func toCustomer(message *Customer) *models.Customer {
+	if message == nil {
+		return nil
+	}
+	return &models.Customer{ID: message.GetId(), Name: message.GetName(), Email: message.GetEmail(), Phone: message.GetPhone(), Tier: message.GetTier()}
+}
+
+func fromPaymentMethod(method *models.PaymentMethod) *PaymentMethod {
+	if method == nil {
+		return nil
+	}
+	message := &PaymentMethod{Type: method.Type}
+	if card := method.Card; card != nil {
+		message.Card = &CardMethod{PaymentToken: card.PaymentToken, Brand: card.Brand, Last4: card.Last4, ExpMonth: int32(card.ExpMonth), ExpYear: int32(card.ExpYear)}
+	}
+	if wallet := method.Wallet; wallet != nil {
+		message.Wallet = &WalletMethod{Provider: wallet.Provider, PaymentToken: wallet.PaymentToken}
+	}
+	if bank := method.BankTransfer; bank != nil {
+		message.BankTransfer = &BankTransferMethod{AccountHolder: bank.AccountHolder, AccountNumber: bank.AccountNumber, RoutingNumber: bank.RoutingNumber}
+	}
+	if giftCard := method.GiftCard; giftCard != nil {
+		message.GiftCard = &GiftCardMethod{CardNumber: giftCard.CardNumber}
+	}
+	return message
+}
+
+func toPaymentMethod(message *PaymentMethod) *models.PaymentMethod {
+	if message == nil {
+		return nil
+	}
+	method := &models.PaymentMethod{Type: message.GetType()}
+	if card := message.GetCard(); card != nil {
+		method.Card = &models.CardMethod{PaymentToken: card.GetPaymentToken(), Brand: card.GetBrand(), Last4: card.GetLast4(), ExpMonth: int(card.GetExpMonth()), ExpYear: int(card.GetExpYear())}
+	}
+	if wallet := message.GetWallet(); wallet != nil {
+		method.Wallet = &models.WalletMethod{Provider: wallet.GetProvider(), PaymentToken: wallet.GetPaymentToken()}
+	}
+	if bank := message.GetBankTransfer(); bank != nil {
+		method.BankTransfer = &models.BankTransferMethod{AccountHolder: bank.GetAccountHolder(), AccountNumber: bank.GetAccountNumber(), RoutingNumber: bank.GetRoutingNumber()}
+	}
+	if giftCard := message.GetGiftCard(); giftCard != nil {
+		method.GiftCard = &models.GiftCardMethod{CardNumber: giftCard.GetCardNumber()}
+	}
+	return method
+}
+
+func fromShippingInfo(shipping *models.ShippingInfo) *ShippingInfo {
+	if shipping == nil {
+		return nil
+	}
+	message := &ShippingInfo{Method: shipping.Method, Carrier: shipping.Carrier, Instructions: shipping.Instructions, PromisedBy: fromTime(shipping.PromisedBy)}
+	if address := shipping.Address; address != nil {
+		message.Address = &Address{Name: address.Name, Line1: address.Line1, Line2: address.Line2, City: address.City, Region: address.Region, PostalCode: address.PostalCode, Country: address.Country}
+	}
+	return message
+}
+
+func toShippingInfo(message *ShippingInfo) *models.ShippingInfo {
+	if message == nil {
+		return nil
+	}
+	shipping := &models.ShippingInfo{Method: message.GetMethod(), Carrier: message.GetCarrier(), Instructions: message.GetInstructions(), PromisedBy: toTime(message.GetPromisedBy())}
+	if address := message.GetAddress(); address != nil {
+		shipping.Address = &models.Address{Name: address.GetName(), Line1: address.GetLine1(), Line2: address.GetLine2(), City: address.GetCity(), Region: address.GetRegion(), PostalCode: address.GetPostalCode(), Country: address.GetCountry()}
+	}
+	return shipping
+}
+
+func fromOrderLines(lines []models.OrderItem) []*OrderLine {
+	var messages []*OrderLine
+	for _, line := range lines {
+		messages = append(messages, &OrderLine{Sku: line.SKU, Qty: int32(line.Quantity), Price: line.Price, Vendor: line.Vendor})
+	}
+	return messages
+}
+
+func toOrderLines(messages []*OrderLine) []models.OrderItem {
+	var lines []models.OrderItem
+	for _, message := range messages {
+		lines = append(lines, models.OrderItem{SKU: message.GetSku(), Quantity: int(message.GetQty()), Price: message.GetPrice(), Vendor: message.GetVendor()})
+	}
+	return lines
+}
+
+func fromTenders(tenders []models.Tender) []*Tender {
+	var messages []*Tender
+	for _, tender := range tenders {
+		messages = append(messages, &Tender{Method: tender.Method, Amount: tender.Amount, Card: tender.Card})
+	}
+	return messages
+}
+
+func toTenders(messages []*Tender) []models.Tender {
+	var tenders []models.Tender
+	for _, message := range messages {
+		tenders = append(tenders, models.Tender{Method: message.GetMethod(), Amount: message.GetAmount(), Card: message.GetCard()})
+	}
+	return tenders
+}
+
+// fromTime leaves the zero time unset, so it round-trips as the zero time
+func fromTime(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func toTime(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
@@ -0,0 +1,142 @@
+package orderspb
+
+import (
+	"fmt"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+	"google.golang.org/protobuf/proto"
+)
+
+// PayloadConverter encodes the domain models as their protobuf messages, in
+// the binary/protobuf format the Temporal SDKs use for messages, so workers
+// in other languages can read them with orders.proto. It decodes those
+// payloads back into the models, and any other binary/protobuf payload like
+// the SDK's proto converter.
+type PayloadConverter struct {
+	encode bool
+	proto  *converter.ProtoPayloadConverter
+}
+
+// NewPayloadConverter creates a converter that, when encode is false, only
+// decodes, so it can be deployed to every worker before payloads are written
+// in protobuf
+func NewPayloadConverter(encode bool) *PayloadConverter {
+	return &PayloadConverter{encode: encode, proto: converter.NewProtoPayloadConverter()}
+}
+
+// NewDataConverter returns the default data converter with a
+// PayloadConverter ahead of JSON, so the models are encoded as protobuf when
+// encode is set, and decoded from protobuf either way
+func NewDataConverter(encode bool) converter.DataConverter {
+	return converter.NewCompositeDataConverter(
+		converter.NewNilPayloadConverter(),
+		converter.NewByteSlicePayloadConverter(),
+		converter.NewProtoJSONPayloadConverter(),
+		NewPayloadConverter(encode),
+		converter.NewJSONPayloadConverter(),
+	)
+}
+
+// Encoding implements converter.PayloadConverter
+func (c *PayloadConverter) Encoding() string {
+	return converter.MetadataEncodingProto
+}
+
+// ToPayload implements converter.PayloadConverter. It returns nil for values
+// other than the models, leaving them to the next converter.
+func (c *PayloadConverter) ToPayload(value interface{}) (*commonpb.Payload, error) {
+	if !c.encode {
+		return nil, nil
+	}
+	message := toMessage(value)
+	if message == nil {
+		return nil, nil
+	}
+	return c.proto.ToPayload(message)
+}
+
+// toMessage returns the message for a model, or nil for any other value
+func toMessage(value interface{}) proto.Message {
+	switch v := value.(type) {
+	case models.Order:
+		return FromOrder(&v)
+	case *models.Order:
+		if v != nil {
+			return FromOrder(v)
+		}
+	case models.OrderStatus:
+		return FromOrderStatus(&v)
+	case *models.OrderStatus:
+		if v != nil {
+			return FromOrderStatus(v)
+		}
+	case models.PaymentRequest:
+		return FromPaymentRequest(&v)
+	case *models.PaymentRequest:
+		if v != nil {
+			return FromPaymentRequest(v)
+		}
+	case models.PaymentResponse:
+		return FromPaymentResponse(&v)
+	case *models.PaymentResponse:
+		if v != nil {
+			return FromPaymentResponse(v)
+		}
+	}
+	return nil
+}
+
+// FromPayload implements converter.PayloadConverter
+func (c *PayloadConverter) FromPayload(payload *commonpb.Payload, valuePtr interface{}) error {
+	switch target := valuePtr.(type) {
+	case *models.Order:
+		return decode(payload, &Order{}, ToOrder, target)
+	case **models.Order:
+		return decodeNew(payload, &Order{}, ToOrder, target)
+	case *models.OrderStatus:
+		return decode(payload, &OrderStatus{}, ToOrderStatus, target)
+	case **models.OrderStatus:
+		return decodeNew(payload, &OrderStatus{}, ToOrderStatus, target)
+	case *models.PaymentRequest:
+		return decode(payload, &PaymentRequest{}, ToPaymentRequest, target)
+	case **models.PaymentRequest:
+		return decodeNew(payload, &PaymentRequest{}, ToPaymentRequest, target)
+	case *models.PaymentResponse:
+		return decode(payload, &PaymentResponse{}, ToPaymentResponse, target)
+	case **models.PaymentResponse:
+		return decodeNew(payload, &PaymentResponse{}, ToPaymentResponse, target)
+	default:
+		return c.proto.FromPayload(payload, valuePtr)
+	}
+}
+
+// decode unmarshals payload into message, checking it holds that message
+// type, and stores the converted model in target
+func decode[M proto.Message, T any](payload *commonpb.Payload, message M, toModel func(M) T, target *T) error {
+	want := string(message.ProtoReflect().Descriptor().FullName())
+	if got := string(payload.GetMetadata()[converter.MetadataMessageType]); got != want {
+		return fmt.Errorf("%w: payload holds %q, not %q", converter.ErrUnableToDecode, got, want)
+	}
+	if err := proto.Unmarshal(payload.GetData(), message); err != nil {
+		return fmt.Errorf("%w: %v", converter.ErrUnableToDecode, err)
+	}
+	*target = toModel(message)
+	return nil
+}
+
+// decodeNew is decode for a pointer target
+func decodeNew[M proto.Message, T any](payload *commonpb.Payload, message M, toModel func(M) T, target **T) error {
+	var value T
+	if err := decode(payload, message, toModel, &value); err != nil {
+		return err
+	}
+	*target = &value
+	return nil
+}
+
+// ToString implements converter.PayloadConverter
+func (c *PayloadConverter) ToString(payload *commonpb.Payload) string {
+	return c.proto.ToString(payload)
+}
@@ -0,0 +1,1835 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: proto/orderspb/orders.proto
+
+// Messages for the payloads the order workflows exchange, shared with
+// consumers in other languages. Field numbers are part of the wire format:
+// never reuse or renumber them, and reserve the numbers of removed fields.
+
+package orderspb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Order is the input of the order workflow
+type Order struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Items     []string               `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	Amount    float64                `protobuf:"fixed64,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Status    string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// Zero uses the workflow default
+	FulfillmentParallelism int32 `protobuf:"varint,6,opt,name=fulfillment_parallelism,json=fulfillmentParallelism,proto3" json:"fulfillment_parallelism,omitempty"`
+	// Split-tender payment, charged in order; empty charges one method
+	Tenders []*Tender `protobuf:"bytes,7,rep,name=tenders,proto3" json:"tenders,omitempty"`
+	// Above one pays the amount in that many scheduled installments
+	Installments int32 `protobuf:"varint,8,opt,name=installments,proto3" json:"installments,omitempty"`
+	// Loyalty points spent on the order; amount is what remains to pay
+	RedeemPoints int32 `protobuf:"varint,9,opt,name=redeem_points,json=redeemPoints,proto3" json:"redeem_points,omitempty"`
+	// Marketplace items and the vendors that dropship them
+	Vendors map[string]string `protobuf:"bytes,10,rep,name=vendors,proto3" json:"vendors,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// ISO 4217 code of amount and the tenders; empty is the base currency
+	Currency string `protobuf:"bytes,11,opt,name=currency,proto3" json:"currency,omitempty"`
+	// Who placed the order and how to reach them; unset for a guest order
+	Customer *Customer `protobuf:"bytes,12,opt,name=customer,proto3" json:"customer,omitempty"`
+	// Order schema version; unset is version 1, which has no lines
+	SchemaVersion int32 `protobuf:"varint,13,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"`
+	// Structured lines the items and amount are derived from
+	Lines []*OrderLine `protobuf:"bytes,14,rep,name=lines,proto3" json:"lines,omitempty"`
+	// How the order is paid; unset charges the default method
+	PaymentMethod *PaymentMethod `protobuf:"bytes,15,opt,name=payment_method,json=paymentMethod,proto3" json:"payment_method,omitempty"`
+	// How and where the order is delivered; unset ships it standard
+	Shipping *ShippingInfo `protobuf:"bytes,16,opt,name=shipping,proto3" json:"shipping,omitempty"`
+	// The payment a re-driven order was already charged
+	PriorPayment  *PaymentResponse `protobuf:"bytes,17,opt,name=prior_payment,json=priorPayment,proto3" json:"prior_payment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Order) Reset() {
+	*x = Order{}
+	mi := &file_proto_orderspb_orders_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Order) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Order) ProtoMessage() {}
+
+func (x *Order) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderspb_orders_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Order.ProtoReflect.Descriptor instead.
+func (*Order) Descriptor() ([]byte, []int) {
+	return file_proto_orderspb_orders_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Order) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Order) GetItems() []string {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *Order) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Order) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Order) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Order) GetFulfillmentParallelism() int32 {
+	if x != nil {
+		return x.FulfillmentParallelism
+	}
+	return 0
+}
+
+func (x *Order) GetTenders() []*Tender {
+	if x != nil {
+		return x.Tenders
+	}
+	return nil
+}
+
+func (x *Order) GetInstallments() int32 {
+	if x != nil {
+		return x.Installments
+	}
+	return 0
+}
+
+func (x *Order) GetRedeemPoints() int32 {
+	if x != nil {
+		return x.RedeemPoints
+	}
+	return 0
+}
+
+func (x *Order) GetVendors() map[string]string {
+	if x != nil {
+		return x.Vendors
+	}
+	return nil
+}
+
+func (x *Order) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Order) GetCustomer() *Customer {
+	if x != nil {
+		return x.Customer
+	}
+	return nil
+}
+
+func (x *Order) GetSchemaVersion() int32 {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return 0
+}
+
+func (x *Order) GetLines() []*OrderLine {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+func (x *Order) GetPaymentMethod() *PaymentMethod {
+	if x != nil {
+		return x.PaymentMethod
+	}
+	return nil
+}
+
+func (x *Order) GetShipping() *ShippingInfo {
+	if x != nil {
+		return x.Shipping
+	}
+	return nil
+}
+
+func (x *Order) GetPriorPayment() *PaymentResponse {
+	if x != nil {
+		return x.PriorPayment
+	}
+	return nil
+}
+
+// OrderLine is a structured line of an order
+type OrderLine struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Sku   string                 `protobuf:"bytes,1,opt,name=sku,proto3" json:"sku,omitempty"`
+	Qty   int32                  `protobuf:"varint,2,opt,name=qty,proto3" json:"qty,omitempty"`
+	Price float64                `protobuf:"fixed64,3,opt,name=price,proto3" json:"price,omitempty"`
+	// The vendor that dropships the line, for a marketplace item
+	Vendor        string `protobuf:"bytes,4,opt,name=vendor,proto3" json:"vendor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderLine) Reset() {
+	*x = OrderLine{}
+	mi := &file_proto_orderspb_orders_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderLine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderLine) ProtoMessage() {}
+
+func (x *OrderLine) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderspb_orders_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderLine.ProtoReflect.Descriptor instead.
+func (*OrderLine) Descriptor() ([]byte, []int) {
+	return file_proto_orderspb_orders_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *OrderLine) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *OrderLine) GetQty() int32 {
+	if x != nil {
+		return x.Qty
+	}
+	return 0
+}
+
+func (x *OrderLine) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *OrderLine) GetVendor() string {
+	if x != nil {
+		return x.Vendor
+	}
+	return ""
+}
+
+// ItemFulfillment is the outcome of fulfilling one item of an order
+type ItemFulfillment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Item          string                 `protobuf:"bytes,1,opt,name=item,proto3" json:"item,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Step          string                 `protobuf:"bytes,3,opt,name=step,proto3" json:"step,omitempty"`
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ItemFulfillment) Reset() {
+	*x = ItemFulfillment{}
+	mi := &file_proto_orderspb_orders_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ItemFulfillment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ItemFulfillment) ProtoMessage() {}
+
+func (x *ItemFulfillment) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderspb_orders_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ItemFulfillment.ProtoReflect.Descriptor instead.
+func (*ItemFulfillment) Descriptor() ([]byte, []int) {
+	return file_proto_orderspb_orders_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ItemFulfillment) GetItem() string {
+	if x != nil {
+		return x.Item
+	}
+	return ""
+}
+
+func (x *ItemFulfillment) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ItemFulfillment) GetStep() string {
+	if x != nil {
+		return x.Step
+	}
+	return ""
+}
+
+func (x *ItemFulfillment) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// OrderShipment is one shipment of an order fulfilled from several warehouses
+type OrderShipment struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ShipmentId     string                 `protobuf:"bytes,1,opt,name=shipment_id,json=shipmentId,proto3" json:"shipment_id,omitempty"`
+	WarehouseId    string                 `protobuf:"bytes,2,opt,name=warehouse_id,json=warehouseId,proto3" json:"warehouse_id,omitempty"`
+	Items          []string               `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	Status         string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	TrackingStatus string                 `protobuf:"bytes,5,opt,name=tracking_status,json=trackingStatus,proto3" json:"tracking_status,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *OrderShipment) Reset() {
+	*x = OrderShipment{}
+	mi := &file_proto_orderspb_orders_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderShipment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderShipment) ProtoMessage() {}
+
+func (x *OrderShipment) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderspb_orders_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderShipment.ProtoReflect.Descriptor instead.
+func (*OrderShipment) Descriptor() ([]byte, []int) {
+	return file_proto_orderspb_orders_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *OrderShipment) GetShipmentId() string {
+	if x != nil {
+		return x.ShipmentId
+	}
+	return ""
+}
+
+func (x *OrderShipment) GetWarehouseId() string {
+	if x != nil {
+		return x.WarehouseId
+	}
+	return ""
+}
+
+func (x *OrderShipment) GetItems() []string {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *OrderShipment) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *OrderShipment) GetTrackingStatus() string {
+	if x != nil {
+		return x.TrackingStatus
+	}
+	return ""
+}
+
+// VendorFulfillment is the state of a purchase order to a dropship vendor
+type VendorFulfillment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PurchaseOrder string                 `protobuf:"bytes,1,opt,name=purchase_order,json=purchaseOrder,proto3" json:"purchase_order,omitempty"`
+	VendorId      string                 `protobuf:"bytes,2,opt,name=vendor_id,json=vendorId,proto3" json:"vendor_id,omitempty"`
+	Items         []string               `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	Reason        string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	LastUpdated   *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VendorFulfillment) Reset() {
+	*x = VendorFulfillment{}
+	mi := &file_proto_orderspb_orders_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VendorFulfillment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VendorFulfillment) ProtoMessage() {}
+
+func (x *VendorFulfillment) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderspb_orders_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VendorFulfillment.ProtoReflect.Descriptor instead.
+func (*VendorFulfillment) Descriptor() ([]byte, []int) {
+	return file_proto_orderspb_orders_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *VendorFulfillment) GetPurchaseOrder() string {
+	if x != nil {
+		return x.PurchaseOrder
+	}
+	return ""
+}
+
+func (x *VendorFulfillment) GetVendorId() string {
+	if x != nil {
+		return x.VendorId
+	}
+	return ""
+}
+
+func (x *VendorFulfillment) GetItems() []string {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *VendorFulfillment) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *VendorFulfillment) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *VendorFulfillment) GetLastUpdated() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastUpdated
+	}
+	return nil
+}
+
+// OrderStatus is the state of an order, returned by the getStatus query and
+// as the workflow result
+type OrderStatus struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	OrderId                string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Status                 string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Stage                  string                 `protobuf:"bytes,3,opt,name=stage,proto3" json:"stage,omitempty"`
+	IsExpedited            bool                   `protobuf:"varint,4,opt,name=is_expedited,json=isExpedited,proto3" json:"is_expedited,omitempty"`
+	PaymentStatus          string                 `protobuf:"bytes,5,opt,name=payment_status,json=paymentStatus,proto3" json:"payment_status,omitempty"`
+	ProvisionallyValidated bool                   `protobuf:"varint,6,opt,name=provisionally_validated,json=provisionallyValidated,proto3" json:"provisionally_validated,omitempty"`
+	ItemResults            []*ItemFulfillment     `protobuf:"bytes,7,rep,name=item_results,json=itemResults,proto3" json:"item_results,omitempty"`
+	InvoiceUrl             string                 `protobuf:"bytes,8,opt,name=invoice_url,json=invoiceUrl,proto3" json:"invoice_url,omitempty"`
+	SlaBreached            bool                   `protobuf:"varint,9,opt,name=sla_breached,json=slaBreached,proto3" json:"sla_breached,omitempty"`
+	LastUpdated            *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"`
+	ShipmentStatus         string                 `protobuf:"bytes,11,opt,name=shipment_status,json=shipmentStatus,proto3" json:"shipment_status,omitempty"`
+	// One per warehouse when the order is fulfilled from several
+	Shipments []*OrderShipment `protobuf:"bytes,12,rep,name=shipments,proto3" json:"shipments,omitempty"`
+	// Purchase orders of the dropshipped items
+	Vendors []*VendorFulfillment `protobuf:"bytes,13,rep,name=vendors,proto3" json:"vendors,omitempty"`
+	// Set once the order is not delivered by its promised delivery
+	PromiseMissed bool `protobuf:"varint,14,opt,name=promise_missed,json=promiseMissed,proto3" json:"promise_missed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderStatus) Reset() {
+	*x = OrderStatus{}
+	mi := &file_proto_orderspb_orders_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderStatus) ProtoMessage() {}
+
+func (x *OrderStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderspb_orders_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderStatus.ProtoReflect.Descriptor instead.
+func (*OrderStatus) Descriptor() ([]byte, []int) {
+	return file_proto_orderspb_orders_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *OrderStatus) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *OrderStatus) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *OrderStatus) GetStage() string {
+	if x != nil {
+		return x.Stage
+	}
+	return ""
+}
+
+func (x *OrderStatus) GetIsExpedited() bool {
+	if x != nil {
+		return x.IsExpedited
+	}
+	return false
+}
+
+func (x *OrderStatus) GetPaymentStatus() string {
+	if x != nil {
+		return x.PaymentStatus
+	}
+	return ""
+}
+
+func (x *OrderStatus) GetProvisionallyValidated() bool {
+	if x != nil {
+		return x.ProvisionallyValidated
+	}
+	return false
+}
+
+func (x *OrderStatus) GetItemResults() []*ItemFulfillment {
+	if x != nil {
+		return x.ItemResults
+	}
+	return nil
+}
+
+func (x *OrderStatus) GetInvoiceUrl() string {
+	if x != nil {
+		return x.InvoiceUrl
+	}
+	return ""
+}
+
+func (x *OrderStatus) GetSlaBreached() bool {
+	if x != nil {
+		return x.SlaBreached
+	}
+	return false
+}
+
+func (x *OrderStatus) GetLastUpdated() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastUpdated
+	}
+	return nil
+}
+
+func (x *OrderStatus) GetShipmentStatus() string {
+	if x != nil {
+		return x.ShipmentStatus
+	}
+	return ""
+}
+
+func (x *OrderStatus) GetShipments() []*OrderShipment {
+	if x != nil {
+		return x.Shipments
+	}
+	return nil
+}
+
+func (x *OrderStatus) GetVendors() []*VendorFulfillment {
+	if x != nil {
+		return x.Vendors
+	}
+	return nil
+}
+
+func (x *OrderStatus) GetPromiseMissed() bool {
+	if x != nil {
+		return x.PromiseMissed
+	}
+	return false
+}
+
+// PaymentRequest is the input of the payment workflow and activity
+type PaymentRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	OrderId string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Amount  float64                `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	// Empty charges the default method
+	Method string `protobuf:"bytes,3,opt,name=method,proto3" json:"method,omitempty"`
+	// The card, wallet, bank account, or gift card charged
+	PaymentMethod *PaymentMethod `protobuf:"bytes,4,opt,name=payment_method,json=paymentMethod,proto3" json:"payment_method,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PaymentRequest) Reset() {
+	*x = PaymentRequest{}
+	mi := &file_proto_orderspb_orders_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentRequest) ProtoMessage() {}
+
+func (x *PaymentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderspb_orders_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentRequest.ProtoReflect.Descriptor instead.
+func (*PaymentRequest) Descriptor() ([]byte, []int) {
+	return file_proto_orderspb_orders_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PaymentRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *PaymentRequest) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *PaymentRequest) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *PaymentRequest) GetPaymentMethod() *PaymentMethod {
+	if x != nil {
+		return x.PaymentMethod
+	}
+	return nil
+}
+
+// PaymentResponse is the result of a payment
+type PaymentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	TransactionId string                 `protobuf:"bytes,2,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	// The capture of each tender of a split-tender payment
+	Tenders []*TenderPayment `protobuf:"bytes,4,rep,name=tenders,proto3" json:"tenders,omitempty"`
+	// The exchange rate an order in another currency was charged at
+	Conversion *CurrencyConversion `protobuf:"bytes,5,opt,name=conversion,proto3" json:"conversion,omitempty"`
+	// The network the payment method was charged through
+	Route         string `protobuf:"bytes,6,opt,name=route,proto3" json:"route,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PaymentResponse) Reset() {
+	*x = PaymentResponse{}
+	mi := &file_proto_orderspb_orders_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentResponse) ProtoMessage() {}
+
+func (x *PaymentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderspb_orders_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentResponse.ProtoReflect.Descriptor instead.
+func (*PaymentResponse) Descriptor() ([]byte, []int) {
+	return file_proto_orderspb_orders_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PaymentResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *PaymentResponse) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *PaymentResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *PaymentResponse) GetTenders() []*TenderPayment {
+	if x != nil {
+		return x.Tenders
+	}
+	return nil
+}
+
+func (x *PaymentResponse) GetConversion() *CurrencyConversion {
+	if x != nil {
+		return x.Conversion
+	}
+	return nil
+}
+
+func (x *PaymentResponse) GetRoute() string {
+	if x != nil {
+		return x.Route
+	}
+	return ""
+}
+
+// PaymentMethod is a tokenized card, a wallet, a bank transfer, or a gift
+// card; type names the one whose details are set
+type PaymentMethod struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Card          *CardMethod            `protobuf:"bytes,2,opt,name=card,proto3" json:"card,omitempty"`
+	Wallet        *WalletMethod          `protobuf:"bytes,3,opt,name=wallet,proto3" json:"wallet,omitempty"`
+	BankTransfer  *BankTransferMethod    `protobuf:"bytes,4,opt,name=bank_transfer,json=bankTransfer,proto3" json:"bank_transfer,omitempty"`
+	GiftCard      *GiftCardMethod        `protobuf:"bytes,5,opt,name=gift_card,json=giftCard,proto3" json:"gift_card,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PaymentMethod) Reset() {
+	*x = PaymentMethod{}
+	mi := &file_proto_orderspb_orders_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentMethod) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentMethod) ProtoMessage() {}
+
+func (x *PaymentMethod) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderspb_orders_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentMethod.ProtoReflect.Descriptor instead.
+func (*PaymentMethod) Descriptor() ([]byte, []int) {
+	return file_proto_orderspb_orders_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PaymentMethod) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *PaymentMethod) GetCard() *CardMethod {
+	if x != nil {
+		return x.Card
+	}
+	return nil
+}
+
+func (x *PaymentMethod) GetWallet() *WalletMethod {
+	if x != nil {
+		return x.Wallet
+	}
+	return nil
+}
+
+func (x *PaymentMethod) GetBankTransfer() *BankTransferMethod {
+	if x != nil {
+		return x.BankTransfer
+	}
+	return nil
+}
+
+func (x *PaymentMethod) GetGiftCard() *GiftCardMethod {
+	if x != nil {
+		return x.GiftCard
+	}
+	return nil
+}
+
+// CardMethod is a card tokenized by the payment gateway
+type CardMethod struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PaymentToken  string                 `protobuf:"bytes,1,opt,name=payment_token,json=paymentToken,proto3" json:"payment_token,omitempty"`
+	Brand         string                 `protobuf:"bytes,2,opt,name=brand,proto3" json:"brand,omitempty"`
+	Last4         string                 `protobuf:"bytes,3,opt,name=last4,proto3" json:"last4,omitempty"`
+	ExpMonth      int32                  `protobuf:"varint,4,opt,name=exp_month,json=expMonth,proto3" json:"exp_month,omitempty"`
+	ExpYear       int32                  `protobuf:"varint,5,opt,name=exp_year,json=expYear,proto3" json:"exp_year,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CardMethod) Reset() {
+	*x = CardMethod{}
+	mi := &file_proto_orderspb_orders_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CardMethod) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CardMethod) ProtoMessage() {}
+
+func (x *CardMethod) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderspb_orders_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CardMethod.ProtoReflect.Descriptor instead.
+func (*CardMethod) Descriptor() ([]byte, []int) {
+	return file_proto_orderspb_orders_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CardMethod) GetPaymentToken() string {
+	if x != nil {
+		return x.PaymentToken
+	}
+	return ""
+}
+
+func (x *CardMethod) GetBrand() string {
+	if x != nil {
+		return x.Brand
+	}
+	return ""
+}
+
+func (x *CardMethod) GetLast4() string {
+	if x != nil {
+		return x.Last4
+	}
+	return ""
+}
+
+func (x *CardMethod) GetExpMonth() int32 {
+	if x != nil {
+		return x.ExpMonth
+	}
+	return 0
+}
+
+func (x *CardMethod) GetExpYear() int32 {
+	if x != nil {
+		return x.ExpYear
+	}
+	return 0
+}
+
+// WalletMethod is a payment token issued by a wallet provider
+type WalletMethod struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Provider      string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	PaymentToken  string                 `protobuf:"bytes,2,opt,name=payment_token,json=paymentToken,proto3" json:"payment_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WalletMethod) Reset() {
+	*x = WalletMethod{}
+	mi := &file_proto_orderspb_orders_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WalletMethod) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WalletMethod) ProtoMessage() {}
+
+func (x *WalletMethod) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderspb_orders_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WalletMethod.ProtoReflect.Descriptor instead.
+func (*WalletMethod) Descriptor() ([]byte, []int) {
+	return file_proto_orderspb_orders_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *WalletMethod) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *WalletMethod) GetPaymentToken() string {
+	if x != nil {
+		return x.PaymentToken
+	}
+	return ""
+}
+
+// BankTransferMethod debits a bank account
+type BankTransferMethod struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountHolder string                 `protobuf:"bytes,1,opt,name=account_holder,json=accountHolder,proto3" json:"account_holder,omitempty"`
+	AccountNumber string                 `protobuf:"bytes,2,opt,name=account_number,json=accountNumber,proto3" json:"account_number,omitempty"`
+	RoutingNumber string                 `protobuf:"bytes,3,opt,name=routing_number,json=routingNumber,proto3" json:"routing_number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BankTransferMethod) Reset() {
+	*x = BankTransferMethod{}
+	mi := &file_proto_orderspb_orders_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BankTransferMethod) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BankTransferMethod) ProtoMessage() {}
+
+func (x *BankTransferMethod) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderspb_orders_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BankTransferMethod.ProtoReflect.Descriptor instead.
+func (*BankTransferMethod) Descriptor() ([]byte, []int) {
+	return file_proto_orderspb_orders_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BankTransferMethod) GetAccountHolder() string {
+	if x != nil {
+		return x.AccountHolder
+	}
+	return ""
+}
+
+func (x *BankTransferMethod) GetAccountNumber() string {
+	if x != nil {
+		return x.AccountNumber
+	}
+	return ""
+}
+
+func (x *BankTransferMethod) GetRoutingNumber() string {
+	if x != nil {
+		return x.RoutingNumber
+	}
+	return ""
+}
+
+// GiftCardMethod charges a gift card
+type GiftCardMethod struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CardNumber    string                 `protobuf:"bytes,1,opt,name=card_number,json=cardNumber,proto3" json:"card_number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GiftCardMethod) Reset() {
+	*x = GiftCardMethod{}
+	mi := &file_proto_orderspb_orders_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GiftCardMethod) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GiftCardMethod) ProtoMessage() {}
+
+func (x *GiftCardMethod) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderspb_orders_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GiftCardMethod.ProtoReflect.Descriptor instead.
+func (*GiftCardMethod) Descriptor() ([]byte, []int) {
+	return file_proto_orderspb_orders_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GiftCardMethod) GetCardNumber() string {
+	if x != nil {
+		return x.CardNumber
+	}
+	return ""
+}
+
+// ShippingInfo is how and where an order is delivered, and by when it was
+// promised
+type ShippingInfo struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// standard, expedited, overnight, or pickup
+	Method  string `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
+	Carrier string `protobuf:"bytes,2,opt,name=carrier,proto3" json:"carrier,omitempty"`
+	// Unset for pickup
+	Address       *Address               `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	Instructions  string                 `protobuf:"bytes,4,opt,name=instructions,proto3" json:"instructions,omitempty"`
+	PromisedBy    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=promised_by,json=promisedBy,proto3" json:"promised_by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShippingInfo) Reset() {
+	*x = ShippingInfo{}
+	mi := &file_proto_orderspb_orders_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShippingInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShippingInfo) ProtoMessage() {}
+
+func (x *ShippingInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderspb_orders_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShippingInfo.ProtoReflect.Descriptor instead.
+func (*ShippingInfo) Descriptor() ([]byte, []int) {
+	return file_proto_orderspb_orders_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ShippingInfo) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *ShippingInfo) GetCarrier() string {
+	if x != nil {
+		return x.Carrier
+	}
+	return ""
+}
+
+func (x *ShippingInfo) GetAddress() *Address {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+func (x *ShippingInfo) GetInstructions() string {
+	if x != nil {
+		return x.Instructions
+	}
+	return ""
+}
+
+func (x *ShippingInfo) GetPromisedBy() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PromisedBy
+	}
+	return nil
+}
+
+// Address is a postal address; country is the ISO 3166-1 alpha-2 code
+type Address struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Line1         string                 `protobuf:"bytes,2,opt,name=line1,proto3" json:"line1,omitempty"`
+	Line2         string                 `protobuf:"bytes,3,opt,name=line2,proto3" json:"line2,omitempty"`
+	City          string                 `protobuf:"bytes,4,opt,name=city,proto3" json:"city,omitempty"`
+	Region        string                 `protobuf:"bytes,5,opt,name=region,proto3" json:"region,omitempty"`
+	PostalCode    string                 `protobuf:"bytes,6,opt,name=postal_code,json=postalCode,proto3" json:"postal_code,omitempty"`
+	Country       string                 `protobuf:"bytes,7,opt,name=country,proto3" json:"country,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Address) Reset() {
+	*x = Address{}
+	mi := &file_proto_orderspb_orders_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Address) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Address) ProtoMessage() {}
+
+func (x *Address) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderspb_orders_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Address.ProtoReflect.Descriptor instead.
+func (*Address) Descriptor() ([]byte, []int) {
+	return file_proto_orderspb_orders_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Address) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Address) GetLine1() string {
+	if x != nil {
+		return x.Line1
+	}
+	return ""
+}
+
+func (x *Address) GetLine2() string {
+	if x != nil {
+		return x.Line2
+	}
+	return ""
+}
+
+func (x *Address) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *Address) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *Address) GetPostalCode() string {
+	if x != nil {
+		return x.PostalCode
+	}
+	return ""
+}
+
+func (x *Address) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+// Customer is who placed an order and how to reach them
+type Customer struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Email string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	Phone string                 `protobuf:"bytes,4,opt,name=phone,proto3" json:"phone,omitempty"`
+	// Loyalty tier: standard, silver, gold, or platinum; empty when unknown
+	Tier          string `protobuf:"bytes,5,opt,name=tier,proto3" json:"tier,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Customer) Reset() {
+	*x = Customer{}
+	mi := &file_proto_orderspb_orders_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Customer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Customer) ProtoMessage() {}
+
+func (x *Customer) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderspb_orders_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Customer.ProtoReflect.Descriptor instead.
+func (*Customer) Descriptor() ([]byte, []int) {
+	return file_proto_orderspb_orders_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Customer) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Customer) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Customer) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *Customer) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *Customer) GetTier() string {
+	if x != nil {
+		return x.Tier
+	}
+	return ""
+}
+
+// Tender is one payment method paying part of an order
+type Tender struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Method string                 `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
+	Amount float64                `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	// The gift card whose balance is held, for a gift card tender
+	Card          string `protobuf:"bytes,3,opt,name=card,proto3" json:"card,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Tender) Reset() {
+	*x = Tender{}
+	mi := &file_proto_orderspb_orders_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Tender) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tender) ProtoMessage() {}
+
+func (x *Tender) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderspb_orders_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tender.ProtoReflect.Descriptor instead.
+func (*Tender) Descriptor() ([]byte, []int) {
+	return file_proto_orderspb_orders_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Tender) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *Tender) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Tender) GetCard() string {
+	if x != nil {
+		return x.Card
+	}
+	return ""
+}
+
+// TenderPayment is the capture of one tender of a split-tender payment
+type TenderPayment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Method        string                 `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
+	Amount        float64                `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	TransactionId string                 `protobuf:"bytes,3,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TenderPayment) Reset() {
+	*x = TenderPayment{}
+	mi := &file_proto_orderspb_orders_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TenderPayment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TenderPayment) ProtoMessage() {}
+
+func (x *TenderPayment) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderspb_orders_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TenderPayment.ProtoReflect.Descriptor instead.
+func (*TenderPayment) Descriptor() ([]byte, []int) {
+	return file_proto_orderspb_orders_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *TenderPayment) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *TenderPayment) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *TenderPayment) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+// CurrencyConversion is an amount converted at an exchange rate
+type CurrencyConversion struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	From            string                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To              string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Rate            float64                `protobuf:"fixed64,3,opt,name=rate,proto3" json:"rate,omitempty"`
+	RateAsOf        *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=rate_as_of,json=rateAsOf,proto3" json:"rate_as_of,omitempty"`
+	Amount          float64                `protobuf:"fixed64,5,opt,name=amount,proto3" json:"amount,omitempty"`
+	ConvertedAmount float64                `protobuf:"fixed64,6,opt,name=converted_amount,json=convertedAmount,proto3" json:"converted_amount,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CurrencyConversion) Reset() {
+	*x = CurrencyConversion{}
+	mi := &file_proto_orderspb_orders_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CurrencyConversion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CurrencyConversion) ProtoMessage() {}
+
+func (x *CurrencyConversion) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_orderspb_orders_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CurrencyConversion.ProtoReflect.Descriptor instead.
+func (*CurrencyConversion) Descriptor() ([]byte, []int) {
+	return file_proto_orderspb_orders_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *CurrencyConversion) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *CurrencyConversion) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *CurrencyConversion) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *CurrencyConversion) GetRateAsOf() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RateAsOf
+	}
+	return nil
+}
+
+func (x *CurrencyConversion) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *CurrencyConversion) GetConvertedAmount() float64 {
+	if x != nil {
+		return x.ConvertedAmount
+	}
+	return 0
+}
+
+var File_proto_orderspb_orders_proto protoreflect.FileDescriptor
+
+const file_proto_orderspb_orders_proto_rawDesc = "" +
+	"\n" +
+	"\x1bproto/orderspb/orders.proto\x12\x12orderprocessing.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xd2\x06\n" +
+	"\x05Order\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05items\x18\x02 \x03(\tR\x05items\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x01R\x06amount\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x127\n" +
+	"\x17fulfillment_parallelism\x18\x06 \x01(\x05R\x16fulfillmentParallelism\x124\n" +
+	"\atenders\x18\a \x03(\v2\x1a.orderprocessing.v1.TenderR\atenders\x12\"\n" +
+	"\finstallments\x18\b \x01(\x05R\finstallments\x12#\n" +
+	"\rredeem_points\x18\t \x01(\x05R\fredeemPoints\x12@\n" +
+	"\avendors\x18\n" +
+	" \x03(\v2&.orderprocessing.v1.Order.VendorsEntryR\avendors\x12\x1a\n" +
+	"\bcurrency\x18\v \x01(\tR\bcurrency\x128\n" +
+	"\bcustomer\x18\f \x01(\v2\x1c.orderprocessing.v1.CustomerR\bcustomer\x12%\n" +
+	"\x0eschema_version\x18\r \x01(\x05R\rschemaVersion\x123\n" +
+	"\x05lines\x18\x0e \x03(\v2\x1d.orderprocessing.v1.OrderLineR\x05lines\x12H\n" +
+	"\x0epayment_method\x18\x0f \x01(\v2!.orderprocessing.v1.PaymentMethodR\rpaymentMethod\x12<\n" +
+	"\bshipping\x18\x10 \x01(\v2 .orderprocessing.v1.ShippingInfoR\bshipping\x12H\n" +
+	"\rprior_payment\x18\x11 \x01(\v2#.orderprocessing.v1.PaymentResponseR\fpriorPayment\x1a:\n" +
+	"\fVendorsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"]\n" +
+	"\tOrderLine\x12\x10\n" +
+	"\x03sku\x18\x01 \x01(\tR\x03sku\x12\x10\n" +
+	"\x03qty\x18\x02 \x01(\x05R\x03qty\x12\x14\n" +
+	"\x05price\x18\x03 \x01(\x01R\x05price\x12\x16\n" +
+	"\x06vendor\x18\x04 \x01(\tR\x06vendor\"g\n" +
+	"\x0fItemFulfillment\x12\x12\n" +
+	"\x04item\x18\x01 \x01(\tR\x04item\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x12\n" +
+	"\x04step\x18\x03 \x01(\tR\x04step\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"\xaa\x01\n" +
+	"\rOrderShipment\x12\x1f\n" +
+	"\vshipment_id\x18\x01 \x01(\tR\n" +
+	"shipmentId\x12!\n" +
+	"\fwarehouse_id\x18\x02 \x01(\tR\vwarehouseId\x12\x14\n" +
+	"\x05items\x18\x03 \x03(\tR\x05items\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12'\n" +
+	"\x0ftracking_status\x18\x05 \x01(\tR\x0etrackingStatus\"\xdc\x01\n" +
+	"\x11VendorFulfillment\x12%\n" +
+	"\x0epurchase_order\x18\x01 \x01(\tR\rpurchaseOrder\x12\x1b\n" +
+	"\tvendor_id\x18\x02 \x01(\tR\bvendorId\x12\x14\n" +
+	"\x05items\x18\x03 \x03(\tR\x05items\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\x12=\n" +
+	"\flast_updated\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\vlastUpdated\"\xf6\x04\n" +
+	"\vOrderStatus\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x14\n" +
+	"\x05stage\x18\x03 \x01(\tR\x05stage\x12!\n" +
+	"\fis_expedited\x18\x04 \x01(\bR\visExpedited\x12%\n" +
+	"\x0epayment_status\x18\x05 \x01(\tR\rpaymentStatus\x127\n" +
+	"\x17provisionally_validated\x18\x06 \x01(\bR\x16provisionallyValidated\x12F\n" +
+	"\fitem_results\x18\a \x03(\v2#.orderprocessing.v1.ItemFulfillmentR\vitemResults\x12\x1f\n" +
+	"\vinvoice_url\x18\b \x01(\tR\n" +
+	"invoiceUrl\x12!\n" +
+	"\fsla_breached\x18\t \x01(\bR\vslaBreached\x12=\n" +
+	"\flast_updated\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\vlastUpdated\x12'\n" +
+	"\x0fshipment_status\x18\v \x01(\tR\x0eshipmentStatus\x12?\n" +
+	"\tshipments\x18\f \x03(\v2!.orderprocessing.v1.OrderShipmentR\tshipments\x12?\n" +
+	"\avendors\x18\r \x03(\v2%.orderprocessing.v1.VendorFulfillmentR\avendors\x12%\n" +
+	"\x0epromise_missed\x18\x0e \x01(\bR\rpromiseMissed\"\xa5\x01\n" +
+	"\x0ePaymentRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x01R\x06amount\x12\x16\n" +
+	"\x06method\x18\x03 \x01(\tR\x06method\x12H\n" +
+	"\x0epayment_method\x18\x04 \x01(\v2!.orderprocessing.v1.PaymentMethodR\rpaymentMethod\"\x87\x02\n" +
+	"\x0fPaymentResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12%\n" +
+	"\x0etransaction_id\x18\x02 \x01(\tR\rtransactionId\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x12;\n" +
+	"\atenders\x18\x04 \x03(\v2!.orderprocessing.v1.TenderPaymentR\atenders\x12F\n" +
+	"\n" +
+	"conversion\x18\x05 \x01(\v2&.orderprocessing.v1.CurrencyConversionR\n" +
+	"conversion\x12\x14\n" +
+	"\x05route\x18\x06 \x01(\tR\x05route\"\x9f\x02\n" +
+	"\rPaymentMethod\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x122\n" +
+	"\x04card\x18\x02 \x01(\v2\x1e.orderprocessing.v1.CardMethodR\x04card\x128\n" +
+	"\x06wallet\x18\x03 \x01(\v2 .orderprocessing.v1.WalletMethodR\x06wallet\x12K\n" +
+	"\rbank_transfer\x18\x04 \x01(\v2&.orderprocessing.v1.BankTransferMethodR\fbankTransfer\x12?\n" +
+	"\tgift_card\x18\x05 \x01(\v2\".orderprocessing.v1.GiftCardMethodR\bgiftCard\"\x95\x01\n" +
+	"\n" +
+	"CardMethod\x12#\n" +
+	"\rpayment_token\x18\x01 \x01(\tR\fpaymentToken\x12\x14\n" +
+	"\x05brand\x18\x02 \x01(\tR\x05brand\x12\x14\n" +
+	"\x05last4\x18\x03 \x01(\tR\x05last4\x12\x1b\n" +
+	"\texp_month\x18\x04 \x01(\x05R\bexpMonth\x12\x19\n" +
+	"\bexp_year\x18\x05 \x01(\x05R\aexpYear\"O\n" +
+	"\fWalletMethod\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\x12#\n" +
+	"\rpayment_token\x18\x02 \x01(\tR\fpaymentToken\"\x89\x01\n" +
+	"\x12BankTransferMethod\x12%\n" +
+	"\x0eaccount_holder\x18\x01 \x01(\tR\raccountHolder\x12%\n" +
+	"\x0eaccount_number\x18\x02 \x01(\tR\raccountNumber\x12%\n" +
+	"\x0erouting_number\x18\x03 \x01(\tR\rroutingNumber\"1\n" +
+	"\x0eGiftCardMethod\x12\x1f\n" +
+	"\vcard_number\x18\x01 \x01(\tR\n" +
+	"cardNumber\"\xd8\x01\n" +
+	"\fShippingInfo\x12\x16\n" +
+	"\x06method\x18\x01 \x01(\tR\x06method\x12\x18\n" +
+	"\acarrier\x18\x02 \x01(\tR\acarrier\x125\n" +
+	"\aaddress\x18\x03 \x01(\v2\x1b.orderprocessing.v1.AddressR\aaddress\x12\"\n" +
+	"\finstructions\x18\x04 \x01(\tR\finstructions\x12;\n" +
+	"\vpromised_by\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"promisedBy\"\xb0\x01\n" +
+	"\aAddress\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05line1\x18\x02 \x01(\tR\x05line1\x12\x14\n" +
+	"\x05line2\x18\x03 \x01(\tR\x05line2\x12\x12\n" +
+	"\x04city\x18\x04 \x01(\tR\x04city\x12\x16\n" +
+	"\x06region\x18\x05 \x01(\tR\x06region\x12\x1f\n" +
+	"\vpostal_code\x18\x06 \x01(\tR\n" +
+	"postalCode\x12\x18\n" +
+	"\acountry\x18\a \x01(\tR\acountry\"n\n" +
+	"\bCustomer\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\x12\x14\n" +
+	"\x05phone\x18\x04 \x01(\tR\x05phone\x12\x12\n" +
+	"\x04tier\x18\x05 \x01(\tR\x04tier\"L\n" +
+	"\x06Tender\x12\x16\n" +
+	"\x06method\x18\x01 \x01(\tR\x06method\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x01R\x06amount\x12\x12\n" +
+	"\x04card\x18\x03 \x01(\tR\x04card\"f\n" +
+	"\rTenderPayment\x12\x16\n" +
+	"\x06method\x18\x01 \x01(\tR\x06method\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x01R\x06amount\x12%\n" +
+	"\x0etransaction_id\x18\x03 \x01(\tR\rtransactionId\"\xc9\x01\n" +
+	"\x12CurrencyConversion\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\tR\x02to\x12\x12\n" +
+	"\x04rate\x18\x03 \x01(\x01R\x04rate\x128\n" +
+	"\n" +
+	"rate_as_of\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\brateAsOf\x12\x16\n" +
+	"\x06amount\x18\x05 \x01(\x01R\x06amount\x12)\n" +
+	"\x10converted_amount\x18\x06 \x01(\x01R\x0fconvertedAmountBp\n" +
+	"&com.aswathylrbuilds.orderprocessing.v1P\x01ZDgithub.com/aswathylr-builds/temporal-order-processing/proto/orderspbb\x06proto3"
+
+var (
+	file_proto_orderspb_orders_proto_rawDescOnce sync.Once
+	file_proto_orderspb_orders_proto_rawDescData []byte
+)
+
+func file_proto_orderspb_orders_proto_rawDescGZIP() []byte {
+	file_proto_orderspb_orders_proto_rawDescOnce.Do(func() {
+		file_proto_orderspb_orders_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_orderspb_orders_proto_rawDesc), len(file_proto_orderspb_orders_proto_rawDesc)))
+	})
+	return file_proto_orderspb_orders_proto_rawDescData
+}
+
+var file_proto_orderspb_orders_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
+var file_proto_orderspb_orders_proto_goTypes = []any{
+	(*Order)(nil),                 // 0: orderprocessing.v1.Order
+	(*OrderLine)(nil),             // 1: orderprocessing.v1.OrderLine
+	(*ItemFulfillment)(nil),       // 2: orderprocessing.v1.ItemFulfillment
+	(*OrderShipment)(nil),         // 3: orderprocessing.v1.OrderShipment
+	(*VendorFulfillment)(nil),     // 4: orderprocessing.v1.VendorFulfillment
+	(*OrderStatus)(nil),           // 5: orderprocessing.v1.OrderStatus
+	(*PaymentRequest)(nil),        // 6: orderprocessing.v1.PaymentRequest
+	(*PaymentResponse)(nil),       // 7: orderprocessing.v1.PaymentResponse
+	(*PaymentMethod)(nil),         // 8: orderprocessing.v1.PaymentMethod
+	(*CardMethod)(nil),            // 9: orderprocessing.v1.CardMethod
+	(*WalletMethod)(nil),          // 10: orderprocessing.v1.WalletMethod
+	(*BankTransferMethod)(nil),    // 11: orderprocessing.v1.BankTransferMethod
+	(*GiftCardMethod)(nil),        // 12: orderprocessing.v1.GiftCardMethod
+	(*ShippingInfo)(nil),          // 13: orderprocessing.v1.ShippingInfo
+	(*Address)(nil),               // 14: orderprocessing.v1.Address
+	(*Customer)(nil),              // 15: orderprocessing.v1.Customer
+	(*Tender)(nil),                // 16: orderprocessing.v1.Tender
+	(*TenderPayment)(nil),         // 17: orderprocessing.v1.TenderPayment
+	(*CurrencyConversion)(nil),    // 18: orderprocessing.v1.CurrencyConversion
+	nil,                           // 19: orderprocessing.v1.Order.VendorsEntry
+	(*timestamppb.Timestamp)(nil), // 20: google.protobuf.Timestamp
+}
+var file_proto_orderspb_orders_proto_depIdxs = []int32{
+	20, // 0: orderprocessing.v1.Order.created_at:type_name -> google.protobuf.Timestamp
+	16, // 1: orderprocessing.v1.Order.tenders:type_name -> orderprocessing.v1.Tender
+	19, // 2: orderprocessing.v1.Order.vendors:type_name -> orderprocessing.v1.Order.VendorsEntry
+	15, // 3: orderprocessing.v1.Order.customer:type_name -> orderprocessing.v1.Customer
+	1,  // 4: orderprocessing.v1.Order.lines:type_name -> orderprocessing.v1.OrderLine
+	8,  // 5: orderprocessing.v1.Order.payment_method:type_name -> orderprocessing.v1.PaymentMethod
+	13, // 6: orderprocessing.v1.Order.shipping:type_name -> orderprocessing.v1.ShippingInfo
+	7,  // 7: orderprocessing.v1.Order.prior_payment:type_name -> orderprocessing.v1.PaymentResponse
+	20, // 8: orderprocessing.v1.VendorFulfillment.last_updated:type_name -> google.protobuf.Timestamp
+	2,  // 9: orderprocessing.v1.OrderStatus.item_results:type_name -> orderprocessing.v1.ItemFulfillment
+	20, // 10: orderprocessing.v1.OrderStatus.last_updated:type_name -> google.protobuf.Timestamp
+	3,  // 11: orderprocessing.v1.OrderStatus.shipments:type_name -> orderprocessing.v1.OrderShipment
+	4,  // 12: orderprocessing.v1.OrderStatus.vendors:type_name -> orderprocessing.v1.VendorFulfillment
+	8,  // 13: orderprocessing.v1.PaymentRequest.payment_method:type_name -> orderprocessing.v1.PaymentMethod
+	17, // 14: orderprocessing.v1.PaymentResponse.tenders:type_name -> orderprocessing.v1.TenderPayment
+	18, // 15: orderprocessing.v1.PaymentResponse.conversion:type_name -> orderprocessing.v1.CurrencyConversion
+	9,  // 16: orderprocessing.v1.PaymentMethod.card:type_name -> orderprocessing.v1.CardMethod
+	10, // 17: orderprocessing.v1.PaymentMethod.wallet:type_name -> orderprocessing.v1.WalletMethod
+	11, // 18: orderprocessing.v1.PaymentMethod.bank_transfer:type_name -> orderprocessing.v1.BankTransferMethod
+	12, // 19: orderprocessing.v1.PaymentMethod.gift_card:type_name -> orderprocessing.v1.GiftCardMethod
+	14, // 20: orderprocessing.v1.ShippingInfo.address:type_name -> orderprocessing.v1.Address
+	20, // 21: orderprocessing.v1.ShippingInfo.promised_by:type_name -> google.protobuf.Timestamp
+	20, // 22: orderprocessing.v1.CurrencyConversion.rate_as_of:type_name -> google.protobuf.Timestamp
+	23, // [23:23] is the sub-list for method output_type
+	23, // [23:23] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
+}
+
+func init() { file_proto_orderspb_orders_proto_init() }
+func file_proto_orderspb_orders_proto_init() {
+	if File_proto_orderspb_orders_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_orderspb_orders_proto_rawDesc), len(file_proto_orderspb_orders_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   20,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_proto_orderspb_orders_proto_goTypes,
+		DependencyIndexes: file_proto_orderspb_orders_proto_depIdxs,
+		MessageInfos:      file_proto_orderspb_orders_proto_msgTypes,
+	}.Build()
+	File_proto_orderspb_orders_proto = out.File
+	file_proto_orderspb_orders_proto_goTypes = nil
+	file_proto_orderspb_orders_proto_depIdxs = nil
+}
@@ -47,7 +47,7 @@ echo "WireMock: http://localhost:8081"
 echo ""
 echo "To run the demo:"
 echo "1. Start worker: ${YELLOW}go run worker/main.go${NC}"
-echo "2. Start workflow: ${YELLOW}go run starter/main.go${NC}"
+echo "2. Start workflow: ${YELLOW}go run ./starter${NC}"
 echo ""
 echo "Or use the Makefile:"
 echo "  ${YELLOW}make worker${NC}  - Start the worker"
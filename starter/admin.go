@@ -0,0 +1,311 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	commonpb "go.temporal.io/api/common/v1"
+	enumspb "go.temporal.io/api/enums/v1"
+	historypb "go.temporal.io/api/history/v1"
+	"go.temporal.io/api/temporalproto"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/client"
+)
+
+// pendingActivity is an activity an order is waiting on, as shown by -action=describe
+type pendingActivity struct {
+	ActivityID      string     `json:"activity_id"`
+	Type            string     `json:"type"`
+	State           string     `json:"state"`
+	Attempt         int32      `json:"attempt"`
+	MaximumAttempts int32      `json:"maximum_attempts"`
+	LastStarted     *time.Time `json:"last_started,omitempty"`
+	NextRetry       *time.Time `json:"next_retry,omitempty"`
+	LastWorker      string     `json:"last_worker,omitempty"`
+	LastFailure     string     `json:"last_failure,omitempty"`
+}
+
+// workflowDescription summarizes DescribeWorkflowExecution for -action=describe
+type workflowDescription struct {
+	WorkflowID    string     `json:"workflow_id"`
+	RunID         string     `json:"run_id"`
+	Type          string     `json:"type"`
+	Status        string     `json:"status"`
+	TaskQueue     string     `json:"task_queue"`
+	StartTime     time.Time  `json:"start_time"`
+	CloseTime     *time.Time `json:"close_time,omitempty"`
+	HistoryLength int64      `json:"history_length"`
+	// WorkflowTaskAttempt above 1 means workflow tasks are failing, usually
+	// from a panic or nondeterminism after a deployment
+	WorkflowTaskAttempt int32             `json:"workflow_task_attempt,omitempty"`
+	PendingActivities   []pendingActivity `json:"pending_activities"`
+	PendingChildren     []string          `json:"pending_children,omitempty"`
+}
+
+// describeWorkflow prints the status and pending work of the latest run of workflowID
+func describeWorkflow(ctx context.Context, c client.Client, workflowID string) {
+	if workflowID == "" {
+		fatal("workflow-id is required for describe operations")
+	}
+
+	resp, err := c.DescribeWorkflowExecution(ctx, workflowID, "")
+	if err != nil {
+		fatal("Unable to describe workflow", "error", err)
+	}
+	info := resp.GetWorkflowExecutionInfo()
+	desc := workflowDescription{
+		WorkflowID:          info.GetExecution().GetWorkflowId(),
+		RunID:               info.GetExecution().GetRunId(),
+		Type:                info.GetType().GetName(),
+		Status:              info.GetStatus().String(),
+		TaskQueue:           info.GetTaskQueue(),
+		StartTime:           info.GetStartTime().AsTime(),
+		CloseTime:           timeOrNil(info.GetCloseTime()),
+		HistoryLength:       info.GetHistoryLength(),
+		WorkflowTaskAttempt: resp.GetPendingWorkflowTask().GetAttempt(),
+		PendingActivities:   make([]pendingActivity, 0, len(resp.GetPendingActivities())),
+	}
+	for _, activity := range resp.GetPendingActivities() {
+		desc.PendingActivities = append(desc.PendingActivities, pendingActivity{
+			ActivityID:      activity.GetActivityId(),
+			Type:            activity.GetActivityType().GetName(),
+			State:           activity.GetState().String(),
+			Attempt:         activity.GetAttempt(),
+			MaximumAttempts: activity.GetMaximumAttempts(),
+			LastStarted:     timeOrNil(activity.GetLastStartedTime()),
+			NextRetry:       timeOrNil(activity.GetNextAttemptScheduleTime()),
+			LastWorker:      activity.GetLastWorkerIdentity(),
+			LastFailure:     activity.GetLastFailure().GetMessage(),
+		})
+	}
+	for _, child := range resp.GetPendingChildren() {
+		desc.PendingChildren = append(desc.PendingChildren, child.GetWorkflowId())
+	}
+
+	out.print(desc, desc.table)
+}
+
+func (d workflowDescription) table(w io.Writer) {
+	fmt.Fprintf(w, "Workflow ID:\t%s\n", d.WorkflowID)
+	fmt.Fprintf(w, "Run ID:\t%s\n", d.RunID)
+	fmt.Fprintf(w, "Type:\t%s\n", d.Type)
+	fmt.Fprintf(w, "Status:\t%s\n", d.Status)
+	fmt.Fprintf(w, "Task queue:\t%s\n", d.TaskQueue)
+	fmt.Fprintf(w, "Started:\t%s\n", formatTime(&d.StartTime))
+	fmt.Fprintf(w, "Closed:\t%s\n", formatTime(d.CloseTime))
+	fmt.Fprintf(w, "History events:\t%d\n", d.HistoryLength)
+	if d.WorkflowTaskAttempt > 1 {
+		fmt.Fprintf(w, "Workflow task attempt:\t%d (failing; check the worker logs)\n", d.WorkflowTaskAttempt)
+	}
+	if len(d.PendingChildren) > 0 {
+		fmt.Fprintf(w, "Pending children:\t%s\n", strings.Join(d.PendingChildren, ", "))
+	}
+
+	fmt.Fprintf(w, "\nPending activities: %d\n", len(d.PendingActivities))
+	if len(d.PendingActivities) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "ID\tTYPE\tSTATE\tATTEMPT\tLAST STARTED\tNEXT RETRY\tLAST FAILURE")
+	for _, activity := range d.PendingActivities {
+		attempts := fmt.Sprintf("%d", activity.Attempt)
+		if activity.MaximumAttempts > 0 {
+			attempts = fmt.Sprintf("%d/%d", activity.Attempt, activity.MaximumAttempts)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", activity.ActivityID, activity.Type, activity.State,
+			attempts, formatTime(activity.LastStarted), formatTime(activity.NextRetry), orDash(activity.LastFailure))
+	}
+}
+
+// stackTrace prints the stack of each workflow coroutine, from the built-in
+// __stack_trace query
+func stackTrace(ctx context.Context, c client.Client, workflowID string) {
+	if workflowID == "" {
+		fatal("workflow-id is required for stack operations")
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	response, err := c.QueryWorkflow(queryCtx, workflowID, "", client.QueryTypeStackTrace)
+	if err != nil {
+		fatal("Unable to query workflow stack trace", "error", err)
+	}
+	result := stackResult{WorkflowID: workflowID}
+	if err := response.Get(&result.Stack); err != nil {
+		fatal("Unable to decode stack trace", "error", err)
+	}
+	out.print(result, func(w io.Writer) { fmt.Fprintln(w, result.Stack) })
+}
+
+// stackResult is the result of -action=stack
+type stackResult struct {
+	WorkflowID string `json:"workflow_id"`
+	Stack      string `json:"stack"`
+}
+
+// cancelWorkflow requests cancellation of the latest run of workflowID. The
+// workflow handles it by interrupting the running step and compensating.
+func cancelWorkflow(ctx context.Context, c client.Client, workflowID string) {
+	if workflowID == "" {
+		fatal("workflow-id is required for hard-cancel operations")
+	}
+
+	if err := c.CancelWorkflow(ctx, workflowID, ""); err != nil {
+		fatal("Unable to cancel workflow", "error", err)
+	}
+	result := cancelResult{WorkflowID: workflowID}
+	out.print(result, func(w io.Writer) {
+		fmt.Fprintf(w, "Requested cancellation of %s\n", workflowID)
+	})
+}
+
+// cancelResult is the result of -action=hard-cancel
+type cancelResult struct {
+	WorkflowID string `json:"workflow_id"`
+}
+
+// terminateWorkflow stops the latest run of workflowID immediately, without
+// running cancellation or compensation logic
+func terminateWorkflow(ctx context.Context, c client.Client, workflowID, reason string) {
+	if workflowID == "" {
+		fatal("workflow-id is required for terminate operations")
+	}
+	if reason == "" {
+		fatal("reason is required for terminate operations")
+	}
+
+	if err := c.TerminateWorkflow(ctx, workflowID, "", reason); err != nil {
+		fatal("Unable to terminate workflow", "error", err)
+	}
+	result := terminateResult{WorkflowID: workflowID, Reason: reason}
+	out.print(result, func(w io.Writer) {
+		fmt.Fprintf(w, "Terminated %s: %s\n", workflowID, reason)
+	})
+}
+
+// terminateResult is the result of -action=terminate
+type terminateResult struct {
+	WorkflowID string `json:"workflow_id"`
+	Reason     string `json:"reason"`
+}
+
+// resetWorkflow starts a new run of workflowID from the workflow task
+// completed at eventID, or from the last completed workflow task when eventID
+// is zero. The old run is terminated, and signals received after the reset
+// point are reapplied to the new run.
+func resetWorkflow(ctx context.Context, c client.Client, namespace, workflowID string, eventID int64, reason string) {
+	if workflowID == "" {
+		fatal("workflow-id is required for reset operations")
+	}
+	if reason == "" {
+		fatal("reason is required for reset operations")
+	}
+	if namespace == "" {
+		namespace = client.DefaultNamespace
+	}
+
+	// Pin the run so the history and the reset refer to the same one
+	desc, err := c.DescribeWorkflowExecution(ctx, workflowID, "")
+	if err != nil {
+		fatal("Unable to describe workflow", "error", err)
+	}
+	runID := desc.GetWorkflowExecutionInfo().GetExecution().GetRunId()
+	if eventID == 0 {
+		eventID, err = lastWorkflowTaskCompleted(ctx, c, workflowID, runID)
+		if err != nil {
+			fatal("Unable to find a workflow task to reset to", "error", err)
+		}
+	}
+
+	resp, err := c.ResetWorkflowExecution(ctx, &workflowservice.ResetWorkflowExecutionRequest{
+		Namespace:                 namespace,
+		WorkflowExecution:         &commonpb.WorkflowExecution{WorkflowId: workflowID, RunId: runID},
+		Reason:                    reason,
+		WorkflowTaskFinishEventId: eventID,
+		RequestId:                 uuid.NewString(),
+	})
+	if err != nil {
+		fatal("Unable to reset workflow", "error", err)
+	}
+	result := resetResult{WorkflowID: workflowID, EventID: eventID, OldRunID: runID, NewRunID: resp.GetRunId()}
+	out.print(result, func(w io.Writer) {
+		fmt.Fprintf(w, "Workflow ID:\t%s\n", result.WorkflowID)
+		fmt.Fprintf(w, "Reset to event:\t%d\n", result.EventID)
+		fmt.Fprintf(w, "Old run ID:\t%s\n", result.OldRunID)
+		fmt.Fprintf(w, "New run ID:\t%s\n", result.NewRunID)
+	})
+}
+
+// resetResult is the result of -action=reset
+type resetResult struct {
+	WorkflowID string `json:"workflow_id"`
+	EventID    int64  `json:"event_id"`
+	OldRunID   string `json:"old_run_id"`
+	NewRunID   string `json:"new_run_id"`
+}
+
+// lastWorkflowTaskCompleted returns the ID of the last WorkflowTaskCompleted
+// event in the history of the run
+func lastWorkflowTaskCompleted(ctx context.Context, c client.Client, workflowID, runID string) (int64, error) {
+	var last int64
+	events := c.GetWorkflowHistory(ctx, workflowID, runID, false, enumspb.HISTORY_EVENT_FILTER_TYPE_ALL_EVENT)
+	for events.HasNext() {
+		event, err := events.Next()
+		if err != nil {
+			return 0, err
+		}
+		if event.GetEventType() == enumspb.EVENT_TYPE_WORKFLOW_TASK_COMPLETED {
+			last = event.GetEventId()
+		}
+	}
+	if last == 0 {
+		return 0, errors.New("no workflow task has completed")
+	}
+	return last, nil
+}
+
+// exportHistory writes the history of the latest run of workflowID as JSON,
+// the format the Temporal CLI and Web UI download, to path or to stdout
+func exportHistory(ctx context.Context, c client.Client, workflowID, path string) {
+	if workflowID == "" {
+		fatal("workflow-id is required for export-history operations")
+	}
+
+	history := &historypb.History{}
+	events := c.GetWorkflowHistory(ctx, workflowID, "", false, enumspb.HISTORY_EVENT_FILTER_TYPE_ALL_EVENT)
+	for events.HasNext() {
+		event, err := events.Next()
+		if err != nil {
+			fatal("Unable to fetch workflow history", "error", err)
+		}
+		history.Events = append(history.Events, event)
+	}
+	data, err := temporalproto.CustomJSONMarshalOptions{Indent: "  "}.Marshal(history)
+	if err != nil {
+		fatal("Unable to encode workflow history", "error", err)
+	}
+
+	if path == "" {
+		os.Stdout.Write(append(data, '\n'))
+		return
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0600); err != nil {
+		fatal("Unable to write workflow history", "error", err)
+	}
+	result := exportResult{WorkflowID: workflowID, File: path, Events: len(history.Events)}
+	out.print(result, func(w io.Writer) {
+		fmt.Fprintf(w, "Wrote %d events of %s to %s\n", result.Events, result.WorkflowID, result.File)
+	})
+}
+
+// exportResult is the result of -action=export-history with -o
+type exportResult struct {
+	WorkflowID string `json:"workflow_id"`
+	File       string `json:"file"`
+	Events     int    `json:"events"`
+}
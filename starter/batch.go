@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/client"
+	"golang.org/x/time/rate"
+)
+
+// batchFailure is an order of a batch that could not be started
+type batchFailure struct {
+	OrderID string `json:"order_id"`
+	Error   string `json:"error"`
+}
+
+// batchSummary reports the outcome of -action=start-batch
+type batchSummary struct {
+	Total    int            `json:"total"`
+	Started  int            `json:"started"`
+	Failed   int            `json:"failed"`
+	Failures []batchFailure `json:"failures,omitempty"`
+	Duration string         `json:"duration"`
+}
+
+func (s batchSummary) table(w io.Writer) {
+	fmt.Fprintf(w, "Started %d of %d orders in %s (%d failed)\n", s.Started, s.Total, s.Duration, s.Failed)
+	for _, failure := range s.Failures {
+		fmt.Fprintf(w, "  %s:\t%s\n", failure.OrderID, failure.Error)
+	}
+}
+
+// startBatch starts a workflow for every order in path, at most concurrency
+// at a time and perSecondRate per second, then prints a summary. It exits
+// with exitFailed if any order could not be started.
+func startBatch(ctx context.Context, c client.Client, path string, concurrency int, perSecondRate float64, opts startOptions) {
+	if path == "" {
+		fatal("file is required for start-batch operations")
+	}
+	if concurrency < 1 {
+		fatal("concurrency must be at least 1", "concurrency", concurrency)
+	}
+	orders, err := readOrders(path, time.Now())
+	if err != nil {
+		fatal("Unable to read orders", "file", path, "error", err)
+	}
+
+	limit := rate.Inf
+	if perSecondRate > 0 {
+		limit = rate.Limit(perSecondRate)
+	}
+	limiter := rate.NewLimiter(limit, 1)
+
+	slog.Info("Starting batch", "file", path, "orders", len(orders), "concurrency", concurrency, "rate", perSecondRate)
+	began := time.Now()
+	summary := batchSummary{Total: len(orders)}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for _, order := range orders {
+		if err := limiter.Wait(ctx); err != nil {
+			fatal("Batch interrupted", "error", err)
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(order models.Order) {
+			defer func() { <-sem; wg.Done() }()
+			run, err := executeOrder(ctx, c, order, opts)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				summary.Failed++
+				summary.Failures = append(summary.Failures, batchFailure{OrderID: order.ID, Error: err.Error()})
+				slog.Warn("Unable to start order", "order_id", order.ID, "error", err)
+				return
+			}
+			summary.Started++
+			slog.Debug("Started order", "order_id", order.ID, "workflow_id", run.GetID(), "run_id", run.GetRunID())
+		}(order)
+	}
+	wg.Wait()
+	summary.Duration = time.Since(began).Round(time.Millisecond).String()
+
+	out.print(summary, summary.table)
+	if summary.Failed > 0 {
+		os.Exit(exitFailed)
+	}
+}
+
+// readOrders parses the orders in a .json file, an array of orders of any
+// schema version, upgraded to the current one, or a .csv file with a header
+// naming its columns: id, amount, items (separated by semicolons), and
+// optionally fulfillment_parallelism. Orders without an ID get one generated
+// from now, and every order must pass Validate.
+func readOrders(path string, now time.Time) ([]models.Order, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var orders []models.Order
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		var raw []json.RawMessage
+		if err := json.NewDecoder(f).Decode(&raw); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		for i, message := range raw {
+			order, err := models.Migrate(message)
+			if err != nil {
+				return nil, fmt.Errorf("order %d: %w", i+1, err)
+			}
+			orders = append(orders, order)
+		}
+	case ".csv":
+		orders, err = readOrdersCSV(csv.NewReader(f))
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported file type %q; use .csv or .json", filepath.Ext(path))
+	}
+
+	for i := range orders {
+		if orders[i].ID == "" {
+			orders[i].ID = fmt.Sprintf("ORD-%d-%d", now.Unix(), i+1)
+		}
+		if orders[i].Status == "" {
+			orders[i].Status = models.StatusPending
+		}
+		if orders[i].CreatedAt.IsZero() {
+			orders[i].CreatedAt = now
+		}
+		if err := orders[i].Validate(); err != nil {
+			return nil, fmt.Errorf("order %d: %w", i+1, err)
+		}
+	}
+	return orders, nil
+}
+
+// readOrdersCSV parses CSV orders whose first record names the columns
+func readOrdersCSV(r *csv.Reader) ([]models.Order, error) {
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case "id", "amount", "items", "fulfillment_parallelism":
+			columns[name] = i
+		default:
+			return nil, fmt.Errorf("unknown CSV column %q", name)
+		}
+	}
+	if _, ok := columns["amount"]; !ok {
+		return nil, errors.New("CSV has no amount column")
+	}
+
+	var orders []models.Order
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return orders, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := r.FieldPos(0)
+		field := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		order := models.Order{ID: field("id")}
+		if order.Amount, err = strconv.ParseFloat(field("amount"), 64); err != nil {
+			return nil, fmt.Errorf("line %d: invalid amount: %w", line, err)
+		}
+		for _, item := range strings.Split(field("items"), ";") {
+			if item = strings.TrimSpace(item); item != "" {
+				order.Items = append(order.Items, item)
+			}
+		}
+		if parallelism := field("fulfillment_parallelism"); parallelism != "" {
+			if order.FulfillmentParallelism, err = strconv.Atoi(parallelism); err != nil {
+				return nil, fmt.Errorf("line %d: invalid fulfillment_parallelism: %w", line, err)
+			}
+		}
+		orders = append(orders, order)
+	}
+}
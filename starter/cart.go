@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/orderitems"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"go.temporal.io/sdk/client"
+)
+
+// newCart returns the cart of -action=cart, with an ID generated if cartID
+// is empty. reminders is a comma-separated list of durations; empty keeps
+// the workflow's default schedule.
+func newCart(cartID string, amount float64, items, customerID, reminders string, expireAfter time.Duration) (models.Cart, error) {
+	if cartID == "" {
+		cartID = fmt.Sprintf("CART-%d", time.Now().Unix())
+	}
+	cart := models.Cart{
+		ID:          cartID,
+		CustomerID:  customerID,
+		Items:       orderitems.Parse(items),
+		Amount:      amount,
+		CreatedAt:   time.Now(),
+		ExpireAfter: expireAfter,
+	}
+	if expireAfter < 0 {
+		return cart, errors.New("cart-expiry must not be negative")
+	}
+	for _, wait := range strings.Split(reminders, ",") {
+		if wait = strings.TrimSpace(wait); wait == "" {
+			continue
+		}
+		d, err := time.ParseDuration(wait)
+		if err != nil || d <= 0 {
+			return cart, fmt.Errorf("reminders: %q is not a positive duration", wait)
+		}
+		cart.Reminders = append(cart.Reminders, d)
+	}
+	return cart, nil
+}
+
+// cartResult is the result of -action=cart
+type cartResult struct {
+	WorkflowID  string             `json:"workflow_id"`
+	RunID       string             `json:"run_id"`
+	CartID      string             `json:"cart_id"`
+	FinalStatus *models.CartStatus `json:"final_status,omitempty"`
+}
+
+func (r cartResult) table(w io.Writer) {
+	fmt.Fprintf(w, "Workflow ID:\t%s\n", r.WorkflowID)
+	fmt.Fprintf(w, "Run ID:\t%s\n", r.RunID)
+	fmt.Fprintf(w, "Cart ID:\t%s\n", r.CartID)
+	if r.FinalStatus != nil {
+		fmt.Fprintf(w, "Status:\t%s\n", r.FinalStatus.Status)
+		fmt.Fprintf(w, "Reminders sent:\t%d\n", r.FinalStatus.RemindersSent)
+	}
+}
+
+// simulateCart starts the reminder workflow of cart and, with checkoutAfter
+// set, checks the cart out that long after. It then waits up to timeout for
+// the cart to check out or expire if a checkout was sent or wait is set.
+func simulateCart(ctx context.Context, c client.Client, cart models.Cart, checkoutAfter time.Duration, wait bool, timeout time.Duration) {
+	run, err := c.ExecuteWorkflow(ctx, client.StartWorkflowOptions{
+		ID:        models.CartWorkflowID(cart.ID),
+		TaskQueue: taskQueue,
+	}, workflows.CartReminderWorkflow, cart)
+	if err != nil {
+		fatal("Unable to start cart workflow", "error", err)
+	}
+	slog.Info("Started cart reminder workflow", "workflow_id", run.GetID(), "run_id", run.GetRunID(), "cart_id", cart.ID, "reminders", cart.Reminders)
+	result := cartResult{WorkflowID: run.GetID(), RunID: run.GetRunID(), CartID: cart.ID}
+
+	if checkoutAfter > 0 {
+		slog.Info("Checking the cart out after a delay", "cart_id", cart.ID, "checkout_after", checkoutAfter)
+		select {
+		case <-time.After(checkoutAfter):
+		case <-ctx.Done():
+			fatal("Interrupted before checkout", "error", ctx.Err())
+		}
+		err := c.SignalWorkflow(ctx, run.GetID(), run.GetRunID(), models.SignalCheckoutCompleted, models.CheckoutCompleted{})
+		if err != nil {
+			fatal("Unable to check the cart out", "error", err)
+		}
+	}
+
+	if wait || checkoutAfter > 0 {
+		waitCtx := ctx
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			waitCtx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		if err := run.Get(waitCtx, &result.FinalStatus); err != nil {
+			fatal("Cart workflow did not finish", "workflow_id", run.GetID(), "error", err)
+		}
+	} else {
+		slog.Info("To check the cart out, run",
+			"command", fmt.Sprintf("go run ./starter -action=checkout -workflow-id=%s", run.GetID()))
+	}
+	out.print(result, result.table)
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"os/signal"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/orderwait"
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/client"
+)
+
+// Commands of -action=interactive
+var interactiveCommands = []string{"start", "signal", "query", "watch", "help", "exit"}
+
+// Signals the interactive signal command sends
+var orderSignals = []string{models.SignalCancel, models.SignalExpedite, models.SignalApprove, models.SignalRestock, models.SignalRetry}
+
+const interactiveHelp = `Commands:
+  start [-order-id ID] [-amount N] [-items a,b]   start an order
+  signal cancel|expedite|approve|restock|retry WORKFLOW-ID
+  query WORKFLOW-ID                               print the order status
+  watch WORKFLOW-ID                               follow the order until it finishes (Ctrl-C stops)
+  help
+  exit
+Tab completes commands, signals, and the IDs of running and started orders.`
+
+// interactive reads commands at a prompt until exit or end of input, reusing
+// c for each. Errors are printed rather than ending the session.
+func interactive(ctx context.Context, c client.Client) {
+	known := &workflowIDs{}
+	resp, err := c.ListWorkflow(ctx, &workflowservice.ListWorkflowExecutionsRequest{
+		PageSize: 100,
+		Query:    "WorkflowType = 'OrderWorkflow' AND ExecutionStatus = 'Running'",
+	})
+	if err != nil {
+		slog.Warn("Unable to list running orders for completion", "error", err)
+	}
+	for _, info := range resp.GetExecutions() {
+		known.add(info.GetExecution().GetWorkflowId())
+	}
+
+	lines := newLineReader(known.complete)
+	fmt.Println(`Type "help" for commands.`)
+	for {
+		line, err := lines.readLine("order> ")
+		if err != nil {
+			return
+		}
+		args := strings.Fields(line)
+		if len(args) == 0 {
+			continue
+		}
+		if args[0] == "exit" || args[0] == "quit" {
+			return
+		}
+		if err := runCommand(ctx, c, known, args); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+		}
+	}
+}
+
+// runCommand runs one interactive command
+func runCommand(ctx context.Context, c client.Client, known *workflowIDs, args []string) error {
+	switch args[0] {
+	case "help":
+		fmt.Println(interactiveHelp)
+	case "start":
+		flags := flag.NewFlagSet("start", flag.ContinueOnError)
+		orderID := flags.String("order-id", "", "Order ID (generated if not provided)")
+		amount := flags.Float64("amount", 100.0, "Order amount")
+		items := flags.String("items", "item1,item2", "Comma-separated list of items")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		order := newOrder(*orderID, *amount, *items)
+		if err := order.Validate(); err != nil {
+			return err
+		}
+		run, err := executeOrder(ctx, c, order, startOptions{ConflictPolicy: enumspb.WORKFLOW_ID_CONFLICT_POLICY_FAIL})
+		if err != nil {
+			return err
+		}
+		known.add(run.GetID())
+		result := startResult{WorkflowID: run.GetID(), RunID: run.GetRunID(), OrderID: order.ID}
+		out.print(result, result.table)
+	case "signal":
+		if len(args) != 3 || !slices.Contains(orderSignals, args[1]) {
+			return errors.New("usage: signal cancel|expedite|approve|restock|retry WORKFLOW-ID")
+		}
+		if err := c.SignalWorkflow(ctx, args[2], "", args[1], nil); err != nil {
+			return err
+		}
+		result := signalResult{WorkflowID: args[2], Signal: args[1]}
+		out.print(result, func(w io.Writer) {
+			fmt.Fprintf(w, "Sent %s to %s\n", result.Signal, result.WorkflowID)
+		})
+	case "query":
+		if len(args) != 2 {
+			return errors.New("usage: query WORKFLOW-ID")
+		}
+		status, err := orderwait.Status(ctx, c, args[1], "")
+		if err != nil {
+			return err
+		}
+		out.print(status, func(w io.Writer) { statusTable(w, status) })
+	case "watch":
+		if len(args) != 2 {
+			return errors.New("usage: watch WORKFLOW-ID")
+		}
+		// Ctrl-C ends the watch, not the session
+		watchCtx, stop := signal.NotifyContext(ctx, os.Interrupt)
+		defer stop()
+		_, err := watchOrder(watchCtx, c, args[1], time.Second)
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+		return err
+	default:
+		return fmt.Errorf("unknown command %q; try help", args[0])
+	}
+	return nil
+}
+
+// workflowIDs are the workflow IDs the interactive prompt completes
+type workflowIDs struct {
+	ids []string
+}
+
+func (k *workflowIDs) add(id string) {
+	if !slices.Contains(k.ids, id) {
+		k.ids = append(k.ids, id)
+	}
+}
+
+// complete returns line with its last word completed as far as the
+// candidates agree, and the candidates when there is more than one
+func (k *workflowIDs) complete(line string) (string, []string) {
+	words := strings.Fields(line)
+	if len(words) == 0 || unicode.IsSpace(rune(line[len(line)-1])) {
+		words = append(words, "")
+	}
+	prefix := words[len(words)-1]
+
+	var options []string
+	switch {
+	case len(words) == 1:
+		options = interactiveCommands
+	case words[0] == "signal" && len(words) == 2:
+		options = orderSignals
+	case words[0] == "signal" && len(words) == 3, (words[0] == "query" || words[0] == "watch") && len(words) == 2:
+		options = k.ids
+	}
+	var matches []string
+	for _, option := range options {
+		if strings.HasPrefix(option, prefix) {
+			matches = append(matches, option)
+		}
+	}
+	if len(matches) == 0 {
+		return line, nil
+	}
+	if len(matches) == 1 {
+		return line + strings.TrimPrefix(matches[0], prefix) + " ", nil
+	}
+
+	common := matches[0]
+	for _, match := range matches[1:] {
+		for !strings.HasPrefix(match, common) {
+			common = common[:len(common)-1]
+		}
+	}
+	slices.Sort(matches)
+	return line + strings.TrimPrefix(common, prefix), matches
+}
+
+// lineReader reads prompted lines from stdin. On a terminal it switches to
+// raw mode with stty while reading, so Tab can complete; elsewhere, or
+// without stty, it reads plain lines.
+type lineReader struct {
+	in       *bufio.Reader
+	complete func(line string) (string, []string)
+	tty      bool
+}
+
+func newLineReader(complete func(line string) (string, []string)) *lineReader {
+	info, err := os.Stdin.Stat()
+	return &lineReader{
+		in:       bufio.NewReader(os.Stdin),
+		complete: complete,
+		tty:      err == nil && info.Mode()&os.ModeCharDevice != 0,
+	}
+}
+
+// readLine prints prompt and returns the next line, or io.EOF at end of
+// input or Ctrl-D
+func (r *lineReader) readLine(prompt string) (string, error) {
+	fmt.Print(prompt)
+	if !r.tty {
+		return r.readPlainLine()
+	}
+	saved, err := stty("-g")
+	if err != nil {
+		return r.readPlainLine()
+	}
+	if _, err := stty("-icanon", "-echo", "-isig", "min", "1"); err != nil {
+		return "", err
+	}
+	defer stty(strings.TrimSpace(saved))
+
+	var line []rune
+	for {
+		ch, _, err := r.in.ReadRune()
+		if err != nil {
+			return "", err
+		}
+		switch ch {
+		case '\r', '\n':
+			fmt.Println()
+			return string(line), nil
+		case 3: // Ctrl-C discards the line
+			fmt.Print("^C\n" + prompt)
+			line = line[:0]
+		case 4: // Ctrl-D on an empty line ends input
+			if len(line) == 0 {
+				fmt.Println()
+				return "", io.EOF
+			}
+		case 127, '\b':
+			if len(line) > 0 {
+				line = line[:len(line)-1]
+				fmt.Print("\b \b")
+			}
+		case '\t':
+			completed, candidates := r.complete(string(line))
+			if len(candidates) > 0 {
+				fmt.Print("\n" + strings.Join(candidates, "  ") + "\n" + prompt + completed)
+			} else {
+				fmt.Print(strings.TrimPrefix(completed, string(line)))
+			}
+			line = []rune(completed)
+		case 27: // escape sequences such as arrow keys are ignored
+			if next, _, _ := r.in.ReadRune(); next == '[' {
+				for {
+					final, _, err := r.in.ReadRune()
+					if err != nil || (final >= 0x40 && final <= 0x7e) {
+						break
+					}
+				}
+			}
+		default:
+			if unicode.IsPrint(ch) {
+				line = append(line, ch)
+				fmt.Print(string(ch))
+			}
+		}
+	}
+}
+
+// readPlainLine reads a line the terminal has already echoed and edited
+func (r *lineReader) readPlainLine() (string, error) {
+	line, err := r.in.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// stty runs stty on the terminal attached to stdin
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	output, err := cmd.Output()
+	return string(output), err
+}
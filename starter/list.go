@@ -0,0 +1,286 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
+	workflowpb "go.temporal.io/api/workflow/v1"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+)
+
+// listFilter narrows the orders shown by -action=list
+type listFilter struct {
+	Status      string
+	OrderStatus string
+	Expedited   bool
+	Since       string
+	Until       string
+	Query       string
+}
+
+// query returns the visibility query selecting order workflows that match f
+func (f listFilter) query(now time.Time) (string, error) {
+	clauses := []string{"WorkflowType = 'OrderWorkflow'"}
+	if f.Status != "" {
+		status, err := executionStatus(f.Status)
+		if err != nil {
+			return "", err
+		}
+		clauses = append(clauses, fmt.Sprintf("ExecutionStatus = '%s'", status))
+	}
+	if f.OrderStatus != "" {
+		clauses = append(clauses, fmt.Sprintf("%s = '%s'", models.SearchAttributeOrderStatus, quoteValue(f.OrderStatus)))
+	}
+	if f.Expedited {
+		clauses = append(clauses, models.SearchAttributeOrderExpedited+" = true")
+	}
+	if f.Since != "" {
+		since, err := parseTime(f.Since, now)
+		if err != nil {
+			return "", fmt.Errorf("invalid -since: %w", err)
+		}
+		clauses = append(clauses, fmt.Sprintf("StartTime >= '%s'", since.Format(time.RFC3339)))
+	}
+	if f.Until != "" {
+		until, err := parseTime(f.Until, now)
+		if err != nil {
+			return "", fmt.Errorf("invalid -until: %w", err)
+		}
+		clauses = append(clauses, fmt.Sprintf("StartTime < '%s'", until.Format(time.RFC3339)))
+	}
+	if f.Query != "" {
+		clauses = append(clauses, "("+f.Query+")")
+	}
+	return strings.Join(clauses, " AND "), nil
+}
+
+// executionStatus returns the visibility name of a workflow execution status,
+// matched case-insensitively
+func executionStatus(name string) (string, error) {
+	for status := range enumspb.WorkflowExecutionStatus_shorthandValue {
+		if status != "Unspecified" && strings.EqualFold(status, name) {
+			return status, nil
+		}
+	}
+	return "", fmt.Errorf("unknown workflow status %q", name)
+}
+
+// parseTime parses an RFC 3339 time, or a duration meaning that long before now
+func parseTime(value string, now time.Time) (time.Time, error) {
+	if ago, err := time.ParseDuration(value); err == nil {
+		return now.Add(-ago), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// quoteValue escapes single quotes in a visibility query string value
+func quoteValue(value string) string {
+	return strings.ReplaceAll(value, "'", "\\'")
+}
+
+// orderSummary is one row of -action=list
+type orderSummary struct {
+	WorkflowID  string     `json:"workflow_id"`
+	RunID       string     `json:"run_id"`
+	Status      string     `json:"status"`
+	OrderStatus string     `json:"order_status,omitempty"`
+	Expedited   bool       `json:"expedited"`
+	StartTime   time.Time  `json:"start_time"`
+	CloseTime   *time.Time `json:"close_time,omitempty"`
+}
+
+// orderPage is a page of -action=list results
+type orderPage struct {
+	Query         string         `json:"query"`
+	Orders        []orderSummary `json:"orders"`
+	NextPageToken string         `json:"next_page_token,omitempty"`
+}
+
+func (p orderPage) table(w io.Writer) {
+	fmt.Fprintln(w, "WORKFLOW ID\tSTATUS\tORDER STATUS\tEXPEDITED\tSTARTED\tCLOSED")
+	for _, order := range p.Orders {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\t%s\n", order.WorkflowID, order.Status, orDash(order.OrderStatus),
+			order.Expedited, formatTime(&order.StartTime), formatTime(order.CloseTime))
+	}
+}
+
+// listWorkflows prints one page of order workflows matching filter
+func listWorkflows(ctx context.Context, c client.Client, filter listFilter, pageSize int, pageToken string) {
+	query, err := filter.query(time.Now())
+	if err != nil {
+		fatal("Invalid list filter", "error", err)
+	}
+	token, err := base64.URLEncoding.DecodeString(pageToken)
+	if err != nil {
+		fatal("Invalid next page token", "error", err)
+	}
+
+	resp, err := c.ListWorkflow(ctx, &workflowservice.ListWorkflowExecutionsRequest{
+		PageSize:      int32(pageSize),
+		NextPageToken: token,
+		Query:         query,
+	})
+	if err != nil {
+		fatal("Unable to list workflows", "query", query, "error", err)
+	}
+
+	page := orderPage{
+		Query:         query,
+		Orders:        make([]orderSummary, 0, len(resp.Executions)),
+		NextPageToken: base64.URLEncoding.EncodeToString(resp.NextPageToken),
+	}
+	for _, info := range resp.Executions {
+		page.Orders = append(page.Orders, summarizeExecution(info))
+	}
+
+	out.print(page, page.table)
+	if page.NextPageToken != "" {
+		slog.Info("More workflows match; for the next page, repeat the command with",
+			"flag", "-next-page-token="+page.NextPageToken)
+	}
+}
+
+// Order statuses counted by -action=stats when the visibility store cannot
+// group by OrderStatus
+var orderStatuses = []models.OrderStatusCode{
+	models.StatusPending, models.StatusValidating, models.StatusAwaitingVerification, models.StatusAwaitingApproval, models.StatusProcessing,
+	models.StatusBackordered, models.StatusCompleted, models.StatusPartiallyCompleted, models.StatusCancelled, models.StatusFailed,
+}
+
+// countGroup is the number of workflows with one value of a field
+type countGroup struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// statsResult is the result of -action=stats
+type statsResult struct {
+	Query             string       `json:"query"`
+	Total             int64        `json:"total"`
+	ByExecutionStatus []countGroup `json:"by_execution_status"`
+	ByOrderStatus     []countGroup `json:"by_order_status,omitempty"`
+}
+
+func (r statsResult) table(w io.Writer) {
+	fmt.Fprintf(w, "%d orders matching %s\n\n", r.Total, r.Query)
+	fmt.Fprintln(w, "WORKFLOW STATUS\tCOUNT")
+	for _, group := range r.ByExecutionStatus {
+		fmt.Fprintf(w, "%s\t%d\n", group.Value, group.Count)
+	}
+	if len(r.ByOrderStatus) == 0 {
+		return
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "ORDER STATUS\tCOUNT")
+	for _, group := range r.ByOrderStatus {
+		fmt.Fprintf(w, "%s\t%d\n", group.Value, group.Count)
+	}
+}
+
+// orderStats prints how many order workflows matching filter are in each
+// workflow execution status and, when the OrderStatus search attribute is
+// registered, each order status. It covers the last 24 hours unless -since
+// is set.
+func orderStats(ctx context.Context, c client.Client, filter listFilter) {
+	if filter.Since == "" {
+		filter.Since = "24h"
+	}
+	query, err := filter.query(time.Now())
+	if err != nil {
+		fatal("Invalid list filter", "error", err)
+	}
+
+	result := statsResult{Query: query}
+	resp, err := c.CountWorkflow(ctx, &workflowservice.CountWorkflowExecutionsRequest{Query: query + " GROUP BY ExecutionStatus"})
+	if err != nil {
+		fatal("Unable to count workflows", "query", query, "error", err)
+	}
+	result.Total = resp.GetCount()
+	result.ByExecutionStatus = countGroups(resp)
+
+	result.ByOrderStatus, err = countByOrderStatus(ctx, c, query)
+	if err != nil {
+		slog.Warn("Unable to count orders by order status; is the OrderStatus search attribute registered?", "error", err)
+	}
+	out.print(result, result.table)
+}
+
+// countByOrderStatus counts the workflows matching query per order status.
+// Visibility stores that only group by ExecutionStatus reject GROUP BY
+// OrderStatus, so then each known status is counted on its own.
+func countByOrderStatus(ctx context.Context, c client.Client, query string) ([]countGroup, error) {
+	resp, err := c.CountWorkflow(ctx, &workflowservice.CountWorkflowExecutionsRequest{
+		Query: query + " GROUP BY " + models.SearchAttributeOrderStatus,
+	})
+	if err == nil {
+		return countGroups(resp), nil
+	}
+	var invalid *serviceerror.InvalidArgument
+	if !errors.As(err, &invalid) {
+		return nil, err
+	}
+
+	var groups []countGroup
+	for _, status := range orderStatuses {
+		resp, err := c.CountWorkflow(ctx, &workflowservice.CountWorkflowExecutionsRequest{
+			Query: fmt.Sprintf("%s AND %s = '%s'", query, models.SearchAttributeOrderStatus, status),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if resp.GetCount() > 0 {
+			groups = append(groups, countGroup{Value: string(status), Count: resp.GetCount()})
+		}
+	}
+	return groups, nil
+}
+
+// countGroups returns the groups of a GROUP BY count, largest first
+func countGroups(resp *workflowservice.CountWorkflowExecutionsResponse) []countGroup {
+	groups := make([]countGroup, 0, len(resp.GetGroups()))
+	for _, group := range resp.GetGroups() {
+		var value string
+		if values := group.GetGroupValues(); len(values) > 0 {
+			_ = converter.GetDefaultDataConverter().FromPayload(values[0], &value)
+		}
+		if value == "" {
+			value = "(none)"
+		}
+		groups = append(groups, countGroup{Value: value, Count: group.GetCount()})
+	}
+	slices.SortStableFunc(groups, func(a, b countGroup) int { return cmp.Compare(b.Count, a.Count) })
+	return groups
+}
+
+// summarizeExecution returns the list row for a workflow execution. Search
+// attributes are missing on orders started before they were enabled.
+func summarizeExecution(info *workflowpb.WorkflowExecutionInfo) orderSummary {
+	summary := orderSummary{
+		WorkflowID: info.GetExecution().GetWorkflowId(),
+		RunID:      info.GetExecution().GetRunId(),
+		Status:     info.GetStatus().String(),
+		StartTime:  info.GetStartTime().AsTime(),
+		CloseTime:  timeOrNil(info.GetCloseTime()),
+	}
+	fields := info.GetSearchAttributes().GetIndexedFields()
+	if payload, ok := fields[models.SearchAttributeOrderStatus]; ok {
+		_ = converter.GetDefaultDataConverter().FromPayload(payload, &summary.OrderStatus)
+	}
+	if payload, ok := fields[models.SearchAttributeOrderExpedited]; ok {
+		_ = converter.GetDefaultDataConverter().FromPayload(payload, &summary.Expedited)
+	}
+	return summary
+}
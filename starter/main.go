@@ -3,190 +3,355 @@ package main
 import (
 	"context"
 	"crypto/rand"
-	"encoding/json"
+	"encoding/hex"
 	"flag"
-	"fmt"
-	"log"
+	"log/slog"
+	"maps"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/aswathylr-builds/temporal-order-processing/authz"
 	"github.com/aswathylr-builds/temporal-order-processing/codec"
+	"github.com/aswathylr-builds/temporal-order-processing/config"
+	"github.com/aswathylr-builds/temporal-order-processing/correlation"
+	"github.com/aswathylr-builds/temporal-order-processing/logging"
 	"github.com/aswathylr-builds/temporal-order-processing/models"
-	"github.com/aswathylr-builds/temporal-order-processing/workflows"
-	"go.temporal.io/sdk/client"
+	"github.com/aswathylr-builds/temporal-order-processing/orderitems"
+	"github.com/aswathylr-builds/temporal-order-processing/orderwait"
+	"github.com/aswathylr-builds/temporal-order-processing/temporalclient"
 )
 
 const (
 	taskQueue = "order-processing-queue"
 )
 
+// Exit codes for -wait, watch, and the batch actions, so scripts can tell a
+// failed order from a slow one
+const (
+	exitFailed  = orderwait.ExitFailed
+	exitTimeout = orderwait.ExitTimeout
+)
+
 func main() {
+	// Settings come from the CONFIG_FILE YAML file, overlaid with environment
+	// variables and then the selected profile; the connection flags below
+	// override all three
+	cfg, err := config.LoadFromEnv()
+	if err != nil {
+		fatal("Invalid configuration", "error", err)
+	}
+	profileName := profileArg(os.Args[1:], os.Getenv(config.ProfileEnv))
+	if cfg, err = cfg.WithProfile(profileName); err != nil {
+		fatal("Invalid profile", "error", err)
+	}
+
 	// Command line flags
 	orderID := flag.String("order-id", "", "Order ID (generated if not provided)")
 	amount := flag.Float64("amount", 100.0, "Order amount")
-	items := flag.String("items", "item1,item2", "Comma-separated list of items")
-	action := flag.String("action", "start", "Action to perform: start, cancel, expedite, query")
+	items := flag.String("items", "item1,item2", "Comma-separated list of items; with -action=update, the items that replace those of an order in its edit window")
+	action := flag.String("action", "start", "Action to perform: start, cancel, hard-cancel, expedite, approve, verify, restock, update, cart, checkout, vendor-response, query, retry, list, describe, stack, terminate, reset, start-batch, interactive, watch, export-history, replay, signal-batch, stats")
 	workflowID := flag.String("workflow-id", "", "Workflow ID for signal/query operations")
-	flag.Parse()
-
-	// Get configuration from environment variables
-	temporalHost := getEnv("TEMPORAL_HOST", "localhost:7233")
-	encryptionEnabled := getEnv("ENCRYPTION_ENABLED", "false") == "true"
-
-	// Create Temporal client options
-	clientOptions := client.Options{
-		HostPort: temporalHost,
+	correlationID := flag.String("correlation-id", "", "Correlation ID forwarded to downstream services (generated if not provided)")
+	tenantID := flag.String("tenant-id", "", "Tenant ID forwarded to downstream services")
+	subject := flag.String("subject", os.Getenv("USER"), "Caller identity in the auth token sent with signals and queries")
+	wait := flag.Bool("wait", false, "With -action=start, block until the workflow completes, print its final status, and exit 1 if it failed or 2 on timeout")
+	waitTimeout := flag.Duration("wait-timeout", 10*time.Minute, "How long -wait blocks before giving up (0 waits indefinitely)")
+	var filter listFilter
+	flag.StringVar(&filter.Status, "status", "", "With -action=list, signal-batch, or stats, only workflows in this execution status: running (the signal-batch default), completed, failed, canceled, terminated, or timedout")
+	flag.StringVar(&filter.OrderStatus, "order-status", "", "With -action=list, signal-batch, or stats, only orders in this order status, e.g. awaiting_approval (needs the OrderStatus search attribute)")
+	flag.BoolVar(&filter.Expedited, "expedited", false, "With -action=list, signal-batch, or stats, only expedited orders (needs the OrderExpedited search attribute)")
+	flag.StringVar(&filter.Since, "since", "", "With -action=list, signal-batch, or stats, only workflows started at or after this RFC 3339 time, or this long ago such as 24h")
+	flag.StringVar(&filter.Until, "until", "", "With -action=list, signal-batch, or stats, only workflows started before this RFC 3339 time, or this long ago such as 1h")
+	flag.StringVar(&filter.Query, "query", "", "With -action=list, signal-batch, or stats, an extra visibility query clause ANDed with the filters above")
+	pageSize := flag.Int("page-size", 50, "With -action=list, how many workflows to list")
+	pageToken := flag.String("next-page-token", "", "With -action=list, the token printed by the previous page")
+	reason := flag.String("reason", "", "With -action=terminate or reset, why, recorded in the workflow history (required)")
+	eventID := flag.Int64("event-id", 0, "With -action=reset, the WorkflowTaskCompleted event to reset to (default the last completed workflow task)")
+	var memo, searchAttrs keyValues
+	flag.Var(&memo, "memo", "With -action=start or start-batch, a memo entry as key=value, where a JSON value keeps its type; repeatable")
+	var orderMemo models.OrderMemo
+	flag.StringVar(&orderMemo.CustomerID, "customer-id", "", "With -action=start or start-batch, the customer placing the order, recorded in the workflow memo")
+	flag.StringVar(&orderMemo.Channel, "channel", "", "With -action=start or start-batch, the sales channel such as web or pos, recorded in the workflow memo; dynamic config can expedite orders by channel")
+	var customer models.Customer
+	flag.StringVar(&customer.Name, "customer-name", "", "With -action=start, the name of the -customer-id customer, carried on the order")
+	flag.StringVar(&customer.Email, "customer-email", "", "With -action=start, where the -customer-id customer's email notifications go, carried on the order")
+	flag.StringVar(&customer.Phone, "customer-phone", "", "With -action=start, where the -customer-id customer's SMS notifications go, carried on the order")
+	flag.StringVar(&customer.Tier, "customer-tier", "", "With -action=start, the loyalty tier of the -customer-id customer: standard, silver, gold, or platinum")
+	flag.StringVar(&orderMemo.Region, "region", "", "With -action=start or start-batch, the sales region of the order, recorded in the workflow memo (unrelated to -cloud-region)")
+	flag.Var(&searchAttrs, "search-attr", "With -action=start or start-batch, a search attribute as name:type=value, where type is keyword, text, int, double, bool, datetime, or keywordlist (comma-separated); repeatable")
+	reusePolicy := flag.String("id-reuse-policy", "", "With -action=start or start-batch, whether an order ID whose workflow has closed can start again: allow-duplicate (the server default), allow-duplicate-failed-only, or reject-duplicate")
+	conflictPolicy := flag.String("id-conflict-policy", "fail", "With -action=start or start-batch, what to do when the order's workflow is still running: fail, use-existing, or terminate-existing")
+	startDelay := flag.Duration("start-delay", 0, "With -action=start or start-batch, how long the workflow waits before it begins processing, e.g. 2h for a pre-order")
+	startAt := flag.String("start-at", "", "With -action=start or start-batch, the RFC 3339 time at which processing begins, instead of -start-delay")
+	queryName := flag.String("query-name", "getStatus", "With -action=query, the query to run: getStatus, getHistory, getEditWindow, or a custom query")
+	queryArgs := flag.String("query-args", "", "With -action=query, the query's arguments as JSON; an array passes each element as an argument")
+	interval := flag.Duration("interval", 2*time.Second, "With -action=watch, how often to poll the order status")
+	watchTimeout := flag.Duration("timeout", 0, "With -action=watch, how long to watch before exiting 2 (0 watches until the order finishes)")
+	batchFile := flag.String("file", "", "With -action=start-batch, a .csv or .json file of orders to start; with -action=replay, a history file or a directory of them")
+	historyOut := flag.String("o", "", "With -action=export-history, the file to write the history to (default stdout)")
+	concurrency := flag.Int("concurrency", 10, "With -action=start-batch or signal-batch, how many workflows to start or signal at once")
+	startRate := flag.Float64("rate", 20, "With -action=start-batch or signal-batch, the most workflows to start or signal per second (0 is unlimited)")
+	batchSignal := flag.String("signal", "", "With -action=signal-batch, the signal to send: cancel, expedite, approve, restock, or retry")
+	dryRun := flag.Bool("dry-run", false, "With -action=start, validate the order and print what would be submitted without starting it; with -action=signal-batch, list the workflows that would be signaled without signaling them")
+	tenders := flag.String("tenders", "", "With -action=start, split the payment across methods, charged in order, as method:amount pairs such as gift_card:20,card:80; the amounts must add up to the order amount. A gift card tender given as gift_card:20:CARD holds that card's balance until the order is fulfilled")
+	installments := flag.Int("installments", 0, "With -action=start, pay the amount in this many installments, one every 30 days, instead of at once")
+	redeemPoints := flag.Int("redeem-points", 0, "With -action=start, loyalty points the customer spends on the order, redeemed from the -customer-id balance; -amount is what remains to pay")
+	currency := flag.String("currency", "", "With -action=start, the ISO 4217 code of -amount and -tenders, such as EUR; the payment is converted into the worker's base currency (default the base currency)")
+	itemsFile := flag.String("items-file", "", "With -action=start, a JSON file of order lines such as [{\"sku\": \"laptop\", \"qty\": 2, \"price\": 999.99}], used instead of -items; the lines' total is the amount unless -amount is set")
+	reminders := flag.String("reminders", "", "With -action=cart, comma-separated waits before each checkout reminder, each counted from the one before, such as 1m,5m,15m (default 1h,24h,72h)")
+	cartExpiry := flag.Duration("cart-expiry", 0, "With -action=cart, how long after the last reminder the cart expires (default 24h)")
+	checkoutAfter := flag.Duration("checkout-after", 0, "With -action=cart, check the cart out this long after it starts, as a returning shopper would; 0 leaves it to be reminded and expire")
+	rejectReason := flag.String("reject-reason", "", "With -action=vendor-response or verify, reject the purchase order or verification for this reason; empty accepts it")
+	templateFile := flag.String("template", "", "With -action=start, a JSON file holding the order to start; -order-id, -amount, and -items override its fields")
+	flag.String("profile", profileName, "Named connection from the profiles section of the config file, such as dev or prod (default $CONFIG_PROFILE)")
+	output := flag.String("output", string(outputTable), "Result format: table, json, or yaml; results go to stdout and logs to stderr")
+	// The connection flags override the config the client is built from
+	flag.StringVar(&cfg.Temporal.Namespace, "namespace", cfg.Temporal.Namespace, "Temporal namespace (default temporal.namespace, or \"default\")")
+	flag.StringVar(&cfg.Temporal.CloudRegion, "cloud-region", cfg.Temporal.CloudRegion, "Temporal Cloud region such as us-east-1.aws, used when temporal.host_port is unset (default temporal.cloud_region)")
+	tlsConfig := &cfg.Temporal.TLS
+	flag.StringVar(&tlsConfig.CAFile, "tls-ca", tlsConfig.CAFile, "CA bundle used to verify the Temporal server (default temporal.tls.ca_file)")
+	flag.StringVar(&tlsConfig.CertFile, "tls-cert", tlsConfig.CertFile, "Client certificate for mTLS to Temporal (default temporal.tls.cert_file)")
+	flag.StringVar(&tlsConfig.KeyFile, "tls-key", tlsConfig.KeyFile, "Client key for mTLS to Temporal (default temporal.tls.key_file)")
+	flag.StringVar(&tlsConfig.ServerName, "tls-server-name", tlsConfig.ServerName, "Server name to verify on the Temporal certificate (default temporal.tls.server_name)")
+	flag.CommandLine.Parse(subcommandArgs(os.Args[1:]))
+	switch out = printer(*output); out {
+	case outputTable, outputJSON, outputYAML:
+	default:
+		fatal("Unknown output format", "output", *output)
 	}
-
-	// Enable encryption if configured
-	if encryptionEnabled {
-		encryptionKey := loadEncryptionKey()
-		dataConverter, err := codec.NewEncryptionDataConverter(encryptionKey)
-		if err != nil {
-			log.Fatalf("Failed to create encryption data converter: %v", err)
+	startOpts, err := newStartOptions(memo, searchAttrs, *reusePolicy, *conflictPolicy)
+	if err == nil {
+		startOpts.StartDelay, err = startDelayUntil(*startDelay, *startAt, time.Now())
+	}
+	// The business context flags win over -memo entries of the same keys
+	if fields := orderMemo.Fields(); len(fields) > 0 {
+		if startOpts.Memo == nil {
+			startOpts.Memo = make(map[string]any)
 		}
-		clientOptions.DataConverter = dataConverter
-		log.Println("Encryption enabled for starter")
+		maps.Copy(startOpts.Memo, fields)
 	}
-
-	// Create the Temporal client
-	c, err := client.Dial(clientOptions)
 	if err != nil {
-		log.Fatalf("Unable to create Temporal client: %v", err)
+		fatal("Invalid start options", "error", err)
 	}
-	defer c.Close()
 
-	ctx := context.Background()
+	// Structured logs for both this process and the Temporal SDK
+	logger := logging.New(os.Stderr, cfg.LoggingConfig())
+	slog.SetDefault(logger)
 
-	switch *action {
-	case "start":
-		startWorkflow(ctx, c, orderID, amount, items)
-	case "cancel":
-		sendSignal(ctx, c, *workflowID, models.SignalCancel)
-	case "expedite":
-		sendSignal(ctx, c, *workflowID, models.SignalExpedite)
-	case "query":
-		queryWorkflow(ctx, c, *workflowID)
-	default:
-		log.Fatalf("Unknown action: %s", *action)
+	if profileName != "" {
+		slog.Info("Using profile", "profile", profileName, "host", cfg.TemporalHostPort())
 	}
-}
 
-func startWorkflow(ctx context.Context, c client.Client, orderID *string, amount *float64, itemsStr *string) {
-	// Generate order ID if not provided
-	if *orderID == "" {
-		*orderID = fmt.Sprintf("ORD-%d", time.Now().Unix())
+	// Create Temporal client options. Compress and encrypt payloads if
+	// configured; this fetches an encryption key now so a misconfigured key
+	// store fails at startup.
+	clientOptions, err := temporalclient.NewOptions(cfg, logger)
+	if err != nil {
+		fatal("Invalid Temporal client configuration", "error", err)
 	}
-
-	// Parse items
-	items := []string{}
-	if *itemsStr != "" {
-		json.Unmarshal([]byte(fmt.Sprintf("[\"%s\"]", *itemsStr)), &items)
+	if cfg.Encryption.Enabled {
+		slog.Info("Encryption enabled for starter", "mode", cfg.Encryption.Mode, "keys", cfg.EncryptionKeySource(), "fips", cfg.Encryption.FIPS)
 	}
-
-	// Create order
-	order := models.Order{
-		ID:        *orderID,
-		Items:     items,
-		Amount:    *amount,
-		Status:    models.StatusPending,
-		CreatedAt: time.Now(),
+	if cfg.Payload.Compression != string(codec.CompressionNone) {
+		slog.Info("Payload compression enabled", "algorithm", cfg.Payload.Compression)
 	}
 
-	// Workflow options
-	workflowOptions := client.StartWorkflowOptions{
-		ID:        fmt.Sprintf("order-workflow-%s", order.ID),
-		TaskQueue: taskQueue,
+	// Replay runs the workflow code locally, so it needs no connection
+	if *action == "replay" {
+		replayHistories(*batchFile, replayerOptions(cfg, clientOptions.DataConverter, clientOptions.FailureConverter))
+		return
 	}
 
-	// Start workflow
-	we, err := c.ExecuteWorkflow(ctx, workflowOptions, workflows.OrderWorkflow, order)
-	if err != nil {
-		log.Fatalf("Unable to execute workflow: %v", err)
-	}
-
-	log.Printf("Started workflow successfully")
-	log.Printf("  Workflow ID: %s", we.GetID())
-	log.Printf("  Run ID: %s", we.GetRunID())
-	log.Printf("  Order ID: %s", order.ID)
-	log.Printf("  Amount: $%.2f", order.Amount)
-	log.Printf("  Items: %v", order.Items)
-	log.Println()
-	log.Println("To query the workflow status, run:")
-	log.Printf("  go run starter/main.go -action=query -workflow-id=%s", we.GetID())
-	log.Println()
-	log.Println("To expedite the order, run:")
-	log.Printf("  go run starter/main.go -action=expedite -workflow-id=%s", we.GetID())
-	log.Println()
-	log.Println("To cancel the order, run:")
-	log.Printf("  go run starter/main.go -action=cancel -workflow-id=%s", we.GetID())
-}
-
-func sendSignal(ctx context.Context, c client.Client, workflowID, signalName string) {
-	if workflowID == "" {
-		log.Fatal("workflow-id is required for signal operations")
+	// A dry run checks the order locally, so it needs no connection either
+	var order models.Order
+	if *action == "start" {
+		set := map[string]bool{}
+		flag.Visit(func(f *flag.Flag) { set[f.Name] = true })
+		customer.ID = orderMemo.CustomerID
+		if order, err = startOrder(*templateFile, *itemsFile, *orderID, *amount, *items, *tenders, *installments, *redeemPoints, *currency, customer, set); err != nil {
+			fatal("Invalid order", "error", err)
+		}
+		// The memo records the customer of an order that carries one
+		if order.Customer != nil && order.Customer.ID != "" && startOpts.Memo[models.MemoCustomerID] == nil {
+			if startOpts.Memo == nil {
+				startOpts.Memo = make(map[string]any)
+			}
+			startOpts.Memo[models.MemoCustomerID] = order.Customer.ID
+		}
+		if *dryRun {
+			result := checkOrder(order, cfg.LocalValidationRules(), cfg.Payload.MaxBytes, startOpts)
+			out.print(result, result.table)
+			if !result.Valid {
+				os.Exit(exitFailed)
+			}
+			return
+		}
 	}
 
-	err := c.SignalWorkflow(ctx, workflowID, "", signalName, nil)
+	// Create the Temporal client, connecting over TLS, with a Temporal Cloud
+	// API key, or with JWT bearer tokens if configured
+	c, _, err := temporalclient.Connect(cfg, &clientOptions)
 	if err != nil {
-		log.Fatalf("Unable to signal workflow: %v", err)
+		fatal("Unable to create Temporal client", "error", err)
 	}
+	defer c.Close()
 
-	log.Printf("Signal '%s' sent successfully to workflow: %s", signalName, workflowID)
-}
-
-func queryWorkflow(ctx context.Context, c client.Client, workflowID string) {
-	if workflowID == "" {
-		log.Fatal("workflow-id is required for query operations")
+	// Correlation values travel with the workflow into every activity
+	if *correlationID == "" {
+		*correlationID = newCorrelationID()
 	}
+	ctx := correlation.WithValues(context.Background(), correlation.Values{
+		CorrelationID: *correlationID,
+		TenantID:      *tenantID,
+	})
+	slog.Info("Using correlation ID", "correlation_id", *correlationID)
 
-	// Create a context with longer timeout for query
-	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
-	defer cancel()
-
-	response, err := c.QueryWorkflow(queryCtx, workflowID, "", "getStatus")
-	if err != nil {
-		log.Fatalf("Unable to query workflow: %v", err)
+	// Sign each request when the worker requires authorized signals. Tokens
+	// are signed per call, so an interactive session or a long batch does not
+	// outlive them.
+	if len(cfg.Auth.SignalSecrets) > 0 {
+		signer, err := authz.NewSigner(cfg.Auth.SignalSecrets...)
+		if err != nil {
+			fatal("Invalid signal auth configuration", "error", err)
+		}
+		ctx = authz.WithSigner(ctx, signer, *subject, 5*time.Minute)
 	}
 
-	var status models.OrderStatus
-	if err := response.Get(&status); err != nil {
-		log.Fatalf("Unable to decode query result: %v", err)
+	switch *action {
+	case "start":
+		run := startWorkflow(ctx, c, order, startOpts)
+		result := startResult{WorkflowID: run.GetID(), RunID: run.GetRunID(), OrderID: order.ID}
+		exitCode := 0
+		if *wait {
+			outcome := orderwait.Await(ctx, c, run, *waitTimeout)
+			result.Result, result.FinalStatus, result.Error, exitCode = outcome.Result, outcome.FinalStatus, outcome.Error, outcome.ExitCode
+		}
+		out.print(result, result.table)
+		if exitCode != 0 {
+			os.Exit(exitCode)
+		}
+	case "interactive":
+		// Keeps one client open for a demo or debugging session
+		interactive(ctx, c)
+	case "start-batch":
+		// Starts every order in a file, for load tests and backfills
+		startBatch(ctx, c, *batchFile, *concurrency, *startRate, startOpts)
+	case "cancel":
+		sendSignal(ctx, c, *workflowID, models.SignalCancel)
+	case "expedite":
+		sendSignal(ctx, c, *workflowID, models.SignalExpedite)
+	case "approve":
+		// Releases a high-value order waiting for approval before payment
+		sendSignal(ctx, c, *workflowID, models.SignalApprove)
+	case "verify":
+		// Answers step-up verification for the customer of a risky order
+		sendSignalArg(ctx, c, *workflowID, models.SignalVerify, models.StepUpResult{Verified: *rejectReason == "", Reason: *rejectReason})
+	case "restock":
+		// Resumes fulfillment of a backordered order once its items are back in stock
+		sendSignal(ctx, c, *workflowID, models.SignalRestock)
+	case "update":
+		// Changes the items of a paid order while its edit window is open
+		sendSignalArg(ctx, c, *workflowID, models.SignalUpdate, models.OrderUpdate{Items: orderitems.Parse(*items)})
+	case "cart":
+		// Simulates an abandoned checkout: -order-id names the cart, and
+		// -checkout-after checks it out partway through the reminders
+		cart, err := newCart(*orderID, *amount, *items, orderMemo.CustomerID, *reminders, *cartExpiry)
+		if err != nil {
+			fatal("Invalid cart", "error", err)
+		}
+		simulateCart(ctx, c, cart, *checkoutAfter, *wait, *waitTimeout)
+	case "checkout":
+		// Ends a cart's reminders; -order-id, if given, names the order it became
+		sendSignalArg(ctx, c, *workflowID, models.SignalCheckoutCompleted, models.CheckoutCompleted{OrderID: *orderID})
+	case "vendor-response":
+		// Answers a dropship purchase order for its vendor; the workflow ID is the vendor-... workflow
+		sendSignalArg(ctx, c, *workflowID, models.SignalVendorResponse, models.VendorResponse{Accepted: *rejectReason == "", Reason: *rejectReason})
+	case "query":
+		queryWorkflow(ctx, c, *workflowID, *queryName, *queryArgs)
+	case "retry":
+		// Re-drives a dead-lettered order; the workflow ID is the failed-order-... workflow
+		sendSignal(ctx, c, *workflowID, models.SignalRetry)
+	case "list":
+		// Finds orders through the visibility API, e.g. running orders started over a day ago
+		listWorkflows(ctx, c, filter, *pageSize, *pageToken)
+	case "watch":
+		// Follows an order live until it finishes
+		watchWorkflow(ctx, c, *workflowID, *interval, *watchTimeout)
+	case "export-history":
+		// Saves a production history for replay against new workflow code
+		exportHistory(ctx, c, *workflowID, *historyOut)
+	case "signal-batch":
+		// Expedites or cancels every matching order at once during an incident
+		signalBatch(ctx, c, filter, *batchSignal, *concurrency, *startRate, *dryRun)
+	case "stats":
+		// A terminal dashboard: orders per workflow and order status
+		orderStats(ctx, c, filter)
+	case "describe":
+		// Shows why an order is stuck: pending activities, their attempts, and the next retry
+		describeWorkflow(ctx, c, *workflowID)
+	case "stack":
+		// Shows where each workflow coroutine is blocked; needs a running worker
+		stackTrace(ctx, c, *workflowID)
+	case "hard-cancel":
+		// Cancels through Temporal, interrupting the running step, rather than
+		// with the cancel signal the workflow checks between steps
+		cancelWorkflow(ctx, c, *workflowID)
+	case "terminate":
+		terminateWorkflow(ctx, c, *workflowID, *reason)
+	case "reset":
+		// Replays the order from a workflow task, e.g. the last one before a bad deployment
+		resetWorkflow(ctx, c, clientOptions.Namespace, *workflowID, *eventID, *reason)
+	default:
+		fatal("Unknown action", "action", *action)
 	}
-
-	// Pretty print the status
-	statusJSON, _ := json.MarshalIndent(status, "", "  ")
-	log.Println("Workflow Status:")
-	fmt.Println(string(statusJSON))
 }
 
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
+// fatal logs an error and exits
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
 }
 
-func loadEncryptionKey() []byte {
-	keyFile := ".encryption.key"
-
-	// Try to read existing key
-	if key, err := os.ReadFile(keyFile); err == nil && len(key) == 32 {
-		log.Println("Using existing encryption key")
-		return key
+// profileArg returns the value of the -profile flag in args, or def. The
+// profile is needed before flag.Parse, since it sets the defaults of the
+// connection flags.
+func profileArg(args []string, def string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if name != "profile" {
+			continue
+		}
+		if hasValue {
+			return value
+		}
+		if i+1 < len(args) {
+			return args[i+1]
+		}
 	}
+	return def
+}
 
-	// Generate new key if not found
-	key := make([]byte, 32) // AES-256
-	if _, err := rand.Read(key); err != nil {
-		log.Fatalf("Failed to generate encryption key: %v", err)
+// subcommandArgs rewrites the subcommand form of an action, such as
+// order start --wait, to its flag form, -action=start --wait
+func subcommandArgs(args []string) []string {
+	if len(args) < 2 || args[0] != "order" || strings.HasPrefix(args[1], "-") {
+		return args
 	}
+	return append([]string{"-action=" + args[1]}, args[2:]...)
+}
 
-	// Save key for future use (development only!)
-	if err := os.WriteFile(keyFile, key, 0600); err != nil {
-		log.Printf("Warning: Failed to save encryption key: %v", err)
+// newCorrelationID returns a random 128-bit hex correlation ID
+func newCorrelationID() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		fatal("Failed to generate correlation ID", "error", err)
 	}
-
-	log.Println("Generated new encryption key")
-	return key
+	return hex.EncodeToString(id)
 }
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/orderitems"
+)
+
+// newOrder creates a pending order from the start flags, generating an ID if
+// orderID is empty
+func newOrder(orderID string, amount float64, itemsStr string) models.Order {
+	// Generate order ID if not provided
+	if orderID == "" {
+		orderID = fmt.Sprintf("ORD-%d", time.Now().Unix())
+	}
+
+	return models.Order{
+		ID:        orderID,
+		Items:     orderitems.Parse(itemsStr),
+		Amount:    amount,
+		Status:    models.StatusPending,
+		CreatedAt: time.Now(),
+	}
+}
+
+// startOrder returns the order of -action=start: the order in the template
+// file, if given, with the fields of the flags in set overriding it, or else
+// the order described by the flags. The lines of the items file, if given,
+// replace the items and vendors, and their total replaces the amount unless
+// -amount is set.
+// Tenders, installments, redeemed points, and currency, if given, replace the
+// order's. So does the customer, when any of its contact or tier flags is
+// given. The order is returned upgraded to the current schema, and only if
+// it passes Validate.
+func startOrder(templatePath, itemsPath, orderID string, amount float64, items, tenders string, installments, redeemPoints int, currency string, customer models.Customer, set map[string]bool) (models.Order, error) {
+	if itemsPath != "" && set["items"] {
+		return models.Order{}, errors.New("-items and -items-file both set the items; use one")
+	}
+	order := newOrder(orderID, amount, items)
+	if templatePath != "" {
+		var err error
+		if order, err = templateOrder(templatePath, order, set); err != nil {
+			return models.Order{}, fmt.Errorf("template %s: %w", templatePath, err)
+		}
+	}
+	if itemsPath != "" {
+		lines, err := orderitems.ReadFile(itemsPath)
+		if err != nil {
+			return models.Order{}, fmt.Errorf("items file %s: %w", itemsPath, err)
+		}
+		var total float64
+		order.Items, total = orderitems.Expand(lines)
+		order.Vendors = orderitems.Vendors(lines)
+		order.Lines = lines
+		if !set["amount"] && total > 0 {
+			order.Amount = total
+		}
+	}
+	if tenders != "" {
+		var err error
+		if order.Tenders, err = parseTenders(tenders); err != nil {
+			return models.Order{}, err
+		}
+	}
+	if set["installments"] {
+		order.Installments = installments
+	}
+	if set["redeem-points"] {
+		order.RedeemPoints = redeemPoints
+	}
+	if set["currency"] {
+		order.Currency = strings.ToUpper(currency)
+		if len(order.Currency) != 3 {
+			return models.Order{}, fmt.Errorf("-currency must be a three-letter ISO 4217 code, got %q", currency)
+		}
+	}
+	if set["customer-name"] || set["customer-email"] || set["customer-phone"] || set["customer-tier"] {
+		if customer.ID == "" {
+			return models.Order{}, errors.New("-customer-name, -customer-email, -customer-phone, and -customer-tier need a -customer-id")
+		}
+		order.Customer = &customer
+	}
+	order, err := order.Upgrade()
+	if err != nil {
+		return models.Order{}, err
+	}
+	if err := order.Validate(); err != nil {
+		return models.Order{}, err
+	}
+	return order, nil
+}
+
+// parseTenders parses comma-separated method:amount pairs, as given to
+// -tenders; a gift card tender may add :card to have its balance held
+func parseTenders(tendersStr string) ([]models.Tender, error) {
+	var tenders []models.Tender
+	for _, pair := range strings.Split(tendersStr, ",") {
+		method, amount, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok {
+			return nil, fmt.Errorf("tender %q is not method:amount", pair)
+		}
+		amount, card, _ := strings.Cut(amount, ":")
+		value, err := strconv.ParseFloat(amount, 64)
+		if err != nil {
+			return nil, fmt.Errorf("tender %q: invalid amount: %w", pair, err)
+		}
+		tenders = append(tenders, models.Tender{Method: method, Amount: value, Card: card})
+	}
+	return tenders, nil
+}
+
+// templateOrder returns the order in the template file at path, with the
+// fields of flagged whose flags are in set overriding it
+func templateOrder(path string, flagged models.Order, set map[string]bool) (models.Order, error) {
+	order, err := readOrderTemplate(path)
+	if err != nil {
+		return models.Order{}, err
+	}
+	if set["order-id"] || order.ID == "" {
+		order.ID = flagged.ID
+	}
+	if set["amount"] {
+		order.Amount = flagged.Amount
+	}
+	if set["items"] {
+		// The template's lines described the items being replaced
+		order.Items = flagged.Items
+		order.Lines = nil
+	}
+	if order.Status == "" {
+		order.Status = models.StatusPending
+	}
+	if order.CreatedAt.IsZero() {
+		order.CreatedAt = flagged.CreatedAt
+	}
+	return order, nil
+}
+
+// readOrderTemplate reads a JSON order from path. Unknown fields are
+// rejected, so a misspelt field is reported rather than silently dropped.
+func readOrderTemplate(path string) (models.Order, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return models.Order{}, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var order models.Order
+	if err := decoder.Decode(&order); err != nil {
+		return models.Order{}, err
+	}
+	if decoder.More() {
+		return models.Order{}, errors.New("unexpected data after the order")
+	}
+	return order, nil
+}
+
+// dryRunResult is what -dry-run prints instead of starting the order
+type dryRunResult struct {
+	Valid      bool         `json:"valid"`
+	Problems   []string     `json:"problems,omitempty"`
+	WorkflowID string       `json:"workflow_id"`
+	TaskQueue  string       `json:"task_queue"`
+	StartDelay string       `json:"start_delay,omitempty"`
+	Order      models.Order `json:"order"`
+}
+
+func (r dryRunResult) table(w io.Writer) {
+	verdict := "valid"
+	if !r.Valid {
+		verdict = "invalid"
+	}
+	fmt.Fprintf(w, "Order:\t%s (%s, not started)\n", r.Order.ID, verdict)
+	for _, problem := range r.Problems {
+		fmt.Fprintf(w, "Problem:\t%s\n", problem)
+	}
+	fmt.Fprintf(w, "Workflow ID:\t%s\n", r.WorkflowID)
+	fmt.Fprintf(w, "Task queue:\t%s\n", r.TaskQueue)
+	if r.StartDelay != "" {
+		fmt.Fprintf(w, "Start delay:\t%s\n", r.StartDelay)
+	}
+	fmt.Fprintf(w, "Amount:\t%.2f\n", r.Order.Amount)
+	if r.Order.Currency != "" {
+		fmt.Fprintf(w, "Currency:\t%s\n", r.Order.Currency)
+	}
+	fmt.Fprintf(w, "Items:\t%s\n", strings.Join(r.Order.Items, ", "))
+	if r.Order.FulfillmentParallelism > 0 {
+		fmt.Fprintf(w, "Fulfillment parallelism:\t%d\n", r.Order.FulfillmentParallelism)
+	}
+	for _, tender := range r.Order.Tenders {
+		if tender.Card != "" {
+			fmt.Fprintf(w, "Tender:\t%s %.2f (card %s, held)\n", tender.Method, tender.Amount, tender.Card)
+			continue
+		}
+		fmt.Fprintf(w, "Tender:\t%s %.2f\n", tender.Method, tender.Amount)
+	}
+	if r.Order.Installments > 1 {
+		fmt.Fprintf(w, "Installments:\t%d\n", r.Order.Installments)
+	}
+	if r.Order.RedeemPoints > 0 {
+		fmt.Fprintf(w, "Redeem points:\t%d\n", r.Order.RedeemPoints)
+	}
+}
+
+// checkOrder validates order as far as it can without the server: the
+// fields the workflow needs, the local validation rules, and the payload
+// size limit. The validation service may still reject an order that passes.
+func checkOrder(order models.Order, rules activities.ValidationRules, maxPayloadBytes int, opts startOptions) dryRunResult {
+	result := dryRunResult{
+		WorkflowID: models.OrderWorkflowID(order.ID),
+		TaskQueue:  taskQueue,
+		Order:      order,
+	}
+	if opts.StartDelay > 0 {
+		result.StartDelay = opts.StartDelay.String()
+	}
+	if len(order.Items) == 0 {
+		result.Problems = append(result.Problems, "the order has no items")
+	}
+	if slices.Contains(order.Items, "") {
+		result.Problems = append(result.Problems, "the order has an empty item")
+	}
+	if order.Amount <= 0 {
+		result.Problems = append(result.Problems, "the amount must be positive")
+	}
+	if order.FulfillmentParallelism < 0 {
+		result.Problems = append(result.Problems, "fulfillment_parallelism must not be negative")
+	}
+	if err := order.ValidateTenders(); err != nil {
+		result.Problems = append(result.Problems, err.Error())
+	}
+	if err := order.ValidateInstallments(); err != nil {
+		result.Problems = append(result.Problems, err.Error())
+	}
+	if order.RedeemPoints < 0 {
+		result.Problems = append(result.Problems, "redeem_points must not be negative")
+	}
+	if order.RedeemPoints > 0 && opts.Memo[models.MemoCustomerID] == nil {
+		result.Problems = append(result.Problems, "redeeming points needs a -customer-id")
+	}
+	if order.Status != models.StatusPending {
+		result.Problems = append(result.Problems, fmt.Sprintf("a new order's status must be %q, got %q", models.StatusPending, order.Status))
+	}
+	// The local rules never return an error
+	verdict, _ := activities.NewRulesValidator(rules).Validate(context.Background(), order)
+	if !verdict.Valid {
+		result.Problems = append(result.Problems, verdict.Message)
+	}
+	if data, err := json.Marshal(order); err != nil {
+		result.Problems = append(result.Problems, err.Error())
+	} else if maxPayloadBytes > 0 && len(data) > maxPayloadBytes {
+		result.Problems = append(result.Problems, fmt.Sprintf("the order is %d bytes, over the payload limit of %d", len(data), maxPayloadBytes))
+	}
+	result.Valid = len(result.Problems) == 0
+	return result
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gopkg.in/yaml.v3"
+)
+
+// timeOrNil converts a protobuf timestamp, returning nil when it is unset
+func timeOrNil(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
+// formatTime formats t in local time for table output, or "-" when it is unset
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Local().Format(time.DateTime)
+}
+
+// orDash returns value, or "-" when it is empty, for table output
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// printer writes action results to stdout in the -output format; logs go to
+// stderr, so results can be piped into jq or scripts
+type printer string
+
+// Output formats
+const (
+	outputTable printer = "table"
+	outputJSON  printer = "json"
+	outputYAML  printer = "yaml"
+)
+
+// out is the -output format
+var out = outputTable
+
+// print writes v as JSON or YAML, with the same field names in both, or as
+// the aligned columns written by table
+func (p printer) print(v any, table func(w io.Writer)) {
+	switch p {
+	case outputJSON:
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(v); err != nil {
+			fatal("Unable to encode result", "error", err)
+		}
+	case outputYAML:
+		data, err := toYAML(v)
+		if err != nil {
+			fatal("Unable to encode result", "error", err)
+		}
+		os.Stdout.Write(data)
+	default:
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		table(w)
+		w.Flush()
+	}
+}
+
+// toYAML encodes v as YAML through its JSON encoding, so the keys follow the
+// json tags and keep their order
+func toYAML(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	blockStyle(&node)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), enc.Close()
+}
+
+// blockStyle clears the flow style and quoting that JSON input leaves on node
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
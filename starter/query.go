@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/orderwait"
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/client"
+)
+
+// watchWorkflow follows an order until it finishes, exiting with exitFailed
+// if it did not complete and exitTimeout if it is still running after timeout
+func watchWorkflow(ctx context.Context, c client.Client, workflowID string, interval, timeout time.Duration) {
+	if workflowID == "" {
+		fatal("workflow-id is required for watch operations")
+	}
+	if interval <= 0 {
+		fatal("interval must be positive", "interval", interval)
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	status, err := watchOrder(ctx, c, workflowID, interval)
+	switch {
+	case err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded):
+		slog.Error("Timed out watching workflow", "workflow_id", workflowID, "timeout", timeout)
+		os.Exit(exitTimeout)
+	case err != nil:
+		fatal("Unable to watch workflow", "error", err)
+	case status.Status != models.StatusCompleted:
+		slog.Error("Order did not complete", "workflow_id", workflowID, "status", status.Status)
+		os.Exit(exitFailed)
+	}
+}
+
+// watchOrder prints the order's status each time its status or stage
+// changes, polling every interval, until the order reaches a terminal status.
+// It returns the last status, and an error if the workflow closed without
+// one, such as when it was terminated.
+func watchOrder(ctx context.Context, c client.Client, workflowID string, interval time.Duration) (models.OrderStatus, error) {
+	var last models.OrderStatus
+	for {
+		status, err := orderwait.Status(ctx, c, workflowID, "")
+		if err != nil {
+			return last, err
+		}
+		if status.Status != last.Status || status.Stage != last.Stage || status.IsExpedited != last.IsExpedited {
+			out.print(status, func(w io.Writer) {
+				fmt.Fprintf(w, "%s  %-20s %-12s expedited=%t\n", status.LastUpdated.Local().Format(time.TimeOnly),
+					status.Status, status.Stage, status.IsExpedited)
+			})
+		}
+		last = status
+		if status.Status.IsTerminal() {
+			return last, nil
+		}
+
+		// A terminated or timed out workflow keeps answering with its last status
+		desc, err := c.DescribeWorkflowExecution(ctx, workflowID, "")
+		if err != nil {
+			return last, err
+		}
+		if closed := desc.GetWorkflowExecutionInfo().GetStatus(); closed != enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING {
+			return last, fmt.Errorf("workflow closed as %s with order status %s", closed, status.Status)
+		}
+
+		select {
+		case <-ctx.Done():
+			return last, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// queryKey identifies a query of a workflow type
+type queryKey struct {
+	workflowType string
+	query        string
+}
+
+// queryResults return a pointer to the type each known query returns, for
+// decoding; the results of other queries are decoded as plain JSON
+var queryResults = map[queryKey]func() any{
+	{"OrderWorkflow", "getStatus"}:                func() any { return &models.OrderStatus{} },
+	{"OrderWorkflow", "getHistory"}:               func() any { return &[]models.StatusChange{} },
+	{"OrderWorkflow", "getEditWindow"}:            func() any { return &models.EditWindow{} },
+	{"FailedOrderWorkflow", "getStatus"}:          func() any { return &models.DeadLetterStatus{} },
+	{"ItemFulfillmentWorkflow", "getStatus"}:      func() any { return &models.ItemFulfillment{} },
+	{"CartReminderWorkflow", "getStatus"}:         func() any { return &models.CartStatus{} },
+	{"TrackingWorkflow", "getStatus"}:             func() any { return &models.TrackingUpdate{} },
+	{"WarehouseFulfillmentWorkflow", "getStatus"}: func() any { return &models.WarehouseFulfillmentResult{} },
+	{"VendorFulfillmentWorkflow", "getStatus"}:    func() any { return &models.VendorFulfillment{} },
+}
+
+// queryWorkflow runs queryName against the latest run of workflowID with the
+// arguments in argsJSON and prints the decoded result
+func queryWorkflow(ctx context.Context, c client.Client, workflowID, queryName, argsJSON string) {
+	if workflowID == "" {
+		fatal("workflow-id is required for query operations")
+	}
+	args, err := parseQueryArgs(argsJSON)
+	if err != nil {
+		fatal("Invalid query arguments", "error", err)
+	}
+
+	// The result type depends on the workflow type as well as the query:
+	// dead-letter and item workflows answer getStatus too
+	desc, err := c.DescribeWorkflowExecution(ctx, workflowID, "")
+	if err != nil {
+		fatal("Unable to describe workflow", "error", err)
+	}
+	var result any = new(any)
+	if newResult, ok := queryResults[queryKey{desc.GetWorkflowExecutionInfo().GetType().GetName(), queryName}]; ok {
+		result = newResult()
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	response, err := c.QueryWorkflow(queryCtx, workflowID, "", queryName, args...)
+	if err != nil {
+		fatal("Unable to query workflow", "query", queryName, "error", err)
+	}
+	if err := response.Get(result); err != nil {
+		fatal("Unable to decode query result", "query", queryName, "error", err)
+	}
+
+	out.print(result, func(w io.Writer) {
+		switch result := result.(type) {
+		case *models.OrderStatus:
+			statusTable(w, *result)
+		case *[]models.StatusChange:
+			fmt.Fprintln(w, "AT\tSTATUS\tSTAGE")
+			for _, change := range *result {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", formatTime(&change.At), change.Status, change.Stage)
+			}
+		default:
+			// No table layout for this result; JSON is still readable
+			resultJSON, _ := json.MarshalIndent(result, "", "  ")
+			fmt.Fprintln(w, string(resultJSON))
+		}
+	})
+}
+
+// parseQueryArgs decodes -query-args: nothing, a JSON array of arguments,
+// or any other JSON value as the only argument
+func parseQueryArgs(argsJSON string) ([]any, error) {
+	if strings.TrimSpace(argsJSON) == "" {
+		return nil, nil
+	}
+	var value any
+	if err := json.Unmarshal([]byte(argsJSON), &value); err != nil {
+		return nil, err
+	}
+	if args, ok := value.([]any); ok {
+		return args, nil
+	}
+	return []any{value}, nil
+}
+
+// statusTable writes an order status as the table output of query and start -wait
+func statusTable(w io.Writer, status models.OrderStatus) {
+	fmt.Fprintf(w, "Order ID:\t%s\n", status.OrderID)
+	fmt.Fprintf(w, "Status:\t%s\n", status.Status)
+	fmt.Fprintf(w, "Stage:\t%s\n", status.Stage)
+	fmt.Fprintf(w, "Payment:\t%s\n", status.PaymentStatus)
+	fmt.Fprintf(w, "Expedited:\t%t\n", status.IsExpedited)
+	if status.ProvisionallyValidated {
+		fmt.Fprintf(w, "Provisionally validated:\t%t\n", status.ProvisionallyValidated)
+	}
+	if status.SLABreached {
+		fmt.Fprintf(w, "SLA breached:\t%t\n", status.SLABreached)
+	}
+	if status.InvoiceURL != "" {
+		fmt.Fprintf(w, "Invoice:\t%s\n", status.InvoiceURL)
+	}
+	if status.ShipmentStatus != "" {
+		fmt.Fprintf(w, "Shipment:\t%s\n", status.ShipmentStatus)
+	}
+	fmt.Fprintf(w, "Last updated:\t%s\n", formatTime(&status.LastUpdated))
+	if len(status.ItemResults) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "ITEM\tSTATUS\tSTEP\tERROR")
+		for _, item := range status.ItemResults {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", item.Item, item.Status, orDash(item.Step), orDash(item.Error))
+		}
+	}
+	if len(status.Shipments) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "SHIPMENT\tWAREHOUSE\tSTATUS\tTRACKING\tITEMS")
+		for _, shipment := range status.Shipments {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", shipment.ShipmentID, shipment.WarehouseID, shipment.Status,
+				orDash(shipment.TrackingStatus), strings.Join(shipment.Items, ","))
+		}
+	}
+	if len(status.Vendors) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "PURCHASE ORDER\tVENDOR\tSTATUS\tITEMS\tREASON")
+		for _, vendor := range status.Vendors {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", vendor.PurchaseOrder, vendor.VendorID, vendor.Status,
+				strings.Join(vendor.Items, ","), orDash(vendor.Reason))
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/aswathylr-builds/temporal-order-processing/authz"
+	"github.com/aswathylr-builds/temporal-order-processing/config"
+	"github.com/aswathylr-builds/temporal-order-processing/correlation"
+	"github.com/aswathylr-builds/temporal-order-processing/logging"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+// replayerOptions mirror the order worker's settings that affect workflow
+// code: payload decoding, and signal authorization, which decides whether a
+// signal reaches the workflow at all
+func replayerOptions(cfg config.Config, dataConverter converter.DataConverter, failureConverter converter.FailureConverter) worker.WorkflowReplayerOptions {
+	opts := worker.WorkflowReplayerOptions{
+		DataConverter:      dataConverter,
+		FailureConverter:   failureConverter,
+		ContextPropagators: []workflow.ContextPropagator{correlation.NewPropagator()},
+	}
+	if len(cfg.Auth.SignalSecrets) > 0 {
+		signer, err := authz.NewSigner(cfg.Auth.SignalSecrets...)
+		if err != nil {
+			fatal("Invalid signal auth configuration", "error", err)
+		}
+		opts.Interceptors = append(opts.Interceptors, authz.NewInterceptor(authz.Config{
+			Signer:           signer,
+			ProtectedSignals: models.ProtectedSignals,
+			SignalSenders:    workflows.SignalSenders,
+			ProtectQueries:   cfg.Auth.ProtectQueries,
+		}))
+	}
+	return opts
+}
+
+// replayResult is the outcome of replaying one history file
+type replayResult struct {
+	File  string `json:"file"`
+	Error string `json:"error,omitempty"`
+}
+
+// replayHistories replays the history in path, or each .json file in the
+// directory path, against the workflow code in this build. It exits with
+// exitFailed if any replay fails, which usually means a change is not
+// deterministic and needs workflow.GetVersion.
+func replayHistories(path string, opts worker.WorkflowReplayerOptions) {
+	if path == "" {
+		fatal("file is required for replay operations")
+	}
+	files := []string{path}
+	if info, err := os.Stat(path); err != nil {
+		fatal("Unable to read history", "error", err)
+	} else if info.IsDir() {
+		files, err = filepath.Glob(filepath.Join(path, "*.json"))
+		if err != nil || len(files) == 0 {
+			fatal("No .json history files found", "dir", path)
+		}
+	}
+
+	replayer, err := worker.NewWorkflowReplayerWithOptions(opts)
+	if err != nil {
+		fatal("Unable to create workflow replayer", "error", err)
+	}
+	replayer.RegisterWorkflow(workflows.OrderWorkflow)
+	replayer.RegisterWorkflow(workflows.PaymentWorkflow)
+	replayer.RegisterWorkflow(workflows.FailedOrderWorkflow)
+	replayer.RegisterWorkflow(workflows.ItemFulfillmentWorkflow)
+
+	results := make([]replayResult, 0, len(files))
+	failed := 0
+	for _, file := range files {
+		result := replayResult{File: file}
+		if err := replayer.ReplayWorkflowHistoryFromJSONFile(logging.NewTemporalLogger(slog.Default()), file); err != nil {
+			result.Error = err.Error()
+			failed++
+		}
+		results = append(results, result)
+	}
+
+	out.print(results, func(w io.Writer) {
+		for _, result := range results {
+			if result.Error == "" {
+				fmt.Fprintf(w, "ok\t%s\n", result.File)
+			} else {
+				fmt.Fprintf(w, "FAIL\t%s\t%s\n", result.File, result.Error)
+			}
+		}
+	})
+	if failed > 0 {
+		slog.Error("Replay failed; the workflow code is not compatible with these histories", "failed", failed, "total", len(files))
+		os.Exit(exitFailed)
+	}
+}
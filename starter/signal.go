@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/client"
+	"golang.org/x/time/rate"
+)
+
+func sendSignal(ctx context.Context, c client.Client, workflowID, signalName string) {
+	sendSignalArg(ctx, c, workflowID, signalName, nil)
+}
+
+// sendSignalArg sends signalName with arg to the latest run of workflowID
+func sendSignalArg(ctx context.Context, c client.Client, workflowID, signalName string, arg any) {
+	if workflowID == "" {
+		fatal("workflow-id is required for signal operations")
+	}
+
+	err := c.SignalWorkflow(ctx, workflowID, "", signalName, arg)
+	if err != nil {
+		fatal("Unable to signal workflow", "error", err)
+	}
+
+	result := signalResult{WorkflowID: workflowID, Signal: signalName}
+	out.print(result, func(w io.Writer) {
+		fmt.Fprintf(w, "Sent %s to %s\n", signalName, workflowID)
+	})
+}
+
+// signalResult is the result of the signal actions
+type signalResult struct {
+	WorkflowID string `json:"workflow_id"`
+	Signal     string `json:"signal"`
+}
+
+// signalFailure is a workflow of a batch that could not be signaled
+type signalFailure struct {
+	WorkflowID string `json:"workflow_id"`
+	Error      string `json:"error"`
+}
+
+// signalBatchSummary reports the outcome of -action=signal-batch
+type signalBatchSummary struct {
+	Query     string          `json:"query"`
+	Signal    string          `json:"signal"`
+	DryRun    bool            `json:"dry_run,omitempty"`
+	Matched   int             `json:"matched"`
+	Signaled  int             `json:"signaled"`
+	Failed    int             `json:"failed"`
+	Workflows []string        `json:"workflows,omitempty"`
+	Failures  []signalFailure `json:"failures,omitempty"`
+}
+
+func (s signalBatchSummary) table(w io.Writer) {
+	if s.DryRun {
+		fmt.Fprintf(w, "Would send %s to %d workflows matching %s\n", s.Signal, s.Matched, s.Query)
+		for _, workflowID := range s.Workflows {
+			fmt.Fprintf(w, "  %s\n", workflowID)
+		}
+		return
+	}
+	fmt.Fprintf(w, "Sent %s to %d of %d workflows (%d failed)\n", s.Signal, s.Signaled, s.Matched, s.Failed)
+	for _, failure := range s.Failures {
+		fmt.Fprintf(w, "  %s:\t%s\n", failure.WorkflowID, failure.Error)
+	}
+}
+
+// signalBatch sends signalName to every running order workflow matching
+// filter, at most concurrency at a time and perSecondRate per second. The
+// matches are listed before any is signaled, so signals that change search
+// attributes do not shift the pages. It exits with exitFailed if any signal
+// failed.
+func signalBatch(ctx context.Context, c client.Client, filter listFilter, signalName string, concurrency int, perSecondRate float64, dryRun bool) {
+	if !slices.Contains(orderSignals, signalName) {
+		fatal("signal must be one of cancel, expedite, approve, or retry", "signal", signalName)
+	}
+	if concurrency < 1 {
+		fatal("concurrency must be at least 1", "concurrency", concurrency)
+	}
+	// Closed workflows cannot be signaled
+	if filter.Status == "" {
+		filter.Status = "running"
+	}
+	query, err := filter.query(time.Now())
+	if err != nil {
+		fatal("Invalid list filter", "error", err)
+	}
+
+	var executions []*commonpb.WorkflowExecution
+	var token []byte
+	for {
+		resp, err := c.ListWorkflow(ctx, &workflowservice.ListWorkflowExecutionsRequest{
+			PageSize:      1000,
+			NextPageToken: token,
+			Query:         query,
+		})
+		if err != nil {
+			fatal("Unable to list workflows", "query", query, "error", err)
+		}
+		for _, info := range resp.GetExecutions() {
+			executions = append(executions, info.GetExecution())
+		}
+		if token = resp.GetNextPageToken(); len(token) == 0 {
+			break
+		}
+	}
+
+	summary := signalBatchSummary{Query: query, Signal: signalName, DryRun: dryRun, Matched: len(executions)}
+	if dryRun {
+		for _, execution := range executions {
+			summary.Workflows = append(summary.Workflows, execution.GetWorkflowId())
+		}
+		out.print(summary, summary.table)
+		return
+	}
+
+	limit := rate.Inf
+	if perSecondRate > 0 {
+		limit = rate.Limit(perSecondRate)
+	}
+	limiter := rate.NewLimiter(limit, 1)
+	progressEvery := max(len(executions)/10, 1)
+
+	slog.Info("Signaling workflows", "signal", signalName, "workflows", len(executions), "query", query)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for _, execution := range executions {
+		if err := limiter.Wait(ctx); err != nil {
+			fatal("Batch interrupted", "error", err)
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(execution *commonpb.WorkflowExecution) {
+			defer func() { <-sem; wg.Done() }()
+			// Signal the listed run, not a newer run that reused the ID
+			err := c.SignalWorkflow(ctx, execution.GetWorkflowId(), execution.GetRunId(), signalName, nil)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				summary.Failed++
+				summary.Failures = append(summary.Failures, signalFailure{WorkflowID: execution.GetWorkflowId(), Error: err.Error()})
+				slog.Warn("Unable to signal workflow", "workflow_id", execution.GetWorkflowId(), "error", err)
+			} else {
+				summary.Signaled++
+			}
+			if done := summary.Signaled + summary.Failed; done%progressEvery == 0 || done == summary.Matched {
+				slog.Info("Signal progress", "done", done, "total", summary.Matched, "failed", summary.Failed)
+			}
+		}(execution)
+	}
+	wg.Wait()
+
+	out.print(summary, summary.table)
+	if summary.Failed > 0 {
+		os.Exit(exitFailed)
+	}
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/temporal"
+)
+
+func startWorkflow(ctx context.Context, c client.Client, order models.Order, opts startOptions) client.WorkflowRun {
+	// Start workflow
+	we, err := executeOrder(ctx, c, order, opts)
+	var alreadyStarted *serviceerror.WorkflowExecutionAlreadyStarted
+	if errors.As(err, &alreadyStarted) {
+		fatal("A workflow already exists for this order ID; see -id-conflict-policy for running workflows and -id-reuse-policy for closed ones",
+			"order_id", order.ID, "run_id", alreadyStarted.RunId)
+	}
+	if err != nil {
+		fatal("Unable to execute workflow", "error", err)
+	}
+
+	if opts.StartDelay > 0 {
+		slog.Info("Order queued; processing begins after the start delay",
+			"start_delay", opts.StartDelay, "starts_at", time.Now().Add(opts.StartDelay).Format(time.RFC3339))
+	}
+	slog.Info("Started workflow successfully",
+		"workflow_id", we.GetID(),
+		"run_id", we.GetRunID(),
+		"order_id", order.ID,
+		"amount", order.Amount,
+		"items", order.Items)
+	slog.Info("To query the workflow status, run",
+		"command", fmt.Sprintf("go run ./starter -action=query -workflow-id=%s", we.GetID()))
+	slog.Info("To expedite the order, run",
+		"command", fmt.Sprintf("go run ./starter -action=expedite -workflow-id=%s", we.GetID()))
+	slog.Info("To cancel the order, run",
+		"command", fmt.Sprintf("go run ./starter -action=cancel -workflow-id=%s", we.GetID()))
+	return we
+}
+
+// executeOrder starts the order workflow for order
+func executeOrder(ctx context.Context, c client.Client, order models.Order, opts startOptions) (client.WorkflowRun, error) {
+	workflowOptions := client.StartWorkflowOptions{
+		ID:                       models.OrderWorkflowID(order.ID),
+		TaskQueue:                taskQueue,
+		Memo:                     opts.Memo,
+		TypedSearchAttributes:    opts.SearchAttributes,
+		WorkflowIDReusePolicy:    opts.ReusePolicy,
+		WorkflowIDConflictPolicy: opts.ConflictPolicy,
+		StartDelay:               opts.StartDelay,
+		// Without this a duplicate order ID silently returns the running workflow
+		WorkflowExecutionErrorWhenAlreadyStarted: opts.ConflictPolicy == enumspb.WORKFLOW_ID_CONFLICT_POLICY_FAIL,
+	}
+	return c.ExecuteWorkflow(ctx, workflowOptions, workflows.OrderWorkflow, order)
+}
+
+// startOptions are the workflow start settings shared by start and start-batch
+type startOptions struct {
+	Memo             map[string]any
+	SearchAttributes temporal.SearchAttributes
+	ReusePolicy      enumspb.WorkflowIdReusePolicy
+	ConflictPolicy   enumspb.WorkflowIdConflictPolicy
+	// StartDelay holds the workflow's first task back, so an order can be
+	// queued now and processed later
+	StartDelay time.Duration
+}
+
+// startDelayUntil returns the start delay from -start-delay or -start-at,
+// which must be in the future
+func startDelayUntil(delay time.Duration, startAt string, now time.Time) (time.Duration, error) {
+	if startAt == "" {
+		if delay < 0 {
+			return 0, fmt.Errorf("start delay %s is negative", delay)
+		}
+		return delay, nil
+	}
+	if delay != 0 {
+		return 0, errors.New("-start-delay and -start-at cannot both be set")
+	}
+	at, err := time.Parse(time.RFC3339, startAt)
+	if err != nil {
+		return 0, fmt.Errorf("invalid start time: %w", err)
+	}
+	if !at.After(now) {
+		return 0, fmt.Errorf("start time %s is not in the future", startAt)
+	}
+	return at.Sub(now), nil
+}
+
+// Flag values of -id-reuse-policy and -id-conflict-policy
+var (
+	reusePolicies = map[string]enumspb.WorkflowIdReusePolicy{
+		"allow-duplicate":             enumspb.WORKFLOW_ID_REUSE_POLICY_ALLOW_DUPLICATE,
+		"allow-duplicate-failed-only": enumspb.WORKFLOW_ID_REUSE_POLICY_ALLOW_DUPLICATE_FAILED_ONLY,
+		"reject-duplicate":            enumspb.WORKFLOW_ID_REUSE_POLICY_REJECT_DUPLICATE,
+	}
+	conflictPolicies = map[string]enumspb.WorkflowIdConflictPolicy{
+		"fail":               enumspb.WORKFLOW_ID_CONFLICT_POLICY_FAIL,
+		"use-existing":       enumspb.WORKFLOW_ID_CONFLICT_POLICY_USE_EXISTING,
+		"terminate-existing": enumspb.WORKFLOW_ID_CONFLICT_POLICY_TERMINATE_EXISTING,
+	}
+)
+
+// newStartOptions parses the start flags
+func newStartOptions(memo, searchAttrs []string, reusePolicy, conflictPolicy string) (startOptions, error) {
+	var opts startOptions
+	if reusePolicy != "" {
+		policy, ok := reusePolicies[reusePolicy]
+		if !ok {
+			return opts, fmt.Errorf("unknown ID reuse policy %q", reusePolicy)
+		}
+		opts.ReusePolicy = policy
+	}
+	policy, ok := conflictPolicies[conflictPolicy]
+	if !ok {
+		return opts, fmt.Errorf("unknown ID conflict policy %q", conflictPolicy)
+	}
+	opts.ConflictPolicy = policy
+
+	for _, entry := range memo {
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok || key == "" {
+			return opts, fmt.Errorf("memo %q is not key=value", entry)
+		}
+		if opts.Memo == nil {
+			opts.Memo = make(map[string]any)
+		}
+		var decoded any
+		if err := json.Unmarshal([]byte(value), &decoded); err == nil {
+			opts.Memo[key] = decoded
+		} else {
+			opts.Memo[key] = value
+		}
+	}
+
+	updates := make([]temporal.SearchAttributeUpdate, 0, len(searchAttrs))
+	for _, entry := range searchAttrs {
+		update, err := parseSearchAttribute(entry)
+		if err != nil {
+			return opts, err
+		}
+		updates = append(updates, update)
+	}
+	opts.SearchAttributes = temporal.NewSearchAttributes(updates...)
+	return opts, nil
+}
+
+// parseSearchAttribute parses a -search-attr value, name:type=value
+func parseSearchAttribute(entry string) (temporal.SearchAttributeUpdate, error) {
+	key, value, ok := strings.Cut(entry, "=")
+	name, kind, typed := strings.Cut(key, ":")
+	if !ok || !typed || name == "" {
+		return nil, fmt.Errorf("search attribute %q is not name:type=value", entry)
+	}
+
+	var err error
+	switch strings.ToLower(kind) {
+	case "keyword":
+		return temporal.NewSearchAttributeKeyKeyword(name).ValueSet(value), nil
+	case "text":
+		return temporal.NewSearchAttributeKeyString(name).ValueSet(value), nil
+	case "int":
+		var n int64
+		if n, err = strconv.ParseInt(value, 10, 64); err == nil {
+			return temporal.NewSearchAttributeKeyInt64(name).ValueSet(n), nil
+		}
+	case "double":
+		var f float64
+		if f, err = strconv.ParseFloat(value, 64); err == nil {
+			return temporal.NewSearchAttributeKeyFloat64(name).ValueSet(f), nil
+		}
+	case "bool":
+		var b bool
+		if b, err = strconv.ParseBool(value); err == nil {
+			return temporal.NewSearchAttributeKeyBool(name).ValueSet(b), nil
+		}
+	case "datetime":
+		var t time.Time
+		if t, err = time.Parse(time.RFC3339, value); err == nil {
+			return temporal.NewSearchAttributeKeyTime(name).ValueSet(t), nil
+		}
+	case "keywordlist":
+		return temporal.NewSearchAttributeKeyKeywordList(name).ValueSet(strings.Split(value, ",")), nil
+	default:
+		return nil, fmt.Errorf("search attribute %q has unknown type %q", name, kind)
+	}
+	return nil, fmt.Errorf("search attribute %q: invalid %s value: %w", name, kind, err)
+}
+
+// keyValues collects the values of a repeatable flag
+type keyValues []string
+
+func (k *keyValues) String() string { return strings.Join(*k, ",") }
+
+func (k *keyValues) Set(value string) error {
+	*k = append(*k, value)
+	return nil
+}
+
+// startResult is the result of -action=start
+type startResult struct {
+	WorkflowID  string              `json:"workflow_id"`
+	RunID       string              `json:"run_id"`
+	OrderID     string              `json:"order_id"`
+	Result      *models.OrderResult `json:"result,omitempty"`
+	Error       *models.OrderError  `json:"error,omitempty"`
+	FinalStatus *models.OrderStatus `json:"final_status,omitempty"`
+}
+
+func (r startResult) table(w io.Writer) {
+	fmt.Fprintf(w, "Workflow ID:\t%s\n", r.WorkflowID)
+	fmt.Fprintf(w, "Run ID:\t%s\n", r.RunID)
+	fmt.Fprintf(w, "Order ID:\t%s\n", r.OrderID)
+	if r.Result != nil {
+		fmt.Fprintf(w, "Result:\t%s in %s\n", r.Result.Status, r.Result.Duration.Round(time.Millisecond))
+		fmt.Fprintf(w, "Transaction ID:\t%s\n", orDash(r.Result.TransactionID))
+		fmt.Fprintf(w, "Shipment ID:\t%s\n", orDash(r.Result.ShipmentID))
+	}
+	if r.Error != nil {
+		fmt.Fprintf(w, "Error:\t%s\n", r.Error.Code)
+		fmt.Fprintf(w, "Failed in:\t%s\n", orDash(string(r.Error.Stage)))
+		fmt.Fprintf(w, "Retryable:\t%t\n", r.Error.Retryable)
+		fmt.Fprintf(w, "Message:\t%s\n", r.Error.Message)
+	}
+	if r.FinalStatus != nil {
+		fmt.Fprintln(w)
+		statusTable(w, *r.FinalStatus)
+	}
+}
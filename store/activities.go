@@ -0,0 +1,81 @@
+package store
+
+import (
+	"context"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/activity"
+)
+
+// StoreActivities contains activities that mirror order state into the database
+type StoreActivities struct {
+	Repository OrderRepository
+}
+
+// NewStoreActivities creates a new instance of StoreActivities
+func NewStoreActivities(repo OrderRepository) *StoreActivities {
+	if repo == nil {
+		repo = NoopRepository{}
+	}
+	return &StoreActivities{
+		Repository: repo,
+	}
+}
+
+// PersistOrder writes the order to the database
+func (a *StoreActivities) PersistOrder(ctx context.Context, order models.Order) error {
+	if activity.IsActivity(ctx) {
+		logger := activity.GetLogger(ctx)
+		logger.Info("Persisting order", "order_id", order.ID)
+	}
+	return a.Repository.SaveOrder(ctx, order)
+}
+
+// UpdateOrderStatus records the latest workflow status in the database
+func (a *StoreActivities) UpdateOrderStatus(ctx context.Context, status models.OrderStatus) error {
+	if activity.IsActivity(ctx) {
+		logger := activity.GetLogger(ctx)
+		logger.Info("Updating order status", "order_id", status.OrderID, "status", status.Status, "stage", status.Stage)
+	}
+	return a.Repository.UpdateStatus(ctx, status)
+}
+
+// RecordOrderFailure stores a dead-lettered order failure in the database
+func (a *StoreActivities) RecordOrderFailure(ctx context.Context, failure models.OrderFailure) error {
+	if activity.IsActivity(ctx) {
+		logger := activity.GetLogger(ctx)
+		logger.Info("Recording order failure", "order_id", failure.Order.ID, "stage", failure.Stage)
+	}
+	return a.Repository.RecordFailure(ctx, failure)
+}
+
+// ListCompletedOrders loads the orders completed in a reconciliation window
+func (a *StoreActivities) ListCompletedOrders(ctx context.Context, window models.ReconciliationWindow) ([]models.CompletedOrder, error) {
+	records, err := a.Repository.ListCompletedOrders(ctx, window.From, window.To)
+	if err != nil {
+		return nil, err
+	}
+	if activity.IsActivity(ctx) {
+		logger := activity.GetLogger(ctx)
+		logger.Info("Listed completed orders", "from", window.From, "to", window.To, "orders", len(records))
+	}
+
+	orders := make([]models.CompletedOrder, 0, len(records))
+	for _, record := range records {
+		orders = append(orders, models.CompletedOrder{
+			OrderID:     record.ID,
+			Amount:      record.Amount,
+			CompletedAt: record.UpdatedAt,
+		})
+	}
+	return orders, nil
+}
+
+// RecordSettlement records the settlement orders were paid out in
+func (a *StoreActivities) RecordSettlement(ctx context.Context, settlement models.OrderSettlement) error {
+	if activity.IsActivity(ctx) {
+		logger := activity.GetLogger(ctx)
+		logger.Info("Recording settlement", "settlement_id", settlement.SettlementID, "orders", len(settlement.OrderIDs))
+	}
+	return a.Repository.RecordSettlement(ctx, settlement)
+}
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS orders (
+    id             TEXT PRIMARY KEY,
+    items          JSONB          NOT NULL DEFAULT '[]',
+    amount         NUMERIC(12, 2) NOT NULL,
+    status         TEXT           NOT NULL,
+    stage          TEXT           NOT NULL DEFAULT '',
+    payment_status TEXT           NOT NULL DEFAULT '',
+    is_expedited   BOOLEAN        NOT NULL DEFAULT FALSE,
+    created_at     TIMESTAMPTZ    NOT NULL,
+    updated_at     TIMESTAMPTZ    NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_orders_status ON orders (status);
+CREATE INDEX IF NOT EXISTS idx_orders_updated_at ON orders (updated_at);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS failed_orders (
+    run_id      TEXT PRIMARY KEY,
+    order_id    TEXT        NOT NULL,
+    workflow_id TEXT        NOT NULL,
+    stage       TEXT        NOT NULL,
+    reason      TEXT        NOT NULL,
+    failed_at   TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_failed_orders_order_id ON failed_orders (order_id);
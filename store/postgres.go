@@ -0,0 +1,260 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	_ "github.com/jackc/pgx/v5/stdlib" // registers the "pgx" database/sql driver
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// PoolConfig configures the database/sql connection pool
+type PoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+// DefaultPoolConfig returns pool settings suitable for a single worker process
+func DefaultPoolConfig() PoolConfig {
+	return PoolConfig{
+		MaxOpenConns:    10,
+		MaxIdleConns:    5,
+		ConnMaxLifetime: 30 * time.Minute,
+		ConnMaxIdleTime: 5 * time.Minute,
+	}
+}
+
+// Open opens a pooled Postgres connection and verifies it is reachable
+func Open(ctx context.Context, dsn string, pool PoolConfig) (*sql.DB, error) {
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	db.SetMaxOpenConns(pool.MaxOpenConns)
+	db.SetMaxIdleConns(pool.MaxIdleConns)
+	db.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(pool.ConnMaxIdleTime)
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	return db, nil
+}
+
+// Migrate applies any embedded migrations that have not been applied yet.
+// Migrations run in file-name order, each inside its own transaction.
+func Migrate(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    TEXT PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	names, err := fs.Glob(migrationFiles, "migrations/*.sql")
+	if err != nil {
+		return fmt.Errorf("failed to list migrations: %w", err)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		version := strings.TrimSuffix(strings.TrimPrefix(name, "migrations/"), ".sql")
+
+		var exists bool
+		err := db.QueryRowContext(ctx,
+			`SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)`, version).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("failed to check migration %s: %w", version, err)
+		}
+		if exists {
+			continue
+		}
+
+		script, err := migrationFiles.ReadFile(name)
+		if err != nil {
+			return fmt.Errorf("failed to read migration %s: %w", version, err)
+		}
+
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to begin migration %s: %w", version, err)
+		}
+		if _, err := tx.ExecContext(ctx, string(script)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to apply migration %s: %w", version, err)
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, version); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to record migration %s: %w", version, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit migration %s: %w", version, err)
+		}
+	}
+	return nil
+}
+
+// PostgresRepository is an OrderRepository backed by Postgres
+type PostgresRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRepository creates a repository using an open connection pool
+func NewPostgresRepository(db *sql.DB) *PostgresRepository {
+	return &PostgresRepository{db: db}
+}
+
+// SaveOrder inserts the order, or refreshes its contents if it already exists.
+// Status columns are left untouched on conflict so a retried insert cannot
+// roll back progress recorded by UpdateStatus.
+func (r *PostgresRepository) SaveOrder(ctx context.Context, order models.Order) error {
+	items, err := json.Marshal(order.Items)
+	if err != nil {
+		return fmt.Errorf("failed to marshal order items: %w", err)
+	}
+
+	createdAt := order.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO orders (id, items, amount, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $5)
+		ON CONFLICT (id) DO UPDATE
+		SET items = EXCLUDED.items, amount = EXCLUDED.amount`,
+		order.ID, items, order.Amount, order.Status, createdAt)
+	if err != nil {
+		return fmt.Errorf("failed to save order %s: %w", order.ID, err)
+	}
+	return nil
+}
+
+// UpdateStatus records the latest workflow status for an order. Updates older
+// than the stored row are ignored so out-of-order activity retries are harmless.
+func (r *PostgresRepository) UpdateStatus(ctx context.Context, status models.OrderStatus) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE orders
+		SET status = $2, stage = $3, payment_status = $4, is_expedited = $5, updated_at = $6
+		WHERE id = $1 AND updated_at <= $6`,
+		status.OrderID, status.Status, status.Stage, status.PaymentStatus, status.IsExpedited, status.LastUpdated)
+	if err != nil {
+		return fmt.Errorf("failed to update status for order %s: %w", status.OrderID, err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read update result for order %s: %w", status.OrderID, err)
+	}
+	if rows == 0 {
+		var exists bool
+		err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM orders WHERE id = $1)`, status.OrderID).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("failed to look up order %s: %w", status.OrderID, err)
+		}
+		if !exists {
+			return fmt.Errorf("order %s: %w", status.OrderID, ErrOrderNotFound)
+		}
+	}
+	return nil
+}
+
+// GetOrder loads the stored record for an order
+func (r *PostgresRepository) GetOrder(ctx context.Context, orderID string) (*OrderRecord, error) {
+	var (
+		record OrderRecord
+		items  []byte
+	)
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, items, amount, status, stage, payment_status, is_expedited, settlement_id, created_at, updated_at
+		FROM orders WHERE id = $1`, orderID).Scan(
+		&record.ID, &items, &record.Amount, &record.Status, &record.Stage,
+		&record.PaymentStatus, &record.IsExpedited, &record.SettlementID, &record.CreatedAt, &record.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("order %s: %w", orderID, ErrOrderNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load order %s: %w", orderID, err)
+	}
+
+	if err := json.Unmarshal(items, &record.Items); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal items for order %s: %w", orderID, err)
+	}
+	return &record, nil
+}
+
+// ListCompletedOrders loads the orders completed from from up to to, by the
+// time of their last status update, sorted by ID
+func (r *PostgresRepository) ListCompletedOrders(ctx context.Context, from, to time.Time) ([]OrderRecord, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, items, amount, status, stage, payment_status, is_expedited, settlement_id, created_at, updated_at
+		FROM orders WHERE status = $1 AND updated_at >= $2 AND updated_at < $3
+		ORDER BY id`, models.StatusCompleted, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list completed orders: %w", err)
+	}
+	defer rows.Close()
+
+	var records []OrderRecord
+	for rows.Next() {
+		var (
+			record OrderRecord
+			items  []byte
+		)
+		err := rows.Scan(&record.ID, &items, &record.Amount, &record.Status, &record.Stage,
+			&record.PaymentStatus, &record.IsExpedited, &record.SettlementID, &record.CreatedAt, &record.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read completed order: %w", err)
+		}
+		if err := json.Unmarshal(items, &record.Items); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal items for order %s: %w", record.ID, err)
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list completed orders: %w", err)
+	}
+	return records, nil
+}
+
+// RecordSettlement records the settlement orders were paid out in.
+// Recording it again is a no-op.
+func (r *PostgresRepository) RecordSettlement(ctx context.Context, settlement models.OrderSettlement) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE orders SET settlement_id = $1 WHERE id = ANY($2)`,
+		settlement.SettlementID, settlement.OrderIDs)
+	if err != nil {
+		return fmt.Errorf("failed to record settlement %s: %w", settlement.SettlementID, err)
+	}
+	return nil
+}
+
+// RecordFailure stores a dead-lettered order failure. Recording the same
+// failed run twice is a no-op.
+func (r *PostgresRepository) RecordFailure(ctx context.Context, failure models.OrderFailure) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO failed_orders (run_id, order_id, workflow_id, stage, reason, failed_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (run_id) DO NOTHING`,
+		failure.RunID, failure.Order.ID, failure.WorkflowID, failure.Stage, failure.Reason, failure.FailedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record failure for order %s: %w", failure.Order.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+)
+
+// ErrOrderNotFound is returned when an order does not exist in the store
+var ErrOrderNotFound = errors.New("order not found")
+
+// OrderRecord is the persisted view of an order and its latest status
+type OrderRecord struct {
+	ID            string                 `json:"id"`
+	Items         []string               `json:"items"`
+	Amount        float64                `json:"amount"`
+	Status        models.OrderStatusCode `json:"status"`
+	Stage         models.OrderStage      `json:"stage"`
+	PaymentStatus string                 `json:"payment_status"`
+	IsExpedited   bool                   `json:"is_expedited"`
+	SettlementID  string                 `json:"settlement_id,omitempty"`
+	CreatedAt     time.Time              `json:"created_at"`
+	UpdatedAt     time.Time              `json:"updated_at"`
+}
+
+// OrderRepository mirrors order state outside of workflow history
+type OrderRepository interface {
+	SaveOrder(ctx context.Context, order models.Order) error
+	UpdateStatus(ctx context.Context, status models.OrderStatus) error
+	GetOrder(ctx context.Context, orderID string) (*OrderRecord, error)
+	RecordFailure(ctx context.Context, failure models.OrderFailure) error
+	ListCompletedOrders(ctx context.Context, from, to time.Time) ([]OrderRecord, error)
+	RecordSettlement(ctx context.Context, settlement models.OrderSettlement) error
+}
+
+// NoopRepository discards writes. It is used when no database is configured
+// so that workflows can always schedule the persistence activities.
+type NoopRepository struct{}
+
+// SaveOrder discards the order
+func (NoopRepository) SaveOrder(ctx context.Context, order models.Order) error {
+	return nil
+}
+
+// UpdateStatus discards the status
+func (NoopRepository) UpdateStatus(ctx context.Context, status models.OrderStatus) error {
+	return nil
+}
+
+// GetOrder always reports the order as missing
+func (NoopRepository) GetOrder(ctx context.Context, orderID string) (*OrderRecord, error) {
+	return nil, ErrOrderNotFound
+}
+
+// RecordFailure discards the failure
+func (NoopRepository) RecordFailure(ctx context.Context, failure models.OrderFailure) error {
+	return nil
+}
+
+// ListCompletedOrders always reports no orders
+func (NoopRepository) ListCompletedOrders(ctx context.Context, from, to time.Time) ([]OrderRecord, error) {
+	return nil, nil
+}
+
+// RecordSettlement discards the settlement
+func (NoopRepository) RecordSettlement(ctx context.Context, settlement models.OrderSettlement) error {
+	return nil
+}
@@ -0,0 +1,181 @@
+// Package temporalauth authenticates the Temporal client to a self-hosted
+// frontend protected by an authorizer plugin, by sending a JWT bearer token
+// in the authorization header of every request.
+package temporalauth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// DefaultRefreshBefore is how long before a token expires it is replaced
+const DefaultRefreshBefore = time.Minute
+
+// fileRereadInterval is how often a token file is read again when its JWT
+// has no exp claim, so rotated files are still picked up
+const fileRereadInterval = time.Minute
+
+// Config selects where tokens come from: a static JWT, a JWT file that is
+// re-read as the token nears expiry (such as a Kubernetes projected service
+// account token), or the OAuth2 client-credentials flow. Set one of them.
+type Config struct {
+	Token     string
+	TokenFile string
+
+	OAuth2TokenURL     string
+	OAuth2ClientID     string
+	OAuth2ClientSecret string
+	OAuth2Scopes       []string
+	// OAuth2Audience is sent as the audience parameter, which providers such
+	// as Auth0 need to issue a JWT for the Temporal frontend
+	OAuth2Audience string
+
+	// RefreshBefore is how long before expiry a token is replaced; zero uses
+	// DefaultRefreshBefore
+	RefreshBefore time.Duration
+}
+
+// Enabled reports whether any token source is configured
+func (c Config) Enabled() bool {
+	return c.Token != "" || c.TokenFile != "" || c.hasOAuth2()
+}
+
+func (c Config) hasOAuth2() bool {
+	return c.OAuth2TokenURL != "" || c.OAuth2ClientID != "" || c.OAuth2ClientSecret != ""
+}
+
+// Validate reports settings that cannot work together
+func (c Config) Validate() error {
+	sources := 0
+	for _, set := range []bool{c.Token != "", c.TokenFile != "", c.hasOAuth2()} {
+		if set {
+			sources++
+		}
+	}
+	if sources > 1 {
+		return errors.New("configure one of a token, a token file, or OAuth2 client credentials")
+	}
+	if c.hasOAuth2() && (c.OAuth2TokenURL == "" || c.OAuth2ClientID == "" || c.OAuth2ClientSecret == "") {
+		return errors.New("OAuth2 token URL, client ID, and client secret are all required")
+	}
+	if c.RefreshBefore < 0 {
+		return errors.New("refresh_before must not be negative")
+	}
+	return nil
+}
+
+// HeadersProvider implements client.HeadersProvider with bearer tokens.
+// Tokens are cached and replaced RefreshBefore their expiry, so most calls
+// do not read the file or hit the token endpoint.
+type HeadersProvider struct {
+	tokens oauth2.TokenSource
+}
+
+// New creates a headers provider from the configuration. It returns nil when
+// no token source is configured. The HTTP client, if not nil, is used for
+// OAuth2 token requests.
+func New(config Config, httpClient *http.Client) (*HeadersProvider, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	refreshBefore := config.RefreshBefore
+	if refreshBefore == 0 {
+		refreshBefore = DefaultRefreshBefore
+	}
+
+	var source oauth2.TokenSource
+	switch {
+	case config.Token != "":
+		return &HeadersProvider{tokens: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: config.Token})}, nil
+	case config.TokenFile != "":
+		source = fileTokenSource{path: config.TokenFile, validFor: fileRereadInterval + refreshBefore}
+	case config.hasOAuth2():
+		credentials := clientcredentials.Config{
+			ClientID:     config.OAuth2ClientID,
+			ClientSecret: config.OAuth2ClientSecret,
+			TokenURL:     config.OAuth2TokenURL,
+			Scopes:       config.OAuth2Scopes,
+		}
+		if config.OAuth2Audience != "" {
+			credentials.EndpointParams = url.Values{"audience": {config.OAuth2Audience}}
+		}
+		ctx := context.Background()
+		if httpClient != nil {
+			ctx = context.WithValue(ctx, oauth2.HTTPClient, httpClient)
+		}
+		source = credentials.TokenSource(ctx)
+	default:
+		return nil, nil
+	}
+	return &HeadersProvider{tokens: oauth2.ReuseTokenSourceWithExpiry(nil, source, refreshBefore)}, nil
+}
+
+// GetHeaders implements client.HeadersProvider
+func (p *HeadersProvider) GetHeaders(ctx context.Context) (map[string]string, error) {
+	token, err := p.tokens.Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain Temporal auth token: %w", err)
+	}
+	return map[string]string{"authorization": "Bearer " + token.AccessToken}, nil
+}
+
+// fileTokenSource reads a JWT from a file, taking its expiry from the exp
+// claim; a JWT without one is treated as expiring after validFor
+type fileTokenSource struct {
+	path     string
+	validFor time.Duration
+}
+
+// Token implements oauth2.TokenSource
+func (s fileTokenSource) Token() (*oauth2.Token, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token file: %w", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return nil, fmt.Errorf("token file %s is empty", s.path)
+	}
+	expiry, err := jwtExpiry(token)
+	if err != nil {
+		return nil, fmt.Errorf("token file %s: %w", s.path, err)
+	}
+	if expiry.IsZero() {
+		expiry = time.Now().Add(s.validFor)
+	}
+	return &oauth2.Token{AccessToken: token, Expiry: expiry}, nil
+}
+
+// jwtExpiry returns the exp claim of a JWT, or the zero time if it has none.
+// The signature is not checked; the Temporal frontend verifies it.
+func jwtExpiry(token string) (time.Time, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, errors.New("token is not a JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("JWT payload is not base64url: %w", err)
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return time.Time{}, fmt.Errorf("JWT payload is not JSON: %w", err)
+	}
+	if claims.Exp == 0 {
+		return time.Time{}, nil
+	}
+	return time.Unix(claims.Exp, 0), nil
+}
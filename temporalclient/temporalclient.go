@@ -0,0 +1,106 @@
+// Package temporalclient builds the Temporal client every process connects
+// with, from the connection, payload codec, and auth settings of the config,
+// so the worker, starter, and intake processes cannot drift apart.
+package temporalclient
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/authz"
+	"github.com/aswathylr-builds/temporal-order-processing/config"
+	"github.com/aswathylr-builds/temporal-order-processing/correlation"
+	"github.com/aswathylr-builds/temporal-order-processing/interceptors"
+	"github.com/aswathylr-builds/temporal-order-processing/logging"
+	"github.com/aswathylr-builds/temporal-order-processing/temporalauth"
+	"github.com/aswathylr-builds/temporal-order-processing/tlsconfig"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/workflow"
+)
+
+// NewOptions returns the client options of cfg that need no connection: the
+// host, logger, context propagators, payload guard, and payload codecs. It
+// fetches an encryption key now so a misconfigured key store fails at
+// startup.
+func NewOptions(cfg config.Config, logger *slog.Logger) (client.Options, error) {
+	options := client.Options{
+		HostPort: cfg.TemporalHostPort(),
+		Logger:   logging.NewTemporalLogger(logger),
+		// Carry correlation and tenant IDs into activities, and the caller's
+		// auth token, if its context has one, into signals and queries
+		ContextPropagators: []workflow.ContextPropagator{correlation.NewPropagator(), authz.NewTokenPropagator()},
+		// Reject oversized payloads with a clear error before they reach the
+		// server; workers created from the client enforce it too
+		Interceptors: []interceptor.ClientInterceptor{interceptors.NewPayloadGuard(cfg.Payload.MaxBytes)},
+	}
+
+	keyCtx, keyCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	dataConverter, err := cfg.DataConverter(keyCtx)
+	keyCancel()
+	if err != nil {
+		return client.Options{}, fmt.Errorf("failed to set up payload codecs: %w", err)
+	}
+	options.DataConverter = dataConverter
+	options.FailureConverter = cfg.FailureConverter(dataConverter)
+	return options, nil
+}
+
+// Connect adds the connection settings of cfg to options and dials: TLS, with
+// a client certificate for mTLS, the Temporal Cloud API key, and JWT bearer
+// tokens, whose first token is fetched now so bad credentials fail at
+// startup. The returned reloader, nil without TLS, reloads rotated
+// certificates.
+func Connect(cfg config.Config, options *client.Options) (client.Client, *tlsconfig.Reloader, error) {
+	var reloader *tlsconfig.Reloader
+	if tlsConfig := cfg.TLSConfig(); tlsConfig.Enabled() {
+		var err error
+		if reloader, err = tlsconfig.NewReloader(tlsConfig); err != nil {
+			return nil, nil, fmt.Errorf("invalid Temporal TLS configuration: %w", err)
+		}
+		if options.ConnectionOptions.TLS, err = reloader.TLSConfig(); err != nil {
+			return nil, nil, fmt.Errorf("invalid Temporal TLS configuration: %w", err)
+		}
+		slog.Info("TLS enabled for Temporal connection", "mtls", tlsConfig.CertFile != "")
+	}
+
+	cloudConfig := cfg.CloudConfig()
+	if err := cloudConfig.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("invalid Temporal Cloud configuration: %w", err)
+	}
+	cloudConfig.Apply(options)
+
+	headers, err := temporalauth.New(cfg.TemporalAuthConfig(), nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid Temporal auth configuration: %w", err)
+	}
+	if headers != nil {
+		tokenCtx, tokenCancel := context.WithTimeout(context.Background(), 30*time.Second)
+		_, err := headers.GetHeaders(tokenCtx)
+		tokenCancel()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to obtain Temporal auth token: %w", err)
+		}
+		options.HeadersProvider = headers
+		slog.Info("Token authentication enabled for Temporal connection")
+	}
+
+	c, err := client.Dial(*options)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c, reloader, nil
+}
+
+// Dial connects to Temporal with the settings of cfg, for processes that add
+// no client options of their own
+func Dial(cfg config.Config, logger *slog.Logger) (client.Client, error) {
+	options, err := NewOptions(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	c, _, err := Connect(cfg, &options)
+	return c, err
+}
@@ -3,18 +3,24 @@ package tests
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/events"
 	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/store"
 	"github.com/aswathylr-builds/temporal-order-processing/workflows"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
 )
 
 func TestValidateOrder_Success(t *testing.T) {
@@ -131,6 +137,9 @@ func TestValidateOrder_HTTPError(t *testing.T) {
 	require.Error(t, err)
 	assert.Nil(t, resp)
 	assert.Contains(t, err.Error(), "validation service returned status 500")
+	var appErr *temporal.ApplicationError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, models.ErrTypeServiceUnreachable, appErr.Type())
 }
 
 func TestProcessOrder(t *testing.T) {
@@ -239,8 +248,12 @@ func TestOrderWorkflow(t *testing.T) {
 	orderActivities := activities.NewOrderActivities("http://mock-url")
 	env.RegisterActivity(orderActivities.ValidateOrder)
 	env.RegisterActivity(orderActivities.ProcessPayment)
-	env.RegisterActivity(orderActivities.ProcessOrder)
+	env.RegisterActivity(orderActivities.FulfillItem)
 	env.RegisterActivity(orderActivities.NotifyOrderComplete)
+	env.RegisterActivity(events.NewEventActivities(nil).PublishOrderEvent)
+	storeActivities := store.NewStoreActivities(nil)
+	env.RegisterActivity(storeActivities.PersistOrder)
+	env.RegisterActivity(storeActivities.UpdateOrderStatus)
 
 	// Mock the ValidateOrder activity
 	env.OnActivity(orderActivities.ValidateOrder, mock.Anything, mock.Anything).Return(&models.ValidationResponse{
@@ -255,8 +268,8 @@ func TestOrderWorkflow(t *testing.T) {
 		Message:       "Payment processed successfully",
 	}, nil)
 
-	// Mock the ProcessOrder activity
-	env.OnActivity(orderActivities.ProcessOrder, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	// Mock the FulfillItem activity
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	// Mock the NotifyOrderComplete activity
 	env.OnActivity(orderActivities.NotifyOrderComplete, mock.Anything, mock.Anything).Return(nil)
@@ -281,3 +294,46 @@ func TestOrderWorkflow(t *testing.T) {
 	require.True(t, env.IsWorkflowCompleted())
 	require.NoError(t, env.GetWorkflowError())
 }
+
+func TestOrderWorkflow_PaymentRunsOnPaymentTaskQueue(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env.RegisterActivity(orderActivities.ValidateOrder)
+	env.RegisterActivity(orderActivities.ProcessPayment)
+	env.RegisterActivity(orderActivities.FulfillItem)
+	env.RegisterActivity(orderActivities.NotifyOrderComplete)
+	env.RegisterActivity(events.NewEventActivities(nil).PublishOrderEvent)
+	storeActivities := store.NewStoreActivities(nil)
+	env.RegisterActivity(storeActivities.PersistOrder)
+	env.RegisterActivity(storeActivities.UpdateOrderStatus)
+	env.RegisterWorkflow(workflows.OrderWorkflow)
+	env.RegisterWorkflow(workflows.PaymentWorkflow)
+
+	env.OnActivity(orderActivities.ValidateOrder, mock.Anything, mock.Anything).Return(&models.ValidationResponse{Valid: true}, nil)
+	env.OnActivity(orderActivities.ProcessPayment, mock.Anything, mock.Anything).Return(&models.PaymentResponse{
+		Success:       true,
+		TransactionID: "TXN-TEST-456",
+	}, nil)
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	env.OnActivity(orderActivities.NotifyOrderComplete, mock.Anything, mock.Anything).Return(nil)
+
+	var paymentTaskQueue string
+	env.SetOnChildWorkflowStartedListener(func(info *workflow.Info, ctx workflow.Context, args converter.EncodedValues) {
+		if info.WorkflowType.Name == workflows.PaymentWorkflowName {
+			paymentTaskQueue = info.TaskQueueName
+		}
+	})
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:     "TEST-WF-002",
+		Items:  []string{"item1"},
+		Amount: 100.0,
+		Status: models.StatusPending,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, workflows.PaymentTaskQueue, paymentTaskQueue)
+}
@@ -0,0 +1,55 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/interceptors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+func TestActivityTracker_CountsInFlightAndCompletedActivities(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+
+	tracker := interceptors.NewActivityTracker()
+	env.SetWorkerOptions(worker.Options{
+		Interceptors: []interceptor.WorkerInterceptor{tracker},
+	})
+
+	var inFlightDuringRun int64
+	observe := func(ctx context.Context, fail bool) error {
+		inFlightDuringRun = tracker.InFlight()
+		if fail {
+			return temporal.NewNonRetryableApplicationError("boom", "", errors.New("boom"))
+		}
+		return nil
+	}
+	env.RegisterActivityWithOptions(observe, activity.RegisterOptions{Name: "Observe"})
+
+	env.ExecuteWorkflow(func(ctx workflow.Context) error {
+		ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{StartToCloseTimeout: time.Minute})
+		if err := workflow.ExecuteActivity(ctx, "Observe", false).Get(ctx, nil); err != nil {
+			return err
+		}
+		// Failed activities still count as completed
+		_ = workflow.ExecuteActivity(ctx, "Observe", true).Get(ctx, nil)
+		return nil
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, int64(1), inFlightDuringRun)
+	assert.Equal(t, int64(0), tracker.InFlight())
+	assert.Equal(t, int64(2), tracker.Completed())
+	assert.Equal(t, int64(0), tracker.Interrupted())
+}
@@ -0,0 +1,135 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/archive"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+)
+
+// recordingArchiveStore keeps every archive written to it, in order
+type recordingArchiveStore struct {
+	mu   sync.Mutex
+	keys []string
+	puts []models.OrderArchive
+}
+
+func (s *recordingArchiveStore) Put(ctx context.Context, key string, data []byte) error {
+	var record models.OrderArchive
+	if err := json.Unmarshal(data, &record); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = append(s.keys, key)
+	s.puts = append(s.puts, record)
+	return nil
+}
+
+func TestOrderWorkflow_ArchivesCompletedOrder(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newDynamicConfigTestEnv(orderActivities, models.DynamicConfig{OrderArchival: true})
+	env.SetStartTime(time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, env.SetMemoOnStart(models.OrderMemo{CustomerID: "CUST-7"}.Fields()))
+	dir := t.TempDir()
+	env.RegisterWorkflow(workflows.OrderArchiveWorkflow)
+	env.RegisterActivity(archive.NewActivities(archive.DirStore{Dir: dir}).ArchiveOrder)
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:     "TEST-ARCHIVE-001",
+		Items:  []string{"item1"},
+		Amount: 50.0,
+		Status: models.StatusPending,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	data, err := os.ReadFile(filepath.Join(dir, "orders", "2026", "10", "17", "TEST-ARCHIVE-001.json"))
+	require.NoError(t, err)
+	var record models.OrderArchive
+	require.NoError(t, json.Unmarshal(data, &record))
+	assert.Equal(t, "TEST-ARCHIVE-001", record.Order.ID)
+	assert.Equal(t, "CUST-7", record.Memo.CustomerID)
+	assert.Equal(t, models.StatusCompleted, record.Result.Status)
+	require.NotEmpty(t, record.Events)
+	assert.Equal(t, models.StatusCompleted, record.Events[len(record.Events)-1].Status)
+	assert.False(t, record.PIIPurged)
+}
+
+func TestOrderArchiveWorkflow_PurgesPIIAfterRetention(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	store := &recordingArchiveStore{}
+	env.RegisterActivity(archive.NewActivities(store).ArchiveOrder)
+	closedAt := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	env.SetStartTime(closedAt)
+
+	env.ExecuteWorkflow(workflows.OrderArchiveWorkflow, models.OrderArchiveRequest{
+		Archive: models.OrderArchive{
+			Order: models.Order{
+				ID:       "TEST-ARCHIVE-002",
+				Amount:   100,
+				Tenders:  []models.Tender{{Method: models.TenderGiftCard, Amount: 40, Card: "GC-1234"}, {Method: models.TenderCard, Amount: 60}},
+				Customer: &models.Customer{ID: "CUST-7", Email: "ada@example.com"},
+			},
+			Memo:     models.OrderMemo{CustomerID: "CUST-7", Channel: "web"},
+			ClosedAt: closedAt,
+		},
+		PIIRetention: 30 * 24 * time.Hour,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.Len(t, store.puts, 2)
+	assert.Equal(t, store.keys[0], store.keys[1], "the purged archive replaces the original")
+	assert.Equal(t, "CUST-7", store.puts[0].Memo.CustomerID)
+	purged := store.puts[1]
+	assert.True(t, purged.PIIPurged)
+	assert.Empty(t, purged.Memo.CustomerID)
+	assert.Nil(t, purged.Order.Customer)
+	assert.Equal(t, "web", purged.Memo.Channel)
+	assert.Empty(t, purged.Order.Tenders[0].Card)
+	assert.Equal(t, 40.0, purged.Order.Tenders[0].Amount)
+}
+
+type fakeS3 struct {
+	input *s3.PutObjectInput
+	body  []byte
+}
+
+func (f *fakeS3) PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	f.input = params
+	f.body, _ = io.ReadAll(params.Body)
+	return &s3.PutObjectOutput{}, nil
+}
+
+func TestS3Store_PutWritesToColdStorage(t *testing.T) {
+	client := &fakeS3{}
+	store := archive.NewS3Store(client, "order-archive", "prod/", "")
+
+	require.NoError(t, store.Put(context.Background(), "orders/2026/10/17/ORDER-1.json", []byte(`{}`)))
+
+	assert.Equal(t, "order-archive", aws.ToString(client.input.Bucket))
+	assert.Equal(t, "prod/orders/2026/10/17/ORDER-1.json", aws.ToString(client.input.Key))
+	assert.Equal(t, types.StorageClassGlacierIr, client.input.StorageClass)
+	assert.Equal(t, []byte(`{}`), client.body)
+	assert.True(t, archive.ValidStorageClass("DEEP_ARCHIVE"))
+	assert.False(t, archive.ValidStorageClass("FREEZER"))
+}
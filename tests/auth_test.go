@@ -0,0 +1,81 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateOrder_APIKey(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != "secret-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"valid":true,"message":"ok"}`))
+	}))
+	defer mockServer.Close()
+
+	orderActivities := activities.NewOrderActivities(mockServer.URL + "/validate")
+	auth, err := activities.NewRequestAuthenticator(activities.AuthConfig{APIKey: "secret-key"}, nil)
+	require.NoError(t, err)
+	orderActivities.ValidationAuth = auth
+
+	resp, err := orderActivities.ValidateOrder(context.Background(), models.Order{ID: "TEST-AUTH-001", Amount: 100.0})
+
+	require.NoError(t, err)
+	assert.True(t, resp.Valid)
+}
+
+func TestValidateOrder_OAuth2TokenIsCached(t *testing.T) {
+	var tokenRequests int32
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&tokenRequests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"token-123","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer tokenServer.Close()
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token-123" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"valid":true,"message":"ok"}`))
+	}))
+	defer mockServer.Close()
+
+	orderActivities := activities.NewOrderActivities(mockServer.URL + "/validate")
+	auth, err := activities.NewRequestAuthenticator(activities.AuthConfig{
+		OAuth2TokenURL:     tokenServer.URL,
+		OAuth2ClientID:     "order-worker",
+		OAuth2ClientSecret: "client-secret",
+	}, orderActivities.HTTPClient)
+	require.NoError(t, err)
+	orderActivities.ValidationAuth = auth
+
+	for i := 0; i < 3; i++ {
+		resp, err := orderActivities.ValidateOrder(context.Background(), models.Order{ID: "TEST-AUTH-002", Amount: 100.0})
+		require.NoError(t, err)
+		assert.True(t, resp.Valid)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&tokenRequests))
+}
+
+func TestNewRequestAuthenticator_RejectsAmbiguousConfig(t *testing.T) {
+	_, err := activities.NewRequestAuthenticator(activities.AuthConfig{
+		APIKey:         "secret-key",
+		OAuth2TokenURL: "https://auth.example.com/token",
+	}, nil)
+
+	require.Error(t, err)
+}
@@ -0,0 +1,242 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/authz"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+// signalHeaderInjector stands in for a client sending headers with its
+// signals, which the test environment cannot do. Signals that already carry
+// a token, signed by the workflow that sent them, keep it.
+type signalHeaderInjector struct {
+	interceptor.WorkerInterceptorBase
+	token string
+}
+
+func (i *signalHeaderInjector) InterceptWorkflow(ctx workflow.Context, next interceptor.WorkflowInboundInterceptor) interceptor.WorkflowInboundInterceptor {
+	w := &signalHeaderInjectorInbound{token: i.token}
+	w.Next = next
+	return w
+}
+
+type signalHeaderInjectorInbound struct {
+	interceptor.WorkflowInboundInterceptorBase
+	token string
+}
+
+func (w *signalHeaderInjectorInbound) HandleSignal(ctx workflow.Context, in *interceptor.HandleSignalInput) error {
+	if _, ok := interceptor.WorkflowHeader(ctx)[authz.HeaderKey]; ok {
+		return w.Next.HandleSignal(ctx, in)
+	}
+	payload, err := converter.GetDefaultDataConverter().ToPayload(w.token)
+	if err != nil {
+		return err
+	}
+	interceptor.WorkflowHeader(ctx)[authz.HeaderKey] = payload
+	return w.Next.HandleSignal(ctx, in)
+}
+
+// awaitCancelWorkflow reports whether a cancel signal arrived within a minute
+func awaitCancelWorkflow(ctx workflow.Context) (bool, error) {
+	received := false
+	if err := workflow.SetQueryHandler(ctx, "getStatus", func() (bool, error) {
+		return received, nil
+	}); err != nil {
+		return false, err
+	}
+	received, _ = workflow.GetSignalChannel(ctx, models.SignalCancel).ReceiveWithTimeout(ctx, time.Minute, nil)
+	return received, nil
+}
+
+func runSignalAuthWorkflow(t *testing.T, signer *authz.Signer, token string) bool {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+
+	workerInterceptors := []interceptor.WorkerInterceptor{}
+	if token != "" {
+		workerInterceptors = append(workerInterceptors, &signalHeaderInjector{token: token})
+	}
+	workerInterceptors = append(workerInterceptors, authz.NewInterceptor(authz.Config{
+		Signer:           signer,
+		ProtectedSignals: []string{models.SignalCancel},
+	}))
+	env.SetWorkerOptions(worker.Options{Interceptors: workerInterceptors})
+	env.RegisterWorkflow(awaitCancelWorkflow)
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(models.SignalCancel, nil)
+	}, time.Second)
+	env.ExecuteWorkflow(awaitCancelWorkflow)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var received bool
+	require.NoError(t, env.GetWorkflowResult(&received))
+	return received
+}
+
+func TestAuthzInterceptor_AcceptsSignedSignal(t *testing.T) {
+	signer, err := authz.NewSigner("secret")
+	require.NoError(t, err)
+	token, err := signer.Sign(authz.Claims{Subject: "ops@example.com"})
+	require.NoError(t, err)
+
+	assert.True(t, runSignalAuthWorkflow(t, signer, token))
+}
+
+func TestAuthzInterceptor_DropsUnsignedSignal(t *testing.T) {
+	signer, err := authz.NewSigner("secret")
+	require.NoError(t, err)
+
+	assert.False(t, runSignalAuthWorkflow(t, signer, ""))
+}
+
+func TestAuthzInterceptor_DropsForgedSignal(t *testing.T) {
+	signer, err := authz.NewSigner("secret")
+	require.NoError(t, err)
+	forger, err := authz.NewSigner("guessed")
+	require.NoError(t, err)
+	token, err := forger.Sign(authz.Claims{Subject: "attacker"})
+	require.NoError(t, err)
+
+	assert.False(t, runSignalAuthWorkflow(t, signer, token))
+}
+
+// notifyParentWorkflow signals a shipment update to its parent, as a
+// tracking child does to its order
+func notifyParentWorkflow(ctx workflow.Context) error {
+	parent := workflow.GetInfo(ctx).ParentWorkflowExecution
+	return workflow.SignalExternalWorkflow(ctx, parent.ID, parent.RunID, models.SignalShipmentUpdate, nil).Get(ctx, nil)
+}
+
+// awaitChildSignalWorkflow reports whether its child's shipment update
+// arrived within a minute
+func awaitChildSignalWorkflow(ctx workflow.Context) (bool, error) {
+	childCtx := workflow.WithChildOptions(ctx, workflow.ChildWorkflowOptions{})
+	if err := workflow.ExecuteChildWorkflow(childCtx, notifyParentWorkflow).Get(ctx, nil); err != nil {
+		return false, err
+	}
+	received, _ := workflow.GetSignalChannel(ctx, models.SignalShipmentUpdate).ReceiveWithTimeout(ctx, time.Minute, nil)
+	return received, nil
+}
+
+func runChildSignalWorkflow(t *testing.T, senders map[string]string) bool {
+	signer, err := authz.NewSigner("secret")
+	require.NoError(t, err)
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	env.SetWorkerOptions(worker.Options{Interceptors: []interceptor.WorkerInterceptor{
+		authz.NewInterceptor(authz.Config{
+			Signer:           signer,
+			ProtectedSignals: []string{models.SignalShipmentUpdate},
+			SignalSenders:    senders,
+		}),
+	}})
+	env.RegisterWorkflow(awaitChildSignalWorkflow)
+	env.RegisterWorkflow(notifyParentWorkflow)
+
+	env.ExecuteWorkflow(awaitChildSignalWorkflow)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var received bool
+	require.NoError(t, env.GetWorkflowResult(&received))
+	return received
+}
+
+func TestAuthzInterceptor_SignsChildSignalToParent(t *testing.T) {
+	assert.True(t, runChildSignalWorkflow(t, nil), "the child signs the protected signal it sends")
+}
+
+func TestAuthzInterceptor_AcceptsSignalFromExpectedSender(t *testing.T) {
+	senders := map[string]string{models.SignalShipmentUpdate: "notifyParentWorkflow"}
+	assert.True(t, runChildSignalWorkflow(t, senders))
+}
+
+func TestAuthzInterceptor_DropsSignalFromUnexpectedSender(t *testing.T) {
+	senders := map[string]string{models.SignalShipmentUpdate: workflows.TrackingWorkflowName}
+	assert.False(t, runChildSignalWorkflow(t, senders))
+}
+
+func TestSigner_Verify(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	oldSigner, err := authz.NewSigner("old")
+	require.NoError(t, err)
+	rotated, err := authz.NewSigner("new", "old")
+	require.NoError(t, err)
+
+	// Tokens signed with a retired key still verify while it is configured
+	token, err := oldSigner.Sign(authz.Claims{Subject: "ops", ExpiresAt: now.Add(time.Minute)})
+	require.NoError(t, err)
+	claims, err := rotated.Verify(token, now)
+	require.NoError(t, err)
+	assert.Equal(t, "ops", claims.Subject)
+
+	_, err = rotated.Verify(token, now.Add(2*time.Minute))
+	assert.ErrorIs(t, err, authz.ErrExpiredToken)
+
+	_, err = rotated.Verify("not-a-token", now)
+	assert.ErrorIs(t, err, authz.ErrInvalidToken)
+
+	_, err = rotated.Verify("", now)
+	assert.ErrorIs(t, err, authz.ErrMissingToken)
+}
+
+func TestAuthzInterceptor_RejectsUnsignedQuery(t *testing.T) {
+	signer, err := authz.NewSigner("secret")
+	require.NoError(t, err)
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	env.SetWorkerOptions(worker.Options{Interceptors: []interceptor.WorkerInterceptor{
+		authz.NewInterceptor(authz.Config{Signer: signer, ProtectQueries: true}),
+	}})
+	env.RegisterWorkflow(awaitCancelWorkflow)
+
+	env.RegisterDelayedCallback(func() {
+		_, err := env.QueryWorkflow("getStatus")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), authz.ErrMissingToken.Error())
+	}, time.Second)
+	env.ExecuteWorkflow(awaitCancelWorkflow)
+
+	require.True(t, env.IsWorkflowCompleted())
+}
+
+func TestTokenPropagator_SignsEachCall(t *testing.T) {
+	signer, err := authz.NewSigner("secret")
+	require.NoError(t, err)
+	ctx := authz.WithSigner(context.Background(), signer, "ops@example.com", time.Minute)
+	propagator := authz.NewTokenPropagator()
+
+	injectedToken := func() string {
+		header := headerMap{}
+		require.NoError(t, propagator.Inject(ctx, header))
+		var token string
+		require.NoError(t, converter.GetDefaultDataConverter().FromPayload(header[authz.HeaderKey], &token))
+		return token
+	}
+
+	// A session outlives any one token, so each call is signed as it is made
+	first := injectedToken()
+	claims, err := signer.Verify(first, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, "ops@example.com", claims.Subject)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), claims.ExpiresAt, 5*time.Second)
+
+	time.Sleep(time.Millisecond)
+	assert.NotEqual(t, first, injectedToken())
+}
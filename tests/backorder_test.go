@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+)
+
+// newBackorderTestEnv returns an environment in which item2 is out of stock
+// on its first fulfillment attempt and every later attempt succeeds
+func newBackorderTestEnv(orderActivities *activities.OrderActivities, dynamic models.DynamicConfig) *testsuite.TestWorkflowEnvironment {
+	env := newDynamicConfigTestEnv(orderActivities, dynamic)
+	env.RegisterActivity(orderActivities.CheckStock)
+	env.RegisterActivity(orderActivities.RefundPayment)
+	outOfStock := (&models.InventoryOutOfStockError{OrderID: "TEST-BACKORDER-001", Items: []string{"item2"}}).ApplicationError()
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, "item2", mock.Anything).Return(outOfStock).Once()
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	return env
+}
+
+var backorderedOrder = models.Order{
+	ID:     "TEST-BACKORDER-001",
+	Items:  []string{"item1", "item2"},
+	Amount: 50.0,
+	Status: models.StatusPending,
+}
+
+func TestOrderWorkflow_BackorderResumesOnRestockSignal(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newBackorderTestEnv(orderActivities, models.DynamicConfig{BackorderTimeout: 24 * time.Hour})
+	var backordered models.OrderStatus
+	env.RegisterDelayedCallback(func() {
+		backordered = queryStatus(t, env)
+		env.SignalWorkflow(models.SignalRestock, nil)
+	}, time.Hour)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, backorderedOrder)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, models.StatusBackordered, backordered.Status)
+	status := queryStatus(t, env)
+	assert.Equal(t, models.StatusCompleted, status.Status)
+	require.Len(t, status.ItemResults, 2)
+	assert.Equal(t, models.ItemFulfilled, status.ItemResults[0].Status)
+	assert.Equal(t, models.ItemFulfilled, status.ItemResults[1].Status)
+}
+
+func TestOrderWorkflow_BackorderResumesWhenRecheckFindsStock(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newBackorderTestEnv(orderActivities, models.DynamicConfig{BackorderTimeout: 24 * time.Hour, BackorderRecheckInterval: time.Hour})
+	env.OnActivity(orderActivities.CheckStock, mock.Anything, []string{"item2"}).Return([]string{"item2"}, nil).Once()
+	env.OnActivity(orderActivities.CheckStock, mock.Anything, []string{"item2"}).Return(nil, nil)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, backorderedOrder)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, models.StatusCompleted, queryStatus(t, env).Status)
+	env.AssertNumberOfCalls(t, "CheckStock", 2)
+}
+
+func TestOrderWorkflow_BackorderFailsAfterTimeout(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newBackorderTestEnv(orderActivities, models.DynamicConfig{BackorderTimeout: time.Hour})
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, backorderedOrder)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.Error(t, env.GetWorkflowError())
+	assert.Contains(t, env.GetWorkflowError().Error(), "items out of stock: item2")
+	assert.Equal(t, models.StatusFailed, queryStatus(t, env).Status)
+}
+
+func TestOrderWorkflow_CancelWhileBackorderedRefundsPayment(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newBackorderTestEnv(orderActivities, models.DynamicConfig{BackorderTimeout: 24 * time.Hour})
+	env.OnActivity(orderActivities.RefundPayment, mock.Anything, mock.Anything).Return(nil)
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(models.SignalCancel, nil)
+	}, time.Hour)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, backorderedOrder)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	status := queryStatus(t, env)
+	assert.Equal(t, models.StatusCancelled, status.Status)
+	assert.Equal(t, "refunded", status.PaymentStatus)
+}
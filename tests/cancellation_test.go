@@ -0,0 +1,62 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/temporal"
+)
+
+func TestOrderWorkflow_CancellationRefundsCapturedPayment(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newFulfillmentTestEnv(orderActivities)
+	env.RegisterActivity(orderActivities.RefundPayment)
+
+	var refund models.RefundRequest
+	env.OnActivity(orderActivities.RefundPayment, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { refund = args.Get(1).(models.RefundRequest) }).
+		Return(nil)
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		After(time.Hour).Return(nil)
+	env.RegisterDelayedCallback(env.CancelWorkflow, time.Minute)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:     "TEST-CANCEL-001",
+		Items:  []string{"item1"},
+		Amount: 100.0,
+		Status: models.StatusPending,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	assert.True(t, temporal.IsCanceledError(env.GetWorkflowError()), "got %v", env.GetWorkflowError())
+	assert.Equal(t, models.RefundRequest{OrderID: "TEST-CANCEL-001", TransactionID: "TXN-FULFILL-123", Amount: 100.0}, refund)
+	status := queryStatus(t, env)
+	assert.Equal(t, models.StatusCancelled, status.Status)
+	assert.Equal(t, "refunded", status.PaymentStatus)
+}
+
+func TestOrderWorkflow_CancellationBeforePaymentRefundsNothing(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newDynamicConfigTestEnv(orderActivities, models.DynamicConfig{HighValueApprovalThreshold: 5000})
+	env.RegisterActivity(orderActivities.RefundPayment)
+	env.RegisterDelayedCallback(env.CancelWorkflow, time.Hour)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:     "TEST-CANCEL-002",
+		Items:  []string{"item1"},
+		Amount: 7500.0,
+		Status: models.StatusPending,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	assert.True(t, temporal.IsCanceledError(env.GetWorkflowError()), "got %v", env.GetWorkflowError())
+	assert.Equal(t, models.StatusCancelled, queryStatus(t, env).Status)
+	env.AssertNotCalled(t, "ProcessPayment", mock.Anything, mock.Anything)
+	env.AssertNotCalled(t, "RefundPayment", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+)
+
+// newCartTestEnv returns a cart reminder environment that records the
+// reminders sent in sent
+func newCartTestEnv(sent *[]models.CartReminder) *testsuite.TestWorkflowEnvironment {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env.RegisterActivity(orderActivities.SendCartReminder)
+	env.OnActivity(orderActivities.SendCartReminder, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, reminder models.CartReminder) error {
+			*sent = append(*sent, reminder)
+			return nil
+		})
+	return env
+}
+
+var abandonedCart = models.Cart{
+	ID:        "TEST-CART-001",
+	Items:     []string{"item1", "item2"},
+	Amount:    40.0,
+	Reminders: []time.Duration{time.Hour, 24 * time.Hour},
+}
+
+func TestCartReminderWorkflow_RemindsThenExpires(t *testing.T) {
+	var sent []models.CartReminder
+	env := newCartTestEnv(&sent)
+
+	env.ExecuteWorkflow(workflows.CartReminderWorkflow, abandonedCart)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var status models.CartStatus
+	require.NoError(t, env.GetWorkflowResult(&status))
+	assert.Equal(t, models.CartExpired, status.Status)
+	assert.Equal(t, 2, status.RemindersSent)
+	require.Len(t, sent, 2)
+	assert.Equal(t, 1, sent[0].Number)
+	assert.False(t, sent[0].Final)
+	assert.Equal(t, 2, sent[1].Number)
+	assert.True(t, sent[1].Final, "the last reminder warns that the cart expires")
+}
+
+func TestCartReminderWorkflow_CheckoutStopsReminders(t *testing.T) {
+	var sent []models.CartReminder
+	env := newCartTestEnv(&sent)
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(models.SignalCheckoutCompleted, models.CheckoutCompleted{OrderID: "ORDER-CART-001"})
+	}, 2*time.Hour)
+
+	env.ExecuteWorkflow(workflows.CartReminderWorkflow, abandonedCart)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var status models.CartStatus
+	require.NoError(t, env.GetWorkflowResult(&status))
+	assert.Equal(t, models.CartCheckedOut, status.Status)
+	assert.Equal(t, "ORDER-CART-001", status.OrderID)
+	assert.Len(t, sent, 1, "only the reminder due before checkout is sent")
+}
+
+func TestCartReminderWorkflow_DefaultSchedule(t *testing.T) {
+	var sent []models.CartReminder
+	env := newCartTestEnv(&sent)
+	start := env.Now()
+
+	env.ExecuteWorkflow(workflows.CartReminderWorkflow, models.Cart{ID: "TEST-CART-002", Items: []string{"item1"}})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	assert.Len(t, sent, len(workflows.DefaultCartReminders))
+	var total time.Duration
+	for _, wait := range workflows.DefaultCartReminders {
+		total += wait
+	}
+	assert.Equal(t, total+workflows.DefaultCartExpireAfter, env.Now().Sub(start), "the cart expires after the default schedule")
+}
@@ -0,0 +1,111 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/events"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/store"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+)
+
+func TestValidateOrder_CircuitBreakerOpensAndRecovers(t *testing.T) {
+	var calls int32
+	var healthy atomic.Bool
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"valid":true,"message":"ok"}`))
+	}))
+	defer mockServer.Close()
+
+	orderActivities := activities.NewOrderActivities(mockServer.URL + "/validate")
+	orderActivities.ValidationBreaker = activities.NewCircuitBreaker("validation", activities.CircuitBreakerConfig{
+		FailureThreshold: 2,
+		OpenTimeout:      100 * time.Millisecond,
+	})
+
+	order := models.Order{ID: "TEST-CB-001", Items: []string{"item1"}, Amount: 100.0}
+	ctx := context.Background()
+
+	// Two failures trip the breaker
+	for i := 0; i < 2; i++ {
+		_, err := orderActivities.ValidateOrder(ctx, order)
+		require.Error(t, err)
+	}
+	assert.Equal(t, activities.CircuitOpen, orderActivities.ValidationBreaker.State())
+
+	// While open, calls fail fast with a typed error and never reach the service
+	callsBeforeOpen := atomic.LoadInt32(&calls)
+	_, err := orderActivities.ValidateOrder(ctx, order)
+	var appErr *temporal.ApplicationError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, models.ErrTypeServiceUnavailable, appErr.Type())
+	assert.Equal(t, callsBeforeOpen, atomic.LoadInt32(&calls))
+
+	// After the cool-down a successful trial request closes the circuit
+	healthy.Store(true)
+	time.Sleep(150 * time.Millisecond)
+	assert.Equal(t, activities.CircuitHalfOpen, orderActivities.ValidationBreaker.State())
+
+	resp, err := orderActivities.ValidateOrder(ctx, order)
+	require.NoError(t, err)
+	assert.True(t, resp.Valid)
+	assert.Equal(t, activities.CircuitClosed, orderActivities.ValidationBreaker.State())
+}
+
+func TestOrderWorkflow_BacksOffWhileValidationUnavailable(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	storeActivities := store.NewStoreActivities(nil)
+	env.RegisterActivity(orderActivities.ValidateOrder)
+	env.RegisterActivity(orderActivities.ProcessPayment)
+	env.RegisterActivity(orderActivities.FulfillItem)
+	env.RegisterActivity(orderActivities.NotifyOrderComplete)
+	env.RegisterActivity(events.NewEventActivities(nil).PublishOrderEvent)
+	env.RegisterActivity(storeActivities.PersistOrder)
+	env.RegisterActivity(storeActivities.UpdateOrderStatus)
+	env.RegisterWorkflow(workflows.OrderWorkflow)
+	env.RegisterWorkflow(workflows.PaymentWorkflow)
+
+	unavailable := temporal.NewNonRetryableApplicationError("validation service unavailable", models.ErrTypeServiceUnavailable, nil)
+	env.OnActivity(orderActivities.ValidateOrder, mock.Anything, mock.Anything).Return(nil, unavailable).Once()
+	env.OnActivity(orderActivities.ValidateOrder, mock.Anything, mock.Anything).Return(&models.ValidationResponse{Valid: true}, nil).Once()
+	env.OnActivity(orderActivities.ProcessPayment, mock.Anything, mock.Anything).Return(&models.PaymentResponse{
+		Success:       true,
+		TransactionID: "TXN-CB-123",
+	}, nil)
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	env.OnActivity(orderActivities.NotifyOrderComplete, mock.Anything, mock.Anything).Return(nil)
+
+	order := models.Order{
+		ID:     "TEST-CB-002",
+		Items:  []string{"item1"},
+		Amount: 100.0,
+		Status: models.StatusPending,
+	}
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, order)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+}
@@ -0,0 +1,81 @@
+package tests
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/aswathylr-builds/temporal-order-processing/cloud"
+	"github.com/aswathylr-builds/temporal-order-processing/config"
+	"github.com/aswathylr-builds/temporal-order-processing/temporalclient"
+	"github.com/aswathylr-builds/temporal-order-processing/tlsconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/client"
+)
+
+func TestCloudConfig_RegionSelectsRegionalEndpoint(t *testing.T) {
+	assert.Equal(t, "localhost:7233", cloud.Config{}.HostPort("localhost:7233"))
+	assert.Equal(t, "us-east-1.aws.api.temporal.io:7233", cloud.Config{Region: "us-east-1.aws"}.HostPort("localhost:7233"))
+}
+
+func TestCloudConfig_APIKeyEnablesCredentialsAndTLS(t *testing.T) {
+	config := cloud.Config{Namespace: "orders.a1b2c", APIKey: "secret-key"}
+	require.NoError(t, config.Validate())
+
+	var options client.Options
+	config.Apply(&options)
+
+	assert.Equal(t, "orders.a1b2c", options.Namespace)
+	assert.NotNil(t, options.Credentials)
+	require.NotNil(t, options.ConnectionOptions.TLS)
+}
+
+func TestCloudConfig_APIKeyKeepsExistingTLSConfig(t *testing.T) {
+	reloader, err := tlsconfig.NewReloader(tlsconfig.Config{ServerName: "temporal.internal"})
+	require.NoError(t, err)
+	tlsConfig, err := reloader.TLSConfig()
+	require.NoError(t, err)
+
+	options := client.Options{ConnectionOptions: client.ConnectionOptions{TLS: tlsConfig}}
+	cloud.Config{Namespace: "orders.a1b2c", APIKey: "secret-key"}.Apply(&options)
+
+	assert.Same(t, tlsConfig, options.ConnectionOptions.TLS)
+}
+
+func TestCloudConfig_NamespaceOnlyLeavesConnectionUnchanged(t *testing.T) {
+	var options client.Options
+	cloud.Config{Namespace: "orders"}.Apply(&options)
+
+	assert.Equal(t, "orders", options.Namespace)
+	assert.Nil(t, options.Credentials)
+	assert.Nil(t, options.ConnectionOptions.TLS)
+}
+
+func TestCloudConfig_APIKeyRequiresNamespace(t *testing.T) {
+	assert.Error(t, cloud.Config{APIKey: "secret-key"}.Validate())
+}
+
+func TestTemporalClient_OptionsCarryCodecsAndPropagators(t *testing.T) {
+	cfg, err := config.Load("")
+	require.NoError(t, err)
+
+	options, err := temporalclient.NewOptions(cfg, slog.New(slog.DiscardHandler))
+	require.NoError(t, err)
+
+	assert.Equal(t, config.DefaultTemporalHost, options.HostPort)
+	assert.NotNil(t, options.DataConverter)
+	assert.NotNil(t, options.FailureConverter)
+	assert.Len(t, options.ContextPropagators, 2)
+	assert.Len(t, options.Interceptors, 1)
+}
+
+func TestTemporalClient_ValidatesCloudConfigBeforeDialing(t *testing.T) {
+	cfg, err := config.Load("")
+	require.NoError(t, err)
+	cfg.Temporal.Namespace = ""
+	cfg.Temporal.APIKey = "secret-key"
+
+	_, err = temporalclient.Dial(cfg, slog.New(slog.DiscardHandler))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "TEMPORAL_NAMESPACE is required")
+}
@@ -0,0 +1,143 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aswathylr-builds/temporal-order-processing/codec"
+	"github.com/aswathylr-builds/temporal-order-processing/config"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var otherNamespaceKey = bytes.Repeat([]byte{9}, codec.KeySize)
+
+func newCodecServer(t *testing.T, options codec.HTTPHandlerOptions) *httptest.Server {
+	t.Helper()
+	ordersCodec, err := codec.NewEncryptionCodec(testKey())
+	require.NoError(t, err)
+	otherCodec, err := codec.NewEncryptionCodec(otherNamespaceKey)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(codec.NewHTTPHandler(map[string][]converter.PayloadCodec{
+		"orders": {ordersCodec},
+		"other":  {otherCodec},
+	}, options))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func postPayloads(t *testing.T, url, namespace string, payloads []*commonpb.Payload, headers map[string]string) *http.Response {
+	t.Helper()
+	body, err := json.Marshal(&commonpb.Payloads{Payloads: payloads})
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(codec.NamespaceHeader, namespace)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func encryptedOrder(t *testing.T, key []byte, id string) []*commonpb.Payload {
+	t.Helper()
+	encryptionCodec, err := codec.NewEncryptionCodec(key)
+	require.NoError(t, err)
+	payloads, err := encryptionCodec.Encode(testPayloads(t, models.Order{ID: id}))
+	require.NoError(t, err)
+	return payloads
+}
+
+func TestCodecServer_DecodesWithNamespaceKeys(t *testing.T) {
+	server := newCodecServer(t, codec.HTTPHandlerOptions{})
+
+	resp := postPayloads(t, server.URL+"/decode", "orders", encryptedOrder(t, testKey(), "TEST-CODEC-001"), nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var buf bytes.Buffer
+	_, err := buf.ReadFrom(resp.Body)
+	require.NoError(t, err)
+	var decoded commonpb.Payloads
+	require.NoError(t, protojson.Unmarshal(buf.Bytes(), &decoded))
+	var order models.Order
+	require.NoError(t, converter.GetDefaultDataConverter().FromPayload(decoded.Payloads[0], &order))
+	assert.Equal(t, "TEST-CODEC-001", order.ID)
+
+	// Another namespace's keys cannot decode the orders namespace's payloads
+	resp = postPayloads(t, server.URL+"/decode", "other", encryptedOrder(t, testKey(), "TEST-CODEC-002"), nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = postPayloads(t, server.URL+"/decode", "unknown", encryptedOrder(t, testKey(), "TEST-CODEC-003"), nil)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func TestCodecServer_CORSPreflight(t *testing.T) {
+	server := newCodecServer(t, codec.HTTPHandlerOptions{CORSOrigins: []string{"http://localhost:8080"}})
+
+	req, err := http.NewRequest(http.MethodOptions, server.URL+"/decode", nil)
+	require.NoError(t, err)
+	req.Header.Set("Origin", "http://localhost:8080")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, "http://localhost:8080", resp.Header.Get("Access-Control-Allow-Origin"))
+	assert.Contains(t, resp.Header.Get("Access-Control-Allow-Headers"), codec.NamespaceHeader)
+
+	req.Header.Set("Origin", "https://evil.example")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+}
+
+func TestCodecServer_RequiresAuthToken(t *testing.T) {
+	server := newCodecServer(t, codec.HTTPHandlerOptions{AuthTokens: []string{"s3cret"}})
+	payloads := encryptedOrder(t, testKey(), "TEST-CODEC-004")
+
+	resp := postPayloads(t, server.URL+"/decode", "orders", payloads, nil)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp = postPayloads(t, server.URL+"/decode", "orders", payloads, map[string]string{"Authorization": "Bearer s3cret"})
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestConfigCodecServerNamespaces_LayerOverTopLevelEncryption(t *testing.T) {
+	path := writeConfigFile(t, `
+temporal:
+  namespace: orders
+encryption:
+  enabled: true
+  vault:
+    address: https://vault.internal:8200
+    token: root
+codec_server:
+  namespaces:
+    payments:
+      vault:
+        key_path: temporal/payments
+`)
+	cfg, err := config.Load(path)
+	require.NoError(t, err)
+
+	namespaces, err := cfg.CodecServerNamespaces()
+	require.NoError(t, err)
+	require.Len(t, namespaces, 2)
+	assert.Equal(t, "temporal/order-processing", namespaces["orders"].Vault.KeyPath)
+	payments := namespaces["payments"]
+	assert.Equal(t, "temporal/payments", payments.Vault.KeyPath)
+	assert.Equal(t, "https://vault.internal:8200", payments.Vault.Address)
+	assert.Equal(t, "root", payments.Vault.Token)
+	assert.True(t, payments.Enabled)
+}
@@ -0,0 +1,132 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aswathylr-builds/temporal-order-processing/awskms"
+	"github.com/aswathylr-builds/temporal-order-processing/codec"
+	"github.com/aswathylr-builds/temporal-order-processing/config"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+)
+
+// batchOrder is a large, repetitive order like the batch orders compression
+// is meant for
+func batchOrder(id string) models.Order {
+	order := models.Order{ID: id, Amount: 100}
+	for i := 0; i < 2000; i++ {
+		order.Items = append(order.Items, fmt.Sprintf("SKU-%05d", i))
+	}
+	return order
+}
+
+func TestCompressionCodec_RoundTrip(t *testing.T) {
+	for _, algorithm := range []codec.Compression{codec.CompressionZstd, codec.CompressionSnappy} {
+		t.Run(string(algorithm), func(t *testing.T) {
+			compression, err := codec.NewCompressionCodec(algorithm)
+			require.NoError(t, err)
+			payloads := testPayloads(t, batchOrder("TEST-ZIP-001"))
+
+			encoded, err := compression.Encode(payloads)
+			require.NoError(t, err)
+			assert.Equal(t, "binary/"+string(algorithm), string(encoded[0].Metadata["encoding"]))
+			assert.Less(t, len(encoded[0].Data), len(payloads[0].Data)/2)
+
+			decoded, err := compression.Decode(encoded)
+			require.NoError(t, err)
+			var order models.Order
+			require.NoError(t, converter.GetDefaultDataConverter().FromPayload(decoded[0], &order))
+			assert.Equal(t, batchOrder("TEST-ZIP-001"), order)
+		})
+	}
+}
+
+func TestCompressionCodec_LeavesSmallPayloads(t *testing.T) {
+	compression, err := codec.NewCompressionCodec(codec.CompressionZstd)
+	require.NoError(t, err)
+	payloads := testPayloads(t, models.Order{ID: "TEST-ZIP-002"})
+
+	encoded, err := compression.Encode(payloads)
+	require.NoError(t, err)
+	assert.Same(t, payloads[0], encoded[0])
+}
+
+func TestDataConverterBuilder_CompressesBeforeEncrypting(t *testing.T) {
+	encryption, err := codec.NewEncryptionCodec(testKey())
+	require.NoError(t, err)
+	dataConverter, err := codec.NewDataConverterBuilder().
+		WithCompression(codec.CompressionZstd).
+		WithEncryption(encryption).
+		Build()
+	require.NoError(t, err)
+
+	payloads, err := dataConverter.ToPayloads(batchOrder("TEST-ZIP-003"))
+	require.NoError(t, err)
+	assert.Equal(t, codec.MetadataEncodingEncrypted, string(payloads.Payloads[0].Metadata["encoding"]))
+
+	// Under the encryption is the compressed payload
+	decrypted, err := encryption.Decode(payloads.Payloads)
+	require.NoError(t, err)
+	assert.Equal(t, codec.MetadataEncodingZstd, string(decrypted[0].Metadata["encoding"]))
+
+	// A converter with compression turned off still reads it
+	plainConverter, err := codec.NewEncryptionDataConverter(testKey())
+	require.NoError(t, err)
+	var order models.Order
+	require.NoError(t, plainConverter.FromPayloads(&commonpb.Payloads{Payloads: payloads.Payloads}, &order))
+	assert.Equal(t, "TEST-ZIP-003", order.ID)
+}
+
+func TestEncryptionDataConverters_ReadCompressedPayloads(t *testing.T) {
+	keyring := codec.Keyring{Current: codec.StaticKey(testKey())}
+	envelope := codec.EnvelopeConfig{DataKeys: awskms.New(newFakeKMS(), "alias/order-processing")}
+	tests := []struct {
+		name       string
+		encryption converter.PayloadCodec
+		converter  func() (converter.DataConverter, error)
+	}{
+		{"keyring", codec.NewEncryptionCodecWithKeyring(keyring), func() (converter.DataConverter, error) {
+			return codec.NewEncryptionDataConverterWithKeyring(keyring)
+		}},
+		{"envelope", codec.NewEnvelopeEncryptionCodec(envelope), func() (converter.DataConverter, error) {
+			return codec.NewEnvelopeEncryptionDataConverter(envelope)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compressing, err := codec.NewDataConverterBuilder().
+				WithCompression(codec.CompressionZstd).
+				WithEncryption(tt.encryption).
+				Build()
+			require.NoError(t, err)
+			payloads, err := compressing.ToPayloads(batchOrder("TEST-ZIP-004"))
+			require.NoError(t, err)
+
+			// The converter without compression decrypts and decompresses it
+			dataConverter, err := tt.converter()
+			require.NoError(t, err)
+			var order models.Order
+			require.NoError(t, dataConverter.FromPayloads(payloads, &order))
+			assert.Equal(t, batchOrder("TEST-ZIP-004"), order)
+
+			// and round-trips its own payloads
+			payloads, err = dataConverter.ToPayloads(order)
+			require.NoError(t, err)
+			var decoded models.Order
+			require.NoError(t, compressing.FromPayloads(payloads, &decoded))
+			assert.Equal(t, order, decoded)
+		})
+	}
+}
+
+func TestConfigLoad_RejectsUnknownCompression(t *testing.T) {
+	t.Setenv("PAYLOAD_COMPRESSION", "gzip")
+
+	_, err := config.Load("")
+	assert.ErrorContains(t, err, "payload.compression")
+}
@@ -0,0 +1,182 @@
+package tests
+
+import (
+	"crypto/fips140"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/config"
+	"github.com/aswathylr-builds/temporal-order-processing/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0600))
+	return path
+}
+
+func TestConfigLoad_Defaults(t *testing.T) {
+	cfg, err := config.Load("")
+	require.NoError(t, err)
+
+	assert.Equal(t, config.DefaultTemporalHost, cfg.TemporalHostPort())
+	assert.Equal(t, "http://localhost:8081/validate", cfg.Validation.URL)
+	assert.Equal(t, 8090, cfg.Health.Port)
+	assert.Equal(t, 9090, cfg.Metrics.Port)
+	assert.Equal(t, config.RoleAll, cfg.Worker.Role)
+	assert.Equal(t, 30*time.Second, cfg.Worker.StopTimeout)
+	assert.Equal(t, logging.Config{Format: logging.FormatText, Level: slog.LevelInfo}, cfg.LoggingConfig())
+	assert.Equal(t, activities.DefaultValidationRules(), cfg.LocalValidationRules())
+}
+
+func TestConfigLoad_EnvironmentOverridesFile(t *testing.T) {
+	path := writeConfigFile(t, `
+temporal:
+  host_port: temporal.internal:7233
+  namespace: orders
+validation:
+  url: https://validation.internal/validate
+  http:
+    timeout: 5s
+kafka:
+  brokers: [kafka-1:9092, kafka-2:9092]
+worker:
+  orders:
+    max_concurrent_activities: 20
+  payments:
+    max_concurrent_activities: 5
+`)
+	t.Setenv("TEMPORAL_HOST", "temporal.override:7233")
+	t.Setenv("VALIDATION_HTTP_TIMEOUT", "2s")
+	t.Setenv("PAYMENT_WORKER_MAX_CONCURRENT_ACTIVITIES", "8")
+	t.Setenv("LOG_FORMAT", "JSON")
+	t.Setenv("LOG_LEVEL", "debug")
+
+	cfg, err := config.Load(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, "temporal.override:7233", cfg.TemporalHostPort())
+	assert.Equal(t, "orders", cfg.Temporal.Namespace)
+	assert.Equal(t, "https://validation.internal/validate", cfg.Validation.URL)
+	assert.Equal(t, 2*time.Second, cfg.Validation.HTTP.Timeout)
+	assert.Equal(t, []string{"kafka-1:9092", "kafka-2:9092"}, cfg.Kafka.Brokers)
+	assert.Equal(t, "order-events", cfg.Kafka.OrderEventsTopic)
+	assert.Equal(t, 20, cfg.Worker.Orders.MaxConcurrentActivities)
+	assert.Equal(t, 8, cfg.Worker.Payments.MaxConcurrentActivities)
+	assert.Equal(t, logging.Config{Format: logging.FormatJSON, Level: slog.LevelDebug}, cfg.LoggingConfig())
+}
+
+func TestConfigWithProfile_LayersOverFileAndEnvironment(t *testing.T) {
+	path := writeConfigFile(t, `
+temporal:
+  namespace: orders
+encryption:
+  enabled: true
+  key_file: dev.key
+profiles:
+  prod:
+    temporal:
+      namespace: orders.a1b2c
+      cloud_region: us-east-1.aws
+      api_key: prod-key
+    encryption:
+      kms:
+        key_id: alias/orders
+`)
+	t.Setenv("TEMPORAL_NAMESPACE", "orders-dev")
+
+	cfg, err := config.Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "orders-dev", cfg.Temporal.Namespace)
+
+	prod, err := cfg.WithProfile("prod")
+	require.NoError(t, err)
+	assert.Equal(t, "orders.a1b2c", prod.Temporal.Namespace)
+	assert.Equal(t, "prod-key", prod.Temporal.APIKey)
+	assert.Equal(t, "us-east-1.aws.api.temporal.io:7233", prod.TemporalHostPort())
+	assert.True(t, prod.Encryption.Enabled)
+	assert.Equal(t, "alias/orders", prod.Encryption.KMS.KeyID)
+	assert.Equal(t, "dev.key", prod.Encryption.KeyFile)
+
+	_, err = cfg.WithProfile("staging")
+	assert.ErrorContains(t, err, `unknown profile "staging"; configured profiles: prod`)
+}
+
+func TestConfigLoad_CloudRegionSelectsEndpoint(t *testing.T) {
+	t.Setenv("TEMPORAL_NAMESPACE", "orders.a1b2c")
+	t.Setenv("TEMPORAL_API_KEY", "secret-key")
+	t.Setenv("TEMPORAL_CLOUD_REGION", "us-east-1.aws")
+
+	cfg, err := config.Load("")
+	require.NoError(t, err)
+	assert.Equal(t, "us-east-1.aws.api.temporal.io:7233", cfg.TemporalHostPort())
+}
+
+func TestConfigLoad_ListsFromEnvironment(t *testing.T) {
+	t.Setenv("SIGNAL_AUTH_SECRETS", "new-secret, old-secret,")
+
+	cfg, err := config.Load("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"new-secret", "old-secret"}, cfg.Auth.SignalSecrets)
+}
+
+func TestConfigLoad_RejectsMalformedValues(t *testing.T) {
+	t.Setenv("HEALTH_PORT", "eighty")
+
+	_, err := config.Load("")
+	assert.ErrorContains(t, err, "HEALTH_PORT")
+}
+
+func TestConfigLoad_ReportsEveryInvalidSetting(t *testing.T) {
+	path := writeConfigFile(t, `
+temporal:
+  api_key: secret-key
+  tls:
+    cert_file: client.pem
+logging:
+  level: verbose
+worker:
+  role: billing
+health:
+  wiremock:
+    warn: 2s
+    critical: 1s
+`)
+
+	_, err := config.Load(path)
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "namespace")
+	assert.ErrorContains(t, err, "temporal.tls.cert_file")
+	assert.ErrorContains(t, err, "logging.level")
+	assert.ErrorContains(t, err, "worker.role")
+	assert.ErrorContains(t, err, "health.wiremock.critical")
+}
+
+func TestConfigLoad_FIPSRejectsNonCompliantSettings(t *testing.T) {
+	t.Setenv("ENCRYPTION_ENABLED", "true")
+	t.Setenv("ENCRYPTION_FIPS", "true")
+
+	_, err := config.Load("")
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "key_file is not an approved key source")
+	if !fips140.Enabled() {
+		assert.ErrorContains(t, err, "Go Cryptographic Module is not enabled")
+	}
+
+	t.Setenv("VAULT_ADDR", "http://vault.internal:8200")
+	t.Setenv("VAULT_TOKEN", "token")
+	_, err = config.Load("")
+	assert.ErrorContains(t, err, "vault.address must use https")
+}
+
+func TestConfigLoad_MissingFile(t *testing.T) {
+	_, err := config.Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.ErrorContains(t, err, "failed to read config file")
+}
@@ -0,0 +1,62 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aswathylr-builds/temporal-order-processing/intake"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingCanceller records the orders it is asked to cancel
+type recordingCanceller struct {
+	cancelled []string
+	err       error
+}
+
+func (c *recordingCanceller) CancelOrder(ctx context.Context, orderID string) error {
+	c.cancelled = append(c.cancelled, orderID)
+	return c.err
+}
+
+func TestMessageHandler_StartsAndCancelsOrders(t *testing.T) {
+	submitter := &recordingSubmitter{}
+	canceller := &recordingCanceller{}
+	handler := intake.NewMessageHandler(submitter, canceller)
+
+	require.NoError(t, handler.HandleMessage(context.Background(),
+		[]byte(`{"type": "order.create", "order": {"id": "ORD-1", "items": ["mug"], "amount": 9.5}}`)))
+	require.NoError(t, handler.HandleMessage(context.Background(),
+		[]byte(`{"type": "order.cancel", "order_id": "ORD-1"}`)))
+
+	require.Len(t, submitter.orders, 1)
+	assert.Equal(t, "ORD-1", submitter.orders[0].ID)
+	assert.Equal(t, models.StatusPending, submitter.orders[0].Status)
+	assert.Equal(t, []string{"ORD-1"}, canceller.cancelled)
+}
+
+func TestMessageHandler_ClassifiesFailures(t *testing.T) {
+	canceller := &recordingCanceller{}
+	handler := intake.NewMessageHandler(&recordingSubmitter{}, canceller)
+	ctx := context.Background()
+
+	for _, value := range []string{
+		`not json`,
+		`{"type": "order.refund", "order_id": "ORD-1"}`,
+		`{"type": "order.create", "order": {"id": "ORD-1", "amount": 9.5}}`,
+		`{"type": "order.cancel"}`,
+	} {
+		assert.ErrorIs(t, handler.HandleMessage(ctx, []byte(value)), intake.ErrInvalidMessage, value)
+	}
+
+	// A closed order is done with; anything else is retried
+	canceller.err = intake.ErrOrderNotFound
+	assert.NoError(t, handler.HandleMessage(ctx, []byte(`{"type": "order.cancel", "order_id": "ORD-1"}`)))
+	canceller.err = errors.New("temporal unavailable")
+	err := handler.HandleMessage(ctx, []byte(`{"type": "order.cancel", "order_id": "ORD-1"}`))
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, intake.ErrInvalidMessage)
+}
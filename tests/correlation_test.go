@@ -0,0 +1,109 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/correlation"
+	"github.com/aswathylr-builds/temporal-order-processing/events"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/store"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+)
+
+// headerMap is an in-memory Temporal header for exercising the propagator
+type headerMap map[string]*commonpb.Payload
+
+func (h headerMap) Set(key string, value *commonpb.Payload) { h[key] = value }
+
+func (h headerMap) Get(key string) (*commonpb.Payload, bool) {
+	value, ok := h[key]
+	return value, ok
+}
+
+func (h headerMap) ForEachKey(handler func(string, *commonpb.Payload) error) error {
+	for key, value := range h {
+		if err := handler(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestPropagator_RoundTrip(t *testing.T) {
+	propagator := correlation.NewPropagator()
+	values := correlation.Values{CorrelationID: "corr-123", TenantID: "tenant-a"}
+
+	header := headerMap{}
+	require.NoError(t, propagator.Inject(correlation.WithValues(context.Background(), values), header))
+
+	ctx, err := propagator.Extract(context.Background(), header)
+	require.NoError(t, err)
+	extracted, ok := correlation.FromContext(ctx)
+	require.True(t, ok)
+	assert.Equal(t, values, extracted)
+}
+
+func TestPropagator_NoValues(t *testing.T) {
+	propagator := correlation.NewPropagator()
+
+	header := headerMap{}
+	require.NoError(t, propagator.Inject(context.Background(), header))
+	assert.Empty(t, header)
+
+	ctx, err := propagator.Extract(context.Background(), header)
+	require.NoError(t, err)
+	_, ok := correlation.FromContext(ctx)
+	assert.False(t, ok)
+}
+
+func TestOrderWorkflow_ForwardsCorrelationIDToValidation(t *testing.T) {
+	var gotCorrelationID, gotTenantID string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotCorrelationID = r.Header.Get(correlation.HeaderCorrelationID)
+		gotTenantID = r.Header.Get(correlation.HeaderTenantID)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(models.ValidationResponse{Valid: false, Message: "rejected for test"})
+	}))
+	defer mockServer.Close()
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	propagator := correlation.NewPropagator()
+	env.SetContextPropagators([]workflow.ContextPropagator{propagator})
+
+	// Simulate the headers a starter using the propagator would send
+	header := headerMap{}
+	ctx := correlation.WithValues(context.Background(), correlation.Values{CorrelationID: "corr-456", TenantID: "tenant-b"})
+	require.NoError(t, propagator.Inject(ctx, header))
+	env.SetHeader(&commonpb.Header{Fields: header})
+
+	orderActivities := activities.NewOrderActivities(mockServer.URL)
+	storeActivities := store.NewStoreActivities(nil)
+	env.RegisterActivity(orderActivities.ValidateOrder)
+	env.RegisterActivity(events.NewEventActivities(nil).PublishOrderEvent)
+	env.RegisterActivity(storeActivities.PersistOrder)
+	env.RegisterActivity(storeActivities.UpdateOrderStatus)
+	env.RegisterWorkflow(workflows.OrderWorkflow)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:     "TEST-CORR-001",
+		Items:  []string{"item1"},
+		Amount: 10.0,
+		Status: models.StatusPending,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.Error(t, env.GetWorkflowError())
+	assert.Equal(t, "corr-456", gotCorrelationID)
+	assert.Equal(t, "tenant-b", gotTenantID)
+}
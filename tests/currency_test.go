@@ -0,0 +1,114 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+)
+
+func TestConvertCurrency_FixedRates(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	orderActivities.ExchangeRates = activities.FixedExchangeRates{"EUR/USD": 1.08}
+	ctx := context.Background()
+
+	conversion, err := orderActivities.ConvertCurrency(ctx, models.ConversionRequest{OrderID: "TEST-FX-001", Amount: 100, From: "EUR"})
+	require.NoError(t, err)
+	assert.Equal(t, "USD", conversion.To)
+	assert.Equal(t, 1.08, conversion.Rate)
+	assert.Equal(t, 108.0, conversion.ConvertedAmount)
+
+	conversion, err = orderActivities.ConvertCurrency(ctx, models.ConversionRequest{OrderID: "TEST-FX-001", Amount: 108, From: "USD", To: "EUR"})
+	require.NoError(t, err)
+	assert.Equal(t, 100.0, conversion.ConvertedAmount, "the inverse of a fixed rate is used the other way round")
+
+	conversion, err = orderActivities.ConvertCurrency(ctx, models.ConversionRequest{OrderID: "TEST-FX-001", Amount: 50, From: "USD"})
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, conversion.Rate)
+
+	_, err = orderActivities.ConvertCurrency(ctx, models.ConversionRequest{OrderID: "TEST-FX-001", Amount: 50, From: "JPY"})
+	var appErr *temporal.ApplicationError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, models.ErrTypeCurrencyUnsupported, appErr.Type())
+	assert.True(t, appErr.NonRetryable())
+}
+
+func TestCachedExchangeRates_AsksRateAPIOnce(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		assert.Equal(t, "/rates", r.URL.Path)
+		if r.URL.Query().Get("from") != "GBP" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"rate": 1.27})
+	}))
+	defer server.Close()
+	rates := activities.NewCachedExchangeRates(&activities.HTTPExchangeRates{URL: server.URL, Client: http.DefaultClient}, 0)
+
+	for range 3 {
+		rate, err := rates.Rate(context.Background(), "GBP", "USD")
+		require.NoError(t, err)
+		assert.Equal(t, 1.27, rate.Rate)
+		assert.False(t, rate.AsOf.IsZero())
+	}
+	_, err := rates.Rate(context.Background(), "XYZ", "USD")
+	var appErr *temporal.ApplicationError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, models.ErrTypeCurrencyUnsupported, appErr.Type())
+	assert.Equal(t, 2, requests)
+}
+
+func TestPaymentWorkflow_ChargesForeignCurrencyOrderInBaseCurrency(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	orderActivities.ExchangeRates = activities.FixedExchangeRates{"EUR/USD": 1.1}
+	env.RegisterActivity(orderActivities.ConvertCurrency)
+	env.RegisterActivity(orderActivities.ProcessPayment)
+	var charged []models.PaymentRequest
+	env.OnActivity(orderActivities.ProcessPayment, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, req models.PaymentRequest) (*models.PaymentResponse, error) {
+			charged = append(charged, req)
+			return &models.PaymentResponse{Success: true, TransactionID: "TXN-" + req.Method}, nil
+		})
+
+	env.ExecuteWorkflow(workflows.PaymentWorkflow, models.Order{
+		ID:       "TEST-FX-002",
+		Items:    []string{"item1"},
+		Amount:   100.01,
+		Currency: "EUR",
+		Tenders:  []models.Tender{{Method: models.TenderGiftCard, Amount: 33.33}, {Method: models.TenderCard, Amount: 66.68}},
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.Len(t, charged, 2)
+	assert.Equal(t, 36.66, charged[0].Amount)
+	assert.Equal(t, 73.35, charged[1].Amount, "the last tender takes up the rounding")
+	var response models.PaymentResponse
+	require.NoError(t, env.GetWorkflowResult(&response))
+	require.NotNil(t, response.Conversion)
+	assert.Equal(t, "EUR", response.Conversion.From)
+	assert.Equal(t, "USD", response.Conversion.To)
+	assert.Equal(t, 1.1, response.Conversion.Rate)
+	assert.Equal(t, 110.01, response.Conversion.ConvertedAmount)
+}
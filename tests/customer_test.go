@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/featureflags"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/temporal"
+)
+
+func TestValidateOrder_SendsCustomer(t *testing.T) {
+	var received models.ValidationRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		json.NewEncoder(w).Encode(models.ValidationResponse{Valid: true, Message: "ok"})
+	}))
+	defer server.Close()
+	orderActivities := activities.NewOrderActivities(server.URL)
+	customer := &models.Customer{ID: "CUST-7", Name: "Ada Lovelace", Email: "ada@example.com", Phone: "+15550100", Tier: models.CustomerTierGold}
+
+	resp, err := orderActivities.ValidateOrder(context.Background(), models.Order{
+		ID:       "TEST-CUSTOMER-001",
+		Items:    []string{"item1"},
+		Amount:   100,
+		Customer: customer,
+	})
+
+	require.NoError(t, err)
+	assert.True(t, resp.Valid)
+	assert.Equal(t, "TEST-CUSTOMER-001", received.OrderID)
+	assert.Equal(t, customer, received.Customer)
+}
+
+func TestCheckFraud_RecordsOrderCustomer(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+
+	_, err := orderActivities.CheckFraud(context.Background(), models.Order{
+		ID:       "TEST-CUSTOMER-002",
+		Items:    []string{"item1"},
+		Amount:   5000,
+		Customer: &models.Customer{ID: "CUST-7"},
+	})
+
+	var appErr *temporal.ApplicationError
+	require.True(t, errors.As(err, &appErr))
+	var details models.FraudSuspectedError
+	require.NoError(t, appErr.Details(&details))
+	assert.Equal(t, "CUST-7", details.CustomerID)
+}
+
+func TestOrderWorkflow_ScreensOrderCustomerWithoutMemo(t *testing.T) {
+	useFlags(t, map[string]bool{featureflags.CustomerScreening: true})
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	orderActivities.ScreeningDenylist = []string{"CUST-BLOCKED"}
+	env := newFulfillmentTestEnv(orderActivities)
+	env.RegisterActivity(orderActivities.ScreenCustomer)
+	env.RegisterActivity(orderActivities.NotifyOpsOfScreeningHit)
+	env.OnActivity(orderActivities.NotifyOpsOfScreeningHit, mock.Anything, mock.Anything).Return(nil)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:       "TEST-CUSTOMER-003",
+		Items:    []string{"item1"},
+		Amount:   100.0,
+		Status:   models.StatusPending,
+		Customer: &models.Customer{ID: "CUST-BLOCKED", Email: "blocked@example.com"},
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	var appErr *temporal.ApplicationError
+	require.True(t, errors.As(env.GetWorkflowError(), &appErr))
+	assert.Equal(t, models.ErrTypeCustomerDenied, appErr.Type())
+}
+
+func TestCustomer_Contact(t *testing.T) {
+	customer := models.Customer{ID: "CUST-7", Email: "ada@example.com"}
+
+	assert.Equal(t, "ada@example.com", customer.Contact("email"))
+	assert.Empty(t, customer.Contact("sms"))
+	assert.NoError(t, customer.Validate())
+	assert.ErrorContains(t, models.Customer{ID: "CUST-7", Email: "ada"}.Validate(), "not an email address")
+}
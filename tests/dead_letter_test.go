@@ -0,0 +1,287 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/events"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/store"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+)
+
+func TestOrderWorkflow_DeadLettersAfterRetriesExhausted(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	storeActivities := store.NewStoreActivities(nil)
+	env.RegisterActivity(orderActivities.ValidateOrder)
+	env.RegisterActivity(orderActivities.ProcessPayment)
+	env.RegisterActivity(orderActivities.FulfillItem)
+	env.RegisterActivity(events.NewEventActivities(nil).PublishOrderEvent)
+	env.RegisterActivity(storeActivities.PersistOrder)
+	env.RegisterActivity(storeActivities.UpdateOrderStatus)
+	env.RegisterWorkflow(workflows.OrderWorkflow)
+	env.RegisterWorkflow(workflows.PaymentWorkflow)
+	env.RegisterWorkflow(workflows.FailedOrderWorkflow)
+
+	env.OnActivity(orderActivities.ValidateOrder, mock.Anything, mock.Anything).Return(&models.ValidationResponse{Valid: true}, nil)
+	env.OnActivity(orderActivities.ProcessPayment, mock.Anything, mock.Anything).Return(&models.PaymentResponse{
+		Success:       true,
+		TransactionID: "TXN-DLQ-123",
+	}, nil)
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(temporal.NewNonRetryableApplicationError("warehouse offline", "", errors.New("warehouse offline")))
+
+	// The dead-letter child is abandoned, so capture its input when it starts
+	// rather than waiting for it to run
+	var deadLettered models.OrderFailure
+	env.OnWorkflow(workflows.FailedOrderWorkflow, mock.Anything, mock.Anything).Return(&models.DeadLetterStatus{}, nil)
+	env.SetOnChildWorkflowStartedListener(func(info *workflow.Info, ctx workflow.Context, args converter.EncodedValues) {
+		require.NoError(t, args.Get(&deadLettered))
+	})
+
+	order := models.Order{
+		ID:     "TEST-DLQ-001",
+		Items:  []string{"item1"},
+		Amount: 100.0,
+		Status: models.StatusPending,
+	}
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, order)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.Error(t, env.GetWorkflowError())
+	assert.Equal(t, order.ID, deadLettered.Order.ID)
+	assert.Equal(t, models.StageProcessing, deadLettered.Stage)
+	assert.Contains(t, deadLettered.Reason, "warehouse offline")
+	// The order failed after it was paid, so the payment is recorded
+	assert.True(t, deadLettered.Paid())
+	assert.Equal(t, "TXN-DLQ-123", deadLettered.TransactionID)
+}
+
+func TestFailedOrderWorkflow_RetrySignalRedrivesOrder(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+
+	repo := newMemoryRepository()
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	storeActivities := store.NewStoreActivities(repo)
+	env.RegisterActivity(orderActivities.NotifyOpsOfFailure)
+	env.RegisterActivity(storeActivities.RecordOrderFailure)
+	env.RegisterWorkflow(workflows.FailedOrderWorkflow)
+	env.RegisterWorkflow(workflows.OrderWorkflow)
+
+	var redriven models.Order
+	env.OnWorkflow(workflows.OrderWorkflow, mock.Anything, mock.Anything).Return(nil)
+	env.SetOnChildWorkflowStartedListener(func(info *workflow.Info, ctx workflow.Context, args converter.EncodedValues) {
+		require.NoError(t, args.Get(&redriven))
+	})
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(models.SignalRetry, models.RetryRequest{RequestedBy: "on-call"})
+	}, time.Hour)
+
+	failure := models.OrderFailure{
+		Order:      models.Order{ID: "TEST-DLQ-002", Items: []string{"item1"}, Amount: 100.0, Status: models.StatusFailed},
+		WorkflowID: "order-workflow-TEST-DLQ-002",
+		RunID:      "run-1",
+		Stage:      models.StagePayment,
+		Reason:     "payment gateway timeout",
+	}
+	env.ExecuteWorkflow(workflows.FailedOrderWorkflow, failure)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+
+	var status models.DeadLetterStatus
+	require.NoError(t, env.GetWorkflowResult(&status))
+	assert.Equal(t, models.DeadLetterRedriven, status.Status)
+	assert.Equal(t, "on-call", status.RetriedBy)
+	assert.Equal(t, models.StatusPending, redriven.Status)
+	assert.Nil(t, redriven.PriorPayment)
+	require.Len(t, repo.failures, 1)
+	assert.Equal(t, "payment gateway timeout", repo.failures[0].Reason)
+}
+
+func TestFailedOrderWorkflow_RedrivesPaidOrderWithItsPayment(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	storeActivities := store.NewStoreActivities(nil)
+	env.RegisterActivity(orderActivities.NotifyOpsOfFailure)
+	env.RegisterActivity(storeActivities.RecordOrderFailure)
+	env.RegisterWorkflow(workflows.FailedOrderWorkflow)
+	env.RegisterWorkflow(workflows.OrderWorkflow)
+
+	var redriven models.Order
+	env.OnWorkflow(workflows.OrderWorkflow, mock.Anything, mock.Anything).Return(nil)
+	env.SetOnChildWorkflowStartedListener(func(info *workflow.Info, ctx workflow.Context, args converter.EncodedValues) {
+		require.NoError(t, args.Get(&redriven))
+	})
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(models.SignalRetry, models.RetryRequest{RequestedBy: "on-call"})
+	}, time.Hour)
+
+	env.ExecuteWorkflow(workflows.FailedOrderWorkflow, models.OrderFailure{
+		Order:         models.Order{ID: "TEST-DLQ-004", Items: []string{"item1"}, Amount: 100.0, Status: models.StatusFailed},
+		WorkflowID:    "order-workflow-TEST-DLQ-004",
+		RunID:         "run-1",
+		Stage:         models.StageProcessing,
+		Reason:        "warehouse offline",
+		TransactionID: "TXN-DLQ-456",
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.NotNil(t, redriven.PriorPayment)
+	assert.Equal(t, "TXN-DLQ-456", redriven.PriorPayment.TransactionID)
+}
+
+// redriveWorkflow runs an order as a child, the way FailedOrderWorkflow
+// re-drives it, and waits for the result
+func redriveWorkflow(ctx workflow.Context, order models.Order) (*models.OrderResult, error) {
+	ctx = workflow.WithChildOptions(ctx, workflow.ChildWorkflowOptions{WorkflowID: "order-workflow-" + order.ID})
+	var result *models.OrderResult
+	err := workflow.ExecuteChildWorkflow(ctx, workflows.OrderWorkflow, order).Get(ctx, &result)
+	return result, err
+}
+
+func TestOrderWorkflow_RedriveOfPaidOrderIsNotChargedAgain(t *testing.T) {
+	order := models.Order{
+		ID:           "TEST-DLQ-005",
+		Items:        []string{"item1"},
+		Amount:       100.0,
+		Status:       models.StatusPending,
+		PriorPayment: &models.PaymentResponse{Success: true, TransactionID: "TXN-DLQ-789"},
+	}
+
+	t.Run("re-driven by the dead-letter workflow", func(t *testing.T) {
+		orderActivities := activities.NewOrderActivities("http://mock-url")
+		env := newFulfillmentTestEnv(orderActivities)
+		env.RegisterWorkflow(redriveWorkflow)
+		env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		env.SetStartWorkflowOptions(client.StartWorkflowOptions{ID: models.DeadLetterWorkflowID(order.ID, "run-1")})
+
+		env.ExecuteWorkflow(redriveWorkflow, order)
+
+		require.True(t, env.IsWorkflowCompleted())
+		require.NoError(t, env.GetWorkflowError())
+		var result *models.OrderResult
+		require.NoError(t, env.GetWorkflowResult(&result))
+		assert.Equal(t, models.StatusCompleted, result.Status)
+		assert.Equal(t, "TXN-DLQ-789", result.TransactionID)
+		env.AssertNotCalled(t, "ProcessPayment", mock.Anything, mock.Anything)
+	})
+
+	t.Run("started directly", func(t *testing.T) {
+		orderActivities := activities.NewOrderActivities("http://mock-url")
+		env := newFulfillmentTestEnv(orderActivities)
+		env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		env.ExecuteWorkflow(workflows.OrderWorkflow, order)
+
+		require.True(t, env.IsWorkflowCompleted())
+		require.NoError(t, env.GetWorkflowError())
+		var result *models.OrderResult
+		require.NoError(t, env.GetWorkflowResult(&result))
+		assert.Equal(t, "TXN-FULFILL-123", result.TransactionID)
+	})
+}
+
+func TestOrderWorkflow_RedriveOfPaidOrderDoesNotRedeemPointsAgain(t *testing.T) {
+	order := models.Order{
+		ID:           "TEST-DLQ-006",
+		Items:        []string{"item1"},
+		Amount:       100.0,
+		Status:       models.StatusPending,
+		Customer:     &models.Customer{ID: "CUST-LOYAL"},
+		RedeemPoints: 200,
+	}
+	redemptions := 0
+	newLoyaltyRedriveTestEnv := func(orderActivities *activities.OrderActivities) *testsuite.TestWorkflowEnvironment {
+		env := newFulfillmentTestEnv(orderActivities)
+		env.RegisterActivity(orderActivities.RedeemLoyaltyPoints)
+		env.RegisterActivity(orderActivities.AwardLoyaltyPoints)
+		env.OnActivity(orderActivities.RedeemLoyaltyPoints, mock.Anything, mock.Anything).Return(
+			func(ctx context.Context, req models.LoyaltyRequest) (*models.LoyaltyTransaction, error) {
+				redemptions++
+				return &models.LoyaltyTransaction{TransactionID: "LOY-REDEEM", Points: -req.Points}, nil
+			})
+		env.OnActivity(orderActivities.AwardLoyaltyPoints, mock.Anything, mock.Anything).Return(
+			&models.LoyaltyTransaction{TransactionID: "LOY-AWARD", Points: 100}, nil)
+		return env
+	}
+
+	// The first run redeems the points and is paid, then fails to fulfill
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newLoyaltyRedriveTestEnv(orderActivities)
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(temporal.NewNonRetryableApplicationError("warehouse offline", "", errors.New("warehouse offline")))
+	env.RegisterWorkflow(workflows.FailedOrderWorkflow)
+	var deadLettered models.OrderFailure
+	env.OnWorkflow(workflows.FailedOrderWorkflow, mock.Anything, mock.Anything).Return(&models.DeadLetterStatus{}, nil)
+	env.SetOnChildWorkflowStartedListener(func(info *workflow.Info, ctx workflow.Context, args converter.EncodedValues) {
+		require.NoError(t, args.Get(&deadLettered))
+	})
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, order)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.Error(t, env.GetWorkflowError())
+	require.True(t, deadLettered.Paid())
+
+	// The re-drive keeps the payment, and the points spent with it
+	orderActivities = activities.NewOrderActivities("http://mock-url")
+	env = newLoyaltyRedriveTestEnv(orderActivities)
+	env.RegisterWorkflow(redriveWorkflow)
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	env.SetStartWorkflowOptions(client.StartWorkflowOptions{ID: models.DeadLetterWorkflowID(order.ID, "run-1")})
+	order.PriorPayment = &models.PaymentResponse{Success: true, TransactionID: deadLettered.TransactionID}
+
+	env.ExecuteWorkflow(redriveWorkflow, order)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var result *models.OrderResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	assert.Equal(t, models.StatusCompleted, result.Status)
+	assert.Equal(t, 1, redemptions, "the points are redeemed once across both runs")
+}
+
+func TestFailedOrderWorkflow_ExpiresWithoutRetry(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	storeActivities := store.NewStoreActivities(nil)
+	env.RegisterActivity(orderActivities.NotifyOpsOfFailure)
+	env.RegisterActivity(storeActivities.RecordOrderFailure)
+	env.RegisterWorkflow(workflows.FailedOrderWorkflow)
+
+	env.ExecuteWorkflow(workflows.FailedOrderWorkflow, models.OrderFailure{
+		Order: models.Order{ID: "TEST-DLQ-003"},
+		Stage: models.StageValidation,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+
+	var status models.DeadLetterStatus
+	require.NoError(t, env.GetWorkflowResult(&status))
+	assert.Equal(t, models.DeadLetterExpired, status.Status)
+}
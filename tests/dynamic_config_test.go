@@ -0,0 +1,256 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/config"
+	"github.com/aswathylr-builds/temporal-order-processing/events"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+)
+
+// staticDynamicConfig serves a fixed dynamic configuration
+type staticDynamicConfig models.DynamicConfig
+
+func (c staticDynamicConfig) DynamicConfig() (models.DynamicConfig, error) {
+	return models.DynamicConfig(c), nil
+}
+
+func newDynamicConfigTestEnv(orderActivities *activities.OrderActivities, dynamic models.DynamicConfig) *testsuite.TestWorkflowEnvironment {
+	env := newFulfillmentTestEnv(orderActivities)
+	env.RegisterActivity(activities.NewConfigActivities(staticDynamicConfig(dynamic)).GetConfig)
+	return env
+}
+
+func queryStatus(t *testing.T, env *testsuite.TestWorkflowEnvironment) models.OrderStatus {
+	t.Helper()
+	result, err := env.QueryWorkflow("getStatus")
+	require.NoError(t, err)
+	var status models.OrderStatus
+	require.NoError(t, result.Get(&status))
+	return status
+}
+
+func TestDynamicFile_ReloadsWhenChanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dynamic.yaml")
+	source := config.NewDynamicFile(path)
+
+	current, err := source.DynamicConfig()
+	require.NoError(t, err)
+	assert.Equal(t, models.DynamicConfig{}, current, "a missing file disables dynamic behavior")
+
+	require.NoError(t, os.WriteFile(path, []byte("high_value_approval_threshold: 5000\nprocessing_sla: 30m\n"), 0600))
+	current, err = source.DynamicConfig()
+	require.NoError(t, err)
+	assert.Equal(t, models.DynamicConfig{HighValueApprovalThreshold: 5000, ProcessingSLA: 30 * time.Minute}, current)
+
+	require.NoError(t, os.WriteFile(path, []byte("high_value_approval_threshold: 2500\n"), 0600))
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, later, later))
+	current, err = source.DynamicConfig()
+	require.NoError(t, err)
+	assert.Equal(t, models.DynamicConfig{HighValueApprovalThreshold: 2500}, current)
+}
+
+func TestDynamicFile_InvalidEditKeepsLastValidValues(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dynamic.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("high_value_approval_threshold: 5000\n"), 0600))
+	source := config.NewDynamicFile(path)
+	_, err := source.DynamicConfig()
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte("high_value_approval_threshold: [not a number\n"), 0600))
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, later, later))
+
+	current, err := source.DynamicConfig()
+	assert.Error(t, err)
+	assert.Equal(t, 5000.0, current.HighValueApprovalThreshold)
+
+	current, err = activities.NewConfigActivities(source).GetConfig(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 5000.0, current.HighValueApprovalThreshold)
+}
+
+func TestOrderWorkflow_HighValueOrderWaitsForApproval(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newDynamicConfigTestEnv(orderActivities, models.DynamicConfig{HighValueApprovalThreshold: 5000})
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	env.RegisterDelayedCallback(func() {
+		status := queryStatus(t, env)
+		assert.Equal(t, models.StatusAwaitingApproval, status.Status)
+		assert.Equal(t, "pending", status.PaymentStatus, "payment waits for approval")
+		env.SignalWorkflow(models.SignalApprove, nil)
+	}, time.Hour)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:     "TEST-APPROVE-001",
+		Items:  []string{"item1"},
+		Amount: 7500.0,
+		Status: models.StatusPending,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, models.StatusCompleted, queryStatus(t, env).Status)
+	env.AssertExpectations(t)
+}
+
+func TestOrderWorkflow_CancelWhileAwaitingApproval(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newDynamicConfigTestEnv(orderActivities, models.DynamicConfig{HighValueApprovalThreshold: 5000})
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(models.SignalCancel, nil)
+	}, time.Hour)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:     "TEST-APPROVE-002",
+		Items:  []string{"item1"},
+		Amount: 5000.0,
+		Status: models.StatusPending,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, models.StatusCancelled, queryStatus(t, env).Status)
+	env.AssertNotCalled(t, "ProcessPayment", mock.Anything, mock.Anything)
+}
+
+func TestOrderWorkflow_OrderBelowThresholdIsNotHeld(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newDynamicConfigTestEnv(orderActivities, models.DynamicConfig{HighValueApprovalThreshold: 5000})
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:     "TEST-APPROVE-003",
+		Items:  []string{"item1"},
+		Amount: 100.0,
+		Status: models.StatusPending,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, models.StatusCompleted, queryStatus(t, env).Status)
+}
+
+func TestOrderWorkflow_FlagsSLABreach(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newDynamicConfigTestEnv(orderActivities, models.DynamicConfig{ProcessingSLA: time.Minute})
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		After(2 * time.Minute).Return(nil)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:     "TEST-SLA-001",
+		Items:  []string{"item1"},
+		Amount: 100.0,
+		Status: models.StatusPending,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	status := queryStatus(t, env)
+	assert.Equal(t, models.StatusCompleted, status.Status)
+	assert.True(t, status.SLABreached)
+}
+
+func TestOrderWorkflow_ExpeditesOrdersFromListedChannels(t *testing.T) {
+	for _, tc := range []struct {
+		channel   string
+		expedited bool
+	}{
+		{channel: "pos", expedited: true},
+		{channel: "web", expedited: false},
+	} {
+		t.Run(tc.channel, func(t *testing.T) {
+			orderActivities := activities.NewOrderActivities("http://mock-url")
+			env := newDynamicConfigTestEnv(orderActivities, models.DynamicConfig{ExpeditedChannels: []string{"pos"}})
+			require.NoError(t, env.SetMemoOnStart(models.OrderMemo{CustomerID: "CUST-7", Channel: tc.channel, Region: "eu"}.Fields()))
+			var expedited bool
+			env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Run(func(args mock.Arguments) { expedited = args.Bool(3) }).
+				Return(nil)
+
+			env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+				ID:     "TEST-CHANNEL-001",
+				Items:  []string{"item1"},
+				Amount: 50.0,
+				Status: models.StatusPending,
+			})
+
+			require.True(t, env.IsWorkflowCompleted())
+			require.NoError(t, env.GetWorkflowError())
+			assert.Equal(t, tc.expedited, expedited)
+			assert.Equal(t, tc.expedited, queryStatus(t, env).IsExpedited)
+		})
+	}
+}
+
+func TestDynamicFile_RejectsUnknownParentClosePolicies(t *testing.T) {
+	for _, contents := range []string{
+		"parent_close_policies:\n  shipping: abandon\n",
+		"parent_close_policies:\n  payment: detach\n",
+	} {
+		path := filepath.Join(t.TempDir(), "dynamic.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0600))
+		_, err := config.NewDynamicFile(path).DynamicConfig()
+		assert.Error(t, err, contents)
+	}
+
+	path := filepath.Join(t.TempDir(), "dynamic.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("parent_close_policies:\n  payment: request-cancel\n  analytics: terminate\nanalytics_export: true\n"), 0600))
+	current, err := config.NewDynamicFile(path).DynamicConfig()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{models.ChildPayment: models.ParentCloseRequestCancel, models.ChildAnalytics: models.ParentCloseTerminate}, current.ParentClosePolicies)
+	assert.True(t, current.AnalyticsExport)
+}
+
+// recordingExporter records the analytics records it is asked to export
+type recordingExporter struct {
+	records []models.OrderAnalytics
+}
+
+func (e *recordingExporter) Export(ctx context.Context, record models.OrderAnalytics) error {
+	e.records = append(e.records, record)
+	return nil
+}
+
+func (e *recordingExporter) Close() error {
+	return nil
+}
+
+func TestOrderWorkflow_ExportsAnalyticsInChild(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newDynamicConfigTestEnv(orderActivities, models.DynamicConfig{AnalyticsExport: true})
+	require.NoError(t, env.SetMemoOnStart(models.OrderMemo{CustomerID: "CUST-7", Channel: "web"}.Fields()))
+	exporter := &recordingExporter{}
+	env.RegisterWorkflow(workflows.OrderAnalyticsWorkflow)
+	env.RegisterActivity(events.NewAnalyticsActivities(exporter).ExportOrderAnalytics)
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:     "TEST-ANALYTICS-001",
+		Items:  []string{"item1", "item2"},
+		Amount: 50.0,
+		Status: models.StatusPending,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.Len(t, exporter.records, 1)
+	record := exporter.records[0]
+	assert.Equal(t, "TEST-ANALYTICS-001", record.OrderID)
+	assert.Equal(t, models.StatusCompleted, record.Status)
+	assert.Equal(t, "CUST-7", record.CustomerID)
+	assert.Equal(t, 2, record.ItemCount)
+}
@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+)
+
+// newEditWindowTestEnv returns an order environment with a 15 minute edit
+// grace period that records the items fulfilled in fulfilled
+func newEditWindowTestEnv(orderActivities *activities.OrderActivities, fulfilled *[]string) *testsuite.TestWorkflowEnvironment {
+	env := newDynamicConfigTestEnv(orderActivities, models.DynamicConfig{EditGracePeriod: 15 * time.Minute})
+	env.RegisterActivity(orderActivities.RefundPayment)
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, order models.Order, item string, expedited bool) error {
+			*fulfilled = append(*fulfilled, item)
+			return nil
+		})
+	return env
+}
+
+var editableOrder = models.Order{
+	ID:     "TEST-EDIT-001",
+	Items:  []string{"item1", "item2"},
+	Amount: 50.0,
+	Status: models.StatusPending,
+}
+
+func queryEditWindow(t *testing.T, env *testsuite.TestWorkflowEnvironment) models.EditWindow {
+	t.Helper()
+	result, err := env.QueryWorkflow("getEditWindow")
+	require.NoError(t, err)
+	var window models.EditWindow
+	require.NoError(t, result.Get(&window))
+	return window
+}
+
+func TestOrderWorkflow_EditWindowExpiresIntoFulfillment(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	var fulfilled []string
+	env := newEditWindowTestEnv(orderActivities, &fulfilled)
+	var during models.EditWindow
+	var status models.OrderStatus
+	var fulfilledDuring int
+	env.RegisterDelayedCallback(func() {
+		during = queryEditWindow(t, env)
+		status = queryStatus(t, env)
+		fulfilledDuring = len(fulfilled)
+	}, 5*time.Minute)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, editableOrder)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	assert.True(t, during.Open)
+	assert.InDelta(t, float64(10*time.Minute), float64(during.Remaining), float64(time.Minute), "about ten minutes of the window remain")
+	assert.Equal(t, "completed", status.PaymentStatus, "the order is paid before the window opens")
+	assert.Zero(t, fulfilledDuring, "fulfillment waits for the window to close")
+	assert.ElementsMatch(t, []string{"item1", "item2"}, fulfilled)
+	assert.Equal(t, models.EditWindow{}, queryEditWindow(t, env), "the window is closed once the order proceeds")
+}
+
+func TestOrderWorkflow_UpdateInEditWindowChangesItems(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	var fulfilled []string
+	env := newEditWindowTestEnv(orderActivities, &fulfilled)
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(models.SignalUpdate, models.OrderUpdate{Items: []string{"item3"}})
+	}, 5*time.Minute)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, editableOrder)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, []string{"item3"}, fulfilled, "the update replaces the order's items")
+}
+
+func TestOrderWorkflow_CancelInEditWindowRefundsPayment(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	var fulfilled []string
+	env := newEditWindowTestEnv(orderActivities, &fulfilled)
+	env.OnActivity(orderActivities.RefundPayment, mock.Anything, mock.Anything).Return(nil)
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(models.SignalCancel, nil)
+	}, 5*time.Minute)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, editableOrder)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	status := queryStatus(t, env)
+	assert.Equal(t, models.StatusCancelled, status.Status)
+	assert.Equal(t, "refunded", status.PaymentStatus)
+	assert.Empty(t, fulfilled)
+}
@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"testing"
+
+	"github.com/aswathylr-builds/temporal-order-processing/codec"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+)
+
+// benchmarkBatch returns a batch of n typical order payloads
+func benchmarkBatch(b *testing.B, n int) []*commonpb.Payload {
+	orders := make([]models.Order, n)
+	for i := range orders {
+		orders[i] = models.Order{ID: fmt.Sprintf("BENCH-%04d", i), Items: []string{"SKU-1", "SKU-2", "SKU-3"}, Amount: 99.5}
+	}
+	return testPayloads(b, orders...)
+}
+
+func BenchmarkEncryptionCodec_Encode(b *testing.B) {
+	for _, n := range []int{1, 100} {
+		b.Run(fmt.Sprintf("batch=%d", n), func(b *testing.B) {
+			encryption, err := codec.NewEncryptionCodec(testKey())
+			require.NoError(b, err)
+			payloads := benchmarkBatch(b, n)
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := encryption.Encode(payloads); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkEncryptionCodec_Decode(b *testing.B) {
+	for _, n := range []int{1, 100} {
+		b.Run(fmt.Sprintf("batch=%d", n), func(b *testing.B) {
+			encryption, err := codec.NewEncryptionCodec(testKey())
+			require.NoError(b, err)
+			encoded, err := encryption.Encode(benchmarkBatch(b, n))
+			require.NoError(b, err)
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := encryption.Decode(encoded); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkEncryptionCodec_EncodeNewCipherPerPayload is the baseline
+// EncryptionCodec improves on: a cipher and GCM built for every payload and
+// a fresh buffer for every marshaled payload
+func BenchmarkEncryptionCodec_EncodeNewCipherPerPayload(b *testing.B) {
+	for _, n := range []int{1, 100} {
+		b.Run(fmt.Sprintf("batch=%d", n), func(b *testing.B) {
+			key := testKey()
+			payloads := benchmarkBatch(b, n)
+			b.ReportAllocs()
+			for b.Loop() {
+				for _, payload := range payloads {
+					plaintext, err := payload.Marshal()
+					if err != nil {
+						b.Fatal(err)
+					}
+					block, err := aes.NewCipher(key)
+					if err != nil {
+						b.Fatal(err)
+					}
+					gcm, err := cipher.NewGCM(block)
+					if err != nil {
+						b.Fatal(err)
+					}
+					nonce := make([]byte, gcm.NonceSize())
+					if _, err := rand.Read(nonce); err != nil {
+						b.Fatal(err)
+					}
+					_ = gcm.Seal(nonce, nonce, plaintext, nil)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/events"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/store"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+)
+
+func TestProcessPayment_DeclineIsNonRetryable(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	orderActivities.PaymentDeclineOver = 500
+
+	_, err := orderActivities.ProcessPayment(context.Background(), models.PaymentRequest{
+		OrderID: "TEST-ERR-001",
+		Amount:  750,
+	})
+
+	var appErr *temporal.ApplicationError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, models.ErrTypePaymentDeclined, appErr.Type())
+	assert.True(t, appErr.NonRetryable())
+
+	var details models.PaymentDeclinedError
+	require.NoError(t, appErr.Details(&details))
+	assert.Equal(t, "insufficient_funds", details.DeclineCode)
+	assert.Equal(t, 750.0, details.Amount)
+}
+
+func TestProcessOrder_OutOfStock(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	orderActivities.OutOfStockItems = []string{"gpu"}
+
+	err := orderActivities.ProcessOrder(context.Background(), models.Order{
+		ID:    "TEST-ERR-002",
+		Items: []string{"laptop", "gpu"},
+	}, false)
+
+	var appErr *temporal.ApplicationError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, models.ErrTypeInventoryOutOfStock, appErr.Type())
+
+	var details models.InventoryOutOfStockError
+	require.NoError(t, appErr.Details(&details))
+	assert.Equal(t, []string{"gpu"}, details.Items)
+}
+
+func TestOrderWorkflow_PaymentDeclinedIsNotRetried(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	storeActivities := store.NewStoreActivities(nil)
+	env.RegisterActivity(orderActivities.ValidateOrder)
+	env.RegisterActivity(orderActivities.ProcessPayment)
+	env.RegisterActivity(events.NewEventActivities(nil).PublishOrderEvent)
+	env.RegisterActivity(storeActivities.PersistOrder)
+	env.RegisterActivity(storeActivities.UpdateOrderStatus)
+	env.RegisterWorkflow(workflows.OrderWorkflow)
+	env.RegisterWorkflow(workflows.PaymentWorkflow)
+	env.RegisterWorkflow(workflows.FailedOrderWorkflow)
+
+	declined := (&models.PaymentDeclinedError{OrderID: "TEST-ERR-003", DeclineCode: "card_declined"}).ApplicationError()
+	env.OnActivity(orderActivities.ValidateOrder, mock.Anything, mock.Anything).Return(&models.ValidationResponse{Valid: true}, nil)
+	env.OnActivity(orderActivities.ProcessPayment, mock.Anything, mock.Anything).Return(nil, declined).Once()
+
+	order := models.Order{
+		ID:     "TEST-ERR-003",
+		Items:  []string{"item1"},
+		Amount: 100.0,
+		Status: models.StatusPending,
+	}
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, order)
+
+	require.True(t, env.IsWorkflowCompleted())
+	err := env.GetWorkflowError()
+	var appErr *temporal.ApplicationError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, models.ErrTypePaymentDeclined, appErr.Type())
+
+	// One payment attempt and no dead-letter workflow for a business decline
+	env.AssertNumberOfCalls(t, "ProcessPayment", 1)
+	env.AssertWorkflowNotCalled(t, "FailedOrderWorkflow", mock.Anything, mock.Anything)
+
+	result, err := env.QueryWorkflow("getStatus")
+	require.NoError(t, err)
+	var status models.OrderStatus
+	require.NoError(t, result.Get(&status))
+	assert.Equal(t, "declined", status.PaymentStatus)
+}
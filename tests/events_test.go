@@ -0,0 +1,141 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/events"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/store"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+)
+
+// recordingPublisher captures published events in memory
+type recordingPublisher struct {
+	mu     sync.Mutex
+	events []models.OrderEvent
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event models.OrderEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event)
+	return nil
+}
+
+func (p *recordingPublisher) Close() error {
+	return nil
+}
+
+func (p *recordingPublisher) types() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	types := make([]string, 0, len(p.events))
+	for _, e := range p.events {
+		types = append(types, e.Type)
+	}
+	return types
+}
+
+func TestPublishOrderEvent_SetsSchemaVersion(t *testing.T) {
+	publisher := &recordingPublisher{}
+	eventActivities := events.NewEventActivities(publisher)
+
+	err := eventActivities.PublishOrderEvent(context.Background(), models.OrderEvent{
+		Type:    models.EventOrderCreated,
+		OrderID: "TEST-EVT-001",
+	})
+
+	require.NoError(t, err)
+	require.Len(t, publisher.events, 1)
+	assert.Equal(t, models.OrderEventSchemaVersion, publisher.events[0].SchemaVersion)
+}
+
+func TestOrderWorkflow_PublishesLifecycleEvents(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+
+	publisher := &recordingPublisher{}
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env.RegisterActivity(orderActivities.ValidateOrder)
+	env.RegisterActivity(orderActivities.ProcessPayment)
+	env.RegisterActivity(orderActivities.FulfillItem)
+	env.RegisterActivity(orderActivities.NotifyOrderComplete)
+	env.RegisterActivity(events.NewEventActivities(publisher).PublishOrderEvent)
+	storeActivities := store.NewStoreActivities(nil)
+	env.RegisterActivity(storeActivities.PersistOrder)
+	env.RegisterActivity(storeActivities.UpdateOrderStatus)
+	env.RegisterWorkflow(workflows.OrderWorkflow)
+	env.RegisterWorkflow(workflows.PaymentWorkflow)
+
+	env.OnActivity(orderActivities.ValidateOrder, mock.Anything, mock.Anything).Return(&models.ValidationResponse{Valid: true}, nil)
+	env.OnActivity(orderActivities.ProcessPayment, mock.Anything, mock.Anything).Return(&models.PaymentResponse{
+		Success:       true,
+		TransactionID: "TXN-EVT-123",
+	}, nil)
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	env.OnActivity(orderActivities.NotifyOrderComplete, mock.Anything, mock.Anything).Return(nil)
+
+	order := models.Order{
+		ID:        "TEST-EVT-002",
+		Items:     []string{"item1"},
+		Amount:    100.0,
+		Status:    models.StatusPending,
+		CreatedAt: time.Now(),
+	}
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, order)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, []string{
+		models.EventOrderCreated,
+		models.EventOrderPaid,
+		models.EventOrderCompleted,
+	}, publisher.types())
+	assert.Equal(t, "TXN-EVT-123", publisher.events[2].TransactionID)
+}
+
+func TestOrderWorkflow_PublishesFailedEventOnRejection(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+
+	publisher := &recordingPublisher{}
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env.RegisterActivity(orderActivities.ValidateOrder)
+	env.RegisterActivity(events.NewEventActivities(publisher).PublishOrderEvent)
+	storeActivities := store.NewStoreActivities(nil)
+	env.RegisterActivity(storeActivities.PersistOrder)
+	env.RegisterActivity(storeActivities.UpdateOrderStatus)
+	env.RegisterWorkflow(workflows.OrderWorkflow)
+
+	env.OnActivity(orderActivities.ValidateOrder, mock.Anything, mock.Anything).Return(&models.ValidationResponse{
+		Valid:   false,
+		Message: "Amount exceeds maximum allowed",
+	}, nil)
+
+	order := models.Order{
+		ID:     "TEST-EVT-003",
+		Items:  []string{"item1"},
+		Amount: 15000.0,
+		Status: models.StatusPending,
+	}
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, order)
+
+	require.True(t, env.IsWorkflowCompleted())
+	var appErr *temporal.ApplicationError
+	require.True(t, errors.As(env.GetWorkflowError(), &appErr))
+	assert.Equal(t, models.ErrTypeValidationRejected, appErr.Type())
+	assert.Equal(t, []string{models.EventOrderCreated, models.EventOrderFailed}, publisher.types())
+	assert.Equal(t, "Amount exceeds maximum allowed", publisher.events[1].Reason)
+}
@@ -0,0 +1,34 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aswathylr-builds/temporal-order-processing/codec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/temporal"
+)
+
+func TestEncryptedFailureConverter_HidesMessageAndStackTrace(t *testing.T) {
+	dataConverter, err := codec.NewEncryptionDataConverter(make([]byte, 32))
+	require.NoError(t, err)
+	failures := codec.NewEncryptedFailureConverter(dataConverter)
+
+	cause := temporal.NewApplicationError("card 4111-1111-1111-1111 declined", "PaymentDeclined", "TEST-FAIL-001")
+	failure := failures.ErrorToFailure(cause)
+
+	assert.Equal(t, "Encoded failure", failure.GetMessage())
+	assert.Empty(t, failure.GetStackTrace())
+	require.NotNil(t, failure.GetEncodedAttributes())
+	assert.Equal(t, codec.MetadataEncodingEncrypted, string(failure.GetEncodedAttributes().GetMetadata()["encoding"]))
+	assert.NotContains(t, string(failure.GetEncodedAttributes().GetData()), "4111")
+
+	var appErr *temporal.ApplicationError
+	require.True(t, errors.As(failures.FailureToError(failure), &appErr))
+	assert.Equal(t, "card 4111-1111-1111-1111 declined", appErr.Message())
+	assert.Equal(t, "PaymentDeclined", appErr.Type())
+	var orderID string
+	require.NoError(t, appErr.Details(&orderID))
+	assert.Equal(t, "TEST-FAIL-001", orderID)
+}
@@ -0,0 +1,230 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/featureflags"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// staticFlags is a provider defining a fixed set of flags
+type staticFlags map[string]bool
+
+func (f staticFlags) Lookup(ctx context.Context, name string) (bool, bool, error) {
+	enabled, ok := f[name]
+	return enabled, ok, nil
+}
+
+// useFlags makes workflows read the given flags for the rest of the test
+func useFlags(t *testing.T, flags map[string]bool) {
+	previous := featureflags.Default()
+	featureflags.SetDefault(featureflags.New(staticFlags(flags)))
+	t.Cleanup(func() { featureflags.SetDefault(previous) })
+}
+
+func TestFlags_FirstDefiningProviderWins(t *testing.T) {
+	t.Setenv("FEATURE_FRAUD_CHECK", "true")
+	flags := featureflags.New(
+		featureflags.NewEnvProvider(),
+		staticFlags{featureflags.FraudCheck: false, featureflags.SMSNotifications: true},
+	)
+	ctx := context.Background()
+
+	assert.True(t, flags.Enabled(ctx, featureflags.FraudCheck), "environment overrides later providers")
+	assert.True(t, flags.Enabled(ctx, featureflags.SMSNotifications))
+	assert.True(t, flags.Enabled(ctx, featureflags.ChildWorkflowPayment), "undefined flags use Defaults")
+	assert.False(t, flags.Enabled(ctx, "unknown-flag"))
+}
+
+func TestFlags_ProviderErrorFallsThrough(t *testing.T) {
+	t.Setenv("FEATURE_FRAUD_CHECK", "maybe")
+	var reported error
+	flags := featureflags.New(featureflags.NewEnvProvider(), staticFlags{featureflags.FraudCheck: true})
+	flags.OnError = func(name string, err error) { reported = err }
+
+	assert.True(t, flags.Enabled(context.Background(), featureflags.FraudCheck))
+	assert.ErrorContains(t, reported, "FEATURE_FRAUD_CHECK")
+}
+
+func TestFileProvider_ReloadsWhenChanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.yaml")
+	provider := featureflags.NewFileProvider(path)
+	ctx := context.Background()
+
+	_, ok, err := provider.Lookup(ctx, featureflags.FraudCheck)
+	require.NoError(t, err)
+	assert.False(t, ok, "a missing file defines no flags")
+
+	require.NoError(t, os.WriteFile(path, []byte("fraud-check: true\n"), 0600))
+	enabled, ok, err := provider.Lookup(ctx, featureflags.FraudCheck)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, enabled)
+
+	require.NoError(t, os.WriteFile(path, []byte("fraud-check: false\n"), 0600))
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, later, later))
+	enabled, _, err = provider.Lookup(ctx, featureflags.FraudCheck)
+	require.NoError(t, err)
+	assert.False(t, enabled)
+}
+
+func TestRemoteProvider_CachesAndKeepsLastFlagsOnFailure(t *testing.T) {
+	var requests atomic.Int32
+	var failing atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"fraud-check": true}`))
+	}))
+	defer server.Close()
+
+	provider := featureflags.NewRemoteProvider(server.URL, time.Hour)
+	ctx := context.Background()
+
+	enabled, ok, err := provider.Lookup(ctx, featureflags.FraudCheck)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, enabled)
+
+	_, _, err = provider.Lookup(ctx, featureflags.FraudCheck)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), requests.Load(), "flags are cached for the refresh interval")
+
+	failing.Store(true)
+	provider.RefreshInterval = 0
+	enabled, ok, err = provider.Lookup(ctx, featureflags.FraudCheck)
+	assert.ErrorContains(t, err, "503")
+	assert.True(t, ok)
+	assert.True(t, enabled, "the last fetched flags are kept")
+}
+
+func TestOrderWorkflow_FraudCheckFlagRejectsSuspiciousOrder(t *testing.T) {
+	useFlags(t, map[string]bool{featureflags.FraudCheck: true})
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newFulfillmentTestEnv(orderActivities)
+	env.RegisterActivity(orderActivities.CheckFraud)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:     "TEST-FRAUD-001",
+		Items:  []string{"item1"},
+		Amount: 9000.0,
+		Status: models.StatusPending,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	err := env.GetWorkflowError()
+	var appErr *temporal.ApplicationError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, models.ErrTypeFraudSuspected, appErr.Type())
+	env.AssertNotCalled(t, "ProcessPayment", mock.Anything, mock.Anything)
+}
+
+func TestOrderWorkflow_FraudCheckSkippedByDefault(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newFulfillmentTestEnv(orderActivities)
+	env.RegisterActivity(orderActivities.CheckFraud)
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:     "TEST-FRAUD-002",
+		Items:  []string{"item1"},
+		Amount: 9000.0,
+		Status: models.StatusPending,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	env.AssertNotCalled(t, "CheckFraud", mock.Anything, mock.Anything)
+}
+
+func TestOrderWorkflow_ChildPaymentFlagOffUsesActivity(t *testing.T) {
+	useFlags(t, map[string]bool{featureflags.ChildWorkflowPayment: false})
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newFulfillmentTestEnv(orderActivities)
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	childStarted := false
+	env.SetOnChildWorkflowStartedListener(func(info *workflow.Info, ctx workflow.Context, args converter.EncodedValues) {
+		childStarted = true
+	})
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:     "TEST-FLAG-PAY-001",
+		Items:  []string{"item1"},
+		Amount: 100.0,
+		Status: models.StatusPending,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	assert.False(t, childStarted, "payment ran as an activity, not a child workflow")
+	env.AssertCalled(t, "ProcessPayment", mock.Anything, mock.Anything)
+}
+
+func TestCheckFraud_ScoresOrder(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+
+	result, err := orderActivities.CheckFraud(context.Background(), models.Order{ID: "TEST-FRAUD-003", Items: []string{"a", "b"}, Amount: 2000})
+	require.NoError(t, err)
+	assert.InDelta(t, 0.5, result.Score, 0.001)
+}
+
+func TestNotifyOrderComplete_NoChannelsEnabled(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	orderActivities.Flags = featureflags.New(staticFlags{featureflags.EmailNotifications: false})
+
+	assert.NoError(t, orderActivities.NotifyOrderComplete(context.Background(), models.Order{ID: "TEST-NOTIFY-001"}))
+}
+
+func TestOrderWorkflow_SearchAttributesFlagUpsertsStatus(t *testing.T) {
+	useFlags(t, map[string]bool{featureflags.SearchAttributes: true})
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newFulfillmentTestEnv(orderActivities)
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	var statuses []string
+	var expedited bool
+	env.OnUpsertTypedSearchAttributes(mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		attributes := args.Get(0).(temporal.SearchAttributes)
+		status, _ := attributes.GetKeyword(workflows.OrderStatusKey)
+		statuses = append(statuses, status)
+		expedited, _ = attributes.GetBool(workflows.OrderExpeditedKey)
+	})
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(models.SignalExpedite, nil)
+	}, 0)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:     "TEST-SEARCH-001",
+		Items:  []string{"item1"},
+		Amount: 100.0,
+		Status: models.StatusPending,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.NotEmpty(t, statuses)
+	assert.Equal(t, string(models.StatusPending), statuses[0])
+	assert.Equal(t, string(models.StatusCompleted), statuses[len(statuses)-1])
+	assert.True(t, expedited)
+}
@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/aswathylr-builds/temporal-order-processing/codec"
+	"github.com/aswathylr-builds/temporal-order-processing/config"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+)
+
+// customerOrder is an order input carrying customer PII alongside the order
+type customerOrder struct {
+	models.Order
+	CustomerEmail string `json:"customer_email"`
+	Shipping      struct {
+		Address string `json:"address"`
+		Method  string `json:"method"`
+	} `json:"shipping"`
+	PaymentToken string `json:"payment_token"`
+}
+
+func TestFieldEncryptionCodec_EncryptsOnlyDesignatedFields(t *testing.T) {
+	fields := codec.NewFieldEncryptionCodec(codec.Keyring{Current: codec.StaticKey(testKey())}, codec.DefaultEncryptedFields)
+	input := customerOrder{Order: models.Order{ID: "TEST-FLE-001", Status: models.StatusPending, Amount: 42}}
+	input.CustomerEmail = "jane@example.com"
+	input.Shipping.Address = "1 Main St"
+	input.Shipping.Method = "ground"
+	input.PaymentToken = "tok_visa_4242"
+
+	payload, err := converter.GetDefaultDataConverter().ToPayload(input)
+	require.NoError(t, err)
+	encoded, err := fields.Encode([]*commonpb.Payload{payload})
+	require.NoError(t, err)
+	data := string(encoded[0].Data)
+	assert.Contains(t, data, "TEST-FLE-001")
+	assert.Contains(t, data, models.StatusPending)
+	assert.Contains(t, data, "ground")
+	assert.NotContains(t, data, "jane@example.com")
+	assert.NotContains(t, data, "1 Main St")
+	assert.NotContains(t, data, "tok_visa_4242")
+	assert.Equal(t, "address,customer_email,payment_token", string(encoded[0].Metadata[codec.MetadataEncryptedFields]))
+
+	// The encrypted fields are listed in the payload, so a codec configured
+	// with other fields still decrypts them
+	decoded, err := codec.NewFieldEncryptionCodec(codec.Keyring{Current: codec.StaticKey(testKey())}, []string{"phone"}).Decode(encoded)
+	require.NoError(t, err)
+	assert.NotContains(t, decoded[0].Metadata, codec.MetadataEncryptedFields)
+	var output customerOrder
+	require.NoError(t, converter.GetDefaultDataConverter().FromPayload(decoded[0], &output))
+	assert.Equal(t, input, output)
+}
+
+func TestFieldEncryptionCodec_LeavesPayloadsWithoutFieldsUntouched(t *testing.T) {
+	fields := codec.NewFieldEncryptionCodec(codec.Keyring{Current: codec.StaticKey(testKey())}, codec.DefaultEncryptedFields)
+	payloads := testPayloads(t, models.Order{ID: "TEST-FLE-002"})
+
+	encoded, err := fields.Encode(payloads)
+	require.NoError(t, err)
+	assert.Same(t, payloads[0], encoded[0])
+}
+
+func TestConfig_FieldEncryptionMode(t *testing.T) {
+	t.Setenv("ENCRYPTION_ENABLED", "true")
+	t.Setenv("ENCRYPTION_MODE", "fields")
+	t.Setenv("ENCRYPTION_KEY_FILE", filepath.Join(t.TempDir(), "encryption.key"))
+	cfg, err := config.Load("")
+	require.NoError(t, err)
+
+	dataConverter, err := cfg.DataConverter(t.Context())
+	require.NoError(t, err)
+	input := customerOrder{Order: models.Order{ID: "TEST-FLE-003"}, CustomerEmail: "jane@example.com"}
+	payload, err := dataConverter.ToPayload(input)
+	require.NoError(t, err)
+	assert.Contains(t, string(payload.Data), "TEST-FLE-003")
+	assert.NotContains(t, string(payload.Data), "jane@example.com")
+
+	var output customerOrder
+	require.NoError(t, dataConverter.FromPayload(payload, &output))
+	assert.Equal(t, input, output)
+}
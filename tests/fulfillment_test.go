@@ -0,0 +1,278 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/events"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/store"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+)
+
+func newFulfillmentTestEnv(orderActivities *activities.OrderActivities) *testsuite.TestWorkflowEnvironment {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+
+	storeActivities := store.NewStoreActivities(nil)
+	env.RegisterActivity(orderActivities.ValidateOrder)
+	env.RegisterActivity(orderActivities.ProcessPayment)
+	env.RegisterActivity(orderActivities.FulfillItem)
+	env.RegisterActivity(orderActivities.NotifyOrderComplete)
+	env.RegisterActivity(events.NewEventActivities(nil).PublishOrderEvent)
+	env.RegisterActivity(storeActivities.PersistOrder)
+	env.RegisterActivity(storeActivities.UpdateOrderStatus)
+	env.RegisterWorkflow(workflows.OrderWorkflow)
+	env.RegisterWorkflow(workflows.PaymentWorkflow)
+
+	env.OnActivity(orderActivities.ValidateOrder, mock.Anything, mock.Anything).Return(&models.ValidationResponse{Valid: true}, nil)
+	env.OnActivity(orderActivities.ProcessPayment, mock.Anything, mock.Anything).Return(&models.PaymentResponse{
+		Success:       true,
+		TransactionID: "TXN-FULFILL-123",
+	}, nil)
+	env.OnActivity(orderActivities.NotifyOrderComplete, mock.Anything, mock.Anything).Return(nil)
+	return env
+}
+
+func TestOrderWorkflow_FulfillsItemsWithinParallelismCap(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newFulfillmentTestEnv(orderActivities)
+
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, order models.Order, item string, isExpedited bool) error {
+			mu.Lock()
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			mu.Unlock()
+
+			time.Sleep(20 * time.Millisecond)
+
+			mu.Lock()
+			running--
+			mu.Unlock()
+			return nil
+		})
+
+	order := models.Order{
+		ID:                     "TEST-FULFILL-001",
+		Items:                  []string{"item1", "item2", "item3", "item4", "item5"},
+		Amount:                 50.0,
+		Status:                 models.StatusPending,
+		FulfillmentParallelism: 2,
+	}
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, order)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	env.AssertNumberOfCalls(t, "FulfillItem", 5)
+	assert.LessOrEqual(t, maxRunning, 2)
+
+	result, err := env.QueryWorkflow("getStatus")
+	require.NoError(t, err)
+	var status models.OrderStatus
+	require.NoError(t, result.Get(&status))
+	require.Len(t, status.ItemResults, 5)
+	for _, item := range status.ItemResults {
+		assert.Equal(t, models.ItemFulfilled, item.Status)
+	}
+}
+
+func TestOrderWorkflow_HistoryQueryRecordsStatusChanges(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newFulfillmentTestEnv(orderActivities)
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:     "TEST-HISTORY-001",
+		Items:  []string{"item1"},
+		Amount: 50.0,
+		Status: models.StatusPending,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+
+	result, err := env.QueryWorkflow("getHistory")
+	require.NoError(t, err)
+	var history []models.StatusChange
+	require.NoError(t, result.Get(&history))
+	require.GreaterOrEqual(t, len(history), 3)
+	assert.Equal(t, models.StatusPending, history[0].Status)
+	assert.Equal(t, models.StatusCompleted, history[len(history)-1].Status)
+	for i := 1; i < len(history); i++ {
+		assert.False(t, history[i].At.Before(history[i-1].At), "changes are in order")
+	}
+}
+
+func TestOrderWorkflow_AggregatesItemFailures(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	orderActivities.OutOfStockItems = []string{"gpu", "monitor"}
+	orderActivities.ItemFulfillmentTime = time.Millisecond
+	env := newFulfillmentTestEnv(orderActivities)
+
+	order := models.Order{
+		ID:     "TEST-FULFILL-002",
+		Items:  []string{"gpu", "laptop", "monitor"},
+		Amount: 1500.0,
+		Status: models.StatusPending,
+	}
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, order)
+
+	require.True(t, env.IsWorkflowCompleted())
+	err := env.GetWorkflowError()
+	require.Error(t, err)
+
+	var appErr *temporal.ApplicationError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, models.ErrTypeInventoryOutOfStock, appErr.Type())
+	var details models.InventoryOutOfStockError
+	require.NoError(t, appErr.Details(&details))
+	assert.Equal(t, []string{"gpu", "monitor"}, details.Items)
+
+	result, err := env.QueryWorkflow("getStatus")
+	require.NoError(t, err)
+	var status models.OrderStatus
+	require.NoError(t, result.Get(&status))
+	require.Len(t, status.ItemResults, 3)
+	assert.Equal(t, models.ItemFailed, status.ItemResults[0].Status)
+	assert.Equal(t, models.ItemFulfilled, status.ItemResults[1].Status)
+	assert.Equal(t, models.ItemFailed, status.ItemResults[2].Status)
+}
+
+func TestOrderWorkflow_LargeOrderPartiallyCompletes(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	orderActivities.OutOfStockItems = []string{"gpu"}
+	orderActivities.ItemFulfillmentTime = 3 * time.Millisecond
+	env := newFulfillmentTestEnv(orderActivities)
+	env.RegisterWorkflow(workflows.ItemFulfillmentWorkflow)
+	env.RegisterActivity(orderActivities.ReserveItem)
+	env.RegisterActivity(orderActivities.PickItem)
+	env.RegisterActivity(orderActivities.PackItem)
+
+	items := make([]string, workflows.ItemChildWorkflowThreshold+1)
+	for i := range items {
+		items[i] = fmt.Sprintf("item%d", i)
+	}
+	items[3] = "gpu"
+	order := models.Order{
+		ID:     "TEST-FULFILL-003",
+		Items:  items,
+		Amount: 1100.0,
+		Status: models.StatusPending,
+	}
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, order)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+
+	result, err := env.QueryWorkflow("getStatus")
+	require.NoError(t, err)
+	var status models.OrderStatus
+	require.NoError(t, result.Get(&status))
+	assert.Equal(t, models.StatusPartiallyCompleted, status.Status)
+	require.Len(t, status.ItemResults, len(items))
+	for i, item := range status.ItemResults {
+		if i == 3 {
+			assert.Equal(t, models.ItemFailed, item.Status)
+			assert.Contains(t, item.Error, "out of stock")
+			continue
+		}
+		assert.Equal(t, models.ItemFulfilled, item.Status)
+		assert.Equal(t, models.ItemStepPacked, item.Step)
+	}
+}
+
+func TestItemFulfillmentWorkflow_StopsAtReservation(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	orderActivities.OutOfStockItems = []string{"gpu"}
+	env.RegisterActivity(orderActivities.ReserveItem)
+	env.RegisterActivity(orderActivities.PickItem)
+	env.RegisterActivity(orderActivities.PackItem)
+	env.RegisterWorkflow(workflows.ItemFulfillmentWorkflow)
+
+	env.ExecuteWorkflow(workflows.ItemFulfillmentWorkflow, models.ItemFulfillmentRequest{
+		OrderID: "TEST-FULFILL-004",
+		Item:    "gpu",
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	err := env.GetWorkflowError()
+	require.Error(t, err)
+	var appErr *temporal.ApplicationError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, models.ErrTypeInventoryOutOfStock, appErr.Type())
+
+	// Nothing was reserved, so the item never reached picking
+	result, err := env.QueryWorkflow("getStatus")
+	require.NoError(t, err)
+	var status models.ItemFulfillment
+	require.NoError(t, result.Get(&status))
+	assert.Equal(t, models.ItemFailed, status.Status)
+	assert.Empty(t, status.Step)
+}
+
+func TestOrderWorkflow_ReturnsOrderResult(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newFulfillmentTestEnv(orderActivities)
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		After(time.Minute).Return(nil)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:     "TEST-RESULT-001",
+		Items:  []string{"item1"},
+		Amount: 25.0,
+		Status: models.StatusPending,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var result *models.OrderResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	require.NotNil(t, result)
+	assert.Equal(t, "TEST-RESULT-001", result.OrderID)
+	assert.Equal(t, models.StatusCompleted, result.Status)
+	assert.Equal(t, "TXN-FULFILL-123", result.TransactionID)
+	assert.Equal(t, "SHIP-TEST-RESULT-001", result.ShipmentID)
+	assert.GreaterOrEqual(t, result.Duration, time.Minute)
+}
+
+func TestOrderWorkflow_ExecutionsBeforeOrderResultReturnNone(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newFulfillmentTestEnv(orderActivities)
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	env.OnGetVersion("order-result", workflow.DefaultVersion, 1).Return(workflow.DefaultVersion)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:     "TEST-RESULT-002",
+		Items:  []string{"item1"},
+		Amount: 25.0,
+		Status: models.StatusPending,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	// The workflow completes with no payload, as it did before returning a result
+	var result *models.OrderResult
+	assert.ErrorIs(t, env.GetWorkflowResult(&result), temporal.ErrNoData)
+}
@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/testsuite"
+)
+
+// giftCardCalls records, in order, the gift card, payment, and fulfillment
+// activities an order workflow runs, and the payments it requests
+type giftCardCalls struct {
+	steps    []string
+	payments []models.PaymentRequest
+}
+
+// newGiftCardTestEnv returns an order environment that runs the real gift
+// card activities, recording calls in calls, with item2 out of stock
+func newGiftCardTestEnv(t *testing.T, orderActivities *activities.OrderActivities, calls *giftCardCalls) *testsuite.TestWorkflowEnvironment {
+	orderActivities.OutOfStockItems = []string{"item2"}
+	env := newDynamicConfigTestEnv(orderActivities, models.DynamicConfig{})
+	env.RegisterActivity(orderActivities.HoldGiftCardBalance)
+	env.RegisterActivity(orderActivities.CaptureGiftCard)
+	env.RegisterActivity(orderActivities.ReleaseGiftCard)
+	env.SetOnActivityStartedListener(func(info *activity.Info, ctx context.Context, args converter.EncodedValues) {
+		switch info.ActivityType.Name {
+		case "HoldGiftCardBalance", "CaptureGiftCard", "ReleaseGiftCard", "FulfillItem":
+			calls.steps = append(calls.steps, info.ActivityType.Name)
+		case "ProcessPayment":
+			calls.steps = append(calls.steps, info.ActivityType.Name)
+			var req models.PaymentRequest
+			require.NoError(t, args.Get(&req))
+			calls.payments = append(calls.payments, req)
+		}
+	})
+	return env
+}
+
+func giftCardOrder(items []string, tenders ...models.Tender) models.Order {
+	return models.Order{
+		ID:      "TEST-GIFT-CARD-001",
+		Items:   items,
+		Amount:  100.0,
+		Status:  models.StatusPending,
+		Tenders: tenders,
+	}
+}
+
+func TestOrderWorkflow_HoldsGiftCardAndCapturesAfterFulfillment(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	var calls giftCardCalls
+	env := newGiftCardTestEnv(t, orderActivities, &calls)
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, giftCardOrder([]string{"item1"},
+		models.Tender{Method: models.TenderGiftCard, Amount: 30, Card: "GC-1"},
+		models.Tender{Method: models.TenderCard, Amount: 70},
+	))
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, []string{"HoldGiftCardBalance", "ProcessPayment", "FulfillItem", "CaptureGiftCard"}, calls.steps)
+	assert.Equal(t, []models.PaymentRequest{{OrderID: "TEST-GIFT-CARD-001", Amount: 70, Method: models.TenderCard}}, calls.payments,
+		"payment charges only what the gift card does not cover")
+}
+
+func TestOrderWorkflow_GiftCardCoveringOrderIsNotCharged(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	var calls giftCardCalls
+	env := newGiftCardTestEnv(t, orderActivities, &calls)
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, giftCardOrder([]string{"item1"},
+		models.Tender{Method: models.TenderGiftCard, Amount: 100, Card: "GC-1"},
+	))
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, []string{"HoldGiftCardBalance", "FulfillItem", "CaptureGiftCard"}, calls.steps)
+	assert.Equal(t, "completed", queryStatus(t, env).PaymentStatus)
+}
+
+func TestOrderWorkflow_FailedFulfillmentReleasesGiftCardHold(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	var calls giftCardCalls
+	env := newGiftCardTestEnv(t, orderActivities, &calls)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, giftCardOrder([]string{"item2"},
+		models.Tender{Method: models.TenderGiftCard, Amount: 40, Card: "GC-1"},
+		models.Tender{Method: models.TenderCard, Amount: 60},
+	))
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.Error(t, env.GetWorkflowError())
+	assert.Equal(t, models.StatusFailed, queryStatus(t, env).Status)
+	assert.Equal(t, []string{"HoldGiftCardBalance", "ProcessPayment", "FulfillItem", "ReleaseGiftCard"}, calls.steps)
+}
+
+func TestOrderWorkflow_DeclinedGiftCardHoldFailsOrderBeforePayment(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	orderActivities.GiftCardBalance = 25
+	var calls giftCardCalls
+	env := newGiftCardTestEnv(t, orderActivities, &calls)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, giftCardOrder([]string{"item1"},
+		models.Tender{Method: models.TenderGiftCard, Amount: 20, Card: "GC-1"},
+		models.Tender{Method: models.TenderGiftCard, Amount: 30, Card: "GC-2"},
+		models.Tender{Method: models.TenderCard, Amount: 50},
+	))
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.Error(t, env.GetWorkflowError())
+	assert.Contains(t, env.GetWorkflowError().Error(), "insufficient_gift_card_balance")
+	assert.Equal(t, []string{"HoldGiftCardBalance", "HoldGiftCardBalance", "ReleaseGiftCard"}, calls.steps,
+		"the first card's hold is released when the second is declined")
+}
@@ -0,0 +1,483 @@
+package tests
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/buildinfo"
+	"github.com/aswathylr-builds/temporal-order-processing/health"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/client"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// reportWorkerMetrics emits the metrics the SDK reports for a running worker
+func reportWorkerMetrics(handler client.MetricsHandler, activitySlotsAvailable float64) {
+	queue := handler.WithTags(map[string]string{"task_queue": "order-processing-queue"})
+	workflows := queue.WithTags(map[string]string{"worker_type": "WorkflowWorker"})
+	workflows.Gauge("temporal_worker_task_slots_used").Update(1)
+	workflows.Gauge("temporal_worker_task_slots_available").Update(99)
+	activities := queue.WithTags(map[string]string{"worker_type": "ActivityWorker"})
+	activities.Gauge("temporal_worker_task_slots_used").Update(10 - activitySlotsAvailable)
+	activities.Gauge("temporal_worker_task_slots_available").Update(activitySlotsAvailable)
+	queue.WithTags(map[string]string{"poller_type": "workflow_task"}).Gauge("temporal_num_pollers").Update(2)
+	queue.WithTags(map[string]string{"poller_type": "activity_task"}).Gauge("temporal_num_pollers").Update(2)
+	handler.Counter("temporal_sticky_cache_hit").Inc(3)
+	handler.Counter("temporal_sticky_cache_miss").Inc(1)
+	handler.Gauge("temporal_sticky_cache_size").Update(7)
+}
+
+func TestWorkerStats_ReportsSlotsPollersAndStickyCache(t *testing.T) {
+	stats := health.NewWorkerStats(client.MetricsNopHandler)
+	reportWorkerMetrics(stats, 6)
+
+	report := stats.Report()
+	assert.Equal(t, []health.SlotUsage{
+		{TaskQueue: "order-processing-queue", WorkerType: "ActivityWorker", Used: 4, Available: 6},
+		{TaskQueue: "order-processing-queue", WorkerType: "WorkflowWorker", Used: 1, Available: 99},
+	}, report.Slots)
+	assert.Equal(t, []health.PollerCount{
+		{TaskQueue: "order-processing-queue", PollerType: "activity_task", Count: 2},
+		{TaskQueue: "order-processing-queue", PollerType: "workflow_task", Count: 2},
+	}, report.Pollers)
+	assert.Equal(t, health.StickyCacheStats{Hits: 3, Misses: 1, HitRate: 0.75, Size: 7}, report.StickyCache)
+
+	assert.Equal(t, health.StatusHealthy, health.NewWorkerChecker(stats).Check(context.Background()).Status)
+}
+
+func TestWorkerChecker_DegradedWhenSlotsExhausted(t *testing.T) {
+	stats := health.NewWorkerStats(nil)
+	checker := health.NewWorkerChecker(stats)
+	assert.Equal(t, health.StatusDegraded, checker.Check(context.Background()).Status, "no pollers yet")
+
+	reportWorkerMetrics(stats, 0)
+	result := checker.Check(context.Background())
+	assert.Equal(t, health.StatusDegraded, result.Status)
+	assert.Contains(t, result.Message, "ActivityWorker slots on order-processing-queue exhausted")
+}
+
+func TestHealthServer_WorkerEndpoint(t *testing.T) {
+	server := health.NewServer(0)
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health/worker", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	stats := health.NewWorkerStats(nil)
+	reportWorkerMetrics(stats, 6)
+	server.RegisterWorkerStats(stats)
+
+	recorder = httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health/worker", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var report health.WorkerReport
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&report))
+	assert.Len(t, report.Slots, 2)
+	assert.Equal(t, 0.75, report.StickyCache.HitRate)
+
+	recorder = httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health", nil))
+	var overall health.HealthResponse
+	require.NoError(t, json.NewDecoder(recorder.Body).Decode(&overall))
+	assert.Equal(t, health.StatusHealthy, overall.Components["worker"].Status)
+}
+
+// countingChecker is a Checker that counts how often it runs
+type countingChecker struct {
+	checks atomic.Int32
+}
+
+func (c *countingChecker) Name() string { return "counting" }
+
+func (c *countingChecker) Check(ctx context.Context) health.ComponentHealth {
+	c.checks.Add(1)
+	return health.ComponentHealth{Status: health.StatusHealthy}
+}
+
+func TestHealthServer_CachedResults(t *testing.T) {
+	checker := &countingChecker{}
+	server := health.NewServer(0)
+	server.CacheTTL = time.Hour
+	server.RegisterChecker(checker)
+
+	for _, path := range []string{"/health", "/health/ready", "/health", "/health/ready"} {
+		recorder := httptest.NewRecorder()
+		server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	}
+	assert.Equal(t, int32(1), checker.checks.Load())
+
+	server.CacheTTL = 0
+	server.Handler().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(t, int32(2), checker.checks.Load())
+}
+
+func TestHealthServer_BackgroundRefresh(t *testing.T) {
+	checker := &countingChecker{}
+	server := health.NewServer(0)
+	server.CacheTTL = 10 * time.Millisecond
+	server.RegisterChecker(checker)
+	require.NoError(t, server.Start())
+	defer server.Shutdown(context.Background())
+
+	assert.Eventually(t, func() bool { return checker.checks.Load() >= 3 }, time.Second, 5*time.Millisecond)
+}
+
+// stubChecker is a Checker that always reports status
+type stubChecker struct {
+	name   string
+	status health.Status
+}
+
+func (c stubChecker) Name() string { return c.name }
+
+func (c stubChecker) Check(ctx context.Context) health.ComponentHealth {
+	return health.ComponentHealth{Status: c.status}
+}
+
+func TestHealthServer_InformationalCheckerOnlyDegrades(t *testing.T) {
+	server := health.NewServer(0)
+	server.RegisterChecker(stubChecker{name: "temporal", status: health.StatusHealthy})
+	server.RegisterCheckerWithCriticality(stubChecker{name: "wiremock", status: health.StatusUnhealthy}, health.CriticalityInformational)
+
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var overall health.HealthResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &overall))
+	assert.Equal(t, health.StatusDegraded, overall.Status)
+	assert.Equal(t, health.CriticalityInformational, overall.Components["wiremock"].Criticality)
+
+	recorder = httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	server.RegisterChecker(stubChecker{name: "database", status: health.StatusUnhealthy})
+	recorder = httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+}
+
+func TestHTTPChecker_LatencyThresholds(t *testing.T) {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+	}))
+	defer slow.Close()
+
+	checker := health.NewHTTPChecker("slow", slow.URL)
+	assert.Equal(t, health.StatusHealthy, checker.Check(context.Background()).Status)
+
+	checker.Thresholds = health.LatencyThresholds{Warn: 10 * time.Millisecond}
+	result := checker.Check(context.Background())
+	assert.Equal(t, health.StatusDegraded, result.Status)
+	assert.Contains(t, result.Message, "warn threshold")
+
+	checker.Thresholds.Critical = 15 * time.Millisecond
+	assert.Equal(t, health.StatusUnhealthy, checker.Check(context.Background()).Status)
+
+	checker.Thresholds = health.LatencyThresholds{Warn: time.Minute}
+	assert.Equal(t, health.StatusHealthy, checker.Check(context.Background()).Status)
+}
+
+func TestHealthServer_GRPCHealth(t *testing.T) {
+	server := health.NewServer(0)
+	server.RegisterChecker(stubChecker{name: "temporal", status: health.StatusHealthy})
+	server.RegisterCheckerWithCriticality(stubChecker{name: "wiremock", status: health.StatusUnhealthy}, health.CriticalityInformational)
+	handler := server.GRPCHandler()
+
+	response, err := handler.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, response.GetStatus())
+
+	response, err = handler.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "wiremock"})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, response.GetStatus())
+
+	_, err = handler.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "billing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	server.RegisterChecker(stubChecker{name: "database", status: health.StatusUnhealthy})
+	response, err = handler.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, response.GetStatus())
+}
+
+func TestHealthServer_Metrics(t *testing.T) {
+	server := health.NewServer(0)
+	server.RegisterChecker(stubChecker{name: "temporal", status: health.StatusHealthy})
+	server.RegisterCheckerWithCriticality(stubChecker{name: "wiremock", status: health.StatusUnhealthy}, health.CriticalityInformational)
+
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	body := recorder.Body.String()
+	assert.Contains(t, body, `health_component_status{component="temporal",criticality="critical",status="healthy"} 1`)
+	assert.Contains(t, body, `health_component_status{component="wiremock",criticality="informational",status="healthy"} 0`)
+	assert.Contains(t, body, `health_component_status{component="wiremock",criticality="informational",status="unhealthy"} 1`)
+	assert.Contains(t, body, `health_check_duration_seconds_count{component="temporal"} 1`)
+	assert.Contains(t, body, "health_uptime_seconds ")
+}
+
+func TestCheckBuilder_IntervalTimeoutAndTags(t *testing.T) {
+	var checks atomic.Int32
+	server := health.NewServer(0)
+	server.RegisterCheck(health.NewCheck("cache", func(ctx context.Context) health.ComponentHealth {
+		checks.Add(1)
+		return health.ComponentHealth{Status: health.StatusUnhealthy}
+	}).
+		WithInterval(time.Hour).
+		WithCriticality(health.CriticalityInformational).
+		WithTags(map[string]string{"team": "fulfilment"}))
+	server.RegisterCheck(health.NewCheck("queue", func(ctx context.Context) health.ComponentHealth {
+		<-ctx.Done()
+		return health.ComponentHealth{Status: health.StatusHealthy}
+	}).WithTimeout(10 * time.Millisecond))
+
+	var overall health.HealthResponse
+	for range 2 {
+		recorder := httptest.NewRecorder()
+		server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health", nil))
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &overall))
+	}
+	assert.Equal(t, int32(1), checks.Load())
+	assert.Equal(t, health.CriticalityInformational, overall.Components["cache"].Criticality)
+	assert.Equal(t, map[string]string{"team": "fulfilment"}, overall.Components["cache"].Tags)
+	assert.Equal(t, health.StatusUnhealthy, overall.Components["queue"].Status)
+	assert.Contains(t, overall.Components["queue"].Message, "timed out")
+	assert.Equal(t, health.StatusUnhealthy, overall.Status)
+}
+
+func TestCheckerFunc(t *testing.T) {
+	checker := health.CheckerFunc("database", func(ctx context.Context) health.ComponentHealth {
+		return health.ComponentHealth{Status: health.StatusDegraded}
+	})
+	assert.Equal(t, "database", checker.Name())
+	assert.Equal(t, health.StatusDegraded, checker.Check(context.Background()).Status)
+}
+
+// sequenceChecker reports statuses in order, then the last one repeatedly
+type sequenceChecker struct {
+	statuses []health.Status
+	next     int
+}
+
+func (c *sequenceChecker) Name() string { return "flaky" }
+
+func (c *sequenceChecker) Check(ctx context.Context) health.ComponentHealth {
+	status := c.statuses[min(c.next, len(c.statuses)-1)]
+	c.next++
+	return health.ComponentHealth{Status: status}
+}
+
+func TestHealthServer_FlapDetection(t *testing.T) {
+	server := health.NewServer(0)
+	server.FailureThreshold = 3
+	server.RecoveryThreshold = 2
+	server.RegisterChecker(&sequenceChecker{statuses: []health.Status{
+		health.StatusUnhealthy, health.StatusHealthy, // blip
+		health.StatusUnhealthy, health.StatusUnhealthy, health.StatusUnhealthy, // outage
+		health.StatusHealthy, health.StatusHealthy, // recovery
+	}})
+	var transitions []health.Transition
+	server.OnTransition(func(transition health.Transition) {
+		transitions = append(transitions, transition)
+	})
+
+	var reported []health.Status
+	for range 7 {
+		recorder := httptest.NewRecorder()
+		server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health", nil))
+		var overall health.HealthResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &overall))
+		reported = append(reported, overall.Components["flaky"].Status)
+	}
+
+	assert.Equal(t, []health.Status{
+		health.StatusDegraded, health.StatusHealthy,
+		health.StatusDegraded, health.StatusDegraded, health.StatusUnhealthy,
+		health.StatusUnhealthy, health.StatusHealthy,
+	}, reported)
+	require.Len(t, transitions, 5)
+	assert.Equal(t, health.StatusDegraded, transitions[3].From)
+	assert.Equal(t, health.StatusUnhealthy, transitions[3].To)
+	assert.Equal(t, "flaky", transitions[3].Component)
+}
+
+func TestHealthServer_AuthTokens(t *testing.T) {
+	server := health.NewServer(0)
+	server.AuthTokens = []string{"s3cret"}
+	server.RegisterChecker(stubChecker{name: "temporal", status: health.StatusHealthy})
+
+	for path, code := range map[string]int{
+		"/health":        http.StatusUnauthorized,
+		"/health/worker": http.StatusUnauthorized,
+		"/metrics":       http.StatusUnauthorized,
+		"/health/live":   http.StatusOK,
+		"/health/ready":  http.StatusOK,
+	} {
+		recorder := httptest.NewRecorder()
+		server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, code, recorder.Code, path)
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "/health", nil)
+	request.Header.Set("Authorization", "Bearer s3cret")
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	request = httptest.NewRequest(http.MethodGet, "/health", nil)
+	request.Header.Set("Authorization", "Bearer wrong")
+	recorder = httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func TestHealthServer_ClientCertificateAuthenticates(t *testing.T) {
+	certFile, _ := writeTestCertificate(t, t.TempDir(), "prometheus")
+	server := health.NewServer(0)
+	server.TLS = health.TLSConfig{ClientCAFile: certFile}
+
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	request := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	request.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{}}}}
+	recorder = httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestHealthServer_StartFailsWithoutCertificate(t *testing.T) {
+	server := health.NewServer(0)
+	server.TLS = health.TLSConfig{CertFile: "missing.pem", KeyFile: "missing-key.pem"}
+	assert.ErrorContains(t, server.Start(), "health server certificate")
+}
+
+func TestSQLChecker_UnreachableDatabase(t *testing.T) {
+	db, err := sql.Open("pgx", "postgres://orders@127.0.0.1:1/orders?connect_timeout=1")
+	require.NoError(t, err)
+	defer db.Close()
+
+	result := health.NewSQLChecker("postgres", db).Check(context.Background())
+	assert.Equal(t, health.StatusUnhealthy, result.Status)
+	assert.Contains(t, result.Message, "Ping failed")
+}
+
+func TestKafkaChecker_UnreachableBrokers(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	checker := health.NewKafkaChecker([]string{"127.0.0.1:1"}, "order-events")
+	assert.Equal(t, "kafka", checker.Name())
+	result := checker.Check(ctx)
+	assert.Equal(t, health.StatusUnhealthy, result.Status)
+	assert.Contains(t, result.Message, "Metadata request failed")
+}
+
+func TestStatsDPublisher_SendsStatuses(t *testing.T) {
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	publisher, err := health.NewStatsDPublisher(listener.LocalAddr().String(), health.StatsDOptions{
+		Prefix: "order_worker",
+		Tags:   []string{"env:test"},
+	})
+	require.NoError(t, err)
+	defer publisher.Close()
+
+	require.NoError(t, publisher.Publish(context.Background(), health.HealthResponse{
+		Status: health.StatusDegraded,
+		Components: map[string]health.ComponentHealth{
+			"temporal": {Status: health.StatusHealthy},
+			"wiremock": {Status: health.StatusUnhealthy},
+		},
+	}))
+
+	var lines []string
+	buffer := make([]byte, 1024)
+	for range 4 {
+		require.NoError(t, listener.SetReadDeadline(time.Now().Add(time.Second)))
+		n, _, err := listener.ReadFrom(buffer)
+		require.NoError(t, err)
+		lines = append(lines, string(buffer[:n]))
+	}
+	assert.Equal(t, []string{
+		"order_worker.heartbeat:1|c|#env:test",
+		"order_worker.status:0.5|g|#env:test",
+		"order_worker.component.status:1|g|#env:test,component:temporal",
+		"order_worker.component.status:0|g|#env:test,component:wiremock",
+	}, lines)
+}
+
+// healthReportRecorder is a health.Publisher that records the reports it is pushed
+type healthReportRecorder struct {
+	reports chan health.HealthResponse
+}
+
+func (p healthReportRecorder) Publish(ctx context.Context, report health.HealthResponse) error {
+	p.reports <- report
+	return nil
+}
+
+func TestHealthServer_PushesToPublishers(t *testing.T) {
+	publisher := healthReportRecorder{reports: make(chan health.HealthResponse, 10)}
+	server := health.NewServer(0)
+	server.RegisterChecker(stubChecker{name: "temporal", status: health.StatusHealthy})
+	server.RegisterPublisher(publisher, 10*time.Millisecond)
+	require.NoError(t, server.Start())
+	defer server.Shutdown(context.Background())
+
+	for range 2 {
+		select {
+		case report := <-publisher.reports:
+			assert.Equal(t, health.StatusHealthy, report.Status)
+			assert.Contains(t, report.Components, "temporal")
+		case <-time.After(time.Second):
+			t.Fatal("no health report pushed")
+		}
+	}
+}
+
+func TestHealthServer_BuildAndComponentMetadata(t *testing.T) {
+	wiremock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer wiremock.Close()
+
+	server := health.NewServer(0)
+	server.Build = buildinfo.Info{Version: "v1.4.0", GitSHA: "abc123", GoVersion: "go1.25.5"}
+	server.RegisterChecker(health.NewHTTPChecker("wiremock", wiremock.URL))
+	server.RegisterChecker(stubChecker{name: "database", status: health.StatusUnhealthy})
+
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health", nil))
+	var overall health.HealthResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &overall))
+
+	assert.Equal(t, "v1.4.0", overall.Version)
+	assert.Equal(t, server.Build, overall.Build)
+	assert.Equal(t, wiremock.URL, overall.Components["wiremock"].Endpoint)
+	require.NotNil(t, overall.Components["wiremock"].LastSuccess)
+	assert.WithinDuration(t, time.Now(), *overall.Components["wiremock"].LastSuccess, time.Minute)
+	assert.Nil(t, overall.Components["database"].LastSuccess)
+}
+
+func TestBuildInfo_AlwaysHasVersion(t *testing.T) {
+	info := buildinfo.Get()
+	assert.NotEmpty(t, info.Version)
+	assert.NotEmpty(t, info.GoVersion)
+}
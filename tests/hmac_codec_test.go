@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/aswathylr-builds/temporal-order-processing/codec"
+	"github.com/aswathylr-builds/temporal-order-processing/config"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/converter"
+)
+
+func TestHMACCodec_SignsWithoutEncrypting(t *testing.T) {
+	signer := codec.NewHMACCodec(codec.Keyring{Current: codec.StaticKey(testKey())})
+	payloads := testPayloads(t, models.Order{ID: "TEST-SIG-001", Amount: 42})
+
+	signed, err := signer.Encode(payloads)
+	require.NoError(t, err)
+	assert.Equal(t, codec.MetadataEncodingSigned, string(signed[0].Metadata["encoding"]))
+	assert.Equal(t, codec.KeyID(testKey()), string(signed[0].Metadata[codec.MetadataEncryptionKeyID]))
+	assert.Contains(t, string(signed[0].Data), "TEST-SIG-001")
+
+	decoded, err := signer.Decode(signed)
+	require.NoError(t, err)
+	var order models.Order
+	require.NoError(t, converter.GetDefaultDataConverter().FromPayload(decoded[0], &order))
+	assert.Equal(t, "TEST-SIG-001", order.ID)
+}
+
+func TestHMACCodec_RejectsTamperedPayloads(t *testing.T) {
+	signer := codec.NewHMACCodec(codec.Keyring{Current: codec.StaticKey(testKey())})
+	signed, err := signer.Encode(testPayloads(t, models.Order{ID: "TEST-SIG-002", Amount: 42}))
+	require.NoError(t, err)
+
+	signed[0].Data = bytes.Replace(signed[0].Data, []byte("42"), []byte("99"), 1)
+	_, err = signer.Decode(signed)
+	assert.ErrorContains(t, err, "signature does not match")
+
+	_, err = signer.Decode(testPayloads(t, models.Order{ID: "TEST-SIG-003"}))
+	assert.ErrorContains(t, err, "not signed")
+	signer.AllowUnsigned = true
+	_, err = signer.Decode(testPayloads(t, models.Order{ID: "TEST-SIG-003"}))
+	assert.NoError(t, err)
+}
+
+func TestHMACCodec_VerifiesWithRetiredKey(t *testing.T) {
+	oldKey, newKey := testKey(), bytes.Repeat([]byte{7}, codec.KeySize)
+	signed, err := codec.NewHMACCodec(codec.Keyring{Current: codec.StaticKey(oldKey)}).
+		Encode(testPayloads(t, models.Order{ID: "TEST-SIG-004"}))
+	require.NoError(t, err)
+
+	rotated := codec.NewHMACCodec(codec.Keyring{
+		Current: codec.StaticKey(newKey),
+		Retired: []codec.KeyProvider{codec.StaticKey(oldKey)},
+	})
+	_, err = rotated.Decode(signed)
+	assert.NoError(t, err)
+}
+
+func TestConfigLoad_RejectsUnknownEncryptionMode(t *testing.T) {
+	t.Setenv("ENCRYPTION_MODE", "rot13")
+
+	_, err := config.Load("")
+	assert.ErrorContains(t, err, "encryption.mode")
+}
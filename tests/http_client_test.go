@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateOrder_RetriesServerErrors(t *testing.T) {
+	var calls int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"valid":true,"message":"ok"}`))
+	}))
+	defer mockServer.Close()
+
+	config := activities.DefaultHTTPConfig()
+	config.MaxRetries = 2
+	config.RetryWaitMin = time.Millisecond
+	config.RetryWaitMax = 10 * time.Millisecond
+
+	orderActivities, err := activities.NewOrderActivitiesWithConfig(mockServer.URL+"/validate", config)
+	require.NoError(t, err)
+
+	resp, err := orderActivities.ValidateOrder(context.Background(), models.Order{ID: "TEST-HTTP-001", Amount: 100.0})
+
+	require.NoError(t, err)
+	assert.True(t, resp.Valid)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestValidateOrder_CustomCABundle(t *testing.T) {
+	mockServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"valid":true,"message":"ok"}`))
+	}))
+	defer mockServer.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: mockServer.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caFile, caPEM, 0600))
+
+	config := activities.DefaultHTTPConfig()
+	config.CAFile = caFile
+
+	orderActivities, err := activities.NewOrderActivitiesWithConfig(mockServer.URL+"/validate", config)
+	require.NoError(t, err)
+
+	resp, err := orderActivities.ValidateOrder(context.Background(), models.Order{ID: "TEST-HTTP-002", Amount: 100.0})
+
+	require.NoError(t, err)
+	assert.True(t, resp.Valid)
+}
+
+func TestNewHTTPClient_RequiresCertAndKeyTogether(t *testing.T) {
+	config := activities.DefaultHTTPConfig()
+	config.CertFile = "client.pem"
+
+	_, err := activities.NewHTTPClient(config)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "both client certificate and key")
+}
@@ -0,0 +1,210 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/authz"
+	"github.com/aswathylr-builds/temporal-order-processing/events"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/store"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/worker"
+)
+
+var installmentPlan = models.InstallmentPlan{
+	OrderID:         "TEST-INSTALLMENTS-001",
+	Amount:          100.0,
+	Installments:    3,
+	Interval:        30 * 24 * time.Hour,
+	DunningRetries:  2,
+	DunningInterval: 24 * time.Hour,
+}
+
+// newInstallmentTestEnv returns an environment running the installment plan,
+// whose charges are answered by charge
+func newInstallmentTestEnv(orderActivities *activities.OrderActivities, charge func(ctx context.Context, req models.PaymentRequest) (*models.PaymentResponse, error)) *testsuite.TestWorkflowEnvironment {
+	env := (&testsuite.WorkflowTestSuite{}).NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(workflows.InstallmentPaymentWorkflow)
+	env.RegisterActivity(orderActivities.ProcessPayment)
+	env.RegisterActivity(orderActivities.RefundPayment)
+	env.OnActivity(orderActivities.ProcessPayment, mock.Anything, mock.Anything).Return(charge)
+	return env
+}
+
+func TestInstallmentPlan_SplitsAmountInCents(t *testing.T) {
+	assert.Equal(t, []float64{33.34, 33.33, 33.33}, installmentPlan.Amounts())
+	assert.Nil(t, models.InstallmentPlan{Amount: 100}.Amounts())
+}
+
+func TestInstallmentPaymentWorkflow_ChargesEachInstallmentOnSchedule(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	charges := 0
+	env := newInstallmentTestEnv(orderActivities, func(ctx context.Context, req models.PaymentRequest) (*models.PaymentResponse, error) {
+		charges++
+		return &models.PaymentResponse{Success: true, TransactionID: fmt.Sprintf("TXN-%d", charges)}, nil
+	})
+	start := env.Now()
+
+	env.ExecuteWorkflow(workflows.InstallmentPaymentWorkflow, installmentPlan)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var result models.InstallmentPlanResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	require.Len(t, result.Payments, 3)
+	for i, payment := range result.Payments {
+		assert.Equal(t, i+1, payment.Number)
+		assert.Equal(t, installmentPlan.Amounts()[i], payment.Amount)
+		assert.Equal(t, fmt.Sprintf("TXN-%d", i+1), payment.TransactionID)
+		assert.Equal(t, 1, payment.Attempts)
+		assert.True(t, start.Add(time.Duration(i)*installmentPlan.Interval).Equal(payment.PaidAt), "installment %d paid at %s", i+1, payment.PaidAt)
+	}
+}
+
+func TestInstallmentPaymentWorkflow_RetriesFailedInstallmentByDunning(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	charges := 0
+	env := newInstallmentTestEnv(orderActivities, func(ctx context.Context, req models.PaymentRequest) (*models.PaymentResponse, error) {
+		charges++
+		// The second installment fails twice before it is paid
+		if charges == 2 || charges == 3 {
+			return nil, temporal.NewNonRetryableApplicationError("insufficient funds", models.ErrTypePaymentDeclined, nil)
+		}
+		return &models.PaymentResponse{Success: true, TransactionID: fmt.Sprintf("TXN-%d", charges)}, nil
+	})
+
+	env.ExecuteWorkflow(workflows.InstallmentPaymentWorkflow, installmentPlan)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var result models.InstallmentPlanResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	require.Len(t, result.Payments, 3)
+	assert.Equal(t, 3, result.Payments[1].Attempts)
+	assert.Equal(t, "TXN-4", result.Payments[1].TransactionID)
+	assert.Equal(t, result.Payments[0].PaidAt.Add(installmentPlan.Interval+2*installmentPlan.DunningInterval), result.Payments[1].PaidAt)
+}
+
+func TestInstallmentPaymentWorkflow_DefaultsAfterLastDunningRetry(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newInstallmentTestEnv(orderActivities, func(ctx context.Context, req models.PaymentRequest) (*models.PaymentResponse, error) {
+		return nil, temporal.NewNonRetryableApplicationError("card expired", models.ErrTypePaymentDeclined, nil)
+	})
+
+	env.ExecuteWorkflow(workflows.InstallmentPaymentWorkflow, installmentPlan)
+
+	require.True(t, env.IsWorkflowCompleted())
+	var appErr *temporal.ApplicationError
+	require.True(t, errors.As(env.GetWorkflowError(), &appErr))
+	assert.Equal(t, models.ErrTypeInstallmentPlanDefaulted, appErr.Type())
+	var defaulted models.InstallmentPlanDefaultedError
+	require.NoError(t, appErr.Details(&defaulted))
+	assert.Equal(t, 1, defaulted.Installment)
+	assert.Equal(t, 3, defaulted.Attempts)
+	env.AssertNotCalled(t, "RefundPayment", mock.Anything, mock.Anything)
+}
+
+// newInstallmentOrderTestEnv runs orders whose first installment is paid and
+// whose second is always declined, so their plan defaults
+func newInstallmentOrderTestEnv(orderActivities *activities.OrderActivities) *testsuite.TestWorkflowEnvironment {
+	env := (&testsuite.WorkflowTestSuite{}).NewTestWorkflowEnvironment()
+	storeActivities := store.NewStoreActivities(nil)
+	env.RegisterActivity(orderActivities.ValidateOrder)
+	env.RegisterActivity(orderActivities.ProcessPayment)
+	env.RegisterActivity(orderActivities.FulfillItem)
+	env.RegisterActivity(orderActivities.NotifyOrderComplete)
+	env.RegisterActivity(events.NewEventActivities(nil).PublishOrderEvent)
+	env.RegisterActivity(storeActivities.PersistOrder)
+	env.RegisterActivity(storeActivities.UpdateOrderStatus)
+	env.RegisterWorkflow(workflows.OrderWorkflow)
+	env.RegisterWorkflow(workflows.InstallmentPaymentWorkflow)
+	env.OnActivity(orderActivities.ValidateOrder, mock.Anything, mock.Anything).Return(&models.ValidationResponse{Valid: true}, nil)
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	env.OnActivity(orderActivities.NotifyOrderComplete, mock.Anything, mock.Anything).Return(nil)
+	env.OnActivity(orderActivities.ProcessPayment, mock.Anything, mock.Anything).Return(&models.PaymentResponse{Success: true, TransactionID: "TXN-INSTALLMENT-1"}, nil).Once()
+	env.OnActivity(orderActivities.ProcessPayment, mock.Anything, mock.Anything).Return(nil, temporal.NewNonRetryableApplicationError("card expired", models.ErrTypePaymentDeclined, nil))
+	return env
+}
+
+func TestOrderWorkflow_InstallmentPlanDefaultSignalsOrder(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newInstallmentOrderTestEnv(orderActivities)
+	var planStatus models.OrderStatus
+	env.RegisterDelayedCallback(func() {
+		planStatus = queryStatus(t, env)
+	}, time.Hour)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:           "TEST-INSTALLMENTS-002",
+		Items:        []string{"item1", "item2"},
+		Amount:       90.0,
+		Status:       models.StatusPending,
+		Installments: 3,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, models.StatusCompleted, planStatus.Status, "the order is fulfilled once its plan starts")
+	assert.Equal(t, "installments", planStatus.PaymentStatus)
+	status := queryStatus(t, env)
+	assert.Equal(t, models.StatusCompleted, status.Status)
+	assert.Equal(t, "defaulted", status.PaymentStatus)
+	var result models.OrderResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	assert.Equal(t, "defaulted", result.PaymentStatus)
+	env.AssertNumberOfCalls(t, "ProcessPayment", 1+workflows.InstallmentDunningRetries+1)
+}
+
+func TestOrderWorkflow_AcceptsInstallmentDefaultOnlyFromInstallmentPlan(t *testing.T) {
+	signer, err := authz.NewSigner("secret")
+	require.NoError(t, err)
+	opsToken, err := signer.Sign(authz.Claims{Subject: "ops@example.com"})
+	require.NoError(t, err)
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newInstallmentOrderTestEnv(orderActivities)
+	env.SetWorkerOptions(worker.Options{Interceptors: []interceptor.WorkerInterceptor{
+		&signalHeaderInjector{token: opsToken},
+		authz.NewInterceptor(authz.Config{
+			Signer:           signer,
+			ProtectedSignals: models.ProtectedSignals,
+			SignalSenders:    workflows.SignalSenders,
+		}),
+	}})
+	// A signed caller is still not the installment plan, so its default is
+	// dropped
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(models.SignalInstallmentDefault, models.InstallmentPlanDefaultedError{
+			OrderID:     "TEST-INSTALLMENTS-003",
+			Installment: 2,
+			Reason:      "forged",
+		})
+	}, time.Hour)
+	var planStatus models.OrderStatus
+	env.RegisterDelayedCallback(func() {
+		planStatus = queryStatus(t, env)
+	}, 2*time.Hour)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:           "TEST-INSTALLMENTS-003",
+		Items:        []string{"item1", "item2"},
+		Amount:       90.0,
+		Status:       models.StatusPending,
+		Installments: 3,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, "installments", planStatus.PaymentStatus)
+	assert.Equal(t, "defaulted", queryStatus(t, env).PaymentStatus, "the installment plan's own default is accepted")
+}
@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/worker"
+)
+
+func newInvoiceActivities(t *testing.T) *activities.OrderActivities {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	orderActivities.InvoiceDir = filepath.Join(t.TempDir(), "scratch")
+	orderActivities.InvoiceStoreDir = filepath.Join(t.TempDir(), "store")
+	return orderActivities
+}
+
+func TestGenerateAndUploadInvoice(t *testing.T) {
+	orderActivities := newInvoiceActivities(t)
+	ctx := context.Background()
+
+	path, err := orderActivities.GenerateInvoice(ctx, models.InvoiceRequest{
+		OrderID:       "TEST-INV-001",
+		Items:         []string{"item1", "item2"},
+		Amount:        42.5,
+		TransactionID: "TXN-INV-001",
+	})
+	require.NoError(t, err)
+
+	url, err := orderActivities.UploadInvoice(ctx, "TEST-INV-001", path)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(url, "file://"))
+
+	uploaded, err := os.ReadFile(strings.TrimPrefix(url, "file://"))
+	require.NoError(t, err)
+	assert.Contains(t, string(uploaded), "TXN-INV-001")
+	assert.Contains(t, string(uploaded), "Total: $42.50")
+	assert.NoFileExists(t, path, "the local scratch copy is removed after upload")
+}
+
+func TestUploadInvoice_FileOnAnotherHostIsNotRetried(t *testing.T) {
+	orderActivities := newInvoiceActivities(t)
+
+	_, err := orderActivities.UploadInvoice(context.Background(), "TEST-INV-002", filepath.Join(t.TempDir(), "missing.txt"))
+
+	var appErr *temporal.ApplicationError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, models.ErrTypeInvoiceFileMissing, appErr.Type())
+	assert.True(t, appErr.NonRetryable())
+}
+
+func TestOrderWorkflow_GeneratesInvoiceInSession(t *testing.T) {
+	orderActivities := newInvoiceActivities(t)
+	env := newFulfillmentTestEnv(orderActivities)
+	env.SetWorkerOptions(worker.Options{EnableSessionWorker: true})
+	env.RegisterActivity(orderActivities.GenerateInvoice)
+	env.RegisterActivity(orderActivities.UploadInvoice)
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:     "TEST-INV-003",
+		Items:  []string{"item1"},
+		Amount: 100.0,
+		Status: models.StatusPending,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+
+	result, err := env.QueryWorkflow("getStatus")
+	require.NoError(t, err)
+	var state models.OrderStatus
+	require.NoError(t, result.Get(&state))
+	require.NotEmpty(t, state.InvoiceURL)
+	assert.FileExists(t, strings.TrimPrefix(state.InvoiceURL, "file://"))
+}
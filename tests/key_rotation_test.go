@@ -0,0 +1,110 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aswathylr-builds/temporal-order-processing/codec"
+	"github.com/aswathylr-builds/temporal-order-processing/config"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/converter"
+)
+
+// countingKey is a KeyProvider that counts how often it is asked for its key
+type countingKey struct {
+	key   []byte
+	calls atomic.Int32
+}
+
+func (k *countingKey) Key(ctx context.Context) ([]byte, error) {
+	k.calls.Add(1)
+	return k.key, nil
+}
+
+func TestKeyRotation_DecryptsPayloadsFromRetiredKey(t *testing.T) {
+	oldKey, newKey := testKey(), bytes.Repeat([]byte{7}, codec.KeySize)
+	oldCodec, err := codec.NewEncryptionCodec(oldKey)
+	require.NoError(t, err)
+	beforeRotation, err := oldCodec.Encode(testPayloads(t, models.Order{ID: "TEST-ROT-001"}))
+	require.NoError(t, err)
+	assert.Equal(t, codec.KeyID(oldKey), string(beforeRotation[0].Metadata[codec.MetadataEncryptionKeyID]))
+
+	retired := &countingKey{key: oldKey}
+	rotated := codec.NewEncryptionCodecWithKeyring(codec.Keyring{
+		Current: codec.StaticKey(newKey),
+		Retired: []codec.KeyProvider{retired},
+	})
+	afterRotation, err := rotated.Encode(testPayloads(t, models.Order{ID: "TEST-ROT-002"}))
+	require.NoError(t, err)
+	assert.Equal(t, codec.KeyID(newKey), string(afterRotation[0].Metadata[codec.MetadataEncryptionKeyID]))
+
+	_, err = rotated.Decode(afterRotation)
+	require.NoError(t, err)
+	assert.Zero(t, retired.calls.Load(), "retired keys are only fetched for payloads that need them")
+
+	decoded, err := rotated.Decode(append(beforeRotation, afterRotation...))
+	require.NoError(t, err)
+	var order models.Order
+	require.NoError(t, converter.GetDefaultDataConverter().FromPayload(decoded[0], &order))
+	assert.Equal(t, "TEST-ROT-001", order.ID)
+	require.NoError(t, converter.GetDefaultDataConverter().FromPayload(decoded[1], &order))
+	assert.Equal(t, "TEST-ROT-002", order.ID)
+}
+
+func TestKeyRotation_PayloadsWithoutKeyIDTryEveryKey(t *testing.T) {
+	oldKey, newKey := testKey(), bytes.Repeat([]byte{7}, codec.KeySize)
+	oldCodec, err := codec.NewEncryptionCodec(oldKey)
+	require.NoError(t, err)
+	payloads, err := oldCodec.Encode(testPayloads(t, models.Order{ID: "TEST-ROT-003"}))
+	require.NoError(t, err)
+	// Written before key IDs were recorded
+	delete(payloads[0].Metadata, codec.MetadataEncryptionKeyID)
+
+	rotated := codec.NewEncryptionCodecWithKeyring(codec.Keyring{
+		Current: codec.StaticKey(newKey),
+		Retired: []codec.KeyProvider{codec.StaticKey(oldKey)},
+	})
+	_, err = rotated.Decode(payloads)
+	require.NoError(t, err)
+}
+
+func TestKeyRotation_UnknownKeyID(t *testing.T) {
+	oldCodec, err := codec.NewEncryptionCodec(testKey())
+	require.NoError(t, err)
+	payloads, err := oldCodec.Encode(testPayloads(t, models.Order{ID: "TEST-ROT-004"}))
+	require.NoError(t, err)
+
+	newCodec, err := codec.NewEncryptionCodec(bytes.Repeat([]byte{7}, codec.KeySize))
+	require.NoError(t, err)
+	_, err = newCodec.Decode(payloads)
+	assert.ErrorContains(t, err, "not in the keyring")
+}
+
+func TestConfigEncryptionKeys_RetiredKeyFiles(t *testing.T) {
+	dir := t.TempDir()
+	retiredFile := filepath.Join(dir, "retired.key")
+	require.NoError(t, os.WriteFile(retiredFile, testKey(), 0600))
+	t.Setenv("ENCRYPTION_KEY_FILE", filepath.Join(dir, "current.key"))
+	t.Setenv("ENCRYPTION_RETIRED_KEY_FILES", retiredFile)
+
+	cfg, err := config.Load("")
+	require.NoError(t, err)
+	keys, err := cfg.EncryptionKeys()
+	require.NoError(t, err)
+	require.Len(t, keys.Retired, 1)
+	retired, err := keys.Retired[0].Key(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, testKey(), retired)
+
+	t.Setenv("ENCRYPTION_RETIRED_KEY_FILES", filepath.Join(dir, "missing.key"))
+	cfg, err = config.Load("")
+	require.NoError(t, err)
+	_, err = cfg.EncryptionKeys()
+	assert.ErrorContains(t, err, "retired key file")
+}
@@ -0,0 +1,144 @@
+package tests
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/awskms"
+	"github.com/aswathylr-builds/temporal-order-processing/codec"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+)
+
+// fakeKMS hands out random data keys and remembers them by their opaque
+// wrapped blob, checking the encryption context like KMS does
+type fakeKMS struct {
+	mu        sync.Mutex
+	keys      map[string][]byte
+	generated int
+	decrypted int
+}
+
+func newFakeKMS() *fakeKMS {
+	return &fakeKMS{keys: make(map[string][]byte)}
+}
+
+func (f *fakeKMS) GenerateDataKey(ctx context.Context, params *kms.GenerateDataKeyInput, optFns ...func(*kms.Options)) (*kms.GenerateDataKeyOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	plaintext, blob := make([]byte, codec.KeySize), make([]byte, 48)
+	_, _ = rand.Read(plaintext)
+	_, _ = rand.Read(blob)
+	f.keys[string(blob)] = plaintext
+	f.generated++
+	return &kms.GenerateDataKeyOutput{Plaintext: plaintext, CiphertextBlob: blob, KeyId: params.KeyId}, nil
+}
+
+func (f *fakeKMS) Decrypt(ctx context.Context, params *kms.DecryptInput, optFns ...func(*kms.Options)) (*kms.DecryptOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.decrypted++
+	plaintext, ok := f.keys[string(params.CiphertextBlob)]
+	if !ok || params.EncryptionContext["service"] != awskms.EncryptionContext["service"] {
+		return nil, errors.New("InvalidCiphertextException")
+	}
+	return &kms.DecryptOutput{Plaintext: plaintext}, nil
+}
+
+func newEnvelopeCodec(fake *fakeKMS, ttl time.Duration) *codec.EncryptionCodec {
+	return codec.NewEnvelopeEncryptionCodec(codec.EnvelopeConfig{
+		DataKeys:   awskms.New(fake, "alias/order-processing"),
+		DataKeyTTL: ttl,
+	})
+}
+
+func testPayloads(t testing.TB, orders ...models.Order) []*commonpb.Payload {
+	t.Helper()
+	var payloads []*commonpb.Payload
+	for _, order := range orders {
+		payload, err := converter.GetDefaultDataConverter().ToPayload(order)
+		require.NoError(t, err)
+		payloads = append(payloads, payload)
+	}
+	return payloads
+}
+
+func TestEnvelopeCodec_StoresWrappedKeyAndReusesDataKey(t *testing.T) {
+	fake := newFakeKMS()
+	encoder := newEnvelopeCodec(fake, time.Hour)
+
+	first, err := encoder.Encode(testPayloads(t, models.Order{ID: "TEST-KMS-001"}))
+	require.NoError(t, err)
+	second, err := encoder.Encode(testPayloads(t, models.Order{ID: "TEST-KMS-002"}))
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, fake.generated, "the data key is reused within its TTL")
+	wrapped := first[0].Metadata[codec.MetadataEncryptionKeyWrapped]
+	require.NotEmpty(t, wrapped)
+	assert.Equal(t, wrapped, second[0].Metadata[codec.MetadataEncryptionKeyWrapped])
+	assert.NotContains(t, string(first[0].Data), "TEST-KMS-001")
+
+	// Another process unwraps the key through KMS once, then caches it
+	decoder := newEnvelopeCodec(fake, time.Hour)
+	decoded, err := decoder.Decode(append(first, second...))
+	require.NoError(t, err)
+	assert.Equal(t, 1, fake.decrypted)
+
+	var order models.Order
+	require.NoError(t, converter.GetDefaultDataConverter().FromPayload(decoded[1], &order))
+	assert.Equal(t, "TEST-KMS-002", order.ID)
+}
+
+func TestEnvelopeCodec_RotatesDataKeyAfterTTL(t *testing.T) {
+	fake := newFakeKMS()
+	encoder := newEnvelopeCodec(fake, 10*time.Millisecond)
+
+	first, err := encoder.Encode(testPayloads(t, models.Order{ID: "TEST-KMS-003"}))
+	require.NoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+	second, err := encoder.Encode(testPayloads(t, models.Order{ID: "TEST-KMS-004"}))
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, fake.generated)
+	assert.NotEqual(t, first[0].Metadata[codec.MetadataEncryptionKeyWrapped], second[0].Metadata[codec.MetadataEncryptionKeyWrapped])
+
+	_, err = newEnvelopeCodec(fake, time.Hour).Decode(append(first, second...))
+	require.NoError(t, err)
+}
+
+func TestEnvelopeCodec_DecryptsLegacyPayloads(t *testing.T) {
+	staticCodec, err := codec.NewEncryptionCodec(testKey())
+	require.NoError(t, err)
+	legacy, err := staticCodec.Encode(testPayloads(t, models.Order{ID: "TEST-KMS-005"}))
+	require.NoError(t, err)
+
+	_, err = newEnvelopeCodec(newFakeKMS(), time.Hour).Decode(legacy)
+	assert.ErrorContains(t, err, "no legacy key")
+
+	envelopeCodec := codec.NewEnvelopeEncryptionCodec(codec.EnvelopeConfig{
+		DataKeys:   awskms.New(newFakeKMS(), "alias/order-processing"),
+		LegacyKeys: codec.Keyring{Current: codec.StaticKey(testKey())},
+	})
+	decoded, err := envelopeCodec.Decode(legacy)
+	require.NoError(t, err)
+	var order models.Order
+	require.NoError(t, converter.GetDefaultDataConverter().FromPayload(decoded[0], &order))
+	assert.Equal(t, "TEST-KMS-005", order.ID)
+}
+
+func TestEnvelopeCodec_FailsWhenKMSRejectsKey(t *testing.T) {
+	encoded, err := newEnvelopeCodec(newFakeKMS(), time.Hour).Encode(testPayloads(t, models.Order{ID: "TEST-KMS-006"}))
+	require.NoError(t, err)
+
+	// A different KMS account cannot unwrap the data key
+	_, err = newEnvelopeCodec(newFakeKMS(), time.Hour).Decode(encoded)
+	assert.ErrorContains(t, err, "failed to unwrap data key")
+}
@@ -0,0 +1,45 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/aswathylr-builds/temporal-order-processing/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/log"
+)
+
+func TestTemporalLogger_NormalizesSDKKeys(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewTemporalLogger(logging.New(&buf, logging.Config{Format: logging.FormatJSON, Level: slog.LevelInfo}))
+
+	// The SDK adds its tags through With, as it does for activity loggers
+	activityLogger := log.With(logger, "WorkflowID", "order-workflow-1", "RunID", "run-1", "ActivityType", "ValidateOrder", "Attempt", 2)
+	activityLogger.Info("Validating order", "order_id", "ORDER-1", "amount", 10.5)
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "Validating order", entry["msg"])
+	assert.Equal(t, "INFO", entry["level"])
+	assert.Equal(t, "order-workflow-1", entry["workflow_id"])
+	assert.Equal(t, "run-1", entry["run_id"])
+	assert.Equal(t, "ValidateOrder", entry["activity_type"])
+	assert.Equal(t, float64(2), entry["attempt"])
+	assert.Equal(t, "ORDER-1", entry["order_id"])
+	assert.NotContains(t, entry, "WorkflowID")
+}
+
+func TestTemporalLogger_RespectsLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewTemporalLogger(logging.New(&buf, logging.Config{Format: logging.FormatText, Level: slog.LevelWarn}))
+
+	logger.Info("dropped")
+	logger.Warn("kept", "Error", "boom")
+
+	assert.NotContains(t, buf.String(), "dropped")
+	assert.Contains(t, buf.String(), "msg=kept")
+	assert.Contains(t, buf.String(), "error=boom")
+}
@@ -0,0 +1,140 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+)
+
+func TestAwardLoyaltyPoints_EarnsPointsPerDollar(t *testing.T) {
+	var got models.LoyaltyRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/award", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		json.NewEncoder(w).Encode(models.LoyaltyTransaction{TransactionID: "LOY-1", OrderID: got.OrderID, CustomerID: got.CustomerID, Points: got.Points})
+	}))
+	defer server.Close()
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	orderActivities.LoyaltyURL = server.URL
+	orderActivities.LoyaltyPointsPerDollar = 2
+
+	txn, err := orderActivities.AwardLoyaltyPoints(context.Background(), models.LoyaltyRequest{OrderID: "ORDER-1", CustomerID: "CUST-1", Amount: 49.99})
+
+	require.NoError(t, err)
+	assert.Equal(t, 99, got.Points)
+	assert.Equal(t, models.LoyaltyTransaction{TransactionID: "LOY-1", OrderID: "ORDER-1", CustomerID: "CUST-1", Points: 99}, *txn)
+}
+
+func TestRedeemLoyaltyPoints_RefusalIsRejection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/redeem", r.URL.Path)
+		http.Error(w, "insufficient points", http.StatusConflict)
+	}))
+	defer server.Close()
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	orderActivities.LoyaltyURL = server.URL
+
+	_, err := orderActivities.RedeemLoyaltyPoints(context.Background(), models.LoyaltyRequest{OrderID: "ORDER-1", CustomerID: "CUST-1", Points: 500})
+
+	var appErr *temporal.ApplicationError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, models.ErrTypeLoyaltyRedemptionRejected, appErr.Type())
+	assert.True(t, appErr.NonRetryable())
+	assert.Contains(t, err.Error(), "insufficient points")
+}
+
+// newLoyaltyTestEnv registers the loyalty activities on env for an order
+// placed by CUST-LOYAL
+func newLoyaltyTestEnv(t *testing.T, env *testsuite.TestWorkflowEnvironment, orderActivities *activities.OrderActivities) *testsuite.TestWorkflowEnvironment {
+	env.RegisterActivity(orderActivities.RedeemLoyaltyPoints)
+	env.RegisterActivity(orderActivities.AwardLoyaltyPoints)
+	env.RegisterActivity(orderActivities.ReverseLoyaltyPoints)
+	require.NoError(t, env.SetMemoOnStart(models.OrderMemo{CustomerID: "CUST-LOYAL"}.Fields()))
+	return env
+}
+
+func TestOrderWorkflow_RedeemsAndAwardsLoyaltyPoints(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newLoyaltyTestEnv(t, newDynamicConfigTestEnv(orderActivities, models.DynamicConfig{}), orderActivities)
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	env.OnActivity(orderActivities.RedeemLoyaltyPoints, mock.Anything, models.LoyaltyRequest{OrderID: "TEST-LOYALTY-001", CustomerID: "CUST-LOYAL", Points: 200}).
+		Return(&models.LoyaltyTransaction{TransactionID: "LOY-REDEEM", Points: -200}, nil).Once()
+	env.OnActivity(orderActivities.AwardLoyaltyPoints, mock.Anything, models.LoyaltyRequest{OrderID: "TEST-LOYALTY-001", CustomerID: "CUST-LOYAL", Amount: 80}).
+		Return(&models.LoyaltyTransaction{TransactionID: "LOY-AWARD", Points: 80}, nil).Once()
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:           "TEST-LOYALTY-001",
+		Items:        []string{"item1"},
+		Amount:       80.0,
+		Status:       models.StatusPending,
+		RedeemPoints: 200,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, models.StatusCompleted, queryStatus(t, env).Status)
+	env.AssertExpectations(t)
+}
+
+func TestOrderWorkflow_RejectedRedemptionFailsOrderBeforePayment(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newLoyaltyTestEnv(t, newDynamicConfigTestEnv(orderActivities, models.DynamicConfig{}), orderActivities)
+	rejected := &models.LoyaltyRedemptionRejectedError{OrderID: "TEST-LOYALTY-002", CustomerID: "CUST-LOYAL", Points: 5000, Reason: "insufficient points"}
+	env.OnActivity(orderActivities.RedeemLoyaltyPoints, mock.Anything, mock.Anything).Return(nil, rejected.ApplicationError())
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:           "TEST-LOYALTY-002",
+		Items:        []string{"item1"},
+		Amount:       80.0,
+		Status:       models.StatusPending,
+		RedeemPoints: 5000,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.Error(t, env.GetWorkflowError())
+	var appErr *temporal.ApplicationError
+	require.True(t, errors.As(env.GetWorkflowError(), &appErr))
+	assert.Equal(t, models.ErrTypeLoyaltyRedemptionRejected, appErr.Type())
+	assert.Equal(t, models.StatusFailed, queryStatus(t, env).Status)
+	env.AssertNotCalled(t, "ProcessPayment", mock.Anything, mock.Anything)
+}
+
+func TestOrderWorkflow_CancelledOrderReversesLoyaltyPoints(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newLoyaltyTestEnv(t, newBackorderTestEnv(orderActivities, models.DynamicConfig{BackorderTimeout: 24 * time.Hour}), orderActivities)
+	env.OnActivity(orderActivities.RefundPayment, mock.Anything, mock.Anything).Return(nil)
+	var reversed []models.LoyaltyTransaction
+	env.OnActivity(orderActivities.ReverseLoyaltyPoints, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, txn models.LoyaltyTransaction) error {
+			reversed = append(reversed, txn)
+			return nil
+		})
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(models.SignalCancel, nil)
+	}, time.Hour)
+
+	order := backorderedOrder
+	order.RedeemPoints = 100
+	env.ExecuteWorkflow(workflows.OrderWorkflow, order)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, models.StatusCancelled, queryStatus(t, env).Status)
+	assert.Equal(t, []models.LoyaltyTransaction{
+		{TransactionID: "LOY-TEST-BACKORDER-001-award", OrderID: "TEST-BACKORDER-001", CustomerID: "CUST-LOYAL", Points: 50},
+		{TransactionID: "LOY-TEST-BACKORDER-001-redeem", OrderID: "TEST-BACKORDER-001", CustomerID: "CUST-LOYAL", Points: -100},
+	}, reversed, "the award is reversed before the redemption")
+}
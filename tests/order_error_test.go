@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/events"
+	"github.com/aswathylr-builds/temporal-order-processing/intake"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/store"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+)
+
+func TestPaymentDeclinedError_CarriesOrderError(t *testing.T) {
+	err := (&models.PaymentDeclinedError{OrderID: "TEST-OERR-001", Amount: 750, DeclineCode: "insufficient_funds"}).ApplicationError()
+
+	var orderErr models.OrderError
+	require.True(t, models.AsOrderError(err, &orderErr))
+	assert.Equal(t, models.ErrTypePaymentDeclined, orderErr.Code)
+	assert.Equal(t, models.StagePayment, orderErr.Stage)
+	assert.False(t, orderErr.Retryable)
+	var declined models.PaymentDeclinedError
+	require.NoError(t, orderErr.DecodeDetails(&declined))
+	assert.Equal(t, "insufficient_funds", declined.DeclineCode)
+
+	// Errors without one are described by their type
+	assert.Equal(t, &models.OrderError{Code: models.ErrTypeServiceUnavailable, Retryable: true, Message: "circuit open"},
+		models.OrderErrorFrom(temporal.NewApplicationError("circuit open", models.ErrTypeServiceUnavailable)))
+	assert.Equal(t, models.ErrTypeInternal, models.OrderErrorFrom(errors.New("boom")).Code)
+	assert.Nil(t, models.OrderErrorFrom(nil))
+}
+
+func TestOrderWorkflow_FailsWithOrderErrorOfStage(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	storeActivities := store.NewStoreActivities(nil)
+	env.RegisterActivity(orderActivities.ValidateOrder)
+	env.RegisterActivity(orderActivities.ProcessPayment)
+	env.RegisterActivity(events.NewEventActivities(nil).PublishOrderEvent)
+	env.RegisterActivity(storeActivities.PersistOrder)
+	env.RegisterActivity(storeActivities.UpdateOrderStatus)
+	env.RegisterWorkflow(workflows.OrderWorkflow)
+	env.RegisterWorkflow(workflows.PaymentWorkflow)
+	env.RegisterWorkflow(workflows.FailedOrderWorkflow)
+	env.OnActivity(orderActivities.ValidateOrder, mock.Anything, mock.Anything).Return(&models.ValidationResponse{Valid: true}, nil)
+	env.OnActivity(orderActivities.ProcessPayment, mock.Anything, mock.Anything).Return(nil,
+		temporal.NewNonRetryableApplicationError("gateway returned an empty body", "UnexpectedResponse", nil))
+	env.OnWorkflow(workflows.FailedOrderWorkflow, mock.Anything, mock.Anything).Return(nil, nil)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:     "TEST-OERR-002",
+		Items:  []string{"item1"},
+		Amount: 100.0,
+		Status: models.StatusPending,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	orderErr := models.OrderErrorFrom(env.GetWorkflowError())
+	require.NotNil(t, orderErr)
+	assert.Equal(t, models.ErrTypeInternal, orderErr.Code)
+	assert.Equal(t, models.StagePayment, orderErr.Stage)
+	assert.False(t, orderErr.Retryable)
+}
+
+func TestWebhookHandler_RejectsWithOrderError(t *testing.T) {
+	submitter := &recordingSubmitter{}
+	handler := intake.NewWebhookHandler(submitter, intake.WebhookHandlerOptions{Secrets: []string{"s3cret"}})
+
+	rec := postWebhook(t, handler, `{"id": 1003, "total_price": "0.00", "line_items": [{"sku": "mug", "quantity": 1, "price": "0.00"}]}`, "s3cret", intake.TopicOrderCreated)
+
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var orderErr models.OrderError
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &orderErr))
+	assert.Equal(t, models.ErrTypeOrderInvalid, orderErr.Code)
+	assert.Equal(t, models.StageValidation, orderErr.Stage)
+	var invalid models.OrderInvalidError
+	require.NoError(t, orderErr.DecodeDetails(&invalid))
+	assert.Equal(t, []models.FieldError{{Field: "amount", Message: "must be positive"}}, invalid.Fields)
+	assert.Empty(t, submitter.orders)
+}
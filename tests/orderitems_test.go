@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/orderitems"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderItems_Parse(t *testing.T) {
+	tests := []struct {
+		name  string
+		list  string
+		items []string
+	}{
+		{"one item", "laptop", []string{"laptop"}},
+		{"several items", "laptop,mouse,keyboard", []string{"laptop", "mouse", "keyboard"}},
+		{"whitespace around items", " laptop ,\tmouse , keyboard\n", []string{"laptop", "mouse", "keyboard"}},
+		{"whitespace inside an item", "usb cable, hdmi cable", []string{"usb cable", "hdmi cable"}},
+		{"empty entries", "laptop,,mouse,", []string{"laptop", "mouse"}},
+		{"blank entries", "laptop, ,mouse", []string{"laptop", "mouse"}},
+		{"only separators", ", ,,", []string{}},
+		{"empty", "", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.items, orderitems.Parse(tt.list))
+		})
+	}
+}
+
+func TestOrderItems_Decode(t *testing.T) {
+	tests := []struct {
+		name  string
+		json  string
+		lines []models.OrderItem
+		err   string
+	}{
+		{"lines", `[{"sku": "laptop", "qty": 2, "price": 999.99}, {"sku": "mouse", "qty": 1, "price": 25}]`,
+			[]models.OrderItem{{SKU: "laptop", Quantity: 2, Price: 999.99}, {SKU: "mouse", Quantity: 1, Price: 25}}, ""},
+		{"missing qty is one unit", `[{"sku": "laptop", "price": 999.99}]`, []models.OrderItem{{SKU: "laptop", Quantity: 1, Price: 999.99}}, ""},
+		{"dropshipped line", `[{"sku": "kayak", "qty": 1, "price": 600, "vendor": "acme"}]`,
+			[]models.OrderItem{{SKU: "kayak", Quantity: 1, Price: 600, Vendor: "acme"}}, ""},
+		{"negative qty", `[{"sku": "laptop", "qty": -1}]`, nil, "line 1: qty must not be negative"},
+		{"qty over the line limit", `[{"sku": "mouse", "qty": 1}, {"sku": "laptop", "qty": 11}]`, nil, "line 2: qty must be at most 10"},
+		{"fractional qty", `[{"sku": "laptop", "qty": 1.5}]`, nil, "cannot unmarshal number 1.5"},
+		{"qty as a string", `[{"sku": "laptop", "qty": "2"}]`, nil, "cannot unmarshal string"},
+		{"blank sku", `[{"sku": " ", "qty": 1}]`, nil, "line 1: sku is required"},
+		{"negative price", `[{"sku": "laptop", "qty": 1, "price": -5}]`, nil, "line 1: price must not be negative"},
+		{"sku with two vendors", `[{"sku": "kayak", "vendor": "acme"}, {"sku": "kayak", "vendor": "globex"}]`, nil, "line 2: sku kayak is listed with another vendor"},
+		{"unknown field", `[{"sku": "laptop", "quantity": 2}]`, nil, `unknown field "quantity"`},
+		{"no lines", `[]`, nil, "no order lines"},
+		{"not an array", `{"sku": "laptop"}`, nil, "cannot unmarshal object"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, err := orderitems.Decode(strings.NewReader(tt.json))
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.lines, lines)
+		})
+	}
+}
+
+func TestOrderItems_ReadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "items.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[
+		{"sku": "laptop", "qty": 1, "price": 999.99},
+		{"sku": "mouse", "qty": 2, "price": 24.995},
+		{"sku": "kayak", "price": 600, "vendor": "acme"}
+	]`), 0600))
+
+	lines, err := orderitems.ReadFile(path)
+	require.NoError(t, err)
+	require.Len(t, lines, 3)
+	assert.Equal(t, 1, lines[2].Quantity, "a line without qty is one unit")
+
+	items, total := orderitems.Expand(lines)
+	assert.Equal(t, []string{"laptop", "mouse", "mouse", "kayak"}, items)
+	assert.Equal(t, 1649.98, total)
+	assert.Equal(t, map[string]string{"kayak": "acme"}, orderitems.Vendors(lines))
+
+	_, err = orderitems.ReadFile(filepath.Join(t.TempDir(), "missing.json"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,92 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/orderwait"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/mocks"
+	"go.temporal.io/sdk/temporal"
+)
+
+// newAwaitedRun returns a workflow run of an order whose Get returns get,
+// and a client whose getStatus query answers with status, or fails when
+// status is nil
+func newAwaitedRun(t *testing.T, get func(ctx context.Context, valuePtr any) error, status *models.OrderStatus) (*mocks.Client, *mocks.WorkflowRun) {
+	run := &mocks.WorkflowRun{}
+	run.On("GetID").Return("order-workflow-ORDER-001")
+	run.On("GetRunID").Return("run-1")
+	run.On("Get", mock.Anything, mock.Anything).Return(get)
+
+	c := mocks.NewClient(t)
+	if status == nil {
+		c.On("QueryWorkflow", mock.Anything, "order-workflow-ORDER-001", "run-1", "getStatus").
+			Return(nil, errors.New("no worker polling the task queue")).Maybe()
+		return c, run
+	}
+	value := mocks.NewEncodedValue(t)
+	value.On("Get", mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).(*models.OrderStatus) = *status
+	}).Return(nil)
+	c.On("QueryWorkflow", mock.Anything, "order-workflow-ORDER-001", "run-1", "getStatus").Return(value, nil)
+	return c, run
+}
+
+func TestAwait_Completed(t *testing.T) {
+	c, run := newAwaitedRun(t, func(_ context.Context, valuePtr any) error {
+		*valuePtr.(**models.OrderResult) = &models.OrderResult{OrderID: "ORDER-001", Status: models.StatusCompleted}
+		return nil
+	}, &models.OrderStatus{OrderID: "ORDER-001", Status: models.StatusCompleted})
+
+	outcome := orderwait.Await(context.Background(), c, run, time.Minute)
+	assert.Equal(t, 0, outcome.ExitCode)
+	require.NotNil(t, outcome.Result)
+	assert.Equal(t, models.StatusCompleted, outcome.Result.Status)
+	require.NotNil(t, outcome.FinalStatus)
+	assert.Equal(t, models.StatusCompleted, outcome.FinalStatus.Status)
+	assert.Nil(t, outcome.Error)
+}
+
+func TestAwait_WorkflowFailedExitsFailed(t *testing.T) {
+	c, run := newAwaitedRun(t, func(context.Context, any) error {
+		return temporal.NewNonRetryableApplicationError("card declined", models.ErrTypePaymentDeclined, nil)
+	}, &models.OrderStatus{OrderID: "ORDER-001", Status: models.StatusFailed})
+
+	outcome := orderwait.Await(context.Background(), c, run, time.Minute)
+	assert.Equal(t, orderwait.ExitFailed, outcome.ExitCode)
+	require.NotNil(t, outcome.Error)
+	assert.Equal(t, models.ErrTypePaymentDeclined, outcome.Error.Code)
+	assert.False(t, outcome.Error.Retryable)
+	require.NotNil(t, outcome.FinalStatus, "the final status is queried after a failure too")
+	assert.Equal(t, models.StatusFailed, outcome.FinalStatus.Status)
+}
+
+func TestAwait_OrderCancelledExitsFailed(t *testing.T) {
+	// Executions started before OrderWorkflow returned a result are judged
+	// by their queried status
+	c, run := newAwaitedRun(t, func(context.Context, any) error { return nil },
+		&models.OrderStatus{OrderID: "ORDER-001", Status: models.StatusCancelled})
+
+	outcome := orderwait.Await(context.Background(), c, run, time.Minute)
+	assert.Equal(t, orderwait.ExitFailed, outcome.ExitCode)
+	assert.Nil(t, outcome.Error)
+}
+
+func TestAwait_TimeoutExitsTimeout(t *testing.T) {
+	c, run := newAwaitedRun(t, func(ctx context.Context, _ any) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, nil)
+
+	outcome := orderwait.Await(context.Background(), c, run, 10*time.Millisecond)
+	assert.Equal(t, orderwait.ExitTimeout, outcome.ExitCode)
+	assert.Nil(t, outcome.Result)
+	assert.Nil(t, outcome.FinalStatus, "a running order is not queried")
+	c.AssertNotCalled(t, "QueryWorkflow", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
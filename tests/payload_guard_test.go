@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/interceptors"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+const testPayloadLimit = 1024
+
+func repeatActivity(ctx context.Context, n int) (string, error) {
+	return strings.Repeat("x", n), nil
+}
+
+func lengthActivity(ctx context.Context, value string) (int, error) {
+	return len(value), nil
+}
+
+// payloadWorkflow sends input to an activity when it is set, otherwise asks
+// an activity to produce a result of size bytes
+func payloadWorkflow(ctx workflow.Context, input string, size int) (string, error) {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{StartToCloseTimeout: time.Minute})
+	if input != "" {
+		var n int
+		err := workflow.ExecuteActivity(ctx, lengthActivity, input).Get(ctx, &n)
+		return "", err
+	}
+	var result string
+	err := workflow.ExecuteActivity(ctx, repeatActivity, size).Get(ctx, &result)
+	return result, err
+}
+
+func newPayloadGuardTestEnv() *testsuite.TestWorkflowEnvironment {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	env.SetWorkerOptions(worker.Options{
+		Interceptors: []interceptor.WorkerInterceptor{interceptors.NewPayloadGuard(testPayloadLimit)},
+	})
+	env.RegisterWorkflow(payloadWorkflow)
+	env.RegisterActivity(repeatActivity)
+	env.RegisterActivity(lengthActivity)
+	return env
+}
+
+func TestPayloadGuard_AllowsSmallPayloads(t *testing.T) {
+	env := newPayloadGuardTestEnv()
+
+	env.ExecuteWorkflow(payloadWorkflow, "", 100)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var result string
+	require.NoError(t, env.GetWorkflowResult(&result))
+	assert.Len(t, result, 100)
+}
+
+func TestPayloadGuard_RejectsOversizedActivityInput(t *testing.T) {
+	env := newPayloadGuardTestEnv()
+
+	env.ExecuteWorkflow(payloadWorkflow, strings.Repeat("x", 2*testPayloadLimit), 0)
+
+	require.True(t, env.IsWorkflowCompleted())
+	err := env.GetWorkflowError()
+	require.Error(t, err)
+	var appErr *temporal.ApplicationError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, models.ErrTypePayloadTooLarge, appErr.Type())
+	assert.True(t, appErr.NonRetryable())
+	assert.Contains(t, appErr.Error(), "activity lengthActivity input")
+}
+
+func TestPayloadGuard_RejectsOversizedActivityResult(t *testing.T) {
+	env := newPayloadGuardTestEnv()
+
+	env.ExecuteWorkflow(payloadWorkflow, "", 2*testPayloadLimit)
+
+	require.True(t, env.IsWorkflowCompleted())
+	err := env.GetWorkflowError()
+	require.Error(t, err)
+	var appErr *temporal.ApplicationError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, models.ErrTypePayloadTooLarge, appErr.Type())
+
+	var details models.PayloadTooLargeError
+	require.NoError(t, appErr.Details(&details))
+	assert.Equal(t, testPayloadLimit, details.Limit)
+	assert.Greater(t, details.Size, testPayloadLimit)
+}
+
+func TestPayloadGuard_RejectsOversizedWorkflowStart(t *testing.T) {
+	guard := interceptors.NewPayloadGuard(testPayloadLimit)
+	// The guard must fail before reaching the next interceptor, so none is needed
+	outbound := guard.InterceptClient(nil)
+
+	order := models.Order{ID: "TEST-BIG-001", Amount: 100.0}
+	for i := 0; i < 200; i++ {
+		order.Items = append(order.Items, "item-with-a-long-sku")
+	}
+
+	_, err := outbound.ExecuteWorkflow(context.Background(), &interceptor.ClientExecuteWorkflowInput{
+		WorkflowType: "OrderWorkflow",
+		Args:         []interface{}{order},
+	})
+
+	var tooLarge *models.PayloadTooLargeError
+	require.True(t, errors.As(err, &tooLarge))
+	assert.Equal(t, "workflow OrderWorkflow input", tooLarge.Operation)
+	assert.Equal(t, testPayloadLimit, tooLarge.Limit)
+}
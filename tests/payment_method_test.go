@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/codec"
+	"github.com/aswathylr-builds/temporal-order-processing/interceptors"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/temporal"
+)
+
+func TestPaymentMethod_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		method models.PaymentMethod
+		err    string
+	}{
+		{"card", models.PaymentMethod{Type: models.PaymentMethodCard, Card: &models.CardMethod{PaymentToken: "tok_visa", Last4: "4242"}}, ""},
+		{"card without token", models.PaymentMethod{Type: models.PaymentMethodCard, Card: &models.CardMethod{}}, "payment_method.card.payment_token is required"},
+		{"wallet", models.PaymentMethod{Type: models.PaymentMethodWallet, Wallet: &models.WalletMethod{Provider: "apple_pay", PaymentToken: "tok_apple"}}, ""},
+		{"unknown wallet", models.PaymentMethod{Type: models.PaymentMethodWallet, Wallet: &models.WalletMethod{Provider: "venmo", PaymentToken: "tok"}}, "payment_method.wallet.provider must be one of"},
+		{"bank transfer", models.PaymentMethod{Type: models.PaymentMethodBankTransfer, BankTransfer: &models.BankTransferMethod{AccountHolder: "Ada Lovelace", AccountNumber: "000123456789", RoutingNumber: "110000000"}}, ""},
+		{"bad routing number", models.PaymentMethod{Type: models.PaymentMethodBankTransfer, BankTransfer: &models.BankTransferMethod{AccountHolder: "Ada Lovelace", AccountNumber: "000123456789", RoutingNumber: "1100"}}, "payment_method.bank_transfer.routing_number must be 9 digits"},
+		{"gift card", models.PaymentMethod{Type: models.PaymentMethodGiftCard, GiftCard: &models.GiftCardMethod{CardNumber: "GC-1234"}}, ""},
+		{"details of another type", models.PaymentMethod{Type: models.PaymentMethodGiftCard, Card: &models.CardMethod{PaymentToken: "tok"}}, "payment_method.card is not allowed for a gift_card method"},
+		{"unknown type", models.PaymentMethod{Type: "cash"}, `payment_method.type must be one of card, wallet, bank_transfer, gift_card, got "cash"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.method.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestProcessPayment_RoutesByPaymentMethod(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+
+	resp, err := orderActivities.ProcessPayment(context.Background(), models.PaymentRequest{
+		OrderID:       "TEST-PM-001",
+		Amount:        50,
+		PaymentMethod: &models.PaymentMethod{Type: models.PaymentMethodWallet, Wallet: &models.WalletMethod{Provider: "google_pay", PaymentToken: "tok_google"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "google_pay", resp.Route)
+
+	_, err = orderActivities.ProcessPayment(context.Background(), models.PaymentRequest{
+		OrderID:       "TEST-PM-001",
+		Amount:        50,
+		PaymentMethod: &models.PaymentMethod{Type: models.PaymentMethodCard},
+	})
+	var appErr *temporal.ApplicationError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, models.ErrTypePaymentDeclined, appErr.Type())
+	var declined models.PaymentDeclinedError
+	require.NoError(t, appErr.Details(&declined))
+	assert.Equal(t, "invalid_payment_method", declined.DeclineCode)
+}
+
+func TestPaymentMethod_SecretsAreRedactedAndEncrypted(t *testing.T) {
+	request := models.PaymentRequest{
+		OrderID: "TEST-PM-002",
+		Amount:  50,
+		PaymentMethod: &models.PaymentMethod{
+			Type:         models.PaymentMethodBankTransfer,
+			BankTransfer: &models.BankTransferMethod{AccountHolder: "Ada Lovelace", AccountNumber: "000123456789", RoutingNumber: "110000000"},
+		},
+	}
+
+	redacted := interceptors.NewRedactor(interceptors.DefaultRedactedFields).Redact(request).(map[string]interface{})
+	bank := redacted["payment_method"].(map[string]interface{})["bank_transfer"].(map[string]interface{})
+	assert.Equal(t, interceptors.RedactedValue, bank["account_number"])
+	assert.Equal(t, "110000000", bank["routing_number"])
+
+	fields := codec.NewFieldEncryptionCodec(codec.Keyring{Current: codec.StaticKey(testKey())}, codec.DefaultEncryptedFields)
+	payload, err := converter.GetDefaultDataConverter().ToPayload(models.PaymentRequest{
+		OrderID:       "TEST-PM-002",
+		PaymentMethod: &models.PaymentMethod{Type: models.PaymentMethodCard, Card: &models.CardMethod{PaymentToken: "tok_visa_4242"}},
+	})
+	require.NoError(t, err)
+	encoded, err := fields.Encode([]*commonpb.Payload{payload})
+	require.NoError(t, err)
+	assert.NotContains(t, string(encoded[0].Data), "tok_visa_4242")
+	assert.Contains(t, string(encoded[0].Data), "TEST-PM-002")
+}
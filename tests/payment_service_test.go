@@ -0,0 +1,140 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"github.com/nexus-rpc/sdk-go/nexus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+)
+
+func TestOrderWorkflow_PaysThroughNexusEndpoint(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newDynamicConfigTestEnv(orderActivities, models.DynamicConfig{PaymentNexusEndpoint: "payments"})
+	paymentService, err := workflows.NewPaymentService()
+	require.NoError(t, err)
+	env.OnNexusOperation(paymentService, workflows.ProcessPaymentOperation, mock.Anything, mock.Anything).Return(
+		&nexus.HandlerStartOperationResultSync[*models.PaymentResponse]{
+			Value: &models.PaymentResponse{Success: true, TransactionID: "TXN-NEXUS-123"},
+		},
+		nil,
+	)
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:     "TEST-NEXUS-001",
+		Items:  []string{"item1"},
+		Amount: 50.0,
+		Status: models.StatusPending,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var result models.OrderResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	assert.Equal(t, "TXN-NEXUS-123", result.TransactionID)
+}
+
+func TestPaymentService_RunsPaymentWorkflow(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newDynamicConfigTestEnv(orderActivities, models.DynamicConfig{PaymentNexusEndpoint: "payments"})
+	paymentService, err := workflows.NewPaymentService()
+	require.NoError(t, err)
+	env.RegisterNexusService(paymentService)
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:     "TEST-NEXUS-002",
+		Items:  []string{"item1"},
+		Amount: 50.0,
+		Status: models.StatusPending,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var result models.OrderResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	assert.Equal(t, "TXN-FULFILL-123", result.TransactionID)
+}
+
+func newSplitTenderTestEnv(orderActivities *activities.OrderActivities) *testsuite.TestWorkflowEnvironment {
+	env := (&testsuite.WorkflowTestSuite{}).NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(workflows.PaymentWorkflow)
+	env.RegisterActivity(orderActivities.ProcessPayment)
+	env.RegisterActivity(orderActivities.RefundPayment)
+	return env
+}
+
+var splitTenderOrder = models.Order{
+	ID:      "TEST-TENDER-001",
+	Items:   []string{"item1"},
+	Amount:  100.0,
+	Tenders: []models.Tender{{Method: models.TenderGiftCard, Amount: 25}, {Method: models.TenderCard, Amount: 75}},
+}
+
+func TestPaymentWorkflow_ChargesEachTenderInOrder(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newSplitTenderTestEnv(orderActivities)
+	var charged []models.PaymentRequest
+	env.OnActivity(orderActivities.ProcessPayment, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, req models.PaymentRequest) (*models.PaymentResponse, error) {
+			charged = append(charged, req)
+			return &models.PaymentResponse{Success: true, TransactionID: "TXN-" + req.Method}, nil
+		})
+
+	env.ExecuteWorkflow(workflows.PaymentWorkflow, splitTenderOrder)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, []models.PaymentRequest{
+		{OrderID: "TEST-TENDER-001", Amount: 25, Method: models.TenderGiftCard},
+		{OrderID: "TEST-TENDER-001", Amount: 75, Method: models.TenderCard},
+	}, charged)
+	var resp models.PaymentResponse
+	require.NoError(t, env.GetWorkflowResult(&resp))
+	assert.True(t, resp.Success)
+	assert.Equal(t, "TXN-gift_card,TXN-card", resp.TransactionID)
+	assert.Equal(t, []models.TenderPayment{
+		{Method: models.TenderGiftCard, Amount: 25, TransactionID: "TXN-gift_card"},
+		{Method: models.TenderCard, Amount: 75, TransactionID: "TXN-card"},
+	}, resp.Tenders)
+}
+
+func TestPaymentWorkflow_DeclinedTenderRollsBackEarlierCaptures(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newSplitTenderTestEnv(orderActivities)
+	env.OnActivity(orderActivities.ProcessPayment, mock.Anything, mock.MatchedBy(func(req models.PaymentRequest) bool {
+		return req.Method == models.TenderGiftCard
+	})).Return(&models.PaymentResponse{Success: true, TransactionID: "TXN-GIFT"}, nil)
+	env.OnActivity(orderActivities.ProcessPayment, mock.Anything, mock.Anything).Return(nil,
+		(&models.PaymentDeclinedError{OrderID: "TEST-TENDER-001", Amount: 75, DeclineCode: "insufficient_funds", Reason: "declined"}).ApplicationError())
+	var refunds []models.RefundRequest
+	env.OnActivity(orderActivities.RefundPayment, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { refunds = append(refunds, args.Get(1).(models.RefundRequest)) }).
+		Return(nil)
+
+	env.ExecuteWorkflow(workflows.PaymentWorkflow, splitTenderOrder)
+
+	require.True(t, env.IsWorkflowCompleted())
+	var appErr *temporal.ApplicationError
+	require.ErrorAs(t, env.GetWorkflowError(), &appErr)
+	assert.Equal(t, models.ErrTypePaymentDeclined, appErr.Type())
+	assert.Equal(t, []models.RefundRequest{{OrderID: "TEST-TENDER-001", TransactionID: "TXN-GIFT", Amount: 25}}, refunds)
+}
+
+func TestOrder_ValidateTenders(t *testing.T) {
+	assert.NoError(t, models.Order{Amount: 100}.ValidateTenders())
+	assert.NoError(t, splitTenderOrder.ValidateTenders())
+
+	short := splitTenderOrder
+	short.Amount = 120
+	assert.ErrorContains(t, short.ValidateTenders(), "not the order amount")
+	assert.Error(t, models.Order{Amount: 10, Tenders: []models.Tender{{Method: models.TenderCard, Amount: -10}, {Method: models.TenderCard, Amount: 20}}}.ValidateTenders())
+}
@@ -0,0 +1,100 @@
+package tests
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/testsuite"
+)
+
+// progressRecorder captures the progress heartbeats of an activity under test
+type progressRecorder struct {
+	mu       sync.Mutex
+	progress []models.ProcessingProgress
+}
+
+func (r *progressRecorder) listen(_ *activity.Info, details converter.EncodedValues) {
+	var p models.ProcessingProgress
+	if details.HasValues() && details.Get(&p) == nil {
+		r.mu.Lock()
+		r.progress = append(r.progress, p)
+		r.mu.Unlock()
+	}
+}
+
+func (r *progressRecorder) first() models.ProcessingProgress {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.progress) == 0 {
+		return models.ProcessingProgress{}
+	}
+	return r.progress[0]
+}
+
+func TestProcessOrder_HeartbeatsProgressPerItem(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+	recorder := &progressRecorder{}
+	env.SetOnActivityHeartbeatListener(recorder.listen)
+
+	orderActivities := activities.NewOrderActivities("http://unused")
+	orderActivities.ProcessingTime = 150 * time.Millisecond
+	env.RegisterActivity(orderActivities.ProcessOrder)
+
+	order := models.Order{ID: "PROGRESS-001", Items: []string{"item1", "item2", "item3"}, Amount: 30.0}
+	_, err := env.ExecuteActivity("ProcessOrder", order, false)
+	require.NoError(t, err)
+
+	// Heartbeats are throttled, so only the first checkpoint is reliably delivered
+	progress := recorder.first()
+	assert.Equal(t, 1, progress.ItemsProcessed)
+	assert.Equal(t, "item1", progress.LastProcessedItem)
+	assert.InDelta(t, 33.33, progress.PercentComplete, 0.01)
+}
+
+func TestProcessOrder_ResumesFromHeartbeatDetails(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+	recorder := &progressRecorder{}
+	env.SetOnActivityHeartbeatListener(recorder.listen)
+	env.SetHeartbeatDetails(models.ProcessingProgress{
+		ItemsProcessed:    3,
+		LastProcessedItem: "item3",
+		PercentComplete:   75,
+	})
+
+	orderActivities := activities.NewOrderActivities("http://unused")
+	orderActivities.ProcessingTime = 2 * time.Second
+	env.RegisterActivity(orderActivities.ProcessOrder)
+
+	order := models.Order{ID: "PROGRESS-002", Items: []string{"item1", "item2", "item3", "item4"}, Amount: 40.0}
+	start := time.Now()
+	_, err := env.ExecuteActivity("ProcessOrder", order, false)
+	require.NoError(t, err)
+
+	// Only the one remaining item is processed
+	assert.Less(t, time.Since(start), time.Second)
+	progress := recorder.first()
+	assert.Equal(t, 4, progress.ItemsProcessed)
+	assert.Equal(t, "item4", progress.LastProcessedItem)
+	assert.InDelta(t, 100.0, progress.PercentComplete, 0.001)
+}
+
+func TestProcessOrder_CancelledMidway(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://unused")
+	orderActivities.ProcessingTime = time.Minute
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := orderActivities.ProcessOrder(ctx, models.Order{ID: "PROGRESS-003", Items: []string{"item1"}}, false)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
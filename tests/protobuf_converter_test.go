@@ -0,0 +1,139 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/proto/orderspb"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/converter"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestProtobufConverter_RoundTripsModels(t *testing.T) {
+	dataConverter := orderspb.NewDataConverter(true)
+	order := models.Order{
+		ID:        "TEST-PB-001",
+		Items:     []string{"item1", "item2"},
+		Amount:    99.5,
+		Status:    models.StatusPending,
+		CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 6, time.UTC),
+		Tenders:   []models.Tender{{Method: models.TenderGiftCard, Amount: 20, Card: "GC-1234"}, {Method: models.TenderCard, Amount: 79.5}},
+		Vendors:   map[string]string{"item2": "acme"},
+		Currency:  "EUR",
+		Customer:  &models.Customer{ID: "CUST-7", Name: "Ada Lovelace", Email: "ada@example.com", Tier: models.CustomerTierGold},
+		PaymentMethod: &models.PaymentMethod{
+			Type: models.PaymentMethodCard,
+			Card: &models.CardMethod{PaymentToken: "tok_visa_4242", Brand: "visa", Last4: "4242", ExpMonth: 12, ExpYear: 2030},
+		},
+		Shipping: &models.ShippingInfo{
+			Method:     models.ShippingOvernight,
+			Carrier:    "ups",
+			Address:    &models.Address{Name: "Ada Lovelace", Line1: "12 St James's Square", City: "London", PostalCode: "SW1Y 4JH", Country: "GB"},
+			PromisedBy: time.Date(2026, 1, 3, 17, 0, 0, 0, time.UTC),
+		},
+		PriorPayment: &models.PaymentResponse{Success: true, TransactionID: "TXN-PB-1"},
+	}
+
+	payload, err := dataConverter.ToPayload(order)
+	require.NoError(t, err)
+	assert.Equal(t, converter.MetadataEncodingProto, string(payload.Metadata[converter.MetadataEncoding]))
+	assert.Equal(t, "orderprocessing.v1.Order", string(payload.Metadata[converter.MetadataMessageType]))
+
+	// Consumers in other languages read the same bytes with orders.proto
+	var message orderspb.Order
+	require.NoError(t, proto.Unmarshal(payload.Data, &message))
+	assert.Equal(t, "TEST-PB-001", message.GetId())
+
+	var decoded models.Order
+	require.NoError(t, dataConverter.FromPayload(payload, &decoded))
+	assert.Equal(t, order, decoded)
+
+	jsonPayload, err := converter.GetDefaultDataConverter().ToPayload(order)
+	require.NoError(t, err)
+	assert.Less(t, len(payload.Data), len(jsonPayload.Data))
+
+	status := &models.OrderStatus{
+		OrderID:        "TEST-PB-001",
+		Status:         models.StatusPartiallyCompleted,
+		ItemResults:    []models.ItemFulfillment{{Item: "item1", Status: "failed", Error: "out of stock"}},
+		ShipmentStatus: models.ShipmentInTransit,
+		Shipments: []models.OrderShipment{{
+			ShipmentID:     models.WarehouseShipmentID("TEST-PB-001", 1),
+			WarehouseID:    "east",
+			Items:          []string{"item2"},
+			Status:         models.ItemFulfilled,
+			TrackingStatus: models.ShipmentInTransit,
+		}},
+		Vendors: []models.VendorFulfillment{{
+			PurchaseOrder: models.PurchaseOrderNumber("TEST-PB-001", "acme"),
+			VendorID:      "acme",
+			Items:         []string{"item2"},
+			Status:        models.VendorAccepted,
+			LastUpdated:   time.Date(2026, 1, 2, 4, 0, 0, 0, time.UTC),
+		}},
+	}
+	payload, err = dataConverter.ToPayload(status)
+	require.NoError(t, err)
+	var decodedStatus *models.OrderStatus
+	require.NoError(t, dataConverter.FromPayload(payload, &decodedStatus))
+	assert.Equal(t, status, decodedStatus)
+}
+
+func TestProtobufConverter_DecodeOnlyStillEncodesJSON(t *testing.T) {
+	response := models.PaymentResponse{
+		Success:       true,
+		TransactionID: "TXN-PB-001",
+		Tenders:       []models.TenderPayment{{Method: models.TenderCard, Amount: 10, TransactionID: "TXN-PB-001"}},
+		Conversion: &models.CurrencyConversion{
+			From: "EUR", To: "USD", Rate: 1.08, RateAsOf: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), Amount: 9.26, ConvertedAmount: 10,
+		},
+	}
+	protoPayload, err := orderspb.NewDataConverter(true).ToPayload(response)
+	require.NoError(t, err)
+
+	decodeOnly := orderspb.NewDataConverter(false)
+	payload, err := decodeOnly.ToPayload(response)
+	require.NoError(t, err)
+	assert.Equal(t, converter.MetadataEncodingJSON, string(payload.Metadata[converter.MetadataEncoding]))
+
+	var decoded models.PaymentResponse
+	require.NoError(t, decodeOnly.FromPayload(protoPayload, &decoded))
+	assert.Equal(t, response, decoded)
+}
+
+func TestProtobufConverter_RejectsOtherMessageType(t *testing.T) {
+	dataConverter := orderspb.NewDataConverter(true)
+	payload, err := dataConverter.ToPayload(models.PaymentRequest{OrderID: "TEST-PB-002", Amount: 10})
+	require.NoError(t, err)
+
+	var order models.Order
+	assert.ErrorContains(t, dataConverter.FromPayload(payload, &order), "orderprocessing.v1.PaymentRequest")
+}
+
+func TestOrderWorkflow_WithProtobufPayloads(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newFulfillmentTestEnv(orderActivities)
+	env.SetDataConverter(orderspb.NewDataConverter(true))
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:     "TEST-PB-003",
+		Items:  []string{"item1"},
+		Amount: 100.0,
+		Status: models.StatusPending,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	result, err := env.QueryWorkflow("getStatus")
+	require.NoError(t, err)
+	var state models.OrderStatus
+	require.NoError(t, result.Get(&state))
+	assert.Equal(t, models.StatusCompleted, state.Status)
+}
@@ -0,0 +1,59 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter_QueuesRequests(t *testing.T) {
+	limiter := activities.NewRateLimiter("validation", activities.RateLimitConfig{
+		RequestsPerSecond: 10,
+		Burst:             1,
+		MaxWait:           time.Second,
+	})
+
+	ctx := context.Background()
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		require.NoError(t, limiter.Wait(ctx))
+	}
+
+	// The first request uses the burst token, the next two wait ~100ms each
+	assert.GreaterOrEqual(t, time.Since(start), 180*time.Millisecond)
+}
+
+func TestRateLimiter_RejectsWhenQueueTooLong(t *testing.T) {
+	limiter := activities.NewRateLimiter("validation", activities.RateLimitConfig{
+		RequestsPerSecond: 1,
+		Burst:             1,
+		MaxWait:           500 * time.Millisecond,
+	})
+
+	ctx := context.Background()
+	require.NoError(t, limiter.Wait(ctx))
+
+	err := limiter.Wait(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rate limit queue full")
+}
+
+func TestRateLimiter_CancelledWhileWaiting(t *testing.T) {
+	limiter := activities.NewRateLimiter("validation", activities.RateLimitConfig{
+		RequestsPerSecond: 1,
+		Burst:             1,
+		MaxWait:           5 * time.Second,
+	})
+
+	require.NoError(t, limiter.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := limiter.Wait(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,147 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/store"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+)
+
+func newReconciliationTestEnv(orderActivities *activities.OrderActivities, storeActivities *store.StoreActivities) *testsuite.TestWorkflowEnvironment {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	env.RegisterActivity(orderActivities.FetchGatewayTransactions)
+	env.RegisterActivity(orderActivities.ReportDiscrepancies)
+	env.RegisterActivity(storeActivities.ListCompletedOrders)
+	return env
+}
+
+func TestReconciliationWorkflow_ReportsDiscrepancies(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	storeActivities := store.NewStoreActivities(nil)
+	env := newReconciliationTestEnv(orderActivities, storeActivities)
+	env.OnActivity(orderActivities.FetchGatewayTransactions, mock.Anything, mock.Anything).Return([]models.GatewayTransaction{
+		{ID: "TXN-1", OrderID: "ORD-MATCHED", Type: models.TransactionCapture, Amount: 100},
+		{ID: "TXN-2", OrderID: "ORD-SHORT", Type: models.TransactionCapture, Amount: 80},
+		{ID: "TXN-3", OrderID: "ORD-CANCELLED", Type: models.TransactionCapture, Amount: 50},
+		{ID: "TXN-4", OrderID: "ORD-CANCELLED", Type: models.TransactionRefund, Amount: 50},
+		{ID: "TXN-5", OrderID: "ORD-ORPHAN", Type: models.TransactionCapture, Amount: 25},
+	}, nil)
+	env.OnActivity(storeActivities.ListCompletedOrders, mock.Anything, mock.Anything).Return([]models.CompletedOrder{
+		{OrderID: "ORD-MATCHED", Amount: 100},
+		{OrderID: "ORD-SHORT", Amount: 90},
+		{OrderID: "ORD-UNPAID", Amount: 40},
+	}, nil)
+	var reported models.ReconciliationReport
+	env.OnActivity(orderActivities.ReportDiscrepancies, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, report models.ReconciliationReport) error {
+			reported = report
+			return nil
+		})
+
+	env.ExecuteWorkflow(workflows.ReconciliationWorkflow, models.ReconciliationRequest{Date: "2026-10-17"})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, "2026-10-17", reported.Date)
+	assert.Equal(t, 5, reported.Transactions)
+	assert.Equal(t, 3, reported.Orders)
+	assert.Equal(t, 1, reported.Matched)
+	require.Len(t, reported.Discrepancies, 3, "a fully refunded capture is not a discrepancy")
+	assert.Equal(t, models.Discrepancy{
+		Kind: models.DiscrepancyUnmatchedPayment, OrderID: "ORD-ORPHAN", TransactionIDs: []string{"TXN-5"}, CapturedAmount: 25,
+	}, reported.Discrepancies[0])
+	assert.Equal(t, models.Discrepancy{
+		Kind: models.DiscrepancyAmountMismatch, OrderID: "ORD-SHORT", TransactionIDs: []string{"TXN-2"}, OrderAmount: 90, CapturedAmount: 80,
+	}, reported.Discrepancies[1])
+	assert.Equal(t, models.Discrepancy{
+		Kind: models.DiscrepancyMissingPayment, OrderID: "ORD-UNPAID", OrderAmount: 40,
+	}, reported.Discrepancies[2])
+}
+
+func TestReconciliationWorkflow_DefaultsToPreviousDay(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	storeActivities := store.NewStoreActivities(nil)
+	env := newReconciliationTestEnv(orderActivities, storeActivities)
+	env.SetStartTime(time.Date(2026, 10, 18, 2, 0, 0, 0, time.UTC))
+	var window models.ReconciliationWindow
+	env.OnActivity(orderActivities.FetchGatewayTransactions, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, w models.ReconciliationWindow) ([]models.GatewayTransaction, error) {
+			window = w
+			return nil, nil
+		})
+	env.OnActivity(orderActivities.ReportDiscrepancies, mock.Anything, mock.Anything).Return(nil)
+
+	env.ExecuteWorkflow(workflows.ReconciliationWorkflow, models.ReconciliationRequest{})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC), window.From.UTC())
+	assert.Equal(t, time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC), window.To.UTC())
+	var report models.ReconciliationReport
+	require.NoError(t, env.GetWorkflowResult(&report))
+	assert.Equal(t, "2026-10-17", report.Date)
+	assert.Empty(t, report.Discrepancies)
+}
+
+func TestFetchGatewayTransactions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/transactions", r.URL.Path)
+		assert.Equal(t, "2026-10-17T00:00:00Z", r.URL.Query().Get("from"))
+		assert.Equal(t, "2026-10-18T00:00:00Z", r.URL.Query().Get("to"))
+		json.NewEncoder(w).Encode([]models.GatewayTransaction{{ID: "TXN-1", OrderID: "ORD-1", Type: models.TransactionCapture, Amount: 100}})
+	}))
+	defer server.Close()
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	window := models.ReconciliationWindow{
+		From: time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+	}
+
+	_, err := orderActivities.FetchGatewayTransactions(context.Background(), window)
+	var appErr *temporal.ApplicationError
+	require.True(t, errors.As(err, &appErr), "payments are only simulated without a gateway")
+	assert.Equal(t, models.ErrTypeGatewayNotConfigured, appErr.Type())
+	assert.True(t, appErr.NonRetryable())
+
+	orderActivities.PaymentGatewayURL = server.URL
+	transactions, err := orderActivities.FetchGatewayTransactions(context.Background(), window)
+	require.NoError(t, err)
+	require.Len(t, transactions, 1)
+	assert.Equal(t, "ORD-1", transactions[0].OrderID)
+}
+
+func TestListCompletedOrders_ReadsWindowFromStore(t *testing.T) {
+	repo := newMemoryRepository()
+	storeActivities := store.NewStoreActivities(repo)
+	ctx := context.Background()
+	day := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	for id, status := range map[string]models.OrderStatus{
+		"ORD-TODAY":    {Status: models.StatusCompleted, LastUpdated: day.Add(10 * time.Hour)},
+		"ORD-TOMORROW": {Status: models.StatusCompleted, LastUpdated: day.Add(26 * time.Hour)},
+		"ORD-RUNNING":  {Status: models.StatusProcessing, LastUpdated: day.Add(10 * time.Hour)},
+	} {
+		require.NoError(t, repo.SaveOrder(ctx, models.Order{ID: id, Amount: 100}))
+		status.OrderID = id
+		require.NoError(t, repo.UpdateStatus(ctx, status))
+	}
+
+	orders, err := storeActivities.ListCompletedOrders(ctx, models.ReconciliationWindow{From: day, To: day.AddDate(0, 0, 1)})
+
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	assert.Equal(t, models.CompletedOrder{OrderID: "ORD-TODAY", Amount: 100, CompletedAt: day.Add(10 * time.Hour)}, orders[0])
+}
@@ -0,0 +1,71 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/aswathylr-builds/temporal-order-processing/interceptors"
+	"github.com/aswathylr-builds/temporal-order-processing/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/worker"
+)
+
+type customerProfile struct {
+	Name          string            `json:"name"`
+	Email         string            `json:"email"`
+	Addresses     []map[string]any  `json:"addresses"`
+	PaymentDetail map[string]string `json:"payment_details"`
+}
+
+func TestRedactor_MasksNestedFields(t *testing.T) {
+	redactor := interceptors.NewRedactor(interceptors.DefaultRedactedFields)
+	profile := customerProfile{
+		Name:          "Ada",
+		Email:         "ada@example.com",
+		Addresses:     []map[string]any{{"Shipping_Address": "1 Main St", "country": "UK"}},
+		PaymentDetail: map[string]string{"card_number": "4111111111111111"},
+	}
+
+	redacted := redactor.Redact(profile).(map[string]interface{})
+
+	assert.Equal(t, "Ada", redacted["name"])
+	assert.Equal(t, interceptors.RedactedValue, redacted["email"])
+	assert.Equal(t, interceptors.RedactedValue, redacted["payment_details"])
+	address := redacted["addresses"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, interceptors.RedactedValue, address["Shipping_Address"])
+	assert.Equal(t, "UK", address["country"])
+
+	// The original value is left untouched
+	assert.Equal(t, "ada@example.com", profile.Email)
+}
+
+func TestLoggingInterceptor_RedactsActivityInputAndOutput(t *testing.T) {
+	var buf bytes.Buffer
+	testSuite := &testsuite.WorkflowTestSuite{}
+	testSuite.SetLogger(logging.NewTemporalLogger(logging.New(&buf, logging.Config{Format: logging.FormatJSON, Level: slog.LevelDebug})))
+	env := testSuite.NewTestActivityEnvironment()
+	env.SetWorkerOptions(worker.Options{
+		Interceptors: []interceptor.WorkerInterceptor{interceptors.NewLoggingInterceptor([]string{"email", "card_number"})},
+	})
+
+	lookupCustomer := func(ctx context.Context, profile customerProfile) (customerProfile, error) {
+		profile.PaymentDetail = map[string]string{"card_number": "4111111111111111", "brand": "visa"}
+		return profile, nil
+	}
+	env.RegisterActivity(lookupCustomer)
+
+	_, err := env.ExecuteActivity(lookupCustomer, customerProfile{Name: "Ada", Email: "ada@example.com"})
+	require.NoError(t, err)
+
+	logs := buf.String()
+	assert.Contains(t, logs, "Activity input")
+	assert.Contains(t, logs, "Activity output")
+	assert.Contains(t, logs, "visa")
+	assert.NotContains(t, logs, "ada@example.com")
+	assert.NotContains(t, logs, "4111111111111111")
+}
@@ -0,0 +1,182 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/authz"
+	"github.com/aswathylr-builds/temporal-order-processing/featureflags"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/worker"
+)
+
+func newRiskTestEnv(orderActivities *activities.OrderActivities, history models.CustomerHistory) *testsuite.TestWorkflowEnvironment {
+	env := newFulfillmentTestEnv(orderActivities)
+	env.RegisterWorkflow(workflows.RiskAssessmentWorkflow)
+	env.RegisterActivity(orderActivities.CheckFraud)
+	env.RegisterActivity(orderActivities.GetCustomerHistory)
+	env.RegisterActivity(orderActivities.RequestStepUpVerification)
+	env.OnActivity(orderActivities.GetCustomerHistory, mock.Anything, mock.Anything).Return(&history, nil)
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	return env
+}
+
+func TestRiskAssessmentWorkflow_Tiers(t *testing.T) {
+	tests := []struct {
+		name       string
+		amount     float64
+		customerID string
+		history    models.CustomerHistory
+		tier       string
+	}{
+		{name: "returning customer", amount: 100, customerID: "CUST-42", history: models.CustomerHistory{Orders: 9}, tier: models.RiskTierAutoApprove},
+		{name: "guest", amount: 100, tier: models.RiskTierAutoApprove},
+		{name: "first order", amount: 1200, customerID: "CUST-NEW", tier: models.RiskTierStepUp},
+		{name: "chargebacks", amount: 1000, customerID: "CUST-42", history: models.CustomerHistory{Orders: 9, Chargebacks: 2}, tier: models.RiskTierStepUp},
+		{name: "suspected fraud", amount: 2500, customerID: "CUST-42", history: models.CustomerHistory{Orders: 9}, tier: models.RiskTierManualReview},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testSuite := &testsuite.WorkflowTestSuite{}
+			env := testSuite.NewTestWorkflowEnvironment()
+			orderActivities := activities.NewOrderActivities("http://mock-url")
+			env.RegisterActivity(orderActivities.CheckFraud)
+			env.RegisterActivity(orderActivities.GetCustomerHistory)
+			env.OnActivity(orderActivities.GetCustomerHistory, mock.Anything, tt.customerID).Return(&tt.history, nil).Maybe()
+
+			env.ExecuteWorkflow(workflows.RiskAssessmentWorkflow, models.RiskAssessmentRequest{
+				Order:      models.Order{ID: "TEST-RISK-001", Items: []string{"item1"}, Amount: tt.amount},
+				CustomerID: tt.customerID,
+			})
+
+			require.True(t, env.IsWorkflowCompleted())
+			require.NoError(t, env.GetWorkflowError())
+			var assessment models.RiskAssessment
+			require.NoError(t, env.GetWorkflowResult(&assessment))
+			assert.Equal(t, tt.tier, assessment.Tier, "score %.3f", assessment.Score)
+		})
+	}
+}
+
+func TestOrderWorkflow_StepUpVerifiedOrderSkipsReview(t *testing.T) {
+	useFlags(t, map[string]bool{featureflags.RiskAssessment: true})
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newRiskTestEnv(orderActivities, models.CustomerHistory{CustomerID: "CUST-NEW"})
+	var challenged models.StepUpRequest
+	env.OnActivity(orderActivities.RequestStepUpVerification, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, req models.StepUpRequest) error {
+			challenged = req
+			return nil
+		})
+	require.NoError(t, env.SetMemoOnStart(models.OrderMemo{CustomerID: "CUST-NEW"}.Fields()))
+
+	env.RegisterDelayedCallback(func() {
+		assert.Equal(t, models.StatusAwaitingVerification, queryStatus(t, env).Status)
+		env.SignalWorkflow(models.SignalVerify, models.StepUpResult{Verified: true})
+	}, time.Hour)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:     "TEST-RISK-002",
+		Items:  []string{"item1"},
+		Amount: 1200.0,
+		Status: models.StatusPending,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, models.StatusCompleted, queryStatus(t, env).Status)
+	assert.Equal(t, models.StepUpRequest{OrderID: "TEST-RISK-002", CustomerID: "CUST-NEW"}, challenged)
+}
+
+func TestOrderWorkflow_FailedStepUpEscalatesToManualReview(t *testing.T) {
+	useFlags(t, map[string]bool{featureflags.RiskAssessment: true})
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newRiskTestEnv(orderActivities, models.CustomerHistory{CustomerID: "CUST-NEW"})
+	require.NoError(t, env.SetMemoOnStart(models.OrderMemo{CustomerID: "CUST-NEW"}.Fields()))
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(models.SignalVerify, models.StepUpResult{Reason: "wrong code"})
+	}, time.Hour)
+	env.RegisterDelayedCallback(func() {
+		status := queryStatus(t, env)
+		assert.Equal(t, models.StatusAwaitingApproval, status.Status)
+		assert.Equal(t, "pending", status.PaymentStatus, "payment waits for review")
+		env.SignalWorkflow(models.SignalApprove, nil)
+	}, 2*time.Hour)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:     "TEST-RISK-003",
+		Items:  []string{"item1"},
+		Amount: 1200.0,
+		Status: models.StatusPending,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, models.StatusCompleted, queryStatus(t, env).Status)
+}
+
+func TestGetCustomerHistory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/customers/CUST-42/history" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"orders": 12, "chargebacks": 1}`))
+	}))
+	defer server.Close()
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	orderActivities.CustomerURL = server.URL
+
+	history, err := orderActivities.GetCustomerHistory(context.Background(), "CUST-42")
+	require.NoError(t, err)
+	assert.Equal(t, models.CustomerHistory{CustomerID: "CUST-42", Orders: 12, Chargebacks: 1}, *history)
+
+	history, err = orderActivities.GetCustomerHistory(context.Background(), "CUST-UNKNOWN")
+	require.NoError(t, err)
+	assert.Equal(t, models.CustomerHistory{CustomerID: "CUST-UNKNOWN"}, *history, "an unknown customer is new")
+}
+
+func TestOrderWorkflow_DropsUnsignedVerifySignal(t *testing.T) {
+	useFlags(t, map[string]bool{featureflags.RiskAssessment: true})
+	signer, err := authz.NewSigner("secret")
+	require.NoError(t, err)
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newRiskTestEnv(orderActivities, models.CustomerHistory{CustomerID: "CUST-NEW"})
+	env.SetWorkerOptions(worker.Options{Interceptors: []interceptor.WorkerInterceptor{
+		authz.NewInterceptor(authz.Config{Signer: signer, ProtectedSignals: models.ProtectedSignals}),
+	}})
+	env.OnActivity(orderActivities.RequestStepUpVerification, mock.Anything, mock.Anything).Return(nil)
+	require.NoError(t, env.SetMemoOnStart(models.OrderMemo{CustomerID: "CUST-NEW"}.Fields()))
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(models.SignalVerify, models.StepUpResult{Verified: true})
+	}, time.Hour)
+	env.RegisterDelayedCallback(func() {
+		assert.Equal(t, models.StatusAwaitingVerification, queryStatus(t, env).Status)
+	}, 2*time.Hour)
+	// Unverified once the step-up times out, the order is held for review
+	env.RegisterDelayedCallback(func() {
+		assert.Equal(t, models.StatusAwaitingApproval, queryStatus(t, env).Status)
+		env.CancelWorkflow()
+	}, workflows.StepUpVerificationTimeout+2*time.Hour)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:     "TEST-RISK-004",
+		Items:  []string{"item1"},
+		Amount: 1200.0,
+		Status: models.StatusPending,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+}
@@ -0,0 +1,84 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/temporal"
+)
+
+func TestMigrate_UpgradesVersion1Order(t *testing.T) {
+	order, err := models.Migrate([]byte(`{
+		"id": "TEST-SCHEMA-001",
+		"items": ["mug", "plate", "mug"],
+		"amount": 42.5,
+		"vendors": {"plate": "VENDOR-A"}
+	}`))
+
+	require.NoError(t, err)
+	assert.Equal(t, models.OrderSchemaVersion, order.SchemaVersion)
+	assert.Equal(t, []models.OrderItem{{SKU: "mug", Quantity: 2}, {SKU: "plate", Quantity: 1, Vendor: "VENDOR-A"}}, order.Lines)
+	assert.Equal(t, []string{"mug", "plate", "mug"}, order.Items)
+	assert.Equal(t, 42.5, order.Amount)
+
+	again, err := order.Upgrade()
+	require.NoError(t, err)
+	assert.Equal(t, order, again)
+}
+
+func TestOrder_UpgradeDerivesItemsFromLines(t *testing.T) {
+	order, err := models.Order{
+		ID: "TEST-SCHEMA-002",
+		Lines: []models.OrderItem{
+			{SKU: "mug", Quantity: 2, Price: 9.5},
+			{SKU: "plate", Quantity: 1, Price: 0.1, Vendor: "VENDOR-A"},
+		},
+	}.Upgrade()
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mug", "mug", "plate"}, order.Items)
+	assert.Equal(t, 19.1, order.Amount)
+	assert.Equal(t, map[string]string{"plate": "VENDOR-A"}, order.Vendors)
+
+	_, err = models.Order{
+		Items: []string{"mug"},
+		Lines: []models.OrderItem{{SKU: "mug", Quantity: 2}},
+	}.Upgrade()
+	assert.ErrorContains(t, err, "lines add up to 2")
+	_, err = models.Order{Lines: []models.OrderItem{{SKU: "mug"}}}.Upgrade()
+	assert.ErrorContains(t, err, "lines[0].qty must be positive")
+}
+
+func TestMigrate_RejectsLineOverQuantityCap(t *testing.T) {
+	order, err := models.Migrate([]byte(`{"id": "TEST-SCHEMA-004", "lines": [{"sku": "mug", "qty": 10}]}`))
+	require.NoError(t, err)
+	assert.Len(t, order.Items, models.MaxLineQuantity)
+
+	_, err = models.Migrate([]byte(`{"id": "TEST-SCHEMA-004", "lines": [{"sku": "mug", "qty": 2000000000}]}`))
+	assert.ErrorContains(t, err, "lines[0].qty must be at most 10")
+}
+
+func TestOrderWorkflow_RejectsNewerOrderSchema(t *testing.T) {
+	_, err := models.Migrate([]byte(`{"id": "TEST-SCHEMA-003", "schema_version": 3, "items": ["mug"]}`))
+	assert.True(t, errors.Is(err, models.ErrUnsupportedOrderSchema))
+
+	env := newFulfillmentTestEnv(activities.NewOrderActivities("http://mock-url"))
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:            "TEST-SCHEMA-003",
+		SchemaVersion: models.OrderSchemaVersion + 1,
+		Items:         []string{"item1"},
+		Amount:        100.0,
+		Status:        models.StatusPending,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	var appErr *temporal.ApplicationError
+	require.True(t, errors.As(env.GetWorkflowError(), &appErr))
+	assert.Equal(t, models.ErrTypeOrderSchemaUnsupported, appErr.Type())
+	assert.True(t, appErr.NonRetryable())
+}
@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/featureflags"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/temporal"
+)
+
+func TestScreenCustomer_RejectsDenylistedCustomer(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	orderActivities.ScreeningDenylist = []string{"CUST-BLOCKED"}
+
+	result, err := orderActivities.ScreenCustomer(context.Background(), models.ScreeningRequest{OrderID: "TEST-SCREEN-001", CustomerID: "CUST-OK"})
+	require.NoError(t, err)
+	assert.False(t, result.Match)
+
+	_, err = orderActivities.ScreenCustomer(context.Background(), models.ScreeningRequest{OrderID: "TEST-SCREEN-001", CustomerID: "CUST-BLOCKED"})
+	var appErr *temporal.ApplicationError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, models.ErrTypeCustomerDenied, appErr.Type())
+	assert.True(t, appErr.NonRetryable())
+	var details models.CustomerDeniedError
+	require.NoError(t, appErr.Details(&details))
+	assert.Equal(t, activities.DenylistName, details.List)
+}
+
+func TestScreenCustomer_ReportsSanctionsMatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/screen", r.URL.Path)
+		var req models.ScreeningRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		json.NewEncoder(w).Encode(models.ScreeningResult{Match: req.CustomerID == "CUST-SANCTIONED", List: "ofac-sdn", Reason: "name match"})
+	}))
+	defer server.Close()
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	orderActivities.ScreeningURL = server.URL
+
+	_, err := orderActivities.ScreenCustomer(context.Background(), models.ScreeningRequest{OrderID: "TEST-SCREEN-002", CustomerID: "CUST-SANCTIONED"})
+
+	var appErr *temporal.ApplicationError
+	require.True(t, errors.As(err, &appErr))
+	var details models.CustomerDeniedError
+	require.NoError(t, appErr.Details(&details))
+	assert.Equal(t, "ofac-sdn", details.List)
+	assert.Equal(t, "name match", details.Reason)
+}
+
+func TestOrderWorkflow_ScreeningHitRejectsOrderAndAlertsOps(t *testing.T) {
+	useFlags(t, map[string]bool{featureflags.CustomerScreening: true})
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	orderActivities.ScreeningDenylist = []string{"CUST-BLOCKED"}
+	env := newFulfillmentTestEnv(orderActivities)
+	env.RegisterActivity(orderActivities.ScreenCustomer)
+	env.RegisterActivity(orderActivities.NotifyOpsOfScreeningHit)
+	var alerted models.CustomerDeniedError
+	env.OnActivity(orderActivities.NotifyOpsOfScreeningHit, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, hit models.CustomerDeniedError) error {
+			alerted = hit
+			return nil
+		})
+	require.NoError(t, env.SetMemoOnStart(models.OrderMemo{CustomerID: "CUST-BLOCKED"}.Fields()))
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:     "TEST-SCREEN-003",
+		Items:  []string{"item1"},
+		Amount: 100.0,
+		Status: models.StatusPending,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	var appErr *temporal.ApplicationError
+	require.True(t, errors.As(env.GetWorkflowError(), &appErr))
+	assert.Equal(t, models.ErrTypeCustomerDenied, appErr.Type())
+	assert.Equal(t, "CUST-BLOCKED", alerted.CustomerID)
+	env.AssertNotCalled(t, "ProcessPayment", mock.Anything, mock.Anything)
+	assert.Equal(t, models.StatusFailed, queryStatus(t, env).Status)
+}
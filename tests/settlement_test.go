@@ -0,0 +1,159 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/store"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+)
+
+func newSettlementTestEnv(orderActivities *activities.OrderActivities, storeActivities *store.StoreActivities) *testsuite.TestWorkflowEnvironment {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	env.RegisterActivity(orderActivities.FetchGatewayTransactions)
+	env.RegisterActivity(orderActivities.SettleBatch)
+	env.RegisterActivity(orderActivities.ReportSettlement)
+	env.RegisterActivity(storeActivities.RecordSettlement)
+	return env
+}
+
+func TestSettlementWorkflow_BatchesByMerchantAccount(t *testing.T) {
+	repo := newMemoryRepository()
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	storeActivities := store.NewStoreActivities(repo)
+	env := newSettlementTestEnv(orderActivities, storeActivities)
+	for _, id := range []string{"ORD-1", "ORD-2", "ORD-3"} {
+		require.NoError(t, repo.SaveOrder(context.Background(), models.Order{ID: id, Amount: 100}))
+	}
+	env.OnActivity(orderActivities.FetchGatewayTransactions, mock.Anything, mock.Anything).Return([]models.GatewayTransaction{
+		{ID: "TXN-1", OrderID: "ORD-1", Type: models.TransactionCapture, Amount: 100, MerchantAccount: "us"},
+		{ID: "TXN-2", OrderID: "ORD-2", Type: models.TransactionCapture, Amount: 100, MerchantAccount: "eu"},
+		{ID: "TXN-3", OrderID: "ORD-3", Type: models.TransactionCapture, Amount: 100, MerchantAccount: "us"},
+		{ID: "TXN-4", OrderID: "ORD-3", Type: models.TransactionRefund, Amount: 30, MerchantAccount: "us"},
+	}, nil)
+	var (
+		mu      sync.Mutex
+		settled []models.SettlementBatch
+	)
+	env.OnActivity(orderActivities.SettleBatch, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, batch models.SettlementBatch) (*models.SettlementResult, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			settled = append(settled, batch)
+			return &models.SettlementResult{SettlementID: "SET-" + batch.MerchantAccount}, nil
+		})
+	env.OnActivity(orderActivities.ReportSettlement, mock.Anything, mock.Anything).Return(nil)
+
+	env.ExecuteWorkflow(workflows.SettlementWorkflow, models.SettlementRequest{Date: "2026-10-17"})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var report models.SettlementReport
+	require.NoError(t, env.GetWorkflowResult(&report))
+	assert.Equal(t, 2, report.Settled)
+	require.Len(t, report.Batches, 2)
+	assert.Equal(t, "eu", report.Batches[0].MerchantAccount)
+	assert.Equal(t, models.BatchSettlement{
+		BatchID:         models.SettlementBatchID("2026-10-17", "us"),
+		MerchantAccount: "us",
+		OrderIDs:        []string{"ORD-1", "ORD-3"},
+		Amount:          170,
+		Status:          models.SettlementSettled,
+		SettlementID:    "SET-us",
+	}, report.Batches[1])
+	assert.Len(t, settled, 2)
+
+	for orderID, settlementID := range map[string]string{"ORD-1": "SET-us", "ORD-2": "SET-eu", "ORD-3": "SET-us"} {
+		record, err := repo.GetOrder(context.Background(), orderID)
+		require.NoError(t, err)
+		assert.Equal(t, settlementID, record.SettlementID, orderID)
+	}
+}
+
+func TestSettlementWorkflow_ReportsFailedBatchAndSettlesTheRest(t *testing.T) {
+	repo := newMemoryRepository()
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	storeActivities := store.NewStoreActivities(repo)
+	env := newSettlementTestEnv(orderActivities, storeActivities)
+	for _, id := range []string{"ORD-1", "ORD-2"} {
+		require.NoError(t, repo.SaveOrder(context.Background(), models.Order{ID: id, Amount: 100}))
+	}
+	env.OnActivity(orderActivities.FetchGatewayTransactions, mock.Anything, mock.Anything).Return([]models.GatewayTransaction{
+		{ID: "TXN-1", OrderID: "ORD-1", Type: models.TransactionCapture, Amount: 100, MerchantAccount: "us"},
+		{ID: "TXN-2", OrderID: "ORD-2", Type: models.TransactionCapture, Amount: 100},
+	}, nil)
+	env.OnActivity(orderActivities.SettleBatch, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, batch models.SettlementBatch) (*models.SettlementResult, error) {
+			if batch.MerchantAccount == "us" {
+				return nil, temporal.NewNonRetryableApplicationError("account frozen", models.ErrTypeSettlementRejected, nil)
+			}
+			return &models.SettlementResult{SettlementID: "SET-default"}, nil
+		})
+	var reported models.SettlementReport
+	env.OnActivity(orderActivities.ReportSettlement, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, report models.SettlementReport) error {
+			reported = report
+			return nil
+		})
+
+	env.ExecuteWorkflow(workflows.SettlementWorkflow, models.SettlementRequest{Date: "2026-10-17"})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, 1, reported.Settled)
+	assert.Equal(t, 1, reported.Failed)
+	require.Len(t, reported.Batches, 2)
+	assert.Equal(t, models.DefaultMerchantAccount, reported.Batches[0].MerchantAccount)
+	assert.Equal(t, models.SettlementSettled, reported.Batches[0].Status)
+	assert.Equal(t, models.SettlementFailed, reported.Batches[1].Status)
+	assert.Contains(t, reported.Batches[1].Error, "account frozen")
+
+	unsettled, err := repo.GetOrder(context.Background(), "ORD-1")
+	require.NoError(t, err)
+	assert.Empty(t, unsettled.SettlementID)
+	settled, err := repo.GetOrder(context.Background(), "ORD-2")
+	require.NoError(t, err)
+	assert.Equal(t, "SET-default", settled.SettlementID)
+}
+
+func TestSettleBatch(t *testing.T) {
+	batch := models.SettlementBatch{ID: models.SettlementBatchID("2026-10-17", "us"), MerchantAccount: "us", Amount: 170}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/settlements", r.URL.Path)
+		assert.Equal(t, batch.ID, r.Header.Get("Idempotency-Key"))
+		var received models.SettlementBatch
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		if received.MerchantAccount != "us" {
+			http.Error(w, "unknown merchant account", http.StatusUnprocessableEntity)
+			return
+		}
+		json.NewEncoder(w).Encode(models.SettlementResult{SettlementID: "SET-123"})
+	}))
+	defer server.Close()
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	orderActivities.PaymentGatewayURL = server.URL
+
+	result, err := orderActivities.SettleBatch(context.Background(), batch)
+	require.NoError(t, err)
+	assert.Equal(t, "SET-123", result.SettlementID)
+
+	batch.MerchantAccount = "mars"
+	_, err = orderActivities.SettleBatch(context.Background(), batch)
+	var appErr *temporal.ApplicationError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, models.ErrTypeSettlementRejected, appErr.Type())
+	assert.True(t, appErr.NonRetryable())
+}
@@ -0,0 +1,153 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/featureflags"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShippingInfo_Validate(t *testing.T) {
+	address := &models.Address{Line1: "1 Main St", City: "Springfield", Country: "US"}
+	tests := []struct {
+		name     string
+		shipping models.ShippingInfo
+		field    string
+	}{
+		{name: "standard", shipping: models.ShippingInfo{Method: models.ShippingStandard, Address: address}},
+		{name: "pickup", shipping: models.ShippingInfo{Method: models.ShippingPickup}},
+		{name: "unknown method", shipping: models.ShippingInfo{Method: "drone", Address: address}, field: "shipping.method"},
+		{name: "missing address", shipping: models.ShippingInfo{Method: models.ShippingExpedited}, field: "shipping.address"},
+		{name: "pickup with address", shipping: models.ShippingInfo{Method: models.ShippingPickup, Address: address}, field: "shipping.address"},
+		{name: "bad country", shipping: models.ShippingInfo{Method: models.ShippingStandard, Address: &models.Address{Line1: "1 Main St", City: "Springfield", Country: "USA"}}, field: "shipping.address.country"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.shipping.Validate()
+			if tt.field == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var field models.FieldError
+			require.True(t, errors.As(err, &field))
+			assert.Equal(t, tt.field, field.Field)
+
+			order := models.Order{ID: "TEST-SHIP-001", Items: []string{"item1"}, Amount: 10, Shipping: &tt.shipping}
+			var invalid *models.OrderInvalidError
+			require.True(t, errors.As(order.Validate(), &invalid))
+			assert.Equal(t, tt.field, invalid.Fields[0].Field)
+		})
+	}
+}
+
+func TestOrderWorkflow_ExpeditesForShippingMethod(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newFulfillmentTestEnv(orderActivities)
+	var expedited bool
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
+		func(_ context.Context, _ models.Order, _ string, isExpedited bool) error {
+			expedited = isExpedited
+			return nil
+		})
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:       "TEST-SHIP-002",
+		Items:    []string{"item1"},
+		Amount:   100.0,
+		Status:   models.StatusPending,
+		Shipping: &models.ShippingInfo{Method: models.ShippingOvernight, Address: &models.Address{Line1: "1 Main St", City: "Springfield", Country: "US"}},
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	assert.True(t, expedited)
+	assert.True(t, queryStatus(t, env).IsExpedited)
+}
+
+func TestOrderWorkflow_FlagsMissedDeliveryPromise(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		promise time.Duration
+		missed  bool
+	}{
+		{name: "kept", promise: time.Hour, missed: false},
+		{name: "missed", promise: time.Minute, missed: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			orderActivities := activities.NewOrderActivities("http://mock-url")
+			env := newFulfillmentTestEnv(orderActivities)
+			start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+			env.SetStartTime(start)
+			env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				After(2 * time.Minute).Return(nil)
+
+			env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+				ID:     "TEST-SHIP-003",
+				Items:  []string{"item1"},
+				Amount: 100.0,
+				Status: models.StatusPending,
+				Shipping: &models.ShippingInfo{
+					Method:     models.ShippingStandard,
+					Address:    &models.Address{Line1: "1 Main St", City: "Springfield", Country: "US"},
+					PromisedBy: start.Add(tc.promise),
+				},
+			})
+
+			require.True(t, env.IsWorkflowCompleted())
+			require.NoError(t, env.GetWorkflowError())
+			status := queryStatus(t, env)
+			assert.Equal(t, models.StatusCompleted, status.Status)
+			assert.Equal(t, tc.missed, status.PromiseMissed)
+		})
+	}
+}
+
+func TestNotifyOrderComplete_SendsShipping(t *testing.T) {
+	var received []models.OrderNotification
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var notification models.OrderNotification
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&notification))
+		received = append(received, notification)
+	}))
+	defer server.Close()
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	orderActivities.NotificationURL = server.URL
+	orderActivities.Flags = featureflags.New(staticFlags{featureflags.EmailNotifications: true, featureflags.SMSNotifications: false})
+	promisedBy := time.Date(2026, 3, 2, 17, 0, 0, 0, time.UTC)
+
+	err := orderActivities.NotifyOrderComplete(context.Background(), models.Order{
+		ID:       "TEST-SHIP-004",
+		Items:    []string{"item1"},
+		Amount:   100.0,
+		Customer: &models.Customer{ID: "CUST-7", Email: "ada@example.com"},
+		Shipping: &models.ShippingInfo{
+			Method:     models.ShippingOvernight,
+			Carrier:    "ups",
+			Address:    &models.Address{Line1: "1 Main St", City: "Springfield", Country: "US"},
+			PromisedBy: promisedBy,
+		},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, received, 1)
+	assert.Equal(t, models.OrderNotification{
+		OrderID:        "TEST-SHIP-004",
+		Channel:        "email",
+		Recipient:      "ada@example.com",
+		ShippingMethod: models.ShippingOvernight,
+		Carrier:        "ups",
+		PromisedBy:     promisedBy,
+	}, received[0])
+}
@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/intake"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSQS delivers one batch of messages, then cancels the consumer
+type fakeSQS struct {
+	messages     []types.Message
+	cancel       context.CancelFunc
+	deleted      []string
+	deadLettered []*sqs.SendMessageInput
+	visibility   map[string]int32
+}
+
+func (f *fakeSQS) ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error) {
+	if f.messages == nil {
+		f.cancel()
+		return nil, ctx.Err()
+	}
+	out := &sqs.ReceiveMessageOutput{Messages: f.messages}
+	f.messages = nil
+	return out, nil
+}
+
+func (f *fakeSQS) DeleteMessage(ctx context.Context, params *sqs.DeleteMessageInput, optFns ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error) {
+	f.deleted = append(f.deleted, aws.ToString(params.ReceiptHandle))
+	return &sqs.DeleteMessageOutput{}, nil
+}
+
+func (f *fakeSQS) ChangeMessageVisibility(ctx context.Context, params *sqs.ChangeMessageVisibilityInput, optFns ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityOutput, error) {
+	f.visibility[aws.ToString(params.ReceiptHandle)] = params.VisibilityTimeout
+	return &sqs.ChangeMessageVisibilityOutput{}, nil
+}
+
+func (f *fakeSQS) SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
+	f.deadLettered = append(f.deadLettered, params)
+	return &sqs.SendMessageOutput{}, nil
+}
+
+func sqsMessage(handle, body, receives string) types.Message {
+	return types.Message{
+		MessageId:     aws.String("id-" + handle),
+		ReceiptHandle: aws.String(handle),
+		Body:          aws.String(body),
+		Attributes:    map[string]string{"ApproximateReceiveCount": receives},
+	}
+}
+
+func TestSQSConsumer_SettlesEachMessage(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	api := &fakeSQS{
+		cancel:     cancel,
+		visibility: map[string]int32{},
+		messages: []types.Message{
+			sqsMessage("created", `{"type": "order.create", "order": {"id": "ORD-1", "items": ["mug"], "amount": 9.5}}`, "1"),
+			sqsMessage("malformed", `{"type": "order.create"`, "1"),
+			sqsMessage("failing", `{"type": "order.cancel", "order_id": "ORD-2"}`, "3"),
+		},
+	}
+	submitter := &recordingSubmitter{}
+	canceller := &recordingCanceller{err: errors.New("temporal unavailable")}
+	consumer, err := intake.NewSQSConsumer(api, intake.NewMessageHandler(submitter, canceller), intake.SQSConsumerOptions{
+		QueueURL:           "https://sqs.us-east-1.amazonaws.com/123456789012/order-intake",
+		DeadLetterQueueURL: "https://sqs.us-east-1.amazonaws.com/123456789012/order-intake-dlq",
+		RetryBackoff:       5 * time.Second,
+		MaxRetryBackoff:    time.Minute,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, consumer.Run(ctx))
+
+	require.Len(t, submitter.orders, 1)
+	assert.Equal(t, []string{"created", "malformed"}, api.deleted)
+	require.Len(t, api.deadLettered, 1)
+	assert.Equal(t, `{"type": "order.create"`, aws.ToString(api.deadLettered[0].MessageBody))
+	assert.Contains(t, aws.ToString(api.deadLettered[0].MessageAttributes[intake.InvalidReasonAttribute].StringValue), "invalid order message")
+	// Hidden for the backoff of its third receive before it is retried
+	assert.Equal(t, map[string]int32{"failing": 20}, api.visibility)
+}
@@ -0,0 +1,71 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderStatusCode_Transitions(t *testing.T) {
+	tests := []struct {
+		from, to models.OrderStatusCode
+		allowed  bool
+	}{
+		{models.StatusPending, models.StatusValidating, true},
+		{models.StatusValidating, models.StatusAwaitingApproval, true},
+		{models.StatusAwaitingApproval, models.StatusValidating, true},
+		{models.StatusValidating, models.StatusProcessing, true},
+		{models.StatusProcessing, models.StatusBackordered, true},
+		{models.StatusBackordered, models.StatusProcessing, true},
+		{models.StatusProcessing, models.StatusPartiallyCompleted, true},
+		{models.StatusAwaitingVerification, models.StatusCancelled, true},
+		{models.StatusFailed, models.StatusCancelled, true},
+		{models.StatusCompleted, models.StatusCompleted, true},
+		{models.StatusPending, models.StatusProcessing, false},
+		{models.StatusCompleted, models.StatusProcessing, false},
+		{models.StatusCancelled, models.StatusFailed, false},
+		{models.StatusFailed, models.StatusProcessing, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to), func(t *testing.T) {
+			assert.Equal(t, tt.allowed, tt.from.CanTransitionTo(tt.to))
+		})
+	}
+	assert.True(t, models.StatusFailed.IsTerminal())
+	assert.True(t, models.StatusPartiallyCompleted.IsTerminal())
+	assert.False(t, models.StatusBackordered.IsTerminal())
+}
+
+func TestOrderStatus_TransitionToRefusesIllegalChange(t *testing.T) {
+	state := &models.OrderStatus{OrderID: "TEST-STATUS-001", Status: models.StatusCompleted}
+
+	err := state.TransitionTo(models.StatusProcessing)
+
+	assert.True(t, errors.Is(err, models.ErrIllegalStatusTransition))
+	assert.Equal(t, models.StatusCompleted, state.Status)
+	require.NoError(t, (&models.OrderStatus{Status: models.StatusProcessing}).TransitionTo(models.StatusCompleted))
+}
+
+func TestOrderStatusCode_JSON(t *testing.T) {
+	data, err := json.Marshal(models.StatusChange{Status: models.StatusBackordered, Stage: models.StageProcessing})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"status":"backordered","stage":"processing"`)
+
+	var change models.StatusChange
+	require.NoError(t, json.Unmarshal(data, &change))
+	assert.Equal(t, models.StatusBackordered, change.Status)
+	assert.Equal(t, models.StageProcessing, change.Stage)
+
+	var order models.Order
+	require.NoError(t, json.Unmarshal([]byte(`{"id": "TEST-STATUS-002", "status": ""}`), &order))
+	assert.Empty(t, order.Status)
+	assert.ErrorContains(t, json.Unmarshal([]byte(`{"id": "TEST-STATUS-002", "status": "shipped"}`), &order), `unknown order status "shipped"`)
+	assert.ErrorContains(t, json.Unmarshal([]byte(`{"status": "pending", "stage": "packing"}`), &change), `unknown order stage "packing"`)
+	_, err = json.Marshal(models.OrderResult{Status: "shipped"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,155 @@
+package tests
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/events"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/store"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+)
+
+// memoryRepository is an in-memory OrderRepository for tests
+type memoryRepository struct {
+	mu       sync.Mutex
+	orders   map[string]*store.OrderRecord
+	statuses []models.OrderStatusCode
+	failures []models.OrderFailure
+}
+
+func newMemoryRepository() *memoryRepository {
+	return &memoryRepository{orders: make(map[string]*store.OrderRecord)}
+}
+
+func (r *memoryRepository) SaveOrder(ctx context.Context, order models.Order) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.orders[order.ID] = &store.OrderRecord{
+		ID:     order.ID,
+		Items:  order.Items,
+		Amount: order.Amount,
+		Status: order.Status,
+	}
+	return nil
+}
+
+func (r *memoryRepository) UpdateStatus(ctx context.Context, status models.OrderStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	record, ok := r.orders[status.OrderID]
+	if !ok {
+		return store.ErrOrderNotFound
+	}
+	record.Status = status.Status
+	record.Stage = status.Stage
+	record.PaymentStatus = status.PaymentStatus
+	record.UpdatedAt = status.LastUpdated
+	r.statuses = append(r.statuses, status.Status)
+	return nil
+}
+
+func (r *memoryRepository) GetOrder(ctx context.Context, orderID string) (*store.OrderRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	record, ok := r.orders[orderID]
+	if !ok {
+		return nil, store.ErrOrderNotFound
+	}
+	copied := *record
+	return &copied, nil
+}
+
+func (r *memoryRepository) RecordSettlement(ctx context.Context, settlement models.OrderSettlement) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, orderID := range settlement.OrderIDs {
+		if record, ok := r.orders[orderID]; ok {
+			record.SettlementID = settlement.SettlementID
+		}
+	}
+	return nil
+}
+
+func (r *memoryRepository) ListCompletedOrders(ctx context.Context, from, to time.Time) ([]store.OrderRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var records []store.OrderRecord
+	for _, record := range r.orders {
+		if record.Status == models.StatusCompleted && !record.UpdatedAt.Before(from) && record.UpdatedAt.Before(to) {
+			records = append(records, *record)
+		}
+	}
+	return records, nil
+}
+
+func TestOrderWorkflow_MirrorsStateToStore(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+
+	repo := newMemoryRepository()
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	storeActivities := store.NewStoreActivities(repo)
+	env.RegisterActivity(orderActivities.ValidateOrder)
+	env.RegisterActivity(orderActivities.ProcessPayment)
+	env.RegisterActivity(orderActivities.FulfillItem)
+	env.RegisterActivity(orderActivities.NotifyOrderComplete)
+	env.RegisterActivity(events.NewEventActivities(nil).PublishOrderEvent)
+	env.RegisterActivity(storeActivities.PersistOrder)
+	env.RegisterActivity(storeActivities.UpdateOrderStatus)
+	env.RegisterWorkflow(workflows.OrderWorkflow)
+	env.RegisterWorkflow(workflows.PaymentWorkflow)
+
+	env.OnActivity(orderActivities.ValidateOrder, mock.Anything, mock.Anything).Return(&models.ValidationResponse{Valid: true}, nil)
+	env.OnActivity(orderActivities.ProcessPayment, mock.Anything, mock.Anything).Return(&models.PaymentResponse{
+		Success:       true,
+		TransactionID: "TXN-STORE-123",
+	}, nil)
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	env.OnActivity(orderActivities.NotifyOrderComplete, mock.Anything, mock.Anything).Return(nil)
+
+	order := models.Order{
+		ID:        "TEST-STORE-001",
+		Items:     []string{"item1", "item2"},
+		Amount:    100.0,
+		Status:    models.StatusPending,
+		CreatedAt: time.Now(),
+	}
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, order)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+
+	record, err := repo.GetOrder(context.Background(), order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusCompleted, record.Status)
+	assert.Equal(t, models.StageCompleted, record.Stage)
+	assert.Equal(t, "completed", record.PaymentStatus)
+	assert.Equal(t, models.StatusValidating, repo.statuses[0])
+}
+
+func TestUpdateOrderStatus_UnknownOrder(t *testing.T) {
+	storeActivities := store.NewStoreActivities(newMemoryRepository())
+
+	err := storeActivities.UpdateOrderStatus(context.Background(), models.OrderStatus{
+		OrderID: "MISSING-001",
+		Status:  models.StatusProcessing,
+	})
+
+	require.ErrorIs(t, err, store.ErrOrderNotFound)
+}
+
+func (r *memoryRepository) RecordFailure(ctx context.Context, failure models.OrderFailure) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failures = append(r.failures, failure)
+	return nil
+}
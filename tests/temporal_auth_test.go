@@ -0,0 +1,98 @@
+package tests
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/config"
+	"github.com/aswathylr-builds/temporal-order-processing/temporalauth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testJWT returns an unsigned JWT expiring at exp
+func testJWT(exp time.Time) string {
+	encode := base64.RawURLEncoding.EncodeToString
+	return encode([]byte(`{"alg":"none"}`)) + "." + encode([]byte(fmt.Sprintf(`{"sub":"order-worker","exp":%d}`, exp.Unix()))) + ".sig"
+}
+
+func TestTemporalAuth_OAuth2TokenRefreshedBeforeExpiry(t *testing.T) {
+	var tokenRequests atomic.Int32
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := tokenRequests.Add(1)
+		assert.Equal(t, "temporal", r.FormValue("audience"))
+		w.Header().Set("Content-Type", "application/json")
+		// The first token expires within the refresh window, the second does not
+		expiresIn := 30
+		if n > 1 {
+			expiresIn = 3600
+		}
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":%d}`, n, expiresIn)
+	}))
+	defer tokenServer.Close()
+
+	headers, err := temporalauth.New(temporalauth.Config{
+		OAuth2TokenURL:     tokenServer.URL,
+		OAuth2ClientID:     "order-worker",
+		OAuth2ClientSecret: "secret",
+		OAuth2Audience:     "temporal",
+	}, nil)
+	require.NoError(t, err)
+
+	first, err := headers.GetHeaders(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer token-1", first["authorization"])
+
+	for i := 0; i < 3; i++ {
+		next, err := headers.GetHeaders(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer token-2", next["authorization"])
+	}
+	assert.Equal(t, int32(2), tokenRequests.Load())
+}
+
+func TestTemporalAuth_TokenFileRereadNearExpiry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	expiring := testJWT(time.Now().Add(30 * time.Second))
+	require.NoError(t, os.WriteFile(path, []byte(expiring+"\n"), 0600))
+
+	headers, err := temporalauth.New(temporalauth.Config{TokenFile: path}, nil)
+	require.NoError(t, err)
+	got, err := headers.GetHeaders(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer "+expiring, got["authorization"])
+
+	rotated := testJWT(time.Now().Add(time.Hour))
+	require.NoError(t, os.WriteFile(path, []byte(rotated), 0600))
+	got, err = headers.GetHeaders(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer "+rotated, got["authorization"])
+}
+
+func TestTemporalAuth_NotConfigured(t *testing.T) {
+	headers, err := temporalauth.New(temporalauth.Config{}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, headers)
+}
+
+func TestConfigLoad_TemporalAuthConflictsWithAPIKey(t *testing.T) {
+	t.Setenv("TEMPORAL_NAMESPACE", "orders.a1b2c")
+	t.Setenv("TEMPORAL_API_KEY", "secret-key")
+	t.Setenv("TEMPORAL_AUTH_TOKEN", testJWT(time.Now().Add(time.Hour)))
+
+	_, err := config.Load("")
+	assert.ErrorContains(t, err, "temporal.auth and temporal.api_key")
+
+	t.Setenv("TEMPORAL_API_KEY", "")
+	t.Setenv("TEMPORAL_OAUTH_CLIENT_ID", "order-worker")
+	_, err = config.Load("")
+	assert.ErrorContains(t, err, "temporal.auth: configure one of")
+}
@@ -0,0 +1,110 @@
+package tests
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/tlsconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestCertificate writes a self-signed certificate and key for name into
+// dir and returns their paths
+func writeTestCertificate(t *testing.T, dir, name string) (certFile, keyFile string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile = filepath.Join(dir, "client.crt")
+	keyFile = filepath.Join(dir, "client.key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
+
+func clientCertificateName(t *testing.T, reloader *tlsconfig.Reloader) string {
+	t.Helper()
+	tlsConfig, err := reloader.TLSConfig()
+	require.NoError(t, err)
+	cert, err := tlsConfig.GetClientCertificate(nil)
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	return leaf.Subject.CommonName
+}
+
+func TestTLSConfig_LoadsCABundleAndClientCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeTestCertificate(t, dir, "order-worker")
+
+	reloader, err := tlsconfig.NewReloader(tlsconfig.Config{
+		CAFile:     certFile,
+		CertFile:   certFile,
+		KeyFile:    keyFile,
+		ServerName: "temporal.internal",
+	})
+	require.NoError(t, err)
+
+	tlsConfig, err := reloader.TLSConfig()
+	require.NoError(t, err)
+	assert.NotNil(t, tlsConfig.RootCAs)
+	assert.Equal(t, "temporal.internal", tlsConfig.ServerName)
+	assert.Equal(t, "order-worker", clientCertificateName(t, reloader))
+}
+
+func TestTLSConfig_ReloadSwapsClientCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeTestCertificate(t, dir, "cert-v1")
+
+	reloader, err := tlsconfig.NewReloader(tlsconfig.Config{CertFile: certFile, KeyFile: keyFile})
+	require.NoError(t, err)
+	assert.Equal(t, "cert-v1", clientCertificateName(t, reloader))
+
+	writeTestCertificate(t, dir, "cert-v2")
+	require.NoError(t, reloader.Reload())
+	assert.Equal(t, "cert-v2", clientCertificateName(t, reloader))
+}
+
+func TestTLSConfig_FailedReloadKeepsCurrentCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeTestCertificate(t, dir, "cert-v1")
+
+	reloader, err := tlsconfig.NewReloader(tlsconfig.Config{CertFile: certFile, KeyFile: keyFile})
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(keyFile, []byte("not a key"), 0o600))
+	assert.Error(t, reloader.Reload())
+	assert.Equal(t, "cert-v1", clientCertificateName(t, reloader))
+}
+
+func TestTLSConfig_RequiresCertificateAndKeyTogether(t *testing.T) {
+	dir := t.TempDir()
+	certFile, _ := writeTestCertificate(t, dir, "order-worker")
+
+	_, err := tlsconfig.NewReloader(tlsconfig.Config{CertFile: certFile})
+	assert.Error(t, err)
+}
@@ -0,0 +1,155 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/authz"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+func newTrackingTestEnv(orderActivities *activities.OrderActivities) *testsuite.TestWorkflowEnvironment {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	env.RegisterActivity(orderActivities.TrackShipment)
+	return env
+}
+
+var trackedShipment = models.Shipment{
+	OrderID:      "TEST-TRACKING-001",
+	ShipmentID:   models.ShipmentID("TEST-TRACKING-001"),
+	PollInterval: time.Hour,
+}
+
+func TestTrackingWorkflow_PollsUntilDelivered(t *testing.T) {
+	env := newTrackingTestEnv(activities.NewOrderActivities("http://mock-url"))
+
+	env.ExecuteWorkflow(workflows.TrackingWorkflow, trackedShipment)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var update models.TrackingUpdate
+	require.NoError(t, env.GetWorkflowResult(&update))
+	assert.Equal(t, models.ShipmentDelivered, update.Status, "the simulated carrier delivers on the third poll")
+}
+
+func TestTrackingWorkflow_ContinuesAsNewWhileInTransit(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newTrackingTestEnv(orderActivities)
+	env.OnActivity(orderActivities.TrackShipment, mock.Anything, mock.Anything).Return(
+		&models.TrackingUpdate{Status: models.ShipmentInTransit}, nil)
+
+	env.ExecuteWorkflow(workflows.TrackingWorkflow, trackedShipment)
+
+	require.True(t, env.IsWorkflowCompleted())
+	var continued *workflow.ContinueAsNewError
+	require.True(t, errors.As(env.GetWorkflowError(), &continued))
+	assert.Equal(t, workflows.TrackingWorkflowName, continued.WorkflowType.Name)
+	var next models.Shipment
+	require.NoError(t, converter.GetDefaultDataConverter().FromPayloads(continued.Input, &next))
+	assert.Equal(t, workflows.TrackingPollsPerRun, next.Polls)
+	assert.Equal(t, models.ShipmentInTransit, next.LastStatus)
+	assert.False(t, next.StartedAt.IsZero(), "the next run keeps the tracking start")
+}
+
+func TestTrackingWorkflow_UndeliveredShipmentTimesOut(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newTrackingTestEnv(orderActivities)
+	env.OnActivity(orderActivities.TrackShipment, mock.Anything, mock.Anything).Return(
+		&models.TrackingUpdate{Status: models.ShipmentInTransit}, nil)
+
+	shipment := trackedShipment
+	shipment.StartedAt = env.Now().Add(-workflows.TrackingTimeout)
+	env.ExecuteWorkflow(workflows.TrackingWorkflow, shipment)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var update models.TrackingUpdate
+	require.NoError(t, env.GetWorkflowResult(&update))
+	assert.Equal(t, models.ShipmentException, update.Status)
+	assert.Contains(t, update.Detail, "not delivered")
+}
+
+func TestOrderWorkflow_StaysOpenUntilShipmentDelivered(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newDynamicConfigTestEnv(orderActivities, models.DynamicConfig{TrackingPollInterval: 6 * time.Hour})
+	env.RegisterWorkflow(workflows.TrackingWorkflow)
+	env.RegisterActivity(orderActivities.TrackShipment)
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	var inTransit models.OrderStatus
+	env.RegisterDelayedCallback(func() {
+		inTransit = queryStatus(t, env)
+	}, time.Hour)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:     "TEST-TRACKING-002",
+		Items:  []string{"item1"},
+		Amount: 30.0,
+		Status: models.StatusPending,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, models.StatusCompleted, inTransit.Status)
+	assert.Equal(t, models.ShipmentInTransit, inTransit.ShipmentStatus)
+	status := queryStatus(t, env)
+	assert.Equal(t, models.StatusCompleted, status.Status)
+	assert.Equal(t, models.ShipmentDelivered, status.ShipmentStatus)
+	var result models.OrderResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	assert.GreaterOrEqual(t, result.Duration, 18*time.Hour, "the order closes after three polls")
+}
+
+func TestOrderWorkflow_AcceptsShipmentUpdatesOnlyFromTracking(t *testing.T) {
+	signer, err := authz.NewSigner("secret")
+	require.NoError(t, err)
+	opsToken, err := signer.Sign(authz.Claims{Subject: "ops@example.com"})
+	require.NoError(t, err)
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newDynamicConfigTestEnv(orderActivities, models.DynamicConfig{TrackingPollInterval: 6 * time.Hour})
+	env.SetWorkerOptions(worker.Options{Interceptors: []interceptor.WorkerInterceptor{
+		&signalHeaderInjector{token: opsToken},
+		authz.NewInterceptor(authz.Config{
+			Signer:           signer,
+			ProtectedSignals: models.ProtectedSignals,
+			SignalSenders:    workflows.SignalSenders,
+		}),
+	}})
+	env.RegisterWorkflow(workflows.TrackingWorkflow)
+	env.RegisterActivity(orderActivities.TrackShipment)
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	// A signed caller is still not the tracking child, so its update is dropped
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(models.SignalShipmentUpdate, models.TrackingUpdate{
+			ShipmentID: models.ShipmentID("TEST-TRACKING-003"),
+			Status:     models.ShipmentDelivered,
+		})
+	}, time.Hour)
+	var inTransit models.OrderStatus
+	env.RegisterDelayedCallback(func() {
+		inTransit = queryStatus(t, env)
+	}, 2*time.Hour)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:     "TEST-TRACKING-003",
+		Items:  []string{"item1"},
+		Amount: 30.0,
+		Status: models.StatusPending,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, models.ShipmentInTransit, inTransit.ShipmentStatus)
+	assert.Equal(t, models.ShipmentDelivered, queryStatus(t, env).ShipmentStatus, "the tracking child's own update is accepted")
+}
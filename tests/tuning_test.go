@@ -0,0 +1,114 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/tuning"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/log"
+	"go.temporal.io/sdk/worker"
+)
+
+// fakeSampler reports fixed resource usage
+type fakeSampler struct {
+	memory float64
+	cpu    float64
+}
+
+func (s *fakeSampler) MemoryUsage() float64 { return s.memory }
+func (s *fakeSampler) CPUUsage() float64    { return s.cpu }
+
+// slotInfo is a worker.SlotReservationInfo with a given number of issued slots
+type slotInfo struct {
+	issued int
+}
+
+func (i slotInfo) TaskQueue() string                     { return "test-queue" }
+func (i slotInfo) WorkerBuildId() string                 { return "" }
+func (i slotInfo) WorkerIdentity() string                { return "test-worker" }
+func (i slotInfo) NumIssuedSlots() int                   { return i.issued }
+func (i slotInfo) Logger() log.Logger                    { return nil }
+func (i slotInfo) MetricsHandler() client.MetricsHandler { return client.MetricsNopHandler }
+
+func newTestSlotSupplier(t *testing.T, sampler *fakeSampler) *tuning.ResourceSlotSupplier {
+	options := tuning.DefaultResourceSlotSupplierOptions()
+	options.MinSlots = 2
+	options.MaxSlots = 10
+	options.RampThrottle = 0
+	options.Sampler = sampler
+	supplier, err := tuning.NewResourceSlotSupplier(options)
+	require.NoError(t, err)
+	return supplier
+}
+
+func TestResourceSlotSupplier_GrantsWithHeadroom(t *testing.T) {
+	supplier := newTestSlotSupplier(t, &fakeSampler{memory: 0.5, cpu: 0.5})
+
+	assert.NotNil(t, supplier.TryReserveSlot(slotInfo{issued: 5}))
+	assert.Nil(t, supplier.TryReserveSlot(slotInfo{issued: 10}), "never more than MaxSlots")
+	assert.Equal(t, 10, supplier.MaxSlots())
+}
+
+func TestResourceSlotSupplier_HoldsBackUnderPressure(t *testing.T) {
+	memoryBound := newTestSlotSupplier(t, &fakeSampler{memory: 0.95, cpu: 0.1})
+	assert.Nil(t, memoryBound.TryReserveSlot(slotInfo{issued: 5}))
+	assert.NotNil(t, memoryBound.TryReserveSlot(slotInfo{issued: 1}), "MinSlots are granted regardless of pressure")
+
+	cpuBound := newTestSlotSupplier(t, &fakeSampler{memory: 0.1, cpu: 0.95})
+	assert.Nil(t, cpuBound.TryReserveSlot(slotInfo{issued: 5}))
+}
+
+func TestResourceSlotSupplier_ReserveWaitsForHeadroom(t *testing.T) {
+	sampler := &fakeSampler{memory: 0.95}
+	supplier := newTestSlotSupplier(t, sampler)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := supplier.ReserveSlot(ctx, slotInfo{issued: 5})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	sampler.memory = 0.5
+	permit, err := supplier.ReserveSlot(context.Background(), slotInfo{issued: 5})
+	require.NoError(t, err)
+	assert.NotNil(t, permit)
+}
+
+func TestResourceSlotSupplier_RampThrottleSpacesGrants(t *testing.T) {
+	options := tuning.DefaultResourceSlotSupplierOptions()
+	options.RampThrottle = time.Hour
+	options.Sampler = &fakeSampler{}
+	supplier, err := tuning.NewResourceSlotSupplier(options)
+	require.NoError(t, err)
+
+	assert.NotNil(t, supplier.TryReserveSlot(slotInfo{issued: 1}))
+	assert.Nil(t, supplier.TryReserveSlot(slotInfo{issued: 2}))
+}
+
+func TestUseActivitySlotSupplier_ReplacesFixedActivityLimit(t *testing.T) {
+	supplier := newTestSlotSupplier(t, &fakeSampler{})
+	options := worker.Options{
+		MaxConcurrentActivityExecutionSize:     50,
+		MaxConcurrentWorkflowTaskExecutionSize: 20,
+	}
+
+	require.NoError(t, tuning.UseActivitySlotSupplier(&options, supplier))
+
+	require.NotNil(t, options.Tuner)
+	assert.Same(t, supplier, options.Tuner.GetActivityTaskSlotSupplier())
+	assert.Equal(t, 20, options.Tuner.GetWorkflowTaskSlotSupplier().MaxSlots())
+	assert.Zero(t, options.MaxConcurrentActivityExecutionSize)
+	assert.Zero(t, options.MaxConcurrentWorkflowTaskExecutionSize)
+}
+
+func TestRuntimeSampler_MeasuresAgainstMemoryLimit(t *testing.T) {
+	sampler := tuning.NewRuntimeSampler(1 << 50)
+
+	usage := sampler.MemoryUsage()
+	assert.Greater(t, usage, 0.0)
+	assert.Less(t, usage, 0.01)
+	assert.GreaterOrEqual(t, sampler.CPUUsage(), 0.0)
+}
@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/intake"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/temporal"
+)
+
+func TestNewOrder_ReportsEveryInvalidField(t *testing.T) {
+	_, err := models.NewOrder("TEST-VALID-001", nil, 0, &models.Customer{Email: "ada"})
+
+	var invalid *models.OrderInvalidError
+	require.True(t, errors.As(err, &invalid))
+	assert.Equal(t, []models.FieldError{
+		{Field: "items", Message: "must not be empty"},
+		{Field: "amount", Message: "must be positive"},
+		{Field: "customer.id", Message: "is required"},
+		{Field: "customer.email", Message: `"ada" is not an email address`},
+	}, invalid.Fields)
+	assert.EqualError(t, err, `invalid order: items must not be empty; amount must be positive; customer.id is required; customer.email "ada" is not an email address`)
+
+	order, err := models.NewOrder("TEST-VALID-001", []string{"item1"}, 25, nil)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusPending, order.Status)
+	assert.ErrorContains(t, models.Order{ID: " ", Items: []string{"item1", ""}, Amount: 25}.Validate(), "id is required; items[1] must not be blank")
+}
+
+func TestOrderWorkflow_RejectsInvalidOrderBeforeValidation(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newFulfillmentTestEnv(orderActivities)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:     "TEST-VALID-002",
+		Amount: -5,
+		Status: models.StatusPending,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	var appErr *temporal.ApplicationError
+	require.True(t, errors.As(env.GetWorkflowError(), &appErr))
+	assert.Equal(t, models.ErrTypeOrderInvalid, appErr.Type())
+	assert.True(t, appErr.NonRetryable())
+	var details models.OrderInvalidError
+	require.NoError(t, appErr.Details(&details))
+	assert.Equal(t, "TEST-VALID-002", details.OrderID)
+	assert.Len(t, details.Fields, 2)
+	env.AssertNotCalled(t, "ValidateOrder")
+}
+
+func TestMessageHandler_RejectsInvalidOrder(t *testing.T) {
+	submitter := &recordingSubmitter{}
+	handler := intake.NewMessageHandler(submitter, nil)
+
+	err := handler.HandleMessage(context.Background(), []byte(`{"type": "order.create", "order": {"id": "TEST-VALID-003", "items": [], "amount": 0}}`))
+
+	assert.ErrorIs(t, err, intake.ErrInvalidMessage)
+	assert.ErrorContains(t, err, "items must not be empty; amount must be positive")
+	assert.Empty(t, submitter.orders)
+}
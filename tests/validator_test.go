@@ -0,0 +1,128 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/events"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/store"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+)
+
+func TestRulesValidator(t *testing.T) {
+	validator := activities.NewRulesValidator(activities.ValidationRules{
+		MaxAmount:          1000,
+		AllowedItems:       []string{"laptop", "mouse"},
+		MaxQuantityPerItem: 2,
+	})
+
+	tests := []struct {
+		name    string
+		order   models.Order
+		valid   bool
+		message string
+	}{
+		{"valid order", models.Order{Amount: 500, Items: []string{"laptop", "mouse"}}, true, "provisionally validated"},
+		{"amount too high", models.Order{Amount: 1000, Items: []string{"laptop"}}, false, "Amount exceeds"},
+		{"item not allowed", models.Order{Amount: 100, Items: []string{"keyboard"}}, false, "not allowed"},
+		{"quantity too high", models.Order{Amount: 100, Items: []string{"mouse", "mouse", "mouse"}}, false, "maximum quantity"},
+		{"customer without id", models.Order{Amount: 100, Items: []string{"mouse"}, Customer: &models.Customer{Email: "ada@example.com"}}, false, "customer.id is required"},
+		{"customer tier unknown", models.Order{Amount: 100, Items: []string{"mouse"}, Customer: &models.Customer{ID: "CUST-7", Tier: "diamond"}}, false, "customer.tier"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := validator.Validate(context.Background(), tt.order)
+			require.NoError(t, err)
+			assert.Equal(t, tt.valid, resp.Valid)
+			assert.True(t, resp.Provisional)
+			assert.Contains(t, resp.Message, tt.message)
+		})
+	}
+}
+
+func newLocalFallbackTestEnv(orderActivities *activities.OrderActivities) *testsuite.TestWorkflowEnvironment {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+
+	storeActivities := store.NewStoreActivities(nil)
+	env.RegisterActivity(orderActivities.ValidateOrder)
+	env.RegisterActivity(orderActivities.ValidateOrderLocally)
+	env.RegisterActivity(orderActivities.ProcessPayment)
+	env.RegisterActivity(orderActivities.FulfillItem)
+	env.RegisterActivity(orderActivities.NotifyOrderComplete)
+	env.RegisterActivity(events.NewEventActivities(nil).PublishOrderEvent)
+	env.RegisterActivity(storeActivities.PersistOrder)
+	env.RegisterActivity(storeActivities.UpdateOrderStatus)
+	env.RegisterWorkflow(workflows.OrderWorkflow)
+	env.RegisterWorkflow(workflows.PaymentWorkflow)
+
+	env.OnActivity(orderActivities.ProcessPayment, mock.Anything, mock.Anything).Return(&models.PaymentResponse{
+		Success:       true,
+		TransactionID: "TXN-LOCAL-123",
+	}, nil)
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	env.OnActivity(orderActivities.NotifyOrderComplete, mock.Anything, mock.Anything).Return(nil)
+	return env
+}
+
+func TestOrderWorkflow_FallsBackToLocalRules(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newLocalFallbackTestEnv(orderActivities)
+	down := temporal.NewNonRetryableApplicationError("validation service returned status 503", models.ErrTypeServiceUnreachable, errors.New("unavailable"))
+	env.OnActivity(orderActivities.ValidateOrder, mock.Anything, mock.Anything).Return(nil, down)
+
+	order := models.Order{
+		ID:     "TEST-LOCAL-001",
+		Items:  []string{"item1"},
+		Amount: 100.0,
+		Status: models.StatusPending,
+	}
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, order)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+
+	result, err := env.QueryWorkflow("getStatus")
+	require.NoError(t, err)
+	var status models.OrderStatus
+	require.NoError(t, result.Get(&status))
+	assert.True(t, status.ProvisionallyValidated)
+	assert.Equal(t, models.StatusCompleted, status.Status)
+}
+
+func TestOrderWorkflow_DoesNotFallBackOnRejectedRequest(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newLocalFallbackTestEnv(orderActivities)
+	unauthorized := temporal.NewNonRetryableApplicationError("validation service returned status 401: unauthorized", "", errors.New("unauthorized"))
+	env.OnActivity(orderActivities.ValidateOrder, mock.Anything, mock.Anything).Return(nil, unauthorized)
+	env.RegisterWorkflow(workflows.FailedOrderWorkflow)
+	env.OnWorkflow(workflows.FailedOrderWorkflow, mock.Anything, mock.Anything).Return(&models.DeadLetterStatus{}, nil)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:     "TEST-LOCAL-002",
+		Items:  []string{"item1"},
+		Amount: 100.0,
+		Status: models.StatusPending,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	assert.ErrorContains(t, env.GetWorkflowError(), "status 401")
+	env.AssertNotCalled(t, "ValidateOrderLocally", mock.Anything, mock.Anything)
+
+	result, err := env.QueryWorkflow("getStatus")
+	require.NoError(t, err)
+	var status models.OrderStatus
+	require.NoError(t, result.Get(&status))
+	assert.False(t, status.ProvisionallyValidated)
+	assert.Equal(t, models.StatusFailed, status.Status)
+}
@@ -0,0 +1,198 @@
+package tests
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/codec"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/vault"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeVault serves the AppRole login, token renewal, and KV v2 endpoints
+type fakeVault struct {
+	mu         sync.Mutex
+	key        []byte
+	leaseTTL   int
+	calls      map[string]int
+	rejectNext bool
+	down       bool
+}
+
+func newFakeVault(t *testing.T, key []byte) (*fakeVault, *httptest.Server) {
+	fake := &fakeVault{key: key, leaseTTL: 3600, calls: map[string]int{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return fake, server
+}
+
+func (f *fakeVault) count(path string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[path]
+}
+
+func (f *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls[r.URL.Path]++
+
+	if f.down {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	auth := map[string]interface{}{"client_token": "s.app-token", "lease_duration": f.leaseTTL, "renewable": true}
+	switch r.URL.Path {
+	case "/v1/auth/approle/login":
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["role_id"] != "orders-role" || body["secret_id"] != "orders-secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string][]string{"errors": {"invalid role or secret ID"}})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"auth": auth})
+	case "/v1/auth/token/renew-self":
+		json.NewEncoder(w).Encode(map[string]interface{}{"auth": auth})
+	case "/v1/secret/data/temporal/order-processing":
+		if f.rejectNext || r.Header.Get("X-Vault-Token") != "s.app-token" {
+			f.rejectNext = false
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(map[string][]string{"errors": {"permission denied"}})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"data": map[string]string{"key": base64.StdEncoding.EncodeToString(f.key)},
+			},
+		})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newVaultKeyProvider(t *testing.T, server *httptest.Server, cacheTTL time.Duration) *vault.KeyProvider {
+	client, err := vault.NewClient(vault.Config{Address: server.URL}, vault.AppRoleAuth{RoleID: "orders-role", SecretID: "orders-secret"})
+	require.NoError(t, err)
+	return vault.NewKeyProvider(client, vault.KeyConfig{Path: "temporal/order-processing", CacheTTL: cacheTTL})
+}
+
+func testKey() []byte {
+	key := make([]byte, codec.KeySize)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	return key
+}
+
+func TestVaultKeyProvider_AppRoleLoginAndCaching(t *testing.T) {
+	fake, server := newFakeVault(t, testKey())
+	keys := newVaultKeyProvider(t, server, time.Hour)
+
+	key, err := keys.Key(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, testKey(), key)
+
+	_, err = keys.Key(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, fake.count("/v1/auth/approle/login"))
+	assert.Equal(t, 1, fake.count("/v1/secret/data/temporal/order-processing"), "the key is cached")
+}
+
+func TestVaultKeyProvider_LogsInAgainWhenTokenRejected(t *testing.T) {
+	fake, server := newFakeVault(t, testKey())
+	fake.rejectNext = true
+	keys := newVaultKeyProvider(t, server, time.Hour)
+
+	key, err := keys.Key(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, testKey(), key)
+	assert.Equal(t, 2, fake.count("/v1/auth/approle/login"))
+}
+
+func TestVaultKeyProvider_RenewsAgingToken(t *testing.T) {
+	fake, server := newFakeVault(t, testKey())
+	fake.leaseTTL = 2
+	keys := newVaultKeyProvider(t, server, time.Millisecond)
+
+	_, err := keys.Key(context.Background())
+	require.NoError(t, err)
+	time.Sleep(1100 * time.Millisecond)
+	_, err = keys.Key(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, fake.count("/v1/auth/token/renew-self"))
+	assert.Equal(t, 1, fake.count("/v1/auth/approle/login"), "a renewable token is renewed rather than replaced")
+}
+
+func TestVaultKeyProvider_ServesCachedKeyWhileVaultIsDown(t *testing.T) {
+	fake, server := newFakeVault(t, testKey())
+	keys := newVaultKeyProvider(t, server, time.Millisecond)
+	var refreshErr error
+	keys.OnRefreshError = func(err error) { refreshErr = err }
+
+	_, err := keys.Key(context.Background())
+	require.NoError(t, err)
+
+	fake.mu.Lock()
+	fake.down = true
+	fake.mu.Unlock()
+	time.Sleep(5 * time.Millisecond)
+
+	key, err := keys.Key(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, testKey(), key)
+	assert.ErrorContains(t, refreshErr, "503")
+}
+
+func TestVaultKeyProvider_RejectsWrongKeyLength(t *testing.T) {
+	_, server := newFakeVault(t, []byte("too-short"))
+	keys := newVaultKeyProvider(t, server, time.Hour)
+
+	_, err := keys.Key(context.Background())
+	assert.ErrorContains(t, err, "32 byte")
+}
+
+func TestEncryptionDataConverter_WithVaultKeys(t *testing.T) {
+	_, server := newFakeVault(t, testKey())
+	dataConverter, err := codec.NewEncryptionDataConverterWithProvider(newVaultKeyProvider(t, server, time.Hour))
+	require.NoError(t, err)
+
+	order := models.Order{ID: "TEST-VAULT-001", Items: []string{"item1"}, Amount: 42}
+	payloads, err := dataConverter.ToPayloads(order)
+	require.NoError(t, err)
+	assert.Equal(t, codec.MetadataEncodingEncrypted, string(payloads.Payloads[0].Metadata["encoding"]))
+
+	// Payloads encrypted with the Vault key decrypt with the same raw key
+	staticConverter, err := codec.NewEncryptionDataConverter(testKey())
+	require.NoError(t, err)
+	var decoded models.Order
+	require.NoError(t, staticConverter.FromPayloads(payloads, &decoded))
+	assert.Equal(t, order.ID, decoded.ID)
+}
+
+func TestFileKeyProvider_GeneratesThenReusesKey(t *testing.T) {
+	keys := codec.FileKeyProvider{Path: filepath.Join(t.TempDir(), ".encryption.key")}
+
+	first, err := keys.Key(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, first, codec.KeySize)
+
+	second, err := keys.Key(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	require.NoError(t, os.WriteFile(keys.Path, []byte("corrupt"), 0600))
+	_, err = keys.Key(context.Background())
+	assert.ErrorContains(t, err, "32 bytes")
+}
@@ -0,0 +1,200 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/authz"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+func newVendorTestEnv(orderActivities *activities.OrderActivities) *testsuite.TestWorkflowEnvironment {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	env.RegisterActivity(orderActivities.SendPurchaseOrder)
+	env.RegisterActivity(orderActivities.NotifyOpsOfUnresponsiveVendor)
+	return env
+}
+
+var dropshipPurchaseOrder = models.PurchaseOrder{
+	Number:   models.PurchaseOrderNumber("TEST-VENDOR-001", "acme"),
+	OrderID:  "TEST-VENDOR-001",
+	VendorID: "acme",
+	Items:    []string{"kayak"},
+}
+
+func TestVendorFulfillmentWorkflow_WaitsForVendorCallback(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newVendorTestEnv(orderActivities)
+	env.OnActivity(orderActivities.SendPurchaseOrder, mock.Anything, mock.Anything).Return(nil, nil)
+	escalated := false
+	env.OnActivity(orderActivities.NotifyOpsOfUnresponsiveVendor, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, fulfillment models.VendorFulfillment) error {
+			escalated = true
+			return nil
+		})
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(models.SignalVendorResponse, models.VendorResponse{Accepted: true})
+	}, time.Hour)
+
+	env.ExecuteWorkflow(workflows.VendorFulfillmentWorkflow, dropshipPurchaseOrder)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var result models.VendorFulfillment
+	require.NoError(t, env.GetWorkflowResult(&result))
+	assert.Equal(t, models.VendorAccepted, result.Status)
+	assert.Equal(t, dropshipPurchaseOrder.Number, result.PurchaseOrder)
+	assert.False(t, escalated, "a vendor answering in time is not escalated")
+}
+
+func TestVendorFulfillmentWorkflow_EscalatesUnresponsiveVendor(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newVendorTestEnv(orderActivities)
+	env.OnActivity(orderActivities.SendPurchaseOrder, mock.Anything, mock.Anything).Return(nil, nil)
+	var alerted models.VendorFulfillment
+	env.OnActivity(orderActivities.NotifyOpsOfUnresponsiveVendor, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, fulfillment models.VendorFulfillment) error {
+			alerted = fulfillment
+			return nil
+		})
+
+	env.ExecuteWorkflow(workflows.VendorFulfillmentWorkflow, dropshipPurchaseOrder)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, dropshipPurchaseOrder.Number, alerted.PurchaseOrder)
+	var result models.VendorFulfillment
+	require.NoError(t, env.GetWorkflowResult(&result))
+	assert.Equal(t, models.VendorRejected, result.Status)
+	assert.Contains(t, result.Reason, "did not answer")
+}
+
+func TestOrderWorkflow_DropshipsMarketplaceItems(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newFulfillmentTestEnv(orderActivities)
+	env.RegisterWorkflow(workflows.VendorFulfillmentWorkflow)
+	env.RegisterActivity(orderActivities.SendPurchaseOrder)
+	var fulfilled []string
+	env.OnActivity(orderActivities.FulfillItem, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, order models.Order, item string, expedited bool) error {
+			fulfilled = append(fulfilled, item)
+			return nil
+		})
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:      "TEST-VENDOR-002",
+		Items:   []string{"paddle", "kayak"},
+		Amount:  650.0,
+		Status:  models.StatusPending,
+		Vendors: map[string]string{"kayak": "acme"},
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, []string{"paddle"}, fulfilled, "the vendor ships its own items")
+	status := queryStatus(t, env)
+	assert.Equal(t, models.StatusCompleted, status.Status)
+	require.Len(t, status.Vendors, 1)
+	assert.Equal(t, models.PurchaseOrderNumber("TEST-VENDOR-002", "acme"), status.Vendors[0].PurchaseOrder)
+	assert.Equal(t, models.VendorAccepted, status.Vendors[0].Status)
+}
+
+func TestOrderWorkflow_FailsWhenVendorRejects(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newFulfillmentTestEnv(orderActivities)
+	env.RegisterWorkflow(workflows.VendorFulfillmentWorkflow)
+	env.RegisterActivity(orderActivities.SendPurchaseOrder)
+	env.OnActivity(orderActivities.SendPurchaseOrder, mock.Anything, mock.Anything).Return(
+		&models.VendorResponse{Reason: "discontinued"}, nil)
+	env.RegisterWorkflow(workflows.FailedOrderWorkflow)
+	deadLettered := false
+	env.OnWorkflow(workflows.FailedOrderWorkflow, mock.Anything, mock.Anything).Return(
+		func(ctx workflow.Context, failure models.OrderFailure) (*models.DeadLetterStatus, error) {
+			deadLettered = true
+			return &models.DeadLetterStatus{}, nil
+		})
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:      "TEST-VENDOR-003",
+		Items:   []string{"kayak"},
+		Amount:  600.0,
+		Status:  models.StatusPending,
+		Vendors: map[string]string{"kayak": "acme"},
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.Error(t, env.GetWorkflowError())
+	assert.Contains(t, env.GetWorkflowError().Error(), "discontinued")
+	status := queryStatus(t, env)
+	assert.Equal(t, models.StatusFailed, status.Status)
+	require.Len(t, status.Vendors, 1)
+	assert.Equal(t, models.VendorRejected, status.Vendors[0].Status)
+	assert.True(t, deadLettered, "a rejected purchase order needs ops to find another vendor")
+}
+
+func TestOrderWorkflow_AcceptsVendorUpdatesOnlyFromVendorFulfillment(t *testing.T) {
+	signer, err := authz.NewSigner("secret")
+	require.NoError(t, err)
+	opsToken, err := signer.Sign(authz.Claims{Subject: "ops@example.com"})
+	require.NoError(t, err)
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	env := newFulfillmentTestEnv(orderActivities)
+	env.SetWorkerOptions(worker.Options{Interceptors: []interceptor.WorkerInterceptor{
+		&signalHeaderInjector{token: opsToken},
+		authz.NewInterceptor(authz.Config{
+			Signer:           signer,
+			ProtectedSignals: models.ProtectedSignals,
+			SignalSenders:    workflows.SignalSenders,
+		}),
+	}})
+	env.RegisterWorkflow(workflows.VendorFulfillmentWorkflow)
+	env.RegisterActivity(orderActivities.SendPurchaseOrder)
+	env.OnActivity(orderActivities.SendPurchaseOrder, mock.Anything, mock.Anything).Return(nil, nil)
+	purchaseOrder := models.PurchaseOrderNumber("TEST-VENDOR-004", "acme")
+	// A signed caller is still not the vendor fulfillment child, so its
+	// update is dropped
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(models.SignalVendorUpdate, models.VendorFulfillment{
+			PurchaseOrder: purchaseOrder,
+			VendorID:      "acme",
+			Status:        models.VendorRejected,
+			Reason:        "forged",
+		})
+	}, time.Hour)
+	var waiting models.OrderStatus
+	env.RegisterDelayedCallback(func() {
+		waiting = queryStatus(t, env)
+	}, 2*time.Hour)
+	env.RegisterDelayedCallback(func() {
+		require.NoError(t, env.SignalWorkflowByID(models.VendorWorkflowID("TEST-VENDOR-004", "acme"),
+			models.SignalVendorResponse, models.VendorResponse{Accepted: true}))
+	}, 3*time.Hour)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:      "TEST-VENDOR-004",
+		Items:   []string{"kayak"},
+		Amount:  600.0,
+		Status:  models.StatusPending,
+		Vendors: map[string]string{"kayak": "acme"},
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.Len(t, waiting.Vendors, 1)
+	assert.Equal(t, models.VendorPending, waiting.Vendors[0].Status)
+	status := queryStatus(t, env)
+	assert.Equal(t, models.StatusCompleted, status.Status)
+	require.Len(t, status.Vendors, 1)
+	assert.Equal(t, models.VendorAccepted, status.Vendors[0].Status)
+}
@@ -0,0 +1,111 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectWarehouse_SplitsItemsByStock(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	orderActivities.Warehouses = []models.Warehouse{
+		{ID: "east", Stock: []string{"laptop", "mouse"}},
+		{ID: "west", Stock: []string{"monitor", "mouse"}},
+	}
+
+	routing, err := orderActivities.SelectWarehouse(context.Background(), models.WarehouseRequest{
+		OrderID: "TEST-WAREHOUSE-001",
+		Items:   []string{"laptop", "mouse", "monitor", "gpu"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []models.WarehouseAssignment{
+		{WarehouseID: "east", Items: []string{"laptop", "mouse"}},
+		{WarehouseID: "west", Items: []string{"monitor"}},
+	}, routing.Assignments)
+	assert.Equal(t, []string{"gpu"}, routing.Unavailable)
+}
+
+func TestSelectWarehouse_PrefersNearestWarehouse(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	orderActivities.Warehouses = []models.Warehouse{
+		{ID: "east", Distances: map[string]float64{"us-east": 50, "us-west": 4000}},
+		{ID: "west", Distances: map[string]float64{"us-east": 4000, "us-west": 80}},
+	}
+
+	routing, err := orderActivities.SelectWarehouse(context.Background(), models.WarehouseRequest{
+		OrderID: "TEST-WAREHOUSE-002",
+		Items:   []string{"laptop", "mouse"},
+		Region:  "us-west",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []models.WarehouseAssignment{{WarehouseID: "west", Items: []string{"laptop", "mouse"}}}, routing.Assignments)
+	assert.Empty(t, routing.Unavailable)
+}
+
+func TestOrderWorkflow_TracksEachWarehouseShipment(t *testing.T) {
+	orderActivities := activities.NewOrderActivities("http://mock-url")
+	orderActivities.ItemFulfillmentTime = time.Millisecond
+	orderActivities.Warehouses = []models.Warehouse{
+		{ID: "east", Stock: []string{"laptop"}},
+		{ID: "west", Stock: []string{"monitor"}},
+	}
+	env := newDynamicConfigTestEnv(orderActivities, models.DynamicConfig{
+		WarehouseRouting:     true,
+		TrackingPollInterval: 6 * time.Hour,
+	})
+	env.RegisterWorkflow(workflows.WarehouseFulfillmentWorkflow)
+	env.RegisterWorkflow(workflows.TrackingWorkflow)
+	env.RegisterActivity(orderActivities.SelectWarehouse)
+	env.RegisterActivity(orderActivities.ReserveItem)
+	env.RegisterActivity(orderActivities.PickItem)
+	env.RegisterActivity(orderActivities.PackItem)
+	env.RegisterActivity(orderActivities.TrackShipment)
+	var inTransit models.OrderStatus
+	env.RegisterDelayedCallback(func() {
+		inTransit = queryStatus(t, env)
+	}, time.Hour)
+
+	env.ExecuteWorkflow(workflows.OrderWorkflow, models.Order{
+		ID:     "TEST-WAREHOUSE-003",
+		Items:  []string{"laptop", "monitor"},
+		Amount: 1300.0,
+		Status: models.StatusPending,
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.Len(t, inTransit.Shipments, 2)
+	assert.Equal(t, models.ShipmentInTransit, inTransit.ShipmentStatus)
+
+	status := queryStatus(t, env)
+	assert.Equal(t, models.StatusCompleted, status.Status)
+	assert.Equal(t, models.ShipmentDelivered, status.ShipmentStatus)
+	assert.Equal(t, []models.OrderShipment{
+		{
+			ShipmentID:     models.WarehouseShipmentID("TEST-WAREHOUSE-003", 1),
+			WarehouseID:    "east",
+			Items:          []string{"laptop"},
+			Status:         models.ItemFulfilled,
+			TrackingStatus: models.ShipmentDelivered,
+		},
+		{
+			ShipmentID:     models.WarehouseShipmentID("TEST-WAREHOUSE-003", 2),
+			WarehouseID:    "west",
+			Items:          []string{"monitor"},
+			Status:         models.ItemFulfilled,
+			TrackingStatus: models.ShipmentDelivered,
+		},
+	}, status.Shipments)
+
+	var result models.OrderResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	assert.Equal(t, "SHIP-TEST-WAREHOUSE-003-1,SHIP-TEST-WAREHOUSE-003-2", result.ShipmentID)
+}
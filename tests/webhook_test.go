@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aswathylr-builds/temporal-order-processing/intake"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const storefrontOrderJSON = `{
+  "id": 1001,
+  "name": "#1001",
+  "created_at": "2026-10-01T09:30:00Z",
+  "currency": "USD",
+  "total_price": "219.00",
+  "source_name": "web",
+  "customer": {"id": 42, "first_name": "Ada", "last_name": "Lovelace", "email": "ada@example.com", "phone": "+15550100"},
+  "shipping_address": {"country_code": "DE"},
+  "line_items": [
+    {"sku": "mug", "title": "Mug", "quantity": 2, "price": "9.50"},
+    {"sku": "", "title": "Gift wrap", "quantity": 1, "price": "200.00"}
+  ]
+}`
+
+// recordingSubmitter records the orders it is asked to start
+type recordingSubmitter struct {
+	orders    []models.Order
+	memos     []models.OrderMemo
+	duplicate bool
+}
+
+func (s *recordingSubmitter) StartOrder(ctx context.Context, order models.Order, memo models.OrderMemo) (intake.Submission, error) {
+	s.orders = append(s.orders, order)
+	s.memos = append(s.memos, memo)
+	return intake.Submission{
+		OrderID:    order.ID,
+		WorkflowID: models.OrderWorkflowID(order.ID),
+		RunID:      "run-1",
+		Duplicate:  s.duplicate,
+	}, nil
+}
+
+func postWebhook(t *testing.T, handler http.Handler, body, secret, topic string) *httptest.ResponseRecorder {
+	t.Helper()
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/orders", strings.NewReader(body))
+	req.Header.Set(intake.SignatureHeader, base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	req.Header.Set(intake.TopicHeader, topic)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestWebhookHandler_StartsSignedOrder(t *testing.T) {
+	submitter := &recordingSubmitter{}
+	handler := intake.NewWebhookHandler(submitter, intake.WebhookHandlerOptions{
+		Secrets:       []string{"old-secret", "s3cret"},
+		OrderIDPrefix: "SHOP-",
+	})
+
+	rec := postWebhook(t, handler, storefrontOrderJSON, "s3cret", intake.TopicOrderCreated)
+
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	require.Len(t, submitter.orders, 1)
+	order := submitter.orders[0]
+	assert.Equal(t, "SHOP-1001", order.ID)
+	assert.Equal(t, []string{"mug", "mug", "Gift wrap"}, order.Items)
+	assert.Equal(t, 219.0, order.Amount)
+	assert.Equal(t, []models.OrderItem{{SKU: "mug", Quantity: 2, Price: 9.5}, {SKU: "Gift wrap", Quantity: 1, Price: 200}}, order.Lines)
+	assert.Equal(t, models.OrderSchemaVersion, order.SchemaVersion)
+	assert.Equal(t, models.StatusPending, order.Status)
+	assert.Equal(t, "USD", order.Currency)
+	assert.Equal(t, &models.Customer{ID: "42", Name: "Ada Lovelace", Email: "ada@example.com", Phone: "+15550100"}, order.Customer)
+	assert.Equal(t, models.OrderMemo{CustomerID: "42", Channel: "web", Region: "DE"}, submitter.memos[0])
+
+	var submission intake.Submission
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &submission))
+	assert.Equal(t, "order-workflow-SHOP-1001", submission.WorkflowID)
+}
+
+func TestWebhookHandler_KeepsStorefrontCurrency(t *testing.T) {
+	submitter := &recordingSubmitter{}
+	handler := intake.NewWebhookHandler(submitter, intake.WebhookHandlerOptions{Secrets: []string{"s3cret"}})
+	body := strings.Replace(storefrontOrderJSON, `"currency": "USD"`, `"currency": "EUR"`, 1)
+
+	rec := postWebhook(t, handler, body, "s3cret", intake.TopicOrderCreated)
+
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	require.Len(t, submitter.orders, 1)
+	assert.Equal(t, "EUR", submitter.orders[0].Currency)
+	assert.Equal(t, 219.0, submitter.orders[0].Amount)
+}
+
+func TestWebhookHandler_RejectsBadSignature(t *testing.T) {
+	submitter := &recordingSubmitter{}
+	handler := intake.NewWebhookHandler(submitter, intake.WebhookHandlerOptions{Secrets: []string{"s3cret"}})
+
+	rec := postWebhook(t, handler, storefrontOrderJSON, "wrong", intake.TopicOrderCreated)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Empty(t, submitter.orders)
+}
+
+func TestWebhookHandler_RedeliveryIsAcknowledged(t *testing.T) {
+	submitter := &recordingSubmitter{duplicate: true}
+	handler := intake.NewWebhookHandler(submitter, intake.WebhookHandlerOptions{Secrets: []string{"s3cret"}})
+
+	assert.Equal(t, http.StatusOK, postWebhook(t, handler, storefrontOrderJSON, "s3cret", intake.TopicOrderCreated).Code)
+	assert.Equal(t, http.StatusNoContent, postWebhook(t, handler, storefrontOrderJSON, "s3cret", "orders/updated").Code)
+	assert.Equal(t, http.StatusBadRequest, postWebhook(t, handler, `{"id": 1002, "total_price": "5.00"}`, "s3cret", intake.TopicOrderCreated).Code)
+	assert.Len(t, submitter.orders, 1)
+}
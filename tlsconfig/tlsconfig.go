@@ -0,0 +1,102 @@
+// Package tlsconfig builds the TLS settings for the Temporal connection,
+// with a client certificate that can be reloaded without a restart.
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Config locates the certificates used to connect to Temporal.
+// CAFile is a PEM bundle used to verify the server instead of the system
+// roots; CertFile and KeyFile enable mTLS with a client certificate.
+// ServerName overrides the name checked against the server certificate.
+type Config struct {
+	CAFile     string
+	CertFile   string
+	KeyFile    string
+	ServerName string
+}
+
+// Enabled reports whether any TLS setting is configured
+func (c Config) Enabled() bool {
+	return c.CAFile != "" || c.CertFile != "" || c.KeyFile != "" || c.ServerName != ""
+}
+
+// Reloader holds the client certificate for a TLS connection and swaps it
+// in place when Reload is called. New handshakes pick up the new certificate;
+// established connections keep the old one until they reconnect.
+type Reloader struct {
+	config Config
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+}
+
+// NewReloader loads the configured client certificate
+func NewReloader(config Config) (*Reloader, error) {
+	if (config.CertFile == "") != (config.KeyFile == "") {
+		return nil, fmt.Errorf("both client certificate and key are required for mTLS")
+	}
+	r := &Reloader{config: config}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload re-reads the client certificate and key. On error the previous
+// certificate stays in use.
+func (r *Reloader) Reload() error {
+	if r.config.CertFile == "" {
+		return nil
+	}
+	cert, err := tls.LoadX509KeyPair(r.config.CertFile, r.config.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load client certificate: %w", err)
+	}
+	r.mu.Lock()
+	r.cert = &cert
+	r.mu.Unlock()
+	return nil
+}
+
+// Certificate returns the client certificate currently in use, or nil when
+// mTLS is not configured
+func (r *Reloader) Certificate() *tls.Certificate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert
+}
+
+// TLSConfig returns the settings for client.ConnectionOptions.TLS. The CA
+// bundle is read once; only the client certificate is reloadable.
+func (r *Reloader) TLSConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: r.config.ServerName,
+	}
+
+	if r.config.CAFile != "" {
+		caPEM, err := os.ReadFile(r.config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", r.config.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if r.config.CertFile != "" {
+		tlsConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return r.Certificate(), nil
+		}
+	}
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package tuning
+
+import "time"
+
+// processCPUTime is not measured on this platform, so CPU usage reads as zero
+// and only memory limits slot grants
+func processCPUTime() time.Duration {
+	return 0
+}
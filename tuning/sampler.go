@@ -0,0 +1,106 @@
+package tuning
+
+import (
+	"math"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"runtime/metrics"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cpuSampleInterval is the shortest window CPU usage is measured over
+const cpuSampleInterval = 500 * time.Millisecond
+
+// ResourceSampler reports resource usage as a fraction of what is available
+type ResourceSampler interface {
+	// MemoryUsage returns used memory as a fraction of the memory limit
+	MemoryUsage() float64
+	// CPUUsage returns recent CPU use as a fraction of GOMAXPROCS cores
+	CPUUsage() float64
+}
+
+// RuntimeSampler samples this process using the Go runtime and OS counters
+type RuntimeSampler struct {
+	memoryLimit uint64
+
+	mu       sync.Mutex
+	lastCPU  time.Duration
+	lastWall time.Time
+	cpuUsage float64
+}
+
+// NewRuntimeSampler creates a sampler measuring memory against memoryLimit
+// bytes. Zero detects the limit from GOMEMLIMIT or the container's cgroup;
+// without either, memory usage is reported as zero.
+func NewRuntimeSampler(memoryLimit uint64) *RuntimeSampler {
+	if memoryLimit == 0 {
+		memoryLimit = detectMemoryLimit()
+	}
+	return &RuntimeSampler{memoryLimit: memoryLimit, lastCPU: processCPUTime(), lastWall: time.Now()}
+}
+
+// MemoryLimit returns the limit memory usage is measured against
+func (s *RuntimeSampler) MemoryLimit() uint64 {
+	return s.memoryLimit
+}
+
+// MemoryUsage returns memory mapped by the Go runtime and not yet released to
+// the OS, as a fraction of the memory limit
+func (s *RuntimeSampler) MemoryUsage() float64 {
+	if s.memoryLimit == 0 {
+		return 0
+	}
+	samples := []metrics.Sample{
+		{Name: "/memory/classes/total:bytes"},
+		{Name: "/memory/classes/heap/released:bytes"},
+	}
+	metrics.Read(samples)
+	used := samples[0].Value.Uint64() - samples[1].Value.Uint64()
+	return float64(used) / float64(s.memoryLimit)
+}
+
+// CPUUsage returns the process CPU use over the last sampling window.
+// Samples are taken at most every cpuSampleInterval; calls in between return
+// the previous value.
+func (s *RuntimeSampler) CPUUsage() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	wall := now.Sub(s.lastWall)
+	if wall < cpuSampleInterval {
+		return s.cpuUsage
+	}
+	cpu := processCPUTime()
+	s.cpuUsage = float64(cpu-s.lastCPU) / (float64(wall) * float64(runtime.GOMAXPROCS(0)))
+	s.lastCPU, s.lastWall = cpu, now
+	return s.cpuUsage
+}
+
+// detectMemoryLimit returns GOMEMLIMIT if set, otherwise the cgroup v2 or v1
+// memory limit, otherwise zero
+func detectMemoryLimit() uint64 {
+	if limit := debug.SetMemoryLimit(-1); limit > 0 && limit < math.MaxInt64 {
+		return uint64(limit)
+	}
+	for _, path := range []string{
+		"/sys/fs/cgroup/memory.max",
+		"/sys/fs/cgroup/memory/memory.limit_in_bytes",
+	} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		limit, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+		// "max", or cgroup v1's near-MaxInt64 value, means unlimited
+		if err != nil || limit >= 1<<60 {
+			continue
+		}
+		return limit
+	}
+	return 0
+}
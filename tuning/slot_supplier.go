@@ -0,0 +1,124 @@
+// Package tuning sizes worker concurrency from resource usage instead of
+// fixed slot counts.
+package tuning
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.temporal.io/sdk/worker"
+)
+
+// pollInterval is how often a blocked ReserveSlot rechecks resource usage
+const pollInterval = 10 * time.Millisecond
+
+// ResourceSlotSupplierOptions configures a ResourceSlotSupplier.
+// MinSlots are always granted, so a worker under pressure still makes
+// progress; MaxSlots caps concurrency however idle the host is. Slots above
+// the minimum are granted only while memory and CPU usage, as fractions of
+// the available memory and GOMAXPROCS, are below their targets. RampThrottle
+// spaces those grants out so a burst of tasks cannot take many slots before
+// the memory they use shows up in the samples.
+type ResourceSlotSupplierOptions struct {
+	MinSlots          int
+	MaxSlots          int
+	TargetMemoryUsage float64
+	TargetCPUUsage    float64
+	RampThrottle      time.Duration
+	Sampler           ResourceSampler
+}
+
+// DefaultResourceSlotSupplierOptions returns options suited to activity slots
+func DefaultResourceSlotSupplierOptions() ResourceSlotSupplierOptions {
+	return ResourceSlotSupplierOptions{
+		MinSlots:          1,
+		MaxSlots:          500,
+		TargetMemoryUsage: 0.8,
+		TargetCPUUsage:    0.9,
+		RampThrottle:      50 * time.Millisecond,
+	}
+}
+
+// ResourceSlotSupplier is a worker.SlotSupplier that hands out slots while
+// the process has memory and CPU headroom
+type ResourceSlotSupplier struct {
+	options ResourceSlotSupplierOptions
+
+	mu        sync.Mutex
+	lastGrant time.Time
+}
+
+var _ worker.SlotSupplier = (*ResourceSlotSupplier)(nil)
+
+// NewResourceSlotSupplier creates a ResourceSlotSupplier. A nil Sampler
+// samples this process with NewRuntimeSampler.
+func NewResourceSlotSupplier(options ResourceSlotSupplierOptions) (*ResourceSlotSupplier, error) {
+	if options.MinSlots < 0 || options.MaxSlots < 1 || options.MinSlots > options.MaxSlots {
+		return nil, fmt.Errorf("invalid slot bounds: min %d, max %d", options.MinSlots, options.MaxSlots)
+	}
+	if options.TargetMemoryUsage <= 0 || options.TargetMemoryUsage > 1 {
+		return nil, fmt.Errorf("target memory usage must be in (0, 1], got %v", options.TargetMemoryUsage)
+	}
+	if options.TargetCPUUsage <= 0 || options.TargetCPUUsage > 1 {
+		return nil, fmt.Errorf("target CPU usage must be in (0, 1], got %v", options.TargetCPUUsage)
+	}
+	if options.Sampler == nil {
+		options.Sampler = NewRuntimeSampler(0)
+	}
+	return &ResourceSlotSupplier{options: options}, nil
+}
+
+// ReserveSlot blocks until a slot can be granted or ctx is done
+func (s *ResourceSlotSupplier) ReserveSlot(ctx context.Context, info worker.SlotReservationInfo) (*worker.SlotPermit, error) {
+	for {
+		if permit := s.TryReserveSlot(info); permit != nil {
+			return permit, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// TryReserveSlot grants a slot if one is available now, and returns nil otherwise
+func (s *ResourceSlotSupplier) TryReserveSlot(info worker.SlotReservationInfo) *worker.SlotPermit {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	issued := info.NumIssuedSlots()
+	if issued < s.options.MinSlots {
+		return s.grant()
+	}
+	if issued >= s.options.MaxSlots {
+		return nil
+	}
+	if time.Since(s.lastGrant) < s.options.RampThrottle {
+		return nil
+	}
+	if s.options.Sampler.MemoryUsage() >= s.options.TargetMemoryUsage ||
+		s.options.Sampler.CPUUsage() >= s.options.TargetCPUUsage {
+		return nil
+	}
+	return s.grant()
+}
+
+// grant records the grant time and returns a new permit; s.mu must be held
+func (s *ResourceSlotSupplier) grant() *worker.SlotPermit {
+	s.lastGrant = time.Now()
+	return &worker.SlotPermit{}
+}
+
+// MarkSlotUsed is a no-op; usage is observed through the sampler
+func (s *ResourceSlotSupplier) MarkSlotUsed(worker.SlotMarkUsedInfo) {}
+
+// ReleaseSlot is a no-op; the SDK tracks the number of issued slots
+func (s *ResourceSlotSupplier) ReleaseSlot(worker.SlotReleaseInfo) {}
+
+// MaxSlots returns the most slots this supplier will issue
+func (s *ResourceSlotSupplier) MaxSlots() int {
+	return s.options.MaxSlots
+}
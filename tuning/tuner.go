@@ -0,0 +1,37 @@
+package tuning
+
+import (
+	"go.temporal.io/sdk/worker"
+)
+
+// UseActivitySlotSupplier sets options.Tuner so activities, including session
+// activities, take their slots from activitySlots. The SDK rejects a Tuner
+// alongside the MaxConcurrent*ExecutionSize options, so those are moved into
+// fixed-size suppliers for the other task types and cleared.
+func UseActivitySlotSupplier(options *worker.Options, activitySlots worker.SlotSupplier) error {
+	fixed, err := worker.NewFixedSizeTuner(worker.FixedSizeTunerOptions{
+		NumWorkflowSlots:      options.MaxConcurrentWorkflowTaskExecutionSize,
+		NumLocalActivitySlots: options.MaxConcurrentLocalActivityExecutionSize,
+		NumNexusSlots:         options.MaxConcurrentNexusTaskExecutionSize,
+	})
+	if err != nil {
+		return err
+	}
+	tuner, err := worker.NewCompositeTuner(worker.CompositeTunerOptions{
+		WorkflowSlotSupplier:        fixed.GetWorkflowTaskSlotSupplier(),
+		ActivitySlotSupplier:        activitySlots,
+		LocalActivitySlotSupplier:   fixed.GetLocalActivitySlotSupplier(),
+		NexusSlotSupplier:           fixed.GetNexusSlotSupplier(),
+		SessionActivitySlotSupplier: activitySlots,
+	})
+	if err != nil {
+		return err
+	}
+
+	options.Tuner = tuner
+	options.MaxConcurrentWorkflowTaskExecutionSize = 0
+	options.MaxConcurrentActivityExecutionSize = 0
+	options.MaxConcurrentLocalActivityExecutionSize = 0
+	options.MaxConcurrentNexusTaskExecutionSize = 0
+	return nil
+}
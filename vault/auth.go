@@ -0,0 +1,83 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// authResponse is the auth block Vault returns from logins and renewals
+type authResponse struct {
+	Auth struct {
+		ClientToken   string `json:"client_token"`
+		LeaseDuration int    `json:"lease_duration"`
+		Renewable     bool   `json:"renewable"`
+	} `json:"auth"`
+}
+
+func (r authResponse) token() *Token {
+	return &Token{
+		Value:     r.Auth.ClientToken,
+		TTL:       time.Duration(r.Auth.LeaseDuration) * time.Second,
+		Renewable: r.Auth.Renewable,
+	}
+}
+
+// TokenAuth uses a token issued outside the process, such as VAULT_TOKEN.
+// Its TTL is looked up so it can be renewed before it expires; once expired
+// it cannot be replaced.
+type TokenAuth struct {
+	Token string
+}
+
+// Login implements Auth
+func (a TokenAuth) Login(ctx context.Context, c *Client) (*Token, error) {
+	if a.Token == "" {
+		return nil, errors.New("vault token is empty")
+	}
+	var resp struct {
+		Data struct {
+			TTL       int  `json:"ttl"`
+			Renewable bool `json:"renewable"`
+		} `json:"data"`
+	}
+	if err := c.do(ctx, http.MethodGet, "auth/token/lookup-self", a.Token, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &Token{
+		Value:     a.Token,
+		TTL:       time.Duration(resp.Data.TTL) * time.Second,
+		Renewable: resp.Data.Renewable,
+	}, nil
+}
+
+// AppRoleAuth logs in with an AppRole role ID and secret ID. Mount defaults
+// to "approle".
+type AppRoleAuth struct {
+	Mount    string
+	RoleID   string
+	SecretID string
+}
+
+// Login implements Auth
+func (a AppRoleAuth) Login(ctx context.Context, c *Client) (*Token, error) {
+	if a.RoleID == "" || a.SecretID == "" {
+		return nil, errors.New("AppRole role ID and secret ID are required")
+	}
+	mount := a.Mount
+	if mount == "" {
+		mount = "approle"
+	}
+
+	body := map[string]string{"role_id": a.RoleID, "secret_id": a.SecretID}
+	var resp authResponse
+	if err := c.do(ctx, http.MethodPost, "auth/"+mount+"/login", "", body, &resp); err != nil {
+		return nil, err
+	}
+	token := resp.token()
+	if token.Value == "" {
+		return nil, errors.New("AppRole login returned no token")
+	}
+	return token, nil
+}
@@ -0,0 +1,251 @@
+// Package vault reads secrets from HashiCorp Vault over its HTTP API,
+// authenticating with a token or AppRole and renewing the token as it ages.
+package vault
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Config locates Vault. Namespace is for Vault Enterprise and HCP Vault;
+// CAFile is a PEM bundle used to verify Vault instead of the system roots.
+type Config struct {
+	Address   string
+	Namespace string
+	CAFile    string
+	Timeout   time.Duration
+}
+
+// Auth obtains a Vault token
+type Auth interface {
+	Login(ctx context.Context, c *Client) (*Token, error)
+}
+
+// Token is a Vault token and its lease. A zero TTL never expires.
+type Token struct {
+	Value     string
+	TTL       time.Duration
+	Renewable bool
+}
+
+// Client makes authenticated requests to Vault. Tokens are renewed once half
+// their TTL has passed, and Auth logs in again when renewal is not possible.
+type Client struct {
+	config     Config
+	httpClient *http.Client
+	auth       Auth
+
+	mu       sync.Mutex
+	token    *Token
+	issuedAt time.Time
+	now      func() time.Time
+}
+
+// NewClient creates a client for the Vault at config.Address
+func NewClient(config Config, auth Auth) (*Client, error) {
+	if config.Address == "" {
+		return nil, errors.New("vault address is required")
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if config.CAFile != "" {
+		pem, err := os.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Vault CA file: %w", err)
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", config.CAFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}
+	}
+
+	return &Client{
+		config:     config,
+		httpClient: &http.Client{Timeout: config.Timeout, Transport: transport},
+		auth:       auth,
+		now:        time.Now,
+	}, nil
+}
+
+// Read returns the data of the secret at path, such as secret/data/app for a
+// KV v2 secret. A rejected token is replaced by logging in again once.
+func (c *Client) Read(ctx context.Context, path string) (map[string]interface{}, error) {
+	var secret struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	err := c.authenticated(ctx, func(token string) error {
+		return c.do(ctx, http.MethodGet, path, token, nil, &secret)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return secret.Data, nil
+}
+
+// authenticated runs request with a valid token, retrying once with a fresh
+// login if Vault rejects the token
+func (c *Client) authenticated(ctx context.Context, request func(token string) error) error {
+	token, err := c.currentToken(ctx)
+	if err != nil {
+		return err
+	}
+	err = request(token)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusForbidden {
+		return err
+	}
+
+	c.mu.Lock()
+	err = c.login(ctx)
+	token = c.tokenValue()
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return request(token)
+}
+
+// currentToken returns a token with time left, renewing or replacing it first
+// when half its TTL has passed
+func (c *Client) currentToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token == nil {
+		if err := c.login(ctx); err != nil {
+			return "", err
+		}
+		return c.tokenValue(), nil
+	}
+	if c.token.TTL == 0 {
+		return c.tokenValue(), nil
+	}
+
+	age := c.now().Sub(c.issuedAt)
+	if age < c.token.TTL/2 {
+		return c.tokenValue(), nil
+	}
+	if c.token.Renewable && age < c.token.TTL {
+		if err := c.renew(ctx); err == nil {
+			return c.tokenValue(), nil
+		}
+	}
+	if err := c.login(ctx); err != nil {
+		if age < c.token.TTL {
+			// The current token still works; try again on the next call
+			return c.tokenValue(), nil
+		}
+		return "", err
+	}
+	return c.tokenValue(), nil
+}
+
+// login replaces the token using Auth; c.mu must be held
+func (c *Client) login(ctx context.Context) error {
+	token, err := c.auth.Login(ctx, c)
+	if err != nil {
+		return fmt.Errorf("vault login failed: %w", err)
+	}
+	c.token, c.issuedAt = token, c.now()
+	return nil
+}
+
+// renew extends the current token's lease; c.mu must be held
+func (c *Client) renew(ctx context.Context) error {
+	var resp authResponse
+	if err := c.do(ctx, http.MethodPost, "auth/token/renew-self", c.token.Value, struct{}{}, &resp); err != nil {
+		return fmt.Errorf("vault token renewal failed: %w", err)
+	}
+	c.token = resp.token()
+	if c.token.Value == "" {
+		return errors.New("vault token renewal returned no token")
+	}
+	c.issuedAt = c.now()
+	return nil
+}
+
+func (c *Client) tokenValue() string {
+	if c.token == nil {
+		return ""
+	}
+	return c.token.Value
+}
+
+// StatusError is returned when Vault responds with an error status
+type StatusError struct {
+	StatusCode int
+	Errors     []string
+}
+
+func (e *StatusError) Error() string {
+	if len(e.Errors) == 0 {
+		return fmt.Sprintf("vault returned status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("vault returned status %d: %s", e.StatusCode, strings.Join(e.Errors, "; "))
+}
+
+// do sends a request to the Vault API path, decoding the JSON response into out
+func (c *Client) do(ctx context.Context, method, path, token string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode vault request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	url := strings.TrimRight(c.config.Address, "/") + "/v1/" + strings.TrimLeft(path, "/")
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create vault request: %w", err)
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if c.config.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", c.config.Namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("vault request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		statusErr := &StatusError{StatusCode: resp.StatusCode}
+		var errorBody struct {
+			Errors []string `json:"errors"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&errorBody) == nil {
+			statusErr.Errors = errorBody.Errors
+		}
+		return statusErr
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode vault response: %w", err)
+	}
+	return nil
+}
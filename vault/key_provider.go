@@ -0,0 +1,98 @@
+package vault
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultKeyCacheTTL is how long KeyProvider reuses a key before reading it again
+const DefaultKeyCacheTTL = 5 * time.Minute
+
+// KeyConfig locates the encryption key: Field of the KV v2 secret at Path in
+// the engine mounted at Mount, stored base64 encoded
+type KeyConfig struct {
+	Mount    string
+	Path     string
+	Field    string
+	CacheTTL time.Duration
+}
+
+// KeyProvider implements codec.KeyProvider with a key stored in Vault's KV v2
+// secrets engine. The key is cached for CacheTTL; if Vault cannot be reached
+// when the cache expires, the cached key keeps being served and OnRefreshError
+// is called.
+type KeyProvider struct {
+	client *Client
+	config KeyConfig
+	// OnRefreshError, if set, is called when a refresh fails but the cached key is still served
+	OnRefreshError func(err error)
+
+	mu        sync.Mutex
+	key       []byte
+	fetchedAt time.Time
+}
+
+// NewKeyProvider creates a key provider reading through client
+func NewKeyProvider(client *Client, config KeyConfig) *KeyProvider {
+	if config.Mount == "" {
+		config.Mount = "secret"
+	}
+	if config.Field == "" {
+		config.Field = "key"
+	}
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = DefaultKeyCacheTTL
+	}
+	return &KeyProvider{client: client, config: config}
+}
+
+// Key implements codec.KeyProvider
+func (p *KeyProvider) Key(ctx context.Context) ([]byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.key != nil && p.client.now().Sub(p.fetchedAt) < p.config.CacheTTL {
+		return p.key, nil
+	}
+
+	key, err := p.fetch(ctx)
+	if err != nil {
+		if p.key == nil {
+			return nil, err
+		}
+		if p.OnRefreshError != nil {
+			p.OnRefreshError(err)
+		}
+		// Serve the cached key, trying Vault again after another interval
+		p.fetchedAt = p.client.now()
+		return p.key, nil
+	}
+	p.key, p.fetchedAt = key, p.client.now()
+	return key, nil
+}
+
+func (p *KeyProvider) fetch(ctx context.Context) ([]byte, error) {
+	path := p.config.Mount + "/data/" + p.config.Path
+	data, err := p.client.Read(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read encryption key from %s: %w", path, err)
+	}
+
+	// KV v2 nests the secret's fields under data.data
+	fields, _ := data["data"].(map[string]interface{})
+	encoded, ok := fields[p.config.Field].(string)
+	if !ok {
+		return nil, fmt.Errorf("secret %s has no %q field", path, p.config.Field)
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("field %q of %s is not base64: %w", p.config.Field, path, err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("field %q of %s must be a 32 byte AES-256 key, got %d bytes", p.config.Field, path, len(key))
+	}
+	return key, nil
+}
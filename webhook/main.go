@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/config"
+	"github.com/aswathylr-builds/temporal-order-processing/intake"
+	"github.com/aswathylr-builds/temporal-order-processing/logging"
+	"github.com/aswathylr-builds/temporal-order-processing/temporalclient"
+)
+
+const (
+	taskQueue = "order-processing-queue"
+)
+
+// Receives order-created webhooks from the storefront and starts an order
+// workflow for each. Point the storefront's webhook at POST /webhooks/orders.
+func main() {
+	// Settings come from the CONFIG_FILE YAML file, overlaid with environment variables
+	cfg, err := config.LoadFromEnv()
+	if err != nil {
+		fatal("Invalid configuration", "error", err)
+	}
+
+	logger := logging.New(os.Stderr, cfg.LoggingConfig())
+	slog.SetDefault(logger)
+
+	// Unsigned webhooks could start orders for anyone who finds the endpoint
+	if len(cfg.Webhook.Secrets) == 0 {
+		fatal("webhook.secrets is required; set WEBHOOK_SECRETS to the storefront's signing secret")
+	}
+
+	c, err := temporalclient.Dial(cfg, logger)
+	if err != nil {
+		fatal("Unable to create Temporal client", "error", err)
+	}
+	defer c.Close()
+
+	mux := http.NewServeMux()
+	mux.Handle("/webhooks/orders", intake.NewWebhookHandler(intake.NewSubmitter(c, taskQueue), intake.WebhookHandlerOptions{
+		Secrets:       cfg.Webhook.Secrets,
+		OrderIDPrefix: cfg.Webhook.OrderIDPrefix,
+	}))
+	server := &http.Server{
+		Addr:         fmt.Sprintf(":%d", cfg.Webhook.Port),
+		Handler:      mux,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 30 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+	slog.Info("Webhook receiver started", "port", cfg.Webhook.Port, "task_queue", taskQueue)
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	select {
+	case <-sigCh:
+		slog.Info("Shutting down webhook receiver")
+	case err := <-errCh:
+		if !errors.Is(err, http.ErrServerClosed) {
+			fatal("Webhook receiver failed", "error", err)
+		}
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Error("Webhook receiver shutdown failed", "error", err)
+	}
+}
+
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
@@ -2,19 +2,37 @@ package main
 
 import (
 	"context"
-	"crypto/rand"
-	"log"
+	"database/sql"
+	"errors"
+	"log/slog"
 	"os"
 	"os/signal"
-	"strconv"
+	"path/filepath"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/aswathylr-builds/temporal-order-processing/activities"
+	"github.com/aswathylr-builds/temporal-order-processing/archive"
+	"github.com/aswathylr-builds/temporal-order-processing/authz"
+	"github.com/aswathylr-builds/temporal-order-processing/buildinfo"
 	"github.com/aswathylr-builds/temporal-order-processing/codec"
+	"github.com/aswathylr-builds/temporal-order-processing/config"
+	"github.com/aswathylr-builds/temporal-order-processing/events"
+	"github.com/aswathylr-builds/temporal-order-processing/featureflags"
 	"github.com/aswathylr-builds/temporal-order-processing/health"
+	"github.com/aswathylr-builds/temporal-order-processing/interceptors"
+	"github.com/aswathylr-builds/temporal-order-processing/logging"
+	"github.com/aswathylr-builds/temporal-order-processing/metrics"
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/aswathylr-builds/temporal-order-processing/store"
+	"github.com/aswathylr-builds/temporal-order-processing/temporalclient"
+	"github.com/aswathylr-builds/temporal-order-processing/tuning"
 	"github.com/aswathylr-builds/temporal-order-processing/workflows"
+	"go.temporal.io/api/enums/v1"
 	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/worker"
 )
 
@@ -23,66 +41,455 @@ const (
 )
 
 func main() {
-	// Get configuration from environment variables
-	temporalHost := getEnv("TEMPORAL_HOST", "localhost:7233")
-	validationURL := getEnv("VALIDATION_URL", "http://localhost:8081/validate")
-	encryptionEnabled := getEnv("ENCRYPTION_ENABLED", "false") == "true"
-	healthPort := getEnvAsInt("HEALTH_PORT", 8090)
-
-	// Create Temporal client options
-	clientOptions := client.Options{
-		HostPort: temporalHost,
+	// Settings come from the CONFIG_FILE YAML file, overlaid with environment variables
+	cfg, err := config.LoadFromEnv()
+	if err != nil {
+		fatal("Invalid configuration", "error", err)
 	}
 
-	// Enable encryption if configured
-	if encryptionEnabled {
-		encryptionKey := generateOrGetEncryptionKey()
-		dataConverter, err := codec.NewEncryptionDataConverter(encryptionKey)
-		if err != nil {
-			log.Fatalf("Failed to create encryption data converter: %v", err)
-		}
-		clientOptions.DataConverter = dataConverter
-		log.Println("Encryption enabled for worker")
+	// Structured logs for both this process and the Temporal SDK
+	logger := logging.New(os.Stderr, cfg.LoggingConfig())
+	slog.SetDefault(logger)
+
+	// Export SDK and custom order metrics to Prometheus, keeping the latest
+	// slot, poller, and sticky cache values for /health/worker
+	metricsServer := metrics.NewServer(cfg.Metrics.Port)
+	workerStats := health.NewWorkerStats(metricsServer.Handler())
+
+	// Create Temporal client options. Compress and encrypt payloads if
+	// configured; this fetches an encryption key now so a misconfigured key
+	// store fails at startup.
+	clientOptions, err := temporalclient.NewOptions(cfg, logger)
+	if err != nil {
+		fatal("Invalid Temporal client configuration", "error", err)
+	}
+	clientOptions.MetricsHandler = workerStats
+	if cfg.Encryption.Enabled {
+		slog.Info("Encryption enabled for worker", "mode", cfg.Encryption.Mode, "keys", cfg.EncryptionKeySource(), "fips", cfg.Encryption.FIPS)
+	}
+	if cfg.Payload.Compression != string(codec.CompressionNone) {
+		slog.Info("Payload compression enabled", "algorithm", cfg.Payload.Compression)
 	}
 
-	// Create the Temporal client
-	c, err := client.Dial(clientOptions)
+	// Create the Temporal client, connecting over TLS, with a Temporal Cloud
+	// API key, or with JWT bearer tokens if configured
+	c, certReloader, err := temporalclient.Connect(cfg, &clientOptions)
 	if err != nil {
-		log.Fatalf("Unable to create Temporal client: %v", err)
+		fatal("Unable to create Temporal client", "error", err)
 	}
 	defer c.Close()
 
 	// Create worker
-	w := worker.New(c, taskQueue, worker.Options{})
+	// Activity inputs and outputs are logged at debug level with PII masked
+	redactFields := append(interceptors.DefaultRedactedFields, cfg.Logging.RedactFields...)
+	workerInterceptors := []interceptor.WorkerInterceptor{interceptors.NewLoggingInterceptor(redactFields)}
+
+	// Require signed tokens for order mutations when auth secrets are configured
+	if len(cfg.Auth.SignalSecrets) > 0 {
+		signer, err := authz.NewSigner(cfg.Auth.SignalSecrets...)
+		if err != nil {
+			fatal("Invalid signal auth configuration", "error", err)
+		}
+		workerInterceptors = append(workerInterceptors, authz.NewInterceptor(authz.Config{
+			Signer:           signer,
+			ProtectedSignals: models.ProtectedSignals,
+			SignalSenders:    workflows.SignalSenders,
+			ProtectQueries:   cfg.Auth.ProtectQueries,
+		}))
+		slog.Info("Signal authorization enabled")
+	}
+
+	// Concurrency and rate limits come from worker.orders; the payment worker
+	// can override them in worker.payments to size payments separately
+	if cfg.Worker.StickyCacheSize > 0 {
+		worker.SetStickyWorkflowCacheSize(cfg.Worker.StickyCacheSize)
+	}
+	// Activities are counted so shutdown can report what drained; fatal worker
+	// errors trigger the same shutdown as SIGTERM
+	activityTracker := interceptors.NewActivityTracker()
+	workerInterceptors = append(workerInterceptors, activityTracker)
+	workerErrCh := make(chan error, 2)
+	orderWorkerOptions := worker.Options{
+		Interceptors: workerInterceptors,
+		// On shutdown, in-flight activities get this long to finish and record
+		// their last heartbeat before their contexts are cancelled
+		WorkerStopTimeout: cfg.Worker.StopTimeout,
+		OnFatalError:      func(err error) { workerErrCh <- err },
+	}
+	applyWorkerTuning(&orderWorkerOptions, cfg.Worker.Orders)
+	paymentWorkerOptions := orderWorkerOptions
+	applyWorkerTuning(&paymentWorkerOptions, cfg.Worker.Payments)
+	// Sessions pin invoice generation and upload to one host
+	orderWorkerOptions.EnableSessionWorker = true
+	orderWorkerOptions.MaxConcurrentSessionExecutionSize = cfg.Worker.MaxConcurrentSessions
+
+	// Size activity concurrency by memory and CPU headroom rather than a fixed
+	// count, so bursts of expedited orders queue instead of exhausting memory
+	if resourceTuning := cfg.Worker.ResourceTuning; resourceTuning.Enabled {
+		supplierOptions := tuning.DefaultResourceSlotSupplierOptions()
+		supplierOptions.MinSlots = resourceTuning.MinActivitySlots
+		supplierOptions.MaxSlots = resourceTuning.MaxActivitySlots
+		supplierOptions.TargetMemoryUsage = resourceTuning.TargetMemoryUsage
+		supplierOptions.TargetCPUUsage = resourceTuning.TargetCPUUsage
+		sampler := tuning.NewRuntimeSampler(0)
+		supplierOptions.Sampler = sampler
+		activitySlots, err := tuning.NewResourceSlotSupplier(supplierOptions)
+		if err != nil {
+			fatal("Invalid resource tuning configuration", "error", err)
+		}
+		for _, options := range []*worker.Options{&orderWorkerOptions, &paymentWorkerOptions} {
+			if err := tuning.UseActivitySlotSupplier(options, activitySlots); err != nil {
+				fatal("Unable to create resource-based tuner", "error", err)
+			}
+		}
+		if sampler.MemoryLimit() == 0 {
+			slog.Warn("No memory limit detected; set GOMEMLIMIT so resource tuning can react to memory pressure")
+		}
+		slog.Info("Resource-based activity tuning enabled", "memory_limit_bytes", sampler.MemoryLimit(),
+			"target_memory_usage", supplierOptions.TargetMemoryUsage, "target_cpu_usage", supplierOptions.TargetCPUUsage)
+	}
+
+	// Feature flags come from FEATURE_* variables, then the flag file, then the
+	// remote flag service; workflows read them through side effects
+	flagProviders := []featureflags.Provider{featureflags.NewEnvProvider()}
+	if cfg.FeatureFlags.File != "" {
+		flagProviders = append(flagProviders, featureflags.NewFileProvider(cfg.FeatureFlags.File))
+	}
+	if cfg.FeatureFlags.URL != "" {
+		flagProviders = append(flagProviders, featureflags.NewRemoteProvider(cfg.FeatureFlags.URL, cfg.FeatureFlags.RefreshInterval))
+	}
+	flags := featureflags.New(flagProviders...)
+	flags.OnError = func(name string, err error) {
+		slog.Warn("Feature flag provider failed", "flag", name, "error", err)
+	}
+	featureflags.SetDefault(flags)
+
+	w := worker.New(c, taskQueue, orderWorkerOptions)
 
 	// Register workflows
 	w.RegisterWorkflow(workflows.OrderWorkflow)
+	// Payments now run on their own queue; this registration and the
+	// ProcessPayment activity below serve executions started before the move
 	w.RegisterWorkflow(workflows.PaymentWorkflow)
+	w.RegisterWorkflow(workflows.FailedOrderWorkflow)
+	w.RegisterWorkflow(workflows.ItemFulfillmentWorkflow)
+	w.RegisterWorkflow(workflows.OrderAnalyticsWorkflow)
+	w.RegisterWorkflow(workflows.OrderArchiveWorkflow)
+	w.RegisterWorkflow(workflows.CartReminderWorkflow)
+	w.RegisterWorkflow(workflows.TrackingWorkflow)
+	w.RegisterWorkflow(workflows.WarehouseFulfillmentWorkflow)
+	w.RegisterWorkflow(workflows.VendorFulfillmentWorkflow)
+	w.RegisterWorkflow(workflows.ReconciliationWorkflow)
+	w.RegisterWorkflow(workflows.SettlementWorkflow)
+	w.RegisterWorkflow(workflows.RiskAssessmentWorkflow)
 
 	// Register activities
-	orderActivities := activities.NewOrderActivities(validationURL)
+	validation := cfg.Validation
+	httpConfig := activities.DefaultHTTPConfig()
+	httpConfig.Timeout = validation.HTTP.Timeout
+	httpConfig.MaxIdleConns = validation.HTTP.MaxIdleConns
+	httpConfig.MaxRetries = validation.HTTP.MaxRetries
+	httpConfig.CAFile = validation.HTTP.CAFile
+	httpConfig.CertFile = validation.HTTP.ClientCertFile
+	httpConfig.KeyFile = validation.HTTP.ClientKeyFile
+	httpConfig.ServerName = validation.HTTP.TLSServerName
+	httpConfig.ProxyURL = validation.HTTP.ProxyURL
+
+	orderActivities, err := activities.NewOrderActivitiesWithConfig(validation.URL, httpConfig)
+	if err != nil {
+		fatal("Failed to create order activities", "error", err)
+	}
+
+	validationAuth, err := activities.NewRequestAuthenticator(activities.AuthConfig{
+		APIKey:             validation.Auth.APIKey,
+		APIKeyHeader:       validation.Auth.APIKeyHeader,
+		OAuth2TokenURL:     validation.Auth.OAuth2TokenURL,
+		OAuth2ClientID:     validation.Auth.OAuth2ClientID,
+		OAuth2ClientSecret: validation.Auth.OAuth2ClientSecret,
+		OAuth2Scopes:       validation.Auth.OAuth2Scopes,
+	}, orderActivities.HTTPClient)
+	if err != nil {
+		fatal("Invalid validation service auth configuration", "error", err)
+	}
+	orderActivities.ValidationAuth = validationAuth
+
+	orderActivities.LocalRules = activities.NewRulesValidator(cfg.LocalValidationRules())
+	orderActivities.OpsWebhookURL = cfg.Ops.WebhookURL
+	orderActivities.NotificationURL = cfg.Notifications.URL
+	orderActivities.OutOfStockItems = cfg.Simulation.OutOfStockItems
+	orderActivities.InvoiceDir = cfg.Invoices.Dir
+	orderActivities.InvoiceStoreDir = cfg.Invoices.StoreDir
+	orderActivities.PaymentDeclineOver = cfg.Simulation.PaymentDeclineOver
+	orderActivities.GiftCardBalance = cfg.Simulation.GiftCardBalance
+	orderActivities.FraudMaxAmountPerItem = cfg.Fraud.MaxAmountPerItem
+	orderActivities.ScreeningURL = cfg.Screening.URL
+	orderActivities.ScreeningDenylist = cfg.Screening.Denylist
+	orderActivities.LoyaltyURL = cfg.Loyalty.URL
+	orderActivities.LoyaltyPointsPerDollar = cfg.Loyalty.PointsPerDollar
+	orderActivities.CustomerURL = cfg.Customers.URL
+	orderActivities.CarrierURL = cfg.Carrier.URL
+	orderActivities.VendorURL = cfg.Vendor.URL
+	orderActivities.PaymentGatewayURL = cfg.Reconciliation.GatewayURL
+	orderActivities.FinanceWebhookURL = cfg.Reconciliation.FinanceWebhookURL
+	orderActivities.BaseCurrency = cfg.Currency.Base
+	switch {
+	case cfg.Currency.RatesURL != "":
+		rates := &activities.HTTPExchangeRates{URL: cfg.Currency.RatesURL, Client: orderActivities.HTTPClient}
+		orderActivities.ExchangeRates = activities.NewCachedExchangeRates(rates, cfg.Currency.CacheTTL)
+	case len(cfg.Currency.FixedRates) > 0:
+		orderActivities.ExchangeRates = activities.FixedExchangeRates(cfg.Currency.FixedRates)
+	}
+	for _, warehouse := range cfg.Warehouses {
+		orderActivities.Warehouses = append(orderActivities.Warehouses, models.Warehouse{
+			ID:        warehouse.ID,
+			Stock:     warehouse.Stock,
+			Distances: warehouse.Distances,
+		})
+	}
+	orderActivities.Flags = flags
+
+	breakerConfig := activities.DefaultCircuitBreakerConfig()
+	breakerConfig.FailureThreshold = validation.CircuitBreaker.FailureThreshold
+	breakerConfig.OpenTimeout = validation.CircuitBreaker.OpenTimeout
+	orderActivities.ValidationBreaker = activities.NewCircuitBreaker("validation", breakerConfig)
+	if rps := validation.RateLimit.RequestsPerSecond; rps > 0 {
+		limitConfig := activities.DefaultRateLimitConfig()
+		limitConfig.RequestsPerSecond = rps
+		limitConfig.Burst = validation.RateLimit.Burst
+		if limitConfig.Burst == 0 {
+			limitConfig.Burst = int(rps)
+		}
+		limitConfig.MaxWait = validation.RateLimit.MaxWait
+		orderActivities.ValidationLimiter = activities.NewRateLimiter("validation", limitConfig)
+		slog.Info("Validation requests rate limited", "requests_per_second", rps)
+	}
 	w.RegisterActivity(orderActivities.ValidateOrder)
+	w.RegisterActivity(orderActivities.ValidateOrderLocally)
+	w.RegisterActivity(orderActivities.CheckFraud)
+	w.RegisterActivity(orderActivities.ScreenCustomer)
+	w.RegisterActivity(orderActivities.GetCustomerHistory)
+	w.RegisterActivity(orderActivities.RequestStepUpVerification)
+	w.RegisterActivity(orderActivities.NotifyOpsOfScreeningHit)
 	w.RegisterActivity(orderActivities.ProcessOrder)
+	w.RegisterActivity(orderActivities.FulfillItem)
+	w.RegisterActivity(orderActivities.CheckStock)
+	w.RegisterActivity(orderActivities.ReserveItem)
+	w.RegisterActivity(orderActivities.PickItem)
+	w.RegisterActivity(orderActivities.PackItem)
 	w.RegisterActivity(orderActivities.NotifyOrderComplete)
+	w.RegisterActivity(orderActivities.GenerateInvoice)
+	w.RegisterActivity(orderActivities.UploadInvoice)
+	w.RegisterActivity(orderActivities.NotifyOpsOfFailure)
 	w.RegisterActivity(orderActivities.ProcessPayment) // Version 1
+	w.RegisterActivity(orderActivities.RefundPayment)
+	w.RegisterActivity(orderActivities.ConvertCurrency)
+	w.RegisterActivity(orderActivities.RedeemLoyaltyPoints)
+	w.RegisterActivity(orderActivities.AwardLoyaltyPoints)
+	w.RegisterActivity(orderActivities.ReverseLoyaltyPoints)
+	w.RegisterActivity(orderActivities.HoldGiftCardBalance)
+	w.RegisterActivity(orderActivities.CaptureGiftCard)
+	w.RegisterActivity(orderActivities.ReleaseGiftCard)
+	w.RegisterActivity(orderActivities.SendCartReminder)
+	w.RegisterActivity(orderActivities.TrackShipment)
+	w.RegisterActivity(orderActivities.SelectWarehouse)
+	w.RegisterActivity(orderActivities.SendPurchaseOrder)
+	w.RegisterActivity(orderActivities.NotifyOpsOfUnresponsiveVendor)
+	w.RegisterActivity(orderActivities.FetchGatewayTransactions)
+	w.RegisterActivity(orderActivities.ReportDiscrepancies)
+	w.RegisterActivity(orderActivities.SettleBatch)
+	w.RegisterActivity(orderActivities.ReportSettlement)
+
+	// Payments run on their own task queue so their capacity and deployments
+	// are managed independently from fulfillment
+	paymentWorker := worker.New(c, workflows.PaymentTaskQueue, paymentWorkerOptions)
+	paymentWorker.RegisterWorkflow(workflows.PaymentWorkflow)
+	paymentWorker.RegisterWorkflow(workflows.InstallmentPaymentWorkflow)
+	paymentWorker.RegisterActivity(orderActivities.ProcessPayment)
+	paymentWorker.RegisterActivity(orderActivities.ConvertCurrency)
+	paymentWorker.RegisterActivity(orderActivities.RefundPayment) // rolls back split tenders and cancelled installment plans
+	// The payment Nexus service lets orders in another namespace pay through
+	// this worker; run it with worker.role payments in the payments namespace
+	paymentService, err := workflows.NewPaymentService()
+	if err != nil {
+		fatal("Failed to create payment Nexus service", "error", err)
+	}
+	paymentWorker.RegisterNexusService(paymentService)
+
+	// Serve dynamic config to workflows; the file is re-read when it changes
+	var dynamicConfig activities.DynamicConfigSource
+	if cfg.Dynamic.File != "" {
+		dynamicConfig = config.NewDynamicFile(cfg.Dynamic.File)
+		slog.Info("Reading dynamic config", "file", cfg.Dynamic.File)
+	}
+	configActivities := activities.NewConfigActivities(dynamicConfig)
+	w.RegisterActivity(configActivities.GetConfig)
 
-	log.Printf("Worker starting on task queue: %s", taskQueue)
-	log.Printf("Validation URL: %s", validationURL)
-	log.Printf("Temporal Host: %s", temporalHost)
+	// Register event publishing activity (no-op when Kafka is not configured)
+	var publisher events.Publisher = events.NoopPublisher{}
+	if len(cfg.Kafka.Brokers) > 0 {
+		kafkaPublisher, err := events.NewKafkaPublisher(cfg.Kafka.Brokers, cfg.Kafka.OrderEventsTopic)
+		if err != nil {
+			fatal("Failed to create Kafka publisher", "error", err)
+		}
+		publisher = kafkaPublisher
+		slog.Info("Publishing order events to Kafka", "topic", cfg.Kafka.OrderEventsTopic)
+	}
+	defer publisher.Close()
+	eventActivities := events.NewEventActivities(publisher)
+	w.RegisterActivity(eventActivities.PublishOrderEvent)
+
+	// Register analytics export activity for detached analytics children
+	var exporter events.AnalyticsExporter = events.NoopAnalyticsExporter{}
+	if len(cfg.Kafka.Brokers) > 0 {
+		kafkaExporter, err := events.NewKafkaAnalyticsExporter(cfg.Kafka.Brokers, cfg.Kafka.AnalyticsTopic)
+		if err != nil {
+			fatal("Failed to create Kafka analytics exporter", "error", err)
+		}
+		exporter = kafkaExporter
+	}
+	defer exporter.Close()
+	analyticsActivities := events.NewAnalyticsActivities(exporter)
+	w.RegisterActivity(analyticsActivities.ExportOrderAnalytics)
+
+	// Register the archive activity for detached order archival children
+	archiveDir := cfg.Archive.Dir
+	if archiveDir == "" {
+		archiveDir = filepath.Join(os.TempDir(), "order-archive")
+	}
+	var archiveStore archive.Store = archive.DirStore{Dir: archiveDir}
+	if cfg.Archive.Bucket != "" {
+		s3Store, err := archive.NewS3StoreFromEnv(context.Background(), cfg.Archive.Bucket, cfg.Archive.Prefix, cfg.Archive.Region, cfg.Archive.StorageClass)
+		if err != nil {
+			fatal("Failed to create S3 archive store", "error", err)
+		}
+		archiveStore = s3Store
+		slog.Info("Archiving completed orders to S3", "bucket", cfg.Archive.Bucket, "storage_class", cfg.Archive.StorageClass)
+	}
+	w.RegisterActivity(archive.NewActivities(archiveStore).ArchiveOrder)
+
+	// Register order store activities (no-op when no database is configured)
+	var orderRepo store.OrderRepository = store.NoopRepository{}
+	var orderDB *sql.DB
+	if cfg.Database.URL != "" {
+		pool := store.DefaultPoolConfig()
+		pool.MaxOpenConns = cfg.Database.MaxOpenConns
+		pool.MaxIdleConns = cfg.Database.MaxIdleConns
+
+		db, err := store.Open(context.Background(), cfg.Database.URL, pool)
+		if err != nil {
+			fatal("Failed to connect to order database", "error", err)
+		}
+		defer db.Close()
+
+		if err := store.Migrate(context.Background(), db); err != nil {
+			fatal("Failed to migrate order database", "error", err)
+		}
+		orderRepo = store.NewPostgresRepository(db)
+		orderDB = db
+		slog.Info("Mirroring order state to Postgres")
+	}
+	storeActivities := store.NewStoreActivities(orderRepo)
+	w.RegisterActivity(storeActivities.PersistOrder)
+	w.RegisterActivity(storeActivities.UpdateOrderStatus)
+	w.RegisterActivity(storeActivities.RecordOrderFailure)
+	w.RegisterActivity(storeActivities.ListCompletedOrders)
+	w.RegisterActivity(storeActivities.RecordSettlement)
+
+	// Reconcile and settle each day's payments on the configured schedules;
+	// an existing schedule is left as it is
+	if cfg.Worker.Role != config.RolePayments {
+		schedules := []struct {
+			id, workflow, cron string
+			request            any
+		}{
+			{models.ReconciliationScheduleID, workflows.ReconciliationWorkflowName, cfg.Reconciliation.Schedule, models.ReconciliationRequest{}},
+			{models.SettlementScheduleID, workflows.SettlementWorkflowName, cfg.Reconciliation.SettlementSchedule, models.SettlementRequest{}},
+		}
+		for _, schedule := range schedules {
+			if schedule.cron == "" {
+				continue
+			}
+			scheduleCtx, scheduleCancel := context.WithTimeout(context.Background(), 30*time.Second)
+			err := createDailySchedule(scheduleCtx, c, schedule.id, schedule.workflow, schedule.cron, schedule.request)
+			scheduleCancel()
+			if err != nil {
+				fatal("Failed to create payment schedule", "schedule_id", schedule.id, "error", err)
+			}
+			slog.Info("Payment workflow scheduled", "schedule_id", schedule.id, "cron", schedule.cron)
+		}
+	}
+
+	build := buildinfo.Get()
+	slog.Info("Worker starting", "task_queue", taskQueue, "validation_url", validation.URL, "temporal_host", clientOptions.HostPort,
+		"version", build.Version, "git_sha", build.GitSHA)
 
 	// Create and configure health check server
-	healthServer := health.NewServer(healthPort)
+	healthServer := health.NewServer(cfg.Health.Port)
+	healthServer.CacheTTL = cfg.Health.CacheTTL
+	healthServer.GRPCPort = cfg.Health.GRPCPort
+	healthServer.FailureThreshold = cfg.Health.FailureThreshold
+	healthServer.RecoveryThreshold = cfg.Health.RecoveryThreshold
+	healthServer.TLS = health.TLSConfig(cfg.Health.TLS)
+	healthServer.AuthTokens = cfg.Health.AuthTokens
+	if healthServer.TLS.Enabled() {
+		slog.Info("TLS enabled for health check server", "mtls", cfg.Health.TLS.ClientCAFile != "")
+	}
+	healthServer.OnTransition(func(t health.Transition) {
+		slog.Warn("Health status changed", "component", t.Component, "from", t.From, "to", t.To, "message", t.Health.Message)
+	})
 
 	// Register Temporal health check
-	healthServer.RegisterChecker(health.NewTemporalChecker(c))
+	temporalChecker := health.NewTemporalChecker(c)
+	temporalChecker.Thresholds = health.LatencyThresholds(cfg.Health.Temporal)
+	temporalChecker.Endpoint = clientOptions.HostPort
+	healthServer.RegisterChecker(temporalChecker)
+
+	// Report slot usage, pollers, and sticky cache hit rate
+	healthServer.RegisterWorkerStats(workerStats)
+
+	// Register WireMock health check; the worker can still process payments
+	// while it is down, so it only degrades /health
+	wiremockHealthURL := cfg.Health.WiremockURL + "/__admin/"
+	wiremockChecker := health.NewHTTPChecker("wiremock", wiremockHealthURL)
+	wiremockChecker.Thresholds = health.LatencyThresholds(cfg.Health.Wiremock)
+	healthServer.RegisterCheckerWithCriticality(wiremockChecker, health.CriticalityInformational)
+
+	// Register Postgres and Kafka health checks when they are configured; the
+	// activities using them retry, so they only degrade /health
+	if orderDB != nil {
+		postgresChecker := health.NewSQLChecker("postgres", orderDB)
+		postgresChecker.Thresholds = health.LatencyThresholds(cfg.Health.Postgres)
+		healthServer.RegisterCheckerWithCriticality(postgresChecker, health.CriticalityInformational)
+	}
+	if len(cfg.Kafka.Brokers) > 0 {
+		kafkaChecker := health.NewKafkaChecker(cfg.Kafka.Brokers, cfg.Kafka.OrderEventsTopic)
+		kafkaChecker.Thresholds = health.LatencyThresholds(cfg.Health.Kafka)
+		healthServer.RegisterCheckerWithCriticality(kafkaChecker, health.CriticalityInformational)
+	}
 
-	// Register WireMock health check
-	wiremockHealthURL := getEnv("WIREMOCK_URL", "http://localhost:8081") + "/__admin/"
-	healthServer.RegisterChecker(health.NewHTTPChecker("wiremock", wiremockHealthURL))
+	// Push health to a StatsD agent for workers that cannot be probed
+	if cfg.Health.Push.Address != "" {
+		publisher, err := health.NewStatsDPublisher(cfg.Health.Push.Address, health.StatsDOptions{
+			Prefix: cfg.Health.Push.Prefix,
+			Format: cfg.Health.Push.Format,
+			Tags:   cfg.Health.Push.Tags,
+		})
+		if err != nil {
+			fatal("Failed to create health publisher", "error", err)
+		}
+		defer publisher.Close()
+		healthServer.RegisterPublisher(publisher, cfg.Health.Push.Interval)
+		slog.Info("Pushing health to StatsD agent", "address", cfg.Health.Push.Address, "interval", cfg.Health.Push.Interval)
+	}
 
 	// Start health check server
 	if err := healthServer.Start(); err != nil {
-		log.Fatalf("Failed to start health check server: %v", err)
+		fatal("Failed to start health check server", "error", err)
+	}
+
+	// Start metrics server
+	if err := metricsServer.Start(); err != nil {
+		fatal("Failed to start metrics server", "error", err)
 	}
 
 	// Setup graceful shutdown
@@ -93,75 +500,133 @@ func main() {
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
 
-	// Start worker in goroutine
-	errCh := make(chan error, 1)
-	go func() {
-		log.Println("Worker started successfully")
-		if err := w.Run(worker.InterruptCh()); err != nil {
-			errCh <- err
+	// Reload the client certificate on SIGHUP so rotated certificates are
+	// used for new connections without restarting the worker
+	if certReloader != nil {
+		hupCh := make(chan os.Signal, 1)
+		signal.Notify(hupCh, syscall.SIGHUP)
+		go func() {
+			for range hupCh {
+				if err := certReloader.Reload(); err != nil {
+					slog.Error("Failed to reload Temporal client certificate", "error", err)
+					continue
+				}
+				slog.Info("Reloaded Temporal client certificate")
+			}
+		}()
+	}
+
+	// worker.role runs only the order or payment worker, so each can be
+	// deployed and scaled on its own; by default this process runs both
+	runningWorkers := map[string]worker.Worker{}
+	switch cfg.Worker.Role {
+	case config.RoleAll:
+		runningWorkers[taskQueue] = w
+		runningWorkers[workflows.PaymentTaskQueue] = paymentWorker
+	case config.RoleOrders:
+		runningWorkers[taskQueue] = w
+	case config.RolePayments:
+		runningWorkers[workflows.PaymentTaskQueue] = paymentWorker
+	}
+
+	// Start workers; they keep polling until stopped below
+	for queue, runningWorker := range runningWorkers {
+		if err := runningWorker.Start(); err != nil {
+			fatal("Unable to start worker", "task_queue", queue, "error", err)
 		}
-	}()
+		slog.Info("Worker started successfully", "task_queue", queue)
+	}
 
 	// Wait for shutdown signal or error
 	select {
 	case <-sigCh:
-		log.Println("Received shutdown signal, gracefully stopping...")
-	case err := <-errCh:
-		log.Printf("Worker error: %v", err)
+		slog.Info("Received shutdown signal, draining")
+	case err := <-workerErrCh:
+		slog.Error("Worker error", "error", err)
 	}
 
-	// Graceful shutdown with timeout
-	shutdownCtx, shutdownCancel := context.WithTimeout(ctx, 30*time.Second)
-	defer shutdownCancel()
+	// Stop polling and drain in-flight activities. Workers stop in parallel so
+	// the whole drain is bounded by worker.stop_timeout.
+	inFlight := activityTracker.InFlight()
+	completedBefore, interruptedBefore := activityTracker.Completed(), activityTracker.Interrupted()
+	slog.Info("Stopping workers", "in_flight_activities", inFlight, "stop_timeout", orderWorkerOptions.WorkerStopTimeout)
+	var stopping sync.WaitGroup
+	for queue, runningWorker := range runningWorkers {
+		stopping.Add(1)
+		go func() {
+			defer stopping.Done()
+			runningWorker.Stop()
+			slog.Info("Worker stopped", "task_queue", queue)
+		}()
+	}
+	stopping.Wait()
+	interrupted := activityTracker.Interrupted() - interruptedBefore
+	slog.Info("Workers drained",
+		"drained_activities", activityTracker.Completed()-completedBefore-interrupted,
+		"interrupted_activities", interrupted,
+		"still_running_activities", activityTracker.InFlight())
 
-	log.Println("Stopping worker...")
-	w.Stop()
+	// Shut down the remaining servers with timeout
+	shutdownCtx, shutdownCancel := context.WithTimeout(ctx, 10*time.Second)
+	defer shutdownCancel()
 
-	log.Println("Stopping health check server...")
+	slog.Info("Stopping health check server")
 	if err := healthServer.Shutdown(shutdownCtx); err != nil {
-		log.Printf("Health server shutdown error: %v", err)
+		slog.Error("Health server shutdown error", "error", err)
+	}
+
+	slog.Info("Stopping metrics server")
+	if err := metricsServer.Shutdown(shutdownCtx); err != nil {
+		slog.Error("Metrics server shutdown error", "error", err)
 	}
 
-	log.Println("Worker shutdown complete")
+	slog.Info("Worker shutdown complete")
 }
 
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
+// fatal logs an error and exits
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
 }
 
-func getEnvAsInt(key string, defaultValue int) int {
-	if value := os.Getenv(key); value != "" {
-		if intVal, err := strconv.Atoi(value); err == nil {
-			return intVal
-		}
+// createDailySchedule creates a schedule that starts a workflow with
+// request, naming no day so that it handles the day before, on each cron
+// tick. A schedule that already exists, such as one created by another
+// worker, is kept; change its spec with the Temporal CLI.
+func createDailySchedule(ctx context.Context, c client.Client, id, workflow, cron string, request any) error {
+	_, err := c.ScheduleClient().Create(ctx, client.ScheduleOptions{
+		ID:   id,
+		Spec: client.ScheduleSpec{CronExpressions: []string{cron}},
+		Action: &client.ScheduleWorkflowAction{
+			ID:        id,
+			Workflow:  workflow,
+			Args:      []interface{}{request},
+			TaskQueue: taskQueue,
+		},
+		Overlap: enums.SCHEDULE_OVERLAP_POLICY_SKIP,
+	})
+	if errors.Is(err, temporal.ErrScheduleAlreadyRunning) {
+		return nil
 	}
-	return defaultValue
+	return err
 }
 
-func generateOrGetEncryptionKey() []byte {
-	// In production, load this from a secure key management system
-	keyFile := ".encryption.key"
-
-	// Try to read existing key
-	if key, err := os.ReadFile(keyFile); err == nil && len(key) == 32 {
-		log.Println("Using existing encryption key")
-		return key
+// applyWorkerTuning overrides worker capacity settings with the non-zero
+// values in settings, so the payment worker inherits what it does not set
+func applyWorkerTuning(options *worker.Options, settings config.WorkerTuning) {
+	if settings.MaxConcurrentActivities != 0 {
+		options.MaxConcurrentActivityExecutionSize = settings.MaxConcurrentActivities
 	}
-
-	// Generate new key
-	key := make([]byte, 32) // AES-256
-	if _, err := rand.Read(key); err != nil {
-		log.Fatalf("Failed to generate encryption key: %v", err)
+	if settings.MaxConcurrentWorkflowTasks != 0 {
+		options.MaxConcurrentWorkflowTaskExecutionSize = settings.MaxConcurrentWorkflowTasks
 	}
-
-	// Save key for future use (development only!)
-	if err := os.WriteFile(keyFile, key, 0600); err != nil {
-		log.Printf("Warning: Failed to save encryption key: %v", err)
+	if settings.MaxConcurrentLocalActivities != 0 {
+		options.MaxConcurrentLocalActivityExecutionSize = settings.MaxConcurrentLocalActivities
+	}
+	if settings.ActivitiesPerSecond != 0 {
+		options.WorkerActivitiesPerSecond = settings.ActivitiesPerSecond
+	}
+	if settings.TaskQueueActivitiesPerSecond != 0 {
+		options.TaskQueueActivitiesPerSecond = settings.TaskQueueActivitiesPerSecond
 	}
-
-	log.Println("Generated new encryption key")
-	return key
 }
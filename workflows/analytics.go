@@ -0,0 +1,56 @@
+package workflows
+
+import (
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/workflow"
+)
+
+// OrderAnalyticsWorkflowName is the registered name of OrderAnalyticsWorkflow
+const OrderAnalyticsWorkflowName = "OrderAnalyticsWorkflow"
+
+// OrderAnalyticsWorkflow exports the analytics record of a completed order.
+// It runs detached from the order, so a slow or unavailable analytics store
+// never holds up or fails the order itself.
+func OrderAnalyticsWorkflow(ctx workflow.Context, record models.OrderAnalytics) error {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: 30 * time.Second,
+		RetryPolicy: &RetryPolicy{
+			InitialInterval:    time.Second,
+			BackoffCoefficient: 2.0,
+			MaximumInterval:    time.Minute,
+			MaximumAttempts:    10,
+		},
+	})
+	return workflow.ExecuteActivity(ctx, "ExportOrderAnalytics", record).Get(ctx, nil)
+}
+
+// exportAnalytics starts OrderAnalyticsWorkflow for the order as a child
+// that, by default, is abandoned so it keeps running after the order closes.
+// Only the start is awaited; a failure to start is logged, not fatal.
+func exportAnalytics(ctx workflow.Context, order models.Order, memo models.OrderMemo, result *models.OrderResult) {
+	logger := workflow.GetLogger(ctx)
+	info := workflow.GetInfo(ctx)
+
+	record := models.OrderAnalytics{
+		OrderResult: *result,
+		OrderMemo:   memo,
+		Amount:      order.Amount,
+		ItemCount:   len(order.Items),
+		ClosedAt:    workflow.Now(ctx),
+	}
+	childOptions := workflow.ChildWorkflowOptions{
+		WorkflowID:        models.OrderAnalyticsWorkflowID(order.ID, info.WorkflowExecution.RunID),
+		ParentClosePolicy: parentClosePolicy(ctx, models.ChildAnalytics, enums.PARENT_CLOSE_POLICY_ABANDON),
+	}
+	childCtx := workflow.WithChildOptions(ctx, childOptions)
+
+	child := workflow.ExecuteChildWorkflow(childCtx, OrderAnalyticsWorkflowName, record)
+	if err := child.GetChildWorkflowExecution().Get(ctx, nil); err != nil {
+		logger.Warn("Failed to start analytics export", "order_id", order.ID, "error", err)
+		return
+	}
+	logger.Info("Analytics export started", "order_id", order.ID, "workflow_id", childOptions.WorkflowID)
+}
@@ -0,0 +1,82 @@
+package workflows
+
+import (
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/workflow"
+)
+
+// OrderArchiveWorkflowName is the registered name of OrderArchiveWorkflow
+const OrderArchiveWorkflowName = "OrderArchiveWorkflow"
+
+// OrderArchiveWorkflow writes a completed order's archive to cold storage
+// with ArchiveOrder. With a PII retention period, it then sleeps through the
+// period on a durable timer and archives the order again without its
+// personal data. It runs detached from the order, so cold storage never
+// holds up or fails the order itself.
+func OrderArchiveWorkflow(ctx workflow.Context, req models.OrderArchiveRequest) error {
+	logger := workflow.GetLogger(ctx)
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: 30 * time.Second,
+		RetryPolicy: &RetryPolicy{
+			InitialInterval:    time.Second,
+			BackoffCoefficient: 2.0,
+			MaximumInterval:    time.Minute,
+			MaximumAttempts:    10,
+		},
+	})
+
+	var key string
+	if err := workflow.ExecuteActivity(ctx, "ArchiveOrder", req.Archive).Get(ctx, &key); err != nil {
+		logger.Error("Failed to archive order", "order_id", req.Archive.Order.ID, "error", err)
+		return err
+	}
+	logger.Info("Order archived", "order_id", req.Archive.Order.ID, "key", key)
+	if req.PIIRetention <= 0 {
+		return nil
+	}
+
+	if err := workflow.Sleep(ctx, req.PIIRetention); err != nil {
+		return err
+	}
+	if err := workflow.ExecuteActivity(ctx, "ArchiveOrder", req.Archive.WithoutPII()).Get(ctx, nil); err != nil {
+		logger.Error("Failed to purge PII from order archive", "order_id", req.Archive.Order.ID, "error", err)
+		return err
+	}
+	logger.Info("PII purged from order archive", "order_id", req.Archive.Order.ID, "key", key)
+	return nil
+}
+
+// archiveOrder starts OrderArchiveWorkflow for the order as a child that, by
+// default, is abandoned so it keeps running after the order closes. Only the
+// start is awaited; a failure to start is logged, not fatal.
+func archiveOrder(ctx workflow.Context, order models.Order, state *models.OrderStatus, history []models.StatusChange, result *models.OrderResult, retention time.Duration) {
+	logger := workflow.GetLogger(ctx)
+	info := workflow.GetInfo(ctx)
+
+	req := models.OrderArchiveRequest{
+		Archive: models.OrderArchive{
+			Order:      order,
+			Memo:       orderMemo(ctx),
+			Result:     *result,
+			Events:     history,
+			InvoiceURL: state.InvoiceURL,
+			ClosedAt:   workflow.Now(ctx),
+		},
+		PIIRetention: retention,
+	}
+	childOptions := workflow.ChildWorkflowOptions{
+		WorkflowID:        models.OrderArchiveWorkflowID(order.ID, info.WorkflowExecution.RunID),
+		ParentClosePolicy: parentClosePolicy(ctx, models.ChildArchive, enums.PARENT_CLOSE_POLICY_ABANDON),
+	}
+	childCtx := workflow.WithChildOptions(ctx, childOptions)
+
+	child := workflow.ExecuteChildWorkflow(childCtx, OrderArchiveWorkflowName, req)
+	if err := child.GetChildWorkflowExecution().Get(ctx, nil); err != nil {
+		logger.Warn("Failed to start order archival", "order_id", order.ID, "error", err)
+		return
+	}
+	logger.Info("Order archival started", "order_id", order.ID, "workflow_id", childOptions.WorkflowID)
+}
@@ -0,0 +1,143 @@
+package workflows
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// errBackorderCancelled is returned by awaitRestock when the order is
+// cancelled while backordered
+var errBackorderCancelled = errors.New("order cancelled while backordered")
+
+// fulfillFunc fulfills the items of order, recording each outcome in
+// state.ItemResults
+type fulfillFunc func(ctx workflow.Context, order models.Order, state *models.OrderStatus) error
+
+// backorder carries what awaitRestock needs from the order workflow: the
+// flags its restock and cancel signal handlers set, and whether status
+// changes are persisted
+type backorder struct {
+	restocked       *bool
+	cancelRequested *bool
+	persistEnabled  bool
+}
+
+// awaitRestock holds an order whose items are out of stock in the
+// backordered status until a restock signal arrives, or a CheckStock recheck
+// finds the items available, and then fulfills those items again. It repeats
+// while items stay out of stock, up to the configured backorder timeout.
+// It returns nil once every item is fulfilled; errBackorderCancelled when
+// the order is cancelled; and otherwise the error the order fails with.
+func awaitRestock(ctx workflow.Context, order models.Order, state *models.OrderStatus, config models.DynamicConfig, fulfill fulfillFunc, b backorder, err error) error {
+	logger := workflow.GetLogger(ctx)
+	deadline := workflow.Now(ctx).Add(config.BackorderTimeout)
+
+	for {
+		missing := outOfStockItems(err)
+		if len(missing) == 0 {
+			return err
+		}
+
+		setOrderStatus(ctx, state, models.StatusBackordered)
+		state.LastUpdated = workflow.Now(ctx)
+		if b.persistEnabled {
+			persistOrderStatus(ctx, state)
+		}
+		logger.Info("Order backordered", "order_id", order.ID, "items", missing)
+
+		*b.restocked = false
+		for !*b.restocked {
+			remaining := deadline.Sub(workflow.Now(ctx))
+			if remaining <= 0 {
+				logger.Warn("Backorder timed out", "order_id", order.ID, "items", missing, "timeout", config.BackorderTimeout)
+				return err
+			}
+			wait := remaining
+			if config.BackorderRecheckInterval > 0 {
+				wait = min(wait, config.BackorderRecheckInterval)
+			}
+			signaled, waitErr := workflow.AwaitWithTimeout(ctx, wait, func() bool { return *b.restocked || *b.cancelRequested })
+			if waitErr != nil {
+				return waitErr
+			}
+			if *b.cancelRequested {
+				return errBackorderCancelled
+			}
+			if !signaled && config.BackorderRecheckInterval > 0 && workflow.Now(ctx).Before(deadline) {
+				*b.restocked = inStock(ctx, order, missing)
+			}
+		}
+
+		logger.Info("Backordered items restocked, resuming fulfillment", "order_id", order.ID, "items", missing)
+		setOrderStatus(ctx, state, models.StatusProcessing)
+		state.LastUpdated = workflow.Now(ctx)
+		if b.persistEnabled {
+			persistOrderStatus(ctx, state)
+		}
+		err = refulfillItems(ctx, order, state, fulfill, missing)
+		if err == nil {
+			return nil
+		}
+	}
+}
+
+// outOfStockItems returns the items of an out-of-stock rejection, or nil
+// when err is any other error
+func outOfStockItems(err error) []string {
+	var appErr *temporal.ApplicationError
+	if !errors.As(err, &appErr) || appErr.Type() != models.ErrTypeInventoryOutOfStock {
+		return nil
+	}
+	var details models.InventoryOutOfStockError
+	if appErr.Details(&details) != nil {
+		return nil
+	}
+	return details.Items
+}
+
+// inStock reports whether CheckStock finds every item available. A failed
+// check counts as still out of stock.
+func inStock(ctx workflow.Context, order models.Order, items []string) bool {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: 10 * time.Second,
+		RetryPolicy: &RetryPolicy{
+			InitialInterval:    time.Second,
+			BackoffCoefficient: 2.0,
+			MaximumAttempts:    3,
+		},
+	})
+	var missing []string
+	if err := workflow.ExecuteActivity(ctx, "CheckStock", items).Get(ctx, &missing); err != nil {
+		workflow.GetLogger(ctx).Warn("Stock recheck failed", "order_id", order.ID, "error", err)
+		return false
+	}
+	return len(missing) == 0
+}
+
+// refulfillItems fulfills again the items of the order that failed as out
+// of stock, merging their outcomes into state.ItemResults
+func refulfillItems(ctx workflow.Context, order models.Order, state *models.OrderStatus, fulfill fulfillFunc, missing []string) error {
+	results := state.ItemResults
+	retry := order
+	retry.Items = nil
+	var indexes []int
+	for i, result := range results {
+		if result.Status == models.ItemFailed && slices.Contains(missing, result.Item) {
+			retry.Items = append(retry.Items, result.Item)
+			indexes = append(indexes, i)
+		}
+	}
+
+	err := fulfill(ctx, retry, state)
+	retried := state.ItemResults
+	state.ItemResults = results
+	for j, i := range indexes {
+		state.ItemResults[i] = retried[j]
+	}
+	return err
+}
@@ -0,0 +1,67 @@
+package workflows
+
+import (
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/workflow"
+)
+
+type cancellationCompensationKey struct{}
+
+// withCancellationCompensation marks ctx so that a cancellation of the
+// workflow is handled by compensateCancellation rather than as a failure
+func withCancellationCompensation(ctx workflow.Context) workflow.Context {
+	return workflow.WithValue(ctx, cancellationCompensationKey{}, true)
+}
+
+// compensatingCancellation reports whether ctx was marked by
+// withCancellationCompensation and the workflow has been cancelled, in which
+// case the failure of the interrupted step is not the order's outcome
+func compensatingCancellation(ctx workflow.Context) bool {
+	marked, _ := ctx.Value(cancellationCompensationKey{}).(bool)
+	return marked && ctx.Err() != nil
+}
+
+// compensateCancellation undoes what a cancelled order has done so far:
+// a captured payment is refunded, loyalty points are reversed, gift card
+// holds are released, and the order is marked cancelled. ctx is
+// already cancelled, so the work runs on a disconnected context. It returns
+// the cancellation, so the workflow closes as canceled.
+func compensateCancellation(ctx workflow.Context, order models.Order, state *models.OrderStatus, transactionID string, tenders []models.TenderPayment, persistEnabled bool) error {
+	logger := workflow.GetLogger(ctx)
+	cause := ctx.Err()
+	ctx, _ = workflow.NewDisconnectedContext(ctx)
+	logger.Info("Order workflow cancelled, compensating", "order_id", order.ID, "stage", state.Stage)
+
+	if transactionID != "" {
+		refundPayment(ctx, order, state, transactionID, tenders)
+	}
+	reverseLoyaltyPoints(ctx, order)
+	releaseGiftCards(ctx, order)
+
+	setOrderStatus(ctx, state, models.StatusCancelled)
+	state.LastUpdated = workflow.Now(ctx)
+	if persistEnabled {
+		persistOrderStatus(ctx, state)
+	}
+	logger.Info("Order cancelled", "order_id", order.ID, "payment_status", state.PaymentStatus)
+	recordTerminalStatus(ctx, state.Status)
+	return cause
+}
+
+// refundPayment refunds the order's captured payment, tender by tender for a
+// split-tender payment, and records the outcome in state.PaymentStatus
+func refundPayment(ctx workflow.Context, order models.Order, state *models.OrderStatus, transactionID string, tenders []models.TenderPayment) {
+	var err error
+	if len(tenders) > 0 {
+		err = refundTenders(ctx, order.ID, tenders)
+	} else {
+		refund := models.RefundRequest{OrderID: order.ID, TransactionID: transactionID, Amount: order.Amount}
+		err = workflow.ExecuteActivity(ctx, "RefundPayment", refund).Get(ctx, nil)
+	}
+	if err != nil {
+		workflow.GetLogger(ctx).Error("Failed to refund cancelled order", "order_id", order.ID, "transaction_id", transactionID, "error", err)
+		state.PaymentStatus = "refund_failed"
+	} else {
+		state.PaymentStatus = "refunded"
+	}
+}
@@ -0,0 +1,99 @@
+package workflows
+
+import (
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/workflow"
+)
+
+// CartReminderWorkflowName is the registered name of CartReminderWorkflow
+const CartReminderWorkflowName = "CartReminderWorkflow"
+
+// Schedule of the reminders for carts that do not set their own
+var (
+	DefaultCartReminders   = []time.Duration{time.Hour, 24 * time.Hour, 72 * time.Hour}
+	DefaultCartExpireAfter = 24 * time.Hour
+)
+
+// CartReminderWorkflow follows a cart from its creation until checkout. If
+// no checkout-completed signal arrives within each of the cart's reminder
+// windows it sends an escalating reminder, and once the last window after
+// the final reminder passes the cart expires.
+func CartReminderWorkflow(ctx workflow.Context, cart models.Cart) (*models.CartStatus, error) {
+	logger := workflow.GetLogger(ctx)
+	reminders := cart.Reminders
+	if len(reminders) == 0 {
+		reminders = DefaultCartReminders
+	}
+	expireAfter := cart.ExpireAfter
+	if expireAfter <= 0 {
+		expireAfter = DefaultCartExpireAfter
+	}
+	logger.Info("Cart reminder workflow started", "cart_id", cart.ID, "reminders", len(reminders))
+
+	state := &models.CartStatus{
+		CartID:      cart.ID,
+		Status:      models.CartAwaitingCheckout,
+		LastUpdated: workflow.Now(ctx),
+	}
+
+	err := workflow.SetQueryHandler(ctx, "getStatus", func() (*models.CartStatus, error) {
+		return state, nil
+	})
+	if err != nil {
+		logger.Error("Failed to register query handler", "error", err)
+		return nil, err
+	}
+
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout:    10 * time.Second,
+		ScheduleToStartTimeout: 5 * time.Second,
+		RetryPolicy: &RetryPolicy{
+			InitialInterval:    time.Second,
+			BackoffCoefficient: 2.0,
+			MaximumInterval:    time.Minute,
+			MaximumAttempts:    5,
+		},
+	})
+
+	checkoutChannel := workflow.GetSignalChannel(ctx, models.SignalCheckoutCompleted)
+	checkedOut := func(checkout models.CheckoutCompleted) (*models.CartStatus, error) {
+		state.Status = models.CartCheckedOut
+		state.OrderID = checkout.OrderID
+		state.LastUpdated = workflow.Now(ctx)
+		logger.Info("Cart checked out", "cart_id", cart.ID, "order_id", checkout.OrderID, "reminders_sent", state.RemindersSent)
+		return state, nil
+	}
+
+	for i, wait := range reminders {
+		var checkout models.CheckoutCompleted
+		if received, _ := checkoutChannel.ReceiveWithTimeout(ctx, wait, &checkout); received {
+			return checkedOut(checkout)
+		}
+
+		// A missed reminder is not worth failing the cart over
+		reminder := models.CartReminder{
+			CartID:     cart.ID,
+			CustomerID: cart.CustomerID,
+			Items:      cart.Items,
+			Amount:     cart.Amount,
+			Number:     i + 1,
+			Final:      i == len(reminders)-1,
+		}
+		if err := workflow.ExecuteActivity(ctx, "SendCartReminder", reminder).Get(ctx, nil); err != nil {
+			logger.Warn("Failed to send cart reminder", "cart_id", cart.ID, "reminder", reminder.Number, "error", err)
+		}
+		state.RemindersSent++
+		state.LastUpdated = workflow.Now(ctx)
+	}
+
+	var checkout models.CheckoutCompleted
+	if received, _ := checkoutChannel.ReceiveWithTimeout(ctx, expireAfter, &checkout); received {
+		return checkedOut(checkout)
+	}
+	state.Status = models.CartExpired
+	state.LastUpdated = workflow.Now(ctx)
+	logger.Info("Cart expired without checkout", "cart_id", cart.ID, "reminders_sent", state.RemindersSent)
+	return state, nil
+}
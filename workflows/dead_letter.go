@@ -0,0 +1,65 @@
+package workflows
+
+import (
+	"strings"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/workflow"
+)
+
+// routeToDeadLetter hands a permanently failed order to FailedOrderWorkflow.
+// The child is abandoned so it outlives this workflow, which still fails
+// with the original error. A step interrupted by cancelling the workflow is
+// not dead-lettered. payment is what the order was charged, nil if it
+// failed before payment.
+func routeToDeadLetter(ctx workflow.Context, order models.Order, stage models.OrderStage, payment *models.PaymentResponse, cause error) {
+	if compensatingCancellation(ctx) {
+		return
+	}
+	logger := workflow.GetLogger(ctx)
+	info := workflow.GetInfo(ctx)
+
+	failure := models.OrderFailure{
+		Order:      order,
+		WorkflowID: info.WorkflowExecution.ID,
+		RunID:      info.WorkflowExecution.RunID,
+		Stage:      stage,
+		Reason:     cause.Error(),
+		Error:      models.OrderErrorFrom(cause),
+		FailedAt:   workflow.Now(ctx),
+	}
+	if failure.Error.Stage == "" {
+		failure.Error.Stage = stage
+	}
+	if payment != nil {
+		failure.TransactionID = payment.TransactionID
+		failure.Tenders = payment.Tenders
+	}
+
+	childOptions := workflow.ChildWorkflowOptions{
+		WorkflowID:        models.DeadLetterWorkflowID(order.ID, info.WorkflowExecution.RunID),
+		ParentClosePolicy: enums.PARENT_CLOSE_POLICY_ABANDON,
+	}
+	childCtx := workflow.WithChildOptions(ctx, childOptions)
+
+	child := workflow.ExecuteChildWorkflow(childCtx, FailedOrderWorkflow, failure)
+	if err := child.GetChildWorkflowExecution().Get(ctx, nil); err != nil {
+		logger.Error("Failed to dead-letter order", "order_id", order.ID, "error", err)
+		return
+	}
+	logger.Info("Order routed to dead-letter workflow", "order_id", order.ID, "workflow_id", childOptions.WorkflowID)
+}
+
+// redrivenPayment returns the payment a re-driven order was already charged,
+// or nil. It is only taken from a run started by the order's dead-letter
+// workflow, so an order submitted with a prior payment of its own is still
+// charged.
+func redrivenPayment(ctx workflow.Context, order models.Order) *models.PaymentResponse {
+	parent := workflow.GetInfo(ctx).ParentWorkflowExecution
+	if order.PriorPayment == nil || parent == nil ||
+		!strings.HasPrefix(parent.ID, models.DeadLetterWorkflowID(order.ID, "")) {
+		return nil
+	}
+	return order.PriorPayment
+}
@@ -0,0 +1,49 @@
+package workflows
+
+import (
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/workflow"
+)
+
+// loadDynamicConfig reads the current dynamic configuration with the GetConfig
+// local activity. The result is recorded in history, so replays reuse it even
+// after operators change the configuration. If it cannot be read, the zero
+// configuration is used, which disables approval and SLA tracking.
+func loadDynamicConfig(ctx workflow.Context) models.DynamicConfig {
+	ctx = workflow.WithLocalActivityOptions(ctx, workflow.LocalActivityOptions{
+		StartToCloseTimeout: 5 * time.Second,
+		RetryPolicy: &RetryPolicy{
+			InitialInterval:    time.Second,
+			BackoffCoefficient: 2.0,
+			MaximumAttempts:    3,
+		},
+	})
+
+	var config models.DynamicConfig
+	if err := workflow.ExecuteLocalActivity(ctx, "GetConfig").Get(ctx, &config); err != nil {
+		workflow.GetLogger(ctx).Warn("Failed to load dynamic config, using defaults", "error", err)
+		return models.DynamicConfig{}
+	}
+	return config
+}
+
+// requiresApproval reports whether the order is at or above the configured
+// high-value approval threshold
+func requiresApproval(config models.DynamicConfig, order models.Order) bool {
+	return config.HighValueApprovalThreshold > 0 && order.Amount >= config.HighValueApprovalThreshold
+}
+
+// watchProcessingSLA flags the order once it has run for longer than sla
+// without reaching a terminal status
+func watchProcessingSLA(ctx workflow.Context, state *models.OrderStatus, sla time.Duration) {
+	workflow.Go(ctx, func(ctx workflow.Context) {
+		if err := workflow.Sleep(ctx, sla); err != nil {
+			return
+		}
+		state.SLABreached = true
+		workflow.GetLogger(ctx).Warn("Order breached its processing SLA", "order_id", state.OrderID, "sla", sla, "status", state.Status, "stage", state.Stage)
+		recordSLABreach(ctx, state.Stage)
+	})
+}
@@ -0,0 +1,80 @@
+package workflows
+
+import (
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/workflow"
+)
+
+// editWindow is a paid order's edit window, shared by the workflow, its
+// update signal handler, and the getEditWindow query. closesAt is zero while
+// the window is closed.
+type editWindow struct {
+	closesAt time.Time
+	updates  []models.OrderUpdate
+}
+
+// open reports whether the window accepts updates
+func (w *editWindow) open() bool {
+	return !w.closesAt.IsZero()
+}
+
+// status returns the window as the getEditWindow query reports it at now
+func (w *editWindow) status(now time.Time) models.EditWindow {
+	if !w.open() {
+		return models.EditWindow{}
+	}
+	return models.EditWindow{Open: true, ClosesAt: w.closesAt, Remaining: max(w.closesAt.Sub(now), 0)}
+}
+
+// awaitEdits holds a paid order in its edit window for the grace period,
+// applying the updates signaled meanwhile to the order. It returns early
+// once the order is cancelled, and closes the window either way; updates
+// signaled after that are ignored.
+func awaitEdits(ctx workflow.Context, order *models.Order, window *editWindow, gracePeriod time.Duration, cancelRequested *bool, persistEnabled bool) error {
+	logger := workflow.GetLogger(ctx)
+	window.closesAt = workflow.Now(ctx).Add(gracePeriod)
+	defer func() {
+		window.closesAt = time.Time{}
+		window.updates = nil
+	}()
+	logger.Info("Edit window open", "order_id", order.ID, "closes_at", window.closesAt)
+
+	for !*cancelRequested {
+		remaining := window.closesAt.Sub(workflow.Now(ctx))
+		if remaining <= 0 {
+			break
+		}
+		_, err := workflow.AwaitWithTimeout(ctx, remaining, func() bool {
+			return *cancelRequested || len(window.updates) > 0
+		})
+		if err != nil {
+			return err
+		}
+
+		updated := false
+		for _, update := range window.updates {
+			if err := update.Validate(); err != nil {
+				logger.Warn("Order update rejected", "order_id", order.ID, "error", err)
+				continue
+			}
+			order.Items = update.Items
+			if order.Lines != nil {
+				// The lines are regrouped from the new items, without prices
+				order.Lines = nil
+				if upgraded, err := order.Upgrade(); err == nil {
+					*order = upgraded
+				}
+			}
+			updated = true
+			logger.Info("Order updated", "order_id", order.ID, "items", order.Items)
+		}
+		window.updates = nil
+		if updated && persistEnabled {
+			persistOrder(ctx, *order)
+		}
+	}
+	logger.Info("Edit window closed", "order_id", order.ID, "cancelled", *cancelRequested)
+	return nil
+}
@@ -0,0 +1,30 @@
+package workflows
+
+import (
+	"errors"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/temporal"
+)
+
+// applicationErrorType returns the type of the first ApplicationError in the
+// error chain, or "" when there is none
+func applicationErrorType(err error) string {
+	var appErr *temporal.ApplicationError
+	if errors.As(err, &appErr) {
+		return appErr.Type()
+	}
+	return ""
+}
+
+// isBusinessRejection reports whether err is a final business outcome, such as
+// a declined card, rather than an infrastructure failure worth retrying later
+func isBusinessRejection(err error) bool {
+	switch applicationErrorType(err) {
+	case models.ErrTypeValidationRejected, models.ErrTypePaymentDeclined, models.ErrTypeInventoryOutOfStock, models.ErrTypeFraudSuspected,
+		models.ErrTypeLoyaltyRedemptionRejected, models.ErrTypeCustomerDenied:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,50 @@
+package workflows
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/workflow"
+)
+
+// publishOrderEvent emits an order lifecycle event. Publishing is best-effort:
+// a failure is logged but never fails the order itself.
+func publishOrderEvent(ctx workflow.Context, eventType string, order models.Order, state *models.OrderStatus, transactionID, reason string) {
+	logger := workflow.GetLogger(ctx)
+	info := workflow.GetInfo(ctx)
+
+	event := models.OrderEvent{
+		SchemaVersion: models.OrderEventSchemaVersion,
+		// RunID + type is unique per emission and stable across replays,
+		// so consumers can use it to deduplicate redelivered messages
+		EventID:       fmt.Sprintf("%s:%s", info.WorkflowExecution.RunID, eventType),
+		Type:          eventType,
+		OrderID:       order.ID,
+		WorkflowID:    info.WorkflowExecution.ID,
+		Status:        state.Status,
+		Stage:         state.Stage,
+		Amount:        order.Amount,
+		Items:         order.Items,
+		TransactionID: transactionID,
+		Reason:        reason,
+		OccurredAt:    workflow.Now(ctx),
+	}
+
+	eventOptions := workflow.ActivityOptions{
+		StartToCloseTimeout:    15 * time.Second,
+		ScheduleToStartTimeout: 5 * time.Second,
+		RetryPolicy: &RetryPolicy{
+			InitialInterval:    time.Second,
+			BackoffCoefficient: 2.0,
+			MaximumInterval:    10 * time.Second,
+			MaximumAttempts:    5,
+		},
+	}
+	eventCtx := workflow.WithActivityOptions(ctx, eventOptions)
+
+	err := workflow.ExecuteActivity(eventCtx, "PublishOrderEvent", event).Get(ctx, nil)
+	if err != nil {
+		logger.Warn("Failed to publish order event", "order_id", order.ID, "type", eventType, "error", err)
+	}
+}
@@ -0,0 +1,103 @@
+package workflows
+
+import (
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/workflow"
+)
+
+const (
+	FailedOrderWorkflowName = "FailedOrderWorkflow"
+
+	// deadLetterRetention is how long a failed order waits for a retry
+	// signal before the dead-letter entry expires
+	deadLetterRetention = 30 * 24 * time.Hour
+)
+
+// FailedOrderWorkflow holds an order that failed after exhausting its retries.
+// It records the failure, alerts ops, and waits for a retry signal to re-drive
+// the order through OrderWorkflow under its original workflow ID. An order
+// that failed after payment is re-driven with that payment, so it is not
+// charged again.
+func FailedOrderWorkflow(ctx workflow.Context, failure models.OrderFailure) (*models.DeadLetterStatus, error) {
+	logger := workflow.GetLogger(ctx)
+	logger.Info("Failed order workflow started", "order_id", failure.Order.ID, "stage", failure.Stage)
+
+	state := &models.DeadLetterStatus{
+		Failure:     failure,
+		Status:      models.DeadLetterAwaitingRetry,
+		LastUpdated: workflow.Now(ctx),
+	}
+
+	err := workflow.SetQueryHandler(ctx, "getStatus", func() (*models.DeadLetterStatus, error) {
+		return state, nil
+	})
+	if err != nil {
+		logger.Error("Failed to register query handler", "error", err)
+		return nil, err
+	}
+
+	activityOptions := workflow.ActivityOptions{
+		StartToCloseTimeout:    10 * time.Second,
+		ScheduleToStartTimeout: 5 * time.Second,
+		RetryPolicy: &RetryPolicy{
+			InitialInterval:    time.Second,
+			BackoffCoefficient: 2.0,
+			MaximumInterval:    time.Minute,
+			MaximumAttempts:    10,
+		},
+	}
+	actCtx := workflow.WithActivityOptions(ctx, activityOptions)
+
+	// Recording and alerting are best-effort: losing either must not lose the
+	// dead-letter entry itself, which lives on as this workflow
+	if err := workflow.ExecuteActivity(actCtx, "RecordOrderFailure", failure).Get(ctx, nil); err != nil {
+		logger.Warn("Failed to record order failure", "order_id", failure.Order.ID, "error", err)
+	}
+	if err := workflow.ExecuteActivity(actCtx, "NotifyOpsOfFailure", failure).Get(ctx, nil); err != nil {
+		logger.Warn("Failed to notify ops of order failure", "order_id", failure.Order.ID, "error", err)
+	}
+
+	var retryReq models.RetryRequest
+	retryChannel := workflow.GetSignalChannel(ctx, models.SignalRetry)
+	retried, _ := retryChannel.ReceiveWithTimeout(ctx, deadLetterRetention, &retryReq)
+	if !retried {
+		state.Status = models.DeadLetterExpired
+		state.LastUpdated = workflow.Now(ctx)
+		logger.Warn("Dead-lettered order expired without retry", "order_id", failure.Order.ID)
+		return state, nil
+	}
+
+	logger.Info("Retry signal received, re-driving order", "order_id", failure.Order.ID, "requested_by", retryReq.RequestedBy)
+
+	// Re-drive under the original workflow ID so existing status queries keep
+	// working. The new run is abandoned rather than owned by this workflow.
+	childOptions := workflow.ChildWorkflowOptions{
+		WorkflowID:        failure.WorkflowID,
+		ParentClosePolicy: enums.PARENT_CLOSE_POLICY_ABANDON,
+	}
+	childCtx := workflow.WithChildOptions(ctx, childOptions)
+
+	// An order that failed after payment keeps that payment rather than
+	// being charged again
+	order := failure.Order
+	order.Status = models.StatusPending
+	if failure.Paid() {
+		order.PriorPayment = &models.PaymentResponse{Success: true, TransactionID: failure.TransactionID, Tenders: failure.Tenders}
+	}
+	child := workflow.ExecuteChildWorkflow(childCtx, OrderWorkflow, order)
+
+	var execution workflow.Execution
+	if err := child.GetChildWorkflowExecution().Get(ctx, &execution); err != nil {
+		logger.Error("Failed to re-drive order", "order_id", failure.Order.ID, "error", err)
+		return nil, err
+	}
+
+	state.Status = models.DeadLetterRedriven
+	state.RetriedBy = retryReq.RequestedBy
+	state.LastUpdated = workflow.Now(ctx)
+	logger.Info("Order re-driven", "order_id", failure.Order.ID, "workflow_id", execution.ID, "run_id", execution.RunID)
+	return state, nil
+}
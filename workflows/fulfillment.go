@@ -0,0 +1,147 @@
+package workflows
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/workflow"
+)
+
+const (
+	// DefaultFulfillmentParallelism is the number of items fulfilled
+	// concurrently when the order does not set its own cap
+	DefaultFulfillmentParallelism = 5
+
+	// ItemChildWorkflowThreshold is the item count above which each item is
+	// fulfilled by its own ItemFulfillmentWorkflow instead of an activity
+	ItemChildWorkflowThreshold = 10
+)
+
+// fulfillItems runs FulfillItem for every order item, keeping at most the
+// order's parallelism cap in flight. Every item runs to completion even when
+// others fail so state.ItemResults reflects the whole order.
+func fulfillItems(ctx workflow.Context, order models.Order, state *models.OrderStatus) error {
+	errs := fanOutItems(ctx, order, state, func(i int) workflow.Future {
+		// Expedite signals received while earlier items run apply to later ones
+		return workflow.ExecuteActivity(ctx, "FulfillItem", order, order.Items[i], state.IsExpedited)
+	})
+	return aggregateItemErrors(order, errs)
+}
+
+// fulfillItemsWithChildren fans out one ItemFulfillmentWorkflow per item so
+// each item is retried and visible on its own. The order partially completes
+// when at least one item is fulfilled; it fails only if every item fails.
+func fulfillItemsWithChildren(ctx workflow.Context, order models.Order, state *models.OrderStatus) error {
+	errs := fanOutItems(ctx, order, state, func(i int) workflow.Future {
+		childOptions := workflow.ChildWorkflowOptions{
+			// Items may repeat within an order, so the index keeps IDs unique
+			WorkflowID:               fmt.Sprintf("fulfill-%s-%d", order.ID, i),
+			WorkflowExecutionTimeout: 5 * time.Minute,
+			ParentClosePolicy:        parentClosePolicy(ctx, models.ChildFulfillment, enums.PARENT_CLOSE_POLICY_UNSPECIFIED),
+		}
+		childCtx := workflow.WithChildOptions(ctx, childOptions)
+		req := models.ItemFulfillmentRequest{
+			OrderID:     order.ID,
+			Item:        order.Items[i],
+			IsExpedited: state.IsExpedited,
+		}
+		return workflow.ExecuteChildWorkflow(childCtx, ItemFulfillmentWorkflowName, req)
+	})
+
+	for _, err := range errs {
+		if err == nil {
+			return nil
+		}
+	}
+	return aggregateItemErrors(order, errs)
+}
+
+// fanOutItems starts one future per order item via start, keeping at most the
+// order's parallelism cap in flight, and records each outcome in
+// state.ItemResults. It returns the per-item errors, indexed like order.Items.
+func fanOutItems(ctx workflow.Context, order models.Order, state *models.OrderStatus, start func(i int) workflow.Future) []error {
+	logger := workflow.GetLogger(ctx)
+
+	parallelism := order.FulfillmentParallelism
+	if parallelism <= 0 {
+		parallelism = DefaultFulfillmentParallelism
+	}
+
+	state.ItemResults = make([]models.ItemFulfillment, len(order.Items))
+	for i, item := range order.Items {
+		state.ItemResults[i] = models.ItemFulfillment{Item: item, Status: models.ItemPending}
+	}
+
+	errs := make([]error, len(order.Items))
+	selector := workflow.NewSelector(ctx)
+	next, inFlight := 0, 0
+
+	for next < len(order.Items) || inFlight > 0 {
+		for next < len(order.Items) && inFlight < parallelism {
+			i := next
+			next++
+			inFlight++
+			selector.AddFuture(start(i), func(f workflow.Future) {
+				inFlight--
+				result := &state.ItemResults[i]
+				var child models.ItemFulfillment
+				if err := f.Get(ctx, &child); err != nil {
+					errs[i] = err
+					result.Status = models.ItemFailed
+					result.Error = err.Error()
+					logger.Warn("Item fulfillment failed", "order_id", order.ID, "item", result.Item, "error", err)
+				} else {
+					result.Status = models.ItemFulfilled
+					result.Step = child.Step
+				}
+				state.LastUpdated = workflow.Now(ctx)
+			})
+		}
+		selector.Select(ctx)
+	}
+
+	return errs
+}
+
+// countItems returns how many item results have the given status
+func countItems(results []models.ItemFulfillment, status string) int {
+	n := 0
+	for _, r := range results {
+		if r.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// aggregateItemErrors folds per-item failures into one error. When every
+// failure is an inventory shortage the result is a single out-of-stock
+// rejection listing all missing items; otherwise the first unexpected error
+// is returned so the order can be retried or dead-lettered.
+func aggregateItemErrors(order models.Order, errs []error) error {
+	var failed int
+	var missing []string
+	var unexpected error
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		failed++
+		if applicationErrorType(err) == models.ErrTypeInventoryOutOfStock {
+			missing = append(missing, order.Items[i])
+		} else if unexpected == nil {
+			unexpected = err
+		}
+	}
+
+	switch {
+	case failed == 0:
+		return nil
+	case unexpected != nil:
+		return fmt.Errorf("fulfillment failed for %d of %d items: %w", failed, len(order.Items), unexpected)
+	default:
+		return (&models.InventoryOutOfStockError{OrderID: order.ID, Items: missing}).ApplicationError()
+	}
+}
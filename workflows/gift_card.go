@@ -0,0 +1,106 @@
+package workflows
+
+import (
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/workflow"
+)
+
+type giftCardHoldsKey struct{}
+
+// giftCardHolds holds the gift card balance holds an order has open, until
+// they are captured or released
+type giftCardHolds struct {
+	holds []models.GiftCardHold
+}
+
+// withGiftCardHolds marks ctx with a record of the order's gift card holds
+func withGiftCardHolds(ctx workflow.Context) workflow.Context {
+	return workflow.WithValue(ctx, giftCardHoldsKey{}, &giftCardHolds{})
+}
+
+// orderGiftCardHolds returns the record ctx was marked with by
+// withGiftCardHolds, or nil
+func orderGiftCardHolds(ctx workflow.Context) *giftCardHolds {
+	holds, _ := ctx.Value(giftCardHoldsKey{}).(*giftCardHolds)
+	return holds
+}
+
+// holdGiftCards holds the balance of each of the order's gift card tenders.
+// If one is declined, the holds already placed are released.
+func holdGiftCards(ctx workflow.Context, order models.Order) error {
+	record := orderGiftCardHolds(ctx)
+	if record == nil {
+		return nil
+	}
+	for _, tender := range order.Tenders {
+		if !tender.HeldOnGiftCard() {
+			continue
+		}
+		var hold models.GiftCardHold
+		err := workflow.ExecuteActivity(ctx, "HoldGiftCardBalance", models.GiftCardHoldRequest{
+			OrderID: order.ID,
+			Card:    tender.Card,
+			Amount:  tender.Amount,
+		}).Get(ctx, &hold)
+		if err != nil {
+			releaseGiftCards(ctx, order)
+			return err
+		}
+		record.holds = append(record.holds, hold)
+	}
+	return nil
+}
+
+// withoutGiftCards returns the order as payment sees it: without the gift
+// card tenders that are held, and with their amounts taken off. The second
+// result reports whether anything is left to charge.
+func withoutGiftCards(ctx workflow.Context, order models.Order) (models.Order, bool) {
+	record := orderGiftCardHolds(ctx)
+	if record == nil || len(record.holds) == 0 {
+		return order, true
+	}
+	charged := order
+	charged.Tenders = nil
+	for _, tender := range order.Tenders {
+		if !tender.HeldOnGiftCard() {
+			charged.Tenders = append(charged.Tenders, tender)
+		}
+	}
+	for _, hold := range record.holds {
+		charged.Amount -= hold.Amount
+	}
+	return charged, len(charged.Tenders) > 0
+}
+
+// captureGiftCards spends the order's held gift card balances once it is
+// fulfilled. The order has shipped either way, so a failed capture is
+// logged for follow-up with its hold left open.
+func captureGiftCards(ctx workflow.Context, order models.Order) {
+	record := orderGiftCardHolds(ctx)
+	if record == nil {
+		return
+	}
+	for _, hold := range record.holds {
+		if err := workflow.ExecuteActivity(ctx, "CaptureGiftCard", hold).Get(ctx, nil); err != nil {
+			workflow.GetLogger(ctx).Error("Failed to capture gift card", "order_id", order.ID, "hold_id", hold.HoldID, "amount", hold.Amount, "error", err)
+		}
+	}
+	record.holds = nil
+}
+
+// releaseGiftCards releases the order's open gift card holds. It runs on a
+// disconnected context so a cancelled order still releases them, and a
+// failed release is logged rather than failing the order.
+func releaseGiftCards(ctx workflow.Context, order models.Order) {
+	record := orderGiftCardHolds(ctx)
+	if record == nil {
+		return
+	}
+	ctx, _ = workflow.NewDisconnectedContext(ctx)
+	for _, hold := range record.holds {
+		if err := workflow.ExecuteActivity(ctx, "ReleaseGiftCard", hold).Get(ctx, nil); err != nil {
+			workflow.GetLogger(ctx).Error("Failed to release gift card hold", "order_id", order.ID, "hold_id", hold.HoldID, "amount", hold.Amount, "error", err)
+		}
+	}
+	record.holds = nil
+}
@@ -0,0 +1,175 @@
+package workflows
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/workflow"
+)
+
+// InstallmentPaymentWorkflowName is the registered name of InstallmentPaymentWorkflow
+const InstallmentPaymentWorkflowName = "InstallmentPaymentWorkflow"
+
+// Schedule of the installment plans that orders start
+const (
+	InstallmentInterval        = 30 * 24 * time.Hour
+	InstallmentDunningRetries  = 3
+	InstallmentDunningInterval = 24 * time.Hour
+)
+
+// InstallmentPaymentWorkflow charges an order amount in scheduled
+// installments. Durable timers space them out, so a plan outlives worker
+// restarts over the months it runs. A failed installment is retried by
+// dunning; one still unpaid after the last retry defaults the plan, which
+// signals its parent order, if any, and fails. A plan cancelled before it
+// completes refunds the installments already paid.
+func InstallmentPaymentWorkflow(ctx workflow.Context, plan models.InstallmentPlan) (*models.InstallmentPlanResult, error) {
+	logger := workflow.GetLogger(ctx)
+	logger.Info("Installment plan started", "order_id", plan.OrderID, "installments", plan.Installments, "interval", plan.Interval)
+
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout:    10 * time.Second,
+		ScheduleToStartTimeout: 5 * time.Second,
+		RetryPolicy: &RetryPolicy{
+			InitialInterval:    time.Second,
+			BackoffCoefficient: 2.0,
+			MaximumInterval:    10 * time.Second,
+			MaximumAttempts:    3,
+		},
+	})
+
+	result := &models.InstallmentPlanResult{OrderID: plan.OrderID}
+	for i, amount := range plan.Amounts() {
+		number := i + 1
+		if i > 0 {
+			if err := workflow.Sleep(ctx, plan.Interval); err != nil {
+				return nil, refundInstallments(ctx, plan.OrderID, result.Payments, err)
+			}
+		}
+
+		payment, err := chargeInstallment(ctx, plan, number, amount)
+		if ctx.Err() != nil {
+			return nil, refundInstallments(ctx, plan.OrderID, result.Payments, ctx.Err())
+		}
+		if err != nil {
+			return nil, defaultInstallmentPlan(ctx, &models.InstallmentPlanDefaultedError{
+				OrderID:     plan.OrderID,
+				Installment: number,
+				Amount:      amount,
+				Attempts:    payment.Attempts,
+				Reason:      err.Error(),
+			})
+		}
+		logger.Info("Installment paid", "order_id", plan.OrderID, "installment", number, "amount", amount, "transaction_id", payment.TransactionID)
+		result.Payments = append(result.Payments, payment)
+	}
+
+	logger.Info("Installment plan completed", "order_id", plan.OrderID, "installments", len(result.Payments))
+	return result, nil
+}
+
+// chargeInstallment charges one installment, retrying a failed charge every
+// dunning interval until it is paid or the dunning retries run out. The
+// returned payment counts the attempts either way.
+func chargeInstallment(ctx workflow.Context, plan models.InstallmentPlan, number int, amount float64) (models.InstallmentPayment, error) {
+	payment := models.InstallmentPayment{Number: number, Amount: amount}
+	paymentReq := models.PaymentRequest{OrderID: plan.OrderID, Amount: amount, PaymentMethod: plan.PaymentMethod}
+	for {
+		payment.Attempts++
+		var paymentResp models.PaymentResponse
+		err := workflow.ExecuteActivity(ctx, "ProcessPayment", paymentReq).Get(ctx, &paymentResp)
+		if err == nil {
+			payment.TransactionID = paymentResp.TransactionID
+			payment.PaidAt = workflow.Now(ctx)
+			return payment, nil
+		}
+		if ctx.Err() != nil || payment.Attempts > plan.DunningRetries {
+			return payment, err
+		}
+		workflow.GetLogger(ctx).Warn("Installment failed, retrying after dunning interval", "order_id", plan.OrderID, "installment", number, "attempt", payment.Attempts, "retry_in", plan.DunningInterval, "error", err)
+		if err := workflow.Sleep(ctx, plan.DunningInterval); err != nil {
+			return payment, err
+		}
+	}
+}
+
+// defaultInstallmentPlan tells the parent order, if the plan has one, that
+// the plan defaulted, and returns the error the plan fails with
+func defaultInstallmentPlan(ctx workflow.Context, defaulted *models.InstallmentPlanDefaultedError) error {
+	logger := workflow.GetLogger(ctx)
+	logger.Error("Installment plan defaulted", "order_id", defaulted.OrderID, "installment", defaulted.Installment, "attempts", defaulted.Attempts, "reason", defaulted.Reason)
+	if parent := workflow.GetInfo(ctx).ParentWorkflowExecution; parent != nil {
+		err := workflow.SignalExternalWorkflow(ctx, parent.ID, parent.RunID, models.SignalInstallmentDefault, *defaulted).Get(ctx, nil)
+		if err != nil {
+			logger.Error("Failed to signal installment default to order", "order_id", defaulted.OrderID, "workflow_id", parent.ID, "error", err)
+		}
+	}
+	return defaulted.ApplicationError()
+}
+
+// refundInstallments refunds the installments paid so far, latest first, on
+// a disconnected context since the plan was cancelled. It returns the first
+// refund failure, or else cause.
+func refundInstallments(ctx workflow.Context, orderID string, payments []models.InstallmentPayment, cause error) error {
+	ctx, _ = workflow.NewDisconnectedContext(ctx)
+	for i := len(payments) - 1; i >= 0; i-- {
+		refund := models.RefundRequest{OrderID: orderID, TransactionID: payments[i].TransactionID, Amount: payments[i].Amount}
+		if err := workflow.ExecuteActivity(ctx, "RefundPayment", refund).Get(ctx, nil); err != nil {
+			workflow.GetLogger(ctx).Error("Failed to refund installment", "order_id", orderID, "installment", payments[i].Number, "transaction_id", payments[i].TransactionID, "error", err)
+			cause = err
+		}
+	}
+	return cause
+}
+
+// startInstallmentPlan starts the order's installment plan on the payment
+// task queue and waits for it to start, not to finish. Unless configured
+// otherwise, an order that closes early asks the plan to cancel, and so to
+// refund what it collected.
+func startInstallmentPlan(ctx workflow.Context, order models.Order) (workflow.ChildWorkflowFuture, error) {
+	childCtx := workflow.WithChildOptions(ctx, workflow.ChildWorkflowOptions{
+		WorkflowID:        fmt.Sprintf("installments-%s", order.ID),
+		TaskQueue:         PaymentTaskQueue,
+		ParentClosePolicy: parentClosePolicy(ctx, models.ChildPayment, enums.PARENT_CLOSE_POLICY_REQUEST_CANCEL),
+	})
+	plan := workflow.ExecuteChildWorkflow(childCtx, InstallmentPaymentWorkflowName, models.InstallmentPlan{
+		OrderID:         order.ID,
+		Amount:          order.Amount,
+		Installments:    order.Installments,
+		Interval:        InstallmentInterval,
+		DunningRetries:  InstallmentDunningRetries,
+		DunningInterval: InstallmentDunningInterval,
+		PaymentMethod:   order.PaymentMethod,
+	})
+	if err := plan.GetChildWorkflowExecution().Get(ctx, nil); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// awaitInstallmentPlan waits for the order's installment plan to settle,
+// recording the outcome in state.PaymentStatus, and returns the transaction
+// IDs of the installments joined
+func awaitInstallmentPlan(ctx workflow.Context, order models.Order, state *models.OrderStatus, plan workflow.ChildWorkflowFuture) string {
+	var result models.InstallmentPlanResult
+	if err := plan.Get(ctx, &result); err != nil {
+		workflow.GetLogger(ctx).Warn("Installment plan did not complete", "order_id", order.ID, "error", err)
+		if applicationErrorType(err) == models.ErrTypeInstallmentPlanDefaulted {
+			state.PaymentStatus = "defaulted"
+		} else if state.PaymentStatus != "defaulted" {
+			state.PaymentStatus = "failed"
+		}
+		state.LastUpdated = workflow.Now(ctx)
+		return ""
+	}
+	state.PaymentStatus = "completed"
+	state.LastUpdated = workflow.Now(ctx)
+	transactionIDs := make([]string, len(result.Payments))
+	for i, payment := range result.Payments {
+		transactionIDs[i] = payment.TransactionID
+	}
+	return strings.Join(transactionIDs, ",")
+}
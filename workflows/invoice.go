@@ -0,0 +1,66 @@
+package workflows
+
+import (
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/workflow"
+)
+
+// maxInvoiceSessionAttempts bounds how many sessions are tried when the host
+// holding a generated invoice is lost before the upload
+const maxInvoiceSessionAttempts = 3
+
+// invoiceSessionOptions pins invoice generation and upload to one worker host
+var invoiceSessionOptions = &workflow.SessionOptions{
+	CreationTimeout:  time.Minute,
+	ExecutionTimeout: 5 * time.Minute,
+	HeartbeatTimeout: 20 * time.Second,
+}
+
+// generateInvoice renders and uploads the order invoice in a worker session.
+// GenerateInvoice writes a host-local file that UploadInvoice reads, so both
+// must run on the same host; when the session's host fails the file is gone
+// and the pair is retried in a new session.
+func generateInvoice(ctx workflow.Context, req models.InvoiceRequest) (string, error) {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: 30 * time.Second,
+		RetryPolicy: &RetryPolicy{
+			InitialInterval:    time.Second,
+			BackoffCoefficient: 2.0,
+			MaximumInterval:    10 * time.Second,
+			MaximumAttempts:    3,
+		},
+	})
+
+	var err error
+	for attempt := 1; attempt <= maxInvoiceSessionAttempts; attempt++ {
+		var url string
+		url, err = generateInvoiceInSession(ctx, req)
+		if err == nil {
+			return url, nil
+		}
+		workflow.GetLogger(ctx).Warn("Invoice session failed", "order_id", req.OrderID, "attempt", attempt, "error", err)
+	}
+	return "", err
+}
+
+// generateInvoiceInSession makes one attempt at generating and uploading the
+// invoice on a single host
+func generateInvoiceInSession(ctx workflow.Context, req models.InvoiceRequest) (string, error) {
+	sessionCtx, err := workflow.CreateSession(ctx, invoiceSessionOptions)
+	if err != nil {
+		return "", err
+	}
+	defer workflow.CompleteSession(sessionCtx)
+
+	var path string
+	if err := workflow.ExecuteActivity(sessionCtx, "GenerateInvoice", req).Get(sessionCtx, &path); err != nil {
+		return "", err
+	}
+	var url string
+	if err := workflow.ExecuteActivity(sessionCtx, "UploadInvoice", req.OrderID, path).Get(sessionCtx, &url); err != nil {
+		return "", err
+	}
+	return url, nil
+}
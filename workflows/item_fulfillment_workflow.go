@@ -0,0 +1,76 @@
+package workflows
+
+import (
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/workflow"
+)
+
+const ItemFulfillmentWorkflowName = "ItemFulfillmentWorkflow"
+
+// ItemFulfillmentWorkflow is a child workflow that reserves, picks, and packs
+// a single item of a large order. Running each item as its own workflow gives
+// it independent retries and its own history in the Temporal UI.
+func ItemFulfillmentWorkflow(ctx workflow.Context, req models.ItemFulfillmentRequest) (*models.ItemFulfillment, error) {
+	logger := workflow.GetLogger(ctx)
+	logger.Info("Item fulfillment workflow started", "order_id", req.OrderID, "item", req.Item)
+
+	state := &models.ItemFulfillment{
+		Item:   req.Item,
+		Status: models.ItemPending,
+	}
+
+	err := workflow.SetQueryHandler(ctx, "getStatus", func() (*models.ItemFulfillment, error) {
+		return state, nil
+	})
+	if err != nil {
+		logger.Error("Failed to register query handler", "error", err)
+		return nil, err
+	}
+
+	ctx = workflow.WithActivityOptions(ctx, itemStepOptions)
+	if err := runItemSteps(ctx, req, state); err != nil {
+		return nil, err
+	}
+	logger.Info("Item fulfillment workflow completed", "order_id", req.OrderID, "item", req.Item)
+	return state, nil
+}
+
+// itemStepOptions are the activity options of the reserve, pick, and pack
+// steps of an item
+var itemStepOptions = workflow.ActivityOptions{
+	StartToCloseTimeout:    30 * time.Second,
+	ScheduleToStartTimeout: 5 * time.Second,
+	HeartbeatTimeout:       5 * time.Second,
+	RetryPolicy: &RetryPolicy{
+		InitialInterval:    time.Second,
+		BackoffCoefficient: 2.0,
+		MaximumInterval:    10 * time.Second,
+		MaximumAttempts:    5,
+	},
+}
+
+// runItemSteps reserves, picks, and packs an item, recording its progress
+// in state, and returns the error of the step that failed, if any
+func runItemSteps(ctx workflow.Context, req models.ItemFulfillmentRequest, state *models.ItemFulfillment) error {
+	steps := []struct {
+		activity string
+		done     string
+	}{
+		{"ReserveItem", models.ItemStepReserved},
+		{"PickItem", models.ItemStepPicked},
+		{"PackItem", models.ItemStepPacked},
+	}
+	for _, step := range steps {
+		if err := workflow.ExecuteActivity(ctx, step.activity, req).Get(ctx, nil); err != nil {
+			state.Status = models.ItemFailed
+			state.Error = err.Error()
+			workflow.GetLogger(ctx).Error("Item fulfillment failed", "order_id", req.OrderID, "item", req.Item, "step", step.activity, "error", err)
+			return err
+		}
+		state.Step = step.done
+	}
+	state.Status = models.ItemFulfilled
+	return nil
+}
@@ -0,0 +1,90 @@
+package workflows
+
+import (
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/workflow"
+)
+
+type loyaltyKey struct{}
+
+// loyaltyLedger holds the loyalty transactions an order has made, so that
+// they can be reversed if the order is cancelled or refunded
+type loyaltyLedger struct {
+	customerID   string
+	transactions []models.LoyaltyTransaction
+}
+
+// withLoyalty marks ctx with a ledger for the loyalty transactions of the
+// customer's order
+func withLoyalty(ctx workflow.Context, customerID string) workflow.Context {
+	return workflow.WithValue(ctx, loyaltyKey{}, &loyaltyLedger{customerID: customerID})
+}
+
+// orderLoyalty returns the ledger ctx was marked with by withLoyalty, or nil
+func orderLoyalty(ctx workflow.Context) *loyaltyLedger {
+	ledger, _ := ctx.Value(loyaltyKey{}).(*loyaltyLedger)
+	return ledger
+}
+
+// redeemLoyaltyPoints spends the points the order redeems, if any, from the
+// customer's balance. An order without a customer has no balance to spend,
+// and RedeemLoyaltyPoints rejects it.
+func redeemLoyaltyPoints(ctx workflow.Context, order models.Order) error {
+	ledger := orderLoyalty(ctx)
+	if ledger == nil || order.RedeemPoints <= 0 {
+		return nil
+	}
+	var txn *models.LoyaltyTransaction
+	err := workflow.ExecuteActivity(ctx, "RedeemLoyaltyPoints", models.LoyaltyRequest{
+		OrderID:    order.ID,
+		CustomerID: ledger.customerID,
+		Points:     order.RedeemPoints,
+	}).Get(ctx, &txn)
+	if err != nil {
+		return err
+	}
+	ledger.transactions = append(ledger.transactions, *txn)
+	return nil
+}
+
+// awardLoyaltyPoints credits the customer, if the order has one, with the
+// points the paid order earns. The payment stands either way, so a failed
+// award is only logged.
+func awardLoyaltyPoints(ctx workflow.Context, order models.Order) {
+	ledger := orderLoyalty(ctx)
+	if ledger == nil || ledger.customerID == "" {
+		return
+	}
+	var txn *models.LoyaltyTransaction
+	err := workflow.ExecuteActivity(ctx, "AwardLoyaltyPoints", models.LoyaltyRequest{
+		OrderID:    order.ID,
+		CustomerID: ledger.customerID,
+		Amount:     order.Amount,
+	}).Get(ctx, &txn)
+	if err != nil {
+		workflow.GetLogger(ctx).Warn("Failed to award loyalty points", "order_id", order.ID, "customer_id", ledger.customerID, "error", err)
+		return
+	}
+	if txn != nil {
+		ledger.transactions = append(ledger.transactions, *txn)
+	}
+}
+
+// reverseLoyaltyPoints reverses the order's loyalty transactions, latest
+// first: awarded points are taken back and redeemed points returned. It runs
+// on a disconnected context so a cancelled order is still compensated, and a
+// failed reversal is logged for follow-up rather than failing the order.
+func reverseLoyaltyPoints(ctx workflow.Context, order models.Order) {
+	ledger := orderLoyalty(ctx)
+	if ledger == nil {
+		return
+	}
+	ctx, _ = workflow.NewDisconnectedContext(ctx)
+	for len(ledger.transactions) > 0 {
+		txn := ledger.transactions[len(ledger.transactions)-1]
+		ledger.transactions = ledger.transactions[:len(ledger.transactions)-1]
+		if err := workflow.ExecuteActivity(ctx, "ReverseLoyaltyPoints", txn).Get(ctx, nil); err != nil {
+			workflow.GetLogger(ctx).Error("Failed to reverse loyalty points", "order_id", order.ID, "transaction_id", txn.TransactionID, "points", txn.Points, "error", err)
+		}
+	}
+}
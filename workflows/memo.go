@@ -0,0 +1,57 @@
+package workflows
+
+import (
+	"slices"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/workflow"
+)
+
+// orderMemo reads the business context the order was started with from the
+// workflow memo. The memo is part of the start event, so replays read the
+// same values. Entries that are missing or not strings are left empty.
+func orderMemo(ctx workflow.Context) models.OrderMemo {
+	var memo models.OrderMemo
+	info := workflow.GetInfo(ctx)
+	if info.Memo == nil {
+		return memo
+	}
+	// Memos are written with the default converter, not the payload codecs
+	dc := converter.GetDefaultDataConverter()
+	entries := []struct {
+		key   string
+		value *string
+	}{
+		{models.MemoCustomerID, &memo.CustomerID},
+		{models.MemoChannel, &memo.Channel},
+		{models.MemoRegion, &memo.Region},
+	}
+	for _, entry := range entries {
+		payload, ok := info.Memo.GetFields()[entry.key]
+		if !ok {
+			continue
+		}
+		if err := dc.FromPayload(payload, entry.value); err != nil {
+			workflow.GetLogger(ctx).Warn("Ignoring invalid memo entry", "key", entry.key, "error", err)
+			*entry.value = ""
+		}
+	}
+	return memo
+}
+
+// orderCustomerID returns the ID of the customer who placed the order: the
+// order's own customer when it carries one, or else the memo's. Both are part
+// of the start event, so replays read the same ID.
+func orderCustomerID(ctx workflow.Context, order models.Order) string {
+	if order.Customer != nil && order.Customer.ID != "" {
+		return order.Customer.ID
+	}
+	return orderMemo(ctx).CustomerID
+}
+
+// expeditedByChannel reports whether the order's sales channel is one whose
+// orders are expedited from the start
+func expeditedByChannel(config models.DynamicConfig, memo models.OrderMemo) bool {
+	return memo.Channel != "" && slices.Contains(config.ExpeditedChannels, memo.Channel)
+}
@@ -0,0 +1,54 @@
+package workflows
+
+import (
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/workflow"
+)
+
+// Custom workflow metrics, exported alongside the SDK's built-in metrics
+const (
+	// OrdersTerminalMetric counts orders reaching a terminal status,
+	// tagged with StatusTag
+	OrdersTerminalMetric = "orders_terminal"
+	StatusTag            = "status"
+
+	// OrdersSLABreachedMetric counts orders outliving their processing SLA,
+	// tagged with the StageTag they were in at the time
+	OrdersSLABreachedMetric = "orders_sla_breached"
+	StageTag                = "stage"
+
+	// OrdersPromiseMissedMetric counts orders not delivered by their
+	// promised delivery, tagged with their ShippingMethodTag
+	OrdersPromiseMissedMetric = "orders_delivery_promise_missed"
+	ShippingMethodTag         = "shipping_method"
+)
+
+// recordTerminalStatus counts an order reaching its terminal status. The
+// workflow metrics handler drops emissions during replay, so each order is
+// counted once no matter how often its history is replayed. A cancelled
+// workflow is counted once compensateCancellation has run.
+func recordTerminalStatus(ctx workflow.Context, status models.OrderStatusCode) {
+	if compensatingCancellation(ctx) || !status.IsTerminal() {
+		return
+	}
+	workflow.GetMetricsHandler(ctx).
+		WithTags(map[string]string{StatusTag: string(status)}).
+		Counter(OrdersTerminalMetric).
+		Inc(1)
+}
+
+// recordSLABreach counts an order breaching its processing SLA
+func recordSLABreach(ctx workflow.Context, stage models.OrderStage) {
+	workflow.GetMetricsHandler(ctx).
+		WithTags(map[string]string{StageTag: string(stage)}).
+		Counter(OrdersSLABreachedMetric).
+		Inc(1)
+}
+
+// recordPromiseMissed counts an order missing its promised delivery
+func recordPromiseMissed(ctx workflow.Context, method string) {
+	workflow.GetMetricsHandler(ctx).
+		WithTags(map[string]string{ShippingMethodTag: method}).
+		Counter(OrdersPromiseMissedMetric).
+		Inc(1)
+}
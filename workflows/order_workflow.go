@@ -1,10 +1,13 @@
 package workflows
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/aswathylr-builds/temporal-order-processing/featureflags"
 	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/api/enums/v1"
 	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/workflow"
 )
@@ -15,13 +18,48 @@ type RetryPolicy = temporal.RetryPolicy
 const (
 	OrderWorkflowName   = "OrderProcessingWorkflow"
 	PaymentWorkflowName = "PaymentWorkflow"
+
+	// PaymentTaskQueue is served by the payment worker, separately from
+	// order processing and fulfillment
+	PaymentTaskQueue = "payment-processing-queue"
 )
 
-// OrderWorkflow is the main workflow for processing orders
-func OrderWorkflow(ctx workflow.Context, order models.Order) error {
+// SignalSenders maps the signals an order's child workflows send it to the
+// child's workflow type, so signal authorization accepts each only from that
+// child. The worker and the replayer both check it.
+var SignalSenders = map[string]string{
+	models.SignalShipmentUpdate:     TrackingWorkflowName,
+	models.SignalVendorUpdate:       VendorFulfillmentWorkflowName,
+	models.SignalInstallmentDefault: InstallmentPaymentWorkflowName,
+}
+
+// OrderWorkflow is the main workflow for processing orders. An order that
+// closes without error, completed or cancelled, returns its OrderResult.
+func OrderWorkflow(ctx workflow.Context, order models.Order) (result *models.OrderResult, err error) {
 	logger := workflow.GetLogger(ctx)
 	logger.Info("Order workflow started", "order_id", order.ID)
 
+	// Orders written in an older schema are upgraded to the current one, so
+	// the rest of the workflow reads one shape (v1)
+	if workflow.GetVersion(ctx, "order-schema", workflow.DefaultVersion, 1) != workflow.DefaultVersion {
+		upgraded, err := order.Upgrade()
+		if err != nil {
+			logger.Error("Order schema not supported", "order_id", order.ID, "schema_version", order.SchemaVersion, "error", err)
+			return nil, (&models.OrderSchemaError{OrderID: order.ID, Reason: err.Error()}).ApplicationError()
+		}
+		order = upgraded
+	}
+
+	// Orders missing what every order needs fail before any step runs, with
+	// every field at fault in the error details (v1)
+	if workflow.GetVersion(ctx, "order-validation", workflow.DefaultVersion, 1) != workflow.DefaultVersion {
+		var invalid *models.OrderInvalidError
+		if err := order.Validate(); errors.As(err, &invalid) {
+			logger.Error("Order invalid", "order_id", order.ID, "error", err)
+			return nil, invalid.ApplicationError()
+		}
+	}
+
 	// Initialize workflow state
 	state := &models.OrderStatus{
 		OrderID:       order.ID,
@@ -32,6 +70,21 @@ func OrderWorkflow(ctx workflow.Context, order models.Order) error {
 		LastUpdated:   workflow.Now(ctx),
 	}
 
+	// A failed order fails with an OrderError, so callers can branch on its
+	// code; errors that carry none are described with the stage they hit (v1).
+	// A cancelled workflow stays cancelled.
+	if workflow.GetVersion(ctx, "order-error", workflow.DefaultVersion, 1) != workflow.DefaultVersion {
+		defer func() {
+			var orderErr models.OrderError
+			if err == nil || temporal.IsCanceledError(err) || models.AsOrderError(err, &orderErr) {
+				return
+			}
+			orderErr = *models.OrderErrorFrom(err)
+			orderErr.Stage = state.Stage
+			err = orderErr.ApplicationError(err)
+		}()
+	}
+
 	// Set up signal and query handlers
 	cancelRequested := false
 
@@ -45,6 +98,9 @@ func OrderWorkflow(ctx workflow.Context, order models.Order) error {
 		}
 	})
 
+	// Set once the search attributes version and flag are checked below
+	searchAttributes := false
+
 	// Signal handler for expedited processing
 	expediteChannel := workflow.GetSignalChannel(ctx, models.SignalExpedite)
 	workflow.Go(ctx, func(ctx workflow.Context) {
@@ -53,24 +109,174 @@ func OrderWorkflow(ctx workflow.Context, order models.Order) error {
 			logger.Info("Expedite signal received", "order_id", order.ID)
 			state.IsExpedited = true
 			state.LastUpdated = workflow.Now(ctx)
+			if searchAttributes {
+				upsertOrderSearchAttributes(ctx, state)
+			}
+		}
+	})
+
+	// Signal handler for approving high-value orders
+	approved := false
+	approveChannel := workflow.GetSignalChannel(ctx, models.SignalApprove)
+	workflow.Go(ctx, func(ctx workflow.Context) {
+		for {
+			approveChannel.Receive(ctx, nil)
+			logger.Info("Approve signal received", "order_id", order.ID)
+			approved = true
+		}
+	})
+
+	// Signal handler for the customer's answer to step-up verification
+	var stepUp *models.StepUpResult
+	verifyChannel := workflow.GetSignalChannel(ctx, models.SignalVerify)
+	workflow.Go(ctx, func(ctx workflow.Context) {
+		for {
+			var result models.StepUpResult
+			verifyChannel.Receive(ctx, &result)
+			logger.Info("Verify signal received", "order_id", order.ID, "verified", result.Verified)
+			stepUp = &result
+		}
+	})
+
+	// Signal handler for restocking backordered items
+	restocked := false
+	restockChannel := workflow.GetSignalChannel(ctx, models.SignalRestock)
+	workflow.Go(ctx, func(ctx workflow.Context) {
+		for {
+			restockChannel.Receive(ctx, nil)
+			logger.Info("Restock signal received", "order_id", order.ID)
+			restocked = true
+		}
+	})
+
+	// Signal handler for order updates, honored while the edit window is open
+	window := &editWindow{}
+	updateChannel := workflow.GetSignalChannel(ctx, models.SignalUpdate)
+	workflow.Go(ctx, func(ctx workflow.Context) {
+		for {
+			var update models.OrderUpdate
+			updateChannel.Receive(ctx, &update)
+			if !window.open() {
+				logger.Warn("Update signal ignored outside the edit window", "order_id", order.ID)
+				continue
+			}
+			logger.Info("Update signal received", "order_id", order.ID, "items", update.Items)
+			window.updates = append(window.updates, update)
+		}
+	})
+
+	// Signal handler for the final status of a tracked shipment; an order
+	// shipped from several warehouses aggregates the status of its shipments
+	shipmentChannel := workflow.GetSignalChannel(ctx, models.SignalShipmentUpdate)
+	workflow.Go(ctx, func(ctx workflow.Context) {
+		for {
+			var update models.TrackingUpdate
+			shipmentChannel.Receive(ctx, &update)
+			if update.Status == models.ShipmentException {
+				logger.Warn("Shipment exception", "order_id", order.ID, "shipment_id", update.ShipmentID, "detail", update.Detail)
+			} else {
+				logger.Info("Shipment update received", "order_id", order.ID, "shipment_id", update.ShipmentID, "status", update.Status)
+			}
+			state.ShipmentStatus = update.Status
+			if len(state.Shipments) > 0 {
+				for i := range state.Shipments {
+					if state.Shipments[i].ShipmentID == update.ShipmentID {
+						state.Shipments[i].TrackingStatus = update.Status
+					}
+				}
+				state.ShipmentStatus = models.AggregateShipmentStatus(state.Shipments)
+			}
+			state.LastUpdated = workflow.Now(ctx)
+		}
+	})
+
+	// Signal handler for the status of a purchase order to a dropship vendor
+	vendorChannel := workflow.GetSignalChannel(ctx, models.SignalVendorUpdate)
+	workflow.Go(ctx, func(ctx workflow.Context) {
+		for {
+			var update models.VendorFulfillment
+			vendorChannel.Receive(ctx, &update)
+			logger.Info("Purchase order update received", "order_id", order.ID, "purchase_order", update.PurchaseOrder, "status", update.Status)
+			for i := range state.Vendors {
+				if state.Vendors[i].PurchaseOrder == update.PurchaseOrder {
+					state.Vendors[i] = update
+				}
+			}
+			state.LastUpdated = workflow.Now(ctx)
+		}
+	})
+
+	// Signal handler for a defaulted installment plan
+	installmentDefaultChannel := workflow.GetSignalChannel(ctx, models.SignalInstallmentDefault)
+	workflow.Go(ctx, func(ctx workflow.Context) {
+		for {
+			var defaulted models.InstallmentPlanDefaultedError
+			installmentDefaultChannel.Receive(ctx, &defaulted)
+			logger.Warn("Installment plan defaulted", "order_id", order.ID, "installment", defaulted.Installment, "reason", defaulted.Reason)
+			state.PaymentStatus = "defaulted"
+			state.LastUpdated = workflow.Now(ctx)
 		}
 	})
 
 	// Query handler for workflow status
-	err := workflow.SetQueryHandler(ctx, "getStatus", func() (*models.OrderStatus, error) {
+	err = workflow.SetQueryHandler(ctx, "getStatus", func() (*models.OrderStatus, error) {
 		return state, nil
 	})
 	if err != nil {
 		logger.Error("Failed to register query handler", "error", err)
-		return err
+		return nil, err
+	}
+
+	// Query handler for the status history. Changes are observed by awaiting
+	// them, which adds no commands, so replays of older executions are unaffected.
+	history := []models.StatusChange{{Status: state.Status, Stage: state.Stage, At: state.LastUpdated}}
+	workflow.Go(ctx, func(ctx workflow.Context) {
+		for {
+			last := history[len(history)-1]
+			if err := workflow.Await(ctx, func() bool {
+				return state.Status != last.Status || state.Stage != last.Stage
+			}); err != nil {
+				return
+			}
+			history = append(history, models.StatusChange{Status: state.Status, Stage: state.Stage, At: workflow.Now(ctx)})
+		}
+	})
+	err = workflow.SetQueryHandler(ctx, "getHistory", func() ([]models.StatusChange, error) {
+		return history, nil
+	})
+	if err != nil {
+		logger.Error("Failed to register query handler", "error", err)
+		return nil, err
+	}
+
+	// Query handler for the time left to update or cancel a paid order
+	err = workflow.SetQueryHandler(ctx, "getEditWindow", func() (models.EditWindow, error) {
+		return window.status(workflow.Now(ctx)), nil
+	})
+	if err != nil {
+		logger.Error("Failed to register query handler", "error", err)
+		return nil, err
+	}
+
+	// The workflow returns an OrderResult rather than only an error (v1);
+	// earlier executions complete with no result
+	var transactionID string
+	var paidTenders []models.TenderPayment
+	if workflow.GetVersion(ctx, "order-result", workflow.DefaultVersion, 1) != workflow.DefaultVersion {
+		defer func() {
+			if err == nil {
+				result = orderResult(ctx, state, transactionID)
+			}
+		}()
 	}
 
 	// Check for cancellation
 	if cancelRequested {
-		state.Status = models.StatusCancelled
+		setOrderStatus(ctx, state, models.StatusCancelled)
 		state.LastUpdated = workflow.Now(ctx)
 		logger.Info("Order cancelled", "order_id", order.ID)
-		return nil
+		recordTerminalStatus(ctx, state.Status)
+		return nil, nil
 	}
 
 	// Configure activity options with retry policy (increased timeout for demo)
@@ -86,121 +292,671 @@ func OrderWorkflow(ctx workflow.Context, order models.Order) error {
 	}
 	ctx = workflow.WithActivityOptions(ctx, activityOptions)
 
+	// Lifecycle events were added after the first release; executions started
+	// before that keep the original command sequence on replay
+	eventsVersion := workflow.GetVersion(ctx, "order-lifecycle-events", workflow.DefaultVersion, 1)
+	eventsEnabled := eventsVersion != workflow.DefaultVersion
+
+	// Order store mirroring was added after lifecycle events
+	persistVersion := workflow.GetVersion(ctx, "order-persistence", workflow.DefaultVersion, 1)
+	persistEnabled := persistVersion != workflow.DefaultVersion
+
+	// Orders that fail after exhausting retries are dead-lettered (v1)
+	deadLetterVersion := workflow.GetVersion(ctx, "dead-letter-routing", workflow.DefaultVersion, 1)
+	deadLetterEnabled := deadLetterVersion != workflow.DefaultVersion
+
+	// Business rejections are typed, non-retryable errors and are not dead-lettered (v1)
+	typedErrorsVersion := workflow.GetVersion(ctx, "typed-order-errors", workflow.DefaultVersion, 1)
+	typedErrorsEnabled := typedErrorsVersion != workflow.DefaultVersion
+
+	// Fraud checks, the payment path, and notification channels are gated by
+	// feature flags, read through side effects so replays agree (v1)
+	flagsEnabled := workflow.GetVersion(ctx, "feature-flags", workflow.DefaultVersion, 1) != workflow.DefaultVersion
+
+	// An order re-driven from the dead-letter queue after it was paid keeps
+	// that payment: it is not charged again, the payment is recorded if the
+	// order is dead-lettered again, and refunded if it is cancelled (v1)
+	var priorPayment *models.PaymentResponse
+	if workflow.GetVersion(ctx, "redrive-prior-payment", workflow.DefaultVersion, 1) != workflow.DefaultVersion {
+		priorPayment = redrivenPayment(ctx, order)
+	}
+	if priorPayment != nil {
+		transactionID = priorPayment.TransactionID
+		paidTenders = priorPayment.Tenders
+	}
+
+	// A cancellation requested through Temporal interrupts the running step;
+	// the order is then compensated and closes as canceled, rather than
+	// failing with the interrupted step's error (v1)
+	if workflow.GetVersion(ctx, "cancellation-compensation", workflow.DefaultVersion, 1) != workflow.DefaultVersion {
+		ctx = withCancellationCompensation(ctx)
+		defer func() {
+			if !compensatingCancellation(ctx) {
+				return
+			}
+			switch state.Status {
+			case models.StatusCompleted, models.StatusPartiallyCompleted, models.StatusCancelled:
+				// Nothing is left to undo
+			default:
+				err = compensateCancellation(ctx, order, state, transactionID, paidTenders, persistEnabled)
+			}
+		}()
+	}
+
+	// Status and expedite are upserted as search attributes so operators can
+	// list orders by them; gated by a flag because the attributes must be
+	// registered on the namespace first (v1)
+	if workflow.GetVersion(ctx, "order-search-attributes", workflow.DefaultVersion, 1) != workflow.DefaultVersion &&
+		flagsEnabled && featureflags.WorkflowEnabled(ctx, featureflags.SearchAttributes) {
+		searchAttributes = true
+		ctx = withSearchAttributes(ctx)
+		upsertOrderSearchAttributes(ctx, state)
+	}
+
+	// The approval threshold and SLA are read from dynamic config rather than
+	// fixed in code, so operators can change them without a redeploy (v1)
+	var dynamicConfig models.DynamicConfig
+	if workflow.GetVersion(ctx, "dynamic-config", workflow.DefaultVersion, 1) != workflow.DefaultVersion {
+		dynamicConfig = loadDynamicConfig(ctx)
+		if dynamicConfig.ProcessingSLA > 0 {
+			watchProcessingSLA(ctx, state, dynamicConfig.ProcessingSLA)
+		}
+	}
+
+	// The memo's customer, sales channel, and region route the order: orders
+	// from channels listed in dynamic config are expedited from the start (v1)
+	if workflow.GetVersion(ctx, "memo-routing", workflow.DefaultVersion, 1) != workflow.DefaultVersion {
+		memo := orderMemo(ctx)
+		logger.Info("Order context", "order_id", order.ID, "customer_id", memo.CustomerID, "channel", memo.Channel, "region", memo.Region)
+		if expeditedByChannel(dynamicConfig, memo) {
+			logger.Info("Expediting order for its sales channel", "order_id", order.ID, "channel", memo.Channel)
+			state.IsExpedited = true
+			state.LastUpdated = workflow.Now(ctx)
+			if searchAttributes {
+				upsertOrderSearchAttributes(ctx, state)
+			}
+		}
+	}
+
+	// The order's shipping makes its delivery explicit: expedited and
+	// overnight shipping expedite it from the start, and a promised delivery
+	// is watched like the processing SLA (v1)
+	if order.Shipping != nil &&
+		workflow.GetVersion(ctx, "shipping-info", workflow.DefaultVersion, 1) != workflow.DefaultVersion {
+		if order.Shipping.Expedited() && !state.IsExpedited {
+			logger.Info("Expediting order for its shipping method", "order_id", order.ID, "shipping_method", order.Shipping.Method)
+			state.IsExpedited = true
+			state.LastUpdated = workflow.Now(ctx)
+			if searchAttributes {
+				upsertOrderSearchAttributes(ctx, state)
+			}
+		}
+		if !order.Shipping.PromisedBy.IsZero() {
+			watchDeliveryPromise(ctx, state, *order.Shipping)
+		}
+	}
+
+	// Child workflows take their parent close policies from dynamic config,
+	// and a completed order can start a detached analytics export (v1)
+	childPoliciesEnabled := workflow.GetVersion(ctx, "parent-close-policies", workflow.DefaultVersion, 1) != workflow.DefaultVersion
+	if childPoliciesEnabled {
+		ctx = withParentClosePolicies(ctx, dynamicConfig.ParentClosePolicies)
+	}
+
+	// Orders redeem and earn loyalty points for their customer, reversed
+	// if the order is cancelled or refunded (v1)
+	if workflow.GetVersion(ctx, "loyalty-points", workflow.DefaultVersion, 1) != workflow.DefaultVersion {
+		ctx = withLoyalty(ctx, orderCustomerID(ctx, order))
+	}
+
+	// Gift card tenders are held at validation and captured once the order
+	// is fulfilled, rather than charged at payment (v1)
+	if workflow.GetVersion(ctx, "gift-card-holds", workflow.DefaultVersion, 1) != workflow.DefaultVersion {
+		ctx = withGiftCardHolds(ctx)
+	}
+
+	if eventsEnabled {
+		publishOrderEvent(ctx, models.EventOrderCreated, order, state, "", "")
+	}
+	if persistEnabled {
+		persistOrder(ctx, order)
+	}
+
 	// Step 1: Validate Order
-	state.Status = models.StatusValidating
+	setOrderStatus(ctx, state, models.StatusValidating)
 	state.Stage = models.StageValidation
 	state.LastUpdated = workflow.Now(ctx)
+	if persistEnabled {
+		persistOrderStatus(ctx, state)
+	}
 	logger.Info("Starting order validation", "order_id", order.ID)
 
+	// Back off for longer when the validation service is down (v1)
+	outageBackoffVersion := workflow.GetVersion(ctx, "validation-outage-backoff", workflow.DefaultVersion, 1)
+
+	// Fall back to local rules when the validation service stays down; any
+	// other validation error, such as a 4xx or an unreadable answer, fails
+	// the order (v1)
+	localFallbackVersion := workflow.GetVersion(ctx, "validation-local-fallback", workflow.DefaultVersion, 1)
+
 	var validationResp models.ValidationResponse
-	err = workflow.ExecuteActivity(ctx, "ValidateOrder", order).Get(ctx, &validationResp)
+	if outageBackoffVersion == workflow.DefaultVersion {
+		err = workflow.ExecuteActivity(ctx, "ValidateOrder", order).Get(ctx, &validationResp)
+	} else {
+		err = validateWithOutageBackoff(ctx, order, &validationResp)
+	}
+	if err != nil && localFallbackVersion != workflow.DefaultVersion && ctx.Err() == nil && isValidationOutage(err) {
+		logger.Warn("Validation service unavailable, falling back to local rules", "order_id", order.ID, "error", err)
+		err = workflow.ExecuteActivity(ctx, "ValidateOrderLocally", order).Get(ctx, &validationResp)
+		state.ProvisionallyValidated = err == nil && validationResp.Valid
+	}
 	if err != nil {
-		state.Status = models.StatusFailed
+		setOrderStatus(ctx, state, models.StatusFailed)
 		state.LastUpdated = workflow.Now(ctx)
+		if persistEnabled {
+			persistOrderStatus(ctx, state)
+		}
 		logger.Error("Order validation failed", "order_id", order.ID, "error", err)
-		return err
+		if eventsEnabled {
+			publishOrderEvent(ctx, models.EventOrderFailed, order, state, "", err.Error())
+		}
+		if deadLetterEnabled {
+			routeToDeadLetter(ctx, order, state.Stage, priorPayment, err)
+		}
+		recordTerminalStatus(ctx, state.Status)
+		return nil, err
 	}
 
 	if !validationResp.Valid {
-		state.Status = models.StatusFailed
+		setOrderStatus(ctx, state, models.StatusFailed)
 		state.LastUpdated = workflow.Now(ctx)
+		if persistEnabled {
+			persistOrderStatus(ctx, state)
+		}
 		logger.Error("Order validation rejected", "order_id", order.ID, "reason", validationResp.Message)
-		return fmt.Errorf("order validation failed: %s", validationResp.Message)
+		if eventsEnabled {
+			publishOrderEvent(ctx, models.EventOrderFailed, order, state, "", validationResp.Message)
+		}
+		recordTerminalStatus(ctx, state.Status)
+		if typedErrorsEnabled {
+			return nil, (&models.ValidationRejectedError{OrderID: order.ID, Reason: validationResp.Message}).ApplicationError()
+		}
+		return nil, fmt.Errorf("order validation failed: %s", validationResp.Message)
+	}
+
+	// Assess the order's risk in a RiskAssessmentWorkflow child, which runs
+	// the fraud check too, and send it on by tier below (v1). Otherwise the
+	// fraud check alone screens the order before any money moves.
+	riskTier := models.RiskTierAutoApprove
+	if flagsEnabled && workflow.GetVersion(ctx, "risk-assessment", workflow.DefaultVersion, 1) != workflow.DefaultVersion &&
+		featureflags.WorkflowEnabled(ctx, featureflags.RiskAssessment) {
+		assessment, err := assessRisk(ctx, order)
+		if err != nil {
+			setOrderStatus(ctx, state, models.StatusFailed)
+			state.LastUpdated = workflow.Now(ctx)
+			if persistEnabled {
+				persistOrderStatus(ctx, state)
+			}
+			logger.Error("Risk assessment failed", "order_id", order.ID, "error", err)
+			if eventsEnabled {
+				publishOrderEvent(ctx, models.EventOrderFailed, order, state, "", err.Error())
+			}
+			if deadLetterEnabled && !isBusinessRejection(err) {
+				routeToDeadLetter(ctx, order, state.Stage, priorPayment, err)
+			}
+			recordTerminalStatus(ctx, state.Status)
+			return nil, err
+		}
+		riskTier = assessment.Tier
+		logger.Info("Risk assessed", "order_id", order.ID, "tier", riskTier, "score", assessment.Score, "reasons", assessment.Reasons)
+	} else if flagsEnabled && featureflags.WorkflowEnabled(ctx, featureflags.FraudCheck) {
+		var fraudResult models.FraudCheckResult
+		err = workflow.ExecuteActivity(ctx, "CheckFraud", order).Get(ctx, &fraudResult)
+		if err != nil {
+			setOrderStatus(ctx, state, models.StatusFailed)
+			state.LastUpdated = workflow.Now(ctx)
+			if persistEnabled {
+				persistOrderStatus(ctx, state)
+			}
+			logger.Error("Fraud check failed", "order_id", order.ID, "error", err)
+			if eventsEnabled {
+				publishOrderEvent(ctx, models.EventOrderFailed, order, state, "", err.Error())
+			}
+			if deadLetterEnabled && !isBusinessRejection(err) {
+				routeToDeadLetter(ctx, order, state.Stage, priorPayment, err)
+			}
+			recordTerminalStatus(ctx, state.Status)
+			return nil, err
+		}
+		logger.Info("Fraud check passed", "order_id", order.ID, "score", fraudResult.Score)
+	}
+
+	// Screen the customer against the denylist and sanctions lists before
+	// any money moves (v1)
+	if flagsEnabled && workflow.GetVersion(ctx, "customer-screening", workflow.DefaultVersion, 1) != workflow.DefaultVersion &&
+		featureflags.WorkflowEnabled(ctx, featureflags.CustomerScreening) {
+		if err = screenCustomer(ctx, order); err != nil {
+			setOrderStatus(ctx, state, models.StatusFailed)
+			state.LastUpdated = workflow.Now(ctx)
+			if persistEnabled {
+				persistOrderStatus(ctx, state)
+			}
+			logger.Error("Customer screening failed", "order_id", order.ID, "error", err)
+			if eventsEnabled {
+				publishOrderEvent(ctx, models.EventOrderFailed, order, state, "", err.Error())
+			}
+			if deadLetterEnabled && !isBusinessRejection(err) {
+				routeToDeadLetter(ctx, order, state.Stage, priorPayment, err)
+			}
+			recordTerminalStatus(ctx, state.Status)
+			return nil, err
+		}
+	}
+
+	// Once the order is validated its gift card balances are held and the
+	// loyalty points it spends are redeemed. A re-driven order that was paid
+	// redeemed its points on its first run, and keeps them spent as it keeps
+	// its payment.
+	err = holdGiftCards(ctx, order)
+	if err == nil && priorPayment == nil {
+		if err = redeemLoyaltyPoints(ctx, order); err != nil {
+			releaseGiftCards(ctx, order)
+		}
+	}
+	if err != nil {
+		setOrderStatus(ctx, state, models.StatusFailed)
+		state.LastUpdated = workflow.Now(ctx)
+		if persistEnabled {
+			persistOrderStatus(ctx, state)
+		}
+		logger.Error("Holding gift cards or redeeming loyalty points failed", "order_id", order.ID, "points", order.RedeemPoints, "error", err)
+		if eventsEnabled {
+			publishOrderEvent(ctx, models.EventOrderFailed, order, state, "", err.Error())
+		}
+		if deadLetterEnabled && !isBusinessRejection(err) {
+			routeToDeadLetter(ctx, order, state.Stage, priorPayment, err)
+		}
+		recordTerminalStatus(ctx, state.Status)
+		return nil, err
+	}
+
+	// Risky orders wait for their customer to pass step-up verification, or
+	// a cancel. One that fails it, or gets no answer in time, is escalated to
+	// manual review.
+	if riskTier == models.RiskTierStepUp && !cancelRequested {
+		previousStatus := state.Status
+		setOrderStatus(ctx, state, models.StatusAwaitingVerification)
+		state.LastUpdated = workflow.Now(ctx)
+		if persistEnabled {
+			persistOrderStatus(ctx, state)
+		}
+		logger.Info("Order awaiting step-up verification", "order_id", order.ID)
+
+		verified := false
+		if requestStepUp(ctx, order) {
+			if _, err := workflow.AwaitWithTimeout(ctx, StepUpVerificationTimeout, func() bool { return stepUp != nil || cancelRequested }); err != nil {
+				return nil, err
+			}
+			verified = stepUp != nil && stepUp.Verified
+		}
+		if !verified && !cancelRequested {
+			riskTier = models.RiskTierManualReview
+			logger.Info("Step-up verification not passed, escalating to manual review", "order_id", order.ID)
+		}
+		setOrderStatus(ctx, state, previousStatus)
+		state.LastUpdated = workflow.Now(ctx)
+	}
+
+	// High-value orders, and those held for manual review, wait for an
+	// approve signal, or a cancel, before payment
+	if (requiresApproval(dynamicConfig, order) || riskTier == models.RiskTierManualReview) && !cancelRequested {
+		previousStatus := state.Status
+		setOrderStatus(ctx, state, models.StatusAwaitingApproval)
+		state.LastUpdated = workflow.Now(ctx)
+		if persistEnabled {
+			persistOrderStatus(ctx, state)
+		}
+		logger.Info("Order awaiting approval", "order_id", order.ID, "amount", order.Amount, "threshold", dynamicConfig.HighValueApprovalThreshold, "risk_tier", riskTier)
+
+		if err := workflow.Await(ctx, func() bool { return approved || cancelRequested }); err != nil {
+			return nil, err
+		}
+		setOrderStatus(ctx, state, previousStatus)
+		state.LastUpdated = workflow.Now(ctx)
 	}
 
 	// Check for cancellation after validation
 	if cancelRequested {
-		state.Status = models.StatusCancelled
+		if priorPayment != nil {
+			refundPayment(ctx, order, state, transactionID, paidTenders)
+		}
+		reverseLoyaltyPoints(ctx, order)
+		releaseGiftCards(ctx, order)
+		setOrderStatus(ctx, state, models.StatusCancelled)
 		state.LastUpdated = workflow.Now(ctx)
+		if persistEnabled {
+			persistOrderStatus(ctx, state)
+		}
 		logger.Info("Order cancelled after validation", "order_id", order.ID)
-		return nil
+		recordTerminalStatus(ctx, state.Status)
+		return nil, nil
 	}
 
 	// Step 2: Process payment with versioning for backward compatibility
 	state.Stage = models.StagePayment
 	state.LastUpdated = workflow.Now(ctx)
+	if persistEnabled {
+		persistOrderStatus(ctx, state)
+	}
 
 	// Workflow versioning: Allows safe evolution from activity to child workflow
 	// Version 1 (DefaultVersion): Used activity directly (old behavior)
 	// Version 2: Uses child workflow (new behavior)
 	version := workflow.GetVersion(ctx, "payment-processing-change", workflow.DefaultVersion, 2)
 
+	// The child workflow path can be switched off by flag, falling back to the activity
+	useChildPayment := version != workflow.DefaultVersion
+	if useChildPayment && flagsEnabled {
+		useChildPayment = featureflags.WorkflowEnabled(ctx, featureflags.ChildWorkflowPayment)
+	}
+
 	var paymentResp *models.PaymentResponse
+	var installmentPlan workflow.ChildWorkflowFuture
 
-	if version == workflow.DefaultVersion {
+	// Payment charges what the held gift cards do not cover
+	paymentOrder, charge := withoutGiftCards(ctx, order)
+
+	if priorPayment != nil {
+		logger.Info("Order already paid before it was dead-lettered", "order_id", order.ID, "transaction_id", priorPayment.TransactionID)
+		paymentResp = priorPayment
+	} else if !charge {
+		logger.Info("Order paid by gift card holds", "order_id", order.ID)
+		paymentResp = &models.PaymentResponse{Success: true, Message: "Paid by gift card"}
+	} else if !useChildPayment {
 		// OLD VERSION: Process payment using activity directly
-		// This path ensures running workflows continue to work when we deploy new code
+		// This path ensures running workflows continue to work when we deploy new code,
+		// and is also taken when the child-workflow-payment flag is off
 		logger.Info("Processing payment via activity (legacy version)", "order_id", order.ID)
 
 		paymentReq := models.PaymentRequest{
-			OrderID: order.ID,
-			Amount:  order.Amount,
+			OrderID:       order.ID,
+			Amount:        paymentOrder.Amount,
+			PaymentMethod: paymentOrder.PaymentMethod,
 		}
 
 		var activityResp models.PaymentResponse
-		err = workflow.ExecuteActivity(ctx, "ProcessPayment", paymentReq).Get(ctx, &activityResp)
+		if len(paymentOrder.Tenders) > 0 {
+			// Split tenders are charged one at a time on this path too
+			var split *models.PaymentResponse
+			if split, err = payTenders(ctx, paymentOrder); err == nil {
+				activityResp = *split
+			}
+		} else {
+			err = workflow.ExecuteActivity(ctx, "ProcessPayment", paymentReq).Get(ctx, &activityResp)
+		}
 		if err != nil {
-			state.Status = models.StatusFailed
+			reverseLoyaltyPoints(ctx, order)
+			releaseGiftCards(ctx, order)
+			setOrderStatus(ctx, state, models.StatusFailed)
 			state.PaymentStatus = "failed"
+			if typedErrorsEnabled && applicationErrorType(err) == models.ErrTypePaymentDeclined {
+				state.PaymentStatus = "declined"
+			}
 			state.LastUpdated = workflow.Now(ctx)
+			if persistEnabled {
+				persistOrderStatus(ctx, state)
+			}
 			logger.Error("Payment processing failed", "order_id", order.ID, "error", err)
-			return err
+			if eventsEnabled {
+				publishOrderEvent(ctx, models.EventOrderFailed, order, state, "", err.Error())
+			}
+			if deadLetterEnabled && !(typedErrorsEnabled && isBusinessRejection(err)) {
+				routeToDeadLetter(ctx, order, state.Stage, nil, err)
+			}
+			recordTerminalStatus(ctx, state.Status)
+			return nil, err
 		}
 		paymentResp = &activityResp
 		logger.Info("Payment completed via activity", "order_id", order.ID, "transaction_id", paymentResp.TransactionID)
 
 	} else {
 		// NEW VERSION: Process payment using child workflow
-		// All new workflow executions will use this path
-		logger.Info("Processing payment via child workflow (v2)", "order_id", order.ID)
-
-		// Configure child workflow options
-		childWorkflowOptions := workflow.ChildWorkflowOptions{
-			WorkflowID:               fmt.Sprintf("payment-%s", order.ID),
-			WorkflowExecutionTimeout: 2 * time.Minute,
-			RetryPolicy: &RetryPolicy{
-				InitialInterval:    time.Second,
-				BackoffCoefficient: 2.0,
-				MaximumInterval:    10 * time.Second,
-				MaximumAttempts:    3,
-			},
-		}
-		childCtx := workflow.WithChildOptions(ctx, childWorkflowOptions)
-
-		// Execute payment as child workflow
-		err = workflow.ExecuteChildWorkflow(childCtx, PaymentWorkflowName, order).Get(ctx, &paymentResp)
+		// All new workflow executions will use this path. Payments can instead
+		// run in the payments namespace behind a Nexus endpoint (v1); earlier
+		// executions, and orders with no endpoint configured, keep the child.
+		// Orders paid in installments start an installment plan child, and are
+		// fulfilled once it starts rather than once it is paid (v1).
+		paymentVia := "child workflow"
+		installmentsEnabled := order.Installments > 1 &&
+			workflow.GetVersion(ctx, "installment-plan", workflow.DefaultVersion, 1) != workflow.DefaultVersion
+		nexusVersion := workflow.GetVersion(ctx, "payment-nexus", workflow.DefaultVersion, 1)
+		if installmentsEnabled {
+			paymentVia = "installment plan"
+			logger.Info("Processing payment via installment plan", "order_id", order.ID, "installments", order.Installments)
+			if installmentPlan, err = startInstallmentPlan(ctx, order); err == nil {
+				paymentResp = &models.PaymentResponse{Success: true, Message: fmt.Sprintf("Installment plan of %d payments started", order.Installments)}
+			}
+		} else if nexusVersion != workflow.DefaultVersion && dynamicConfig.PaymentNexusEndpoint != "" {
+			paymentVia = "Nexus"
+			logger.Info("Processing payment via Nexus", "order_id", order.ID, "endpoint", dynamicConfig.PaymentNexusEndpoint)
+			paymentClient := workflow.NewNexusClient(dynamicConfig.PaymentNexusEndpoint, PaymentServiceName)
+			err = paymentClient.ExecuteOperation(ctx, ProcessPaymentOperationName, paymentOrder, workflow.NexusOperationOptions{
+				ScheduleToCloseTimeout: 10 * time.Minute,
+			}).Get(ctx, &paymentResp)
+		} else {
+			logger.Info("Processing payment via child workflow (v2)", "order_id", order.ID)
+
+			// Configure child workflow options
+			childWorkflowOptions := workflow.ChildWorkflowOptions{
+				WorkflowID:               fmt.Sprintf("payment-%s", order.ID),
+				WorkflowExecutionTimeout: 2 * time.Minute,
+				ParentClosePolicy:        parentClosePolicy(ctx, models.ChildPayment, enums.PARENT_CLOSE_POLICY_UNSPECIFIED),
+				RetryPolicy: &RetryPolicy{
+					InitialInterval:    time.Second,
+					BackoffCoefficient: 2.0,
+					MaximumInterval:    10 * time.Second,
+					MaximumAttempts:    3,
+				},
+			}
+			// Payments moved to their own task queue (v1); earlier executions keep
+			// running their payment child on the order queue
+			if workflow.GetVersion(ctx, "payment-task-queue", workflow.DefaultVersion, 1) != workflow.DefaultVersion {
+				childWorkflowOptions.TaskQueue = PaymentTaskQueue
+			}
+			childCtx := workflow.WithChildOptions(ctx, childWorkflowOptions)
+
+			// Execute payment as child workflow
+			err = workflow.ExecuteChildWorkflow(childCtx, PaymentWorkflowName, paymentOrder).Get(ctx, &paymentResp)
+		}
 		if err != nil {
-			state.Status = models.StatusFailed
+			reverseLoyaltyPoints(ctx, order)
+			releaseGiftCards(ctx, order)
+			setOrderStatus(ctx, state, models.StatusFailed)
 			state.PaymentStatus = "failed"
+			if typedErrorsEnabled && applicationErrorType(err) == models.ErrTypePaymentDeclined {
+				state.PaymentStatus = "declined"
+			}
 			state.LastUpdated = workflow.Now(ctx)
-			logger.Error("Payment child workflow failed", "order_id", order.ID, "error", err)
-			return err
+			if persistEnabled {
+				persistOrderStatus(ctx, state)
+			}
+			logger.Error("Payment failed", "order_id", order.ID, "via", paymentVia, "error", err)
+			if eventsEnabled {
+				publishOrderEvent(ctx, models.EventOrderFailed, order, state, "", err.Error())
+			}
+			if deadLetterEnabled && !(typedErrorsEnabled && isBusinessRejection(err)) {
+				routeToDeadLetter(ctx, order, state.Stage, nil, err)
+			}
+			recordTerminalStatus(ctx, state.Status)
+			return nil, err
 		}
-		logger.Info("Payment completed via child workflow", "order_id", order.ID, "transaction_id", paymentResp.TransactionID)
+		logger.Info("Payment completed", "order_id", order.ID, "via", paymentVia, "transaction_id", paymentResp.TransactionID)
 	}
 
 	state.PaymentStatus = "completed"
+	if installmentPlan != nil {
+		state.PaymentStatus = "installments"
+	}
+	transactionID = paymentResp.TransactionID
+	paidTenders = paymentResp.Tenders
+	if priorPayment == nil {
+		// A re-driven order was awarded its points when it was first paid
+		awardLoyaltyPoints(ctx, order)
+	}
+
+	if eventsEnabled {
+		publishOrderEvent(ctx, models.EventOrderPaid, order, state, paymentResp.TransactionID, "")
+	}
+	if persistEnabled {
+		persistOrderStatus(ctx, state)
+	}
+
+	// Paid orders wait out the dynamic edit grace period before fulfillment,
+	// taking item updates, and a cancel in that window refunds the payment (v1)
+	if dynamicConfig.EditGracePeriod > 0 && !cancelRequested &&
+		workflow.GetVersion(ctx, "edit-grace-period", workflow.DefaultVersion, 1) != workflow.DefaultVersion {
+		if err := awaitEdits(ctx, &order, window, dynamicConfig.EditGracePeriod, &cancelRequested, persistEnabled); err != nil {
+			return nil, err
+		}
+		if cancelRequested && installmentPlan == nil && transactionID != "" {
+			refundPayment(ctx, order, state, transactionID, paidTenders)
+		}
+	}
 
 	// Check for cancellation after payment
 	if cancelRequested {
-		state.Status = models.StatusCancelled
+		reverseLoyaltyPoints(ctx, order)
+		releaseGiftCards(ctx, order)
+		setOrderStatus(ctx, state, models.StatusCancelled)
 		state.LastUpdated = workflow.Now(ctx)
+		if persistEnabled {
+			persistOrderStatus(ctx, state)
+		}
 		logger.Info("Order cancelled after payment", "order_id", order.ID)
-		return nil
+		recordTerminalStatus(ctx, state.Status)
+		return nil, nil
 	}
 
 	// Step 3: Process Order
-	state.Status = models.StatusProcessing
+	setOrderStatus(ctx, state, models.StatusProcessing)
 	state.Stage = models.StageProcessing
 	state.LastUpdated = workflow.Now(ctx)
+	if persistEnabled {
+		persistOrderStatus(ctx, state)
+	}
 	logger.Info("Starting order processing", "order_id", order.ID, "expedited", state.IsExpedited)
 
-	err = workflow.ExecuteActivity(ctx, "ProcessOrder", order, state.IsExpedited).Get(ctx, nil)
+	// A heartbeat timeout lets a crashed worker's attempt be retried quickly;
+	// the retry resumes from the last heartbeat checkpoint
+	processOptions := workflow.GetActivityOptions(ctx)
+	processOptions.HeartbeatTimeout = 5 * time.Second
+	processCtx := workflow.WithActivityOptions(ctx, processOptions)
+
+	// Items are fulfilled in parallel rather than by one ProcessOrder call (v1)
+	fulfillmentVersion := workflow.GetVersion(ctx, "parallel-item-fulfillment", workflow.DefaultVersion, 1)
+
+	// Large orders fan out one child workflow per item and may partially complete (v1)
+	itemChildVersion := workflow.GetVersion(ctx, "item-child-workflows", workflow.DefaultVersion, 1)
+
+	// Items are routed to the warehouses that stock them and each warehouse
+	// ships its share separately, when enabled (v1)
+	warehouseVersion := workflow.GetVersion(ctx, "multi-warehouse", workflow.DefaultVersion, 1)
+
+	// Marketplace items are dropshipped by their vendors, one
+	// VendorFulfillmentWorkflow child per vendor, while the order's own items
+	// are fulfilled as usual (v1)
+	fulfillOrder := order
+	var vendors []workflow.ChildWorkflowFuture
+	if len(order.Vendors) > 0 &&
+		workflow.GetVersion(ctx, "dropship", workflow.DefaultVersion, 1) != workflow.DefaultVersion {
+		fulfillOrder.Items, vendors = startVendorFulfillment(ctx, order, state)
+	}
+
+	var fulfill fulfillFunc
+	switch {
+	case warehouseVersion != workflow.DefaultVersion && dynamicConfig.WarehouseRouting && len(fulfillOrder.Items) > 0:
+		fulfill = fulfillFromWarehouses
+	case itemChildVersion != workflow.DefaultVersion && len(fulfillOrder.Items) > ItemChildWorkflowThreshold:
+		fulfill = fulfillItemsWithChildren
+	case fulfillmentVersion != workflow.DefaultVersion && len(fulfillOrder.Items) > 0:
+		fulfill = fulfillItems
+	}
+	switch {
+	case len(vendors) > 0 && len(fulfillOrder.Items) == 0:
+		// Every item is dropshipped
+	case fulfill != nil:
+		err = fulfill(processCtx, fulfillOrder, state)
+	default:
+		err = workflow.ExecuteActivity(processCtx, "ProcessOrder", fulfillOrder, state.IsExpedited).Get(ctx, nil)
+	}
+
+	// Items out of stock are backordered until restocked, up to the dynamic
+	// backorder timeout, rather than failing the order (v1)
+	if workflow.GetVersion(ctx, "backorder", workflow.DefaultVersion, 1) != workflow.DefaultVersion &&
+		fulfill != nil && dynamicConfig.BackorderTimeout > 0 && outOfStockItems(err) != nil {
+		err = awaitRestock(processCtx, fulfillOrder, state, dynamicConfig, fulfill, backorder{
+			restocked:       &restocked,
+			cancelRequested: &cancelRequested,
+			persistEnabled:  persistEnabled,
+		}, err)
+		if errors.Is(err, errBackorderCancelled) {
+			// The order was paid, so the payment is refunded; an installment
+			// plan refunds itself when the order closes
+			if installmentPlan == nil {
+				refundPayment(ctx, order, state, transactionID, paidTenders)
+			}
+			reverseLoyaltyPoints(ctx, order)
+			releaseGiftCards(ctx, order)
+			setOrderStatus(ctx, state, models.StatusCancelled)
+			state.LastUpdated = workflow.Now(ctx)
+			if persistEnabled {
+				persistOrderStatus(ctx, state)
+			}
+			logger.Info("Order cancelled while backordered", "order_id", order.ID, "payment_status", state.PaymentStatus)
+			recordTerminalStatus(ctx, state.Status)
+			return nil, nil
+		}
+	}
+	// Vendors' answers are awaited once the order's own items are fulfilled;
+	// a rejected purchase order fails the order as a failed item would
+	if err == nil && len(vendors) > 0 {
+		err = awaitVendorFulfillment(ctx, order, state, vendors)
+	}
 	if err != nil {
-		state.Status = models.StatusFailed
+		// Gift card holds are only captured for fulfilled orders
+		releaseGiftCards(ctx, order)
+		setOrderStatus(ctx, state, models.StatusFailed)
 		state.LastUpdated = workflow.Now(ctx)
+		if persistEnabled {
+			persistOrderStatus(ctx, state)
+		}
 		logger.Error("Order processing failed", "order_id", order.ID, "error", err)
-		return err
+		if eventsEnabled {
+			publishOrderEvent(ctx, models.EventOrderFailed, order, state, paymentResp.TransactionID, err.Error())
+		}
+		if deadLetterEnabled && !(typedErrorsEnabled && isBusinessRejection(err)) {
+			routeToDeadLetter(ctx, order, state.Stage, paymentResp, err)
+		}
+		recordTerminalStatus(ctx, state.Status)
+		return nil, err
+	}
+
+	captureGiftCards(ctx, order)
+
+	// Invoices are generated and uploaded on one host in a worker session (v1).
+	// The order is already fulfilled, so a failed invoice is logged, not fatal.
+	if workflow.GetVersion(ctx, "invoice-session", workflow.DefaultVersion, 1) != workflow.DefaultVersion {
+		invoiceURL, err := generateInvoice(ctx, models.InvoiceRequest{
+			OrderID:       order.ID,
+			Items:         order.Items,
+			Amount:        order.Amount,
+			TransactionID: paymentResp.TransactionID,
+		})
+		if err != nil {
+			logger.Warn("Invoice generation failed but order completed", "order_id", order.ID, "error", err)
+		} else {
+			state.InvoiceURL = invoiceURL
+		}
 	}
 
 	// Step 4: Notify completion
@@ -211,10 +967,64 @@ func OrderWorkflow(ctx workflow.Context, order models.Order) error {
 	}
 
 	// Mark as completed
-	state.Status = models.StatusCompleted
+	completed := models.StatusCompleted
+	if failed := countItems(state.ItemResults, models.ItemFailed); failed > 0 {
+		completed = models.StatusPartiallyCompleted
+		logger.Warn("Order partially completed", "order_id", order.ID, "failed_items", failed, "total_items", len(order.Items))
+	}
+	setOrderStatus(ctx, state, completed)
 	state.Stage = models.StageCompleted
 	state.LastUpdated = workflow.Now(ctx)
+	if persistEnabled {
+		persistOrderStatus(ctx, state)
+	}
+
+	if eventsEnabled {
+		publishOrderEvent(ctx, models.EventOrderCompleted, order, state, paymentResp.TransactionID, "")
+	}
+
+	// A shipped order stays open while a TrackingWorkflow child polls the
+	// carrier, until it signals delivery or an exception (v1). An order
+	// shipped from several warehouses tracks each shipment.
+	var trackings []workflow.ChildWorkflowFuture
+	if dynamicConfig.TrackingPollInterval > 0 &&
+		workflow.GetVersion(ctx, "shipment-tracking", workflow.DefaultVersion, 1) != workflow.DefaultVersion {
+		trackings = trackShipments(ctx, order, state, dynamicConfig.TrackingPollInterval)
+	}
+
+	// An order paid in installments stays open until its plan settles, so
+	// the plan's default signal has an order to reach
+	if installmentPlan != nil {
+		transactionID = awaitInstallmentPlan(ctx, order, state, installmentPlan)
+		if persistEnabled {
+			persistOrderStatus(ctx, state)
+		}
+		logger.Info("Installment plan settled", "order_id", order.ID, "payment_status", state.PaymentStatus)
+	}
+
+	if len(trackings) > 0 {
+		for _, tracking := range trackings {
+			awaitTracking(ctx, order, tracking)
+		}
+		if persistEnabled {
+			persistOrderStatus(ctx, state)
+		}
+		logger.Info("Shipment tracking finished", "order_id", order.ID, "shipment_status", state.ShipmentStatus)
+	}
+
+	if childPoliciesEnabled && dynamicConfig.AnalyticsExport {
+		exportAnalytics(ctx, order, orderMemo(ctx), orderResult(ctx, state, transactionID))
+	}
+
+	// Completed orders are archived to cold storage by a detached child,
+	// which purges their PII once the retention period passes (v1)
+	if dynamicConfig.OrderArchival &&
+		workflow.GetVersion(ctx, "order-archival", workflow.DefaultVersion, 1) != workflow.DefaultVersion {
+		archiveOrder(ctx, order, state, history, orderResult(ctx, state, transactionID), dynamicConfig.PIIRetention)
+	}
+
 	logger.Info("Order workflow completed successfully", "order_id", order.ID)
 
-	return nil
+	recordTerminalStatus(ctx, state.Status)
+	return nil, nil
 }
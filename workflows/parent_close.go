@@ -0,0 +1,31 @@
+package workflows
+
+import (
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/workflow"
+)
+
+type parentClosePoliciesKey struct{}
+
+// withParentClosePolicies marks ctx with the parent close policies the
+// order's child workflows are started with
+func withParentClosePolicies(ctx workflow.Context, policies map[string]string) workflow.Context {
+	return workflow.WithValue(ctx, parentClosePoliciesKey{}, policies)
+}
+
+// parentClosePolicy returns the configured parent close policy of child, or
+// fallback when ctx carries none for it
+func parentClosePolicy(ctx workflow.Context, child string, fallback enums.ParentClosePolicy) enums.ParentClosePolicy {
+	policies, _ := ctx.Value(parentClosePoliciesKey{}).(map[string]string)
+	switch policies[child] {
+	case models.ParentCloseTerminate:
+		return enums.PARENT_CLOSE_POLICY_TERMINATE
+	case models.ParentCloseRequestCancel:
+		return enums.PARENT_CLOSE_POLICY_REQUEST_CANCEL
+	case models.ParentCloseAbandon:
+		return enums.PARENT_CLOSE_POLICY_ABANDON
+	default:
+		return fallback
+	}
+}
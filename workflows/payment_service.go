@@ -0,0 +1,51 @@
+package workflows
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"github.com/nexus-rpc/sdk-go/nexus"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/temporalnexus"
+)
+
+const (
+	// PaymentServiceName is the Nexus service the payments namespace serves
+	PaymentServiceName = "payment-service"
+	// ProcessPaymentOperationName runs a PaymentWorkflow for an order and
+	// returns its PaymentResponse
+	ProcessPaymentOperationName = "process-payment"
+)
+
+// ProcessPaymentOperation starts the order's PaymentWorkflow in the handler's
+// namespace, on the task queue of the worker serving the operation. The
+// workflow ID is derived from the order, so an order never has two payments
+// running at once.
+var ProcessPaymentOperation = temporalnexus.NewWorkflowRunOperation(
+	ProcessPaymentOperationName,
+	PaymentWorkflow,
+	func(ctx context.Context, order models.Order, options nexus.StartOperationOptions) (client.StartWorkflowOptions, error) {
+		return client.StartWorkflowOptions{
+			ID:                       fmt.Sprintf("payment-%s", order.ID),
+			WorkflowExecutionTimeout: 2 * time.Minute,
+			RetryPolicy: &RetryPolicy{
+				InitialInterval:    time.Second,
+				BackoffCoefficient: 2.0,
+				MaximumInterval:    10 * time.Second,
+				MaximumAttempts:    3,
+			},
+		}, nil
+	},
+)
+
+// NewPaymentService returns the payment Nexus service, for registration on
+// the payments worker
+func NewPaymentService() (*nexus.Service, error) {
+	service := nexus.NewService(PaymentServiceName)
+	if err := service.Register(ProcessPaymentOperation); err != nil {
+		return nil, err
+	}
+	return service, nil
+}
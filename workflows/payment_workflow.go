@@ -1,6 +1,9 @@
 package workflows
 
 import (
+	"fmt"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/aswathylr-builds/temporal-order-processing/models"
@@ -25,10 +28,31 @@ func PaymentWorkflow(ctx workflow.Context, order models.Order) (*models.PaymentR
 	}
 	ctx = workflow.WithActivityOptions(ctx, activityOptions)
 
+	// An order in another currency is charged in the base currency at the
+	// current exchange rate, which the response records for audit (v1)
+	var conversion *models.CurrencyConversion
+	if order.Currency != "" && workflow.GetVersion(ctx, "currency-conversion", workflow.DefaultVersion, 1) != workflow.DefaultVersion {
+		conversionReq := models.ConversionRequest{OrderID: order.ID, Amount: order.Amount, From: order.Currency}
+		if err := workflow.ExecuteActivity(ctx, "ConvertCurrency", conversionReq).Get(ctx, &conversion); err != nil {
+			logger.Error("Currency conversion failed", "order_id", order.ID, "currency", order.Currency, "error", err)
+			return nil, err
+		}
+		order = convertOrder(order, *conversion)
+	}
+
+	if len(order.Tenders) > 0 {
+		paymentResp, err := payTenders(ctx, order)
+		if paymentResp != nil {
+			paymentResp.Conversion = conversion
+		}
+		return paymentResp, err
+	}
+
 	// Process payment
 	paymentReq := models.PaymentRequest{
-		OrderID: order.ID,
-		Amount:  order.Amount,
+		OrderID:       order.ID,
+		Amount:        order.Amount,
+		PaymentMethod: order.PaymentMethod,
 	}
 
 	var paymentResp models.PaymentResponse
@@ -38,6 +62,104 @@ func PaymentWorkflow(ctx workflow.Context, order models.Order) (*models.PaymentR
 		return nil, err
 	}
 
+	paymentResp.Conversion = conversion
+
 	logger.Info("Payment workflow completed", "order_id", order.ID, "transaction_id", paymentResp.TransactionID)
 	return &paymentResp, nil
 }
+
+// convertOrder returns the order with its amount and tenders in the
+// conversion's currency. Tenders are rounded to the cent, with the last
+// taking up the rounding so they still add up to the amount.
+func convertOrder(order models.Order, conversion models.CurrencyConversion) models.Order {
+	converted := order
+	converted.Currency = conversion.To
+	converted.Amount = conversion.ConvertedAmount
+	if len(order.Tenders) == 0 {
+		return converted
+	}
+	converted.Tenders = make([]models.Tender, len(order.Tenders))
+	remaining := converted.Amount
+	for i, tender := range order.Tenders {
+		if i < len(order.Tenders)-1 {
+			tender.Amount = math.Round(tender.Amount*conversion.Rate*100) / 100
+		} else {
+			tender.Amount = math.Round(remaining*100) / 100
+		}
+		remaining -= tender.Amount
+		converted.Tenders[i] = tender
+	}
+	return converted
+}
+
+// payTenders charges the order's tenders one at a time, in order. If one
+// fails, the tenders already captured are refunded so a failed payment leaves
+// nothing charged. The response lists every capture and joins their
+// transaction IDs.
+func payTenders(ctx workflow.Context, order models.Order) (*models.PaymentResponse, error) {
+	logger := workflow.GetLogger(ctx)
+
+	if err := order.ValidateTenders(); err != nil {
+		return nil, (&models.PaymentDeclinedError{
+			OrderID:     order.ID,
+			Amount:      order.Amount,
+			DeclineCode: "invalid_tenders",
+			Reason:      err.Error(),
+		}).ApplicationError()
+	}
+
+	var captured []models.TenderPayment
+	for _, tender := range order.Tenders {
+		paymentReq := models.PaymentRequest{
+			OrderID:       order.ID,
+			Amount:        tender.Amount,
+			Method:        tender.Method,
+			PaymentMethod: order.PaymentMethodFor(tender),
+		}
+		var paymentResp models.PaymentResponse
+		if err := workflow.ExecuteActivity(ctx, "ProcessPayment", paymentReq).Get(ctx, &paymentResp); err != nil {
+			logger.Error("Tender payment failed", "order_id", order.ID, "method", tender.Method, "amount", tender.Amount, "error", err)
+			if len(captured) > 0 {
+				if rollbackErr := refundTenders(ctx, order.ID, captured); rollbackErr != nil {
+					logger.Error("Failed to roll back captured tenders", "order_id", order.ID, "error", rollbackErr)
+				}
+			}
+			return nil, err
+		}
+		logger.Info("Tender captured", "order_id", order.ID, "method", tender.Method, "amount", tender.Amount, "transaction_id", paymentResp.TransactionID)
+		captured = append(captured, models.TenderPayment{
+			Method:        tender.Method,
+			Amount:        tender.Amount,
+			TransactionID: paymentResp.TransactionID,
+		})
+	}
+
+	transactionIDs := make([]string, len(captured))
+	for i, tender := range captured {
+		transactionIDs[i] = tender.TransactionID
+	}
+	return &models.PaymentResponse{
+		Success:       true,
+		TransactionID: strings.Join(transactionIDs, ","),
+		Message:       fmt.Sprintf("Payment processed across %d tenders", len(captured)),
+		Tenders:       captured,
+	}, nil
+}
+
+// refundTenders refunds captured tenders, latest first. It runs on a
+// disconnected context so a cancelled workflow still undoes its charges,
+// and attempts every refund, returning the first failure.
+func refundTenders(ctx workflow.Context, orderID string, tenders []models.TenderPayment) error {
+	ctx, _ = workflow.NewDisconnectedContext(ctx)
+	var firstErr error
+	for i := len(tenders) - 1; i >= 0; i-- {
+		refund := models.RefundRequest{OrderID: orderID, TransactionID: tenders[i].TransactionID, Amount: tenders[i].Amount}
+		if err := workflow.ExecuteActivity(ctx, "RefundPayment", refund).Get(ctx, nil); err != nil {
+			workflow.GetLogger(ctx).Error("Failed to refund tender", "order_id", orderID, "method", tenders[i].Method, "transaction_id", tenders[i].TransactionID, "error", err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
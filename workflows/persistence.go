@@ -0,0 +1,43 @@
+package workflows
+
+import (
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/workflow"
+)
+
+// persistenceActivityOptions returns options for the database mirroring activities
+func persistenceActivityOptions(ctx workflow.Context) workflow.Context {
+	return workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout:    10 * time.Second,
+		ScheduleToStartTimeout: 5 * time.Second,
+		RetryPolicy: &RetryPolicy{
+			InitialInterval:    time.Second,
+			BackoffCoefficient: 2.0,
+			MaximumInterval:    30 * time.Second,
+			MaximumAttempts:    10,
+		},
+	})
+}
+
+// persistOrder mirrors the order into the order store. The database is a
+// read model only, so failures are logged rather than failing the order.
+func persistOrder(ctx workflow.Context, order models.Order) {
+	err := workflow.ExecuteActivity(persistenceActivityOptions(ctx), "PersistOrder", order).Get(ctx, nil)
+	if err != nil {
+		workflow.GetLogger(ctx).Warn("Failed to persist order", "order_id", order.ID, "error", err)
+	}
+}
+
+// persistOrderStatus mirrors the current workflow status into the order store,
+// and into search attributes when they are enabled
+func persistOrderStatus(ctx workflow.Context, state *models.OrderStatus) {
+	if searchAttributesEnabled(ctx) {
+		upsertOrderSearchAttributes(ctx, state)
+	}
+	err := workflow.ExecuteActivity(persistenceActivityOptions(ctx), "UpdateOrderStatus", *state).Get(ctx, nil)
+	if err != nil {
+		workflow.GetLogger(ctx).Warn("Failed to persist order status", "order_id", state.OrderID, "status", state.Status, "error", err)
+	}
+}
@@ -0,0 +1,141 @@
+package workflows
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/workflow"
+)
+
+// ReconciliationWorkflowName is the registered name of ReconciliationWorkflow
+const ReconciliationWorkflowName = "ReconciliationWorkflow"
+
+// ReconciliationWorkflow reconciles a day's payments: it pulls the captures
+// and refunds the payment gateway made that UTC day, compares them with the
+// orders the order store recorded as completed, and reports the
+// discrepancies to finance. The nightly schedule starts it for the day
+// before; it can also be started for a given day to reconcile it again.
+func ReconciliationWorkflow(ctx workflow.Context, req models.ReconciliationRequest) (*models.ReconciliationReport, error) {
+	logger := workflow.GetLogger(ctx)
+
+	window, err := dayWindow(ctx, req.Date)
+	if err != nil {
+		return nil, err
+	}
+	date := window.From.Format(models.ReconciliationDateFormat)
+	logger.Info("Reconciliation workflow started", "date", date)
+
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout:    time.Minute,
+		ScheduleToStartTimeout: 5 * time.Minute,
+		RetryPolicy: &RetryPolicy{
+			InitialInterval:    time.Second,
+			BackoffCoefficient: 2.0,
+			MaximumInterval:    time.Minute,
+			MaximumAttempts:    5,
+		},
+	})
+
+	transactionsFuture := workflow.ExecuteActivity(ctx, "FetchGatewayTransactions", window)
+	ordersFuture := workflow.ExecuteActivity(ctx, "ListCompletedOrders", window)
+	var transactions []models.GatewayTransaction
+	if err := transactionsFuture.Get(ctx, &transactions); err != nil {
+		logger.Error("Failed to fetch gateway transactions", "date", date, "error", err)
+		return nil, err
+	}
+	var orders []models.CompletedOrder
+	if err := ordersFuture.Get(ctx, &orders); err != nil {
+		logger.Error("Failed to list completed orders", "date", date, "error", err)
+		return nil, err
+	}
+
+	report := reconcilePayments(date, transactions, orders)
+	if err := workflow.ExecuteActivity(ctx, "ReportDiscrepancies", *report).Get(ctx, nil); err != nil {
+		logger.Error("Failed to report discrepancies", "date", date, "error", err)
+		return nil, err
+	}
+
+	logger.Info("Reconciliation workflow completed", "date", date, "matched", report.Matched, "discrepancies", len(report.Discrepancies))
+	return report, nil
+}
+
+// dayWindow returns the UTC day date names, or the day before the workflow
+// started when date is empty
+func dayWindow(ctx workflow.Context, date string) (models.ReconciliationWindow, error) {
+	var from time.Time
+	if date == "" {
+		from = workflow.Now(ctx).UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+	} else {
+		var err error
+		if from, err = time.Parse(models.ReconciliationDateFormat, date); err != nil {
+			return models.ReconciliationWindow{}, fmt.Errorf("invalid date %q: %w", date, err)
+		}
+	}
+	return models.ReconciliationWindow{From: from, To: from.AddDate(0, 0, 1)}, nil
+}
+
+// reconcilePayments matches each completed order with what the gateway
+// captured for it, net of refunds. Money captured for an order that was not
+// completed is reported unless it was refunded in full.
+func reconcilePayments(date string, transactions []models.GatewayTransaction, orders []models.CompletedOrder) *models.ReconciliationReport {
+	report := &models.ReconciliationReport{Date: date, Transactions: len(transactions), Orders: len(orders)}
+
+	captured := make(map[string]float64)
+	transactionIDs := make(map[string][]string)
+	for _, transaction := range transactions {
+		amount := transaction.Amount
+		if transaction.Type == models.TransactionRefund {
+			amount = -amount
+		}
+		captured[transaction.OrderID] += amount
+		transactionIDs[transaction.OrderID] = append(transactionIDs[transaction.OrderID], transaction.ID)
+	}
+
+	completed := make(map[string]bool, len(orders))
+	for _, order := range orders {
+		completed[order.OrderID] = true
+		discrepancy := models.Discrepancy{
+			OrderID:        order.OrderID,
+			TransactionIDs: transactionIDs[order.OrderID],
+			OrderAmount:    order.Amount,
+			CapturedAmount: captured[order.OrderID],
+		}
+		switch {
+		case len(discrepancy.TransactionIDs) == 0:
+			discrepancy.Kind = models.DiscrepancyMissingPayment
+		case !sameAmount(discrepancy.CapturedAmount, order.Amount):
+			discrepancy.Kind = models.DiscrepancyAmountMismatch
+		default:
+			report.Matched++
+			continue
+		}
+		report.Discrepancies = append(report.Discrepancies, discrepancy)
+	}
+
+	// Map order is random, but only the sorted report leaves this function,
+	// so it is the same on replay
+	for orderID, ids := range transactionIDs {
+		if completed[orderID] || sameAmount(captured[orderID], 0) {
+			continue
+		}
+		report.Discrepancies = append(report.Discrepancies, models.Discrepancy{
+			Kind:           models.DiscrepancyUnmatchedPayment,
+			OrderID:        orderID,
+			TransactionIDs: ids,
+			CapturedAmount: captured[orderID],
+		})
+	}
+	slices.SortFunc(report.Discrepancies, func(a, b models.Discrepancy) int {
+		return strings.Compare(a.OrderID, b.OrderID)
+	})
+	return report
+}
+
+// sameAmount reports whether two dollar amounts are equal to the cent
+func sameAmount(a, b float64) bool {
+	return math.Abs(a-b) < 0.005
+}
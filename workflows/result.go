@@ -0,0 +1,34 @@
+package workflows
+
+import (
+	"strings"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/workflow"
+)
+
+// orderResult summarizes the order as it closes. Only a completed order has
+// packed its items, so only it is given a shipment; an order fulfilled from
+// several warehouses lists the shipments that went out.
+func orderResult(ctx workflow.Context, state *models.OrderStatus, transactionID string) *models.OrderResult {
+	result := &models.OrderResult{
+		OrderID:       state.OrderID,
+		Status:        state.Status,
+		PaymentStatus: state.PaymentStatus,
+		TransactionID: transactionID,
+		Duration:      workflow.Now(ctx).Sub(workflow.GetInfo(ctx).WorkflowStartTime),
+	}
+	if state.Stage == models.StageCompleted {
+		result.ShipmentID = models.ShipmentID(state.OrderID)
+		if len(state.Shipments) > 0 {
+			var shipped []string
+			for _, shipment := range state.Shipments {
+				if shipment.Status == models.ItemFulfilled {
+					shipped = append(shipped, shipment.ShipmentID)
+				}
+			}
+			result.ShipmentID = strings.Join(shipped, ",")
+		}
+	}
+	return result
+}
@@ -0,0 +1,164 @@
+package workflows
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// RiskAssessmentWorkflowName is the registered name of RiskAssessmentWorkflow
+const RiskAssessmentWorkflowName = "RiskAssessmentWorkflow"
+
+const (
+	// RiskStepUpScore is the risk score at or above which an order's
+	// customer must pass step-up verification before payment
+	RiskStepUpScore = 0.4
+	// RiskManualReviewScore is the risk score at or above which an order
+	// waits for manual review before payment
+	RiskManualReviewScore = 0.7
+	// RiskOrderValueCeiling is the order amount at which the order value
+	// score reaches 1
+	RiskOrderValueCeiling = 10000.0
+
+	// StepUpVerificationTimeout is how long an order waits for its customer
+	// to pass step-up verification before it is escalated to manual review
+	StepUpVerificationTimeout = 24 * time.Hour
+)
+
+// Weights of the fraud, order value, and customer history scores in an
+// order's risk score; they add up to 1
+const (
+	riskFraudWeight   = 0.5
+	riskValueWeight   = 0.3
+	riskHistoryWeight = 0.2
+)
+
+// RiskAssessmentWorkflow scores an order's risk from the CheckFraud score, its
+// amount, and its customer's history, fetched concurrently, and sorts it into
+// a tier: auto-approve, step-up verification, or manual review. An order the
+// fraud check suspects goes to manual review whatever its score, and a guest
+// order or a customer with chargebacks has the highest history score.
+func RiskAssessmentWorkflow(ctx workflow.Context, req models.RiskAssessmentRequest) (*models.RiskAssessment, error) {
+	logger := workflow.GetLogger(ctx)
+	order := req.Order
+	logger.Info("Risk assessment workflow started", "order_id", order.ID, "customer_id", req.CustomerID)
+
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout:    10 * time.Second,
+		ScheduleToStartTimeout: 5 * time.Second,
+		RetryPolicy: &RetryPolicy{
+			InitialInterval:    time.Second,
+			BackoffCoefficient: 2.0,
+			MaximumInterval:    10 * time.Second,
+			MaximumAttempts:    3,
+		},
+	})
+
+	fraudFuture := workflow.ExecuteActivity(ctx, "CheckFraud", order)
+	var historyFuture workflow.Future
+	if req.CustomerID != "" {
+		historyFuture = workflow.ExecuteActivity(ctx, "GetCustomerHistory", req.CustomerID)
+	}
+
+	assessment := &models.RiskAssessment{OrderID: order.ID}
+	suspected := false
+	var fraudResult models.FraudCheckResult
+	err := fraudFuture.Get(ctx, &fraudResult)
+	var appErr *temporal.ApplicationError
+	var fraud models.FraudSuspectedError
+	switch {
+	case err == nil:
+		assessment.FraudScore = math.Min(fraudResult.Score, 1)
+	case errors.As(err, &appErr) && appErr.Type() == models.ErrTypeFraudSuspected && appErr.Details(&fraud) == nil:
+		suspected = true
+		assessment.FraudScore = 1
+		assessment.Reasons = append(assessment.Reasons, "suspected fraud: "+fraud.Reason)
+	default:
+		logger.Error("Fraud check failed", "order_id", order.ID, "error", err)
+		return nil, err
+	}
+
+	assessment.ValueScore = math.Min(order.Amount/RiskOrderValueCeiling, 1)
+
+	if historyFuture == nil {
+		assessment.HistoryScore = 1
+		assessment.Reasons = append(assessment.Reasons, "guest order")
+	} else {
+		var history models.CustomerHistory
+		if err := historyFuture.Get(ctx, &history); err != nil {
+			logger.Error("Failed to fetch customer history", "order_id", order.ID, "customer_id", req.CustomerID, "error", err)
+			return nil, err
+		}
+		switch {
+		case history.Chargebacks > 0:
+			assessment.HistoryScore = 1
+			assessment.Reasons = append(assessment.Reasons, fmt.Sprintf("%d chargebacks", history.Chargebacks))
+		case history.Orders == 0:
+			assessment.HistoryScore = 1
+			assessment.Reasons = append(assessment.Reasons, "first order")
+		default:
+			assessment.HistoryScore = 1 / float64(history.Orders+1)
+		}
+	}
+
+	assessment.Score = riskFraudWeight*assessment.FraudScore +
+		riskValueWeight*assessment.ValueScore +
+		riskHistoryWeight*assessment.HistoryScore
+	switch {
+	case suspected || assessment.Score >= RiskManualReviewScore:
+		assessment.Tier = models.RiskTierManualReview
+	case assessment.Score >= RiskStepUpScore:
+		assessment.Tier = models.RiskTierStepUp
+	default:
+		assessment.Tier = models.RiskTierAutoApprove
+	}
+
+	logger.Info("Risk assessment workflow completed", "order_id", order.ID, "tier", assessment.Tier, "score", assessment.Score)
+	return assessment, nil
+}
+
+// assessRisk runs the order's RiskAssessmentWorkflow child and returns its
+// assessment
+func assessRisk(ctx workflow.Context, order models.Order) (*models.RiskAssessment, error) {
+	childCtx := workflow.WithChildOptions(ctx, workflow.ChildWorkflowOptions{
+		WorkflowID:               models.RiskAssessmentWorkflowID(order.ID),
+		WorkflowExecutionTimeout: 2 * time.Minute,
+		ParentClosePolicy:        enums.PARENT_CLOSE_POLICY_TERMINATE,
+	})
+	var assessment models.RiskAssessment
+	err := workflow.ExecuteChildWorkflow(childCtx, RiskAssessmentWorkflowName, models.RiskAssessmentRequest{
+		Order:      order,
+		CustomerID: orderCustomerID(ctx, order),
+	}).Get(ctx, &assessment)
+	if err != nil {
+		return nil, err
+	}
+	return &assessment, nil
+}
+
+// requestStepUp asks the order's customer to pass step-up verification with
+// RequestStepUpVerification. It reports whether the request went out; an
+// order without a customer has no one to ask.
+func requestStepUp(ctx workflow.Context, order models.Order) bool {
+	logger := workflow.GetLogger(ctx)
+	customerID := orderCustomerID(ctx, order)
+	if customerID == "" {
+		logger.Info("Step-up verification skipped, order has no customer", "order_id", order.ID)
+		return false
+	}
+	err := workflow.ExecuteActivity(ctx, "RequestStepUpVerification", models.StepUpRequest{
+		OrderID:    order.ID,
+		CustomerID: customerID,
+	}).Get(ctx, nil)
+	if err != nil {
+		logger.Error("Failed to request step-up verification", "order_id", order.ID, "error", err)
+		return false
+	}
+	return true
+}
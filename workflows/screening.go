@@ -0,0 +1,39 @@
+package workflows
+
+import (
+	"errors"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// screenCustomer checks the order's customer against the denylist and
+// sanctions lists with ScreenCustomer. On a match ops are alerted before the
+// CustomerDenied error is returned; a failed alert is logged, since the order
+// is rejected either way. An order without a customer has no one to screen.
+func screenCustomer(ctx workflow.Context, order models.Order) error {
+	logger := workflow.GetLogger(ctx)
+	customerID := orderCustomerID(ctx, order)
+	if customerID == "" {
+		logger.Info("Customer screening skipped, order has no customer", "order_id", order.ID)
+		return nil
+	}
+
+	err := workflow.ExecuteActivity(ctx, "ScreenCustomer", models.ScreeningRequest{
+		OrderID:    order.ID,
+		CustomerID: customerID,
+	}).Get(ctx, nil)
+	if applicationErrorType(err) != models.ErrTypeCustomerDenied {
+		return err
+	}
+
+	var appErr *temporal.ApplicationError
+	var hit models.CustomerDeniedError
+	if errors.As(err, &appErr) && appErr.Details(&hit) == nil {
+		if alertErr := workflow.ExecuteActivity(ctx, "NotifyOpsOfScreeningHit", hit).Get(ctx, nil); alertErr != nil {
+			logger.Error("Failed to alert ops of screening hit", "order_id", order.ID, "error", alertErr)
+		}
+	}
+	return err
+}
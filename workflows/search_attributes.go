@@ -0,0 +1,53 @@
+package workflows
+
+import (
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// Search attribute keys for listing orders through the visibility API
+var (
+	OrderStatusKey    = temporal.NewSearchAttributeKeyKeyword(models.SearchAttributeOrderStatus)
+	OrderExpeditedKey = temporal.NewSearchAttributeKeyBool(models.SearchAttributeOrderExpedited)
+	ShipmentStatusKey = temporal.NewSearchAttributeKeyKeyword(models.SearchAttributeShipmentStatus)
+)
+
+type searchAttributesKey struct{}
+
+// withSearchAttributes marks ctx so that status updates persisted through it
+// are also upserted as search attributes
+func withSearchAttributes(ctx workflow.Context) workflow.Context {
+	return workflow.WithValue(ctx, searchAttributesKey{}, true)
+}
+
+// searchAttributesEnabled reports whether ctx was marked by withSearchAttributes
+func searchAttributesEnabled(ctx workflow.Context) bool {
+	enabled, _ := ctx.Value(searchAttributesKey{}).(bool)
+	return enabled
+}
+
+// upsertOrderSearchAttributes sets OrderStatus and OrderExpedited from state.
+// Visibility is a convenience for operators, so a rejected upsert is logged
+// rather than failing the order.
+func upsertOrderSearchAttributes(ctx workflow.Context, state *models.OrderStatus) {
+	err := workflow.UpsertTypedSearchAttributes(ctx,
+		OrderStatusKey.ValueSet(string(state.Status)),
+		OrderExpeditedKey.ValueSet(state.IsExpedited))
+	if err != nil {
+		workflow.GetLogger(ctx).Warn("Failed to upsert search attributes", "order_id", state.OrderID, "error", err)
+	}
+}
+
+// upsertShipmentSearchAttributes sets ShipmentStatus from the shipment's last
+// status, if the shipment's order enabled search attributes; a rejected
+// upsert is logged rather than ending the tracking
+func upsertShipmentSearchAttributes(ctx workflow.Context, shipment models.Shipment) {
+	if !shipment.SearchAttributes {
+		return
+	}
+	err := workflow.UpsertTypedSearchAttributes(ctx, ShipmentStatusKey.ValueSet(shipment.LastStatus))
+	if err != nil {
+		workflow.GetLogger(ctx).Warn("Failed to upsert search attributes", "shipment_id", shipment.ShipmentID, "error", err)
+	}
+}
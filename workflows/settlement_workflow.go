@@ -0,0 +1,130 @@
+package workflows
+
+import (
+	"slices"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/workflow"
+)
+
+// SettlementWorkflowName is the registered name of SettlementWorkflow
+const SettlementWorkflowName = "SettlementWorkflow"
+
+// SettlementWorkflow settles a day's payments: it collects the captures and
+// refunds the payment gateway made that UTC day, batches them by merchant
+// account, and settles the batches concurrently. Each settled batch's
+// settlement ID is recorded on its orders in the order store. A batch that
+// fails does not hold up the others; it is reported to finance with the
+// rest, and starting the workflow for the day again settles it without
+// settling the others twice.
+func SettlementWorkflow(ctx workflow.Context, req models.SettlementRequest) (*models.SettlementReport, error) {
+	logger := workflow.GetLogger(ctx)
+
+	window, err := dayWindow(ctx, req.Date)
+	if err != nil {
+		return nil, err
+	}
+	date := window.From.Format(models.ReconciliationDateFormat)
+	logger.Info("Settlement workflow started", "date", date)
+
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout:    time.Minute,
+		ScheduleToStartTimeout: 5 * time.Minute,
+		RetryPolicy: &RetryPolicy{
+			InitialInterval:    time.Second,
+			BackoffCoefficient: 2.0,
+			MaximumInterval:    time.Minute,
+			MaximumAttempts:    5,
+		},
+	})
+
+	var transactions []models.GatewayTransaction
+	if err := workflow.ExecuteActivity(ctx, "FetchGatewayTransactions", window).Get(ctx, &transactions); err != nil {
+		logger.Error("Failed to fetch gateway transactions", "date", date, "error", err)
+		return nil, err
+	}
+
+	batches := settlementBatches(date, transactions)
+	futures := make([]workflow.Future, len(batches))
+	for i, batch := range batches {
+		futures[i] = workflow.ExecuteActivity(ctx, "SettleBatch", batch)
+	}
+
+	report := &models.SettlementReport{Date: date}
+	for i, batch := range batches {
+		outcome := models.BatchSettlement{
+			BatchID:         batch.ID,
+			MerchantAccount: batch.MerchantAccount,
+			OrderIDs:        batch.OrderIDs,
+			Amount:          batch.Amount,
+			Status:          models.SettlementFailed,
+		}
+		var result models.SettlementResult
+		if err := futures[i].Get(ctx, &result); err != nil {
+			logger.Error("Failed to settle batch", "batch_id", batch.ID, "error", err)
+			outcome.Error = err.Error()
+			report.Failed++
+			report.Batches = append(report.Batches, outcome)
+			continue
+		}
+		outcome.Status = models.SettlementSettled
+		outcome.SettlementID = result.SettlementID
+		report.Settled++
+
+		settlement := models.OrderSettlement{SettlementID: result.SettlementID, OrderIDs: batch.OrderIDs}
+		if err := workflow.ExecuteActivity(ctx, "RecordSettlement", settlement).Get(ctx, nil); err != nil {
+			logger.Error("Failed to record settlement on orders", "batch_id", batch.ID, "settlement_id", result.SettlementID, "error", err)
+			outcome.Error = "settlement ID not recorded on orders: " + err.Error()
+		}
+		report.Batches = append(report.Batches, outcome)
+	}
+
+	if err := workflow.ExecuteActivity(ctx, "ReportSettlement", *report).Get(ctx, nil); err != nil {
+		logger.Error("Failed to report settlement", "date", date, "error", err)
+		return nil, err
+	}
+
+	logger.Info("Settlement workflow completed", "date", date, "settled", report.Settled, "failed", report.Failed)
+	return report, nil
+}
+
+// settlementBatches groups a day's transactions into a batch per merchant
+// account, sorted by account so batches are settled in the same order on
+// replay
+func settlementBatches(date string, transactions []models.GatewayTransaction) []models.SettlementBatch {
+	byAccount := make(map[string]*models.SettlementBatch)
+	var accounts []string
+	for _, transaction := range transactions {
+		account := transaction.MerchantAccount
+		if account == "" {
+			account = models.DefaultMerchantAccount
+		}
+		batch, ok := byAccount[account]
+		if !ok {
+			batch = &models.SettlementBatch{
+				ID:              models.SettlementBatchID(date, account),
+				Date:            date,
+				MerchantAccount: account,
+			}
+			byAccount[account] = batch
+			accounts = append(accounts, account)
+		}
+		amount := transaction.Amount
+		if transaction.Type == models.TransactionRefund {
+			amount = -amount
+		}
+		batch.Amount += amount
+		batch.TransactionIDs = append(batch.TransactionIDs, transaction.ID)
+		if !slices.Contains(batch.OrderIDs, transaction.OrderID) {
+			batch.OrderIDs = append(batch.OrderIDs, transaction.OrderID)
+		}
+	}
+	slices.Sort(accounts)
+
+	batches := make([]models.SettlementBatch, 0, len(accounts))
+	for _, account := range accounts {
+		batches = append(batches, *byAccount[account])
+	}
+	return batches
+}
@@ -0,0 +1,36 @@
+package workflows
+
+import (
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/workflow"
+)
+
+// watchDeliveryPromise flags the order once its shipping's promised delivery
+// passes before the order is delivered. A promise already past when the
+// order starts is checked at once.
+func watchDeliveryPromise(ctx workflow.Context, state *models.OrderStatus, shipping models.ShippingInfo) {
+	workflow.Go(ctx, func(ctx workflow.Context) {
+		if wait := shipping.PromisedBy.Sub(workflow.Now(ctx)); wait > 0 {
+			if err := workflow.Sleep(ctx, wait); err != nil {
+				return
+			}
+		}
+		if deliveryPromiseKept(state) {
+			return
+		}
+		state.PromiseMissed = true
+		workflow.GetLogger(ctx).Warn("Order missed its promised delivery", "order_id", state.OrderID, "promised_by", shipping.PromisedBy,
+			"shipping_method", shipping.Method, "status", state.Status, "shipment_status", state.ShipmentStatus)
+		recordPromiseMissed(ctx, shipping.Method)
+	})
+}
+
+// deliveryPromiseKept reports whether the order owes no delivery: its
+// tracked shipments are delivered or, untracked, it has closed. A closed
+// order that failed or was cancelled has nothing left to deliver.
+func deliveryPromiseKept(state *models.OrderStatus) bool {
+	if state.ShipmentStatus != "" {
+		return state.ShipmentStatus == models.ShipmentDelivered
+	}
+	return state.Status.IsTerminal()
+}
@@ -0,0 +1,17 @@
+package workflows
+
+import (
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/workflow"
+)
+
+// setOrderStatus moves the order to status through its state machine. An
+// illegal transition, such as completed to processing, is logged and leaves
+// the status as it was. It reports whether the status was set.
+func setOrderStatus(ctx workflow.Context, state *models.OrderStatus, status models.OrderStatusCode) bool {
+	if err := state.TransitionTo(status); err != nil {
+		workflow.GetLogger(ctx).Error("Refusing order status change", "order_id", state.OrderID, "error", err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,190 @@
+package workflows
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/workflow"
+)
+
+// TrackingWorkflowName is the registered name of TrackingWorkflow
+const TrackingWorkflowName = "TrackingWorkflow"
+
+const (
+	// TrackingPollsPerRun is how many times one run of TrackingWorkflow polls
+	// the carrier before it continues as new, which keeps the history of a
+	// shipment that is in transit for weeks bounded
+	TrackingPollsPerRun = 100
+
+	// TrackingTimeout is how long a shipment may go undelivered before its
+	// tracking gives up and reports an exception
+	TrackingTimeout = 30 * 24 * time.Hour
+)
+
+// TrackingWorkflow polls the carrier for a shipment's status on a durable
+// timer until it is delivered or runs into an exception, then signals its
+// parent order, if any, and returns the final status. Each status change is
+// upserted as the ShipmentStatus search attribute when enabled. A failed
+// poll is retried at the next tick rather than ending the tracking.
+func TrackingWorkflow(ctx workflow.Context, shipment models.Shipment) (*models.TrackingUpdate, error) {
+	logger := workflow.GetLogger(ctx)
+	if shipment.StartedAt.IsZero() {
+		shipment.StartedAt = workflow.Now(ctx)
+	}
+	logger.Info("Tracking workflow started", "order_id", shipment.OrderID, "shipment_id", shipment.ShipmentID, "polls", shipment.Polls)
+
+	status := models.TrackingUpdate{ShipmentID: shipment.ShipmentID, Status: shipment.LastStatus}
+	err := workflow.SetQueryHandler(ctx, "getStatus", func() (models.TrackingUpdate, error) {
+		return status, nil
+	})
+	if err != nil {
+		logger.Error("Failed to register query handler", "error", err)
+		return nil, err
+	}
+
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout:    10 * time.Second,
+		ScheduleToStartTimeout: 5 * time.Second,
+		RetryPolicy: &RetryPolicy{
+			InitialInterval:    time.Second,
+			BackoffCoefficient: 2.0,
+			MaximumInterval:    10 * time.Second,
+			MaximumAttempts:    3,
+		},
+	})
+
+	for range TrackingPollsPerRun {
+		if err := workflow.Sleep(ctx, shipment.PollInterval); err != nil {
+			return nil, err
+		}
+		shipment.Polls++
+
+		var update *models.TrackingUpdate
+		err := workflow.ExecuteActivity(ctx, "TrackShipment", models.TrackingRequest{
+			OrderID:    shipment.OrderID,
+			ShipmentID: shipment.ShipmentID,
+			Carrier:    shipment.Carrier,
+			Poll:       shipment.Polls,
+		}).Get(ctx, &update)
+		if err != nil {
+			logger.Warn("Failed to track shipment", "order_id", shipment.OrderID, "shipment_id", shipment.ShipmentID, "poll", shipment.Polls, "error", err)
+		} else {
+			status = *update
+			if update.Status != shipment.LastStatus {
+				shipment.LastStatus = update.Status
+				upsertShipmentSearchAttributes(ctx, shipment)
+			}
+			if update.Final() {
+				return &status, notifyShipmentUpdate(ctx, shipment, status)
+			}
+		}
+
+		if workflow.Now(ctx).Sub(shipment.StartedAt) >= TrackingTimeout {
+			status = models.TrackingUpdate{
+				ShipmentID: shipment.ShipmentID,
+				Status:     models.ShipmentException,
+				Detail:     fmt.Sprintf("not delivered within %s", TrackingTimeout),
+				At:         workflow.Now(ctx),
+			}
+			shipment.LastStatus = status.Status
+			upsertShipmentSearchAttributes(ctx, shipment)
+			return &status, notifyShipmentUpdate(ctx, shipment, status)
+		}
+	}
+
+	logger.Info("Tracking continues as new", "order_id", shipment.OrderID, "shipment_id", shipment.ShipmentID, "polls", shipment.Polls)
+	return nil, workflow.NewContinueAsNewError(ctx, TrackingWorkflowName, shipment)
+}
+
+// notifyShipmentUpdate signals the final status of the shipment to the
+// parent order, if any
+func notifyShipmentUpdate(ctx workflow.Context, shipment models.Shipment, update models.TrackingUpdate) error {
+	logger := workflow.GetLogger(ctx)
+	logger.Info("Shipment tracking finished", "order_id", shipment.OrderID, "shipment_id", shipment.ShipmentID, "status", update.Status, "polls", shipment.Polls)
+	if !shipment.PromisedBy.IsZero() && update.At.After(shipment.PromisedBy) {
+		logger.Warn("Shipment finished after its promised delivery", "order_id", shipment.OrderID, "shipment_id", shipment.ShipmentID, "promised_by", shipment.PromisedBy)
+	}
+	parent := workflow.GetInfo(ctx).ParentWorkflowExecution
+	if parent == nil {
+		return nil
+	}
+	err := workflow.SignalExternalWorkflow(ctx, parent.ID, parent.RunID, models.SignalShipmentUpdate, update).Get(ctx, nil)
+	if err != nil {
+		logger.Error("Failed to signal shipment update to order", "order_id", shipment.OrderID, "workflow_id", parent.ID, "error", err)
+	}
+	return err
+}
+
+// trackShipments starts tracking the order's shipments, one for an order
+// shipped whole or one per fulfilled warehouse shipment, and returns the
+// trackings that started
+func trackShipments(ctx workflow.Context, order models.Order, state *models.OrderStatus, pollInterval time.Duration) []workflow.ChildWorkflowFuture {
+	logger := workflow.GetLogger(ctx)
+	if len(state.Shipments) == 0 {
+		tracking, err := startTracking(ctx, order, models.TrackingWorkflowID(order.ID), models.ShipmentID(order.ID), pollInterval)
+		if err != nil {
+			logger.Warn("Failed to start shipment tracking", "order_id", order.ID, "error", err)
+			return nil
+		}
+		state.ShipmentStatus = models.ShipmentInTransit
+		state.LastUpdated = workflow.Now(ctx)
+		return []workflow.ChildWorkflowFuture{tracking}
+	}
+
+	var trackings []workflow.ChildWorkflowFuture
+	for i := range state.Shipments {
+		shipment := &state.Shipments[i]
+		if shipment.Status != models.ItemFulfilled {
+			continue
+		}
+		tracking, err := startTracking(ctx, order, models.TrackingWorkflowID(shipment.ShipmentID), shipment.ShipmentID, pollInterval)
+		if err != nil {
+			logger.Warn("Failed to start shipment tracking", "order_id", order.ID, "shipment_id", shipment.ShipmentID, "error", err)
+			continue
+		}
+		shipment.TrackingStatus = models.ShipmentInTransit
+		trackings = append(trackings, tracking)
+	}
+	if len(trackings) > 0 {
+		state.ShipmentStatus = models.AggregateShipmentStatus(state.Shipments)
+		state.LastUpdated = workflow.Now(ctx)
+	}
+	return trackings
+}
+
+// startTracking starts tracking a shipment of the order, with the carrier and
+// promise of its shipping, and waits for the tracking to start, not to
+// finish. The tracking is of no use once the order has closed, so it is
+// terminated with the order.
+func startTracking(ctx workflow.Context, order models.Order, workflowID, shipmentID string, pollInterval time.Duration) (workflow.ChildWorkflowFuture, error) {
+	childCtx := workflow.WithChildOptions(ctx, workflow.ChildWorkflowOptions{
+		WorkflowID:        workflowID,
+		ParentClosePolicy: enums.PARENT_CLOSE_POLICY_TERMINATE,
+	})
+	shipment := models.Shipment{
+		OrderID:          order.ID,
+		ShipmentID:       shipmentID,
+		PollInterval:     pollInterval,
+		SearchAttributes: searchAttributesEnabled(ctx),
+	}
+	if order.Shipping != nil {
+		shipment.Carrier = order.Shipping.Carrier
+		shipment.PromisedBy = order.Shipping.PromisedBy
+	}
+	tracking := workflow.ExecuteChildWorkflow(childCtx, TrackingWorkflowName, shipment)
+	if err := tracking.GetChildWorkflowExecution().Get(ctx, nil); err != nil {
+		return nil, err
+	}
+	return tracking, nil
+}
+
+// awaitTracking waits for the tracking of a shipment of the order to finish.
+// The final status arrives by the shipment-update signal; a tracking that
+// fails without sending one leaves the last status known.
+func awaitTracking(ctx workflow.Context, order models.Order, tracking workflow.ChildWorkflowFuture) {
+	if err := tracking.Get(ctx, nil); err != nil {
+		workflow.GetLogger(ctx).Warn("Shipment tracking did not complete", "order_id", order.ID, "error", err)
+	}
+}
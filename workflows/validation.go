@@ -0,0 +1,56 @@
+package workflows
+
+import (
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/sdk/workflow"
+)
+
+const (
+	// outageBackoffRounds is how many times validation is re-attempted after
+	// the validation service is reported unavailable
+	outageBackoffRounds = 4
+	// outageInitialBackoff is the first wait before re-attempting validation
+	outageInitialBackoff = time.Minute
+	// outageMaxBackoff caps the wait between re-attempts
+	outageMaxBackoff = 10 * time.Minute
+)
+
+// validateWithOutageBackoff runs ValidateOrder and, when the validation
+// service is behind an open circuit breaker, waits with a much longer backoff
+// than the activity retry policy before trying again. Any other error is
+// returned unchanged.
+func validateWithOutageBackoff(ctx workflow.Context, order models.Order, resp *models.ValidationResponse) error {
+	logger := workflow.GetLogger(ctx)
+	backoff := outageInitialBackoff
+
+	for round := 0; ; round++ {
+		err := workflow.ExecuteActivity(ctx, "ValidateOrder", order).Get(ctx, resp)
+		if err == nil || !isServiceUnavailable(err) || round >= outageBackoffRounds {
+			return err
+		}
+
+		logger.Warn("Validation service unavailable, backing off", "order_id", order.ID, "backoff", backoff, "round", round+1)
+		if err := workflow.Sleep(ctx, backoff); err != nil {
+			return err
+		}
+
+		backoff *= 2
+		if backoff > outageMaxBackoff {
+			backoff = outageMaxBackoff
+		}
+	}
+}
+
+// isServiceUnavailable reports whether err was caused by a ServiceUnavailable application error
+func isServiceUnavailable(err error) bool {
+	return applicationErrorType(err) == models.ErrTypeServiceUnavailable
+}
+
+// isValidationOutage reports whether err means the validation service is
+// down, behind an open circuit breaker, unreachable, or failing with a 5xx,
+// rather than refusing the request, as with a 4xx or an unreadable answer
+func isValidationOutage(err error) bool {
+	return isServiceUnavailable(err) || applicationErrorType(err) == models.ErrTypeServiceUnreachable
+}
@@ -0,0 +1,189 @@
+package workflows
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/workflow"
+)
+
+const VendorFulfillmentWorkflowName = "VendorFulfillmentWorkflow"
+
+const (
+	// VendorResponseTimeout is how long a vendor has to answer a purchase
+	// order before ops are alerted
+	VendorResponseTimeout = 24 * time.Hour
+
+	// VendorEscalationTimeout is how long an escalated purchase order still
+	// waits for an answer, from the vendor or from ops on its behalf, before
+	// it is taken as rejected
+	VendorEscalationTimeout = 48 * time.Hour
+)
+
+// VendorFulfillmentWorkflow is a child workflow that has a dropship vendor
+// ship the marketplace items of an order. It sends the purchase order and,
+// unless the vendor answers at once, waits for the vendor-response signal.
+// A vendor that does not answer in time is escalated to ops, and one that
+// still has not answered after that is taken to have rejected the purchase
+// order. The parent order is signalled as the purchase order waits and is
+// escalated; the vendor's answer is the workflow result.
+func VendorFulfillmentWorkflow(ctx workflow.Context, po models.PurchaseOrder) (*models.VendorFulfillment, error) {
+	logger := workflow.GetLogger(ctx)
+	logger.Info("Vendor fulfillment workflow started", "order_id", po.OrderID, "purchase_order", po.Number, "vendor_id", po.VendorID)
+
+	state := &models.VendorFulfillment{
+		PurchaseOrder: po.Number,
+		VendorID:      po.VendorID,
+		Items:         po.Items,
+		Status:        models.VendorPending,
+		LastUpdated:   workflow.Now(ctx),
+	}
+
+	err := workflow.SetQueryHandler(ctx, "getStatus", func() (*models.VendorFulfillment, error) {
+		return state, nil
+	})
+	if err != nil {
+		logger.Error("Failed to register query handler", "error", err)
+		return nil, err
+	}
+
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout:    10 * time.Second,
+		ScheduleToStartTimeout: 5 * time.Second,
+		RetryPolicy: &RetryPolicy{
+			InitialInterval:    time.Second,
+			BackoffCoefficient: 2.0,
+			MaximumInterval:    10 * time.Second,
+			MaximumAttempts:    5,
+		},
+	})
+
+	var response *models.VendorResponse
+	if err := workflow.ExecuteActivity(ctx, "SendPurchaseOrder", po).Get(ctx, &response); err != nil {
+		logger.Error("Failed to send purchase order", "order_id", po.OrderID, "purchase_order", po.Number, "error", err)
+		return nil, err
+	}
+
+	if response == nil {
+		notifyVendorUpdate(ctx, po, state)
+		responses := workflow.GetSignalChannel(ctx, models.SignalVendorResponse)
+		var answer models.VendorResponse
+		answered, _ := responses.ReceiveWithTimeout(ctx, VendorResponseTimeout, &answer)
+		if !answered {
+			logger.Warn("Vendor did not answer purchase order", "order_id", po.OrderID, "purchase_order", po.Number, "vendor_id", po.VendorID)
+			if err := workflow.ExecuteActivity(ctx, "NotifyOpsOfUnresponsiveVendor", *state).Get(ctx, nil); err != nil {
+				logger.Error("Failed to alert ops of unresponsive vendor", "purchase_order", po.Number, "error", err)
+			}
+			state.Status = models.VendorEscalated
+			state.LastUpdated = workflow.Now(ctx)
+			notifyVendorUpdate(ctx, po, state)
+			answered, _ = responses.ReceiveWithTimeout(ctx, VendorEscalationTimeout, &answer)
+		}
+		if !answered {
+			answer = models.VendorResponse{Reason: fmt.Sprintf("vendor did not answer within %s", VendorResponseTimeout+VendorEscalationTimeout)}
+		}
+		response = &answer
+	}
+
+	state.Status = models.VendorRejected
+	if response.Accepted {
+		state.Status = models.VendorAccepted
+	}
+	state.Reason = response.Reason
+	state.LastUpdated = workflow.Now(ctx)
+	logger.Info("Vendor fulfillment workflow completed", "order_id", po.OrderID, "purchase_order", po.Number, "status", state.Status, "reason", state.Reason)
+	return state, nil
+}
+
+// notifyVendorUpdate signals the purchase order's status to the parent
+// order, if any. The order learns the vendor's answer from the workflow
+// result, so a failed signal only leaves its status behind for a while.
+func notifyVendorUpdate(ctx workflow.Context, po models.PurchaseOrder, state *models.VendorFulfillment) {
+	parent := workflow.GetInfo(ctx).ParentWorkflowExecution
+	if parent == nil {
+		return
+	}
+	err := workflow.SignalExternalWorkflow(ctx, parent.ID, parent.RunID, models.SignalVendorUpdate, *state).Get(ctx, nil)
+	if err != nil {
+		workflow.GetLogger(ctx).Warn("Failed to signal purchase order status to order", "order_id", po.OrderID, "purchase_order", po.Number, "error", err)
+	}
+}
+
+// startVendorFulfillment sends a purchase order for the order's marketplace
+// items to each of their vendors, in a VendorFulfillmentWorkflow child per
+// vendor, and records them in state.Vendors. It returns the items left for
+// the order's own fulfillment and the children, indexed like state.Vendors.
+func startVendorFulfillment(ctx workflow.Context, order models.Order, state *models.OrderStatus) ([]string, []workflow.ChildWorkflowFuture) {
+	var own []string
+	itemsByVendor := make(map[string][]string)
+	for _, item := range order.Items {
+		if vendorID, ok := order.Vendors[item]; ok && vendorID != "" {
+			itemsByVendor[vendorID] = append(itemsByVendor[vendorID], item)
+		} else {
+			own = append(own, item)
+		}
+	}
+
+	// Map order is random, so vendors are sorted to start children in the
+	// same order on replay
+	vendorIDs := make([]string, 0, len(itemsByVendor))
+	for vendorID := range itemsByVendor {
+		vendorIDs = append(vendorIDs, vendorID)
+	}
+	slices.Sort(vendorIDs)
+
+	var children []workflow.ChildWorkflowFuture
+	for _, vendorID := range vendorIDs {
+		po := models.PurchaseOrder{
+			Number:   models.PurchaseOrderNumber(order.ID, vendorID),
+			OrderID:  order.ID,
+			VendorID: vendorID,
+			Items:    itemsByVendor[vendorID],
+		}
+		childCtx := workflow.WithChildOptions(ctx, workflow.ChildWorkflowOptions{
+			WorkflowID:        models.VendorWorkflowID(order.ID, vendorID),
+			ParentClosePolicy: parentClosePolicy(ctx, models.ChildFulfillment, enums.PARENT_CLOSE_POLICY_UNSPECIFIED),
+		})
+		children = append(children, workflow.ExecuteChildWorkflow(childCtx, VendorFulfillmentWorkflowName, po))
+		state.Vendors = append(state.Vendors, models.VendorFulfillment{
+			PurchaseOrder: po.Number,
+			VendorID:      vendorID,
+			Items:         po.Items,
+			Status:        models.VendorPending,
+			LastUpdated:   workflow.Now(ctx),
+		})
+	}
+	state.LastUpdated = workflow.Now(ctx)
+	return own, children
+}
+
+// awaitVendorFulfillment waits for the order's vendors to answer their
+// purchase orders, recording the answers in state.Vendors. It returns an
+// error for the first purchase order rejected or failed.
+func awaitVendorFulfillment(ctx workflow.Context, order models.Order, state *models.OrderStatus, children []workflow.ChildWorkflowFuture) error {
+	logger := workflow.GetLogger(ctx)
+	var firstErr error
+	for i, child := range children {
+		vendor := &state.Vendors[i]
+		var result models.VendorFulfillment
+		if err := child.Get(ctx, &result); err != nil {
+			logger.Warn("Vendor fulfillment failed", "order_id", order.ID, "purchase_order", vendor.PurchaseOrder, "error", err)
+			vendor.Status = models.VendorRejected
+			vendor.Reason = err.Error()
+			vendor.LastUpdated = workflow.Now(ctx)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("purchase order %s failed: %w", vendor.PurchaseOrder, err)
+			}
+			continue
+		}
+		*vendor = result
+		if result.Status == models.VendorRejected && firstErr == nil {
+			firstErr = fmt.Errorf("vendor %s rejected purchase order %s: %s", result.VendorID, result.PurchaseOrder, result.Reason)
+		}
+	}
+	state.LastUpdated = workflow.Now(ctx)
+	return firstErr
+}
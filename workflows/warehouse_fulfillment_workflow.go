@@ -0,0 +1,175 @@
+package workflows
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/aswathylr-builds/temporal-order-processing/models"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/workflow"
+)
+
+const WarehouseFulfillmentWorkflowName = "WarehouseFulfillmentWorkflow"
+
+// WarehouseFulfillmentWorkflow is a child workflow that reserves, picks, and
+// packs the items of one shipment at its warehouse. Items run concurrently,
+// and a failed item is recorded in the result rather than failing the
+// shipment, so the parent can tell which items shipped.
+func WarehouseFulfillmentWorkflow(ctx workflow.Context, req models.WarehouseFulfillmentRequest) (*models.WarehouseFulfillmentResult, error) {
+	logger := workflow.GetLogger(ctx)
+	logger.Info("Warehouse fulfillment workflow started", "order_id", req.OrderID, "shipment_id", req.ShipmentID, "warehouse_id", req.WarehouseID, "items", len(req.Items))
+
+	result := &models.WarehouseFulfillmentResult{
+		ShipmentID: req.ShipmentID,
+		Items:      make([]models.ItemFulfillment, len(req.Items)),
+	}
+	for i, item := range req.Items {
+		result.Items[i] = models.ItemFulfillment{Item: item, Status: models.ItemPending}
+	}
+
+	err := workflow.SetQueryHandler(ctx, "getStatus", func() (*models.WarehouseFulfillmentResult, error) {
+		return result, nil
+	})
+	if err != nil {
+		logger.Error("Failed to register query handler", "error", err)
+		return nil, err
+	}
+
+	ctx = workflow.WithActivityOptions(ctx, itemStepOptions)
+	wg := workflow.NewWaitGroup(ctx)
+	for i, item := range req.Items {
+		wg.Add(1)
+		workflow.Go(ctx, func(ctx workflow.Context) {
+			defer wg.Done()
+			itemReq := models.ItemFulfillmentRequest{
+				OrderID:     req.OrderID,
+				Item:        item,
+				WarehouseID: req.WarehouseID,
+				IsExpedited: req.IsExpedited,
+			}
+			err := runItemSteps(ctx, itemReq, &result.Items[i])
+			if applicationErrorType(err) == models.ErrTypeInventoryOutOfStock {
+				result.OutOfStock = append(result.OutOfStock, item)
+			}
+		})
+	}
+	wg.Wait(ctx)
+
+	logger.Info("Warehouse fulfillment workflow completed", "order_id", req.OrderID, "shipment_id", req.ShipmentID,
+		"fulfilled", countItems(result.Items, models.ItemFulfilled), "failed", countItems(result.Items, models.ItemFailed))
+	return result, nil
+}
+
+// fulfillFromWarehouses routes the order's items with SelectWarehouse and
+// fulfills each warehouse's share as a shipment of its own, in a
+// WarehouseFulfillmentWorkflow child. Items no warehouse stocks fail as out
+// of stock. Shipments are appended to state.Shipments, so items refulfilled
+// after a backorder ship separately.
+func fulfillFromWarehouses(ctx workflow.Context, order models.Order, state *models.OrderStatus) error {
+	logger := workflow.GetLogger(ctx)
+
+	var routing models.WarehouseRouting
+	err := workflow.ExecuteActivity(ctx, "SelectWarehouse", models.WarehouseRequest{
+		OrderID: order.ID,
+		Items:   order.Items,
+		Region:  orderMemo(ctx).Region,
+	}).Get(ctx, &routing)
+	if err != nil {
+		return err
+	}
+
+	state.ItemResults = make([]models.ItemFulfillment, len(order.Items))
+	for i, item := range order.Items {
+		state.ItemResults[i] = models.ItemFulfillment{Item: item, Status: models.ItemPending}
+	}
+	errs := make([]error, len(order.Items))
+
+	// Items may repeat within an order, so each assigned item claims the
+	// first unclaimed index of that item
+	claimed := make([]bool, len(order.Items))
+	claim := func(item string) int {
+		for i, orderItem := range order.Items {
+			if !claimed[i] && orderItem == item {
+				claimed[i] = true
+				return i
+			}
+		}
+		return -1
+	}
+
+	type shipment struct {
+		req     models.WarehouseFulfillmentRequest
+		indexes []int
+		future  workflow.ChildWorkflowFuture
+	}
+	shipments := make([]shipment, len(routing.Assignments))
+	for n, assignment := range routing.Assignments {
+		shipmentID := models.WarehouseShipmentID(order.ID, len(state.Shipments)+n+1)
+		s := &shipments[n]
+		s.req = models.WarehouseFulfillmentRequest{
+			OrderID:     order.ID,
+			ShipmentID:  shipmentID,
+			WarehouseID: assignment.WarehouseID,
+			Items:       assignment.Items,
+			IsExpedited: state.IsExpedited,
+		}
+		for _, item := range assignment.Items {
+			s.indexes = append(s.indexes, claim(item))
+		}
+		childCtx := workflow.WithChildOptions(ctx, workflow.ChildWorkflowOptions{
+			WorkflowID:               fmt.Sprintf("warehouse-%s", shipmentID),
+			WorkflowExecutionTimeout: 5 * time.Minute,
+			ParentClosePolicy:        parentClosePolicy(ctx, models.ChildFulfillment, enums.PARENT_CLOSE_POLICY_UNSPECIFIED),
+		})
+		s.future = workflow.ExecuteChildWorkflow(childCtx, WarehouseFulfillmentWorkflowName, s.req)
+	}
+
+	for i, item := range order.Items {
+		if !claimed[i] {
+			errs[i] = (&models.InventoryOutOfStockError{OrderID: order.ID, Items: []string{item}}).ApplicationError()
+			state.ItemResults[i].Status = models.ItemFailed
+			state.ItemResults[i].Error = "no warehouse stocks the item"
+		}
+	}
+
+	for _, s := range shipments {
+		var result models.WarehouseFulfillmentResult
+		err := s.future.Get(ctx, &result)
+		var packed []string
+		for j, i := range s.indexes {
+			switch {
+			case err != nil:
+				errs[i] = err
+				state.ItemResults[i].Status = models.ItemFailed
+				state.ItemResults[i].Error = err.Error()
+			case result.Items[j].Status == models.ItemFulfilled:
+				state.ItemResults[i] = result.Items[j]
+				packed = append(packed, result.Items[j].Item)
+			case slices.Contains(result.OutOfStock, result.Items[j].Item):
+				state.ItemResults[i] = result.Items[j]
+				errs[i] = (&models.InventoryOutOfStockError{OrderID: order.ID, Items: []string{result.Items[j].Item}}).ApplicationError()
+			default:
+				state.ItemResults[i] = result.Items[j]
+				errs[i] = errors.New(result.Items[j].Error)
+			}
+		}
+
+		orderShipment := models.OrderShipment{
+			ShipmentID:  s.req.ShipmentID,
+			WarehouseID: s.req.WarehouseID,
+			Items:       packed,
+			Status:      models.ItemFulfilled,
+		}
+		if len(packed) == 0 {
+			orderShipment.Items = s.req.Items
+			orderShipment.Status = models.ItemFailed
+			logger.Warn("Shipment fulfillment failed", "order_id", order.ID, "shipment_id", s.req.ShipmentID, "warehouse_id", s.req.WarehouseID, "error", err)
+		}
+		state.Shipments = append(state.Shipments, orderShipment)
+		state.LastUpdated = workflow.Now(ctx)
+	}
+
+	return aggregateItemErrors(order, errs)
+}